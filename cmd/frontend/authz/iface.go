@@ -66,3 +66,14 @@ type Provider interface {
 	// problems.
 	Validate() (problems []string)
 }
+
+// PermissionsInvalidator is implemented by authz providers that cache the permissions they compute.
+// It lets callers (e.g. code host webhook handlers) invalidate cached permissions when they know they
+// changed upstream, instead of waiting for them to expire.
+type PermissionsInvalidator interface {
+	// InvalidatePermissions invalidates the cached permissions of the user identified by the
+	// given external account on the given repos, whose external service id and type match the
+	// Provider's. The permissions are fetched again from the code host the next time they are
+	// needed.
+	InvalidatePermissions(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) error
+}
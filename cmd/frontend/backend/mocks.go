@@ -13,7 +13,8 @@ import (
 var Mocks MockServices
 
 type MockServices struct {
-	Repos MockRepos
+	Repos  MockRepos
+	Owners MockOwners
 }
 
 // testContext creates a new context.Context for use by tests
@@ -0,0 +1,83 @@
+package backend
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Owners backend.
+var Owners = &owners{}
+
+type owners struct{}
+
+// defaultOwnershipFiles are the paths of the files that assign owners to the
+// files in a repository, in order of precedence, if the site configuration
+// doesn't set search.ownershipFiles.
+var defaultOwnershipFiles = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// ownershipFileCache caches the contents of the ownership file of a repository
+// at a commit. Repositories without an ownership file are cached with empty
+// contents.
+var ownershipFileCache = rcache.New("ownership_file")
+
+// maxOwnershipFileSize is the maximum size of an ownership file that is read.
+const maxOwnershipFileSize = 1 << 20
+
+func ownershipFiles() []string {
+	if files := conf.Get().SearchOwnershipFiles; len(files) > 0 {
+		return files
+	}
+	return defaultOwnershipFiles
+}
+
+// Ruleset returns the ownership rules of the repository at the given commit,
+// parsed from the first ownership file that exists. If the repository has no
+// ownership file, the returned ruleset has no rules.
+func (owners) Ruleset(ctx context.Context, repo *types.Repo, commitID api.CommitID) (rs *codeowners.Ruleset, err error) {
+	if Mocks.Owners.Ruleset != nil {
+		return Mocks.Owners.Ruleset(ctx, repo, commitID)
+	}
+
+	ctx, done := trace(ctx, "Owners", "Ruleset", map[string]interface{}{"repo": repo.Name, "commitID": commitID}, &err)
+	defer done()
+
+	if !git.IsAbsoluteRevision(string(commitID)) {
+		return nil, errors.Errorf("non-absolute CommitID for Owners.Ruleset: %v", commitID)
+	}
+
+	cacheKey := string(repo.Name) + "@" + string(commitID)
+	data, ok := ownershipFileCache.Get(cacheKey)
+	if !ok {
+		cachedRepo, err := CachedGitRepo(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		data = []byte{}
+		for _, name := range ownershipFiles() {
+			b, err := git.ReadFile(ctx, *cachedRepo, commitID, name, maxOwnershipFileSize)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			data = b
+			break
+		}
+		ownershipFileCache.Set(cacheKey, data)
+	}
+
+	rs, err = codeowners.Parse(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing ownership file of %s@%s", repo.Name, commitID)
+	}
+	return rs, nil
+}
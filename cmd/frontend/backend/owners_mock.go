@@ -0,0 +1,13 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+)
+
+type MockOwners struct {
+	Ruleset func(v0 context.Context, repo *types.Repo, commitID api.CommitID) (*codeowners.Ruleset, error)
+}
@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestOwners_Ruleset(t *testing.T) {
+	ctx := testContext()
+
+	const wantCommitID = "cccccccccccccccccccccccccccccccccccccccc"
+	repo := &types.Repo{Name: "a"}
+
+	tests := map[string]struct {
+		ownershipFiles []string
+		files          map[string]string
+		path           string
+		want           []string
+	}{
+		"no ownership file": {
+			files: map[string]string{},
+			path:  "main.go",
+			want:  nil,
+		},
+		"default ownership file": {
+			files: map[string]string{".github/CODEOWNERS": "*.go @go"},
+			path:  "main.go",
+			want:  []string{"@go"},
+		},
+		"first default ownership file takes precedence": {
+			files: map[string]string{"CODEOWNERS": "* @root", ".github/CODEOWNERS": "* @github"},
+			path:  "main.go",
+			want:  []string{"@root"},
+		},
+		"configured ownership files": {
+			ownershipFiles: []string{"OWNERS"},
+			files:          map[string]string{"OWNERS": "* @owners", "CODEOWNERS": "* @root"},
+			path:           "main.go",
+			want:           []string{"@owners"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rcache.SetupForTest(t)
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchOwnershipFiles: test.ownershipFiles}})
+			defer conf.Mock(nil)
+
+			git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+				if commit != wantCommitID {
+					t.Errorf("got commit %q, want %q", commit, wantCommitID)
+				}
+				if data, ok := test.files[name]; ok {
+					return []byte(data), nil
+				}
+				return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+			}
+			defer git.ResetMocks()
+
+			rs, err := Owners.Ruleset(ctx, repo, wantCommitID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := rs.Match(test.path); !reflect.DeepEqual(have, test.want) {
+				t.Errorf("got owners %q, want %q", have, test.want)
+			}
+		})
+	}
+}
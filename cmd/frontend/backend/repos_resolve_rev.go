@@ -0,0 +1,203 @@
+package backend
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"golang.org/x/sync/singleflight"
+)
+
+// resolveRevCacheTTL is how long a resolved revision is reused. Pages and
+// searches often resolve the same revisions of the same repositories many
+// times within a single request, so a short TTL suffices. It also bounds how
+// long a branch that was just pushed to resolves to its previous commit.
+const resolveRevCacheTTL = 2 * time.Second
+
+// resolveRevCacheSize is the maximum number of cached resolved revisions.
+const resolveRevCacheSize = 10000
+
+// resolveRevConcurrency is the maximum number of revisions that ResolveRevs
+// resolves concurrently.
+const resolveRevConcurrency = 16
+
+var resolveRevRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "backend",
+	Name:      "resolve_rev_requests_total",
+	Help:      "Calls to Repos.ResolveRev by whether they were served from the cache (hit), joined an identical call in flight (coalesced), or resolved the revision on gitserver (miss).",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(resolveRevRequests)
+}
+
+type resolveRevKey struct {
+	repo api.RepoID
+	rev  string
+}
+
+func (k resolveRevKey) String() string {
+	return strconv.Itoa(int(k.repo)) + "@" + k.rev
+}
+
+var (
+	resolveRevGroup singleflight.Group
+	resolvedRevs    = resolveRevCache{entries: map[resolveRevKey]resolveRevCacheEntry{}}
+)
+
+// resolveRevCache caches resolved revisions for a short time.
+type resolveRevCache struct {
+	mu      sync.Mutex
+	entries map[resolveRevKey]resolveRevCacheEntry
+}
+
+type resolveRevCacheEntry struct {
+	commitID api.CommitID
+	expires  time.Time
+}
+
+// resolveRevCacheNow is mocked in tests.
+var resolveRevCacheNow = time.Now
+
+func (c *resolveRevCache) get(key resolveRevKey) (api.CommitID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !resolveRevCacheNow().Before(e.expires) {
+		return "", false
+	}
+	return e.commitID, true
+}
+
+func (c *resolveRevCache) set(key resolveRevKey, commitID api.CommitID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := resolveRevCacheNow()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= resolveRevCacheSize {
+		// All entries have the same TTL, so the expired entries are the
+		// oldest. If none have expired, the cache is reset.
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= resolveRevCacheSize {
+			c.entries = map[resolveRevKey]resolveRevCacheEntry{}
+		}
+	}
+	c.entries[key] = resolveRevCacheEntry{commitID: commitID, expires: now.Add(resolveRevCacheTTL)}
+}
+
+func (c *resolveRevCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[resolveRevKey]resolveRevCacheEntry{}
+}
+
+// resolveRevCoalesced resolves rev in repo like resolveRev, but collapses the
+// identical calls that repository pages and searches issue in bursts: a
+// revision resolved within the last resolveRevCacheTTL is served from the
+// cache, and concurrent calls for the same revision share one request to
+// gitserver. Errors are not cached.
+//
+// The key is the repo's ID, so callers must have checked that the actor may
+// access the repo, which is the case for any *types.Repo obtained from the
+// database.
+func resolveRevCoalesced(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+	key := resolveRevKey{repo: repo.ID, rev: rev}
+	if commitID, ok := resolvedRevs.get(key); ok {
+		resolveRevRequests.WithLabelValues("hit").Inc()
+		return commitID, nil
+	}
+
+	leader := false
+	v, err, _ := resolveRevGroup.Do(key.String(), func() (interface{}, error) {
+		leader = true
+		commitID, err := resolveRev(ctx, repo, rev)
+		if err == nil && ctx.Err() == nil {
+			resolvedRevs.set(key, commitID)
+		}
+		return commitID, err
+	})
+
+	if leader {
+		resolveRevRequests.WithLabelValues("miss").Inc()
+		return v.(api.CommitID), err
+	}
+
+	resolveRevRequests.WithLabelValues("coalesced").Inc()
+	if err != nil && ctx.Err() == nil && (err == context.Canceled || err == context.DeadlineExceeded) {
+		// The call we joined ran out of time on behalf of its own caller,
+		// but we still have time.
+		return resolveRev(ctx, repo, rev)
+	}
+	return v.(api.CommitID), err
+}
+
+// RepoRev is a revision of a repository, as given to ResolveRevs.
+type RepoRev struct {
+	Repo *types.Repo
+	Rev  string
+}
+
+// ResolvedRev is the result of resolving a RepoRev with ResolveRevs.
+type ResolvedRev struct {
+	CommitID api.CommitID
+	Err      error
+}
+
+// ResolveRevs resolves many revisions (e.g. of all the repositories shown on a
+// page) like ResolveRev, concurrently. Duplicate revisions are only resolved
+// once. The i-th result is the result of resolving the i-th revision.
+func (s *repos) ResolveRevs(ctx context.Context, revs []RepoRev) []ResolvedRev {
+	type result struct {
+		ResolvedRev
+		indexes []int
+	}
+
+	unique := map[resolveRevKey]*result{}
+	var keys []resolveRevKey
+	for i, rr := range revs {
+		key := resolveRevKey{repo: rr.Repo.ID, rev: rr.Rev}
+		if res, ok := unique[key]; ok {
+			res.indexes = append(res.indexes, i)
+			continue
+		}
+		unique[key] = &result{indexes: []int{i}}
+		keys = append(keys, key)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, resolveRevConcurrency)
+	)
+	for _, key := range keys {
+		res := unique[key]
+		rr := revs[res.indexes[0]]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res.CommitID, res.Err = s.ResolveRev(ctx, rr.Repo, rr.Rev)
+		}()
+	}
+	wg.Wait()
+
+	resolved := make([]ResolvedRev, len(revs))
+	for _, res := range unique {
+		for _, i := range res.indexes {
+			resolved[i] = res.ResolvedRev
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,133 @@
+package backend
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestRepos_ResolveRev_coalesced(t *testing.T) {
+	ctx := testContext()
+	defer resolvedRevs.reset()
+
+	now := time.Now()
+	resolveRevCacheNow = func() time.Time { return now }
+	defer func() { resolveRevCacheNow = time.Now }()
+
+	var calls int32
+	release := make(chan struct{})
+	git.Mocks.ResolveRevision = func(rev string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return api.CommitID("c-" + rev), nil
+	}
+	defer git.ResetMocks()
+
+	repo := &types.Repo{ID: 1, Name: "a"}
+
+	// Concurrent calls share one request to gitserver.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if commitID, err := Repos.ResolveRev(ctx, repo, "b"); err != nil || commitID != "c-b" {
+				t.Errorf("got %q, %v, want c-b", commitID, err)
+			}
+		}()
+	}
+	// Give the goroutines time to join the call in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("got %d calls for concurrent resolves, want 1", got)
+	}
+
+	// The result is reused until it expires.
+	if _, err := Repos.ResolveRev(ctx, repo, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("got %d calls after resolving a cached revision, want 1", got)
+	}
+
+	now = now.Add(resolveRevCacheTTL)
+	if _, err := Repos.ResolveRev(ctx, repo, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("got %d calls after resolving an expired revision, want 2", got)
+	}
+}
+
+func TestRepos_ResolveRev_errorsNotCached(t *testing.T) {
+	ctx := testContext()
+	defer resolvedRevs.reset()
+
+	var calls int
+	git.Mocks.ResolveRevision = func(rev string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		calls++
+		return "", &gitserver.RevisionNotFoundError{Repo: "a", Spec: rev}
+	}
+	defer git.ResetMocks()
+
+	repo := &types.Repo{ID: 1, Name: "a"}
+	for i := 0; i < 2; i++ {
+		if _, err := Repos.ResolveRev(ctx, repo, "b"); !gitserver.IsRevisionNotFound(err) {
+			t.Fatalf("got error %v, want revision not found", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestRepos_ResolveRevs(t *testing.T) {
+	ctx := testContext()
+	defer resolvedRevs.reset()
+
+	var (
+		mu    sync.Mutex
+		calls = map[string]int{}
+	)
+	git.Mocks.ResolveRevision = func(rev string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		mu.Lock()
+		calls[rev]++
+		mu.Unlock()
+		if rev == "missing" {
+			return "", &gitserver.RevisionNotFoundError{Spec: rev}
+		}
+		return api.CommitID("c-" + rev), nil
+	}
+	defer git.ResetMocks()
+
+	a, b := &types.Repo{ID: 1, Name: "a"}, &types.Repo{ID: 2, Name: "b"}
+	resolved := Repos.ResolveRevs(ctx, []RepoRev{
+		{Repo: a, Rev: "x"},
+		{Repo: b, Rev: "y"},
+		{Repo: a, Rev: "x"},
+		{Repo: b, Rev: "missing"},
+	})
+
+	if len(resolved) != 4 {
+		t.Fatalf("got %d results, want 4", len(resolved))
+	}
+	for i, want := range []api.CommitID{"c-x", "c-y", "c-x"} {
+		if resolved[i].CommitID != want || resolved[i].Err != nil {
+			t.Errorf("%d: got %+v, want %q", i, resolved[i], want)
+		}
+	}
+	if !gitserver.IsRevisionNotFound(resolved[3].Err) {
+		t.Errorf("got error %v, want revision not found", resolved[3].Err)
+	}
+	if calls["x"] != 1 {
+		t.Errorf("got %d calls for a duplicate revision, want 1", calls["x"])
+	}
+}
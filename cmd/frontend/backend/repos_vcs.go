@@ -141,6 +141,9 @@ func hasGitLabDotComToken(ctx context.Context) (bool, error) {
 // * Empty repository: git.RevisionNotFoundError
 // * The user does not have permission: errcode.IsNotFound
 // * Other unexpected errors.
+//
+// Concurrent calls for the same repo and rev are coalesced into one, and
+// their result is reused for a short time (see resolveRevCoalesced).
 func (s *repos) ResolveRev(ctx context.Context, repo *types.Repo, rev string) (commitID api.CommitID, err error) {
 	if Mocks.Repos.ResolveRev != nil {
 		return Mocks.Repos.ResolveRev(ctx, repo, rev)
@@ -149,6 +152,11 @@ func (s *repos) ResolveRev(ctx context.Context, repo *types.Repo, rev string) (c
 	ctx, done := trace(ctx, "Repos", "ResolveRev", map[string]interface{}{"repo": repo.Name, "rev": rev}, &err)
 	defer done()
 
+	return resolveRevCoalesced(ctx, repo, rev)
+}
+
+// resolveRev resolves rev in repo on gitserver. See Repos.ResolveRev.
+func resolveRev(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
 	// We start out by using a CachedGitRepo which doesn't have a remote URL.
 	// If we need the remote URL, git.ResolveRevision will ask for it via
 	// remoteURLFunc (which is costly as it e.g. consumes code host API
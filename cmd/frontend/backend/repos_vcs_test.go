@@ -15,6 +15,7 @@ import (
 
 func TestRepos_ResolveRev_noRevSpecified_getsDefaultBranch(t *testing.T) {
 	ctx := testContext()
+	defer resolvedRevs.reset()
 
 	const wantRepo = "a"
 	want := strings.Repeat("a", 40)
@@ -55,6 +56,7 @@ func TestRepos_ResolveRev_noRevSpecified_getsDefaultBranch(t *testing.T) {
 
 func TestRepos_ResolveRev_noCommitIDSpecified_resolvesRev(t *testing.T) {
 	ctx := testContext()
+	defer resolvedRevs.reset()
 
 	const wantRepo = "a"
 	want := strings.Repeat("a", 40)
@@ -94,6 +96,7 @@ func TestRepos_ResolveRev_noCommitIDSpecified_resolvesRev(t *testing.T) {
 
 func TestRepos_ResolveRev_commitIDSpecified_resolvesCommitID(t *testing.T) {
 	ctx := testContext()
+	defer resolvedRevs.reset()
 
 	const wantRepo = "a"
 	want := strings.Repeat("a", 40)
@@ -133,6 +136,7 @@ func TestRepos_ResolveRev_commitIDSpecified_resolvesCommitID(t *testing.T) {
 
 func TestRepos_ResolveRev_commitIDSpecified_failsToResolve(t *testing.T) {
 	ctx := testContext()
+	defer resolvedRevs.reset()
 
 	const wantRepo = "a"
 	want := errors.New("x")
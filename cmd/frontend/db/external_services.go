@@ -15,6 +15,7 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
@@ -42,6 +43,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	"GITHUB":          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	"GITLAB":          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	"GITOLITE":        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
+	"MANIFEST":        {CodeHost: true, JSONSchema: schema.ManifestSchemaJSON},
 	"PHABRICATOR":     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
 	"OTHER":           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
@@ -232,11 +234,33 @@ func (c *ExternalServicesStore) Create(ctx context.Context, confGet func() *conf
 	externalService.CreatedAt = time.Now()
 	externalService.UpdatedAt = externalService.CreatedAt
 
-	return dbconn.Global.QueryRowContext(
-		ctx,
-		"INSERT INTO external_services(kind, display_name, config, created_at, updated_at) VALUES($1, $2, $3, $4, $5) RETURNING id",
-		externalService.Kind, externalService.DisplayName, externalService.Config, externalService.CreatedAt, externalService.UpdatedAt,
-	).Scan(&externalService.ID)
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			"INSERT INTO external_services(kind, display_name, config, created_at, updated_at) VALUES($1, $2, $3, $4, $5) RETURNING id",
+			externalService.Kind, externalService.DisplayName, externalService.Config, externalService.CreatedAt, externalService.UpdatedAt,
+		).Scan(&externalService.ID)
+		if err != nil {
+			return err
+		}
+		return insertExternalServiceConfigRevision(ctx, tx, externalService.ID, externalService.Config)
+	})
+}
+
+// insertExternalServiceConfigRevision records config as the latest
+// configuration revision of the external service, authored by the actor.
+func insertExternalServiceConfigRevision(ctx context.Context, tx *sql.Tx, id int64, config string) error {
+	var authorUserID *int32
+	if a := actor.FromContext(ctx); a.IsAuthenticated() {
+		authorUserID = &a.UID
+	}
+	q := sqlf.Sprintf(`
+INSERT INTO external_service_config_revisions(external_service_id, revision, author_user_id, config)
+SELECT %d, COALESCE(MAX(revision), 0) + 1, %s, %s FROM external_service_config_revisions WHERE external_service_id=%d`,
+		id, authorUserID, config, id,
+	)
+	_, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
 }
 
 // ExternalServiceUpdate contains optional fields to update.
@@ -245,10 +269,12 @@ type ExternalServiceUpdate struct {
 	Config      *string
 }
 
-// Update updates a external service.
+// Update updates a external service. A changed configuration is recorded as a
+// new configuration revision.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
 func (c *ExternalServicesStore) Update(ctx context.Context, ps []schema.AuthProviders, id int64, update *ExternalServiceUpdate) error {
+	configChanged := false
 	if update.Config != nil {
 		// Query to get the kind (which is immutable) so we can validate the new config.
 		externalService, err := c.GetByID(ctx, id)
@@ -259,6 +285,7 @@ func (c *ExternalServicesStore) Update(ctx context.Context, ps []schema.AuthProv
 		if err := c.ValidateConfig(externalService.Kind, *update.Config, ps); err != nil {
 			return err
 		}
+		configChanged = *update.Config != externalService.Config
 	}
 
 	execUpdate := func(ctx context.Context, tx *sql.Tx, update *sqlf.Query) error {
@@ -287,10 +314,112 @@ func (c *ExternalServicesStore) Update(ctx context.Context, ps []schema.AuthProv
 				return err
 			}
 		}
+		if configChanged {
+			return insertExternalServiceConfigRevision(ctx, tx, id, *update.Config)
+		}
 		return nil
 	})
 }
 
+type externalServiceConfigRevisionNotFoundError struct {
+	id       int64
+	revision int32
+}
+
+func (e externalServiceConfigRevisionNotFoundError) Error() string {
+	return fmt.Sprintf("external service configuration revision not found: %v (external service %v)", e.revision, e.id)
+}
+
+func (e externalServiceConfigRevisionNotFoundError) NotFound() bool {
+	return true
+}
+
+// GetConfigRevision returns the given configuration revision of the external
+// service.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (c *ExternalServicesStore) GetConfigRevision(ctx context.Context, id int64, revision int32) (*types.ExternalServiceConfigRevision, error) {
+	if Mocks.ExternalServices.GetConfigRevision != nil {
+		return Mocks.ExternalServices.GetConfigRevision(id, revision)
+	}
+
+	conds := []*sqlf.Query{sqlf.Sprintf("external_service_id=%d", id), sqlf.Sprintf("revision=%d", revision)}
+	revisions, err := c.listConfigRevisions(ctx, conds, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, externalServiceConfigRevisionNotFoundError{id: id, revision: revision}
+	}
+	return revisions[0], nil
+}
+
+// ExternalServiceConfigRevisionsListOptions contains options for listing the
+// configuration revisions of an external service.
+type ExternalServiceConfigRevisionsListOptions struct {
+	ExternalServiceID int64
+	*LimitOffset
+}
+
+func (o ExternalServiceConfigRevisionsListOptions) sqlConditions() []*sqlf.Query {
+	return []*sqlf.Query{sqlf.Sprintf("external_service_id=%d", o.ExternalServiceID)}
+}
+
+// ListConfigRevisions lists the configuration revisions of an external
+// service, latest first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (c *ExternalServicesStore) ListConfigRevisions(ctx context.Context, opt ExternalServiceConfigRevisionsListOptions) ([]*types.ExternalServiceConfigRevision, error) {
+	if Mocks.ExternalServices.ListConfigRevisions != nil {
+		return Mocks.ExternalServices.ListConfigRevisions(opt)
+	}
+	return c.listConfigRevisions(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (*ExternalServicesStore) listConfigRevisions(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*types.ExternalServiceConfigRevision, error) {
+	q := sqlf.Sprintf(`
+		SELECT external_service_id, revision, author_user_id, config, created_at
+		FROM external_service_config_revisions
+		WHERE (%s)
+		ORDER BY revision DESC
+		%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*types.ExternalServiceConfigRevision
+	for rows.Next() {
+		var r types.ExternalServiceConfigRevision
+		if err := rows.Scan(&r.ExternalServiceID, &r.Revision, &r.AuthorUserID, &r.Config, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountConfigRevisions counts the configuration revisions that satisfy the
+// options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*ExternalServicesStore) CountConfigRevisions(ctx context.Context, opt ExternalServiceConfigRevisionsListOptions) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM external_service_config_revisions WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 type externalServiceNotFoundError struct {
 	id int64
 }
@@ -510,6 +639,8 @@ func (c *ExternalServicesStore) Count(ctx context.Context, opt ExternalServicesL
 
 // MockExternalServices mocks the external services store.
 type MockExternalServices struct {
-	GetByID func(id int64) (*types.ExternalService, error)
-	List    func(opt ExternalServicesListOptions) ([]*types.ExternalService, error)
+	GetByID             func(id int64) (*types.ExternalService, error)
+	List                func(opt ExternalServicesListOptions) ([]*types.ExternalService, error)
+	GetConfigRevision   func(id int64, revision int32) (*types.ExternalServiceConfigRevision, error)
+	ListConfigRevisions func(opt ExternalServiceConfigRevisionsListOptions) ([]*types.ExternalServiceConfigRevision, error)
 }
@@ -1,6 +1,14 @@
 package db
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
 
 func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 	tests := map[string]struct {
@@ -36,3 +44,66 @@ func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExternalServicesStore_ConfigRevisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &types.ExternalService{Kind: "OTHER", DisplayName: "other", Config: `{"url": "https://example.com", "repos": ["a"]}`}
+	confGet := func() *conf.Unified { return &conf.Unified{} }
+	if err := ExternalServices.Create(ctx, confGet, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only configuration changes are recorded, and the actor is their author.
+	config := `{"url": "https://example.com", "repos": ["b"]}`
+	actorCtx := actor.WithActor(ctx, &actor.Actor{UID: user.ID})
+	for i := 0; i < 2; i++ {
+		if err := ExternalServices.Update(actorCtx, nil, svc.ID, &ExternalServiceUpdate{Config: &config}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	displayName := "renamed"
+	if err := ExternalServices.Update(actorCtx, nil, svc.ID, &ExternalServiceUpdate{DisplayName: &displayName}); err != nil {
+		t.Fatal(err)
+	}
+
+	opt := ExternalServiceConfigRevisionsListOptions{ExternalServiceID: svc.ID}
+	revisions, err := ExternalServices.ListConfigRevisions(ctx, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revisions))
+	}
+	if r := revisions[0]; r.Revision != 2 || r.Config != config || r.AuthorUserID == nil || *r.AuthorUserID != user.ID {
+		t.Errorf("got latest revision %+v", r)
+	}
+	if r := revisions[1]; r.Revision != 1 || r.Config != svc.Config || r.AuthorUserID != nil {
+		t.Errorf("got first revision %+v", r)
+	}
+	if count, err := ExternalServices.CountConfigRevisions(ctx, opt); err != nil {
+		t.Fatal(err)
+	} else if count != 2 {
+		t.Errorf("got count %d, want 2", count)
+	}
+
+	r, err := ExternalServices.GetConfigRevision(ctx, svc.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Config != svc.Config {
+		t.Errorf("got config %q, want %q", r.Config, svc.Config)
+	}
+	if _, err := ExternalServices.GetConfigRevision(ctx, svc.ID, 3); err == nil {
+		t.Error("expected error for missing revision")
+	}
+}
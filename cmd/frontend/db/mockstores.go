@@ -25,4 +25,16 @@ type MockStores struct {
 	OrgInvitations MockOrgInvitations
 
 	ExternalServices MockExternalServices
+
+	SearchHistory MockSearchHistory
+
+	SearchIndexExclusions MockSearchIndexExclusions
+
+	SearchJobs MockSearchJobs
+
+	RepoKVPs MockRepoKVPs
+
+	RepoIntroductions MockRepoIntroductions
+
+	Storage MockStorage
 }
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// repoIntroductions reads the append-only record of when each external
+// service first yielded each repository, which repo-updater writes when it
+// syncs repositories.
+type repoIntroductions struct{}
+
+// RepoIntroductionsListOptions contains options for listing repository
+// introductions.
+type RepoIntroductionsListOptions struct {
+	// ExternalServiceID, if non-zero, only lists the repositories introduced by
+	// this external service.
+	ExternalServiceID int64
+	*LimitOffset
+}
+
+func (o RepoIntroductionsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ExternalServiceID != 0 {
+		conds = append(conds, sqlf.Sprintf("external_service_id=%d", o.ExternalServiceID))
+	}
+	return conds
+}
+
+// List lists repository introductions, latest first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoIntroductions) List(ctx context.Context, opt RepoIntroductionsListOptions) ([]*types.RepoIntroduction, error) {
+	if Mocks.RepoIntroductions.List != nil {
+		return Mocks.RepoIntroductions.List(opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, repo_id, repo_name, external_service_id, author_user_id, created_at FROM repo_introductions
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var introductions []*types.RepoIntroduction
+	for rows.Next() {
+		var i types.RepoIntroduction
+		if err := rows.Scan(&i.ID, &i.RepoID, &i.RepoName, &i.ExternalServiceID, &i.AuthorUserID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		introductions = append(introductions, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return introductions, nil
+}
+
+// Count counts the repository introductions that satisfy the options
+// (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoIntroductions) Count(ctx context.Context, opt RepoIntroductionsListOptions) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM repo_introductions WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MockRepoIntroductions mocks the repository introductions store.
+type MockRepoIntroductions struct {
+	List func(opt RepoIntroductionsListOptions) ([]*types.RepoIntroduction, error)
+}
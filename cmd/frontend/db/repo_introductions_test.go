@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestRepoIntroductions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO repo_introductions(repo_id, repo_name, external_service_id, author_user_id)
+VALUES (1, 'github.com/foo/a', 1, NULL), (2, 'github.com/foo/b', 1, 7), (2, 'github.com/foo/b', 2, NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	introductions, err := RepoIntroductions.List(ctx, RepoIntroductionsListOptions{ExternalServiceID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(introductions) != 2 {
+		t.Fatalf("got %d introductions, want 2", len(introductions))
+	}
+	if i := introductions[0]; i.RepoName != "github.com/foo/b" || i.AuthorUserID == nil || *i.AuthorUserID != 7 {
+		t.Errorf("got latest introduction %+v, want github.com/foo/b by user 7", i)
+	}
+	if i := introductions[1]; i.RepoName != "github.com/foo/a" || i.AuthorUserID != nil {
+		t.Errorf("got earliest introduction %+v, want github.com/foo/a by an unknown author", i)
+	}
+
+	introductions, err = RepoIntroductions.List(ctx, RepoIntroductionsListOptions{LimitOffset: &LimitOffset{Limit: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(introductions) != 1 || introductions[0].ExternalServiceID != 2 {
+		t.Errorf("got introductions %+v, want the one by external service 2", introductions)
+	}
+
+	if count, err := RepoIntroductions.Count(ctx, RepoIntroductionsListOptions{}); err != nil {
+		t.Fatal(err)
+	} else if count != 3 {
+		t.Errorf("got count %d, want 3", count)
+	}
+}
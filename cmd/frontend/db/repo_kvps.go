@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// ErrRepoKVPNotFound occurs when a database operation expects a specific
+// key-value pair of a repository to exist but it does not exist.
+var ErrRepoKVPNotFound = errors.New("repository key-value pair not found")
+
+// A RepoKVP is a key-value pair attached to a repository, e.g. to tag it with
+// the team that owns it. The value is optional.
+type RepoKVP struct {
+	Key   string
+	Value *string
+}
+
+// A RepoKVPFilter matches repositories that have a key-value pair with the
+// given key and, if Value is set, the given value.
+type RepoKVPFilter struct {
+	Key   string
+	Value *string
+}
+
+func (f RepoKVPFilter) sql() *sqlf.Query {
+	cond := sqlf.Sprintf("kvp.repo_id = repo.id AND kvp.key = %s", f.Key)
+	if f.Value != nil {
+		cond = sqlf.Sprintf("%s AND kvp.value = %s", cond, *f.Value)
+	}
+	return sqlf.Sprintf("EXISTS (SELECT 1 FROM repo_kvps kvp WHERE %s)", cond)
+}
+
+type repoKVPs struct{}
+
+// List lists the key-value pairs of the repository, ordered by key.
+func (*repoKVPs) List(ctx context.Context, repoID api.RepoID) ([]RepoKVP, error) {
+	if Mocks.RepoKVPs.List != nil {
+		return Mocks.RepoKVPs.List(repoID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT key, value FROM repo_kvps WHERE repo_id=$1 ORDER BY key ASC", repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var kvps []RepoKVP
+	for rows.Next() {
+		var kvp RepoKVP
+		if err := rows.Scan(&kvp.Key, &kvp.Value); err != nil {
+			return nil, err
+		}
+		kvps = append(kvps, kvp)
+	}
+	return kvps, rows.Err()
+}
+
+// Set sets the value of the key-value pair of the repository with the given
+// key, adding the pair if it doesn't exist yet.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or an
+// internal service.
+func (*repoKVPs) Set(ctx context.Context, repoID api.RepoID, key string, value *string) error {
+	if Mocks.RepoKVPs.Set != nil {
+		return Mocks.RepoKVPs.Set(repoID, key, value)
+	}
+
+	if key == "" {
+		return errors.New("empty repository key-value pair key")
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO repo_kvps(repo_id, key, value) VALUES($1, $2, $3)
+ON CONFLICT (repo_id, key) DO UPDATE SET value=EXCLUDED.value`,
+		repoID, key, value,
+	)
+	return err
+}
+
+// Delete deletes the key-value pair of the repository with the given key. If
+// no such pair exists, ErrRepoKVPNotFound is returned.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or an
+// internal service.
+func (*repoKVPs) Delete(ctx context.Context, repoID api.RepoID, key string) error {
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM repo_kvps WHERE repo_id=$1 AND key=$2", repoID, key)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrRepoKVPNotFound
+	}
+	return nil
+}
+
+type MockRepoKVPs struct {
+	List func(repoID api.RepoID) ([]RepoKVP, error)
+	Set  func(repoID api.RepoID, key string, value *string) error
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	regexpsyntax "regexp/syntax"
 	"strings"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db/query"
@@ -130,6 +132,7 @@ var getBySQLColumns = []string{
 	"external_id",
 	"external_service_type",
 	"external_service_id",
+	"visibility_override",
 	"uri",
 	"description",
 	"language",
@@ -142,7 +145,7 @@ func (s *repos) getBySQL(ctx context.Context, querySuffix *sqlf.Query) ([]*types
 func (s *repos) getReposBySQL(ctx context.Context, minimal bool, querySuffix *sqlf.Query) ([]*types.Repo, error) {
 	columns := getBySQLColumns
 	if minimal {
-		columns = columns[:5]
+		columns = columns[:6]
 	}
 
 	q := sqlf.Sprintf(
@@ -185,6 +188,7 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 			&dbutil.NullString{S: &r.ExternalRepo.ID},
 			&dbutil.NullString{S: &r.ExternalRepo.ServiceType},
 			&dbutil.NullString{S: &r.ExternalRepo.ServiceID},
+			&dbutil.NullString{S: (*string)(&r.VisibilityOverride)},
 		)
 	}
 
@@ -194,6 +198,7 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		&dbutil.NullString{S: &r.ExternalRepo.ID},
 		&dbutil.NullString{S: &r.ExternalRepo.ServiceType},
 		&dbutil.NullString{S: &r.ExternalRepo.ServiceID},
+		&dbutil.NullString{S: (*string)(&r.VisibilityOverride)},
 		&dbutil.NullString{S: &r.URI},
 		&r.Description,
 		&r.Language,
@@ -238,6 +243,26 @@ type ReposListOptions struct {
 	// OnlyArchived excludes non-archived repositories from the list.
 	OnlyArchived bool
 
+	// NoArchivedMirrors excludes archived mirrors, repositories which were
+	// removed from their code host, from the list.
+	NoArchivedMirrors bool
+
+	// OnlyArchivedMirrors excludes repositories which are not archived mirrors
+	// from the list.
+	OnlyArchivedMirrors bool
+
+	// ExternalServiceID, if non-zero, only includes repositories which were
+	// synced from the external service with this ID.
+	ExternalServiceID int64
+
+	// KVPFilters only includes repositories which match all of the filters
+	// by their key-value pairs.
+	KVPFilters []RepoKVPFilter
+
+	// ExcludeKVPFilters excludes repositories which match any of the filters
+	// by their key-value pairs.
+	ExcludeKVPFilters []RepoKVPFilter
+
 	// OnlyRepoIDs skips fetching of RepoFields in each Repo.
 	OnlyRepoIDs bool
 
@@ -315,6 +340,80 @@ func (s *repos) List(ctx context.Context, opt ReposListOptions) (results []*type
 	return s.getReposBySQL(ctx, opt.OnlyRepoIDs, fetchSQL)
 }
 
+// ReposSearchOptions specifies the options for searching repositories.
+type ReposSearchOptions struct {
+	// Query is the text to search for in the names and descriptions of
+	// repositories. It is matched case-insensitively.
+	Query string
+
+	*LimitOffset
+}
+
+// Search returns the enabled repositories whose name or description contains
+// opt.Query, ordered by how similar their names and then their descriptions
+// are to it. Unlike listing repositories by a pattern, this uses the trigram
+// indexes over the name and description columns instead of scanning the whole
+// repo table.
+func (s *repos) Search(ctx context.Context, opt ReposSearchOptions) (results []*types.Repo, err error) {
+	tr, ctx := trace.New(ctx, "repos.Search", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if Mocks.Repos.Search != nil {
+		return Mocks.Repos.Search(ctx, opt)
+	}
+
+	if opt.Query == "" {
+		return nil, errors.New("Repos.Search: empty query")
+	}
+
+	q := strings.ToLower(opt.Query)
+	like := "%" + escapeLikePattern(q) + "%"
+	fetchSQL := sqlf.Sprintf(
+		"(lower(name) LIKE %s OR lower(description) LIKE %s) ORDER BY similarity(lower(name), %s) DESC, similarity(lower(coalesce(description, '')), %s) DESC, id ASC %s",
+		like, like, q, q, opt.LimitOffset.SQL(),
+	)
+	tr.LazyPrintf("SQL query: %s, SQL args: %v", fetchSQL.Query(sqlf.PostgresBindVar), fetchSQL.Args())
+	return s.getReposBySQL(ctx, false, fetchSQL)
+}
+
+// ListSimilar returns up to limit enabled repositories whose names contain
+// words similar to name, by trigram similarity, ordered from the most similar.
+// It is used to suggest the repositories a name with a typo was meant to
+// refer to.
+func (s *repos) ListSimilar(ctx context.Context, name string, limit int) (results []*types.Repo, err error) {
+	tr, ctx := trace.New(ctx, "repos.ListSimilar", name)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if Mocks.Repos.ListSimilar != nil {
+		return Mocks.Repos.ListSimilar(ctx, name, limit)
+	}
+
+	if name == "" {
+		return nil, errors.New("Repos.ListSimilar: empty name")
+	}
+
+	// The <% operator uses the trigram index over lower(name).
+	q := strings.ToLower(name)
+	fetchSQL := sqlf.Sprintf(
+		"%s <%% lower(name) ORDER BY word_similarity(%s, lower(name)) DESC, similarity(%s, lower(name)) DESC, id ASC LIMIT %s",
+		q, q, q, limit,
+	)
+	tr.LazyPrintf("SQL query: %s, SQL args: %v", fetchSQL.Query(sqlf.PostgresBindVar), fetchSQL.Args())
+	return s.getReposBySQL(ctx, true, fetchSQL)
+}
+
+// escapeLikePattern escapes the characters of s that have a special meaning
+// in LIKE patterns.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // ListEnabledNames returns a list of all enabled repo names. This is commonly
 // requested information by other services (repo-updater and
 // indexed-search). We special case just returning enabled names so that we
@@ -434,14 +533,43 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	if opt.OnlyArchived {
 		conds = append(conds, sqlf.Sprintf("archived"))
 	}
+	if opt.NoArchivedMirrors {
+		conds = append(conds, sqlf.Sprintf("NOT archived_mirror"))
+	}
+	if opt.OnlyArchivedMirrors {
+		conds = append(conds, sqlf.Sprintf("archived_mirror"))
+	}
+	if opt.ExternalServiceID != 0 {
+		// repo.sources is keyed by the URNs of the external services the repo
+		// was synced from (see repos.ExternalService.URN in repo-updater).
+		conds = append(conds, sqlf.Sprintf(`EXISTS (
+			SELECT 1 FROM external_services es
+			WHERE es.id = %s AND repo.sources ? ('extsvc:' || lower(es.kind) || ':' || es.id)
+		)`, opt.ExternalServiceID))
+	}
+	for _, f := range opt.KVPFilters {
+		conds = append(conds, f.sql())
+	}
+	for _, f := range opt.ExcludeKVPFilters {
+		conds = append(conds, sqlf.Sprintf("NOT %s", f.sql()))
+	}
 
 	if opt.Index != nil {
 		// We don't currently have an index column, but when we want the
 		// indexable repositories to be a subset it will live in the database
 		// layer. So we do the filtering here.
 		indexAll := conf.SearchIndexEnabled()
-		if indexAll != *opt.Index {
-			conds = append(conds, sqlf.Sprintf("false"))
+		excluded := sqlf.Sprintf("EXISTS (SELECT 1 FROM search_index_exclusions e WHERE e.repo_id = repo.id)")
+		switch {
+		case !indexAll:
+			if *opt.Index {
+				conds = append(conds, sqlf.Sprintf("false"))
+			}
+		case *opt.Index:
+			// Site admins can exclude repositories from indexing.
+			conds = append(conds, sqlf.Sprintf("NOT %s", excluded))
+		default:
+			conds = append(conds, excluded)
 		}
 	}
 
@@ -615,6 +743,55 @@ func (s *repos) Delete(ctx context.Context, repo api.RepoID) error {
 	return err
 }
 
+// RepoDeletion describes the data that was deleted along with a repository.
+type RepoDeletion struct {
+	// Changesets is the number of changesets on the repository that were
+	// deleted, which detached them from their campaigns.
+	Changesets int
+	// Campaigns is the number of campaigns that the changesets were detached
+	// from.
+	Campaigns int
+}
+
+// DeleteCascading deletes the repository like Delete, along with the data
+// that references it, and reports on that data. Changesets are detached from
+// their campaigns by the trig_delete_changeset_reference_on_campaigns trigger.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *repos) DeleteCascading(ctx context.Context, repo api.RepoID) (*RepoDeletion, error) {
+	if Mocks.Repos.DeleteCascading != nil {
+		return Mocks.Repos.DeleteCascading(ctx, repo)
+	}
+
+	// The changesets CTE sees the rows as of before the repository (and, by
+	// cascading, its changesets) is deleted.
+	q := sqlf.Sprintf(`
+WITH cs AS (
+  SELECT id, campaign_ids FROM changesets WHERE repo_id = %d
+),
+deleted AS (
+  DELETE FROM repo WHERE id = %d RETURNING id
+)
+SELECT
+  (SELECT COUNT(*) FROM deleted),
+  (SELECT COUNT(*) FROM cs),
+  (SELECT COUNT(DISTINCT campaign_id) FROM cs, jsonb_object_keys(cs.campaign_ids) AS campaign_id)
+`, repo, repo)
+
+	var (
+		deleted int
+		d       RepoDeletion
+	)
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&deleted, &d.Changesets, &d.Campaigns)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, &repoNotFoundErr{ID: repo}
+	}
+	return &d, nil
+}
+
 func (s *repos) SetEnabled(ctx context.Context, id api.RepoID, enabled bool) error {
 	q := sqlf.Sprintf("UPDATE repo SET enabled=%t WHERE id=%d", enabled, id)
 	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -631,11 +808,74 @@ func (s *repos) SetEnabled(ctx context.Context, id api.RepoID, enabled bool) err
 	return nil
 }
 
+// SetVisibilityOverride sets the visibility of the repository on Sourcegraph,
+// regardless of its visibility on the code host. An empty visibility removes
+// the override.
+func (s *repos) SetVisibilityOverride(ctx context.Context, id api.RepoID, visibility types.RepoVisibility) error {
+	q := sqlf.Sprintf("UPDATE repo SET visibility_override=NULLIF(%s, '') WHERE id=%d", string(visibility), id)
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &repoNotFoundErr{ID: id}
+	}
+	return nil
+}
+
 func (s *repos) UpdateLanguage(ctx context.Context, repo api.RepoID, language string) error {
 	_, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET language=$1 WHERE id=$2", language, repo)
 	return err
 }
 
+// UpdateLastCommitAt records the committer date of the latest commit on the
+// default branch of the repository. It is maintained by the repo-updater
+// scheduler after fetches.
+func (s *repos) UpdateLastCommitAt(ctx context.Context, name api.RepoName, lastCommitAt time.Time) error {
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET last_commit_at=$1 WHERE name=$2", lastCommitAt, name)
+	return err
+}
+
+// ListLastCommitAt returns the recorded committer dates of the latest commits
+// on the default branches of the given repositories. Repositories whose date
+// has not been recorded yet are not included.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is allowed to read the
+// repositories.
+func (s *repos) ListLastCommitAt(ctx context.Context, ids []api.RepoID) (map[api.RepoID]time.Time, error) {
+	if Mocks.Repos.ListLastCommitAt != nil {
+		return Mocks.Repos.ListLastCommitAt(ctx, ids)
+	}
+
+	ints := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		ints = append(ints, int64(id))
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT id, last_commit_at FROM repo WHERE id = ANY($1) AND last_commit_at IS NOT NULL", pq.Array(ints))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dates := make(map[api.RepoID]time.Time, len(ids))
+	for rows.Next() {
+		var (
+			id api.RepoID
+			t  time.Time
+		)
+		if err := rows.Scan(&id, &t); err != nil {
+			return nil, err
+		}
+		dates[id] = t
+	}
+	return dates, rows.Err()
+}
+
 func (s *repos) UpdateRepositoryMetadata(ctx context.Context, name api.RepoName, description string, fork bool, archived bool) error {
 	_, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET description=$1, fork=$2, archived=$3 WHERE name=$4 	AND (description <> $1 OR fork <> $2 OR archived <> $3)", description, fork, archived, name)
 	return err
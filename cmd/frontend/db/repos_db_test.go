@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
 )
 
@@ -161,6 +162,53 @@ func TestRepos_List_fork(t *testing.T) {
 	}
 }
 
+func TestRepos_List_externalService(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	if _, err := dbconn.Global.ExecContext(ctx, `INSERT INTO external_services(id, kind, display_name, config) VALUES (1, 'GITHUB', 'GitHub', '{}'), (2, 'GITLAB', 'GitLab', '{}')`); err != nil {
+		t.Fatal(err)
+	}
+
+	github := mustCreate(ctx, t, &types.Repo{Name: "github.com/a/r"})
+	gitlab := mustCreate(ctx, t, &types.Repo{Name: "gitlab.com/b/r"})
+	for _, r := range []struct {
+		name    api.RepoName
+		sources string
+	}{
+		{github[0].Name, `{"extsvc:github:1": {}}`},
+		{gitlab[0].Name, `{"extsvc:gitlab:2": {}}`},
+	} {
+		if _, err := dbconn.Global.ExecContext(ctx, `UPDATE repo SET sources = $1 WHERE name = $2`, r.sources, r.name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		id   int64
+		want []*types.Repo
+	}{
+		{id: 1, want: github},
+		{id: 2, want: gitlab},
+		{id: 3, want: nil},
+	} {
+		repos, err := Repos.List(ctx, ReposListOptions{Enabled: true, ExternalServiceID: tc.id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertJSONEqual(t, tc.want, repos)
+	}
+}
+
 func TestRepos_List_pagination(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -255,6 +303,91 @@ func TestRepos_List_query1(t *testing.T) {
 	}
 }
 
+func TestRepos_Search(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	createdRepos := []*types.Repo{
+		{Name: "github.com/foo/search-api", RepoFields: &types.RepoFields{Description: "The API"}},
+		{Name: "github.com/foo/web", RepoFields: &types.RepoFields{Description: "Search UI"}},
+		{Name: "github.com/foo/search", RepoFields: &types.RepoFields{}},
+		{Name: "github.com/foo/100%_done", RepoFields: &types.RepoFields{}},
+		{Name: "github.com/foo/1000done", RepoFields: &types.RepoFields{}},
+	}
+	for _, repo := range createdRepos {
+		createRepo(ctx, t, repo)
+	}
+	tests := []struct {
+		query string
+		want  []api.RepoName
+	}{
+		// Repositories are ordered by the similarity of their names, so matches of the
+		// description come last.
+		{"search", []api.RepoName{"github.com/foo/search", "github.com/foo/search-api", "github.com/foo/web"}},
+		{"SEARCH UI", []api.RepoName{"github.com/foo/web"}},
+		{"100%_", []api.RepoName{"github.com/foo/100%_done"}},
+		{"nomatch", nil},
+	}
+	for _, test := range tests {
+		repos, err := Repos.Search(ctx, ReposSearchOptions{Query: test.query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := repoNames(repos); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got repos %q, want %q", test.query, got, test.want)
+		}
+	}
+}
+
+func TestRepos_ListSimilar(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	createdRepos := []*types.Repo{
+		{Name: "github.com/sourcegraph/sourcegraph", RepoFields: &types.RepoFields{}},
+		{Name: "github.com/sourcegraph/src-cli", RepoFields: &types.RepoFields{}},
+		{Name: "github.com/kubernetes/kubernetes", RepoFields: &types.RepoFields{}},
+	}
+	for _, repo := range createdRepos {
+		createRepo(ctx, t, repo)
+	}
+	tests := []struct {
+		name string
+		want []api.RepoName
+	}{
+		{"sourcegraph/sourcegrahp", []api.RepoName{"github.com/sourcegraph/sourcegraph"}},
+		{"Kubernetes/kubernets", []api.RepoName{"github.com/kubernetes/kubernetes"}},
+		{"nomatch", nil},
+	}
+	for _, test := range tests {
+		repos, err := Repos.ListSimilar(ctx, test.name, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := repoNames(repos); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got repos %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
 // Test batch 2 (correct ranking)
 func TestRepos_List_query2(t *testing.T) {
 	if testing.Short() {
@@ -4,18 +4,24 @@ import (
 	"testing"
 
 	"context"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 type MockRepos struct {
-	Get       func(ctx context.Context, repo api.RepoID) (*types.Repo, error)
-	GetByName func(ctx context.Context, repo api.RepoName) (*types.Repo, error)
-	List      func(v0 context.Context, v1 ReposListOptions) ([]*types.Repo, error)
-	Delete    func(ctx context.Context, repo api.RepoID) error
-	Count     func(ctx context.Context, opt ReposListOptions) (int, error)
-	Upsert    func(api.InsertRepoOp) error
+	Get         func(ctx context.Context, repo api.RepoID) (*types.Repo, error)
+	GetByName   func(ctx context.Context, repo api.RepoName) (*types.Repo, error)
+	List        func(v0 context.Context, v1 ReposListOptions) ([]*types.Repo, error)
+	Search      func(ctx context.Context, opt ReposSearchOptions) ([]*types.Repo, error)
+	ListSimilar func(ctx context.Context, name string, limit int) ([]*types.Repo, error)
+	Delete      func(ctx context.Context, repo api.RepoID) error
+	Count       func(ctx context.Context, opt ReposListOptions) (int, error)
+	Upsert      func(api.InsertRepoOp) error
+
+	DeleteCascading  func(ctx context.Context, repo api.RepoID) (*RepoDeletion, error)
+	ListLastCommitAt func(ctx context.Context, ids []api.RepoID) (map[api.RepoID]time.Time, error)
 }
 
 func (s *MockRepos) MockGet(t *testing.T, wantRepo api.RepoID) (called *bool) {
@@ -27,6 +27,10 @@ var MockAuthzFilter func(ctx context.Context, repos []*types.Repo, p authz.Perms
 //
 // The enforcement policy:
 //
+// - If a site admin set a visibility override on the repository, it takes precedence over
+//   everything below: public repositories are accessible to everyone, internal ones to all
+//   authenticated users and private ones only to site admins.
+//
 // - If there are no authz providers and `authzAllowByDefault` is true, then the repository is
 //   accessible to everyone.
 //
@@ -91,7 +95,7 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		otlog.Bool("authzAllowByDefault", authzAllowByDefault),
 		otlog.Int("authzProviders.count", len(authzProviders)),
 	)
-	if authzAllowByDefault && len(authzProviders) == 0 {
+	if authzAllowByDefault && len(authzProviders) == 0 && !hasVisibilityOverrides(repos) {
 		return repos, nil
 	}
 
@@ -103,8 +107,22 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		}
 	}
 
+	verified := roaring.NewBitmap()
 	toverify := make(map[string]*[]*types.Repo, len(authzProviders))
 	for _, r := range repos {
+		// 🚨 SECURITY: Visibility overrides take precedence over the permissions of the code host.
+		if visible, ok := visibleByOverride(r, currentUser); ok {
+			if visible {
+				verified.Add(uint32(r.ID))
+			}
+			continue
+		}
+
+		if authzAllowByDefault && len(authzProviders) == 0 {
+			verified.Add(uint32(r.ID))
+			continue
+		}
+
 		group := toverify[r.ExternalRepo.ServiceID]
 		if group == nil {
 			group = getSlice(&reposPool, len(repos))
@@ -119,7 +137,6 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 
 	// Walk through all authz providers, checking repo permissions against each. If any own a given
 	// repo, we use its permissions for that repo.
-	verified := roaring.NewBitmap()
 	for _, authzProvider := range authzProviders {
 		// determine external account to use
 		var providerAcct *extsvc.ExternalAccount
@@ -197,6 +214,34 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 	return filtered, nil
 }
 
+// hasVisibilityOverrides returns true if any of the given repos has a visibility override.
+func hasVisibilityOverrides(repos []*types.Repo) bool {
+	for _, r := range repos {
+		if r.VisibilityOverride != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleByOverride returns whether the repo is visible to the current user (nil if
+// unauthenticated) according to its visibility override. The second return value is false
+// if the repo has no override, in which case the authz providers decide.
+//
+// Site admins see all repos and are handled before this is called.
+func visibleByOverride(r *types.Repo, currentUser *types.User) (visible, ok bool) {
+	switch r.VisibilityOverride {
+	case types.RepoVisibilityPublic:
+		return true, true
+	case types.RepoVisibilityInternal:
+		return currentUser != nil, true
+	case types.RepoVisibilityPrivate:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 // isInternalActor returns true if the actor represents an internal agent (i.e., non-user-bound
 // request that originates from within Sourcegraph itself).
 //
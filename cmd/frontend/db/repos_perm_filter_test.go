@@ -652,6 +652,91 @@ func Test_authzFilter(t *testing.T) {
 	}
 }
 
+func Test_authzFilter_visibilityOverrides(t *testing.T) {
+	repos := map[api.RepoName]*types.Repo{}
+	for _, r := range makeRepos(
+		"gitlab.mine/u1/r0",
+		"gitlab.mine/u2/r0",
+		"gitlab.mine/public/r0",
+		"gitlab.mine/internal/r0",
+	) {
+		repos[r.Name] = r
+	}
+	repos["gitlab.mine/u1/r0"].VisibilityOverride = types.RepoVisibilityPrivate
+	repos["gitlab.mine/public/r0"].VisibilityOverride = types.RepoVisibilityPublic
+	repos["gitlab.mine/internal/r0"].VisibilityOverride = types.RepoVisibilityInternal
+
+	getRepos := func(names ...api.RepoName) (rs []*types.Repo) {
+		for _, name := range names {
+			rs = append(rs, repos[name])
+		}
+		return rs
+	}
+
+	tests := []authzFilter_Test{
+		{
+			description:         "no authz providers, authzAllowByDefault",
+			authzAllowByDefault: true,
+			calls: []authzFilter_call{
+				{
+					description:      "unauthenticated user can only read public and non-overridden repos",
+					perm:             authz.Read,
+					repos:            getRepos("gitlab.mine/u1/r0", "gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+					expFilteredRepos: getRepos("gitlab.mine/u2/r0", "gitlab.mine/public/r0"),
+				},
+				{
+					description:      "authenticated user can read internal repos",
+					user:             &types.User{ID: 1},
+					perm:             authz.Read,
+					repos:            getRepos("gitlab.mine/u1/r0", "gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+					expFilteredRepos: getRepos("gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+				},
+				{
+					description:      "admin can read private repos",
+					user:             &types.User{ID: 2, SiteAdmin: true},
+					perm:             authz.Read,
+					repos:            getRepos("gitlab.mine/u1/r0", "gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+					expFilteredRepos: getRepos("gitlab.mine/u1/r0", "gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+				},
+			},
+		},
+		{
+			description: "overrides take precedence over authz provider",
+			authzProviders: []authz.Provider{
+				&MockAuthzProvider{
+					serviceID:   "https://gitlab.mine/",
+					serviceType: "gitlab",
+					perms: map[extsvc.ExternalAccount]map[api.RepoName]authz.Perms{
+						*acct(1, "gitlab", "https://gitlab.mine/", "u1"): {
+							"gitlab.mine/u1/r0": authz.Read,
+						},
+						{}: {},
+					},
+				},
+			},
+			calls: []authzFilter_call{
+				{
+					description:      "unauthenticated user can read public repos",
+					perm:             authz.Read,
+					repos:            getRepos("gitlab.mine/u1/r0", "gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+					expFilteredRepos: getRepos("gitlab.mine/public/r0"),
+				},
+				{
+					description:      "u1 can't read its own repo overridden as private",
+					user:             &types.User{ID: 1},
+					userAccounts:     []*extsvc.ExternalAccount{acct(1, "gitlab", "https://gitlab.mine/", "u1")},
+					perm:             authz.Read,
+					repos:            getRepos("gitlab.mine/u1/r0", "gitlab.mine/u2/r0", "gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+					expFilteredRepos: getRepos("gitlab.mine/public/r0", "gitlab.mine/internal/r0"),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		test.run(t)
+	}
+}
+
 func Test_authzFilter_createsNewUsers(t *testing.T) {
 	associateUserAndSaveCount := make(map[int32]map[extsvc.ExternalAccountSpec]int)
 	Mocks.ExternalAccounts.AssociateUserAndSave = func(userID int32, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) error {
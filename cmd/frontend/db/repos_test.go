@@ -4,9 +4,11 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
@@ -103,6 +105,103 @@ func TestRepos_Delete(t *testing.T) {
 	}
 }
 
+func TestRepos_DeleteCascading(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{UID: 1, Internal: true})
+
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var campaignID int64
+	if err := dbconn.Global.QueryRowContext(ctx, "INSERT INTO campaigns(name, author_id, namespace_user_id) VALUES('c', $1, $1) RETURNING id", user.ID).Scan(&campaignID); err != nil {
+		t.Fatal(err)
+	}
+	for _, externalID := range []string{"1", "2"} {
+		var changesetID int64
+		if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO changesets(repo_id, external_id, external_service_type, campaign_ids)
+VALUES($1, $2, 'github', jsonb_build_object($3::bigint, NULL))
+RETURNING id`, rp.ID, externalID, campaignID).Scan(&changesetID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE campaigns SET changeset_ids = changeset_ids || jsonb_build_object($1::bigint, NULL) WHERE id = $2", changesetID, campaignID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := Repos.DeleteCascading(ctx, rp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RepoDeletion{Changesets: 2, Campaigns: 1}); *d != want {
+		t.Errorf("got deletion %+v, want %+v", *d, want)
+	}
+
+	if _, err := Repos.Get(ctx, rp.ID); !errcode.IsNotFound(err) {
+		t.Errorf("expected repo not found, but got error %v", err)
+	}
+	var changesetIDs string
+	if err := dbconn.Global.QueryRowContext(ctx, "SELECT changeset_ids::text FROM campaigns WHERE id = $1", campaignID).Scan(&changesetIDs); err != nil {
+		t.Fatal(err)
+	}
+	if changesetIDs != "{}" {
+		t.Errorf("got campaign changeset IDs %s, want none", changesetIDs)
+	}
+
+	if _, err := Repos.DeleteCascading(ctx, rp.ID); !errcode.IsNotFound(err) {
+		t.Errorf("expected repo not found when deleting again, but got error %v", err)
+	}
+}
+
+func TestRepos_LastCommitAt(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{UID: 1, Internal: true})
+
+	for _, name := range []api.RepoName{"a", "b"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, err := Repos.GetByName(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Repos.GetByName(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastCommitAt := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	if err := Repos.UpdateLastCommitAt(ctx, "a", lastCommitAt); err != nil {
+		t.Fatal(err)
+	}
+
+	dates, err := Repos.ListLastCommitAt(ctx, []api.RepoID{a.ID, b.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 1 || !dates[a.ID].Equal(lastCommitAt) {
+		t.Errorf("got dates %v, want only %v for repo a", dates, lastCommitAt)
+	}
+}
+
 func TestRepos_Count(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
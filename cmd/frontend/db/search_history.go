@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// SearchHistoryEntry describes a search that a user ran, so that it can be
+// listed in their search history and run again with the same settings.
+type SearchHistoryEntry struct {
+	ID          int64
+	UserID      int32
+	Query       string
+	Version     string // the search syntax version, e.g. "V2"
+	PatternType string // the search pattern type, e.g. "literal"
+	ResultCount int32
+	CreatedAt   time.Time
+}
+
+// ErrSearchHistoryEntryNotFound occurs when a database operation expects a
+// specific search history entry to exist but it does not exist.
+var ErrSearchHistoryEntryNotFound = errors.New("search history entry not found")
+
+// SearchHistoryMaxEntriesPerUser is the number of most recent searches kept
+// in the search history of each user. Older searches are deleted when new
+// ones are added.
+const SearchHistoryMaxEntriesPerUser = 100
+
+type searchHistory struct{}
+
+// Add adds a search to the search history of the entry's user. A previous
+// entry for the same search (same query, version and pattern type) is
+// replaced, so that each search only appears once in the history. The ID and
+// CreatedAt fields of the entry are set.
+func (*searchHistory) Add(ctx context.Context, e *SearchHistoryEntry) (err error) {
+	if Mocks.SearchHistory.Add != nil {
+		return Mocks.SearchHistory.Add(e)
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollErr := tx.Rollback()
+			if rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	q := sqlf.Sprintf(
+		"DELETE FROM search_history WHERE user_id=%d AND query=%s AND version=%s AND pattern_type=%s",
+		e.UserID, e.Query, e.Version, e.PatternType,
+	)
+	if _, err = tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		return err
+	}
+
+	q = sqlf.Sprintf(
+		"INSERT INTO search_history(user_id, query, version, pattern_type, result_count) VALUES(%d, %s, %s, %s, %d) RETURNING id, created_at",
+		e.UserID, e.Query, e.Version, e.PatternType, e.ResultCount,
+	)
+	if err = tx.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&e.ID, &e.CreatedAt); err != nil {
+		return err
+	}
+
+	// Enforce the retention limit.
+	q = sqlf.Sprintf(`
+DELETE FROM search_history WHERE user_id=%d AND id NOT IN (
+	SELECT id FROM search_history WHERE user_id=%d ORDER BY created_at DESC, id DESC LIMIT %d
+)`,
+		e.UserID, e.UserID, SearchHistoryMaxEntriesPerUser,
+	)
+	_, err = tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// GetByID returns the search history entry with the given ID. If no such
+// entry exists, ErrSearchHistoryEntryNotFound is returned.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view the
+// search history of the entry's user.
+func (s *searchHistory) GetByID(ctx context.Context, id int64) (*SearchHistoryEntry, error) {
+	if Mocks.SearchHistory.GetByID != nil {
+		return Mocks.SearchHistory.GetByID(id)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("id=%d", id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrSearchHistoryEntryNotFound
+	}
+	return results[0], nil
+}
+
+// SearchHistoryListOptions contains options for listing search history
+// entries.
+type SearchHistoryListOptions struct {
+	UserID int32 // only list the search history of this user
+	*LimitOffset
+}
+
+func (o SearchHistoryListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id=%d", o.UserID))
+	}
+	return conds
+}
+
+// List lists the search history entries that satisfy the options, most recent
+// first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with
+// the specified options.
+func (s *searchHistory) List(ctx context.Context, opt SearchHistoryListOptions) ([]*SearchHistoryEntry, error) {
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (*searchHistory) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*SearchHistoryEntry, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, query, version, pattern_type, result_count, created_at FROM search_history
+WHERE (%s)
+ORDER BY created_at DESC, id DESC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*SearchHistoryEntry
+	for rows.Next() {
+		var e SearchHistoryEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Query, &e.Version, &e.PatternType, &e.ResultCount, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Count counts the search history entries that satisfy the options (ignoring
+// limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to count the
+// entries.
+func (*searchHistory) Count(ctx context.Context, opt SearchHistoryListOptions) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM search_history WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+type MockSearchHistory struct {
+	Add     func(e *SearchHistoryEntry) error
+	GetByID func(id int64) (*SearchHistoryEntry, error)
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestSearchHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := Users.Create(ctx, NewUser{Username: "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(userID int32, query string, resultCount int32) *SearchHistoryEntry {
+		t.Helper()
+		e := &SearchHistoryEntry{UserID: userID, Query: query, Version: "V2", PatternType: "literal", ResultCount: resultCount}
+		if err := SearchHistory.Add(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	queries := func(es []*SearchHistoryEntry) (qs []string) {
+		for _, e := range es {
+			qs = append(qs, e.Query)
+		}
+		return qs
+	}
+
+	add(user.ID, "a", 1)
+	b := add(user.ID, "b", 2)
+	add(other.ID, "c", 3)
+	a := add(user.ID, "a", 4) // replaces the first search for "a"
+
+	entries, err := SearchHistory.List(ctx, SearchHistoryListOptions{UserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := queries(entries), []string{"a", "b"}; !reflect.DeepEqual(have, want) {
+		t.Errorf("got queries %v, want %v", have, want)
+	}
+	if entries[0].ResultCount != 4 {
+		t.Errorf("got result count %d, want 4", entries[0].ResultCount)
+	}
+
+	got, err := SearchHistory.GetByID(ctx, b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Query != b.Query || got.UserID != user.ID {
+		t.Errorf("got entry %+v, want %+v", got, b)
+	}
+	if _, err := SearchHistory.GetByID(ctx, a.ID+100); err != ErrSearchHistoryEntryNotFound {
+		t.Errorf("got err %v, want %v", err, ErrSearchHistoryEntryNotFound)
+	}
+
+	// Only the most recent searches are kept.
+	for i := 0; i < SearchHistoryMaxEntriesPerUser; i++ {
+		add(user.ID, fmt.Sprintf("q%d", i), 0)
+	}
+	count, err := SearchHistory.Count(ctx, SearchHistoryListOptions{UserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != SearchHistoryMaxEntriesPerUser {
+		t.Errorf("got count %d, want %d", count, SearchHistoryMaxEntriesPerUser)
+	}
+	if _, err := SearchHistory.GetByID(ctx, a.ID); err != ErrSearchHistoryEntryNotFound {
+		t.Errorf("expected oldest search to be deleted, got err %v", err)
+	}
+
+	count, err = SearchHistory.Count(ctx, SearchHistoryListOptions{UserID: other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("got count %d for other user, want 1", count)
+	}
+}
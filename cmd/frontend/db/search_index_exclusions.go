@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// ErrSearchIndexExclusionNotFound occurs when a database operation expects a
+// specific search index exclusion to exist but it does not exist.
+var ErrSearchIndexExclusionNotFound = errors.New("search index exclusion not found")
+
+type searchIndexExclusions struct{}
+
+// Create excludes the repository or path pattern of e from indexed search. The
+// ID and CreatedAt fields of e are set.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*searchIndexExclusions) Create(ctx context.Context, e *types.SearchIndexExclusion) error {
+	if Mocks.SearchIndexExclusions.Create != nil {
+		return Mocks.SearchIndexExclusions.Create(e)
+	}
+
+	if (e.RepoID == 0) == (e.PathPattern == "") {
+		return errors.New("exactly one of repository and path pattern must be set")
+	}
+
+	var repoID, pathPattern interface{}
+	if e.RepoID != 0 {
+		repoID = e.RepoID
+	} else {
+		pathPattern = e.PathPattern
+	}
+	q := sqlf.Sprintf(
+		"INSERT INTO search_index_exclusions(repo_id, path_pattern, index_bytes) VALUES(%s, %s, %s) RETURNING id, created_at",
+		repoID, pathPattern, e.IndexBytes,
+	)
+	return dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&e.ID, &e.CreatedAt)
+}
+
+// GetByID returns the search index exclusion with the given ID. If no such
+// exclusion exists, ErrSearchIndexExclusionNotFound is returned.
+func (s *searchIndexExclusions) GetByID(ctx context.Context, id int32) (*types.SearchIndexExclusion, error) {
+	results, err := s.list(ctx, sqlf.Sprintf("id=%d", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrSearchIndexExclusionNotFound
+	}
+	return results[0], nil
+}
+
+// List lists all search index exclusions, oldest first.
+func (s *searchIndexExclusions) List(ctx context.Context) ([]*types.SearchIndexExclusion, error) {
+	if Mocks.SearchIndexExclusions.List != nil {
+		return Mocks.SearchIndexExclusions.List()
+	}
+	return s.list(ctx, sqlf.Sprintf("TRUE"))
+}
+
+// ListPathPatterns lists the path patterns of all search index exclusions.
+func (s *searchIndexExclusions) ListPathPatterns(ctx context.Context) ([]string, error) {
+	exclusions, err := s.list(ctx, sqlf.Sprintf("path_pattern IS NOT NULL"))
+	if err != nil {
+		return nil, err
+	}
+	patterns := make([]string, 0, len(exclusions))
+	for _, e := range exclusions {
+		patterns = append(patterns, e.PathPattern)
+	}
+	return patterns, nil
+}
+
+func (*searchIndexExclusions) list(ctx context.Context, cond *sqlf.Query) ([]*types.SearchIndexExclusion, error) {
+	q := sqlf.Sprintf(`
+SELECT id, repo_id, path_pattern, index_bytes, created_at FROM search_index_exclusions
+WHERE %s
+ORDER BY id ASC`,
+		cond,
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*types.SearchIndexExclusion
+	for rows.Next() {
+		var (
+			e           types.SearchIndexExclusion
+			repoID      sql.NullInt64
+			pathPattern sql.NullString
+		)
+		if err := rows.Scan(&e.ID, &repoID, &pathPattern, &e.IndexBytes, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.RepoID = api.RepoID(repoID.Int64)
+		e.PathPattern = pathPattern.String
+		results = append(results, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Delete deletes the search index exclusion with the given ID, so that its
+// repository or path pattern is indexed again. If no such exclusion exists,
+// ErrSearchIndexExclusionNotFound is returned.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*searchIndexExclusions) Delete(ctx context.Context, id int32) error {
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM search_index_exclusions WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrSearchIndexExclusionNotFound
+	}
+	return nil
+}
+
+type MockSearchIndexExclusions struct {
+	Create func(e *types.SearchIndexExclusion) error
+	List   func() ([]*types.SearchIndexExclusion, error)
+}
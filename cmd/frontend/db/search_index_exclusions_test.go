@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSearchIndexExclusions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	for _, name := range []api.RepoName{"a", "b"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, err := Repos.GetByName(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repoExclusion := &types.SearchIndexExclusion{RepoID: a.ID, IndexBytes: 1000}
+	if err := SearchIndexExclusions.Create(ctx, repoExclusion); err != nil {
+		t.Fatal(err)
+	}
+	pathExclusion := &types.SearchIndexExclusion{PathPattern: "**/*.min.js"}
+	if err := SearchIndexExclusions.Create(ctx, pathExclusion); err != nil {
+		t.Fatal(err)
+	}
+	if err := SearchIndexExclusions.Create(ctx, &types.SearchIndexExclusion{}); err == nil {
+		t.Error("expected error for exclusion without repository and path pattern")
+	}
+
+	exclusions, err := SearchIndexExclusions.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*types.SearchIndexExclusion{repoExclusion, pathExclusion}; !reflect.DeepEqual(exclusions, want) {
+		t.Errorf("got exclusions %+v, want %+v", exclusions, want)
+	}
+
+	patterns, err := SearchIndexExclusions.ListPathPatterns(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"**/*.min.js"}; !reflect.DeepEqual(patterns, want) {
+		t.Errorf("got path patterns %v, want %v", patterns, want)
+	}
+
+	// Excluded repositories are not listed for indexing.
+	enabled := true
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchIndexEnabled: &enabled}})
+	defer conf.Mock(nil)
+	repos, err := Repos.List(ctx, ReposListOptions{Enabled: true, Index: &enabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Name != "b" {
+		t.Errorf("got repos %v, want only b", repos)
+	}
+
+	if err := SearchIndexExclusions.Delete(ctx, repoExclusion.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := SearchIndexExclusions.Delete(ctx, repoExclusion.ID); err != ErrSearchIndexExclusionNotFound {
+		t.Errorf("got error %v, want %v", err, ErrSearchIndexExclusionNotFound)
+	}
+	if _, err := SearchIndexExclusions.GetByID(ctx, repoExclusion.ID); err != ErrSearchIndexExclusionNotFound {
+		t.Errorf("got error %v, want %v", err, ErrSearchIndexExclusionNotFound)
+	}
+}
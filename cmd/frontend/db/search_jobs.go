@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// ErrSearchJobNotFound occurs when a database operation expects a specific
+// search job to exist but it does not exist.
+var ErrSearchJobNotFound = errors.New("search job not found")
+
+// ErrSearchJobNotProcessing occurs when the progress of a search job is
+// recorded, but the job is no longer processed by the caller (because it was
+// canceled, or because another process took over the job).
+var ErrSearchJobNotProcessing = errors.New("search job is not processed by the caller")
+
+type searchJobs struct{}
+
+// Create creates a queued search job. The ID, State, CreatedAt and UpdatedAt
+// fields of job are set.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the job's user.
+func (*searchJobs) Create(ctx context.Context, job *types.SearchJob) error {
+	if Mocks.SearchJobs.Create != nil {
+		return Mocks.SearchJobs.Create(job)
+	}
+
+	job.State = types.SearchJobStateQueued
+	q := sqlf.Sprintf(
+		"INSERT INTO search_jobs(user_id, query, pattern_type, state) VALUES(%s, %s, %s, %s) RETURNING id, created_at, updated_at",
+		job.UserID, job.Query, job.PatternType, job.State,
+	)
+	return dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+}
+
+// GetByID returns the search job with the given ID. If no such job exists,
+// ErrSearchJobNotFound is returned.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the job's user or a site admin.
+func (s *searchJobs) GetByID(ctx context.Context, id int32) (*types.SearchJob, error) {
+	if Mocks.SearchJobs.GetByID != nil {
+		return Mocks.SearchJobs.GetByID(id)
+	}
+
+	jobs, err := s.list(ctx, sqlf.Sprintf("id=%d", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrSearchJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// ListByUser lists the search jobs of the user, newest first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user or a site admin.
+func (s *searchJobs) ListByUser(ctx context.Context, userID int32) ([]*types.SearchJob, error) {
+	return s.list(ctx, sqlf.Sprintf("user_id=%d", userID))
+}
+
+func (*searchJobs) list(ctx context.Context, cond *sqlf.Query) ([]*types.SearchJob, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, query, pattern_type, state, repo_revs, repos_searched, result_count, incomplete_repos, error, created_at, updated_at, finished_at FROM search_jobs
+WHERE %s
+ORDER BY id DESC`,
+		cond,
+	)
+	return scanSearchJobs(dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
+}
+
+func scanSearchJobs(rows *sql.Rows, err error) ([]*types.SearchJob, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*types.SearchJob
+	for rows.Next() {
+		var (
+			job      types.SearchJob
+			repoRevs []byte
+			errMsg   sql.NullString
+		)
+		if err := rows.Scan(&job.ID, &job.UserID, &job.Query, &job.PatternType, &job.State, &repoRevs, &job.ReposSearched, &job.ResultCount, pq.Array(&job.IncompleteRepos), &errMsg, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt); err != nil {
+			return nil, err
+		}
+		job.RepoRevs = repoRevs
+		job.Error = errMsg.String
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Dequeue marks the oldest queued search job as processing and returns it.
+// Jobs whose processing made no progress for longer than staleAfter (e.g.
+// because the process running them was restarted) are resumed, too. If there
+// are no such jobs, it returns nil.
+func (*searchJobs) Dequeue(ctx context.Context, staleAfter time.Duration) (*types.SearchJob, error) {
+	q := sqlf.Sprintf(`
+UPDATE search_jobs SET state=%s, updated_at=now()
+WHERE id = (
+	SELECT id FROM search_jobs
+	WHERE state=%s OR (state=%s AND updated_at < now() - %s * interval '1 second')
+	ORDER BY id ASC
+	FOR UPDATE SKIP LOCKED
+	LIMIT 1
+)
+RETURNING id, user_id, query, pattern_type, state, repo_revs, repos_searched, result_count, incomplete_repos, error, created_at, updated_at, finished_at`,
+		types.SearchJobStateProcessing,
+		types.SearchJobStateQueued, types.SearchJobStateProcessing, int(staleAfter/time.Second),
+	)
+	jobs, err := scanSearchJobs(dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
+// SetRepoRevs records the repository revisions that the processing search job
+// searches (JSON-encoded in repoRevs), and the repositories whose results are
+// incomplete because e.g. their revisions don't exist.
+func (*searchJobs) SetRepoRevs(ctx context.Context, id int32, repoRevs []byte, incompleteRepos []string) error {
+	if incompleteRepos == nil {
+		incompleteRepos = []string{} // the column is NOT NULL
+	}
+	return execProcessingSearchJobUpdate(ctx, dbconn.Global, id,
+		sqlf.Sprintf("repo_revs=%s, incomplete_repos=%s", string(repoRevs), pq.Array(incompleteRepos)),
+		sqlf.Sprintf("TRUE"),
+	)
+}
+
+// AddResults records the progress of the processing search job after it
+// searched the repository revisions from repoOffset to repoOffset+repoCount,
+// which found resultCount results (JSON-encoded in results). The results and
+// the progress are recorded together, so that a job resumed later continues
+// with the next repository revisions.
+//
+// If the job was canceled or is processed by another process,
+// ErrSearchJobNotProcessing is returned.
+func (*searchJobs) AddResults(ctx context.Context, id int32, repoOffset, repoCount, resultCount int, incompleteRepos []string, results string) error {
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		// Recording the progress first fails if another process already
+		// recorded the results of these repository revisions.
+		if err := execProcessingSearchJobUpdate(ctx, tx, id,
+			sqlf.Sprintf("repos_searched=%s, result_count=result_count+%s, incomplete_repos=incomplete_repos || %s::text[]", repoOffset+repoCount, resultCount, pq.Array(incompleteRepos)),
+			sqlf.Sprintf("repos_searched=%s", repoOffset),
+		); err != nil {
+			return err
+		}
+		if results == "" {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO search_job_results(search_job_id, repo_offset, results) VALUES($1, $2, $3)", id, repoOffset, results)
+		return err
+	})
+}
+
+// Finish marks the processing search job as completed or, if errMsg is
+// non-empty, as errored.
+func (*searchJobs) Finish(ctx context.Context, id int32, errMsg string) error {
+	if errMsg != "" {
+		return execProcessingSearchJobUpdate(ctx, dbconn.Global, id, sqlf.Sprintf("state=%s, error=%s, finished_at=now()", types.SearchJobStateErrored, errMsg), sqlf.Sprintf("TRUE"))
+	}
+	return execProcessingSearchJobUpdate(ctx, dbconn.Global, id, sqlf.Sprintf("state=%s, finished_at=now()", types.SearchJobStateCompleted), sqlf.Sprintf("TRUE"))
+}
+
+// execProcessingSearchJobUpdate updates the search job, if it is processing
+// and matches cond. Otherwise, ErrSearchJobNotProcessing is returned.
+func execProcessingSearchJobUpdate(ctx context.Context, dbh interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, id int32, update, cond *sqlf.Query) error {
+	q := sqlf.Sprintf("UPDATE search_jobs SET updated_at=now(), %s WHERE id=%d AND state=%s AND %s", update, id, types.SearchJobStateProcessing, cond)
+	res, err := dbh.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrSearchJobNotProcessing
+	}
+	return nil
+}
+
+// Cancel cancels the search job, if it is not finished.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the job's user or a site admin.
+func (*searchJobs) Cancel(ctx context.Context, id int32) error {
+	q := sqlf.Sprintf(
+		"UPDATE search_jobs SET state=%s, updated_at=now(), finished_at=now() WHERE id=%d AND state IN (%s, %s)",
+		types.SearchJobStateCanceled, id, types.SearchJobStateQueued, types.SearchJobStateProcessing,
+	)
+	if _, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteResults writes the JSON-encoded results that the search job found so
+// far to w, in the order of the repository revisions.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the job's user or a site admin.
+func (*searchJobs) WriteResults(ctx context.Context, id int32, w io.Writer) error {
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT results FROM search_job_results WHERE search_job_id=$1 ORDER BY repo_offset ASC", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var results string
+		if err := rows.Scan(&results); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, results); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+type MockSearchJobs struct {
+	Create  func(job *types.SearchJob) error
+	GetByID func(id int32) (*types.SearchJob, error)
+}
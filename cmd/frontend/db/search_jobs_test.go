@@ -0,0 +1,105 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestSearchJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := &types.SearchJob{UserID: user.ID, Query: "foo count:all", PatternType: "literal"}
+	if err := SearchJobs.Create(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if job.State != types.SearchJobStateQueued {
+		t.Errorf("got state %q, want %q", job.State, types.SearchJobStateQueued)
+	}
+
+	dequeued, err := SearchJobs.Dequeue(ctx, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dequeued == nil || dequeued.ID != job.ID || dequeued.State != types.SearchJobStateProcessing {
+		t.Fatalf("got dequeued job %+v, want processing job %d", dequeued, job.ID)
+	}
+
+	// A processing job is not dequeued again until it is stale.
+	if other, err := SearchJobs.Dequeue(ctx, time.Hour); err != nil {
+		t.Fatal(err)
+	} else if other != nil {
+		t.Errorf("got dequeued job %+v, want none", other)
+	}
+
+	if err := SearchJobs.SetRepoRevs(ctx, job.ID, []byte(`[{"RepoID":1}]`), []string{"r0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SearchJobs.AddResults(ctx, job.ID, 0, 2, 1, []string{"r1"}, "a\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SearchJobs.AddResults(ctx, job.ID, 2, 2, 2, nil, "b\nc\n"); err != nil {
+		t.Fatal(err)
+	}
+	// Results for repository revisions that were already searched are
+	// rejected.
+	if err := SearchJobs.AddResults(ctx, job.ID, 2, 2, 2, nil, "b\nc\n"); err != ErrSearchJobNotProcessing {
+		t.Errorf("got error %v, want %v", err, ErrSearchJobNotProcessing)
+	}
+	if err := SearchJobs.Finish(ctx, job.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := SearchJobs.GetByID(ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != types.SearchJobStateCompleted || got.ReposSearched != 4 || got.ResultCount != 3 || !reflect.DeepEqual(got.IncompleteRepos, []string{"r0", "r1"}) || got.FinishedAt == nil {
+		t.Errorf("got job %+v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := SearchJobs.WriteResults(ctx, job.ID, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nb\nc\n"; buf.String() != want {
+		t.Errorf("got results %q, want %q", buf.String(), want)
+	}
+
+	// Canceled jobs can't record progress.
+	canceled := &types.SearchJob{UserID: user.ID, Query: "bar count:all", PatternType: "literal"}
+	if err := SearchJobs.Create(ctx, canceled); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SearchJobs.Dequeue(ctx, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := SearchJobs.Cancel(ctx, canceled.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := SearchJobs.AddResults(ctx, canceled.ID, 0, 1, 0, nil, ""); err != ErrSearchJobNotProcessing {
+		t.Errorf("got error %v, want %v", err, ErrSearchJobNotProcessing)
+	}
+
+	jobs, err := SearchJobs.ListByUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != canceled.ID || jobs[0].State != types.SearchJobStateCanceled {
+		t.Errorf("got jobs %+v", jobs)
+	}
+}
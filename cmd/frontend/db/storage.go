@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// StorageTables are the database tables whose row counts are accounted for,
+// because they grow with the number of repositories synced from code hosts.
+var StorageTables = []string{"repo", "changesets"}
+
+// TableRowCount is the number of rows of a database table.
+type TableRowCount struct {
+	Table    string
+	RowCount int64
+}
+
+type storage struct{}
+
+// RowCounts returns the number of rows of each of the StorageTables, in
+// order. The rows are counted exactly, so this is slow on large tables and
+// must not be called in request handlers.
+func (*storage) RowCounts(ctx context.Context) ([]TableRowCount, error) {
+	if Mocks.Storage.RowCounts != nil {
+		return Mocks.Storage.RowCounts()
+	}
+
+	counts := make([]TableRowCount, 0, len(StorageTables))
+	for _, table := range StorageTables {
+		c := TableRowCount{Table: table}
+		q := fmt.Sprintf("SELECT COUNT(*) FROM %s", pq.QuoteIdentifier(table))
+		if err := dbconn.Global.QueryRowContext(ctx, q).Scan(&c.RowCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
+
+type MockStorage struct {
+	RowCounts func() ([]TableRowCount, error)
+}
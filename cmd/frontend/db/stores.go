@@ -23,4 +23,16 @@ var (
 	ExternalAccounts = &userExternalAccounts{}
 
 	OrgInvitations = &orgInvitations{}
+
+	SearchHistory = &searchHistory{}
+
+	SearchIndexExclusions = &searchIndexExclusions{}
+
+	SearchJobs = &searchJobs{}
+
+	RepoKVPs = &repoKVPs{}
+
+	RepoIntroductions = &repoIntroductions{}
+
+	Storage = &storage{}
 )
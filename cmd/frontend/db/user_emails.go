@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/globalstatedb"
 )
@@ -67,6 +68,39 @@ func (*userEmails) GetPrimaryEmail(ctx context.Context, id int32) (email string,
 	return email, verified, nil
 }
 
+// GetPrimaryEmails gets the primary email (as returned by GetPrimaryEmail) of
+// each of the given users. Users without an email are left out.
+func (*userEmails) GetPrimaryEmails(ctx context.Context, ids ...int32) (map[int32]string, error) {
+	if Mocks.UserEmails.GetPrimaryEmails != nil {
+		return Mocks.UserEmails.GetPrimaryEmails(ctx, ids...)
+	}
+
+	emails := make(map[int32]string, len(ids))
+	if len(ids) == 0 {
+		return emails, nil
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT DISTINCT ON (user_id) user_id, email FROM user_emails WHERE user_id=ANY($1) ORDER BY user_id, (verified_at IS NOT NULL) DESC, created_at ASC, email ASC",
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    int32
+			email string
+		)
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		emails[id] = email
+	}
+	return emails, rows.Err()
+}
+
 // Get gets information about the user's associated email address.
 func (*userEmails) Get(ctx context.Context, userID int32, email string) (emailCanonicalCase string, verified bool, err error) {
 	if Mocks.UserEmails.Get != nil {
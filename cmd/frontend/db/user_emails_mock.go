@@ -3,7 +3,8 @@ package db
 import "context"
 
 type MockUserEmails struct {
-	GetPrimaryEmail func(ctx context.Context, id int32) (email string, verified bool, err error)
-	Get             func(userID int32, email string) (emailCanonicalCase string, verified bool, err error)
-	ListByUser      func(id int32) ([]*UserEmail, error)
+	GetPrimaryEmail  func(ctx context.Context, id int32) (email string, verified bool, err error)
+	GetPrimaryEmails func(ctx context.Context, ids ...int32) (map[int32]string, error)
+	Get              func(userID int32, email string) (emailCanonicalCase string, verified bool, err error)
+	ListByUser       func(id int32) ([]*UserEmail, error)
 }
@@ -88,6 +88,14 @@ func TestUserEmails_GetPrimary(t *testing.T) {
 		if verified != wantVerified {
 			t.Errorf("got verified %v, want %v", verified, wantVerified)
 		}
+
+		emails, err := UserEmails.GetPrimaryEmails(ctx, user.ID, user.ID+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[int32]string{user.ID: wantEmail}; !reflect.DeepEqual(emails, want) {
+			t.Errorf("got primary emails %v, want %v", emails, want)
+		}
 	}
 
 	checkPrimaryEmail(t, "a@example.com", false)
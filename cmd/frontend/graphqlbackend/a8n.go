@@ -32,13 +32,35 @@ type UpdateCampaignArgs struct {
 		ID          graphql.ID
 		Name        *string
 		Description *string
+
+		Labels *[]string
+
+		WebhookURL    *string
+		WebhookSecret *string
+
+		UpdatedAt *DateTime
 	}
 }
 
+type ListCampaignArgs struct {
+	graphqlutil.ConnectionArgs
+	Labels *[]string
+}
+
+type ListViewerCampaignsArgs struct {
+	graphqlutil.ConnectionArgs
+	Role string
+}
+
 type DeleteCampaignArgs struct {
 	Campaign graphql.ID
 }
 
+type CloseCampaignArgs struct {
+	Campaign        graphql.ID
+	CloseChangesets bool
+}
+
 type CreateChangesetsArgs struct {
 	Input []struct {
 		Repository graphql.ID
@@ -46,16 +68,41 @@ type CreateChangesetsArgs struct {
 	}
 }
 
+type ChangesetCountsByLabelArgs struct {
+	Labels *[]string
+}
+
+type RetryChangesetArgs struct {
+	Changeset graphql.ID
+}
+
+type CommentOnChangesetsArgs struct {
+	Changesets []graphql.ID
+	Body       string
+}
+
+type ReviewChangesetsArgs struct {
+	Changesets []graphql.ID
+	Event      string
+	Body       *string
+}
+
 type A8NResolver interface {
 	CreateCampaign(ctx context.Context, args *CreateCampaignArgs) (CampaignResolver, error)
 	UpdateCampaign(ctx context.Context, args *UpdateCampaignArgs) (CampaignResolver, error)
 	CampaignByID(ctx context.Context, id graphql.ID) (CampaignResolver, error)
-	Campaigns(ctx context.Context, args *graphqlutil.ConnectionArgs) (CampaignsConnectionResolver, error)
+	Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error)
+	ViewerCampaigns(ctx context.Context, args *ListViewerCampaignsArgs) (CampaignsConnectionResolver, error)
 	DeleteCampaign(ctx context.Context, args *DeleteCampaignArgs) (*EmptyResponse, error)
+	CloseCampaign(ctx context.Context, args *CloseCampaignArgs) (CloseCampaignResultResolver, error)
+	ChangesetCountsByLabel(ctx context.Context, args *ChangesetCountsByLabelArgs) ([]LabelChangesetCountsResolver, error)
 
 	CreateChangesets(ctx context.Context, args *CreateChangesetsArgs) ([]ChangesetResolver, error)
 	ChangesetByID(ctx context.Context, id graphql.ID) (ChangesetResolver, error)
 	Changesets(ctx context.Context, args *graphqlutil.ConnectionArgs) (ChangesetsConnectionResolver, error)
+	RetryChangeset(ctx context.Context, args *RetryChangesetArgs) (ChangesetResolver, error)
+	CommentOnChangesets(ctx context.Context, args *CommentOnChangesetsArgs) ([]ChangesetResolver, error)
+	ReviewChangesets(ctx context.Context, args *ReviewChangesetsArgs) ([]ChangesetResolver, error)
 
 	AddChangesetsToCampaign(ctx context.Context, args *AddChangesetsToCampaignArgs) (CampaignResolver, error)
 }
@@ -90,13 +137,34 @@ func (r *schemaResolver) DeleteCampaign(ctx context.Context, args *DeleteCampaig
 	return r.a8nResolver.DeleteCampaign(ctx, args)
 }
 
-func (r *schemaResolver) Campaigns(ctx context.Context, args *graphqlutil.ConnectionArgs) (CampaignsConnectionResolver, error) {
+func (r *schemaResolver) CloseCampaign(ctx context.Context, args *CloseCampaignArgs) (CloseCampaignResultResolver, error) {
+	if r.a8nResolver == nil {
+		return nil, onlyInEnterprise
+	}
+	return r.a8nResolver.CloseCampaign(ctx, args)
+}
+
+func (r *schemaResolver) Campaigns(ctx context.Context, args *ListCampaignArgs) (CampaignsConnectionResolver, error) {
 	if r.a8nResolver == nil {
 		return nil, onlyInEnterprise
 	}
 	return r.a8nResolver.Campaigns(ctx, args)
 }
 
+func (r *schemaResolver) ViewerCampaigns(ctx context.Context, args *ListViewerCampaignsArgs) (CampaignsConnectionResolver, error) {
+	if r.a8nResolver == nil {
+		return nil, onlyInEnterprise
+	}
+	return r.a8nResolver.ViewerCampaigns(ctx, args)
+}
+
+func (r *schemaResolver) ChangesetCountsByLabel(ctx context.Context, args *ChangesetCountsByLabelArgs) ([]LabelChangesetCountsResolver, error) {
+	if r.a8nResolver == nil {
+		return nil, onlyInEnterprise
+	}
+	return r.a8nResolver.ChangesetCountsByLabel(ctx, args)
+}
+
 func (r *schemaResolver) CreateChangesets(ctx context.Context, args *CreateChangesetsArgs) ([]ChangesetResolver, error) {
 	if r.a8nResolver == nil {
 		return nil, onlyInEnterprise
@@ -111,6 +179,27 @@ func (r *schemaResolver) Changesets(ctx context.Context, args *graphqlutil.Conne
 	return r.a8nResolver.Changesets(ctx, args)
 }
 
+func (r *schemaResolver) RetryChangeset(ctx context.Context, args *RetryChangesetArgs) (ChangesetResolver, error) {
+	if r.a8nResolver == nil {
+		return nil, onlyInEnterprise
+	}
+	return r.a8nResolver.RetryChangeset(ctx, args)
+}
+
+func (r *schemaResolver) CommentOnChangesets(ctx context.Context, args *CommentOnChangesetsArgs) ([]ChangesetResolver, error) {
+	if r.a8nResolver == nil {
+		return nil, onlyInEnterprise
+	}
+	return r.a8nResolver.CommentOnChangesets(ctx, args)
+}
+
+func (r *schemaResolver) ReviewChangesets(ctx context.Context, args *ReviewChangesetsArgs) ([]ChangesetResolver, error) {
+	if r.a8nResolver == nil {
+		return nil, onlyInEnterprise
+	}
+	return r.a8nResolver.ReviewChangesets(ctx, args)
+}
+
 type ChangesetCountsArgs struct {
 	From *DateTime
 	To   *DateTime
@@ -125,10 +214,34 @@ type CampaignResolver interface {
 	Namespace(ctx context.Context) (n NamespaceResolver, err error)
 	CreatedAt() DateTime
 	UpdatedAt() DateTime
+	ClosedAt() *DateTime
+	Labels() []string
+	WebhookURL() *string
+	ChangesetsCSV(ctx context.Context) (string, error)
 	Changesets(ctx context.Context, args struct{ graphqlutil.ConnectionArgs }) ChangesetsConnectionResolver
+	PatchGroups(ctx context.Context) ([]PatchGroupResolver, error)
 	ChangesetCountsOverTime(ctx context.Context, args *ChangesetCountsArgs) ([]ChangesetCountsResolver, error)
 }
 
+type PatchGroupResolver interface {
+	Hash() string
+	Representative() ChangesetResolver
+	Changesets() []ChangesetResolver
+	Count() int32
+}
+
+type CloseCampaignResultResolver interface {
+	Campaign() CampaignResolver
+	Changesets() []ClosedChangesetResolver
+}
+
+type ClosedChangesetResolver interface {
+	Changeset() ChangesetResolver
+	Closed() bool
+	Detached() bool
+	Error() *string
+}
+
 type CampaignsConnectionResolver interface {
 	Nodes(ctx context.Context) ([]CampaignResolver, error)
 	TotalCount(ctx context.Context) (int32, error)
@@ -153,6 +266,9 @@ type ChangesetResolver interface {
 	Repository(ctx context.Context) (*RepositoryResolver, error)
 	Campaigns(ctx context.Context, args *struct{ graphqlutil.ConnectionArgs }) (CampaignsConnectionResolver, error)
 	Events(ctx context.Context, args *struct{ graphqlutil.ConnectionArgs }) (ChangesetEventsConnectionResolver, error)
+	SyncFailures() int32
+	SyncError() *string
+	NextSyncRetryAt() *DateTime
 }
 
 type ChangesetEventsConnectionResolver interface {
@@ -177,3 +293,8 @@ type ChangesetCountsResolver interface {
 	OpenChangesRequested() int32
 	OpenPending() int32
 }
+
+type LabelChangesetCountsResolver interface {
+	Label() string
+	Counts() ChangesetCountsResolver
+}
@@ -0,0 +1,30 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/apiquota"
+)
+
+func (r *schemaResolver) ViewerApiQuota(ctx context.Context) *apiQuotaResolver {
+	status := apiquota.FromContext(ctx)
+	if status == nil {
+		return nil
+	}
+	return &apiQuotaResolver{status: status}
+}
+
+type apiQuotaResolver struct {
+	status *apiquota.Status
+}
+
+func (r *apiQuotaResolver) Limit() int32 { return int32(r.status.Limit) }
+
+func (r *apiQuotaResolver) Remaining() int32 {
+	if r.status.Remaining < 0 {
+		return 0
+	}
+	return int32(r.status.Remaining)
+}
+
+func (r *apiQuotaResolver) ResetAt() DateTime { return DateTime{Time: r.status.Reset} }
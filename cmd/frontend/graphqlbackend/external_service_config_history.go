@@ -0,0 +1,235 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+)
+
+func (*schemaResolver) RollbackExternalService(ctx context.Context, args *struct {
+	ExternalService graphql.ID
+	ToRevision      int32
+}) (*externalServiceResolver, error) {
+	// 🚨 SECURITY: Only site admins may roll back external services.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if os.Getenv("EXTSVC_CONFIG_FILE") != "" && !extsvcConfigAllowEdits {
+		return nil, errors.New("rolling back external service not allowed when using EXTSVC_CONFIG_FILE")
+	}
+
+	id, err := unmarshalExternalServiceID(args.ExternalService)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := db.ExternalServices.GetConfigRevision(ctx, id, args.ToRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateExternalService(ctx, id, &db.ExternalServiceUpdate{Config: &revision.Config})
+}
+
+func (r *externalServiceResolver) ConfigHistory(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*externalServiceConfigRevisionConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may read the configuration history (it has secrets).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := db.ExternalServiceConfigRevisionsListOptions{ExternalServiceID: r.externalService.ID}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &externalServiceConfigRevisionConnectionResolver{opt: opt}, nil
+}
+
+// externalServiceConfigRevisionConnectionResolver resolves a list of
+// configuration revisions of an external service.
+//
+// 🚨 SECURITY: When instantiating an externalServiceConfigRevisionConnectionResolver
+// value, the caller MUST check that the actor is a site admin.
+type externalServiceConfigRevisionConnectionResolver struct {
+	opt db.ExternalServiceConfigRevisionsListOptions
+
+	// cache results because they are used by multiple fields
+	once      sync.Once
+	revisions []*types.ExternalServiceConfigRevision
+	err       error
+}
+
+func (r *externalServiceConfigRevisionConnectionResolver) compute(ctx context.Context) ([]*types.ExternalServiceConfigRevision, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.revisions, r.err = db.ExternalServices.ListConfigRevisions(ctx, opt2)
+	})
+	return r.revisions, r.err
+}
+
+func (r *externalServiceConfigRevisionConnectionResolver) Nodes(ctx context.Context) ([]*externalServiceConfigRevisionResolver, error) {
+	revisions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(revisions) > r.opt.LimitOffset.Limit {
+		revisions = revisions[:r.opt.LimitOffset.Limit]
+	}
+
+	resolvers := make([]*externalServiceConfigRevisionResolver, len(revisions))
+	for i, revision := range revisions {
+		resolvers[i] = &externalServiceConfigRevisionResolver{revision: revision}
+	}
+	return resolvers, nil
+}
+
+func (r *externalServiceConfigRevisionConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.ExternalServices.CountConfigRevisions(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *externalServiceConfigRevisionConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	revisions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(revisions) > r.opt.Limit), nil
+}
+
+type externalServiceConfigRevisionResolver struct {
+	revision *types.ExternalServiceConfigRevision
+}
+
+func (r *externalServiceConfigRevisionResolver) Revision() int32 { return r.revision.Revision }
+
+func (r *externalServiceConfigRevisionResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.revision.CreatedAt}
+}
+
+func (r *externalServiceConfigRevisionResolver) Author(ctx context.Context) (*UserResolver, error) {
+	if r.revision.AuthorUserID == nil {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, *r.revision.AuthorUserID)
+}
+
+func (r *externalServiceConfigRevisionResolver) Diff(ctx context.Context) (string, error) {
+	var previousConfig string
+	if r.revision.Revision > 1 {
+		previous, err := db.ExternalServices.GetConfigRevision(ctx, r.revision.ExternalServiceID, r.revision.Revision-1)
+		if err != nil {
+			return "", err
+		}
+		previousConfig = previous.Config
+	}
+	return diffExternalServiceConfigs(previousConfig, r.revision.Config)
+}
+
+// diffExternalServiceConfigs returns a line diff of the two external service
+// configurations, with their secrets redacted. An empty config is treated as
+// no configuration.
+func diffExternalServiceConfigs(a, b string) (string, error) {
+	var err error
+	if a != "" {
+		if a, err = redactExternalServiceConfig(a); err != nil {
+			return "", err
+		}
+	}
+	if b, err = redactExternalServiceConfig(b); err != nil {
+		return "", err
+	}
+
+	dmp := diffmatchpatch.New()
+	aChars, bChars, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(aChars, bChars, false), lines)
+
+	var buf strings.Builder
+	for _, d := range diffs {
+		prefix := " "
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		}
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(d.Text, "\n"), "\n") {
+			buf.WriteString(prefix)
+			buf.WriteString(strings.TrimSuffix(line, "\n"))
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String(), nil
+}
+
+// secretConfigFields are the fields of external service configurations whose
+// values are secrets.
+var secretConfigFields = map[string]bool{
+	"appPassword":     true,
+	"password":        true,
+	"privateKey":      true,
+	"secret":          true,
+	"secretAccessKey": true,
+	"signingKey":      true,
+	"token":           true,
+	"webhookSecret":   true,
+}
+
+// redactExternalServiceConfig returns the external service configuration as
+// normalized, indented JSON, with the values of secret fields and the
+// passwords in URLs replaced by "REDACTED".
+func redactExternalServiceConfig(config string) (string, error) {
+	var v interface{}
+	if err := jsonc.Unmarshal(config, &v); err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(redactConfigValue(v)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func redactConfigValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && s != "" && secretConfigFields[key] {
+				v[key] = "REDACTED"
+			} else {
+				v[key] = redactConfigValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactConfigValue(value)
+		}
+	case string:
+		if u, err := url.Parse(v); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), "REDACTED")
+				return u.String()
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,343 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/randstring"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"golang.org/x/oauth2"
+)
+
+// externalServiceOAuthSetups stores the external service OAuth setups in progress by ID. They
+// contain the client secret and the access token, so they expire soon.
+var externalServiceOAuthSetups = rcache.NewWithTTL("extsvc_oauth_setup", int(time.Hour/time.Second))
+
+// externalServiceOAuthSetupRedirectPath is the path of the web app page that the code host
+// redirects back to with the code and state of the authorization.
+const externalServiceOAuthSetupRedirectPath = "/site-admin/external-services/oauth-callback"
+
+// externalServiceOAuthSetup is a connection of a GitHub or GitLab code host with OAuth. A site admin
+// starts it with the client ID and secret of an OAuth application on the code host, authorizes
+// Sourcegraph on the code host, and then adds an external service with the access token that the
+// authorization code is exchanged for.
+type externalServiceOAuthSetup struct {
+	ID           string // also the state of the OAuth authorization
+	UserID       int32  // the site admin who started the setup
+	Kind         string
+	URL          string
+	ClientID     string
+	ClientSecret string
+	Token        string // empty until the setup is completed
+}
+
+func (r *schemaResolver) StartExternalServiceOAuthSetup(ctx context.Context, args *struct {
+	Input *struct {
+		Kind         string
+		URL          string
+		ClientID     string
+		ClientSecret string
+	}
+}) (*externalServiceOAuthSetupResolver, error) {
+	// 🚨 SECURITY: Only site admins may add external services.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if os.Getenv("EXTSVC_CONFIG_FILE") != "" && !extsvcConfigAllowEdits {
+		return nil, errors.New("adding external service not allowed when using EXTSVC_CONFIG_FILE")
+	}
+
+	setup := &externalServiceOAuthSetup{
+		ID:           randstring.NewLen(32),
+		UserID:       actor.FromContext(ctx).UID,
+		Kind:         args.Input.Kind,
+		URL:          args.Input.URL,
+		ClientID:     args.Input.ClientID,
+		ClientSecret: args.Input.ClientSecret,
+	}
+	if setup.ClientID == "" || setup.ClientSecret == "" {
+		return nil, errors.New("the client ID and secret of the OAuth application are required")
+	}
+	// Fail early for unsupported kinds and invalid URLs.
+	if _, err := setup.oauth2Config(); err != nil {
+		return nil, err
+	}
+	if err := setup.save(); err != nil {
+		return nil, err
+	}
+	return &externalServiceOAuthSetupResolver{setup: setup}, nil
+}
+
+func (r *schemaResolver) CompleteExternalServiceOAuthSetup(ctx context.Context, args *struct {
+	State string
+	Code  string
+}) (*externalServiceOAuthSetupResolver, error) {
+	setup, err := getExternalServiceOAuthSetup(ctx, args.State)
+	if err != nil {
+		return nil, err
+	}
+	if setup.Token != "" {
+		return nil, errors.New("external service OAuth setup is already completed")
+	}
+
+	cfg, err := setup.oauth2Config()
+	if err != nil {
+		return nil, err
+	}
+	token, err := cfg.Exchange(ctx, args.Code)
+	if err != nil {
+		return nil, errors.Wrap(err, "exchanging the OAuth code for an access token")
+	}
+	setup.Token = token.AccessToken
+	if err := setup.save(); err != nil {
+		return nil, err
+	}
+	return &externalServiceOAuthSetupResolver{setup: setup}, nil
+}
+
+func (r *schemaResolver) AddExternalServiceFromOAuthSetup(ctx context.Context, args *struct {
+	Input *struct {
+		Setup       string
+		DisplayName string
+		Namespaces  []string
+	}
+}) (*externalServiceResolver, error) {
+	setup, err := getExternalServiceOAuthSetup(ctx, args.Input.Setup)
+	if err != nil {
+		return nil, err
+	}
+	if setup.Token == "" {
+		return nil, errors.New("external service OAuth setup is not completed")
+	}
+	if os.Getenv("EXTSVC_CONFIG_FILE") != "" && !extsvcConfigAllowEdits {
+		return nil, errors.New("adding external service not allowed when using EXTSVC_CONFIG_FILE")
+	}
+
+	config, err := setup.externalServiceConfig(args.Input.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	res, err := addExternalService(ctx, &types.ExternalService{
+		Kind:        setup.Kind,
+		DisplayName: args.Input.DisplayName,
+		Config:      config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	externalServiceOAuthSetups.Delete(setup.ID)
+	return res, nil
+}
+
+// getExternalServiceOAuthSetup returns the setup with the ID, if the current user started it.
+func getExternalServiceOAuthSetup(ctx context.Context, id string) (*externalServiceOAuthSetup, error) {
+	// 🚨 SECURITY: Only site admins may add external services.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	notFound := errors.New("external service OAuth setup not found (it may have expired)")
+	data, ok := externalServiceOAuthSetups.Get(id)
+	if !ok {
+		return nil, notFound
+	}
+	var setup externalServiceOAuthSetup
+	if err := json.Unmarshal(data, &setup); err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the site admin who started the setup may use its access token.
+	if setup.UserID != actor.FromContext(ctx).UID {
+		return nil, notFound
+	}
+	return &setup, nil
+}
+
+func (s *externalServiceOAuthSetup) save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	externalServiceOAuthSetups.Set(s.ID, data)
+	return nil
+}
+
+func (s *externalServiceOAuthSetup) baseURL() (*url.URL, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid code host URL %q (must start with http:// or https://)", s.URL)
+	}
+	return extsvc.NormalizeBaseURL(u), nil
+}
+
+func (s *externalServiceOAuthSetup) oauth2Config() (*oauth2.Config, error) {
+	baseURL, err := s.baseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var authPath, tokenPath string
+	var scopes []string
+	switch s.Kind {
+	case "GITHUB":
+		// See https://developer.github.com/apps/building-oauth-apps/authorizing-oauth-apps/.
+		authPath, tokenPath = "/login/oauth/authorize", "/login/oauth/access_token"
+		scopes = []string{"repo", "read:org"}
+	case "GITLAB":
+		// See https://docs.gitlab.com/ee/api/oauth2.html.
+		authPath, tokenPath = "/oauth/authorize", "/oauth/token"
+		scopes = []string{"api"}
+	default:
+		return nil, fmt.Errorf("connecting external services of kind %s with OAuth is not supported", s.Kind)
+	}
+
+	return &oauth2.Config{
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		Scopes:       scopes,
+		RedirectURL:  globals.ExternalURL().ResolveReference(&url.URL{Path: externalServiceOAuthSetupRedirectPath}).String(),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  baseURL.ResolveReference(&url.URL{Path: authPath}).String(),
+			TokenURL: baseURL.ResolveReference(&url.URL{Path: tokenPath}).String(),
+		},
+	}, nil
+}
+
+// namespaces returns the GitHub organizations or GitLab groups that the user who authorized
+// Sourcegraph is a member of.
+func (s *externalServiceOAuthSetup) namespaces(ctx context.Context) ([]*externalServiceOAuthNamespaceResolver, error) {
+	if s.Token == "" {
+		return nil, errors.New("external service OAuth setup is not completed")
+	}
+	baseURL, err := s.baseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaces []*externalServiceOAuthNamespaceResolver
+	switch s.Kind {
+	case "GITHUB":
+		apiURL, _ := github.APIRoot(baseURL)
+		orgs, err := github.NewClient(apiURL, "", nil).GetAuthenticatedUserOrgs(ctx, s.Token)
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			namespaces = append(namespaces, &externalServiceOAuthNamespaceResolver{
+				name: org.Login,
+				url:  baseURL.ResolveReference(&url.URL{Path: org.Login}).String(),
+			})
+		}
+
+	case "GITLAB":
+		groups, _, err := gitlab.NewClientProvider(baseURL, nil).GetOAuthClient(s.Token).ListGroups(ctx, "groups?min_access_level=10&per_page=100")
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			namespaces = append(namespaces, &externalServiceOAuthNamespaceResolver{
+				name: group.FullPath,
+				url:  group.WebURL,
+			})
+		}
+	}
+	return namespaces, nil
+}
+
+// externalServiceConfig returns the JSON configuration of an external service that uses the
+// access token of the setup, and mirrors the repositories of the namespaces (or, if there are
+// none, of the user who authorized Sourcegraph).
+func (s *externalServiceOAuthSetup) externalServiceConfig(namespaces []string) (string, error) {
+	var config interface{}
+	switch s.Kind {
+	case "GITHUB":
+		c := struct {
+			URL             string   `json:"url"`
+			Token           string   `json:"token"`
+			Orgs            []string `json:"orgs,omitempty"`
+			RepositoryQuery []string `json:"repositoryQuery,omitempty"`
+		}{URL: s.URL, Token: s.Token, Orgs: namespaces}
+		if len(namespaces) == 0 {
+			c.RepositoryQuery = []string{"affiliated"}
+		}
+		config = c
+
+	case "GITLAB":
+		c := struct {
+			URL          string   `json:"url"`
+			Token        string   `json:"token"`
+			TokenType    string   `json:"tokenType"`
+			ProjectQuery []string `json:"projectQuery"`
+		}{URL: s.URL, Token: s.Token, TokenType: "oauth"}
+		for _, group := range namespaces {
+			c.ProjectQuery = append(c.ProjectQuery, "groups/"+url.PathEscape(group)+"/projects?include_subgroups=true&archived=no")
+		}
+		if len(namespaces) == 0 {
+			c.ProjectQuery = []string{"projects?membership=true&archived=no"}
+		}
+		config = c
+
+	default:
+		return "", fmt.Errorf("connecting external services of kind %s with OAuth is not supported", s.Kind)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+type externalServiceOAuthSetupResolver struct {
+	setup *externalServiceOAuthSetup
+}
+
+func (r *externalServiceOAuthSetupResolver) ID() string { return r.setup.ID }
+
+func (r *externalServiceOAuthSetupResolver) Kind() string { return r.setup.Kind }
+
+func (r *externalServiceOAuthSetupResolver) URL() string { return r.setup.URL }
+
+func (r *externalServiceOAuthSetupResolver) AuthorizationURL() (string, error) {
+	cfg, err := r.setup.oauth2Config()
+	if err != nil {
+		return "", err
+	}
+	return cfg.AuthCodeURL(r.setup.ID), nil
+}
+
+func (r *externalServiceOAuthSetupResolver) RedirectURL() (string, error) {
+	cfg, err := r.setup.oauth2Config()
+	if err != nil {
+		return "", err
+	}
+	return cfg.RedirectURL, nil
+}
+
+func (r *externalServiceOAuthSetupResolver) Authorized() bool { return r.setup.Token != "" }
+
+func (r *externalServiceOAuthSetupResolver) Namespaces(ctx context.Context) ([]*externalServiceOAuthNamespaceResolver, error) {
+	return r.setup.namespaces(ctx)
+}
+
+type externalServiceOAuthNamespaceResolver struct {
+	name, url string
+}
+
+func (r *externalServiceOAuthNamespaceResolver) Name() string { return r.name }
+
+func (r *externalServiceOAuthNamespaceResolver) URL() string { return r.url }
@@ -0,0 +1,79 @@
+package graphqlbackend
+
+import (
+	"testing"
+)
+
+func TestExternalServiceOAuthSetup_externalServiceConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      externalServiceOAuthSetup
+		namespaces []string
+		want       string
+	}{
+		{
+			name:  "github affiliated",
+			setup: externalServiceOAuthSetup{Kind: "GITHUB", URL: "https://github.com", Token: "t"},
+			want: `{
+  "url": "https://github.com",
+  "token": "t",
+  "repositoryQuery": [
+    "affiliated"
+  ]
+}`,
+		},
+		{
+			name:       "github orgs",
+			setup:      externalServiceOAuthSetup{Kind: "GITHUB", URL: "https://github.com", Token: "t"},
+			namespaces: []string{"a", "b"},
+			want: `{
+  "url": "https://github.com",
+  "token": "t",
+  "orgs": [
+    "a",
+    "b"
+  ]
+}`,
+		},
+		{
+			name:  "gitlab membership",
+			setup: externalServiceOAuthSetup{Kind: "GITLAB", URL: "https://gitlab.example.com", Token: "t"},
+			want: `{
+  "url": "https://gitlab.example.com",
+  "token": "t",
+  "tokenType": "oauth",
+  "projectQuery": [
+    "projects?membership=true&archived=no"
+  ]
+}`,
+		},
+		{
+			name:       "gitlab groups",
+			setup:      externalServiceOAuthSetup{Kind: "GITLAB", URL: "https://gitlab.example.com", Token: "t"},
+			namespaces: []string{"a/b"},
+			want: `{
+  "url": "https://gitlab.example.com",
+  "token": "t",
+  "tokenType": "oauth",
+  "projectQuery": [
+    "groups/a%2Fb/projects?include_subgroups=true&archived=no"
+  ]
+}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.setup.externalServiceConfig(test.namespaces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got config\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+
+	if _, err := (&externalServiceOAuthSetup{Kind: "PHABRICATOR"}).externalServiceConfig(nil); err == nil {
+		t.Error("got nil error for unsupported kind")
+	}
+}
@@ -37,12 +37,16 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 		return nil, errors.New("adding external service not allowed when using EXTSVC_CONFIG_FILE")
 	}
 
-	externalService := &types.ExternalService{
+	return addExternalService(ctx, &types.ExternalService{
 		Kind:        args.Input.Kind,
 		DisplayName: args.Input.DisplayName,
 		Config:      args.Input.Config,
-	}
+	})
+}
 
+// addExternalService creates the external service and triggers its sync. Callers must check
+// that the current user may add it.
+func addExternalService(ctx context.Context, externalService *types.ExternalService) (*externalServiceResolver, error) {
 	if err := db.ExternalServices.Create(ctx, conf.Get, externalService); err != nil {
 		return nil, err
 	}
@@ -79,11 +83,16 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 		return nil, fmt.Errorf("blank external service configuration is invalid (must be valid JSONC)")
 	}
 
-	ps := conf.Get().Critical.AuthProviders
-	update := &db.ExternalServiceUpdate{
+	return updateExternalService(ctx, externalServiceID, &db.ExternalServiceUpdate{
 		DisplayName: args.Input.DisplayName,
 		Config:      args.Input.Config,
-	}
+	})
+}
+
+// updateExternalService updates the external service and triggers its sync. Callers must
+// check that the current user may update it.
+func updateExternalService(ctx context.Context, externalServiceID int64, update *db.ExternalServiceUpdate) (*externalServiceResolver, error) {
+	ps := conf.Get().Critical.AuthProviders
 	if err := db.ExternalServices.Update(ctx, ps, externalServiceID, update); err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"fmt"
 	"html/template"
 	"path"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/markdown"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -133,3 +135,72 @@ func (r *gitTreeEntryResolver) Highlight(ctx context.Context, args *struct {
 	result.html = string(html)
 	return result, nil
 }
+
+// highlightedRangeCache caches the results of HighlightedRange. Its keys
+// include the blob OID, so the cached values never go stale.
+var highlightedRangeCache = rcache.NewWithTTL("highlighted_range", 24*3600)
+
+// maxHighlightedRangeLines is the maximum number of lines HighlightedRange
+// highlights at once.
+const maxHighlightedRangeLines = 1000
+
+func (r *gitTreeEntryResolver) HighlightedRange(ctx context.Context, args *struct {
+	StartLine      int32
+	EndLine        int32
+	DisableTimeout bool
+	IsLightTheme   bool
+}) (*highlightedFileResolver, error) {
+	if args.StartLine < 1 || args.EndLine < args.StartLine {
+		return nil, fmt.Errorf("invalid line range %d-%d", args.StartLine, args.EndLine)
+	}
+	if n := args.EndLine - args.StartLine + 1; n > maxHighlightedRangeLines {
+		return nil, fmt.Errorf("line range of %d lines exceeds the maximum of %d lines", n, maxHighlightedRangeLines)
+	}
+
+	// Blobs are immutable, so their OID identifies their contents. If we don't
+	// know it, the commit and path do too.
+	blob := string(r.commit.oid) + ":" + r.Path()
+	if oi, ok := r.stat.Sys().(git.ObjectInfo); ok {
+		blob = oi.OID().String()
+	}
+	cacheKey := fmt.Sprintf("%s:%d-%d:%t", blob, args.StartLine, args.EndLine, args.IsLightTheme)
+	if html, ok := highlightedRangeCache.Get(cacheKey); ok {
+		return &highlightedFileResolver{html: string(html)}, nil
+	}
+
+	// Timeout for reading file via Git.
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, r.commit.repo.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := git.ReadFileLines(ctx, *cachedRepo, api.CommitID(r.commit.OID()), r.Path(), int(args.StartLine), int(args.EndLine))
+	if err != nil {
+		return nil, err
+	}
+
+	html, aborted, err := highlight.Code(ctx, highlight.Params{
+		Content:        content,
+		Filepath:       r.Path(),
+		DisableTimeout: args.DisableTimeout,
+		IsLightTheme:   args.IsLightTheme,
+		FirstLine:      int(args.StartLine),
+		Metadata: highlight.Metadata{
+			RepoName: string(r.commit.repo.repo.Name),
+			Revision: string(r.commit.oid),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Aborted highlighting falls back to plain text, which we don't want to
+	// keep serving once the highlighter is able to handle the range.
+	if !aborted {
+		highlightedRangeCache.Set(cacheKey, []byte(html))
+	}
+	return &highlightedFileResolver{aborted: aborted, html: string(html)}, nil
+}
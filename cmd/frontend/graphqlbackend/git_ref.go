@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 const (
@@ -83,6 +86,8 @@ type GitRefResolver struct {
 	name string
 
 	target GitObjectID // the target's OID, if known (otherwise computed on demand)
+
+	behindAhead *behindAheadBatch // shared by the refs on a connection page, if any
 }
 
 // gitRefGQLID is a type used for marshaling and unmarshaling a Git ref's
@@ -125,3 +130,66 @@ func (r *GitRefResolver) Target() interface {
 func (r *GitRefResolver) Repository() *RepositoryResolver { return r.repo }
 
 func (r *GitRefResolver) URL() string { return r.repo.URL() + "@" + escapeRevspecForURL(r.AbbrevName()) }
+
+func (r *GitRefResolver) BehindAheadDefaultBranch(ctx context.Context) (*behindAheadCountsResolver, error) {
+	batch := r.behindAhead
+	if batch == nil {
+		batch = &behindAheadBatch{repo: r.repo, refs: []*GitRefResolver{r}}
+	}
+	counts, err := batch.get(ctx, r.name)
+	if err != nil || counts == nil {
+		return nil, err
+	}
+	return &behindAheadCountsResolver{
+		behind: int32(counts.Behind),
+		ahead:  int32(counts.Ahead),
+	}, nil
+}
+
+// behindAheadBatch computes the behind/ahead counts relative to the default
+// branch for a set of refs (typically a page of a GitRefConnection) on first
+// use, so that a branches page doesn't need a separate request for each ref.
+type behindAheadBatch struct {
+	repo *RepositoryResolver
+	refs []*GitRefResolver
+
+	once   sync.Once
+	counts map[string]*git.BehindAhead // by ref name; nil if there is no default branch
+	err    error
+}
+
+func (b *behindAheadBatch) get(ctx context.Context, name string) (*git.BehindAhead, error) {
+	b.once.Do(func() {
+		b.counts, b.err = b.compute(ctx)
+	})
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.counts[name], nil
+}
+
+func (b *behindAheadBatch) compute(ctx context.Context) (map[string]*git.BehindAhead, error) {
+	defaultBranch, err := b.repo.DefaultBranch(ctx)
+	if err != nil || defaultBranch == nil {
+		return nil, err
+	}
+	cachedRepo, err := backend.CachedGitRepo(ctx, b.repo.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(b.refs))
+	for i, ref := range b.refs {
+		names[i] = ref.name
+	}
+	counts, err := git.GetBehindAheadMany(ctx, *cachedRepo, defaultBranch.name, names)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*git.BehindAhead, len(names))
+	for i, name := range names {
+		byName[name] = counts[i]
+	}
+	return byName, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	tracepkg "github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
 var graphqlFieldHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
@@ -39,7 +40,15 @@ func (prometheusTracer) TraceField(ctx context.Context, label, typeName, fieldNa
 	traceCtx, finish := trace.OpenTracingTracer{}.TraceField(ctx, label, typeName, fieldName, trivial, args)
 	start := time.Now()
 	return traceCtx, func(err *gqlerrors.QueryError) {
-		graphqlFieldHistogram.WithLabelValues(typeName, fieldName, strconv.FormatBool(err != nil)).Observe(time.Since(start).Seconds())
+		took := time.Since(start)
+		graphqlFieldHistogram.WithLabelValues(typeName, fieldName, strconv.FormatBool(err != nil)).Observe(took.Seconds())
+
+		// Avoid passing a typed nil *QueryError as a non-nil error.
+		var observedErr error
+		if err != nil {
+			observedErr = err
+		}
+		tracepkg.ObserveExemplar(traceCtx, "graphql "+typeName+"."+fieldName, took, observedErr)
 		finish(err)
 	}
 }
@@ -162,6 +171,11 @@ func (r *NodeResolver) ToSavedSearch() (*savedSearchResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToSearchJob() (*searchJobResolver, bool) {
+	n, ok := r.Node.(*searchJobResolver)
+	return n, ok
+}
+
 func (r *NodeResolver) ToSite() (*siteResolver, bool) {
 	n, ok := r.Node.(*siteResolver)
 	return n, ok
@@ -235,6 +249,8 @@ func (r *schemaResolver) nodeByID(ctx context.Context, id graphql.ID) (Node, err
 		return RegistryExtensionByID(ctx, id)
 	case "SavedSearch":
 		return savedSearchByID(ctx, id)
+	case searchJobIDKind:
+		return searchJobByID(ctx, id)
 	case "Site":
 		return siteByGQLID(ctx, id)
 	default:
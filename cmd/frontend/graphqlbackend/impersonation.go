@@ -0,0 +1,59 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/impersonation"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func (*schemaResolver) ImpersonateUser(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can impersonate users.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	adminID := actor.FromContext(ctx).UID
+	if userID == adminID {
+		return nil, errors.New("refusing to impersonate current user")
+	}
+
+	// Check that the user exists.
+	if _, err := db.Users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	impersonation.Start(adminID, userID)
+	return &EmptyResponse{}, nil
+}
+
+func (*schemaResolver) StopImpersonatingUser(ctx context.Context) (*EmptyResponse, error) {
+	// The actor is the impersonated user, so the site admin is identified by
+	// the impersonation itself.
+	adminID := impersonation.Impersonator(ctx)
+	if adminID == 0 {
+		return nil, errors.New("not impersonating a user")
+	}
+
+	impersonation.Stop(adminID)
+	return &EmptyResponse{}, nil
+}
+
+func (*schemaResolver) Impersonator(ctx context.Context) (*UserResolver, error) {
+	adminID := impersonation.Impersonator(ctx)
+	if adminID == 0 {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, adminID)
+}
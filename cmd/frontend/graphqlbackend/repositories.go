@@ -19,21 +19,24 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 func (r *schemaResolver) Repositories(args *struct {
 	graphqlutil.ConnectionArgs
-	Query           *string
-	Names           *[]string
-	Enabled         bool // deprecated
-	Disabled        bool // deprecated
-	Cloned          bool
-	CloneInProgress bool
-	NotCloned       bool
-	Indexed         bool
-	NotIndexed      bool
-	OrderBy         string
-	Descending      bool
+	Query             *string
+	Names             *[]string
+	Enabled           bool // deprecated
+	Disabled          bool // deprecated
+	Cloned            bool
+	CloneInProgress   bool
+	NotCloned         bool
+	Indexed           bool
+	NotIndexed        bool
+	ExternalService   *graphql.ID
+	LastFetchedBefore *DateTime
+	OrderBy           string
+	Descending        bool
 }) (*repositoryConnectionResolver, error) {
 	// New call sites don't specify Enable and Disable. Assume if disabled
 	// isn't specified we want Enabled since all repos are enabled.
@@ -59,24 +62,36 @@ func (r *schemaResolver) Repositories(args *struct {
 	if args.Query != nil {
 		opt.Query = *args.Query
 	}
+	if args.ExternalService != nil {
+		id, err := unmarshalExternalServiceID(*args.ExternalService)
+		if err != nil {
+			return nil, err
+		}
+		opt.ExternalServiceID = id
+	}
 	args.ConnectionArgs.Set(&opt.LimitOffset)
-	return &repositoryConnectionResolver{
+	conn := &repositoryConnectionResolver{
 		opt:             opt,
 		cloned:          args.Cloned,
 		cloneInProgress: args.CloneInProgress,
 		notCloned:       args.NotCloned,
 		indexed:         args.Indexed,
 		notIndexed:      args.NotIndexed,
-	}, nil
+	}
+	if args.LastFetchedBefore != nil {
+		conn.lastFetchedBefore = &args.LastFetchedBefore.Time
+	}
+	return conn, nil
 }
 
 type repositoryConnectionResolver struct {
-	opt             db.ReposListOptions
-	cloned          bool
-	cloneInProgress bool
-	notCloned       bool
-	indexed         bool
-	notIndexed      bool
+	opt               db.ReposListOptions
+	cloned            bool
+	cloneInProgress   bool
+	notCloned         bool
+	indexed           bool
+	notIndexed        bool
+	lastFetchedBefore *time.Time
 
 	// cache results because they are used by multiple fields
 	once  sync.Once
@@ -142,8 +157,9 @@ func (r *repositoryConnectionResolver) compute(ctx context.Context) ([]*types.Re
 			}
 			reposFromDB := len(repos)
 
-			if !r.cloned || !r.cloneInProgress || !r.notCloned {
-				// Query gitserver to filter by repository clone status.
+			if !r.cloned || !r.cloneInProgress || !r.notCloned || r.lastFetchedBefore != nil {
+				// Query gitserver to filter by repository clone status and
+				// last fetch time.
 				repoNames := make([]api.RepoName, len(repos))
 				for i, repo := range repos {
 					repoNames[i] = repo.Name
@@ -157,6 +173,8 @@ func (r *repositoryConnectionResolver) compute(ctx context.Context) ([]*types.Re
 				for _, repo := range repos {
 					if info := response.Results[repo.Name]; info == nil {
 						continue
+					} else if r.lastFetchedBefore != nil && info.LastFetched != nil && !info.LastFetched.Before(*r.lastFetchedBefore) {
+						continue
 					} else if (r.cloned && info.Cloned && !info.CloneInProgress) || (r.cloneInProgress && info.CloneInProgress) || (r.notCloned && !info.Cloned && !info.CloneInProgress) {
 						keepRepos = append(keepRepos, repo)
 					}
@@ -319,6 +337,31 @@ func (r *schemaResolver) SetRepositoryEnabled(ctx context.Context, args *struct
 	return &EmptyResponse{}, nil
 }
 
+func (r *schemaResolver) SetRepositoryVisibilityOverride(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Visibility *string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can override the visibility of repositories, because it
+	// bypasses the permissions of the code host.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var visibility types.RepoVisibility
+	if args.Visibility != nil {
+		visibility = types.RepoVisibility(strings.ToLower(*args.Visibility))
+	}
+	if err := db.Repos.SetVisibilityOverride(ctx, repo.repo.ID, visibility); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
 func (r *schemaResolver) SetAllRepositoriesEnabled(ctx context.Context, args *struct {
 	Enabled bool
 }) (*EmptyResponse, error) {
@@ -370,6 +413,50 @@ func (r *schemaResolver) DeleteRepository(ctx context.Context, args *struct {
 	return &EmptyResponse{}, nil
 }
 
+func (r *schemaResolver) DeleteRepositories(ctx context.Context, args *struct {
+	Repos []graphql.ID
+}) ([]*repositoryDeletionResolver, error) {
+	// 🚨 SECURITY: Only site admins can delete repositories, because it's a site-wide
+	// and destructive action.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Look up all repositories first, so that no repository is deleted if
+	// any of the IDs is invalid.
+	repos := make([]*types.Repo, 0, len(args.Repos))
+	for _, id := range args.Repos {
+		repo, err := repositoryByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo.repo)
+	}
+
+	deletions := make([]*repositoryDeletionResolver, 0, len(repos))
+	for _, repo := range repos {
+		d, err := db.Repos.DeleteCascading(ctx, repo.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "deleting repository %s", repo.Name)
+		}
+		log15.Info("deleted repository", "repo", repo.Name, "changesets", d.Changesets, "campaigns", d.Campaigns)
+		deletions = append(deletions, &repositoryDeletionResolver{name: repo.Name, deletion: d})
+	}
+	return deletions, nil
+}
+
+// repositoryDeletionResolver implements the GraphQL type RepositoryDeletion.
+type repositoryDeletionResolver struct {
+	name     api.RepoName
+	deletion *db.RepoDeletion
+}
+
+func (r *repositoryDeletionResolver) Name() string { return string(r.name) }
+
+func (r *repositoryDeletionResolver) ChangesetsDeleted() int32 { return int32(r.deletion.Changesets) }
+
+func (r *repositoryDeletionResolver) CampaignsAffected() int32 { return int32(r.deletion.Campaigns) }
+
 func repoNamesToStrings(repoNames []api.RepoName) []string {
 	strings := make([]string, len(repoNames))
 	for i, repoName := range repoNames {
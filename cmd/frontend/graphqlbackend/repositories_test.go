@@ -2,10 +2,15 @@ package graphqlbackend
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/graph-gophers/graphql-go/gqltesting"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 func TestRepositories(t *testing.T) {
@@ -62,3 +67,77 @@ func TestRepositories(t *testing.T) {
 		},
 	})
 }
+
+func TestRepositories_externalService(t *testing.T) {
+	resetMocks()
+	db.Mocks.Repos.List = func(ctx context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		if want := int64(5); opt.ExternalServiceID != want {
+			t.Errorf("got external service ID %d, want %d", opt.ExternalServiceID, want)
+		}
+		return []*types.Repo{{Name: "repo1"}}, nil
+	}
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t, nil),
+			Query: `
+				{
+					repositories(externalService: "RXh0ZXJuYWxTZXJ2aWNlOjU=") {
+						nodes { name }
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repositories": {
+						"nodes": [
+							{ "name": "repo1" }
+						]
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestDeleteRepositories(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id, Name: api.RepoName(fmt.Sprintf("repo%d", id))}, nil
+	}
+	var deleted []api.RepoID
+	db.Mocks.Repos.DeleteCascading = func(ctx context.Context, id api.RepoID) (*db.RepoDeletion, error) {
+		deleted = append(deleted, id)
+		return &db.RepoDeletion{Changesets: int(id) * 2, Campaigns: int(id)}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema:  mustParseGraphQLSchema(t, nil),
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Query: `
+				mutation {
+					deleteRepositories(repos: ["UmVwb3NpdG9yeTox", "UmVwb3NpdG9yeToy"]) {
+						name
+						changesetsDeleted
+						campaignsAffected
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"deleteRepositories": [
+						{"name": "repo1", "changesetsDeleted": 2, "campaignsAffected": 1},
+						{"name": "repo2", "changesetsDeleted": 4, "campaignsAffected": 2}
+					]
+				}
+			`,
+		},
+	})
+
+	if want := []api.RepoID{1, 2}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("got deleted %v, want %v", deleted, want)
+	}
+}
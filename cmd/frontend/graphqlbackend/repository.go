@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -92,6 +93,14 @@ func (r *RepositoryResolver) Description(ctx context.Context) (string, error) {
 	return r.repo.Description, nil
 }
 
+func (r *RepositoryResolver) VisibilityOverride() *string {
+	if r.repo.VisibilityOverride == "" {
+		return nil
+	}
+	v := strings.ToUpper(string(r.repo.VisibilityOverride))
+	return &v
+}
+
 func (r *RepositoryResolver) RedirectURL() *string {
 	return r.redirectURL
 }
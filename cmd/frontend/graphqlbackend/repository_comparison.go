@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -121,6 +122,19 @@ func (r *RepositoryComparisonResolver) FileDiffs(args *struct {
 	}
 }
 
+// IsAncestor returns true if the base commit is an ancestor of the head commit, i.e., if the head
+// can be fast-forwarded to from the base. The empty tree base is an ancestor of every head.
+func (r *RepositoryComparisonResolver) IsAncestor(ctx context.Context) (bool, error) {
+	if r.base == nil {
+		return true, nil
+	}
+	cachedRepo, err := backend.CachedGitRepo(ctx, r.repo.repo)
+	if err != nil {
+		return false, err
+	}
+	return git.IsAncestor(ctx, *cachedRepo, api.CommitID(r.base.OID()), api.CommitID(r.head.OID()))
+}
+
 type fileDiffConnectionResolver struct {
 	cmp   *RepositoryComparisonResolver // {base,head}{,RevSpec} and repo
 	first *int32
@@ -4,8 +4,10 @@ import (
 	"context"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -14,6 +16,7 @@ import (
 
 type refsArgs struct {
 	graphqlutil.ConnectionArgs
+	After       *string
 	Query       *string
 	Type        *string
 	OrderBy     *string
@@ -147,6 +150,7 @@ func (r *RepositoryResolver) GitRefs(ctx context.Context, args *refsArgs) (*gitR
 
 	return &gitRefConnectionResolver{
 		first: args.First,
+		after: args.After,
 		refs:  refs,
 		repo:  r,
 	}, nil
@@ -179,21 +183,51 @@ func hydrateBranchCommits(ctx context.Context, repo gitserver.Repo, interactive
 
 type gitRefConnectionResolver struct {
 	first *int32
+	after *string // the name of the last ref on the previous page
 	refs  []*GitRefResolver
 
 	repo *RepositoryResolver
+
+	once        sync.Once
+	nodes       []*GitRefResolver
+	hasNextPage bool
 }
 
-func (r *gitRefConnectionResolver) Nodes() []*GitRefResolver {
-	var nodes []*GitRefResolver
+// page returns the refs on the requested page and whether there are more refs
+// after it. If the ref named by the cursor no longer exists, the page is empty.
+func (r *gitRefConnectionResolver) page() ([]*GitRefResolver, bool) {
+	r.once.Do(func() {
+		refs := r.refs
+		if r.after != nil {
+			i := 0
+			for i < len(refs) && refs[i].name != *r.after {
+				i++
+			}
+			if i < len(refs) {
+				i++
+			}
+			refs = refs[i:]
+		}
 
-	// Paginate.
-	if r.first != nil && len(r.refs) > int(*r.first) {
-		nodes = r.refs[:int(*r.first)]
-	} else {
-		nodes = r.refs
-	}
+		// Paginate.
+		if r.first != nil && len(refs) > int(*r.first) {
+			r.nodes, r.hasNextPage = refs[:int(*r.first)], true
+		} else {
+			r.nodes = refs
+		}
+
+		// Compute the behind/ahead counts of all refs on the page at once, if
+		// they are requested.
+		batch := &behindAheadBatch{repo: r.repo, refs: r.nodes}
+		for _, ref := range r.nodes {
+			ref.behindAhead = batch
+		}
+	})
+	return r.nodes, r.hasNextPage
+}
 
+func (r *gitRefConnectionResolver) Nodes() []*GitRefResolver {
+	nodes, _ := r.page()
 	return nodes
 }
 
@@ -202,5 +236,9 @@ func (r *gitRefConnectionResolver) TotalCount() int32 {
 }
 
 func (r *gitRefConnectionResolver) PageInfo() *graphqlutil.PageInfo {
-	return graphqlutil.HasNextPage(r.first != nil && int(*r.first) < len(r.refs))
+	nodes, hasNextPage := r.page()
+	if !hasNextPage || len(nodes) == 0 {
+		return graphqlutil.HasNextPage(false)
+	}
+	return graphqlutil.NextPageCursor(graphql.ID(nodes[len(nodes)-1].name))
 }
@@ -0,0 +1,54 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGitRefConnectionResolver_pagination(t *testing.T) {
+	var refs []*GitRefResolver
+	for _, name := range []string{"refs/heads/a", "refs/heads/b", "refs/heads/c"} {
+		refs = append(refs, &GitRefResolver{name: name})
+	}
+
+	strptr := func(s string) *string { return &s }
+	int32ptr := func(i int32) *int32 { return &i }
+
+	tests := []struct {
+		name          string
+		first         *int32
+		after         *string
+		wantNodes     []string
+		wantEndCursor string
+	}{
+		{name: "all", wantNodes: []string{"refs/heads/a", "refs/heads/b", "refs/heads/c"}},
+		{name: "first page", first: int32ptr(2), wantNodes: []string{"refs/heads/a", "refs/heads/b"}, wantEndCursor: "refs/heads/b"},
+		{name: "last page", first: int32ptr(2), after: strptr("refs/heads/b"), wantNodes: []string{"refs/heads/c"}},
+		{name: "unknown cursor", first: int32ptr(2), after: strptr("refs/heads/gone")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &gitRefConnectionResolver{first: test.first, after: test.after, refs: refs}
+
+			var nodes []string
+			for _, ref := range r.Nodes() {
+				nodes = append(nodes, ref.name)
+			}
+			if !reflect.DeepEqual(nodes, test.wantNodes) {
+				t.Errorf("got nodes %v, want %v", nodes, test.wantNodes)
+			}
+
+			pageInfo := r.PageInfo()
+			if hasNextPage := test.wantEndCursor != ""; pageInfo.HasNextPage() != hasNextPage {
+				t.Errorf("got hasNextPage %v, want %v", pageInfo.HasNextPage(), hasNextPage)
+			}
+			if c := pageInfo.EndCursor(); test.wantEndCursor != "" && (c == nil || string(*c) != test.wantEndCursor) {
+				t.Errorf("got endCursor %v, want %q", c, test.wantEndCursor)
+			}
+
+			if got := r.TotalCount(); got != int32(len(refs)) {
+				t.Errorf("got totalCount %d, want %d", got, len(refs))
+			}
+		})
+	}
+}
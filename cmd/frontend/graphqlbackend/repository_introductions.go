@@ -0,0 +1,134 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func (r *siteResolver) RepositoryIntroductions(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	ExternalService *graphql.ID
+}) (*repositoryIntroductionConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may view how repositories were added.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.RepoIntroductionsListOptions
+	if args.ExternalService != nil {
+		id, err := unmarshalExternalServiceID(*args.ExternalService)
+		if err != nil {
+			return nil, err
+		}
+		opt.ExternalServiceID = id
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &repositoryIntroductionConnectionResolver{opt: opt}, nil
+}
+
+// repositoryIntroductionConnectionResolver resolves a list of repository
+// introductions.
+//
+// 🚨 SECURITY: When instantiating a repositoryIntroductionConnectionResolver
+// value, the caller MUST check that the actor is a site admin.
+type repositoryIntroductionConnectionResolver struct {
+	opt db.RepoIntroductionsListOptions
+
+	// cache results because they are used by multiple fields
+	once          sync.Once
+	introductions []*types.RepoIntroduction
+	err           error
+}
+
+func (r *repositoryIntroductionConnectionResolver) compute(ctx context.Context) ([]*types.RepoIntroduction, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.introductions, r.err = db.RepoIntroductions.List(ctx, opt2)
+	})
+	return r.introductions, r.err
+}
+
+func (r *repositoryIntroductionConnectionResolver) Nodes(ctx context.Context) ([]*repositoryIntroductionResolver, error) {
+	introductions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(introductions) > r.opt.LimitOffset.Limit {
+		introductions = introductions[:r.opt.LimitOffset.Limit]
+	}
+
+	resolvers := make([]*repositoryIntroductionResolver, len(introductions))
+	for i, introduction := range introductions {
+		resolvers[i] = &repositoryIntroductionResolver{introduction: introduction}
+	}
+	return resolvers, nil
+}
+
+func (r *repositoryIntroductionConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.RepoIntroductions.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *repositoryIntroductionConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	introductions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(introductions) > r.opt.Limit), nil
+}
+
+type repositoryIntroductionResolver struct {
+	introduction *types.RepoIntroduction
+}
+
+func (r *repositoryIntroductionResolver) RepositoryName() string {
+	return string(r.introduction.RepoName)
+}
+
+func (r *repositoryIntroductionResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := db.Repos.Get(ctx, r.introduction.RepoID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(repo), nil
+}
+
+func (r *repositoryIntroductionResolver) ExternalService(ctx context.Context) (*externalServiceResolver, error) {
+	externalService, err := db.ExternalServices.GetByID(ctx, r.introduction.ExternalServiceID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &externalServiceResolver{externalService: externalService}, nil
+}
+
+func (r *repositoryIntroductionResolver) Author(ctx context.Context) (*UserResolver, error) {
+	if r.introduction.AuthorUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *r.introduction.AuthorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *repositoryIntroductionResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.introduction.CreatedAt}
+}
@@ -0,0 +1,70 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func (r *schemaResolver) SetRepositoryKeyValuePair(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Key        string
+	Value      *string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can set the key-value pairs of repositories, because
+	// they are visible to all users and affect search results.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.RepoKVPs.Set(ctx, repo.repo.ID, args.Key, args.Value); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DeleteRepositoryKeyValuePair(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Key        string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can delete the key-value pairs of repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.RepoKVPs.Delete(ctx, repo.repo.ID, args.Key); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *RepositoryResolver) KeyValuePairs(ctx context.Context) ([]*keyValuePairResolver, error) {
+	kvps, err := db.RepoKVPs.List(ctx, r.repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*keyValuePairResolver, len(kvps))
+	for i, kvp := range kvps {
+		resolvers[i] = &keyValuePairResolver{kvp: kvp}
+	}
+	return resolvers, nil
+}
+
+// keyValuePairResolver implements the GraphQL type KeyValuePair.
+type keyValuePairResolver struct {
+	kvp db.RepoKVP
+}
+
+func (r *keyValuePairResolver) Key() string { return r.kvp.Key }
+
+func (r *keyValuePairResolver) Value() *string { return r.kvp.Value }
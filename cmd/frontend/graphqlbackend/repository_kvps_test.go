@@ -0,0 +1,49 @@
+package graphqlbackend
+
+import (
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestRepository_KeyValuePairs(t *testing.T) {
+	resetMocks()
+	db.Mocks.Repos.MockGetByName(t, "github.com/gorilla/mux", 2)
+	db.Mocks.RepoKVPs.List = func(repoID api.RepoID) ([]db.RepoKVP, error) {
+		if repoID != 2 {
+			t.Errorf("got repo ID %d, want 2", repoID)
+		}
+		team := "search"
+		return []db.RepoKVP{{Key: "archived"}, {Key: "team", Value: &team}}, nil
+	}
+	defer func() { db.Mocks.RepoKVPs = db.MockRepoKVPs{} }()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t, nil),
+			Query: `
+				{
+					repository(name: "github.com/gorilla/mux") {
+						keyValuePairs {
+							key
+							value
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repository": {
+						"keyValuePairs": [
+							{"key": "archived", "value": null},
+							{"key": "team", "value": "search"}
+						]
+					}
+				}
+			`,
+		},
+	})
+}
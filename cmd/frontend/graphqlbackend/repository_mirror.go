@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
@@ -172,6 +173,40 @@ func (r *updateQueueResolver) Total() int32 {
 	return int32(r.queue.Total)
 }
 
+func (r *repositoryMirrorInfoResolver) Submodules(ctx context.Context) ([]*RepositoryResolver, error) {
+	info, err := r.repoUpdateSchedulerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repositoriesByUpdateSchedulerIDs(ctx, info.Submodules)
+}
+
+func (r *repositoryMirrorInfoResolver) Supermodules(ctx context.Context) ([]*RepositoryResolver, error) {
+	info, err := r.repoUpdateSchedulerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repositoriesByUpdateSchedulerIDs(ctx, info.Supermodules)
+}
+
+// repositoriesByUpdateSchedulerIDs returns the repositories with the given IDs
+// that the current user may see.
+func repositoriesByUpdateSchedulerIDs(ctx context.Context, ids []uint32) ([]*RepositoryResolver, error) {
+	resolvers := make([]*RepositoryResolver, 0, len(ids))
+	for _, id := range ids {
+		// 🚨 SECURITY: db.Repos.Get enforces repository permissions, so the
+		// repositories that the user may not see are omitted.
+		repo, err := db.Repos.Get(ctx, api.RepoID(id))
+		if errcode.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, &RepositoryResolver{repo: repo})
+	}
+	return resolvers, nil
+}
+
 func (r *schemaResolver) CheckMirrorRepositoryConnection(ctx context.Context, args *struct {
 	Repository *graphql.ID
 	Name       *string
@@ -242,7 +277,9 @@ func (r *schemaResolver) UpdateMirrorRepository(ctx context.Context, args *struc
 	if err != nil {
 		return nil, err
 	}
-	if _, err := repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, gitserverRepo); err != nil {
+	// The update was requested from the UI, so it jumps ahead of the
+	// background updates.
+	if _, err := repoupdater.DefaultClient.EnqueuePriorityRepoUpdate(ctx, gitserverRepo); err != nil {
 		return nil, err
 	}
 	return &EmptyResponse{}, nil
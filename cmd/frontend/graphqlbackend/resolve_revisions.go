@@ -0,0 +1,72 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// maxResolveRevisions is the maximum number of revisions that
+// Query.resolveRevisions resolves at once.
+const maxResolveRevisions = 1000
+
+func (r *schemaResolver) ResolveRevisions(ctx context.Context, args *struct {
+	Revisions []*struct {
+		Repository graphql.ID
+		Rev        string
+	}
+}) ([]*resolvedRevisionResolver, error) {
+	if len(args.Revisions) > maxResolveRevisions {
+		return nil, fmt.Errorf("at most %d revisions may be resolved at once, got %d", maxResolveRevisions, len(args.Revisions))
+	}
+
+	repos := map[graphql.ID]*RepositoryResolver{}
+	revs := make([]backend.RepoRev, len(args.Revisions))
+	for i, input := range args.Revisions {
+		repo, ok := repos[input.Repository]
+		if !ok {
+			// 🚨 SECURITY: repositoryByID fails for repositories the user may not access.
+			var err error
+			if repo, err = repositoryByID(ctx, input.Repository); err != nil {
+				return nil, err
+			}
+			repos[input.Repository] = repo
+		}
+		revs[i] = backend.RepoRev{Repo: repo.repo, Rev: input.Rev}
+	}
+
+	resolved := backend.Repos.ResolveRevs(ctx, revs)
+	resolvers := make([]*resolvedRevisionResolver, len(resolved))
+	for i, res := range resolved {
+		if res.Err != nil && !gitserver.IsRevisionNotFound(res.Err) {
+			return nil, res.Err
+		}
+		resolvers[i] = &resolvedRevisionResolver{
+			repo:     repos[args.Revisions[i].Repository],
+			rev:      revs[i].Rev,
+			commitID: string(res.CommitID),
+		}
+	}
+	return resolvers, nil
+}
+
+type resolvedRevisionResolver struct {
+	repo     *RepositoryResolver
+	rev      string
+	commitID string // empty if the revision doesn't exist
+}
+
+func (r *resolvedRevisionResolver) Repository() *RepositoryResolver { return r.repo }
+
+func (r *resolvedRevisionResolver) Rev() string { return r.rev }
+
+func (r *resolvedRevisionResolver) OID() *GitObjectID {
+	if r.commitID == "" {
+		return nil
+	}
+	oid := GitObjectID(r.commitID)
+	return &oid
+}
@@ -31,14 +31,30 @@ type Mutation {
     # pull request on GitHub). If a changeset with the given input already
     # exists, it's returned instead of a new entry being added to the database.
     createChangesets(input: [CreateChangesetInput!]!): [Changeset!]!
+    # Retries syncing a Changeset with its code host right away, regardless of
+    # whether its last sync failed permanently or when its next retry is due.
+    retryChangeset(changeset: ID!): Changeset!
+    # Adds a comment with the given body to each of the Changesets on their code
+    # host. The comments are posted by the user of the code host's access token.
+    commentOnChangesets(changesets: [ID!]!, body: String!): [Changeset!]!
+    # Submits a review of each of the Changesets on their code host, e.g. to approve
+    # them or request changes in bulk. The reviews are submitted by the user of the
+    # code host's access token.
+    reviewChangesets(changesets: [ID!]!, event: ChangesetReviewEvent!, body: String): [Changeset!]!
     # Adds a list of Changesets to a Campaign.
     addChangesetsToCampaign(campaign: ID!, changesets: [ID!]!): Campaign!
     # Create a campaign in a namespace. The newly created campaign is returned.
     createCampaign(input: CreateCampaignInput!): Campaign!
     # Updates a campaign.
     updateCampaign(input: UpdateCampaignInput!): Campaign!
-    # Deletes a campaign.
+    # Deletes a campaign. Its changesets are left as they are on their code hosts; use closeCampaign
+    # to close or detach them first.
     deleteCampaign(campaign: ID!): EmptyResponse
+    # Closes a campaign, after which no more changesets can be added to it. If closeChangesets is
+    # true, its open changesets are closed on their code hosts with the code hosts' access tokens.
+    # Otherwise they're detached from the campaign and left open. Closing a campaign again retries
+    # the changesets that failed to close. Only site admins may perform this mutation.
+    closeCampaign(campaign: ID!, closeChangesets: Boolean = false): CloseCampaignResult!
     # Updates the user profile information for the user with the given ID.
     #
     # Only the user and site admins may perform this mutation.
@@ -59,6 +75,49 @@ type Mutation {
     updateExternalService(input: UpdateExternalServiceInput!): ExternalService!
     # Delete an external service. Only site admins may perform this mutation.
     deleteExternalService(externalService: ID!): EmptyResponse!
+    # Restores the configuration that an external service had at the given revision of its
+    # configuration history, which is recorded as a new revision. Only site admins may perform
+    # this mutation.
+    rollbackExternalService(externalService: ID!, toRevision: Int!): ExternalService!
+    # Starts connecting a GitHub or GitLab code host with OAuth, instead of with a manually created
+    # access token. The site admin must then visit the authorizationURL of the returned setup to
+    # authorize Sourcegraph on the code host, which redirects back to the setup's redirectURL with
+    # the code and state to pass to completeExternalServiceOAuthSetup.
+    #
+    # Only site admins may perform this mutation.
+    startExternalServiceOAuthSetup(input: StartExternalServiceOAuthSetupInput!): ExternalServiceOAuthSetup!
+    # Exchanges the code that the code host redirected back with for an access token.
+    #
+    # Only the site admin who started the setup may perform this mutation.
+    completeExternalServiceOAuthSetup(state: String!, code: String!): ExternalServiceOAuthSetup!
+    # Adds an external service that uses the access token of a completed OAuth setup.
+    #
+    # Only the site admin who started the setup may perform this mutation.
+    addExternalServiceFromOAuthSetup(input: AddExternalServiceFromOAuthSetupInput!): ExternalService!
+    # Excludes a repository, or the files matching a glob pattern in all repositories, from indexed
+    # search, e.g. because they contain large generated files that bloat the index. Exactly one of
+    # repository and pathPattern must be given.
+    #
+    # Only site admins may perform this mutation.
+    addSearchIndexExclusion(repository: ID, pathPattern: String): SearchIndexExclusion!
+    # Removes an exclusion from indexed search, so that its repository or files are indexed again.
+    #
+    # Only site admins may perform this mutation.
+    deleteSearchIndexExclusion(searchIndexExclusion: ID!): EmptyResponse!
+    # Starts an exhaustive search job for the current user. The job finds all results of the query
+    # (as if it had "count:all"), searching all matching repositories in the background without the
+    # timeout of interactive searches. Its progress is persisted, so that it resumes after restarts.
+    createSearchJob(
+        # The search query.
+        query: String!
+        # The pattern type of the query, if it is not specified in the query with the patternType:
+        # field. The default is literal.
+        patternType: SearchPatternType
+    ): SearchJob!
+    # Cancels a search job that is not finished. The results found so far remain available.
+    #
+    # Only the user who created the search job and site admins may perform this mutation.
+    cancelSearchJob(searchJob: ID!): SearchJob!
     # DEPRECATED: All repositories are accessible or deleted. To prevent a
     # repository from being accessed on Sourcegraph add it to the external
     # service exclude configuration. This mutation will be removed in 3.6.
@@ -119,6 +178,31 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     deleteRepository(repository: ID!): EmptyResponse @deprecated(reason: "update external service exclude setting.")
+    # Deletes repositories and the data associated with them, irreversibly. Their changesets are
+    # deleted and detached from their campaigns, they are removed from indexed search, and their
+    # clones on gitserver are removed by the next run of the repository purge worker.
+    #
+    # Repositories that originate from a configured code host are re-added during the next sync,
+    # unless they are also excluded in the external service configuration.
+    #
+    # Only site admins may perform this mutation.
+    deleteRepositories(repos: [ID!]!): [RepositoryDeletion!]!
+    # Overrides the visibility of a repository on Sourcegraph, regardless of its visibility on the
+    # code host and of the permissions reported by authorization providers. A null visibility removes
+    # the override.
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryVisibilityOverride(repository: ID!, visibility: RepositoryVisibility): EmptyResponse!
+    # Sets the value of a key-value pair of a repository (such as "team: search"), adding the pair
+    # if it doesn't exist yet. The value is optional. Repositories can be searched by their key-value
+    # pairs with repo:has.meta(key) and repo:has.meta(key:value).
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryKeyValuePair(repository: ID!, key: String!, value: String): EmptyResponse!
+    # Deletes a key-value pair of a repository.
+    #
+    # Only site admins may perform this mutation.
+    deleteRepositoryKeyValuePair(repository: ID!, key: String!): EmptyResponse!
     # Creates a new user account.
     #
     # Only site admins may perform this mutation.
@@ -327,6 +411,16 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     setUserIsSiteAdmin(userID: ID!, siteAdmin: Boolean!): EmptyResponse
+    # Makes the subsequent GraphQL requests of the current user, a site admin, evaluate repository
+    # permissions and settings as the specified user, to debug what that user can see. It lasts for an
+    # hour unless stopImpersonatingUser is called. While impersonating a user, no other mutations can be
+    # performed. Starting and stopping impersonations is logged.
+    #
+    # Only site admins may perform this mutation.
+    impersonateUser(user: ID!): EmptyResponse
+    # Stops the impersonation started by impersonateUser. It must be called by the site admin that is
+    # impersonating the user (while the requests still act as the impersonated user).
+    stopImpersonatingUser: EmptyResponse
     # Reloads the site by restarting the server. This is not supported for all deployment
     # types. This may cause downtime.
     #
@@ -390,6 +484,23 @@ input UpdateCampaignInput {
 
     # The updated description of the campaign as Markdown (if non-null).
     description: String
+
+    # The updated labels of the campaign (if non-null). See Campaign.labels.
+    labels: [String!]
+
+    # The updated URL that notifications about state transitions of the campaign are sent to (if
+    # non-null). See Campaign.webhookURL. An empty string disables the notifications.
+    webhookURL: String
+
+    # The updated secret that the payloads of the webhook notifications are signed with (if
+    # non-null). The hex encoded HMAC-SHA256 of each payload, keyed with this secret, is sent in the
+    # X-Sourcegraph-Signature header as "sha256=<signature>".
+    webhookSecret: String
+
+    # The Campaign.updatedAt of the campaign that the update is based on (if non-null). If the
+    # campaign was updated since, the update fails with an error whose "code" extension is
+    # "ErrConflict", instead of overwriting the other update.
+    updatedAt: DateTime
 }
 
 # A collection of threads.
@@ -418,9 +529,32 @@ type Campaign implements Node {
     # The date and time when the campaign was updated.
     updatedAt: DateTime!
 
+    # The date and time when the campaign was closed, or null if it is open.
+    closedAt: DateTime
+
+    # The labels of the campaign, used to group and filter campaigns (e.g., to track related
+    # migrations across many campaigns). They are not added to the changesets.
+    labels: [String!]!
+
+    # The URL that a JSON payload is POSTed to when the campaign transitions to another state: when
+    # all of its changesets are merged ("campaign.completed") and when one of its changesets fails
+    # to sync with its code host ("changeset.failed"). Null if no webhook is configured.
+    webhookURL: String
+
+    # The current states of the changesets in this campaign as CSV, with a header row, for import
+    # into program-management tools.
+    changesetsCSV: String!
+
     # The changesets in this campaign.
     changesets(first: Int): ChangesetConnection!
 
+    # The changesets in this campaign grouped by their diffs, so that changesets making the same
+    # change in different repositories can be reviewed once: review the representative of a group
+    # and submit the same review for the rest with reviewChangesets. Changesets whose diffs can't
+    # be computed (e.g. because they weren't synced since their commits were recorded) are left
+    # out. Only site admins may access the patch groups.
+    patchGroups: [PatchGroup!]!
+
     # The changeset counts over time, in 1 day intervals backwards from the point in time given in 'to'.
     changesetCountsOverTime(
         # Only include changeset counts up to this point in time (inclusive).
@@ -432,6 +566,46 @@ type Campaign implements Node {
     ): [ChangesetCounts!]!
 }
 
+# A group of changesets whose diffs are identical except for the paths of the changed files.
+type PatchGroup {
+    # The SHA-256 hash of the normalized diff that the changesets have in common.
+    hash: String!
+
+    # The changeset to review on behalf of the group (the one created first).
+    representative: Changeset!
+
+    # The changesets of the group, including the representative.
+    changesets: [Changeset!]!
+
+    # The number of changesets of the group.
+    count: Int!
+}
+
+# The result of closing a campaign.
+type CloseCampaignResult {
+    # The closed campaign.
+    campaign: Campaign!
+
+    # The outcome for each changeset of the campaign that was open.
+    changesets: [ClosedChangeset!]!
+}
+
+# The outcome of closing a campaign for one of its open changesets.
+type ClosedChangeset {
+    # The changeset.
+    changeset: Changeset!
+
+    # Whether the changeset was closed on its code host.
+    closed: Boolean!
+
+    # Whether the changeset was detached from the campaign and left open on its code host.
+    detached: Boolean!
+
+    # The error that occurred while closing the changeset on its code host, if any. The changeset
+    # stays attached to the campaign.
+    error: String
+}
+
 # The counts of changesets in certain states at a specific point in time.
 type ChangesetCounts {
     # The point in time these counts were recorded.
@@ -452,6 +626,15 @@ type ChangesetCounts {
     openPending: Int!
 }
 
+# The changeset counts of all campaigns with a label.
+type LabelChangesetCounts {
+    # The campaign label.
+    label: String!
+    # The current counts of the changesets in all campaigns with the label. A changeset that is in
+    # several of these campaigns is only counted once.
+    counts: ChangesetCounts!
+}
+
 # A list of campaigns.
 type CampaignConnection {
     # A list of campaigns.
@@ -464,6 +647,18 @@ type CampaignConnection {
     pageInfo: PageInfo!
 }
 
+# The role of a user in a campaign.
+enum CampaignRole {
+    # The user created the campaign.
+    AUTHOR
+    # The user was requested to review or reviewed a changeset of the campaign, as identified by
+    # their code host accounts.
+    REVIEWER
+    # The user authored, reviewed or commented on a changeset of the campaign, as identified by
+    # their code host accounts.
+    PARTICIPANT
+}
+
 # A Changeset's state
 enum ChangesetState {
     OPEN
@@ -478,6 +673,16 @@ enum ChangesetReviewState {
     PENDING
 }
 
+# The kind of a review submitted with the reviewChangesets mutation.
+enum ChangesetReviewEvent {
+    # Approve the changes.
+    APPROVE
+    # Request changes before the changeset can be merged.
+    REQUEST_CHANGES
+    # Comment on the changes without approving them or requesting changes.
+    COMMENT
+}
+
 # The input to the createChangesets mutation.
 input CreateChangesetInput {
     # The repository ID that this Changeset belongs to.
@@ -521,6 +726,17 @@ type Changeset implements Node {
 
     # The review state of this changeset.
     reviewState: ChangesetReviewState!
+
+    # The number of consecutive times syncing this changeset with the code host failed.
+    syncFailures: Int!
+
+    # The error message of the last failed sync with the code host, if any.
+    syncError: String
+
+    # When syncing this changeset is retried next, if its last sync failed. Null if
+    # the failure is considered permanent, in which case the changeset is only
+    # retried with the retryChangeset mutation.
+    nextSyncRetryAt: DateTime
 }
 
 # A list of changesets.
@@ -569,6 +785,31 @@ input AddExternalServiceInput {
     config: String!
 }
 
+# A new OAuth setup of an external service.
+input StartExternalServiceOAuthSetupInput {
+    # The kind of the external service (GITHUB or GITLAB).
+    kind: ExternalServiceKind!
+    # The URL of the code host, such as https://github.com or https://gitlab.example.com.
+    url: String!
+    # The client ID of the OAuth application registered on the code host. The callback URL of the
+    # application must be the redirectURL of the setup.
+    clientID: String!
+    # The client secret of the OAuth application.
+    clientSecret: String!
+}
+
+# A new external service that uses the access token of a completed OAuth setup.
+input AddExternalServiceFromOAuthSetupInput {
+    # The ID of the setup.
+    setup: String!
+    # The display name of the external service.
+    displayName: String!
+    # The names of the namespaces (GitHub organizations or GitLab groups) whose repositories to
+    # mirror. If empty, all repositories that the user who authorized Sourcegraph is a member of are
+    # mirrored.
+    namespaces: [String!]!
+}
+
 # Fields to update for an existing external service.
 input UpdateExternalServiceInput {
     # The id of the external service to update.
@@ -888,8 +1129,26 @@ type Query {
     campaigns(
         # Returns the first n campaigns from the list.
         first: Int
+        # Only return campaigns that have all of these labels.
+        labels: [String!]
     ): CampaignConnection!
 
+    # A list of the campaigns that the viewer is involved in, in the given role. Unlike campaigns,
+    # this is available to all authenticated users.
+    viewerCampaigns(
+        # Returns the first n campaigns from the list.
+        first: Int
+        # The role of the viewer in the campaigns.
+        role: CampaignRole!
+    ): CampaignConnection!
+
+    # The current changeset counts of campaigns, grouped by the labels of the campaigns and sorted by
+    # label. Campaigns without labels are not counted.
+    changesetCountsByLabel(
+        # Only count campaigns that have all of these labels.
+        labels: [String!]
+    ): [LabelChangesetCounts!]!
+
     # Looks up a repository by either name or cloneURL.
     repository(
         # Query the repository by name, for example "github.com/gorilla/mux".
@@ -901,11 +1160,20 @@ type Query {
         # An alias for name. DEPRECATED: use name instead.
         uri: String
     ): Repository
+    # Resolves revisions of many repositories at once, e.g. for pages that show many repositories.
+    # This is faster than resolving them one at a time, and duplicate revisions are only resolved
+    # once. At most 1000 revisions may be given.
+    resolveRevisions(revisions: [RepositoryRevisionInput!]!): [ResolvedRevision!]!
     # Lists all external services.
     externalServices(
         # Returns the first n external services from the list.
         first: Int
     ): ExternalServiceConnection!
+    # Lists the repositories and file paths excluded from indexed search. Only site admins may list
+    # them.
+    searchIndexExclusions: SearchIndexExclusionConnection!
+    # Lists the search jobs of the current user, newest first.
+    searchJobs: SearchJobConnection!
     # List all repositories.
     repositories(
         # Returns the first n repositories from the list.
@@ -932,6 +1200,11 @@ type Query {
         indexed: Boolean = true
         # Include repositories that do not have a text search index.
         notIndexed: Boolean = true
+        # Only include repositories synced from the external service with this ID.
+        externalService: ID
+        # Only include repositories that were last fetched from their code host before this time (or
+        # never).
+        lastFetchedBefore: DateTime
         # Sort field.
         orderBy: RepositoryOrderBy = REPOSITORY_NAME
         # Sort direction.
@@ -946,6 +1219,13 @@ type Query {
     ): PhabricatorRepo
     # The current user.
     currentUser: User
+    # The API quota of the current user (or of the access token or anonymous client that made the
+    # request), as of after the cost of the current request. Null if API quotas are disabled (see the
+    # "api.quota" site configuration property).
+    viewerApiQuota: APIQuota
+    # The site admin impersonating the current user in this request (see impersonateUser), or null if the
+    # current user is not impersonated.
+    impersonator: User
     # Looks up a user by username or email address.
     user(
         # Query the user by username.
@@ -1063,6 +1343,38 @@ type Query {
         # A future request can be made for more results by passing in the
         # 'SearchResults.pageInfo.endCursor' that is returned.
         first: Int
+
+        # Whether to collect per-repository timings while searching. They are
+        # reported in 'SearchResults.repositorySearchTimings' and the trace of the
+        # request, to help attribute slow searches to specific repositories.
+        debug: Boolean = false
+
+        # Whether to fetch the last commit that modified the file of each file match, which is then
+        # available as 'FileMatch.lastCommit'. The last commits are fetched in batches and cached,
+        # so that result lists can show the freshness of files without a request per file.
+        includeCommitInfo: Boolean = false
+
+        # The name of the repository whose page the search was run from, if any. If the query has no
+        # repo: or repogroup: filter, the search is scoped to this repository, and the scope is
+        # listed first in 'SearchResults.dynamicFilters' with the kind "context" so that users can
+        # remove it.
+        contextRepo: String
+    ): Search
+    # Explains how a search query is interpreted, without running the search.
+    explainSearchQuery(
+        # The version of the search syntax being used.
+        version: SearchVersion = V1
+        # PatternType controls the search pattern type, if and only if it is not specified in the query string using
+        # the patternType: field.
+        patternType: SearchPatternType
+        # The search query (such as "foo" or "repo:myrepo foo").
+        query: String = ""
+    ): SearchQueryExplanation!
+    # Runs a search from the viewer's search history again, with the same query, version and pattern type
+    # as the original search.
+    resumeSearch(
+        # The ID of the search history entry to run again.
+        id: ID!
     ): Search
     # All saved searches configured for the current user, merged from all configurations.
     savedSearches: [SavedSearch!]!
@@ -1100,6 +1412,69 @@ enum SearchVersion {
 enum SearchPatternType {
     literal
     regexp
+    # Matches the paths that contain the characters of each term of the pattern in order, ranked by
+    # how well they match (like fzf). Only supports type:path.
+    fuzzy
+}
+
+# An explanation of how a search query is interpreted.
+type SearchQueryExplanation {
+    # The pattern type that the query is interpreted with.
+    patternType: SearchPatternType!
+    # The regular expression that file contents and paths are matched against, or null if the query
+    # has no search pattern.
+    pattern: String
+    # The filters in the query (such as "repo:myrepo" or "-file:_test"), in order.
+    filters: [SearchQueryFilter!]!
+    # The types of results that are searched for (such as "file", "path", "repo" or "diff").
+    resultTypes: [String!]!
+    # The number of repositories that would be searched.
+    repositoriesCount: Int!
+    # The number of the repositories that would be searched that are indexed.
+    indexedRepositoriesCount: Int!
+    # Whether the query matches more repositories than can be searched at once, in which case the
+    # search would return an alert instead of results.
+    repositoriesLimitHit: Boolean!
+    # The backends that the search would run on: "zoekt" (indexed search), "searcher" (unindexed
+    # search), "symbols", "gitserver" (diff and commit search) and "replacer" (codemod).
+    backends: [String!]!
+}
+
+# A filter in a search query.
+type SearchQueryFilter {
+    # The field of the filter (such as "repo").
+    field: String!
+    # The raw value of the filter (such as "myrepo").
+    value: String!
+    # Whether the filter is negated (such as "-repo:myrepo").
+    negated: Boolean!
+}
+
+# A search in a user's search history.
+type SearchHistoryEntry {
+    # The unique ID of the search history entry.
+    id: ID!
+    # The search query.
+    query: String!
+    # The version of the search syntax used by the search.
+    version: SearchVersion!
+    # The pattern type used by the search.
+    patternType: SearchPatternType!
+    # The number of results the search returned.
+    resultCount: Int!
+    # The date when the search was run.
+    createdAt: DateTime!
+}
+
+# A list of searches in a user's search history.
+type SearchHistoryConnection {
+    # A list of searches.
+    nodes: [SearchHistoryEntry!]!
+    # The total count of searches in the connection. This total count may be larger than the number of nodes in
+    # this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
 }
 
 # Configuration details for the browser extension, editor extensions, etc.
@@ -1221,6 +1596,10 @@ type SearchResults {
     timedout: [Repository!]!
     # True if indexed search is enabled but was not available during this search.
     indexUnavailable: Boolean!
+    # The number of repositories that were searched without the index because it was not
+    # available. When the index is not available, only a limited number of the repositories that
+    # would have been searched with the index are searched.
+    indexUnavailableFallbackCount: Int!
     # An alert message that should be displayed before any results.
     alert: SearchAlert
     # The time it took to generate these results.
@@ -1231,6 +1610,22 @@ type SearchResults {
     #
     # This field is only applcable when the original request was a paginated one.
     pageInfo: PageInfo!
+    # The time it took to search each repository. Only set when the search was run with
+    # debug: true.
+    repositorySearchTimings: [RepositorySearchTiming!]
+}
+
+# Timing information about searching a single repository.
+type RepositorySearchTiming {
+    # The repository that was searched.
+    repository: Repository!
+    # The search backend used to search the repository ("zoekt" or "searcher").
+    backend: String!
+    # The time it took to search the repository. Indexed repositories are searched
+    # with a single request to zoekt, whose duration is reported for each of them.
+    durationMilliseconds: Int!
+    # The number of matches found in the repository.
+    resultCount: Int!
 }
 
 # Statistics about search results.
@@ -1251,7 +1646,9 @@ type SearchFilter {
     count: Int!
     # Whether the results returned are incomplete.
     limitHit: Boolean!
-    # The kind of filter. Should be "file" or "repo".
+    # The kind of filter. Should be "file", "repo", "repogroup", "lang", "symbol", "case" or "context".
+    # A "context" filter is the implicit scope of the search to the repository given by the
+    # contextRepo argument of 'Query.search'.
     kind: String!
 }
 
@@ -1407,10 +1804,40 @@ enum ExternalServiceKind {
     GITHUB
     GITLAB
     GITOLITE
+    MANIFEST
     PHABRICATOR
     OTHER
 }
 
+# A connection of a code host with OAuth that is in progress. Setups expire after an hour.
+type ExternalServiceOAuthSetup {
+    # The ID of the setup, which is also the state of the OAuth authorization.
+    id: String!
+    # The kind of the external service.
+    kind: ExternalServiceKind!
+    # The URL of the code host.
+    url: String!
+    # The URL on the code host that the site admin must visit to authorize Sourcegraph.
+    authorizationURL: String!
+    # The URL that the code host redirects back to after the authorization.
+    redirectURL: String!
+    # Whether the setup has an access token (see completeExternalServiceOAuthSetup).
+    authorized: Boolean!
+    # The namespaces (GitHub organizations or GitLab groups) that the user who authorized
+    # Sourcegraph is a member of, up to 100. It is an error to request them before the setup is
+    # authorized.
+    namespaces: [ExternalServiceOAuthNamespace!]!
+}
+
+# A GitHub organization or GitLab group.
+type ExternalServiceOAuthNamespace {
+    # The name of the namespace (the login of a GitHub organization or the full path of a GitLab
+    # group).
+    name: String!
+    # The URL of the namespace on the code host.
+    url: String!
+}
+
 # A configured external service.
 type ExternalService implements Node {
     # The external service's unique ID.
@@ -1425,6 +1852,12 @@ type ExternalService implements Node {
     createdAt: DateTime!
     # When the external service was last updated.
     updatedAt: DateTime!
+    # The history of the external service's configuration, latest revision first. Every
+    # configuration change is recorded as a new revision.
+    configHistory(
+        # Returns the first n revisions from the list.
+        first: Int
+    ): ExternalServiceConfigRevisionConnection!
     # This is an optional field that's populated when we ran into errors on the
     # backend side when trying to create/update an ExternalService, but the
     # create/update still succeeded.
@@ -1433,6 +1866,31 @@ type ExternalService implements Node {
     warning: String
 }
 
+# A revision of the configuration of an external service.
+type ExternalServiceConfigRevision {
+    # The revision number. The first configuration of an external service is revision 1.
+    revision: Int!
+    # The author, or null if the configuration was changed by Sourcegraph (e.g. from
+    # EXTSVC_CONFIG_FILE) or the authoring user was deleted.
+    author: User
+    # When the configuration was changed.
+    createdAt: DateTime!
+    # The changes from the previous revision, as a diff of the normalized JSON configurations.
+    # Secrets such as tokens and passwords are redacted, so changes to them are not shown.
+    diff: String!
+}
+
+# A list of revisions of the configuration of an external service.
+type ExternalServiceConfigRevisionConnection {
+    # A list of revisions.
+    nodes: [ExternalServiceConfigRevision!]!
+    # The total count of revisions in the connection. This total count may be larger than the
+    # number of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
 # A list of repositories.
 type RepositoryConnection {
     # A list of repositories.
@@ -1448,6 +1906,34 @@ type RepositoryConnection {
     pageInfo: PageInfo!
 }
 
+# The visibility of a repository on Sourcegraph.
+enum RepositoryVisibility {
+    # Visible to everyone, including unauthenticated users.
+    PUBLIC
+    # Visible to all authenticated users.
+    INTERNAL
+    # Only visible to site admins.
+    PRIVATE
+}
+
+# A report on a deleted repository and the data that was deleted along with it.
+type RepositoryDeletion {
+    # The name of the deleted repository.
+    name: String!
+    # The number of changesets on the repository that were deleted, which detached them from their campaigns.
+    changesetsDeleted: Int!
+    # The number of campaigns that the changesets were detached from.
+    campaignsAffected: Int!
+}
+
+# A key-value pair, whose value is optional.
+type KeyValuePair {
+    # The key.
+    key: String!
+    # The value, or null if the pair has none.
+    value: String
+}
+
 # A repository is a Git source control repository that is mirrored from some origin code host.
 type Repository implements Node & GenericSearchResultInterface {
     # The repository's unique ID.
@@ -1467,6 +1953,11 @@ type Repository implements Node & GenericSearchResultInterface {
     description: String!
     # The primary programming language in the repository.
     language: String!
+    # The visibility of the repository on Sourcegraph as set by a site admin, or null if its visibility
+    # is not overridden and is determined by the code host.
+    visibilityOverride: RepositoryVisibility
+    # The key-value pairs of the repository (such as "team: search"), ordered by key.
+    keyValuePairs: [KeyValuePair!]!
     # DEPRECATED: All repositories are enabled. This field will be removed in 3.6.
     #
     # Whether the repository is enabled. A disabled repository should only be accessible to site admins.
@@ -1519,6 +2010,8 @@ type Repository implements Node & GenericSearchResultInterface {
     gitRefs(
         # Returns the first n Git refs from the list.
         first: Int
+        # Opaque pagination cursor (the endCursor of the previous page).
+        after: String
         # Return Git refs whose names match the query.
         query: String
         # Return only Git refs of the given type.
@@ -1537,6 +2030,8 @@ type Repository implements Node & GenericSearchResultInterface {
     branches(
         # Returns the first n Git branches from the list.
         first: Int
+        # Opaque pagination cursor (the endCursor of the previous page).
+        after: String
         # Return Git branches whose names match the query.
         query: String
         # Ordering for Git branches in the list.
@@ -1550,8 +2045,12 @@ type Repository implements Node & GenericSearchResultInterface {
     tags(
         # Returns the first n Git tags from the list.
         first: Int
+        # Opaque pagination cursor (the endCursor of the previous page).
+        after: String
         # Return Git tags whose names match the query.
         query: String
+        # Ordering for Git tags in the list.
+        orderBy: GitRefOrder
     ): GitRefConnection!
     # A Git comparison in this repository between a base and head commit.
     comparison(
@@ -1617,6 +2116,11 @@ type MirrorRepositoryInfo {
     updateSchedule: UpdateSchedule
     # The state of this repository in the update queue.
     updateQueue: UpdateQueue
+    # The repositories that are submodules of this repository (according to the .gitmodules file of
+    # its default branch). They are updated before or together with this repository.
+    submodules: [Repository!]!
+    # The repositories of which this repository is a submodule.
+    supermodules: [Repository!]!
 }
 
 # The state of a repository in the update schedule.
@@ -1732,6 +2236,9 @@ type RepositoryComparison {
         # Return the first n file diffs from the list.
         first: Int
     ): FileDiffConnection!
+    # Whether the base commit is an ancestor of the head commit (i.e., the head commit contains all of
+    # the base's history). A commit is an ancestor of itself.
+    isAncestor: Boolean!
 }
 
 # A list of file diffs.
@@ -1925,6 +2432,24 @@ type SymbolConnection {
     pageInfo: PageInfo!
 }
 
+# A revision of a repository to resolve.
+input RepositoryRevisionInput {
+    # The repository.
+    repository: ID!
+    # The revision (e.g. a branch, tag or commit). The default branch if empty.
+    rev: String!
+}
+
+# A revision of a repository, resolved to a commit.
+type ResolvedRevision {
+    # The repository.
+    repository: Repository!
+    # The revision, as given.
+    rev: String!
+    # The ID of the commit that the revision resolves to, or null if the revision doesn't exist.
+    oid: GitObjectID
+}
+
 # A Git object ID (SHA-1 hash, 40 hexadecimal characters).
 scalar GitObjectID
 
@@ -1951,6 +2476,12 @@ type GitRef implements Node {
     target: GitObject!
     # The associated repository.
     repository: Repository!
+    # The number of commits that the ref is behind and ahead of the repository's default
+    # branch, or null if the repository has no default branch (e.g., because it is empty).
+    #
+    # When requested for the refs in a GitRefConnection, the counts are computed for all refs
+    # in the page at once.
+    behindAheadDefaultBranch: BehindAheadCounts
     # The URL to this Git ref.
     url: String!
 }
@@ -2126,6 +2657,34 @@ type GitCommit implements Node {
         # file paths returned in the list.
         includePatterns: [String!]
     ): SymbolConnection!
+    # The number of search results for the query in each top-level directory of the tree at this commit,
+    # for showing where the results are in the file tree. The counts are computed with indexed search,
+    # so this is null if this commit is not the indexed commit of the repository's default branch.
+    searchResultDirectoryCounts(
+        # The version of the search syntax being used.
+        version: SearchVersion = V1
+        # PatternType controls the search pattern type, if and only if it is not specified in the query string using
+        # the patternType: field.
+        patternType: SearchPatternType
+        # The search query (such as "foo" or "lang:go foo"). Repository filters are ignored.
+        query: String!
+    ): SearchResultDirectoryCounts
+}
+
+# The number of search results in each top-level directory of a tree.
+type SearchResultDirectoryCounts {
+    # The top-level directories that contain search results, ordered by path.
+    directories: [SearchResultDirectoryCount!]!
+    # Whether the search hit a limit, in which case the counts are lower than the actual number of results.
+    limitHit: Boolean!
+}
+
+# The number of search results in a directory.
+type SearchResultDirectoryCount {
+    # The path of the directory.
+    path: String!
+    # The number of search results in the directory.
+    count: Int!
 }
 
 # A set of Git behind/ahead counts for one commit relative to another.
@@ -2351,6 +2910,16 @@ type GitBlob implements TreeEntry & File2 {
     blame(startLine: Int!, endLine: Int!): [Hunk!]!
     # Highlight the blob contents.
     highlight(disableTimeout: Boolean!, isLightTheme: Boolean!): HighlightedFile!
+    # Highlight only the lines startLine through endLine (1-based and inclusive) of the blob contents. The
+    # lines of the returned HTML table are numbered by their line number in the blob. Only the requested lines
+    # are read and highlighted, so this is much cheaper than highlight for small ranges of large files (e.g.,
+    # for hover previews).
+    highlightedRange(
+        startLine: Int!
+        endLine: Int!
+        disableTimeout: Boolean = false
+        isLightTheme: Boolean!
+    ): HighlightedFile!
     # Submodule metadata if this tree points to a submodule
     submodule: Submodule
     # Symbols defined in this blob.
@@ -2395,8 +2964,36 @@ type FileMatch {
     symbols: [Symbol!]!
     # The line matches.
     lineMatches: [LineMatch!]!
+    # The owners of the file (users, teams or email addresses), as assigned by the ownership file
+    # of the repository (such as CODEOWNERS). Empty if the file has no owners.
+    owners: [String!]!
     # Whether or not the limit was hit.
     limitHit: Boolean!
+    # The identical matches of this file in forks (or mirrors) of the repository, which are collapsed
+    # into this match. They are instead listed as separate results if the query contains
+    # "dedupforks:no".
+    forkDuplicates: [FileMatch!]!
+    # For searches in several revisions of the repository, the revisions in which the file has the same
+    # contents (blob) as in this match, whose identical matches are collapsed into this one. Empty if the
+    # file was only matched in one revision.
+    foundInRevs: [String!]!
+    # For matches of a search in a revision range (such as "repo:foo@v1..v2"), the commit in the range
+    # that added or removed the matched lines. Null for other matches.
+    rangeChange: FileMatchRangeChange
+    # The last commit that modified the file, as of the searched revision (with its author and date).
+    # Only set if the search was run with 'includeCommitInfo: true', and null if it couldn't be
+    # determined in time.
+    lastCommit: GitCommit
+}
+
+# The commit of a revision range that added or removed the lines of a FileMatch. Lines that were
+# added are matched in the file as of the commit, and lines that were removed in the file as of its
+# first parent.
+type FileMatchRangeChange {
+    # The commit that added or removed the matched lines.
+    commit: GitCommit!
+    # Whether the matched lines were added (true) or removed (false) by the commit.
+    added: Boolean!
 }
 
 # A line match.
@@ -2539,6 +3136,14 @@ type User implements Node & SettingsSubject & Namespace {
     #
     # Only the user and site admins can access this field.
     surveyResponses: [SurveyResponse!]!
+    # The user's most recent searches, most recent first. Running the same search again replaces its previous
+    # entry, and only the most recent 100 searches are kept.
+    #
+    # Only the user and site admins can access this field.
+    searchHistory(
+        # Returns the first n searches from the list.
+        first: Int
+    ): SearchHistoryConnection!
     # The URL to view this user's customer information (for Sourcegraph.com site admins).
     #
     # Only Sourcegraph.com site admins may query this field.
@@ -3054,6 +3659,17 @@ type DefaultSettings implements SettingsSubject {
         )
 }
 
+# The state of an API quota. Each GraphQL request costs 1 point, and requests are rejected with HTTP
+# status 429 once the quota is used up, until it resets.
+type APIQuota {
+    # The number of points per window.
+    limit: Int!
+    # The number of points left in the current window.
+    remaining: Int!
+    # When the current window ends and the quota resets.
+    resetAt: DateTime!
+}
+
 # A site is an installation of Sourcegraph that consists of one or more
 # servers that share the same configuration and database.
 #
@@ -3152,6 +3768,185 @@ type Site implements SettingsSubject {
     #
     # Only site admins may retrieve this information.
     managementConsoleState: ManagementConsoleState!
+    # The disk usage of each gitserver shard, for capacity planning. Shards that
+    # could not be reached are omitted.
+    #
+    # Only site admins may retrieve this information.
+    gitserverShards: [GitserverShard!]!
+    # The row counts of the database tables that grow with the number of repositories synced
+    # from code hosts, compared with their soft quotas (the storage.softQuotas site configuration).
+    #
+    # Only site admins may retrieve this information.
+    storageStatus: StorageStatus!
+    # The record of when each external service first yielded each repository, and who configured
+    # the external service then, latest first. It is also available as CSV at
+    # /.api/repository-introductions.csv.
+    #
+    # Only site admins may retrieve this information.
+    repositoryIntroductions(
+        # Returns the first n introductions from the list.
+        first: Int
+        # Only returns the repositories introduced by this external service.
+        externalService: ID
+    ): RepositoryIntroductionConnection!
+    # Monitoring information about the site's services, as collected by
+    # Prometheus. Null if Prometheus is not configured (the PROMETHEUS_URL
+    # environment variable of the frontend is not set).
+    #
+    # Only site admins may retrieve this information.
+    monitoring: SiteMonitoring
+    # The repositories that were the most often in search results in the last 7 days, the most
+    # searched first. Only repositories, directories and revisions that were in the results of at
+    # least 5 searches are reported, and searches with results in more than 50 repositories are not
+    # counted.
+    #
+    # Only site admins may retrieve this information.
+    searchHotSpots(
+        # Returns the first n hot spots.
+        first: Int = 20
+    ): [SearchHotSpot!]!
+}
+
+# A repository that was often in search results.
+type SearchHotSpot {
+    # The repository.
+    repository: Repository!
+    # The number of searches that the repository was in the results of.
+    searches: Int!
+    # The directories of the repository that were the most often in search results, truncated to
+    # their first 2 path components ("" for the root directory).
+    paths: [SearchHotSpotCount!]!
+    # The revisions of the repository that were the most often searched, as specified in the
+    # queries ("HEAD" for the default branch).
+    revisions: [SearchHotSpotCount!]!
+}
+
+# The number of searches that a directory or revision of a search hot spot was in the results of.
+type SearchHotSpotCount {
+    # The directory or revision.
+    value: String!
+    # The number of searches.
+    searches: Int!
+}
+
+# Monitoring information about the site's services, as collected by
+# Prometheus.
+type SiteMonitoring {
+    # The alerts that are currently firing for search, repository syncing and
+    # gitserver, ordered by service and name. Alerts of other services are
+    # omitted.
+    alerts: [MonitoringAlert!]!
+}
+
+# A service whose alerts are reported in the site monitoring information.
+enum MonitoringService {
+    # The searchers and indexed search.
+    SEARCH
+    # The repo-updater, which syncs repositories from code hosts.
+    REPOSITORY_SYNCING
+    # The gitservers, which clone and store repositories.
+    GITSERVER
+}
+
+# A firing Prometheus alert.
+type MonitoringAlert {
+    # The name of the alert.
+    name: String!
+    # The service the alert is about.
+    service: MonitoringService!
+    # The severity of the alert (its severity label), if any.
+    severity: String
+    # A human-readable summary of the alert (its summary or description
+    # annotation), if any.
+    summary: String
+    # When the alert became active.
+    firingSince: DateTime!
+}
+
+# A record of when an external service first yielded a repository.
+type RepositoryIntroduction {
+    # The name of the repository when it was introduced.
+    repositoryName: String!
+    # The repository, or null if it was deleted.
+    repository: Repository
+    # The external service that yielded the repository, or null if it was deleted.
+    externalService: ExternalService
+    # The user who last changed the configuration of the external service before it yielded the
+    # repository, or null if unknown (e.g. for repositories that were introduced before
+    # introductions were recorded) or the user was deleted.
+    author: User
+    # When the external service first yielded the repository.
+    createdAt: DateTime!
+}
+
+# A list of repository introductions.
+type RepositoryIntroductionConnection {
+    # A list of repository introductions.
+    nodes: [RepositoryIntroduction!]!
+    # The total count of repository introductions in the connection. This total count may be
+    # larger than the number of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# The row counts of the database tables that grow with the number of repositories synced from code
+# hosts, as counted periodically.
+#
+# Only site admins may retrieve this information.
+type StorageStatus {
+    # When the rows were last counted, or null if they haven't been counted yet.
+    checkedAt: DateTime
+    # The counted tables.
+    tables: [StorageTableStatus!]!
+}
+
+# The row count of a database table and its soft quota.
+type StorageTableStatus {
+    # The name of the table.
+    name: String!
+    # The number of rows of the table.
+    rowCount: Float!
+    # The soft quota on the number of rows of the table, or null if it has none.
+    softQuota: Float
+    # Whether the table has at least 90% of its soft quota of rows, but not more than the quota.
+    nearing: Boolean!
+    # Whether the table has more rows than its soft quota.
+    exceeded: Boolean!
+}
+
+# The disk usage of a gitserver shard, which stores a subset of the cloned
+# repositories.
+#
+# Only site admins may retrieve this information.
+type GitserverShard {
+    # The address of the gitserver.
+    address: String!
+    # The number of bytes free on the disk holding the repositories. This is a
+    # Float because GraphQL's Int is limited to 32 bits.
+    freeBytes: Float!
+    # The size in bytes of the disk holding the repositories.
+    totalBytes: Float!
+    # The percentage of disk space the gitserver tries to keep free by evicting
+    # repositories that have not been used recently.
+    desiredPercentFree: Int!
+    # Whether less disk space than desiredPercentFree is free. No new
+    # repositories are cloned onto a nearly full shard.
+    nearlyFull: Boolean!
+    # The number of repositories cloned on the shard.
+    repositoryCount: Int!
+    # The largest repositories cloned on the shard, largest first.
+    largestRepositories: [GitserverRepositorySize!]!
+    # When the repository sizes were last computed.
+    computedAt: DateTime!
+}
+
+# The disk usage of a repository cloned on a gitserver shard.
+type GitserverRepositorySize {
+    # The name of the repository.
+    name: String!
+    # The size in bytes of the repository on disk.
+    bytes: Float!
 }
 
 # Information about this site's management console.
@@ -4062,11 +4857,97 @@ type SyncError {
     message: String!
 }
 
+# FOR INTERNAL USE ONLY: A status message produced when many repositories are
+# waiting to be updated
+type UpdateQueueBacklog {
+    # The message of this status message
+    message: String!
+    # The number of repositories waiting to be updated
+    count: Int!
+}
+
 # FOR INTERNAL USE ONLY: A status message
-union StatusMessage = CloningProgress | ExternalServiceSyncError | SyncError
+union StatusMessage = CloningProgress | ExternalServiceSyncError | SyncError | UpdateQueueBacklog
 
 # An RFC 3339-encoded UTC date string, such as 1973-11-29T21:33:09Z. This value can be parsed into a
 # JavaScript Date using Date.parse. To produce this value from a JavaScript Date instance, use
 # Date#toISOString.
 scalar DateTime
+
+# A repository, or a glob pattern of file paths in all repositories, that is excluded from indexed
+# search.
+type SearchIndexExclusion {
+    # The unique ID for the exclusion.
+    id: ID!
+    # The excluded repository, if a repository is excluded.
+    repository: Repository
+    # The glob pattern of the excluded file paths (e.g. "**/*.min.js"), if file paths are excluded.
+    pathPattern: String
+    # The byte size of the repository's index when it was excluded, i.e. the disk space saved by
+    # excluding it. It is null for path patterns, whose savings are not known.
+    savedIndexByteSize: Int
+    # The date when the exclusion was added.
+    createdAt: DateTime!
+}
+
+# A list of search index exclusions.
+type SearchIndexExclusionConnection {
+    # A list of search index exclusions.
+    nodes: [SearchIndexExclusion!]!
+    # The total count of search index exclusions.
+    totalCount: Int!
+    # The total byte size of the indexes of the excluded repositories when they were excluded.
+    savedIndexByteSize: Int!
+}
+
+# The state of a search job.
+enum SearchJobState {
+    # The search job waits to be run.
+    QUEUED
+    # The search job is running.
+    PROCESSING
+    # The search job searched all repositories.
+    COMPLETED
+    # The search job failed.
+    ERRORED
+    # The search job was canceled.
+    CANCELED
+}
+
+# An exhaustive search that finds all results of a query in the background (see
+# Mutation.createSearchJob).
+type SearchJob implements Node {
+    # The unique ID for the search job.
+    id: ID!
+    # The search query.
+    query: String!
+    # The state of the search job.
+    state: SearchJobState!
+    # The number of repositories that were searched so far.
+    repositoriesSearched: Int!
+    # The total number of repositories to search, or null if they were not resolved yet.
+    repositoriesTotal: Int
+    # The number of results found so far.
+    resultCount: Int!
+    # The names of the searched repositories whose results may be incomplete, because they timed out,
+    # were still being cloned, or their revisions were not found.
+    incompleteRepositories: [String!]!
+    # The error that the search job failed with, if it is errored.
+    error: String
+    # The date when the search job was created.
+    createdAt: DateTime!
+    # The date when the search job finished, if it is finished.
+    finishedAt: DateTime
+    # The URL to download the results found so far from, as newline-delimited JSON objects (one per
+    # result).
+    resultsURL: String!
+}
+
+# A list of search jobs.
+type SearchJobConnection {
+    # A list of search jobs.
+    nodes: [SearchJob!]!
+    # The total count of search jobs.
+    totalCount: Int!
+}
 `
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"gopkg.in/inconshreveable/log15.v2"
@@ -22,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	searchquerytypes "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/types"
@@ -51,12 +53,28 @@ func maxReposToSearch() int {
 	}
 }
 
+// maxReposToSearchCeiling returns the maximum number of repositories that can
+// be searched with the maxRepos: field.
+func maxReposToSearchCeiling() int {
+	switch max := conf.Get().MaxReposToSearchCeiling; {
+	case max == 0:
+		return maxReposToSearch()
+	case max < 0:
+		return math.MaxInt32 >> 1
+	default:
+		return max
+	}
+}
+
 type searchArgs struct {
-	Version     string
-	PatternType *string
-	Query       string
-	After       *graphql.ID
-	First       *int32
+	Version           string
+	PatternType       *string
+	Query             string
+	After             *graphql.ID
+	First             *int32
+	Debug             bool
+	IncludeCommitInfo bool
+	ContextRepo       *string
 }
 
 type searchIntf interface {
@@ -77,9 +95,12 @@ func (r *schemaResolver) Search(args *searchArgs) (searchIntf, error) {
 	}
 
 	var queryString string
-	if searchType == "literal" {
+	switch searchType {
+	case "literal":
 		queryString = query.ConvertToLiteral(args.Query)
-	} else {
+	case "fuzzy":
+		queryString = query.ConvertToFuzzy(args.Query)
+	default:
 		queryString = args.Query
 	}
 
@@ -88,6 +109,17 @@ func (r *schemaResolver) Search(args *searchArgs) (searchIntf, error) {
 		return &didYouMeanQuotedResolver{query: args.Query, err: err}, nil
 	}
 
+	// Searches from a repository page are scoped to that repository, unless the query
+	// selects repositories itself.
+	var contextRepo api.RepoName
+	if args.ContextRepo != nil && *args.ContextRepo != "" && !hasRepoScope(q) {
+		contextRepo = api.RepoName(*args.ContextRepo)
+		q, err = query.ParseAndCheck(contextRepoFilter(contextRepo) + " " + queryString)
+		if err != nil {
+			return &didYouMeanQuotedResolver{query: args.Query, err: err}, nil
+		}
+	}
+
 	// If the request is a paginated one, decode those arguments now.
 	var pagination *searchPaginationInfo
 	if args.First != nil {
@@ -107,17 +139,21 @@ func (r *schemaResolver) Search(args *searchArgs) (searchIntf, error) {
 	}
 
 	return &searchResolver{
-		query:         q,
-		originalQuery: args.Query,
-		pagination:    pagination,
-		patternType:   searchType,
-		zoekt:         search.Indexed(),
-		searcherURLs:  search.SearcherURLs(),
+		query:             q,
+		originalQuery:     args.Query,
+		version:           args.Version,
+		pagination:        pagination,
+		patternType:       searchType,
+		debug:             args.Debug,
+		includeCommitInfo: args.IncludeCommitInfo,
+		contextRepo:       contextRepo,
+		zoekt:             search.Indexed(),
+		searcherURLs:      search.SearcherURLs(),
 	}, nil
 }
 
-// detectSearchType returns the search type to perfrom ("regexp", or
-// "literal"). The search type derives from three sources: the version and
+// detectSearchType returns the search type to perfrom ("regexp",
+// "literal", or "fuzzy"). The search type derives from three sources: the version and
 // patternType parameters passed to the search endpoint (literal search is the
 // default in V2), and the `patternType:` filter in the input query string which
 // overrides the searchType, if present.
@@ -125,7 +161,7 @@ func detectSearchType(version string, patternType *string, input string) (string
 	var searchType string
 	if patternType != nil {
 		switch *patternType {
-		case "regexp", "literal":
+		case "regexp", "literal", "fuzzy":
 			searchType = *patternType
 		default:
 			return "", fmt.Errorf("unrecognized patternType: %v", patternType)
@@ -158,6 +194,8 @@ func detectSearchType(version string, patternType *string, input string) (string
 			searchType = "regexp"
 		case "literal":
 			searchType = "literal"
+		case "fuzzy":
+			searchType = "fuzzy"
 		}
 	}
 
@@ -179,8 +217,27 @@ func asString(v *searchquerytypes.Value) string {
 type searchResolver struct {
 	query         *query.Query          // the parsed search query
 	originalQuery string                // the raw string of the original search query
+	version       string                // the version of the search syntax
 	pagination    *searchPaginationInfo // pagination information, or nil if the request is not paginated.
 	patternType   string
+	debug         bool // whether to collect per-repository timings
+
+	// includeCommitInfo is whether to fetch the last commits of the files of
+	// file matches.
+	includeCommitInfo bool
+
+	// contextRepo is the repository that the search is implicitly scoped to
+	// because it was run from that repository's page, if any.
+	contextRepo api.RepoName
+
+	// exhaustive is whether the search must find all results, without the
+	// limits of interactive searches (see search_jobs.go).
+	exhaustive bool
+
+	// stream, if set, is called with each batch of results as soon as a
+	// search backend produces it (see StreamSearch). It may be called
+	// concurrently.
+	stream func([]searchResultResolver)
 
 	// Cached resolveRepositories results.
 	reposMu                   sync.Mutex
@@ -188,6 +245,11 @@ type searchResolver struct {
 	repoOverLimit             bool
 	repoErr                   error
 
+	// Cached searchExclusions results.
+	exclusionsOnce sync.Once
+	exclusions     *searchExclusions
+	exclusionsErr  error
+
 	zoekt        *searchbackend.Zoekt
 	searcherURLs *endpoint.Map
 }
@@ -205,6 +267,10 @@ func (r *searchResolver) countIsSet() bool {
 
 const defaultMaxSearchResults = 30
 
+// countAll is the value of the count: field that removes the limit on the
+// number of results ("count:all").
+const countAll = "all"
+
 func (r *searchResolver) maxResults() int32 {
 	if r.pagination != nil {
 		// Paginated search requests always consume an entire result set for a
@@ -213,6 +279,9 @@ func (r *searchResolver) maxResults() int32 {
 		return math.MaxInt32
 	}
 	count, _ := r.query.StringValues(query.FieldCount)
+	if r.exhaustive || (len(count) > 0 && strings.EqualFold(count[0], countAll)) {
+		return math.MaxInt32
+	}
 	if len(count) > 0 {
 		n, _ := strconv.Atoi(count[0])
 		if n > 0 {
@@ -322,6 +391,13 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 	if effectiveRepoFieldValues != nil {
 		repoFilters = effectiveRepoFieldValues
 	}
+
+	exclusions, err := r.searchExclusions(ctx)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	minusRepoFilters = append(minusRepoFilters, exclusions.repos...)
+
 	repoGroupFilters, _ := r.query.StringValues(query.FieldRepoGroup)
 
 	forkStr, _ := r.query.StringValue(query.FieldFork)
@@ -330,8 +406,17 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 	archivedStr, _ := r.query.StringValue(query.FieldArchived)
 	archived := parseYesNoOnly(archivedStr)
 
+	// Archived mirrors are excluded unless explicitly asked for.
+	archivedMirrorStr, _ := r.query.StringValue(query.FieldArchivedMirror)
+	archivedMirror := parseYesNoOnly(archivedMirrorStr)
+
 	commitAfter, _ := r.query.StringValue(query.FieldRepoHasCommitAfter)
 
+	maxRepos, err := r.maxRepos()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
 	tr.LazyPrintf("resolveRepositories - start")
 	repoRevs, missingRepoRevs, overLimit, err = resolveRepositories(ctx, resolveRepoOp{
 		repoFilters:      repoFilters,
@@ -342,6 +427,10 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 		onlyArchived:     archived == Only || archived == True,
 		noArchived:       archived == No || archived == False,
 		commitAfter:      commitAfter,
+		maxRepos:         maxRepos,
+
+		onlyArchivedMirrors: archivedMirror == Only,
+		noArchivedMirrors:   archivedMirror != Only && archivedMirror != Yes && archivedMirror != True,
 	})
 	tr.LazyPrintf("resolveRepositories - done")
 	if effectiveRepoFieldValues == nil {
@@ -353,6 +442,27 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 	return repoRevs, missingRepoRevs, overLimit, err
 }
 
+// maxRepos returns the maximum number of repositories to search given by the
+// maxRepos: field of the query, or 0 if there is none.
+func (r *searchResolver) maxRepos() (int, error) {
+	v, _ := r.query.StringValue(query.FieldMaxRepos)
+	if v == "" {
+		if r.exhaustive {
+			// Exhaustive searches search as many repositories as allowed.
+			return maxReposToSearchCeiling(), nil
+		}
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, &badRequestError{errors.Errorf(`invalid "maxRepos:" value %q (example: "maxRepos:1000")`, v)}
+	}
+	if ceiling := maxReposToSearchCeiling(); n > ceiling {
+		n = ceiling
+	}
+	return n, nil
+}
+
 // a patternRevspec maps an include pattern to a list of revisions
 // for repos matching that pattern. "map" in this case does not mean
 // an actual map, because we want regexp matches, not identity matches.
@@ -455,6 +565,14 @@ type resolveRepoOp struct {
 	noArchived       bool
 	onlyArchived     bool
 	commitAfter      string
+
+	// maxRepos, if non-zero, is the maximum number of repositories to
+	// resolve. Unlike with the default limit, the first maxRepos repositories
+	// are returned when more of them match.
+	maxRepos int
+
+	noArchivedMirrors   bool
+	onlyArchivedMirrors bool
 }
 
 func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions, missingRepoRevisions []*search.RepositoryRevisions, overLimit bool, err error) {
@@ -472,7 +590,15 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 
 	excludePatterns := op.minusRepoFilters
 
+	// Repositories can also be matched by their key-value pairs, with
+	// repo:has.meta(key:value).
+	includePatterns, kvpFilters := extractRepoKVPFilters(includePatterns)
+	excludePatterns, excludeKVPFilters := extractRepoKVPFilters(excludePatterns)
+
 	maxRepoListSize := maxReposToSearch()
+	if op.maxRepos > 0 {
+		maxRepoListSize = op.maxRepos
+	}
 
 	// If any repo groups are specified, take the intersection of the repo
 	// groups and the set of repos specified with repo:. (If none are specified
@@ -490,8 +616,9 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 		}
 		includePatterns = append(includePatterns, unionRegExps(patterns))
 
-		// Ensure we don't omit any repos explicitly included via a repo group.
-		if len(patterns) > maxRepoListSize {
+		// Ensure we don't omit any repos explicitly included via a repo group,
+		// unless the query explicitly limits the number of repos.
+		if len(patterns) > maxRepoListSize && op.maxRepos == 0 {
 			maxRepoListSize = len(patterns)
 		}
 	}
@@ -504,7 +631,7 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 	}
 
 	var defaultRepos []*types.Repo
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 {
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && len(kvpFilters) == 0 {
 		getIndexedRepos := func(ctx context.Context, revs []*search.RepositoryRevisions) (indexed, unindexed []*search.RepositoryRevisions, err error) {
 			return zoektIndexedRepos(ctx, search.Indexed(), revs, nil)
 		}
@@ -533,15 +660,31 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 			OnlyForks:    op.onlyForks,
 			NoArchived:   op.noArchived,
 			OnlyArchived: op.onlyArchived,
+
+			NoArchivedMirrors:   op.noArchivedMirrors,
+			OnlyArchivedMirrors: op.onlyArchivedMirrors,
+
+			KVPFilters:        kvpFilters,
+			ExcludeKVPFilters: excludeKVPFilters,
 		})
 		tr.LazyPrintf("Repos.List - done")
 		if err != nil {
 			return nil, nil, false, err
 		}
 	}
-	overLimit = len(repos) >= maxRepoListSize
+	if op.maxRepos > 0 {
+		overLimit = len(repos) > maxRepoListSize
+		if overLimit {
+			repos = repos[:maxRepoListSize]
+		}
+	} else {
+		overLimit = len(repos) >= maxRepoListSize
+	}
 
 	repoRevisions = make([]*search.RepositoryRevisions, 0, len(repos))
+	// pinnedCommits maps the commit IDs that revisions are pinned to (as in
+	// "repo:foo@a1b2c3d") to the repositories that contain them.
+	pinnedCommits := map[string][]api.RepoName{}
 	tr.LazyPrintf("Associate/validate revs - start")
 	for _, repo := range repos {
 		revs, clashingRevs := getRevsForMatchedRepo(repo.Name, includePatternRevs)
@@ -577,7 +720,37 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 				// searches like "repo:@foobar" (where foobar is an invalid revspec on most repos)
 				// taking a long time because they all ask gitserver to try to fetch from the remote
 				// repo.
-				if _, err := git.ResolveRevision(ctx, repoRev.GitserverRepo(), nil, rev.RevSpec, &git.ResolveRevisionOptions{NoEnsureRevision: true}); gitserver.IsRevisionNotFound(err) || err == context.DeadlineExceeded {
+				//
+				// A revision range doesn't resolve to a single commit, so both of its ends are
+				// validated instead.
+				revSpecs := []string{rev.RevSpec}
+				if base, head, ok := rev.Range(); ok {
+					revSpecs = []string{base, head}
+				}
+				missing := false
+				var (
+					commitID   api.CommitID
+					resolveErr error
+				)
+				for _, revSpec := range revSpecs {
+					commitID, resolveErr = git.ResolveRevision(ctx, repoRev.GitserverRepo(), nil, revSpec, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+					if gitserver.IsRevisionNotFound(resolveErr) || resolveErr == context.DeadlineExceeded {
+						missing = true
+						break
+					}
+				}
+				if rev.IsCommitID() {
+					if _, seen := pinnedCommits[rev.RevSpec]; !seen {
+						pinnedCommits[rev.RevSpec] = nil
+					}
+					if !missing && resolveErr == nil {
+						pinnedCommits[rev.RevSpec] = append(pinnedCommits[rev.RevSpec], repo.Name)
+						// Search at the full commit ID, so that the results (and
+						// their URLs) refer to exactly the pinned commit.
+						rev.RevSpec = string(commitID)
+					}
+				}
+				if missing {
 					// The revspec does not exist, so don't include it, and report that it's missing.
 					if rev.RevSpec == "" {
 						// Report as HEAD not "" (empty string) to avoid user confusion.
@@ -601,6 +774,12 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 
 	tr.LazyPrintf("Associate/validate revs - done")
 
+	for commit, commitRepos := range pinnedCommits {
+		if len(commitRepos) == 0 {
+			return nil, nil, false, &missingCommitError{commit: commit}
+		}
+	}
+
 	if op.commitAfter != "" {
 		repoRevisions, err = filterRepoHasCommitAfter(ctx, repoRevisions, op.commitAfter)
 	}
@@ -608,6 +787,21 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 	return repoRevisions, missingRepoRevisions, overLimit, err
 }
 
+// missingCommitError occurs when a query pins its repositories to a commit
+// (as in "repo:foo@a1b2c3d") that none of the matched repositories contain.
+// Unlike other missing revisions, which are reported in an alert, this is an
+// error: a search pinned to a commit is meant to be reproducible, and
+// silently searching nothing is not.
+type missingCommitError struct {
+	commit string
+}
+
+func (e *missingCommitError) Error() string {
+	return fmt.Sprintf("commit %q was not found in any repository matching the query", e.commit)
+}
+
+func (e *missingCommitError) BadRequest() bool { return true }
+
 type indexedReposFunc func(ctx context.Context, revs []*search.RepositoryRevisions) (indexed, unindexed []*search.RepositoryRevisions, err error)
 type defaultReposFunc func(ctx context.Context) ([]*types.Repo, error)
 
@@ -658,11 +852,17 @@ func defaultRepositories(ctx context.Context, getRawDefaultRepos defaultReposFun
 }
 
 func filterRepoHasCommitAfter(ctx context.Context, revisions []*search.RepositoryRevisions, after string) ([]*search.RepositoryRevisions, error) {
+	// Only run git for the repositories that can't be filtered by the
+	// recorded date of their last commit.
+	pass, revisions, err := filterRepoHasCommitAfterByLastCommitAt(ctx, revisions, after, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		mut  sync.Mutex
-		pass = []*search.RepositoryRevisions{}
-		res  = make(chan *search.RepositoryRevisions, 100)
-		run  = parallel.NewRun(128)
+		mut sync.Mutex
+		res = make(chan *search.RepositoryRevisions, 100)
+		run = parallel.NewRun(128)
 	)
 
 	goroutine.Go(func() {
@@ -704,7 +904,7 @@ func filterRepoHasCommitAfter(ctx context.Context, revisions []*search.Repositor
 		})
 	}
 
-	err := run.Wait()
+	err = run.Wait()
 	close(res)
 
 	return pass, err
@@ -732,7 +932,7 @@ func (r *searchResolver) suggestFilePaths(ctx context.Context, limit int) ([]*se
 		return nil, nil
 	}
 
-	p, err := r.getPatternInfo(&getPatternInfoOptions{forceFileSearch: true})
+	p, err := r.getPatternInfo(ctx, &getPatternInfoOptions{forceFileSearch: true})
 	if err != nil {
 		return nil, err
 	}
@@ -741,6 +941,7 @@ func (r *searchResolver) suggestFilePaths(ctx context.Context, limit int) ([]*se
 		Repos:           repos,
 		Query:           r.query,
 		UseFullDeadline: r.searchTimeoutFieldSet(),
+		Debug:           r.debug,
 		Zoekt:           r.zoekt,
 		SearcherURLs:    r.searcherURLs,
 	}
@@ -886,7 +1087,11 @@ func sortSearchSuggestions(s []*searchSuggestionResolver) {
 
 // langIncludeExcludePatterns returns regexps for the include/exclude path patterns given the lang:
 // and -lang: filter values in a search query. For example, a query containing "lang:go" should
-// include files whose paths match /\.go$/.
+// include files whose paths match /\.go$/, and "lang:makefile" files whose paths match
+// /\.mk$/ or /(^|\/)Makefile$/.
+//
+// The patterns only take file names into account. See filterFileMatchesByLanguage for the
+// languages of files with ambiguous extensions.
 func langIncludeExcludePatterns(values, negatedValues []string) (includePatterns, excludePatterns []string, err error) {
 	do := func(values []string, patterns *[]string) error {
 		for _, value := range values {
@@ -895,12 +1100,17 @@ func langIncludeExcludePatterns(values, negatedValues []string) (includePatterns
 				return fmt.Errorf("unknown language: %q", value)
 			}
 			exts := enry.GetLanguageExtensions(lang)
-			extPatterns := make([]string, len(exts))
-			for i, ext := range exts {
+			filenames := inventory.GetLanguageFilenames(lang)
+			pathPatterns := make([]string, 0, len(exts)+len(filenames))
+			for _, ext := range exts {
 				// Add `\.ext$` pattern to match files with the given extension.
-				extPatterns[i] = regexp.QuoteMeta(ext) + "$"
+				pathPatterns = append(pathPatterns, regexp.QuoteMeta(ext)+"$")
+			}
+			for _, filename := range filenames {
+				// Add `(^|/)filename$` pattern to match files with the given name.
+				pathPatterns = append(pathPatterns, "(^|/)"+regexp.QuoteMeta(filename)+"$")
 			}
-			*patterns = append(*patterns, unionRegExps(extPatterns))
+			*patterns = append(*patterns, unionRegExps(pathPatterns))
 		}
 		return nil
 	}
@@ -914,6 +1124,55 @@ func langIncludeExcludePatterns(values, negatedValues []string) (includePatterns
 	return includePatterns, excludePatterns, nil
 }
 
+// filterFileMatchesByLanguage removes the file matches from results whose language, as detected
+// from their path and the contents of their matched lines, contradicts the lang: and -lang: filter
+// values in a search query. For example, it removes C++ headers from the results of a query
+// containing "lang:c", which the path patterns of langIncludeExcludePatterns can't tell apart
+// from C headers. Files whose language can't be detected conclusively are kept.
+func filterFileMatchesByLanguage(results []searchResultResolver, values, negatedValues []string) []searchResultResolver {
+	if len(values) == 0 && len(negatedValues) == 0 {
+		return results
+	}
+
+	languages := func(values []string) []string {
+		langs := make([]string, 0, len(values))
+		for _, value := range values {
+			if lang, ok := enry.GetLanguageByAlias(value); ok {
+				langs = append(langs, lang)
+			}
+		}
+		return langs
+	}
+	include, exclude := languages(values), languages(negatedValues)
+
+	matches := func(fm *fileMatchResolver) bool {
+		lang, safe := fm.language()
+		if !safe {
+			return true
+		}
+		for _, l := range include {
+			if lang != l {
+				return false
+			}
+		}
+		for _, l := range exclude {
+			if lang == l {
+				return false
+			}
+		}
+		return true
+	}
+
+	filtered := results[:0]
+	for _, result := range results {
+		if fm, ok := result.ToFileMatch(); ok && !matches(fm) {
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
 // handleRepoSearchResult handles the limitHit and searchErr returned by a search function,
 // updating common as to reflect that new information. If searchErr is a fatal error,
 // it returns a non-nil error; otherwise, if searchErr == nil or a non-fatal error, it returns a
@@ -923,6 +1182,9 @@ func handleRepoSearchResult(common *searchResultsCommon, repoRev *search.Reposit
 	if vcs.IsRepoNotExist(searchErr) {
 		if vcs.IsCloneInProgress(searchErr) {
 			common.cloning = append(common.cloning, repoRev.Repo)
+		} else if cloneOnDemand.enqueue(repoRev.Repo.Name) {
+			// The repo exists in the DB but is not cloned yet.
+			common.cloning = append(common.cloning, repoRev.Repo)
 		} else {
 			common.missing = append(common.missing, repoRev.Repo)
 		}
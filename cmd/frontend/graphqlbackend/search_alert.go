@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/syntax"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 )
 
 type searchAlert struct {
@@ -210,6 +211,9 @@ func (r *searchResolver) alertForNoResolvedRepos(ctx context.Context) (*searchAl
 			} else {
 				a.title = "No repositories satisfied your repo: filter"
 				a.description = "Change your repo: filter to see results"
+				if proposeQueries {
+					a.proposedQueries = append(a.proposedQueries, proposedQueriesForSimilarRepos(ctx, withoutRepoFields, repoFilters[0])...)
+				}
 			}
 			if proposeQueries && strings.TrimSpace(withoutRepoFields) != "" {
 				a.proposedQueries = append(a.proposedQueries, &searchQueryDescription{
@@ -223,6 +227,57 @@ func (r *searchResolver) alertForNoResolvedRepos(ctx context.Context) (*searchAl
 	return &a, nil
 }
 
+// maxSimilarReposToPropose is the maximum number of repositories with names
+// similar to a repo: filter that matched nothing to propose instead.
+const maxSimilarReposToPropose = 3
+
+// proposedQueriesForSimilarRepos proposes replacing a repo: filter that
+// matched no repositories with the names of the repositories most similar to
+// it, because typos in long repository names are a common cause of empty
+// results. Only filters that match a name literally (apart from ^ and $
+// anchors) are considered.
+func proposedQueriesForSimilarRepos(ctx context.Context, withoutRepoFields, repoFilter string) []*searchQueryDescription {
+	repoPattern, _ := search.ParseRepositoryRevisions(repoFilter)
+	name := literalRepoPattern(string(repoPattern))
+	if name == "" {
+		return nil
+	}
+
+	repos, err := db.Repos.ListSimilar(ctx, name, maxSimilarReposToPropose)
+	if err != nil {
+		// The suggestions are best effort, so don't fail the alert.
+		return nil
+	}
+
+	// Keep the revisions of the repo: filter, if any.
+	var revs string
+	if i := strings.Index(repoFilter, "@"); i != -1 {
+		revs = repoFilter[i:]
+	}
+
+	proposed := make([]*searchQueryDescription, 0, len(repos))
+	for _, repo := range repos {
+		proposed = append(proposed, &searchQueryDescription{
+			description: "did you mean " + strings.TrimPrefix(string(repo.Name), "github.com/") + "?",
+			query:       strings.TrimSpace(withoutRepoFields + " repo:^" + regexp.QuoteMeta(string(repo.Name)) + "$" + revs),
+		})
+	}
+	return proposed
+}
+
+// literalRepoPattern returns the string that the repo: filter pattern matches
+// literally, ignoring ^ and $ anchors, or "" if the pattern isn't a literal.
+func literalRepoPattern(pattern string) string {
+	re, err := regexp.Compile(strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$"))
+	if err != nil {
+		return ""
+	}
+	if literal, complete := re.LiteralPrefix(); complete {
+		return literal
+	}
+	return ""
+}
+
 func (r *searchResolver) alertForOverRepoLimit(ctx context.Context) (*searchAlert, error) {
 	alert := &searchAlert{
 		title: "Too many matching repositories",
@@ -238,6 +293,13 @@ func (r *searchResolver) alertForOverRepoLimit(ctx context.Context) (*searchAler
 	if isSiteAdmin {
 		alert.description += " As a site admin, you can increase the limit by changing maxReposToSearch in site config."
 	}
+	if ceiling := maxReposToSearchCeiling(); ceiling > maxReposToSearch() {
+		if conf.Get().MaxReposToSearchCeiling < 0 {
+			alert.description += " To search more repositories anyway, use a 'maxRepos:' filter."
+		} else {
+			alert.description += fmt.Sprintf(" To search more repositories anyway, use a 'maxRepos:' filter (up to %d).", ceiling)
+		}
+	}
 
 	// Try to suggest the most helpful repo: filters to narrow the query.
 	//
@@ -1,11 +1,15 @@
 package graphqlbackend
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/syntax"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 )
 
 func TestAddQueryRegexpField(t *testing.T) {
@@ -95,3 +99,45 @@ func TestAddQueryRegexpField(t *testing.T) {
 		})
 	}
 }
+
+func TestProposedQueriesForSimilarRepos(t *testing.T) {
+	db.Mocks.Repos.ListSimilar = func(ctx context.Context, name string, limit int) ([]*types.Repo, error) {
+		if name != "github.com/sourcegraph/sourcegrahp" {
+			return nil, nil
+		}
+		return []*types.Repo{{Name: "github.com/sourcegraph/sourcegraph"}}, nil
+	}
+	defer func() { db.Mocks.Repos.ListSimilar = nil }()
+
+	tests := []struct {
+		repoFilter string
+		want       []string
+	}{
+		{
+			repoFilter: `^github\.com/sourcegraph/sourcegrahp$`,
+			want:       []string{`foo repo:^github\.com/sourcegraph/sourcegraph$`},
+		},
+		{
+			repoFilter: `github\.com/sourcegraph/sourcegrahp@3.9:master`,
+			want:       []string{`foo repo:^github\.com/sourcegraph/sourcegraph$@3.9:master`},
+		},
+		{
+			// Only literal patterns are considered.
+			repoFilter: `github\.com/sourcegraph/.*`,
+			want:       nil,
+		},
+		{
+			repoFilter: `nomatch`,
+			want:       nil,
+		},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, q := range proposedQueriesForSimilarRepos(context.Background(), "foo", test.repoFilter) {
+			got = append(got, q.query)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.repoFilter, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"golang.org/x/time/rate"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// cloneOnDemand enqueues high-priority updates (and thus clones) of repos
+// that a search resolved but that are not yet cloned, so that the first
+// search against a newly added code host warms its clones instead of
+// reporting all of its repos as missing.
+var cloneOnDemand = newCloneOnDemandQueue(rate.Limit(10), 50, 5*time.Minute)
+
+type cloneOnDemandQueue struct {
+	limiter *rate.Limiter
+	ttl     time.Duration // how long an enqueued repo is considered to be cloning
+
+	mu       sync.Mutex
+	enqueued map[api.RepoName]time.Time
+
+	// enqueueRepoUpdate is called in a new goroutine for each enqueued repo.
+	enqueueRepoUpdate func(ctx context.Context, repo gitserver.Repo) error
+	clock             func() time.Time
+}
+
+func newCloneOnDemandQueue(limit rate.Limit, burst int, ttl time.Duration) *cloneOnDemandQueue {
+	return &cloneOnDemandQueue{
+		limiter:  rate.NewLimiter(limit, burst),
+		ttl:      ttl,
+		enqueued: map[api.RepoName]time.Time{},
+		enqueueRepoUpdate: func(ctx context.Context, repo gitserver.Repo) error {
+			_, err := repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, repo)
+			return err
+		},
+		clock: time.Now,
+	}
+}
+
+// enqueue enqueues an update of the given repo with repo-updater, unless one
+// was already enqueued recently. It reports whether an update of the repo is
+// pending, i.e. whether the repo should be reported as cloning. It returns
+// false if the rate limit for clones on demand was exceeded.
+func (q *cloneOnDemandQueue) enqueue(name api.RepoName) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock()
+	if t, ok := q.enqueued[name]; ok && now.Sub(t) < q.ttl {
+		return true
+	}
+	if !q.limiter.AllowN(now, 1) {
+		return false
+	}
+
+	for n, t := range q.enqueued {
+		if now.Sub(t) >= q.ttl {
+			delete(q.enqueued, n)
+		}
+	}
+	q.enqueued[name] = now
+
+	enqueueRepoUpdate := q.enqueueRepoUpdate
+	goroutine.Go(func() {
+		// The search request may be done long before the update is
+		// enqueued, so don't use its context.
+		if err := enqueueRepoUpdate(context.Background(), gitserver.Repo{Name: name}); err != nil {
+			log15.Warn("Failed to enqueue clone of repo resolved by search.", "repo", name, "error", err)
+		}
+	})
+	return true
+}
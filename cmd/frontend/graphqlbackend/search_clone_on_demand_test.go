@@ -0,0 +1,62 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"golang.org/x/time/rate"
+)
+
+func TestCloneOnDemandQueue(t *testing.T) {
+	now := time.Now()
+	q := newCloneOnDemandQueue(rate.Every(time.Minute), 2, 5*time.Minute)
+	q.clock = func() time.Time { return now }
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		enqueued []api.RepoName
+	)
+	q.enqueueRepoUpdate = func(ctx context.Context, repo gitserver.Repo) error {
+		mu.Lock()
+		enqueued = append(enqueued, repo.Name)
+		mu.Unlock()
+		wg.Done()
+		return nil
+	}
+
+	wg.Add(2)
+	for _, tc := range []struct {
+		repo api.RepoName
+		want bool
+	}{
+		{"a", true},
+		{"a", true}, // deduped
+		{"b", true},
+		{"c", false}, // rate limited
+	} {
+		if got := q.enqueue(tc.repo); got != tc.want {
+			t.Errorf("enqueue(%q): got %v, want %v", tc.repo, got, tc.want)
+		}
+	}
+	wg.Wait()
+
+	if len(enqueued) != 2 {
+		t.Fatalf("got %d enqueued updates, want 2: %v", len(enqueued), enqueued)
+	}
+
+	// Once the TTL passed, a repo that is still not cloned is enqueued again.
+	now = now.Add(5 * time.Minute)
+	wg.Add(1)
+	if !q.enqueue("a") {
+		t.Error("expected a to be enqueued again")
+	}
+	wg.Wait()
+	if len(enqueued) != 3 || enqueued[2] != "a" {
+		t.Errorf("unexpected enqueued updates: %v", enqueued)
+	}
+}
@@ -0,0 +1,98 @@
+package graphqlbackend
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// filterRepoHasCommitAfterByLastCommitAt evaluates the repohascommitafter:
+// filter for the repositories that are only searched at their default branch
+// by the date of the latest commit on it, which the repo-updater scheduler
+// records after fetches. It returns the repositories that pass the filter and
+// those that still need to be checked with git, because their date is not
+// recorded yet, other revisions are searched or after can't be parsed.
+func filterRepoHasCommitAfterByLastCommitAt(ctx context.Context, revisions []*search.RepositoryRevisions, after string, now time.Time) (pass, unknown []*search.RepositoryRevisions, err error) {
+	afterTime, ok := parseCommitAfter(after, now)
+	if !ok {
+		return []*search.RepositoryRevisions{}, revisions, nil
+	}
+
+	ids := make([]api.RepoID, 0, len(revisions))
+	for _, revs := range revisions {
+		if onlyDefaultBranch(revs.Revs) {
+			ids = append(ids, revs.Repo.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return []*search.RepositoryRevisions{}, revisions, nil
+	}
+
+	lastCommitAt, err := db.Repos.ListLastCommitAt(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pass = []*search.RepositoryRevisions{}
+	for _, revs := range revisions {
+		t, ok := lastCommitAt[revs.Repo.ID]
+		if !ok || !onlyDefaultBranch(revs.Revs) {
+			unknown = append(unknown, revs)
+			continue
+		}
+		if t.After(afterTime) {
+			pass = append(pass, revs)
+		}
+	}
+	return pass, unknown, nil
+}
+
+func onlyDefaultBranch(revs []search.RevisionSpecifier) bool {
+	for _, rev := range revs {
+		if (rev.RevSpec != "" && rev.RevSpec != "HEAD") || rev.RefGlob != "" || rev.ExcludeRefGlob != "" {
+			return false
+		}
+	}
+	return len(revs) > 0
+}
+
+var relativeDatePattern = regexp.MustCompile(`^(\d+)[. ]+(second|minute|hour|day|week|month|year)s?[. ]+ago$`)
+
+// parseCommitAfter parses the value of a repohascommitafter: filter relative
+// to now. It only supports the formats that git interprets unambiguously,
+// i.e. relative dates like "2 weeks ago" and RFC 3339 timestamps.
+func parseCommitAfter(after string, now time.Time) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, after); err == nil {
+		return t, true
+	}
+
+	m := relativeDatePattern.FindStringSubmatch(after)
+	if m == nil {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch m[2] {
+	case "second":
+		return now.Add(-time.Duration(n) * time.Second), true
+	case "minute":
+		return now.Add(-time.Duration(n) * time.Minute), true
+	case "hour":
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case "day":
+		return now.AddDate(0, 0, -n), true
+	case "week":
+		return now.AddDate(0, 0, -7*n), true
+	case "month":
+		return now.AddDate(0, -n, 0), true
+	default: // "year"
+		return now.AddDate(-n, 0, 0), true
+	}
+}
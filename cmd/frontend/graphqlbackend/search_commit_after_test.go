@@ -0,0 +1,66 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseCommitAfter(t *testing.T) {
+	now := time.Date(2019, 12, 15, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		want time.Time
+		ok   bool
+	}{
+		"2 weeks ago":          {want: time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC), ok: true},
+		"1.day.ago":            {want: time.Date(2019, 12, 14, 12, 0, 0, 0, time.UTC), ok: true},
+		"3 hours ago":          {want: time.Date(2019, 12, 15, 9, 0, 0, 0, time.UTC), ok: true},
+		"1 month ago":          {want: time.Date(2019, 11, 15, 12, 0, 0, 0, time.UTC), ok: true},
+		"2019-10-01T00:00:00Z": {want: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		"last tuesday":         {},
+		"2 fortnights ago":     {},
+	}
+	for after, test := range tests {
+		got, ok := parseCommitAfter(after, now)
+		if ok != test.ok || !got.Equal(test.want) {
+			t.Errorf("%q: got (%s, %t), want (%s, %t)", after, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestFilterRepoHasCommitAfterByLastCommitAt(t *testing.T) {
+	resetMocks()
+	now := time.Date(2019, 12, 15, 12, 0, 0, 0, time.UTC)
+	db.Mocks.Repos.ListLastCommitAt = func(ctx context.Context, ids []api.RepoID) (map[api.RepoID]time.Time, error) {
+		if want := []api.RepoID{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got IDs %v, want %v", ids, want)
+		}
+		return map[api.RepoID]time.Time{
+			1: now.AddDate(0, 0, -1),
+			2: now.AddDate(0, 0, -30),
+			4: now.AddDate(0, 0, -1),
+		}, nil
+	}
+
+	active := &search.RepositoryRevisions{Repo: &types.Repo{ID: 1}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}}
+	stale := &search.RepositoryRevisions{Repo: &types.Repo{ID: 2}, Revs: []search.RevisionSpecifier{{RevSpec: "HEAD"}}}
+	unrecorded := &search.RepositoryRevisions{Repo: &types.Repo{ID: 3}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}}
+	otherBranch := &search.RepositoryRevisions{Repo: &types.Repo{ID: 4}, Revs: []search.RevisionSpecifier{{RevSpec: "dev"}}}
+
+	pass, unknown, err := filterRepoHasCommitAfterByLastCommitAt(context.Background(), []*search.RepositoryRevisions{active, stale, unrecorded, otherBranch}, "1 week ago", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*search.RepositoryRevisions{active}; !reflect.DeepEqual(pass, want) {
+		t.Errorf("got pass %v, want %v", pass, want)
+	}
+	if want := []*search.RepositoryRevisions{unrecorded, otherBranch}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("got unknown %v, want %v", unknown, want)
+	}
+}
@@ -0,0 +1,133 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/neelance/parallel"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// lastCommitCache caches the last commit that modified a file as of a commit.
+// Its keys include the commit ID, so the cached values never go stale.
+var lastCommitCache = rcache.NewWithTTL("search_last_commit", 24*3600)
+
+const (
+	// maxCommitInfoFileMatches is the maximum number of file matches of a
+	// search whose last commits are fetched.
+	maxCommitInfoFileMatches = 500
+
+	// commitInfoTimeout is how long fetching the last commits of the file
+	// matches may delay the results of a search. File matches whose last
+	// commits weren't fetched in time have none.
+	commitInfoTimeout = 5 * time.Second
+)
+
+// addFileMatchLastCommits sets the last commits of the files of the file
+// matches in results (see fileMatchResolver.LastCommit). They are fetched
+// with one git command per searched repository revision, instead of one per
+// file, and cached.
+func addFileMatchLastCommits(ctx context.Context, results []searchResultResolver) {
+	ctx, cancel := context.WithTimeout(ctx, commitInfoTimeout)
+	defer cancel()
+
+	type repoCommit struct {
+		repo   api.RepoName
+		commit api.CommitID
+	}
+
+	var (
+		fms  []*fileMatchResolver
+		keys []string
+	)
+	for _, r := range results {
+		fm, ok := r.ToFileMatch()
+		if !ok || !git.IsAbsoluteRevision(string(fm.commitID)) {
+			continue
+		}
+		fms = append(fms, fm)
+		keys = append(keys, lastCommitCacheKey(fm))
+		if len(fms) == maxCommitInfoFileMatches {
+			break
+		}
+	}
+
+	// Fetch the file matches that aren't cached, grouped by the repository
+	// revision they were found in.
+	var (
+		misses = map[repoCommit][]*fileMatchResolver{}
+		repos  = map[repoCommit]*types.Repo{}
+	)
+	cached := lastCommitCache.GetMulti(keys...)
+	for i, fm := range fms {
+		if i < len(cached) && cached[i] != nil {
+			var c git.Commit
+			if err := json.Unmarshal(cached[i], &c); err == nil {
+				fm.lastCommit = toGitCommitResolver(&RepositoryResolver{repo: fm.repo}, &c)
+				continue
+			}
+		}
+		rc := repoCommit{repo: fm.repo.Name, commit: fm.commitID}
+		misses[rc] = append(misses[rc], fm)
+		repos[rc] = fm.repo
+	}
+
+	var (
+		run         = parallel.NewRun(8) // number of concurrent git commands
+		cacheValsMu sync.Mutex
+		cacheVals   [][2]string
+	)
+	for rc, fms := range misses {
+		rc, fms := rc, fms // shadow so they don't change in the goroutine
+		run.Acquire()
+		goroutine.Go(func() {
+			defer run.Release()
+
+			cachedRepo, err := backend.CachedGitRepo(ctx, repos[rc])
+			if err != nil {
+				log15.Warn("Failed to get last commits of search results", "repo", rc.repo, "err", err)
+				return
+			}
+
+			paths := make([]string, len(fms))
+			for i, fm := range fms {
+				paths[i] = fm.JPath
+			}
+			commits, err := git.LastCommits(ctx, *cachedRepo, rc.commit, paths)
+			if err != nil {
+				log15.Warn("Failed to get last commits of search results", "repo", rc.repo, "commitID", rc.commit, "err", err)
+				return
+			}
+
+			for _, fm := range fms {
+				c, ok := commits[fm.JPath]
+				if !ok {
+					continue
+				}
+				fm.lastCommit = toGitCommitResolver(&RepositoryResolver{repo: fm.repo}, c)
+				if b, err := json.Marshal(c); err == nil {
+					cacheValsMu.Lock()
+					cacheVals = append(cacheVals, [2]string{lastCommitCacheKey(fm), string(b)})
+					cacheValsMu.Unlock()
+				}
+			}
+		})
+	}
+	_ = run.Wait()
+
+	if len(cacheVals) > 0 {
+		lastCommitCache.SetMulti(cacheVals...)
+	}
+}
+
+func lastCommitCacheKey(fm *fileMatchResolver) string {
+	return string(fm.repo.Name) + "@" + string(fm.commitID) + ":" + fm.JPath
+}
@@ -0,0 +1,84 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestAddFileMatchLastCommits(t *testing.T) {
+	const (
+		commitA = api.CommitID("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		commitB = api.CommitID("bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	)
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	git.Mocks.LastCommits = func(commit api.CommitID, paths []string) (map[string]*git.Commit, error) {
+		mu.Lock()
+		sort.Strings(paths)
+		calls = append(calls, string(commit)[:1]+":"+strings.Join(paths, ","))
+		mu.Unlock()
+
+		commits := map[string]*git.Commit{}
+		for _, p := range paths {
+			if p != "deleted.go" {
+				commits[p] = &git.Commit{ID: api.CommitID("c-" + p), Author: git.Signature{Name: "alice"}}
+			}
+		}
+		return commits, nil
+	}
+	defer git.ResetMocks()
+
+	fileMatch := func(repo string, commit api.CommitID, path string) *fileMatchResolver {
+		return &fileMatchResolver{
+			JPath:    path,
+			repo:     &types.Repo{Name: api.RepoName(repo)},
+			commitID: commit,
+		}
+	}
+
+	var (
+		a1 = fileMatch("a", commitA, "main.go")
+		a2 = fileMatch("a", commitA, "deleted.go")
+		b1 = fileMatch("b", commitB, "main.go")
+		// Unresolved revisions are skipped.
+		c1 = fileMatch("c", "HEAD", "main.go")
+	)
+
+	addFileMatchLastCommits(context.Background(), []searchResultResolver{a1, &RepositoryResolver{}, a2, b1, c1})
+
+	sort.Strings(calls)
+	if want := []string{"a:deleted.go,main.go", "b:main.go"}; strings.Join(calls, " ") != strings.Join(want, " ") {
+		t.Errorf("got git.LastCommits calls %q, want %q", calls, want)
+	}
+
+	for _, tc := range []struct {
+		fm   *fileMatchResolver
+		want string
+	}{
+		{a1, "c-main.go"},
+		{a2, ""},
+		{b1, "c-main.go"},
+		{c1, ""},
+	} {
+		var have string
+		if c := tc.fm.LastCommit(); c != nil {
+			have = string(c.OID())
+			if name := c.Author().Person().name; name != "alice" {
+				t.Errorf("%s: got author %q, want alice", tc.fm.JPath, name)
+			}
+		}
+		if have != tc.want {
+			t.Errorf("%s@%s: got last commit %q, want %q", tc.fm.repo.Name, tc.fm.JPath, have, tc.want)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -89,18 +90,7 @@ func searchCommitDiffsInRepo(ctx context.Context, repoRevs *search.RepositoryRev
 		return mockSearchCommitDiffsInRepo(ctx, repoRevs, info, query)
 	}
 
-	textSearchOptions := git.TextSearchOptions{
-		Pattern:         info.Pattern,
-		IsRegExp:        info.IsRegExp,
-		IsCaseSensitive: info.IsCaseSensitive,
-	}
-	return searchCommitsInRepo(ctx, commitSearchOp{
-		repoRevs:          repoRevs,
-		info:              info,
-		query:             query,
-		diff:              true,
-		textSearchOptions: textSearchOptions,
-	})
+	return searchCommitsInRepo(ctx, newCommitSearchOp(repoRevs, info, query, true))
 }
 
 var mockSearchCommitLogInRepo func(ctx context.Context, repoRevs *search.RepositoryRevisions, info *search.PatternInfo, query *query.Query) (results []*commitSearchResultResolver, limitHit, timedOut bool, err error)
@@ -110,18 +100,7 @@ func searchCommitLogInRepo(ctx context.Context, repoRevs *search.RepositoryRevis
 		return mockSearchCommitLogInRepo(ctx, repoRevs, info, query)
 	}
 
-	var terms []string
-	if info.Pattern != "" {
-		terms = append(terms, info.Pattern)
-	}
-	return searchCommitsInRepo(ctx, commitSearchOp{
-		repoRevs:           repoRevs,
-		info:               info,
-		query:              query,
-		diff:               false,
-		textSearchOptions:  git.TextSearchOptions{},
-		extraMessageValues: terms,
-	})
+	return searchCommitsInRepo(ctx, newCommitSearchOp(repoRevs, info, query, false))
 }
 
 type commitSearchOp struct {
@@ -133,6 +112,27 @@ type commitSearchOp struct {
 	extraMessageValues []string
 }
 
+// newCommitSearchOp returns the search of the commit diffs (if diff is true) or of the commit
+// log of the repository.
+func newCommitSearchOp(repoRevs *search.RepositoryRevisions, info *search.PatternInfo, query *query.Query, diff bool) commitSearchOp {
+	op := commitSearchOp{
+		repoRevs: repoRevs,
+		info:     info,
+		query:    query,
+		diff:     diff,
+	}
+	if diff {
+		op.textSearchOptions = git.TextSearchOptions{
+			Pattern:         info.Pattern,
+			IsRegExp:        info.IsRegExp,
+			IsCaseSensitive: info.IsCaseSensitive,
+		}
+	} else if info.Pattern != "" {
+		op.extraMessageValues = []string{info.Pattern}
+	}
+	return op
+}
+
 func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*commitSearchResultResolver, limitHit, timedOut bool, err error) {
 	tr, ctx := trace.New(ctx, "searchCommitsInRepo", fmt.Sprintf("repoRevs: %v, pattern %+v", op.repoRevs, op.info))
 	defer func() {
@@ -141,7 +141,19 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 		tr.Finish()
 	}()
 
-	repo := op.repoRevs.Repo
+	opt, err := op.rawLogDiffSearchOptions(ctx)
+	if err != nil {
+		return nil, false, false, err
+	}
+	rawResults, complete, err := git.RawLogDiffSearch(ctx, op.repoRevs.GitserverRepo(), opt)
+	if err != nil {
+		return nil, false, false, err
+	}
+	return op.results(rawResults, complete)
+}
+
+// rawLogDiffSearchOptions returns the options of the git.RawLogDiffSearch for the commit search.
+func (op commitSearchOp) rawLogDiffSearchOptions(ctx context.Context) (git.RawLogDiffSearchOptions, error) {
 	maxResults := int(op.info.FileMatchLimit)
 
 	args := []string{
@@ -169,7 +181,7 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 				// against a whitelist, but it could cause unexpected errors by (e.g.)
 				// changing the format of `git log` to a format that our parser doesn't
 				// expect.
-				return nil, false, false, fmt.Errorf("invalid revspec: %q", rev.RevSpec)
+				return git.RawLogDiffSearchOptions{}, fmt.Errorf("invalid revspec: %q", rev.RevSpec)
 			}
 			args = append(args, rev.RevSpec)
 
@@ -236,16 +248,16 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 		return nil
 	}
 	if err := addGrepLikeFlags(&args, "--grep", query.FieldMessage, op.extraMessageValues, false); err != nil {
-		return nil, false, false, err
+		return git.RawLogDiffSearchOptions{}, err
 	}
 	if err := addGrepLikeFlags(&args, "--author", query.FieldAuthor, nil, true); err != nil {
-		return nil, false, false, err
+		return git.RawLogDiffSearchOptions{}, err
 	}
 	if err := addGrepLikeFlags(&args, "--committer", query.FieldCommitter, nil, true); err != nil {
-		return nil, false, false, err
+		return git.RawLogDiffSearchOptions{}, err
 	}
 
-	rawResults, complete, err := git.RawLogDiffSearch(ctx, op.repoRevs.GitserverRepo(), git.RawLogDiffSearchOptions{
+	return git.RawLogDiffSearchOptions{
 		Query: op.textSearchOptions,
 		Paths: git.PathOptions{
 			IncludePatterns: op.info.IncludePatterns,
@@ -256,10 +268,12 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 		Diff:              op.diff,
 		OnlyMatchingHunks: true,
 		Args:              args,
-	})
-	if err != nil {
-		return nil, false, false, err
-	}
+	}, nil
+}
+
+// results returns the search results of the raw results of the commit search.
+func (op commitSearchOp) results(rawResults []*git.LogCommitSearchResult, complete bool) (results []*commitSearchResultResolver, limitHit, timedOut bool, err error) {
+	maxResults := int(op.info.FileMatchLimit)
 
 	// if the result is incomplete, git log timed out and the client should be notified of that
 	timedOut = !complete
@@ -268,7 +282,7 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 		rawResults = rawResults[:maxResults]
 	}
 
-	repoResolver := &RepositoryResolver{repo: repo}
+	repoResolver := &RepositoryResolver{repo: op.repoRevs.Repo}
 	results = make([]*commitSearchResultResolver, len(rawResults))
 	for i, rawResult := range rawResults {
 		commit := rawResult.Commit
@@ -445,58 +459,7 @@ func searchCommitDiffsInRepos(ctx context.Context, args *search.Args) ([]searchR
 	if mockSearchCommitDiffsInRepos != nil {
 		return mockSearchCommitDiffsInRepos(args)
 	}
-
-	var err error
-	tr, ctx := trace.New(ctx, "searchCommitDiffsInRepos", fmt.Sprintf("query: %+v, numRepoRevs: %d", args.Pattern, len(args.Repos)))
-	defer func() {
-		tr.SetError(err)
-		tr.Finish()
-	}()
-
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	var (
-		wg          sync.WaitGroup
-		mu          sync.Mutex
-		unflattened [][]*commitSearchResultResolver
-		common      = &searchResultsCommon{}
-	)
-	for _, repoRev := range args.Repos {
-		wg.Add(1)
-		go func(repoRev *search.RepositoryRevisions) {
-			defer wg.Done()
-			results, repoLimitHit, repoTimedOut, searchErr := searchCommitDiffsInRepo(ctx, repoRev, args.Pattern, args.Query)
-			if ctx.Err() == context.Canceled {
-				// Our request has been canceled (either because another one of args.repos had a
-				// fatal error, or otherwise), so we can just ignore these results.
-				return
-			}
-			repoTimedOut = repoTimedOut || ctx.Err() == context.DeadlineExceeded
-			if searchErr != nil {
-				tr.LogFields(otlog.String("repo", string(repoRev.Repo.Name)), otlog.String("searchErr", searchErr.Error()), otlog.Bool("timeout", errcode.IsTimeout(searchErr)), otlog.Bool("temporary", errcode.IsTemporary(searchErr)), otlog.Bool("timeout", errcode.IsTimeout(searchErr)), otlog.Bool("temporary", errcode.IsTemporary(searchErr)))
-			}
-			mu.Lock()
-			defer mu.Unlock()
-			if fatalErr := handleRepoSearchResult(common, repoRev, repoLimitHit, repoTimedOut, searchErr); fatalErr != nil {
-				err = errors.Wrapf(searchErr, "failed to search commit diffs %s", repoRev.String())
-				cancel()
-			}
-			if len(results) > 0 {
-				unflattened = append(unflattened, results)
-			}
-		}(repoRev)
-	}
-	wg.Wait()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var flattened []*commitSearchResultResolver
-	for _, results := range unflattened {
-		flattened = append(flattened, results...)
-	}
-	return commitSearchResultsToSearchResults(flattened), common, nil
+	return searchCommitsInRepos(ctx, args, true)
 }
 
 var mockSearchCommitLogInRepos func(args *search.Args) ([]searchResultResolver, *searchResultsCommon, error)
@@ -506,9 +469,20 @@ func searchCommitLogInRepos(ctx context.Context, args *search.Args) ([]searchRes
 	if mockSearchCommitLogInRepos != nil {
 		return mockSearchCommitLogInRepos(args)
 	}
+	return searchCommitsInRepos(ctx, args, false)
+}
+
+// searchCommitsInRepos searches a set of repos for matching commit diffs (if diff is true) or
+// commits. The git commands of the repos on the same gitserver are run in a single batch (see
+// git.RawLogDiffSearchInRepos).
+func searchCommitsInRepos(ctx context.Context, args *search.Args, diff bool) ([]searchResultResolver, *searchResultsCommon, error) {
+	name, kind := "searchCommitLogInRepos", "commit log"
+	if diff {
+		name, kind = "searchCommitDiffsInRepos", "commit diffs"
+	}
 
 	var err error
-	tr, ctx := trace.New(ctx, "searchCommitLogInRepos", fmt.Sprintf("query: %+v, numRepoRevs: %d", args.Pattern, len(args.Repos)))
+	tr, ctx := trace.New(ctx, name, fmt.Sprintf("query: %+v, numRepoRevs: %d", args.Pattern, len(args.Repos)))
 	defer func() {
 		tr.SetError(err)
 		tr.Finish()
@@ -518,37 +492,68 @@ func searchCommitLogInRepos(ctx context.Context, args *search.Args) ([]searchRes
 	defer cancel()
 
 	var (
-		wg          sync.WaitGroup
 		mu          sync.Mutex
 		unflattened [][]*commitSearchResultResolver
 		common      = &searchResultsCommon{}
 	)
-	for _, repoRev := range args.Repos {
-		wg.Add(1)
-		go func(repoRev *search.RepositoryRevisions) {
-			defer wg.Done()
-			results, repoLimitHit, repoTimedOut, searchErr := searchCommitLogInRepo(ctx, repoRev, args.Pattern, args.Query)
-			if ctx.Err() == context.Canceled {
-				// Our request has been canceled (either because another one of args.repos had a
-				// fatal error, or otherwise), so we can just ignore these results.
-				return
-			}
-			repoTimedOut = repoTimedOut || ctx.Err() == context.DeadlineExceeded
+	handleResult := func(repoRev *search.RepositoryRevisions, results []*commitSearchResultResolver, repoLimitHit, repoTimedOut bool, searchErr error) {
+		if ctx.Err() == context.Canceled {
+			// Our request has been canceled (either because another one of args.repos had a
+			// fatal error, or otherwise), so we can just ignore these results.
+			return
+		}
+		repoTimedOut = repoTimedOut || ctx.Err() == context.DeadlineExceeded
+		if searchErr != nil {
+			tr.LogFields(otlog.String("repo", string(repoRev.Repo.Name)), otlog.String("searchErr", searchErr.Error()), otlog.Bool("timeout", errcode.IsTimeout(searchErr)), otlog.Bool("temporary", errcode.IsTemporary(searchErr)))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if fatalErr := handleRepoSearchResult(common, repoRev, repoLimitHit, repoTimedOut, searchErr); fatalErr != nil {
+			err = errors.Wrapf(searchErr, "failed to search %s %s", kind, repoRev.String())
+			cancel()
+		}
+		if len(results) > 0 {
+			unflattened = append(unflattened, results)
+		}
+	}
+
+	if (diff && mockSearchCommitDiffsInRepo != nil) || (!diff && mockSearchCommitLogInRepo != nil) {
+		searchInRepo := searchCommitLogInRepo
+		if diff {
+			searchInRepo = searchCommitDiffsInRepo
+		}
+		for _, repoRev := range args.Repos {
+			results, repoLimitHit, repoTimedOut, searchErr := searchInRepo(ctx, repoRev, args.Pattern, args.Query)
+			handleResult(repoRev, results, repoLimitHit, repoTimedOut, searchErr)
+		}
+	} else {
+		var (
+			ops   []commitSearchOp
+			repos []gitserver.Repo
+			opts  []git.RawLogDiffSearchOptions
+		)
+		for _, repoRev := range args.Repos {
+			op := newCommitSearchOp(repoRev, args.Pattern, args.Query, diff)
+			opt, searchErr := op.rawLogDiffSearchOptions(ctx)
 			if searchErr != nil {
-				tr.LogFields(otlog.String("repo", string(repoRev.Repo.Name)), otlog.String("searchErr", searchErr.Error()), otlog.Bool("timeout", errcode.IsTimeout(searchErr)), otlog.Bool("temporary", errcode.IsTemporary(searchErr)))
+				handleResult(repoRev, nil, false, false, searchErr)
+				continue
 			}
-			mu.Lock()
-			defer mu.Unlock()
-			if fatalErr := handleRepoSearchResult(common, repoRev, repoLimitHit, repoTimedOut, searchErr); fatalErr != nil {
-				err = errors.Wrapf(searchErr, "failed to search commit log %s", repoRev.String())
-				cancel()
-			}
-			if len(results) > 0 {
-				unflattened = append(unflattened, results)
+			ops = append(ops, op)
+			repos = append(repos, repoRev.GitserverRepo())
+			opts = append(opts, opt)
+		}
+		git.RawLogDiffSearchInRepos(ctx, repos, opts, func(i int, rawResults []*git.LogCommitSearchResult, complete bool, searchErr error) {
+			var (
+				results                    []*commitSearchResultResolver
+				repoLimitHit, repoTimedOut bool
+			)
+			if searchErr == nil {
+				results, repoLimitHit, repoTimedOut, searchErr = ops[i].results(rawResults, complete)
 			}
-		}(repoRev)
+			handleResult(ops[i].repoRevs, results, repoLimitHit, repoTimedOut, searchErr)
+		})
 	}
-	wg.Wait()
 	if err != nil {
 		return nil, nil, err
 	}
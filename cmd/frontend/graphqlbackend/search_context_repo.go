@@ -0,0 +1,35 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// hasRepoScope reports whether q selects the repositories to search with
+// repo: or repogroup: filters.
+func hasRepoScope(q *query.Query) bool {
+	return len(q.Values(query.FieldRepo)) > 0 || len(q.Values(query.FieldRepoGroup)) > 0
+}
+
+// contextRepoFilter returns the repo: filter that scopes a search to the
+// context repository.
+func contextRepoFilter(repo api.RepoName) string {
+	return fmt.Sprintf(`repo:^%s$`, regexp.QuoteMeta(string(repo)))
+}
+
+// contextRepoSearchFilter returns the dynamic filter that describes the
+// implicit scope of a search to the context repository. It is listed first,
+// so that clients can offer to search all repositories instead.
+func contextRepoSearchFilter(repo api.RepoName, count int) *searchFilterResolver {
+	return &searchFilterResolver{
+		value: contextRepoFilter(repo),
+		label: string(repo),
+		count: int32(count),
+		kind:  "context",
+		score: math.MaxInt32,
+	}
+}
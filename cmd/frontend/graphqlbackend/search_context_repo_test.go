@@ -0,0 +1,73 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestSearch_contextRepo(t *testing.T) {
+	contextRepo := "github.com/foo/bar"
+
+	tests := []struct {
+		query           string
+		wantRepoFilters []string
+		wantContextRepo api.RepoName
+	}{
+		{query: "x", wantRepoFilters: []string{`^github\.com/foo/bar$`}, wantContextRepo: "github.com/foo/bar"},
+		{query: "repo:baz x", wantRepoFilters: []string{"baz"}},
+		{query: "-repo:baz x", wantRepoFilters: nil},
+		{query: "repogroup:g x", wantRepoFilters: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			sr, err := (&schemaResolver{}).Search(&searchArgs{Query: test.query, Version: "V2", ContextRepo: &contextRepo})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := sr.(*searchResolver)
+
+			repoFilters, _ := r.query.RegexpPatterns(query.FieldRepo)
+			if !reflect.DeepEqual(repoFilters, test.wantRepoFilters) {
+				t.Errorf("got repo filters %q, want %q", repoFilters, test.wantRepoFilters)
+			}
+			if r.contextRepo != test.wantContextRepo {
+				t.Errorf("got context repo %q, want %q", r.contextRepo, test.wantContextRepo)
+			}
+			if r.originalQuery != test.query {
+				t.Errorf("got original query %q, want %q", r.originalQuery, test.query)
+			}
+		})
+	}
+}
+
+func TestSearchResultsResolver_DynamicFilters_contextRepo(t *testing.T) {
+	mockResolveRepoGroups = func() (map[string][]*types.Repo, error) { return nil, nil }
+	defer func() { mockResolveRepoGroups = nil }()
+
+	repo := &types.Repo{Name: "github.com/foo/bar"}
+	sr := &searchResultsResolver{
+		results: []searchResultResolver{
+			&fileMatchResolver{JPath: "a.go", repo: repo, JLineMatches: []*lineMatch{{}, {}}},
+		},
+		contextRepo: repo.Name,
+	}
+
+	filters := sr.DynamicFilters(context.Background())
+	if len(filters) == 0 {
+		t.Fatal("got no dynamic filters")
+	}
+	f := filters[0]
+	if f.Kind() != "context" || f.Value() != `repo:^github\.com/foo/bar$` || f.Label() != "github.com/foo/bar" || f.Count() != 2 {
+		t.Errorf("got first filter %+v, want the context repo filter", f)
+	}
+	for _, f := range filters[1:] {
+		if f.Value() == filters[0].Value() {
+			t.Errorf("context repo filter is also listed as a %q filter", f.Kind())
+		}
+	}
+}
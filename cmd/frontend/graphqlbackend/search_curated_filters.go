@@ -0,0 +1,164 @@
+package graphqlbackend
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// curatedFilters returns the curated filters (see the "search.curatedFilters"
+// site configuration property) whose query fragments match some of the
+// results. groups are the repository groups that repogroup: fields refer to.
+func curatedFilters(curated []*schema.CuratedSearchFilter, results []searchResultResolver, groups map[string][]*types.Repo) []*searchFilterResolver {
+	var filters []*searchFilterResolver
+	for _, c := range curated {
+		m, err := newCuratedFilterMatcher(c.Value, groups)
+		if err != nil {
+			log15.Warn("DynamicFilters: invalid curated filter", "value", c.Value, "error", err)
+			continue
+		}
+
+		f := &searchFilterResolver{value: c.Value, label: c.Label, kind: "curated"}
+		for _, result := range results {
+			if fm, ok := result.ToFileMatch(); ok {
+				if m.match(fm.repo.Name, fm) {
+					f.count += int32(len(fm.LineMatches()))
+					f.limitHit = f.limitHit || fm.JLimitHit
+					f.score++
+				}
+			} else if r, ok := result.ToRepository(); ok {
+				if m.match(api.RepoName(r.Name()), nil) {
+					f.count++
+					f.score++
+				}
+			}
+		}
+		if f.score > 0 {
+			filters = append(filters, f)
+		}
+	}
+	return filters
+}
+
+// curatedFilterMatcher reports whether search results match the query
+// fragment of a curated filter. Only the fields that select repositories
+// and files are considered.
+type curatedFilterMatcher struct {
+	repos, excludedRepos []*regexp.Regexp
+	files, excludedFiles []*regexp.Regexp
+	langs, excludedLangs []string
+
+	// repoGroup is the set of repositories in the repogroup: of the query
+	// fragment, or nil if it has none.
+	repoGroup map[api.RepoName]bool
+}
+
+func newCuratedFilterMatcher(value string, groups map[string][]*types.Repo) (*curatedFilterMatcher, error) {
+	q, err := query.ParseAndCheck(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var m curatedFilterMatcher
+
+	compile := func(patterns []string, repo bool) ([]*regexp.Regexp, error) {
+		rs := make([]*regexp.Regexp, 0, len(patterns))
+		for _, p := range patterns {
+			if repo {
+				// Revisions don't change which repositories match.
+				name, _ := search.ParseRepositoryRevisions(p)
+				p = string(name)
+			}
+			r, err := regexp.Compile("(?i)" + p)
+			if err != nil {
+				return nil, err
+			}
+			rs = append(rs, r)
+		}
+		return rs, nil
+	}
+
+	repos, excludedRepos := q.RegexpPatterns(query.FieldRepo)
+	if m.repos, err = compile(repos, true); err != nil {
+		return nil, err
+	}
+	if m.excludedRepos, err = compile(excludedRepos, false); err != nil {
+		return nil, err
+	}
+
+	files, excludedFiles := q.RegexpPatterns(query.FieldFile)
+	if m.files, err = compile(files, false); err != nil {
+		return nil, err
+	}
+	if m.excludedFiles, err = compile(excludedFiles, false); err != nil {
+		return nil, err
+	}
+
+	m.langs, m.excludedLangs = q.StringValues(query.FieldLang)
+
+	if group, _ := q.StringValue(query.FieldRepoGroup); group != "" {
+		m.repoGroup = map[api.RepoName]bool{}
+		for _, repo := range groups[group] {
+			m.repoGroup[repo.Name] = true
+		}
+	}
+
+	return &m, nil
+}
+
+// match reports whether a result in repo matches. fm is the file match of
+// the result, or nil if it is a repository result, which doesn't match query
+// fragments that require files.
+func (m *curatedFilterMatcher) match(repo api.RepoName, fm *fileMatchResolver) bool {
+	if m.repoGroup != nil && !m.repoGroup[repo] {
+		return false
+	}
+	if !matchAll(m.repos, string(repo)) || matchAny(m.excludedRepos, string(repo)) {
+		return false
+	}
+
+	if fm == nil {
+		return len(m.files) == 0 && len(m.langs) == 0
+	}
+
+	if !matchAll(m.files, fm.JPath) || matchAny(m.excludedFiles, fm.JPath) {
+		return false
+	}
+
+	language, _ := fm.language()
+	for _, lang := range m.langs {
+		if !strings.EqualFold(lang, language) {
+			return false
+		}
+	}
+	for _, lang := range m.excludedLangs {
+		if strings.EqualFold(lang, language) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAll(rs []*regexp.Regexp, s string) bool {
+	for _, r := range rs {
+		if !r.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(rs []*regexp.Regexp, s string) bool {
+	for _, r := range rs {
+		if r.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
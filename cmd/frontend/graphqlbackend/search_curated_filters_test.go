@@ -0,0 +1,70 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCuratedFilters(t *testing.T) {
+	var (
+		payments = &types.Repo{Name: "github.com/acme/payments-api"}
+		web      = &types.Repo{Name: "github.com/acme/web"}
+		groups   = map[string][]*types.Repo{"frontend": {web}}
+	)
+
+	results := []searchResultResolver{
+		&fileMatchResolver{JPath: "main.go", repo: payments, JLineMatches: []*lineMatch{{}, {}}},
+		&fileMatchResolver{JPath: "vendor/lib.go", repo: payments, JLimitHit: true},
+		&fileMatchResolver{JPath: "src/app.ts", repo: web},
+		&RepositoryResolver{repo: web},
+	}
+
+	tests := []struct {
+		value string
+		want  *searchFilterResolver // nil if the filter is not proposed
+	}{
+		{
+			value: `repo:^github\.com/acme/payments-`,
+			want:  &searchFilterResolver{count: 2, limitHit: true, score: 2},
+		},
+		{
+			value: `repo:payments -file:^vendor/ lang:go`,
+			want:  &searchFilterResolver{count: 2, score: 1},
+		},
+		{
+			value: `repogroup:frontend`,
+			want:  &searchFilterResolver{count: 1, score: 2},
+		},
+		{
+			// Repository results don't match fragments that require files.
+			value: `repogroup:frontend file:\.go$`,
+		},
+		{
+			value: `repo:^github\.com/other/`,
+		},
+		{
+			// Invalid fragments are ignored.
+			value: `repo:(`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			filters := curatedFilters([]*schema.CuratedSearchFilter{{Label: "label", Value: test.value}}, results, groups)
+
+			var want []*searchFilterResolver
+			if test.want != nil {
+				test.want.value = test.value
+				test.want.label = "label"
+				test.want.kind = "curated"
+				want = []*searchFilterResolver{test.want}
+			}
+			if !reflect.DeepEqual(filters, want) {
+				t.Errorf("got filters %+v, want %+v", filters, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+)
+
+type searchResultDirectoryCountsArgs struct {
+	Version     string
+	PatternType *string
+	Query       string
+}
+
+// SearchResultDirectoryCounts counts the search results for the query in each
+// top-level directory of the tree at the commit.
+func (r *GitCommitResolver) SearchResultDirectoryCounts(ctx context.Context, args *searchResultDirectoryCountsArgs) (*searchResultDirectoryCountsResolver, error) {
+	searchType, err := detectSearchType(args.Version, args.PatternType, args.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString := args.Query
+	switch searchType {
+	case "literal":
+		queryString = query.ConvertToLiteral(args.Query)
+	case "fuzzy":
+		queryString = query.ConvertToFuzzy(args.Query)
+	}
+
+	q, err := query.ParseAndCheck(queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &searchResolver{query: q, patternType: searchType}
+	p, err := sr.getPatternInfo(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return searchResultDirectoryCounts(ctx, search.Indexed(), r.repo.repo, api.CommitID(r.oid), p)
+}
+
+// searchResultDirectoryCounts runs the search for the pattern on the indexed
+// commit of the repository and counts the results in each top-level
+// directory. Only the file list of the results is used, so no file contents
+// are fetched. It returns nil if the commit is not indexed.
+func searchResultDirectoryCounts(ctx context.Context, z *searchbackend.Zoekt, repo *types.Repo, commit api.CommitID, p *search.PatternInfo) (*searchResultDirectoryCountsResolver, error) {
+	if !z.Enabled() {
+		return nil, nil
+	}
+
+	indexed, _, err := zoektIndexedRepos(ctx, z, []*search.RepositoryRevisions{{
+		Repo: repo,
+		Revs: []search.RevisionSpecifier{{RevSpec: ""}},
+	}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexed) == 0 || indexed[0].IndexedHEADCommit() != commit {
+		return nil, nil
+	}
+
+	queryExceptRepos, err := queryToZoektQuery(p, false)
+	if err != nil {
+		return nil, err
+	}
+	finalQuery := zoektquery.NewAnd(&zoektquery.RepoSet{Set: map[string]bool{string(repo.Name): true}}, queryExceptRepos)
+
+	searchOpts := zoektSearchOpts(zoektResultCountFactor(1, p), p)
+	resp, err := z.Search(ctx, finalQuery, &searchOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &searchResultDirectoryCountsResolver{
+		directories: countResultsByTopLevelDirectory(resp.Files),
+		limitHit:    resp.FilesSkipped+resp.ShardsSkipped > 0,
+	}, nil
+}
+
+// countResultsByTopLevelDirectory sums up the results in the files by the
+// top-level directory they are in, like fileMatchResolver.resultCount counts
+// the results in a file. Files in the root directory are not counted.
+func countResultsByTopLevelDirectory(files []zoekt.FileMatch) []*searchResultDirectoryCountResolver {
+	counts := map[string]int32{}
+	for _, file := range files {
+		i := strings.Index(file.FileName, "/")
+		if i < 0 {
+			continue
+		}
+
+		var count int32
+		for _, l := range file.LineMatches {
+			if !l.FileName {
+				count++
+			}
+		}
+		if count == 0 {
+			count = 1 // 1 to count "empty" results like type:path results
+		}
+		counts[file.FileName[:i]] += count
+	}
+
+	directories := make([]*searchResultDirectoryCountResolver, 0, len(counts))
+	for path, count := range counts {
+		directories = append(directories, &searchResultDirectoryCountResolver{path: path, count: count})
+	}
+	sort.Slice(directories, func(i, j int) bool { return directories[i].path < directories[j].path })
+	return directories
+}
+
+// searchResultDirectoryCountsResolver implements the GraphQL type SearchResultDirectoryCounts.
+type searchResultDirectoryCountsResolver struct {
+	directories []*searchResultDirectoryCountResolver
+	limitHit    bool
+}
+
+func (r *searchResultDirectoryCountsResolver) Directories() []*searchResultDirectoryCountResolver {
+	return r.directories
+}
+
+func (r *searchResultDirectoryCountsResolver) LimitHit() bool { return r.limitHit }
+
+// searchResultDirectoryCountResolver implements the GraphQL type SearchResultDirectoryCount.
+type searchResultDirectoryCountResolver struct {
+	path  string
+	count int32
+}
+
+func (r *searchResultDirectoryCountResolver) Path() string { return r.path }
+
+func (r *searchResultDirectoryCountResolver) Count() int32 { return r.count }
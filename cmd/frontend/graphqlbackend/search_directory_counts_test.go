@@ -0,0 +1,62 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/zoekt"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+)
+
+func TestSearchResultDirectoryCounts(t *testing.T) {
+	repo := &types.Repo{Name: "foo/bar"}
+	z := &searchbackend.Zoekt{Client: &fakeSearcher{
+		repos: &zoekt.RepoList{Repos: []*zoekt.RepoListEntry{{
+			Repository: zoekt.Repository{
+				Name:     "foo/bar",
+				Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: "deadbeef"}},
+			},
+		}}},
+		result: &zoekt.SearchResult{Files: []zoekt.FileMatch{
+			{FileName: "cmd/a.go", LineMatches: []zoekt.LineMatch{{}, {}}},
+			{FileName: "cmd/b/c.go", LineMatches: []zoekt.LineMatch{{}}},
+			{FileName: "internal/d.go", LineMatches: []zoekt.LineMatch{{FileName: true}}},
+			{FileName: "README.md", LineMatches: []zoekt.LineMatch{{}}},
+		}},
+	}, DisableCache: true}
+	p := &search.PatternInfo{Pattern: "foo", FileMatchLimit: defaultMaxSearchResults}
+
+	r, err := searchResultDirectoryCounts(context.Background(), z, repo, "deadbeef", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r == nil {
+		t.Fatal("got nil counts for indexed commit")
+	}
+	var got []searchResultDirectoryCountResolver
+	for _, d := range r.Directories() {
+		got = append(got, *d)
+	}
+	want := []searchResultDirectoryCountResolver{
+		{path: "cmd", count: 3},
+		{path: "internal", count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if r.LimitHit() {
+		t.Error("got limitHit, want none")
+	}
+
+	r, err = searchResultDirectoryCounts(context.Background(), z, repo, api.CommitID("c0ffee"), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != nil {
+		t.Errorf("got counts %+v for unindexed commit, want nil", r)
+	}
+}
@@ -0,0 +1,86 @@
+package graphqlbackend
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// searchExclusions are the repositories and paths that are excluded from a
+// search by the search.exclusions settings of the viewer's organizations.
+type searchExclusions struct {
+	repos []string // regexps matching repository names
+	paths []string // regexps matching file paths
+}
+
+var mockOrgSearchExclusions func() (*searchExclusions, error)
+
+// orgSearchExclusions returns the union of the search.exclusions settings of
+// the viewer's organizations. Unlike other settings, they are not read from
+// the merged settings, so that members can't override them in their user
+// settings.
+func orgSearchExclusions(ctx context.Context) (*searchExclusions, error) {
+	if mockOrgSearchExclusions != nil {
+		return mockOrgSearchExclusions()
+	}
+
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return &searchExclusions{}, nil
+	}
+
+	orgs, err := db.Orgs.GetByUserID(ctx, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].ID < orgs[j].ID })
+
+	var e searchExclusions
+	for _, org := range orgs {
+		var settings schema.Settings
+		if err := (&settingsSubject{org: &OrgResolver{org}}).readSettings(ctx, &settings); err != nil {
+			return nil, err
+		}
+		if settings.SearchExclusions == nil {
+			continue
+		}
+		e.repos = append(e.repos, validExclusionPatterns(org.Name, settings.SearchExclusions.Repositories)...)
+		e.paths = append(e.paths, validExclusionPatterns(org.Name, settings.SearchExclusions.Paths)...)
+	}
+	return &e, nil
+}
+
+// validExclusionPatterns returns the patterns that are valid regexps. The
+// others are skipped, so that a typo in an organization's settings doesn't
+// break the searches of all its members.
+func validExclusionPatterns(org string, patterns []string) []string {
+	valid := patterns[:0:0]
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			log15.Warn("ignoring invalid search.exclusions pattern in organization settings", "org", org, "pattern", p, "error", err)
+			continue
+		}
+		valid = append(valid, p)
+	}
+	return valid
+}
+
+// searchExclusions returns the exclusions that apply to the search, which are
+// none if the query contains orgexclusions:no.
+func (r *searchResolver) searchExclusions(ctx context.Context) (*searchExclusions, error) {
+	v, _ := r.query.StringValue(query.FieldOrgExclusions)
+	if e := parseYesNoOnly(v); e == No || e == False {
+		return &searchExclusions{}, nil
+	}
+
+	r.exclusionsOnce.Do(func() {
+		r.exclusions, r.exclusionsErr = orgSearchExclusions(ctx)
+	})
+	return r.exclusions, r.exclusionsErr
+}
@@ -0,0 +1,67 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+)
+
+func TestSearchResolver_searchExclusions(t *testing.T) {
+	calls := 0
+	mockOrgSearchExclusions = func() (*searchExclusions, error) {
+		calls++
+		return &searchExclusions{repos: []string{"-mirror$"}, paths: []string{"^vendor/"}}, nil
+	}
+	defer func() { mockOrgSearchExclusions = nil }()
+
+	tests := []struct {
+		query          string
+		excludePattern string
+		repos          []string
+	}{
+		{query: "p", excludePattern: "^vendor/", repos: []string{"-mirror$"}},
+		{query: "p -file:f", excludePattern: "f|^vendor/", repos: []string{"-mirror$"}},
+		{query: "p orgexclusions:yes", excludePattern: "^vendor/", repos: []string{"-mirror$"}},
+		{query: "p orgexclusions:no"},
+		{query: "p -file:f orgexclusions:no", excludePattern: "f"},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := query.ParseAndCheck(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sr := searchResolver{query: q}
+
+			p, err := sr.getPatternInfo(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.ExcludePattern != test.excludePattern {
+				t.Errorf("got exclude pattern %q, want %q", p.ExcludePattern, test.excludePattern)
+			}
+
+			e, err := sr.searchExclusions(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(e.repos, test.repos) {
+				t.Errorf("got excluded repos %q, want %q", e.repos, test.repos)
+			}
+		})
+	}
+
+	// The exclusions are only looked up once per search.
+	if want := 3; calls != want {
+		t.Errorf("got %d lookups, want %d", calls, want)
+	}
+}
+
+func TestValidExclusionPatterns(t *testing.T) {
+	got := validExclusionPatterns("acme", []string{"^vendor/", "(", `\.pb\.go$`})
+	if want := []string{"^vendor/", `\.pb\.go$`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
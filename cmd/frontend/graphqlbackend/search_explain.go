@@ -0,0 +1,216 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+)
+
+type explainSearchQueryArgs struct {
+	Version     string
+	PatternType *string
+	Query       string
+}
+
+// ExplainSearchQuery explains how the search query is interpreted, without
+// running the search.
+func (r *schemaResolver) ExplainSearchQuery(ctx context.Context, args *explainSearchQueryArgs) (*searchQueryExplanationResolver, error) {
+	searchType, err := detectSearchType(args.Version, args.PatternType, args.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString := args.Query
+	switch searchType {
+	case "literal":
+		queryString = query.ConvertToLiteral(args.Query)
+	case "fuzzy":
+		queryString = query.ConvertToFuzzy(args.Query)
+	}
+
+	q, err := query.ParseAndCheck(queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &searchResolver{
+		query:         q,
+		originalQuery: args.Query,
+		version:       args.Version,
+		patternType:   searchType,
+		zoekt:         search.Indexed(),
+		searcherURLs:  search.SearcherURLs(),
+	}
+
+	p, err := sr.getPatternInfo(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, _, overLimit, err := sr.resolveRepositories(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like the search itself, treat all repositories as unindexed if the
+	// index is not available.
+	indexed, unindexed := 0, len(repos)
+	if sr.zoekt.Enabled() {
+		if zoektRepos, searcherRepos, err := zoektIndexedRepos(ctx, sr.zoekt, repos, nil); err == nil {
+			indexed, unindexed = len(zoektRepos), len(searcherRepos)
+		}
+	}
+
+	var resultTypes []string
+	allResultTypes, seenResultTypes := sr.determineResultTypes(search.Args{Pattern: p, Query: q}, "")
+	for _, resultType := range allResultTypes {
+		if _, seen := seenResultTypes[resultType]; !seen {
+			seenResultTypes[resultType] = struct{}{}
+			resultTypes = append(resultTypes, resultType)
+		}
+	}
+	backends, err := searchBackends(q, resultTypes, indexed, unindexed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &searchQueryExplanationResolver{
+		sr:            sr,
+		pattern:       p,
+		resultTypes:   resultTypes,
+		repos:         repos,
+		reposLimitHit: overLimit,
+		indexedRepos:  indexed,
+		backends:      backends,
+	}, nil
+}
+
+// searchBackends returns the backends that a search for the given result
+// types would run on, given the number of indexed and unindexed repositories
+// it runs over.
+func searchBackends(q *query.Query, resultTypes []string, indexed, unindexed int) ([]string, error) {
+	// Support index:yes (default), index:only, and index:no in search query,
+	// like textSearchInRepos does.
+	if index, _ := q.StringValues(query.FieldIndex); len(index) > 0 {
+		switch parseYesNoOnly(index[len(index)-1]) {
+		case Yes, True:
+			// default
+		case Only:
+			unindexed = 0
+		case No, False:
+			indexed, unindexed = 0, indexed+unindexed
+		default:
+			return nil, fmt.Errorf("invalid index:%q (valid values are: yes, only, no)", index[len(index)-1])
+		}
+	}
+
+	var backends []string
+	seen := map[string]bool{}
+	add := func(backend string) {
+		if !seen[backend] {
+			seen[backend] = true
+			backends = append(backends, backend)
+		}
+	}
+	for _, resultType := range resultTypes {
+		switch resultType {
+		case "file", "path":
+			if indexed > 0 {
+				add("zoekt")
+			}
+			if unindexed > 0 {
+				add("searcher")
+			}
+		case "symbol":
+			if indexed > 0 {
+				add("zoekt")
+			}
+			if unindexed > 0 {
+				add("symbols")
+			}
+		case "filename-only":
+			if indexed > 0 {
+				add("zoekt")
+			}
+			if unindexed > 0 {
+				add("gitserver")
+			}
+		case "diff", "commit":
+			add("gitserver")
+		case "codemod":
+			add("replacer")
+		}
+	}
+	return backends, nil
+}
+
+// searchQueryExplanationResolver implements the GraphQL type SearchQueryExplanation.
+type searchQueryExplanationResolver struct {
+	sr            *searchResolver
+	pattern       *search.PatternInfo
+	resultTypes   []string
+	repos         []*search.RepositoryRevisions
+	reposLimitHit bool
+	indexedRepos  int
+	backends      []string
+}
+
+func (r *searchQueryExplanationResolver) PatternType() string {
+	return r.sr.patternType
+}
+
+func (r *searchQueryExplanationResolver) Pattern() *string {
+	if r.pattern.IsEmpty() {
+		return nil
+	}
+	return &r.pattern.Pattern
+}
+
+func (r *searchQueryExplanationResolver) Filters() []*searchQueryFilterResolver {
+	var filters []*searchQueryFilterResolver
+	for _, expr := range r.sr.query.ParseTree {
+		if expr.Field == query.FieldDefault {
+			continue
+		}
+		filters = append(filters, &searchQueryFilterResolver{
+			field:   expr.Field,
+			value:   expr.Value,
+			negated: expr.Not,
+		})
+	}
+	return filters
+}
+
+func (r *searchQueryExplanationResolver) ResultTypes() []string {
+	return r.resultTypes
+}
+
+func (r *searchQueryExplanationResolver) RepositoriesCount() int32 {
+	return int32(len(r.repos))
+}
+
+func (r *searchQueryExplanationResolver) IndexedRepositoriesCount() int32 {
+	return int32(r.indexedRepos)
+}
+
+func (r *searchQueryExplanationResolver) RepositoriesLimitHit() bool {
+	return r.reposLimitHit
+}
+
+func (r *searchQueryExplanationResolver) Backends() []string {
+	return r.backends
+}
+
+// searchQueryFilterResolver implements the GraphQL type SearchQueryFilter.
+type searchQueryFilterResolver struct {
+	field, value string
+	negated      bool
+}
+
+func (r *searchQueryFilterResolver) Field() string { return r.field }
+
+func (r *searchQueryFilterResolver) Value() string { return r.value }
+
+func (r *searchQueryFilterResolver) Negated() bool { return r.negated }
@@ -0,0 +1,98 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestExplainSearchQuery(t *testing.T) {
+	db.Mocks.Repos.List = func(_ context.Context, _ db.ReposListOptions) ([]*types.Repo, error) {
+		return []*types.Repo{
+			{Name: "github.com/foo/a"},
+			{Name: "github.com/foo/b"},
+		}, nil
+	}
+	defer func() { db.Mocks.Repos.List = nil }()
+
+	r, err := (&schemaResolver{}).ExplainSearchQuery(context.Background(), &explainSearchQueryArgs{
+		Version: "V2",
+		Query:   "repo:foo -file:_test foo.bar type:file type:diff",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := r.PatternType(), "literal"; got != want {
+		t.Errorf("got pattern type %q, want %q", got, want)
+	}
+	if got, want := r.Pattern(), `foo\.bar`; got == nil || *got != want {
+		t.Errorf("got pattern %v, want %q", got, want)
+	}
+
+	var filters []searchQueryFilterResolver
+	for _, f := range r.Filters() {
+		filters = append(filters, *f)
+	}
+	wantFilters := []searchQueryFilterResolver{
+		{field: "repo", value: "foo"},
+		{field: "file", value: "_test", negated: true},
+		{field: "type", value: "file"},
+		{field: "type", value: "diff"},
+	}
+	if !reflect.DeepEqual(filters, wantFilters) {
+		t.Errorf("got filters %+v, want %+v", filters, wantFilters)
+	}
+
+	if got, want := r.ResultTypes(), []string{"file", "diff"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got result types %v, want %v", got, want)
+	}
+	if got, want := r.RepositoriesCount(), int32(2); got != want {
+		t.Errorf("got %d repositories, want %d", got, want)
+	}
+	if got, want := r.Backends(), []string{"searcher", "gitserver"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got backends %v, want %v", got, want)
+	}
+}
+
+func TestSearchBackends(t *testing.T) {
+	cases := []struct {
+		query              string
+		resultTypes        []string
+		indexed, unindexed int
+		want               []string
+	}{
+		{"foo", []string{"file", "path", "repo"}, 2, 1, []string{"zoekt", "searcher"}},
+		{"foo", []string{"file"}, 2, 0, []string{"zoekt"}},
+		{"foo index:only", []string{"file"}, 2, 1, []string{"zoekt"}},
+		{"foo index:no", []string{"file"}, 2, 1, []string{"searcher"}},
+		{"foo", []string{"symbol"}, 0, 1, []string{"symbols"}},
+		{"foo", []string{"diff", "commit"}, 2, 1, []string{"gitserver"}},
+		{"foo", []string{"repo"}, 2, 1, nil},
+	}
+	for _, c := range cases {
+		q, err := query.ParseAndCheck(c.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := searchBackends(q, c.resultTypes, c.indexed, c.unindexed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q %v: got %v, want %v", c.query, c.resultTypes, got, c.want)
+		}
+	}
+
+	q, err := query.ParseAndCheck("foo index:maybe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := searchBackends(q, []string{"file"}, 1, 1); err == nil {
+		t.Error("expected error for invalid index: value")
+	}
+}
@@ -0,0 +1,107 @@
+package graphqlbackend
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// fileOwnerFilterPattern matches file: filter values of the form
+// "has.owner(owner)", which match files by the owners assigned to them in the
+// repository's ownership file (see backend.Owners) instead of by their paths.
+var fileOwnerFilterPattern = regexp.MustCompile(`^has\.owner\(([^()]+)\)$`)
+
+// extractFileOwnerFilters separates the owner filters from the other file:
+// filter values.
+func extractFileOwnerFilters(patterns []string) (rest, owners []string) {
+	for _, pattern := range patterns {
+		m := fileOwnerFilterPattern.FindStringSubmatch(pattern)
+		if m == nil {
+			rest = append(rest, pattern)
+			continue
+		}
+		owners = append(owners, m[1])
+	}
+	return rest, owners
+}
+
+// filterFileMatchesByOwner removes the file matches from results that are not
+// owned by all of the given owners, or that are owned by any of the negated
+// owners. It is applied after searching because ownership can't be expressed
+// as a path pattern that the searchers understand. Files whose ownership rules
+// can't be loaded are treated as unowned.
+func filterFileMatchesByOwner(ctx context.Context, results []searchResultResolver, owners, negatedOwners []string) []searchResultResolver {
+	if len(owners) == 0 && len(negatedOwners) == 0 {
+		return results
+	}
+
+	// Many file matches share the same repository and commit, so only load
+	// their ownership rules once.
+	rulesets := map[string]*codeowners.Ruleset{}
+	fileOwners := func(fm *fileMatchResolver) []string {
+		key := string(fm.repo.Name) + "@" + string(fm.commitID)
+		rs, ok := rulesets[key]
+		if !ok {
+			var err error
+			rs, err = backend.Owners.Ruleset(ctx, fm.repo, fm.commitID)
+			if err != nil {
+				log15.Warn("Failed to load ownership rules for search results", "repo", fm.repo.Name, "commitID", fm.commitID, "err", err)
+			}
+			rulesets[key] = rs
+		}
+		if rs == nil {
+			return nil
+		}
+		return rs.Match(fm.JPath)
+	}
+
+	ownedBy := func(fileOwners []string, owner string) bool {
+		for _, o := range fileOwners {
+			if codeowners.OwnerMatches(o, owner) {
+				return true
+			}
+		}
+		return false
+	}
+
+	matches := func(fm *fileMatchResolver) bool {
+		fo := fileOwners(fm)
+		for _, owner := range owners {
+			if !ownedBy(fo, owner) {
+				return false
+			}
+		}
+		for _, owner := range negatedOwners {
+			if ownedBy(fo, owner) {
+				return false
+			}
+		}
+		return true
+	}
+
+	filtered := results[:0]
+	for _, result := range results {
+		if fm, ok := result.ToFileMatch(); ok && !matches(fm) {
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// Owners returns the owners of the file, as assigned by the ownership file of
+// the repository at the commit of the file match.
+func (fm *fileMatchResolver) Owners(ctx context.Context) ([]string, error) {
+	rs, err := backend.Owners.Ruleset(ctx, fm.repo, fm.commitID)
+	if err != nil {
+		return nil, err
+	}
+	owners := rs.Match(fm.JPath)
+	if owners == nil {
+		owners = []string{}
+	}
+	return owners, nil
+}
@@ -0,0 +1,87 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+)
+
+func TestExtractFileOwnerFilters(t *testing.T) {
+	rest, owners := extractFileOwnerFilters([]string{
+		`\.go$`,
+		"has.owner(@sourcegraph/web)",
+		"has.owner(alice)",
+		"has.owner()",
+	})
+
+	if want := []string{`\.go$`, "has.owner()"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("got rest %q, want %q", rest, want)
+	}
+	if want := []string{"@sourcegraph/web", "alice"}; !reflect.DeepEqual(owners, want) {
+		t.Errorf("got owners %q, want %q", owners, want)
+	}
+}
+
+func TestFilterFileMatchesByOwner(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	rulesets := map[api.RepoName]string{
+		"a": "* @sourcegraph/everyone\n/web/ @sourcegraph/web @alice\n",
+	}
+	calls := 0
+	backend.Mocks.Owners.Ruleset = func(ctx context.Context, repo *types.Repo, commitID api.CommitID) (*codeowners.Ruleset, error) {
+		calls++
+		data, ok := rulesets[repo.Name]
+		if !ok {
+			return nil, errors.New("boom")
+		}
+		return codeowners.Parse([]byte(data))
+	}
+
+	fileMatch := func(repo api.RepoName, path string) *fileMatchResolver {
+		return &fileMatchResolver{JPath: path, repo: &types.Repo{Name: repo}, commitID: "c"}
+	}
+	results := []searchResultResolver{
+		fileMatch("a", "README.md"),
+		fileMatch("a", "web/app.ts"),
+		fileMatch("b", "web/app.ts"),
+	}
+	key := func(results []searchResultResolver) (keys []string) {
+		for _, r := range results {
+			fm, _ := r.ToFileMatch()
+			keys = append(keys, string(fm.repo.Name)+"/"+fm.JPath)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		owners, negatedOwners []string
+		want                  []string
+	}{
+		{want: []string{"a/README.md", "a/web/app.ts", "b/web/app.ts"}},
+		{owners: []string{"sourcegraph/web"}, want: []string{"a/web/app.ts"}},
+		{owners: []string{"@Alice", "sourcegraph/web"}, want: []string{"a/web/app.ts"}},
+		{owners: []string{"sourcegraph/everyone"}, want: []string{"a/README.md"}},
+		{negatedOwners: []string{"sourcegraph/web"}, want: []string{"a/README.md", "b/web/app.ts"}},
+	}
+	for _, test := range tests {
+		calls = 0
+		got := filterFileMatchesByOwner(context.Background(), append([]searchResultResolver{}, results...), test.owners, test.negatedOwners)
+		if have := key(got); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("owners %q, negated owners %q: got %q, want %q", test.owners, test.negatedOwners, have, test.want)
+		}
+		if len(test.owners) > 0 || len(test.negatedOwners) > 0 {
+			// The rules are loaded once per repository and commit.
+			if calls != 2 {
+				t.Errorf("got %d calls to Owners.Ruleset, want 2", calls)
+			}
+		}
+	}
+}
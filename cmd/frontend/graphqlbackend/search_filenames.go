@@ -0,0 +1,285 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Filename-only searches (type:filename-only) find the files whose paths match
+// the pattern without searching file contents. Indexed repositories are
+// searched with zoekt's filename index only, and the others by listing their
+// files on gitserver (the recursive listing of a commit's root is cached), so
+// no archives need to be fetched by searcher.
+
+const (
+	// filenameOnlyDefaultLimit is the number of files a filename-only search
+	// returns unless count: is given. It is higher than the default for other
+	// searches because file names are cheap to find and to show.
+	filenameOnlyDefaultLimit = 500
+
+	// filenameOnlyConcurrency is the number of unindexed repositories whose
+	// files are listed concurrently.
+	filenameOnlyConcurrency = 16
+)
+
+// filenameOnlyLimit returns the maximum number of results of a filename-only
+// search.
+func (r *searchResolver) filenameOnlyLimit() int {
+	if r.countIsSet() || r.pagination != nil || r.exhaustive {
+		return int(r.maxResults())
+	}
+	return filenameOnlyDefaultLimit
+}
+
+// searchFilenamesInRepos searches the repositories for files whose paths match
+// the pattern, returning at most limit file matches (without line matches).
+func searchFilenamesInRepos(ctx context.Context, args *search.Args, limit int) (res []*fileMatchResolver, common *searchResultsCommon, err error) {
+	tr, ctx := trace.New(ctx, "searchFilenamesInRepos", fmt.Sprintf("pattern: %q, numRepoRevs: %d", args.Pattern.Pattern, len(args.Repos)))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	common = &searchResultsCommon{partial: make(map[api.RepoName]struct{})}
+	common.repos = make([]*types.Repo, len(args.Repos))
+	for i, repo := range args.Repos {
+		common.repos[i] = repo.Repo
+	}
+
+	if args.Pattern.IsEmpty() {
+		return nil, common, nil
+	}
+
+	zoektRepos, gitserverRepos := []*search.RepositoryRevisions(nil), args.Repos
+	if args.Zoekt.Enabled() {
+		zoektRepos, gitserverRepos, err = zoektIndexedRepos(ctx, args.Zoekt, args.Repos, nil)
+		if err != nil {
+			// Fall back to listing the files of the repositories.
+			tr.LazyPrintf("zoektIndexedRepos failed: %v", err)
+			zoektRepos, gitserverRepos, err = nil, indexUnavailableFallback(common, args.Repos), nil
+		}
+	}
+
+	matchPattern, matchPath, err := compileFilenamePatterns(args.Pattern)
+	if err != nil {
+		return nil, common, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		matches  []*fileMatchResolver
+		fatalErr error
+	)
+
+	if len(zoektRepos) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			zoektMatches, limitHit, searchErr := zoektSearchFilenames(ctx, args, zoektRepos, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			if searchErr != nil && fatalErr == nil {
+				fatalErr = errors.Wrap(searchErr, "indexed filename search failed")
+			}
+			for _, repoRev := range zoektRepos {
+				common.searched = append(common.searched, repoRev.Repo)
+				common.indexed = append(common.indexed, repoRev.Repo)
+			}
+			common.limitHit = common.limitHit || limitHit
+			matches = append(matches, zoektMatches...)
+		}()
+	}
+
+	sem := make(chan struct{}, filenameOnlyConcurrency)
+	for _, repoRev := range gitserverRepos {
+		if len(repoRev.Revs) == 0 {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(repoRev *search.RepositoryRevisions) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			repoMatches, limitHit, searchErr := listFilenamesInRepo(ctx, repoRev, matchPattern, matchPath, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			if ctx.Err() == nil {
+				common.searched = append(common.searched, repoRev.Repo)
+			}
+			if limitHit {
+				common.partial[repoRev.Repo.Name] = struct{}{}
+			}
+			if err := handleRepoSearchResult(common, repoRev, limitHit, false, searchErr); err != nil && fatalErr == nil {
+				fatalErr = errors.Wrapf(searchErr, "failed to list files of %s", repoRev.String())
+			}
+			matches = append(matches, repoMatches...)
+		}(repoRev)
+	}
+	wg.Wait()
+	if fatalErr != nil {
+		return nil, common, fatalErr
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].uri < matches[j].uri })
+	if len(matches) > limit {
+		matches = matches[:limit]
+		common.limitHit = true
+	}
+	common.resultCount = int32(len(matches))
+	return matches, common, nil
+}
+
+// filenamePattern returns the search pattern as a regular expression.
+func filenamePattern(p *search.PatternInfo) string {
+	if p.IsRegExp {
+		return p.Pattern
+	}
+	return regexp.QuoteMeta(p.Pattern)
+}
+
+// compileFilenamePatterns compiles the matchers of the paths of files that a
+// filename-only search returns: matchPattern for the search pattern, and
+// matchPath for the file: and -file: filters.
+func compileFilenamePatterns(p *search.PatternInfo) (matchPattern, matchPath pathmatch.PathMatcher, err error) {
+	matchPattern, err = pathmatch.CompilePattern(filenamePattern(p), pathmatch.CompileOptions{RegExp: true, CaseSensitive: p.IsCaseSensitive})
+	if err != nil {
+		return nil, nil, err
+	}
+	matchPath, err = pathmatch.CompilePathPatterns(p.IncludePatterns, p.ExcludePattern, pathmatch.CompileOptions{
+		RegExp:        p.PathPatternsAreRegExps,
+		CaseSensitive: p.PathPatternsAreCaseSensitive,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return matchPattern, matchPath, nil
+}
+
+// listFilenamesInRepo lists the files of the revisions of the repository whose
+// paths match, returning at most limit file matches.
+func listFilenamesInRepo(ctx context.Context, repoRev *search.RepositoryRevisions, matchPattern, matchPath pathmatch.PathMatcher, limit int) (matches []*fileMatchResolver, limitHit bool, err error) {
+	gitserverRepo := repoRev.GitserverRepo()
+	searched := make(map[api.CommitID]bool, len(repoRev.Revs))
+	for _, rev := range repoRev.RevSpecs() {
+		if _, _, ok := (search.RevisionSpecifier{RevSpec: rev}).Range(); ok {
+			// Revision ranges have no files of their own.
+			continue
+		}
+
+		// As in searchFilesInRepo, don't trigger a repo-updater lookup.
+		commit, err := git.ResolveRevision(ctx, gitserverRepo, nil, rev, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+		if err != nil {
+			return matches, limitHit, err
+		}
+		if searched[commit] {
+			continue
+		}
+		searched[commit] = true
+
+		files, err := git.ReadDir(ctx, gitserverRepo, commit, "", true)
+		if err != nil {
+			return matches, limitHit, err
+		}
+
+		rev := rev
+		for _, fi := range files {
+			if fi.IsDir() || fi.Mode()&git.ModeSubmodule != 0 || !matchPattern.MatchPath(fi.Name()) || !matchPath.MatchPath(fi.Name()) {
+				continue
+			}
+			if len(matches) == limit {
+				return matches, true, nil
+			}
+			matches = append(matches, &fileMatchResolver{
+				JPath:    fi.Name(),
+				uri:      fileMatchURI(repoRev.Repo.Name, rev, fi.Name()),
+				repo:     repoRev.Repo,
+				commitID: commit,
+				inputRev: &rev,
+			})
+		}
+	}
+	return matches, limitHit, nil
+}
+
+// zoektSearchFilenames searches zoekt's filename index of the repositories,
+// returning at most limit file matches.
+func zoektSearchFilenames(ctx context.Context, args *search.Args, repos []*search.RepositoryRevisions, limit int) (matches []*fileMatchResolver, limitHit bool, err error) {
+	repoSet := &zoektquery.RepoSet{Set: make(map[string]bool, len(repos))}
+	repoMap := make(map[api.RepoName]*search.RepositoryRevisions, len(repos))
+	for _, repoRev := range repos {
+		repoSet.Set[string(repoRev.Repo.Name)] = true
+		repoMap[api.RepoName(strings.ToLower(string(repoRev.Repo.Name)))] = repoRev
+	}
+
+	p := args.Pattern
+	q, err := fileRe(filenamePattern(p), p.IsCaseSensitive)
+	if err != nil {
+		return nil, false, err
+	}
+	and := []zoektquery.Q{repoSet, q}
+	for _, include := range p.IncludePatterns {
+		q, err := fileRe(include, p.PathPatternsAreCaseSensitive)
+		if err != nil {
+			return nil, false, err
+		}
+		and = append(and, q)
+	}
+	if p.ExcludePattern != "" {
+		q, err := fileRe(p.ExcludePattern, p.PathPatternsAreCaseSensitive)
+		if err != nil {
+			return nil, false, err
+		}
+		and = append(and, &zoektquery.Not{Child: q})
+	}
+
+	// File names only match once per file, so the match counts are file
+	// counts.
+	opts := zoekt.SearchOptions{
+		ShardMaxMatchCount: limit + 1,
+		TotalMaxMatchCount: limit + 1,
+		MaxDocDisplayCount: limit + 1,
+	}
+	opts.SetDefaults()
+	resp, err := args.Zoekt.Search(ctx, zoektquery.Simplify(zoektquery.NewAnd(and...)), &opts)
+	if err != nil {
+		return nil, false, err
+	}
+	limitHit = resp.FilesSkipped+resp.ShardsSkipped > 0 || len(resp.Files) > limit
+
+	for _, file := range resp.Files {
+		repoRev, ok := repoMap[api.RepoName(strings.ToLower(file.Repository))]
+		if !ok {
+			continue
+		}
+		inputRev := repoRev.RevSpecs()[0]
+		fm := &fileMatchResolver{
+			JPath:     file.FileName,
+			uri:       fileMatchURI(repoRev.Repo.Name, inputRev, file.FileName),
+			repo:      repoRev.Repo,
+			commitID:  repoRev.IndexedHEADCommit(),
+			zoektLang: file.Language,
+		}
+		if inputRev != "" {
+			fm.inputRev = &inputRev
+		}
+		matches = append(matches, fm)
+	}
+	return matches, limitHit, nil
+}
@@ -0,0 +1,83 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/zoekt"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+)
+
+func TestSearchFilenamesInRepos_indexed(t *testing.T) {
+	z := &searchbackend.Zoekt{Client: &fakeSearcher{
+		repos: &zoekt.RepoList{Repos: []*zoekt.RepoListEntry{{
+			Repository: zoekt.Repository{
+				Name:     "foo/bar",
+				Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: "deadbeef"}},
+			},
+		}}},
+		result: &zoekt.SearchResult{Files: []zoekt.FileMatch{
+			{Repository: "foo/bar", FileName: "b/Dockerfile"},
+			{Repository: "foo/bar", FileName: "a/Dockerfile"},
+			{Repository: "foo/bar", FileName: "c/Dockerfile"},
+		}},
+	}, DisableCache: true}
+
+	args := &search.Args{
+		Pattern: &search.PatternInfo{Pattern: "Dockerfile", PathPatternsAreRegExps: true},
+		Repos: []*search.RepositoryRevisions{{
+			Repo: &types.Repo{Name: "foo/bar"},
+			Revs: []search.RevisionSpecifier{{RevSpec: ""}},
+		}},
+		Zoekt: z,
+	}
+	matches, common, err := searchFilenamesInRepos(context.Background(), args, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, fm := range matches {
+		paths = append(paths, fm.JPath)
+		if len(fm.JLineMatches) != 0 {
+			t.Errorf("%s: got line matches %v, want none", fm.JPath, fm.JLineMatches)
+		}
+	}
+	if want := []string{"a/Dockerfile", "b/Dockerfile"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+	if !common.limitHit {
+		t.Error("got no limitHit, want limitHit")
+	}
+	if len(common.indexed) != 1 {
+		t.Errorf("got %d indexed repos, want 1", len(common.indexed))
+	}
+}
+
+func TestCompileFilenamePatterns(t *testing.T) {
+	tests := []struct {
+		pattern search.PatternInfo
+		path    string
+		want    bool
+	}{
+		{pattern: search.PatternInfo{Pattern: "dockerfile"}, path: "a/Dockerfile", want: true},
+		{pattern: search.PatternInfo{Pattern: "dockerfile", IsCaseSensitive: true}, path: "a/Dockerfile", want: false},
+		{pattern: search.PatternInfo{Pattern: "a.b"}, path: "axb", want: false},
+		{pattern: search.PatternInfo{Pattern: "a.b", IsRegExp: true}, path: "axb", want: true},
+		{pattern: search.PatternInfo{Pattern: "Dockerfile", IncludePatterns: []string{"^cmd/"}, PathPatternsAreRegExps: true}, path: "a/Dockerfile", want: false},
+		{pattern: search.PatternInfo{Pattern: "Dockerfile", ExcludePattern: "^a/", PathPatternsAreRegExps: true}, path: "a/Dockerfile", want: false},
+		{pattern: search.PatternInfo{Pattern: "Dockerfile", ExcludePattern: "^a/", PathPatternsAreRegExps: true}, path: "b/Dockerfile", want: true},
+	}
+	for _, test := range tests {
+		matchPattern, matchPath, err := compileFilenamePatterns(&test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := matchPattern.MatchPath(test.path) && matchPath.MatchPath(test.path); got != test.want {
+			t.Errorf("pattern %+v, path %q: got %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
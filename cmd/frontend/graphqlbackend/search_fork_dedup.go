@@ -0,0 +1,96 @@
+package graphqlbackend
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// dedupForkFileMatches collapses file matches in forks (and mirrors) that are
+// identical to a file match in another repository into that file match, where
+// they are listed as forkDuplicates. Two file matches are identical if they
+// are for the same path and have the same line matches.
+//
+// Identical file matches are only collapsed if at least one of them is in a
+// fork, so that e.g. vendored files in unrelated repositories are still all
+// listed. The match in a non-fork repository (or else the first one) is kept.
+//
+// The order of results is preserved.
+func dedupForkFileMatches(results []searchResultResolver) []searchResultResolver {
+	groups := map[[sha256.Size]byte][]*fileMatchResolver{}
+	for _, result := range results {
+		fm, ok := result.ToFileMatch()
+		if !ok || len(fm.JLineMatches) == 0 {
+			continue
+		}
+		h := fm.contentHash()
+		groups[h] = append(groups[h], fm)
+	}
+
+	collapsed := map[*fileMatchResolver]bool{}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+
+		var kept *fileMatchResolver
+		forks := 0
+		for _, fm := range group {
+			if fm.inFork() {
+				forks++
+			} else if kept == nil {
+				kept = fm
+			}
+		}
+		if forks == 0 {
+			continue
+		}
+		if kept == nil {
+			kept = group[0]
+		}
+
+		for _, fm := range group {
+			if fm != kept {
+				kept.forkDuplicates = append(kept.forkDuplicates, fm)
+				collapsed[fm] = true
+			}
+		}
+	}
+	if len(collapsed) == 0 {
+		return results
+	}
+
+	deduped := results[:0]
+	for _, result := range results {
+		if fm, ok := result.ToFileMatch(); ok && collapsed[fm] {
+			continue
+		}
+		deduped = append(deduped, result)
+	}
+	return deduped
+}
+
+// contentHash returns a hash of the path and line matches of fm, which is the
+// same for the file matches of an identical file in different repositories.
+func (fm *fileMatchResolver) contentHash() (sum [sha256.Size]byte) {
+	h := sha256.New()
+	writeString := func(s string) {
+		_ = binary.Write(h, binary.LittleEndian, int64(len(s)))
+		_, _ = io.WriteString(h, s)
+	}
+
+	writeString(fm.JPath)
+	for _, lm := range fm.JLineMatches {
+		_ = binary.Write(h, binary.LittleEndian, lm.JLineNumber)
+		writeString(lm.JPreview)
+		for _, ol := range lm.JOffsetAndLengths {
+			_ = binary.Write(h, binary.LittleEndian, ol)
+		}
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+func (fm *fileMatchResolver) inFork() bool {
+	return fm.repo != nil && fm.repo.RepoFields != nil && fm.repo.Fork
+}
@@ -0,0 +1,53 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestDedupForkFileMatches(t *testing.T) {
+	repo := func(name string, fork bool) *types.Repo {
+		return &types.Repo{Name: api.RepoName(name), RepoFields: &types.RepoFields{Fork: fork}}
+	}
+	fileMatch := func(repo *types.Repo, path, preview string) *fileMatchResolver {
+		return &fileMatchResolver{
+			JPath:        path,
+			JLineMatches: []*lineMatch{{JPreview: preview, JLineNumber: 1, JOffsetAndLengths: [][2]int32{{0, 3}}}},
+			uri:          "git://" + string(repo.Name) + "#" + path,
+			repo:         repo,
+		}
+	}
+
+	var (
+		fork1    = repo("github.com/a/foo", true)
+		upstream = repo("github.com/b/foo", false)
+		fork2    = repo("github.com/c/foo", true)
+		other    = repo("github.com/d/bar", false)
+
+		fork1Match    = fileMatch(fork1, "main.go", "foo()")
+		upstreamMatch = fileMatch(upstream, "main.go", "foo()")
+		fork2Match    = fileMatch(fork2, "main.go", "foo()")
+		changedMatch  = fileMatch(fork2, "main.go", "foo(1)")
+		otherMatch    = fileMatch(other, "main.go", "foo()")
+		vendoredMatch = fileMatch(upstream, "vendor/x.go", "foo()")
+		vendoredOther = fileMatch(other, "vendor/x.go", "foo()")
+	)
+	otherMatch.JPath = "cmd/main.go"
+
+	results := []searchResultResolver{fork1Match, upstreamMatch, fork2Match, changedMatch, otherMatch, vendoredMatch, vendoredOther}
+	got := dedupForkFileMatches(results)
+
+	want := []searchResultResolver{upstreamMatch, changedMatch, otherMatch, vendoredMatch, vendoredOther}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %d results, want %d", len(got), len(want))
+	}
+	if want := []*fileMatchResolver{fork1Match, fork2Match}; !reflect.DeepEqual(upstreamMatch.forkDuplicates, want) {
+		t.Errorf("got %d fork duplicates, want %d", len(upstreamMatch.forkDuplicates), len(want))
+	}
+	if len(vendoredMatch.forkDuplicates) != 0 {
+		t.Error("identical matches in non-forks were collapsed")
+	}
+}
@@ -0,0 +1,159 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+)
+
+// fuzzyCandidateLimit is the minimum number of paths that a fuzzy search
+// fetches to rank, so that the best matches are among the returned results
+// even if fewer results are requested.
+const fuzzyCandidateLimit = 1000
+
+// Scores of the characters of a fuzzy search term matched in a path. Matches
+// at the start of path components and words are preferred, as are
+// consecutive matches and matches in the file name, like in fzf.
+const (
+	fuzzyScoreMatch        = 16
+	fuzzyScoreGapStart     = -3
+	fuzzyScoreGapExtension = -1
+
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusCamelCase   = 7
+	fuzzyBonusConsecutive = 4
+	fuzzyBonusFileName    = 2
+)
+
+// validateFuzzyQuery returns an error if the query can't be run as a fuzzy
+// search, which only matches paths.
+func validateFuzzyQuery(q *query.Query) error {
+	for _, v := range q.Values(query.FieldType) {
+		if t := asString(v); t != "path" {
+			return fmt.Errorf("patterntype:fuzzy only supports type:path, not type:%s", t)
+		}
+	}
+	if len(q.Values(query.FieldReplace)) > 0 {
+		return fmt.Errorf("patterntype:fuzzy doesn't support replace:")
+	}
+	return nil
+}
+
+// rankFuzzyResults sorts the results of a fuzzy search by how well their
+// paths match the terms, best first. Ties are broken by preferring shorter
+// paths, and then by the usual order of results.
+func rankFuzzyResults(results []searchResultResolver, terms []string) {
+	scores := make(map[searchResultResolver]int, len(results))
+	for _, r := range results {
+		_, path := r.searchResultURIs()
+		score := 0
+		for _, term := range terms {
+			s := fuzzyScore(term, path)
+			if s == math.MinInt32 {
+				score = math.MinInt32
+				break
+			}
+			score += s
+		}
+		scores[r] = score
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		_, apath := a.searchResultURIs()
+		_, bpath := b.searchResultURIs()
+		if len(apath) != len(bpath) {
+			return len(apath) < len(bpath)
+		}
+		return compareSearchResults(a, b)
+	})
+}
+
+// fuzzyScore returns the score of the best match of the characters of term in
+// path, in order and ignoring case, or math.MinInt32 if they don't match.
+func fuzzyScore(term, path string) int {
+	t := []rune(strings.ToLower(term))
+	p := []rune(path)
+	if len(t) == 0 {
+		return 0
+	}
+	if len(t) > len(p) {
+		return math.MinInt32
+	}
+
+	fileName := strings.LastIndex(path, "/") + 1
+	bonus := make([]int, len(p))
+	lower := make([]rune, len(p))
+	fileNameStart := len([]rune(path[:fileName]))
+	for j, r := range p {
+		lower[j] = unicode.ToLower(r)
+		switch {
+		case j == 0 || strings.ContainsRune("/_-. ", p[j-1]):
+			bonus[j] = fuzzyBonusBoundary
+		case unicode.IsUpper(r) && unicode.IsLower(p[j-1]):
+			bonus[j] = fuzzyBonusCamelCase
+		}
+		if j >= fileNameStart {
+			bonus[j] += fuzzyBonusFileName
+		}
+	}
+
+	const none = math.MinInt32 / 2
+
+	// prev[j] is the best score of matching the previous characters of the
+	// term with the last one at p[j].
+	prev := make([]int, len(p))
+	cur := make([]int, len(p))
+	for j := range p {
+		prev[j] = none
+		if lower[j] == t[0] {
+			prev[j] = fuzzyScoreMatch + bonus[j]
+		}
+	}
+
+	for i := 1; i < len(t); i++ {
+		// gapped is the best score of a match of the previous characters
+		// that ended before p[j-1], with the gap up to p[j] penalized.
+		gapped := none
+		for j := range p {
+			cur[j] = none
+			if j == 0 {
+				continue
+			}
+			if lower[j] == t[i] {
+				best := gapped
+				if prev[j-1] != none && prev[j-1]+fuzzyBonusConsecutive > best {
+					best = prev[j-1] + fuzzyBonusConsecutive
+				}
+				if best != none {
+					cur[j] = best + fuzzyScoreMatch + bonus[j]
+				}
+			}
+			if gapped != none {
+				gapped += fuzzyScoreGapExtension
+			}
+			if prev[j-1] != none && prev[j-1]+fuzzyScoreGapStart > gapped {
+				gapped = prev[j-1] + fuzzyScoreGapStart
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	score := none
+	for _, s := range prev {
+		if s > score {
+			score = s
+		}
+	}
+	if score == none {
+		return math.MinInt32
+	}
+	return score
+}
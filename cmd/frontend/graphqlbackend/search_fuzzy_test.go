@@ -0,0 +1,71 @@
+package graphqlbackend
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	// Each term is expected to match the paths better in the given order.
+	tests := []struct {
+		term  string
+		paths []string
+	}{
+		{"main", []string{"cmd/frontend/main.go", "maintainers/README.md"}},
+		{"main", []string{"cmd/frontend/main.go", "cmd/frontend/internal/domain.go"}},
+		{"sr", []string{"search_results.go", "cmd/searcher/README.md", "user.go"}},
+		{"fm", []string{"FileMatch.go", "fullmoon.go"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.term, func(t *testing.T) {
+			for i := 1; i < len(test.paths); i++ {
+				better, worse := test.paths[i-1], test.paths[i]
+				if a, b := fuzzyScore(test.term, better), fuzzyScore(test.term, worse); a <= b {
+					t.Errorf("fuzzyScore(%q, %q) = %d, want more than fuzzyScore(%q, %q) = %d", test.term, better, a, test.term, worse, b)
+				}
+			}
+		})
+	}
+
+	if s := fuzzyScore("xyz", "abc"); s != math.MinInt32 {
+		t.Errorf("fuzzyScore of non-matching term = %d, want %d", s, math.MinInt32)
+	}
+	if s := fuzzyScore("MAIN", "main.go"); s != fuzzyScore("main", "main.go") {
+		t.Errorf("fuzzyScore is not case-insensitive")
+	}
+}
+
+func TestRankFuzzyResults(t *testing.T) {
+	fileMatch := func(repo, path string) *fileMatchResolver {
+		return &fileMatchResolver{
+			JPath: path,
+			uri:   "git://" + repo + "#" + path,
+			repo:  &types.Repo{Name: api.RepoName(repo)},
+		}
+	}
+
+	var (
+		a = fileMatch("github.com/a/a", "internal/domain.go")
+		b = fileMatch("github.com/a/a", "cmd/frontend/main.go")
+		c = fileMatch("github.com/b/b", "cmd/frontend/main.go")
+		d = fileMatch("github.com/a/a", "main.go")
+	)
+
+	results := []searchResultResolver{a, b, c, d}
+	rankFuzzyResults(results, query.FuzzyTerms("repo:a main"))
+
+	if want := []searchResultResolver{d, b, c, a}; !reflect.DeepEqual(results, want) {
+		var have []string
+		for _, r := range results {
+			repo, path := r.searchResultURIs()
+			have = append(have, repo+"/"+path)
+		}
+		t.Errorf("unexpected order %v", have)
+	}
+}
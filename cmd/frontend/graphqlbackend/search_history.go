@@ -0,0 +1,170 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/impersonation"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// addToSearchHistory records the search in the search history of the
+// current user. Searches by unauthenticated users and by site admins
+// impersonating the user are not recorded. It doesn't block.
+func (r *searchResolver) addToSearchHistory(ctx context.Context, rr *searchResultsResolver) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() || impersonation.Impersonator(ctx) != 0 {
+		return
+	}
+
+	e := &db.SearchHistoryEntry{
+		UserID:      a.UID,
+		Query:       r.originalQuery,
+		Version:     r.version,
+		PatternType: r.patternType,
+		ResultCount: rr.MatchCount(),
+	}
+	goroutine.Go(func() {
+		// The search request may be done before the search is recorded, so
+		// don't use its context.
+		if err := db.SearchHistory.Add(context.Background(), e); err != nil {
+			log15.Warn("failed to add search to search history", "error", err)
+		}
+	})
+}
+
+func (r *schemaResolver) ResumeSearch(ctx context.Context, args *struct {
+	ID graphql.ID
+}) (searchIntf, error) {
+	id, err := unmarshalSearchHistoryEntryID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only the user and site admins may run a search from the
+	// user's search history. The entries of other users are reported as not
+	// found, so that it can't be told whether an entry with the ID exists.
+	isSiteAdmin := false
+	switch err := backend.CheckCurrentUserIsSiteAdmin(ctx); err {
+	case nil:
+		isSiteAdmin = true
+	case backend.ErrMustBeSiteAdmin:
+	default:
+		return nil, err
+	}
+	entry, err := db.SearchHistory.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isSiteAdmin && entry.UserID != actor.FromContext(ctx).UID {
+		return nil, db.ErrSearchHistoryEntryNotFound
+	}
+
+	patternType := entry.PatternType
+	return r.Search(&searchArgs{
+		Version:     entry.Version,
+		PatternType: &patternType,
+		Query:       entry.Query,
+	})
+}
+
+func (r *UserResolver) SearchHistory(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*searchHistoryConnectionResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins can view the user's search history.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	opt := db.SearchHistoryListOptions{UserID: r.user.ID}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &searchHistoryConnectionResolver{opt: opt}, nil
+}
+
+// searchHistoryConnectionResolver resolves a list of search history entries.
+//
+// 🚨 SECURITY: When instantiating a searchHistoryConnectionResolver value, the
+// caller MUST check permissions.
+type searchHistoryConnectionResolver struct {
+	opt db.SearchHistoryListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*db.SearchHistoryEntry
+	err     error
+}
+
+func (r *searchHistoryConnectionResolver) compute(ctx context.Context) ([]*db.SearchHistoryEntry, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = db.SearchHistory.List(ctx, opt2)
+	})
+	return r.entries, r.err
+}
+
+func (r *searchHistoryConnectionResolver) Nodes(ctx context.Context) ([]*searchHistoryEntryResolver, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(entries) > r.opt.LimitOffset.Limit {
+		entries = entries[:r.opt.LimitOffset.Limit]
+	}
+
+	resolvers := make([]*searchHistoryEntryResolver, len(entries))
+	for i, e := range entries {
+		resolvers[i] = &searchHistoryEntryResolver{entry: e}
+	}
+	return resolvers, nil
+}
+
+func (r *searchHistoryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.SearchHistory.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *searchHistoryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(entries) > r.opt.Limit), nil
+}
+
+type searchHistoryEntryResolver struct {
+	entry *db.SearchHistoryEntry
+}
+
+func marshalSearchHistoryEntryID(id int64) graphql.ID {
+	return relay.MarshalID("SearchHistoryEntry", id)
+}
+
+func unmarshalSearchHistoryEntryID(id graphql.ID) (entryID int64, err error) {
+	err = relay.UnmarshalSpec(id, &entryID)
+	return
+}
+
+func (r *searchHistoryEntryResolver) ID() graphql.ID { return marshalSearchHistoryEntryID(r.entry.ID) }
+
+func (r *searchHistoryEntryResolver) Query() string { return r.entry.Query }
+
+func (r *searchHistoryEntryResolver) Version() string { return r.entry.Version }
+
+func (r *searchHistoryEntryResolver) PatternType() string { return r.entry.PatternType }
+
+func (r *searchHistoryEntryResolver) ResultCount() int32 { return r.entry.ResultCount }
+
+func (r *searchHistoryEntryResolver) CreatedAt() DateTime { return DateTime{Time: r.entry.CreatedAt} }
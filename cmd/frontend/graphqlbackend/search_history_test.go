@@ -0,0 +1,61 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestResumeSearch(t *testing.T) {
+	resetMocks()
+	db.Mocks.SearchHistory.GetByID = func(id int64) (*db.SearchHistoryEntry, error) {
+		if id != 3 {
+			return nil, db.ErrSearchHistoryEntryNotFound
+		}
+		return &db.SearchHistoryEntry{ID: id, UserID: 1, Query: "foo.*bar", Version: "V2", PatternType: "regexp"}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	defer resetMocks()
+
+	id := marshalSearchHistoryEntryID(3)
+
+	t.Run("other user", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+		// Entries of other users can't be told apart from missing ones.
+		for _, id := range []graphql.ID{id, marshalSearchHistoryEntryID(4)} {
+			if _, err := (&schemaResolver{}).ResumeSearch(ctx, &struct{ ID graphql.ID }{ID: id}); err != db.ErrSearchHistoryEntryNotFound {
+				t.Errorf("got error %v resuming search %s, want %v", err, id, db.ErrSearchHistoryEntryNotFound)
+			}
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		if _, err := (&schemaResolver{}).ResumeSearch(context.Background(), &struct{ ID graphql.ID }{ID: id}); err == nil {
+			t.Fatal("expected error resuming a search without a user")
+		}
+	})
+
+	t.Run("same user", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		s, err := (&schemaResolver{}).ResumeSearch(ctx, &struct{ ID graphql.ID }{ID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr, ok := s.(*searchResolver)
+		if !ok {
+			t.Fatalf("got %T, want *searchResolver", s)
+		}
+		if sr.originalQuery != "foo.*bar" || sr.version != "V2" || sr.patternType != "regexp" {
+			t.Errorf("got query %q, version %q, pattern type %q", sr.originalQuery, sr.version, sr.patternType)
+		}
+	})
+}
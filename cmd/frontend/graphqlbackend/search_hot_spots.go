@@ -0,0 +1,202 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/searchhotspots"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// recordSearchHotSpots records the repositories, directories and revisions
+// that the results are in (see package searchhotspots). It doesn't block.
+func recordSearchHotSpots(results []searchResultResolver) {
+	hits := make([]searchhotspots.Hit, 0, len(results))
+	for _, result := range results {
+		if fm, ok := result.ToFileMatch(); ok && fm.repo != nil {
+			var rev string
+			if fm.inputRev != nil {
+				rev = *fm.inputRev
+			}
+			hits = append(hits, searchhotspots.Hit{Repo: fm.repo.Name, Rev: rev, Path: fm.JPath})
+		} else if r, ok := result.ToRepository(); ok {
+			hits = append(hits, searchhotspots.Hit{Repo: r.repo.Name})
+		} else if c, ok := result.ToCommitSearchResult(); ok {
+			hits = append(hits, searchhotspots.Hit{Repo: c.commit.repo.repo.Name})
+		}
+	}
+	if len(hits) == 0 {
+		return
+	}
+
+	goroutine.Go(func() {
+		if err := searchhotspots.Record(hits); err != nil {
+			log15.Warn("failed to record search hot spots", "error", err)
+		}
+	})
+}
+
+func (r *siteResolver) SearchHotSpots(ctx context.Context, args *struct {
+	First *int32
+}) ([]*searchHotSpotResolver, error) {
+	// 🚨 SECURITY: Only site admins may see which code is searched the most.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	first := 20
+	if args.First != nil {
+		first = int(*args.First)
+	}
+	hotSpots, err := searchhotspots.List(first)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*searchHotSpotResolver, 0, len(hotSpots))
+	for _, h := range hotSpots {
+		repo, err := db.Repos.GetByName(ctx, h.Repo)
+		if errcode.IsNotFound(err) {
+			continue // the repository was deleted since
+		} else if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, &searchHotSpotResolver{repo: &RepositoryResolver{repo: repo}, hotSpot: h})
+	}
+	return resolvers, nil
+}
+
+type searchHotSpotResolver struct {
+	repo    *RepositoryResolver
+	hotSpot *searchhotspots.HotSpot
+}
+
+func (r *searchHotSpotResolver) Repository() *RepositoryResolver { return r.repo }
+
+func (r *searchHotSpotResolver) Searches() int32 { return int32(r.hotSpot.Searches) }
+
+func (r *searchHotSpotResolver) Paths() []*searchHotSpotCountResolver {
+	return toSearchHotSpotCountResolvers(r.hotSpot.Paths)
+}
+
+func (r *searchHotSpotResolver) Revisions() []*searchHotSpotCountResolver {
+	return toSearchHotSpotCountResolvers(r.hotSpot.Revs)
+}
+
+type searchHotSpotCountResolver struct {
+	count searchhotspots.Count
+}
+
+func toSearchHotSpotCountResolvers(counts []searchhotspots.Count) []*searchHotSpotCountResolver {
+	resolvers := make([]*searchHotSpotCountResolver, len(counts))
+	for i, c := range counts {
+		resolvers[i] = &searchHotSpotCountResolver{count: c}
+	}
+	return resolvers
+}
+
+func (r *searchHotSpotCountResolver) Value() string { return r.count.Value }
+
+func (r *searchHotSpotCountResolver) Searches() int32 { return int32(r.count.Searches) }
+
+const (
+	// searcherWarmInterval is how often the searcher caches are warmed.
+	searcherWarmInterval = 30 * time.Minute
+
+	// searcherWarmRepos and searcherWarmRevs are the number of hot spots, and
+	// of revisions of each, whose archives are fetched into the searcher
+	// caches.
+	searcherWarmRepos = 50
+	searcherWarmRevs  = 3
+)
+
+// WarmSearcherCaches periodically makes the searchers fetch the archives of
+// the most searched revisions of the repositories that are the most often in
+// search results (see package searchhotspots), so that the next searches of
+// them don't have to wait for the archives to be fetched. It runs until ctx
+// is done.
+func WarmSearcherCaches(ctx context.Context) {
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+	for {
+		warmSearcherCaches(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(searcherWarmInterval):
+		}
+	}
+}
+
+func warmSearcherCaches(ctx context.Context) {
+	hotSpots, err := searchhotspots.List(searcherWarmRepos)
+	if err != nil {
+		log15.Warn("failed to list search hot spots to warm searcher caches", "error", err)
+		return
+	}
+
+	// The default branches of indexed repositories are searched with zoekt.
+	var indexed map[string]bool
+	if z := search.Indexed(); z.Enabled() {
+		listCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		set, err := z.ListAll(listCtx)
+		cancel()
+		if err != nil {
+			log15.Warn("failed to list indexed repositories to warm searcher caches", "error", err)
+			return
+		}
+		indexed = make(map[string]bool, len(set))
+		for name := range set {
+			indexed[name] = true
+		}
+	}
+
+	searcherURLs := search.SearcherURLs()
+	for _, h := range hotSpots {
+		revs := h.Revs
+		if len(revs) > searcherWarmRevs {
+			revs = revs[:searcherWarmRevs]
+		}
+		for _, rev := range revs {
+			if ctx.Err() != nil {
+				return
+			}
+			if rev.Value == "HEAD" && indexed[strings.ToLower(string(h.Repo))] {
+				continue
+			}
+			if err := warmSearcherCache(ctx, searcherURLs, h.Repo, rev.Value); err != nil {
+				log15.Debug("failed to warm searcher cache", "repo", h.Repo, "rev", rev.Value, "error", err)
+			}
+		}
+	}
+}
+
+// warmSearcherCache makes the searcher that repo@rev is searched on fetch its
+// archive, with a search that stops at the first file.
+func warmSearcherCache(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName, rev string) error {
+	gitserverRepo := gitserver.Repo{Name: repo}
+	commit, err := git.ResolveRevision(ctx, gitserverRepo, nil, rev, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return err
+	}
+
+	p := &search.PatternInfo{
+		Pattern:            ".",
+		IsRegExp:           true,
+		FileMatchLimit:     1,
+		PatternMatchesPath: true,
+	}
+	_, _, err = textSearch(ctx, searcherURLs, gitserverRepo, commit, p, time.Minute, textSearchHints{ExpectedReuse: true})
+	return err
+}
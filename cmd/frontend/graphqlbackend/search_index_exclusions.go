@@ -0,0 +1,174 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	zoektquery "github.com/google/zoekt/query"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+const searchIndexExclusionIDKind = "SearchIndexExclusion"
+
+func marshalSearchIndexExclusionID(id int32) graphql.ID {
+	return relay.MarshalID(searchIndexExclusionIDKind, id)
+}
+
+func unmarshalSearchIndexExclusionID(id graphql.ID) (exclusionID int32, err error) {
+	if kind := relay.UnmarshalKind(id); kind != searchIndexExclusionIDKind {
+		err = fmt.Errorf("expected graphql ID to have kind %q; got %q", searchIndexExclusionIDKind, kind)
+		return
+	}
+	err = relay.UnmarshalSpec(id, &exclusionID)
+	return
+}
+
+func (r *schemaResolver) SearchIndexExclusions(ctx context.Context) (*searchIndexExclusionConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may list search index exclusions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	exclusions, err := db.SearchIndexExclusions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &searchIndexExclusionConnectionResolver{exclusions: exclusions}, nil
+}
+
+func (r *schemaResolver) AddSearchIndexExclusion(ctx context.Context, args *struct {
+	Repository  *graphql.ID
+	PathPattern *string
+}) (*searchIndexExclusionResolver, error) {
+	// 🚨 SECURITY: Only site admins may exclude repositories or files from indexed search.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if (args.Repository == nil) == (args.PathPattern == nil) {
+		return nil, errors.New("exactly one of repository and pathPattern must be given")
+	}
+
+	exclusion := &types.SearchIndexExclusion{}
+	if args.Repository != nil {
+		repo, err := repositoryByID(ctx, *args.Repository)
+		if err != nil {
+			return nil, err
+		}
+		exclusion.RepoID = repo.repo.ID
+		exclusion.IndexBytes, err = indexByteSize(ctx, repo.repo)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := filepath.Match(*args.PathPattern, ""); err != nil || *args.PathPattern == "" {
+			return nil, fmt.Errorf("invalid path pattern %q", *args.PathPattern)
+		}
+		exclusion.PathPattern = *args.PathPattern
+	}
+
+	if err := db.SearchIndexExclusions.Create(ctx, exclusion); err != nil {
+		return nil, err
+	}
+	return &searchIndexExclusionResolver{exclusion: exclusion}, nil
+}
+
+// indexByteSize returns the byte size of the index of the repository, or 0 if
+// it is not indexed.
+func indexByteSize(ctx context.Context, repo *types.Repo) (int64, error) {
+	if !search.Indexed().Enabled() {
+		return 0, nil
+	}
+	repoList, err := search.Indexed().Client.List(ctx, zoektquery.NewRepoSet(string(repo.Name)))
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, entry := range repoList.Repos {
+		size += entry.Stats.IndexBytes + entry.Stats.ContentBytes
+	}
+	return size, nil
+}
+
+func (r *schemaResolver) DeleteSearchIndexExclusion(ctx context.Context, args *struct {
+	SearchIndexExclusion graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may delete search index exclusions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalSearchIndexExclusionID(args.SearchIndexExclusion)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SearchIndexExclusions.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+type searchIndexExclusionConnectionResolver struct {
+	exclusions []*types.SearchIndexExclusion
+}
+
+func (r *searchIndexExclusionConnectionResolver) Nodes() []*searchIndexExclusionResolver {
+	resolvers := make([]*searchIndexExclusionResolver, 0, len(r.exclusions))
+	for _, e := range r.exclusions {
+		resolvers = append(resolvers, &searchIndexExclusionResolver{exclusion: e})
+	}
+	return resolvers
+}
+
+func (r *searchIndexExclusionConnectionResolver) TotalCount() int32 {
+	return int32(len(r.exclusions))
+}
+
+func (r *searchIndexExclusionConnectionResolver) SavedIndexByteSize() int32 {
+	var size int64
+	for _, e := range r.exclusions {
+		size += e.IndexBytes
+	}
+	return int32(size)
+}
+
+type searchIndexExclusionResolver struct {
+	exclusion *types.SearchIndexExclusion
+}
+
+func (r *searchIndexExclusionResolver) ID() graphql.ID {
+	return marshalSearchIndexExclusionID(r.exclusion.ID)
+}
+
+func (r *searchIndexExclusionResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	if r.exclusion.RepoID == 0 {
+		return nil, nil
+	}
+	return RepositoryByIDInt32(ctx, r.exclusion.RepoID)
+}
+
+func (r *searchIndexExclusionResolver) PathPattern() *string {
+	if r.exclusion.PathPattern == "" {
+		return nil
+	}
+	return &r.exclusion.PathPattern
+}
+
+func (r *searchIndexExclusionResolver) SavedIndexByteSize() *int32 {
+	if r.exclusion.RepoID == 0 {
+		return nil
+	}
+	size := int32(r.exclusion.IndexBytes)
+	return &size
+}
+
+func (r *searchIndexExclusionResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.exclusion.CreatedAt}
+}
@@ -0,0 +1,101 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestSearchIndexExclusions(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	createdAt := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	db.Mocks.SearchIndexExclusions.Create = func(e *types.SearchIndexExclusion) error {
+		e.ID = 2
+		e.CreatedAt = createdAt
+		return nil
+	}
+	db.Mocks.SearchIndexExclusions.List = func() ([]*types.SearchIndexExclusion, error) {
+		return []*types.SearchIndexExclusion{
+			{ID: 1, RepoID: 1, IndexBytes: 1000, CreatedAt: createdAt},
+			{ID: 2, PathPattern: "**/*.min.js", CreatedAt: createdAt},
+		}, nil
+	}
+	db.Mocks.Repos.MockGet(t, 1)
+	defer resetMocks()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  mustParseGraphQLSchema(t, nil),
+			Query: `
+				mutation {
+					addSearchIndexExclusion(pathPattern: "**/*.min.js") {
+						id
+						pathPattern
+						savedIndexByteSize
+						createdAt
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"addSearchIndexExclusion": {
+						"id": "U2VhcmNoSW5kZXhFeGNsdXNpb246Mg==",
+						"pathPattern": "**/*.min.js",
+						"savedIndexByteSize": null,
+						"createdAt": "2019-11-01T00:00:00Z"
+					}
+				}
+			`,
+		},
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  mustParseGraphQLSchema(t, nil),
+			Query: `
+				{
+					searchIndexExclusions {
+						nodes {
+							repository {
+								id
+							}
+							pathPattern
+							savedIndexByteSize
+						}
+						totalCount
+						savedIndexByteSize
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"searchIndexExclusions": {
+						"nodes": [
+							{
+								"repository": {
+									"id": "UmVwb3NpdG9yeTox"
+								},
+								"pathPattern": null,
+								"savedIndexByteSize": 1000
+							},
+							{
+								"repository": null,
+								"pathPattern": "**/*.min.js",
+								"savedIndexByteSize": null
+							}
+						],
+						"totalCount": 2,
+						"savedIndexByteSize": 1000
+					}
+				}
+			`,
+		},
+	})
+}
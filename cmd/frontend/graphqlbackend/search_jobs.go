@@ -0,0 +1,454 @@
+package graphqlbackend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Search jobs are exhaustive searches that find all results of a query (as if
+// it had "count:all"), for audits that must be complete. They run in the
+// background (see RunSearchJobs), a batch of repositories at a time, without
+// the limits of interactive searches. The results and progress of each batch
+// are persisted, so that a job resumes with the next batch after a restart.
+
+const (
+	// searchJobPollInterval is how often queued search jobs are polled for
+	// when there are none.
+	searchJobPollInterval = 10 * time.Second
+
+	// searchJobBatchSize is the number of repositories that a search job
+	// searches at a time.
+	searchJobBatchSize = 25
+
+	// searchJobBatchTimeout is how long the search of a batch may take.
+	// Repositories whose search timed out are listed as incomplete.
+	searchJobBatchTimeout = 5 * time.Minute
+
+	// searchJobStaleAfter is how long a processing search job may make no
+	// progress before it is resumed by another frontend (e.g. because the
+	// frontend running it was restarted). It must be longer than
+	// searchJobBatchTimeout.
+	searchJobStaleAfter = 3 * searchJobBatchTimeout
+)
+
+// RunSearchJobs runs the queued search jobs, one at a time. It runs until ctx
+// is done.
+func RunSearchJobs(ctx context.Context) {
+	for {
+		job, err := db.SearchJobs.Dequeue(ctx, searchJobStaleAfter)
+		if err != nil {
+			log15.Error("failed to dequeue search job", "error", err)
+		}
+		if job != nil {
+			if err := runSearchJob(ctx, job); err != nil && err != db.ErrSearchJobNotProcessing {
+				log15.Warn("search job failed", "id", job.ID, "error", err)
+				if err := db.SearchJobs.Finish(ctx, job.ID, err.Error()); err != nil && err != db.ErrSearchJobNotProcessing {
+					log15.Error("failed to record search job failure", "id", job.ID, "error", err)
+				}
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(searchJobPollInterval):
+		}
+	}
+}
+
+// searchJobRepoRevs is a repository revision that a search job searches, as
+// persisted in the job.
+type searchJobRepoRevs struct {
+	RepoID   api.RepoID
+	RepoName api.RepoName
+	Revs     []search.RevisionSpecifier
+}
+
+// runSearchJob searches the repositories of the search job that were not
+// searched yet. If the job was canceled, db.ErrSearchJobNotProcessing is
+// returned.
+func runSearchJob(ctx context.Context, job *types.SearchJob) error {
+	// 🚨 SECURITY: The search job only searches the repositories that its user
+	// may access.
+	ctx = actor.WithActor(ctx, &actor.Actor{UID: job.UserID})
+
+	sr, err := newExhaustiveSearchResolver(job.Query, job.PatternType)
+	if err != nil {
+		return err
+	}
+
+	var repoRevs []*searchJobRepoRevs
+	if job.RepoRevs == nil {
+		var missingRepos []string
+		repoRevs, missingRepos, err = resolveSearchJobRepoRevs(ctx, sr)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(repoRevs)
+		if err != nil {
+			return err
+		}
+		if err := db.SearchJobs.SetRepoRevs(ctx, job.ID, data, missingRepos); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(job.RepoRevs, &repoRevs); err != nil {
+		return err
+	}
+
+	for offset := job.ReposSearched; offset < len(repoRevs); offset += searchJobBatchSize {
+		end := offset + searchJobBatchSize
+		if end > len(repoRevs) {
+			end = len(repoRevs)
+		}
+		results, resultCount, incompleteRepos, err := searchSearchJobBatch(ctx, sr, repoRevs[offset:end])
+		if err != nil {
+			return err
+		}
+		if err := db.SearchJobs.AddResults(ctx, job.ID, offset, end-offset, resultCount, incompleteRepos, results); err != nil {
+			return err
+		}
+	}
+	return db.SearchJobs.Finish(ctx, job.ID, "")
+}
+
+// newExhaustiveSearchResolver returns a resolver for an exhaustive search of
+// the query.
+func newExhaustiveSearchResolver(queryString, patternType string) (*searchResolver, error) {
+	s, err := (&schemaResolver{}).Search(&searchArgs{Version: "V2", PatternType: &patternType, Query: queryString})
+	if err != nil {
+		return nil, err
+	}
+	sr, ok := s.(*searchResolver)
+	if !ok {
+		if d, ok := s.(*didYouMeanQuotedResolver); ok && d.err != nil {
+			return nil, d.err
+		}
+		return nil, fmt.Errorf("invalid query %q", queryString)
+	}
+	sr.exhaustive = true
+	return sr, nil
+}
+
+// resolveSearchJobRepoRevs resolves the repository revisions that a search job
+// searches. The names of the repositories whose revisions don't exist are
+// returned as missing.
+func resolveSearchJobRepoRevs(ctx context.Context, sr *searchResolver) (repoRevs []*searchJobRepoRevs, missing []string, err error) {
+	resolved, missingRepoRevs, overLimit, err := sr.resolveRepositories(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if overLimit && !sr.maxReposIsSet() {
+		return nil, nil, fmt.Errorf("the query matches more than %d repositories, the most that can be searched; narrow it with repo: filters", maxReposToSearchCeiling())
+	}
+
+	repoRevs = make([]*searchJobRepoRevs, 0, len(resolved))
+	for _, rr := range resolved {
+		repoRevs = append(repoRevs, &searchJobRepoRevs{RepoID: rr.Repo.ID, RepoName: rr.Repo.Name, Revs: rr.Revs})
+	}
+	for _, rr := range missingRepoRevs {
+		missing = append(missing, string(rr.Repo.Name))
+	}
+	return repoRevs, missing, nil
+}
+
+// searchSearchJobBatch searches a batch of the repository revisions of a
+// search job. It returns the results (JSON-encoded, one per line) and the
+// names of the repositories whose results are incomplete.
+func searchSearchJobBatch(ctx context.Context, sr *searchResolver, batch []*searchJobRepoRevs) (results string, resultCount int, incompleteRepos []string, err error) {
+	incomplete := map[api.RepoName]struct{}{}
+
+	repoRevs := make([]*search.RepositoryRevisions, 0, len(batch))
+	for _, rr := range batch {
+		repo, err := db.Repos.Get(ctx, rr.RepoID)
+		if errcode.IsNotFound(err) {
+			// The repository was deleted (or became inaccessible to the
+			// user) since the search job started.
+			incomplete[rr.RepoName] = struct{}{}
+			continue
+		} else if err != nil {
+			return "", 0, nil, err
+		}
+		repoRevs = append(repoRevs, &search.RepositoryRevisions{Repo: repo, Revs: rr.Revs})
+	}
+
+	if len(repoRevs) > 0 {
+		batchResolver := &searchResolver{
+			query:         sr.query,
+			originalQuery: sr.originalQuery,
+			version:       sr.version,
+			patternType:   sr.patternType,
+			exhaustive:    true,
+			repoRevs:      repoRevs,
+			zoekt:         sr.zoekt,
+			searcherURLs:  sr.searcherURLs,
+		}
+
+		batchCtx, cancel := context.WithTimeout(ctx, searchJobBatchTimeout)
+		res, err := batchResolver.doResults(batchCtx, "")
+		cancel()
+		if err != nil {
+			return "", 0, nil, err
+		}
+
+		for _, repos := range [][]*types.Repo{res.cloning, res.missing, res.timedout} {
+			for _, repo := range repos {
+				incomplete[repo.Name] = struct{}{}
+			}
+		}
+		for name := range res.partial {
+			incomplete[name] = struct{}{}
+		}
+
+		results, err = marshalSearchJobResults(res.results)
+		if err != nil {
+			return "", 0, nil, err
+		}
+		for _, result := range res.results {
+			resultCount += int(result.resultCount())
+		}
+	}
+
+	for name := range incomplete {
+		incompleteRepos = append(incompleteRepos, string(name))
+	}
+	sort.Strings(incompleteRepos)
+	return results, resultCount, incompleteRepos, nil
+}
+
+// searchJobResult is a result of a search job, as written to its results
+// download.
+type searchJobResult struct {
+	Type        string               `json:"type"` // "file", "repository" or "commit"
+	Repository  api.RepoName         `json:"repository"`
+	Revision    string               `json:"revision,omitempty"`
+	Commit      string               `json:"commit,omitempty"`
+	Path        string               `json:"path,omitempty"`
+	LineMatches []searchJobLineMatch `json:"lineMatches,omitempty"`
+	URL         string               `json:"url,omitempty"`
+}
+
+type searchJobLineMatch struct {
+	LineNumber int32  `json:"lineNumber"` // 0-based, as in the GraphQL API
+	Preview    string `json:"preview"`
+}
+
+// marshalSearchJobResults encodes the results as newline-delimited JSON
+// objects.
+func marshalSearchJobResults(results []searchResultResolver) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, result := range results {
+		r, ok := toSearchJobResult(result)
+		if !ok {
+			continue
+		}
+		if err := enc.Encode(&r); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// toSearchJobResult converts a search result to its JSON representation. It
+// returns false for results that have none (e.g. codemod results).
+func toSearchJobResult(result searchResultResolver) (searchJobResult, bool) {
+	var r searchJobResult
+	if fm, ok := result.ToFileMatch(); ok && fm.repo != nil {
+		r = searchJobResult{Type: "file", Repository: fm.repo.Name, Commit: string(fm.commitID), Path: fm.JPath}
+		if fm.inputRev != nil {
+			r.Revision = *fm.inputRev
+		}
+		for _, lm := range fm.JLineMatches {
+			r.LineMatches = append(r.LineMatches, searchJobLineMatch{LineNumber: lm.JLineNumber, Preview: lm.JPreview})
+		}
+	} else if repo, ok := result.ToRepository(); ok {
+		r = searchJobResult{Type: "repository", Repository: repo.repo.Name}
+	} else if c, ok := result.ToCommitSearchResult(); ok {
+		r = searchJobResult{Type: "commit", Repository: c.commit.repo.repo.Name, Commit: string(c.commit.oid), URL: c.url}
+	} else {
+		return r, false
+	}
+	return r, true
+}
+
+const searchJobIDKind = "SearchJob"
+
+func marshalSearchJobID(id int32) graphql.ID {
+	return relay.MarshalID(searchJobIDKind, id)
+}
+
+func unmarshalSearchJobID(id graphql.ID) (jobID int32, err error) {
+	if kind := relay.UnmarshalKind(id); kind != searchJobIDKind {
+		err = fmt.Errorf("expected graphql ID to have kind %q; got %q", searchJobIDKind, kind)
+		return
+	}
+	err = relay.UnmarshalSpec(id, &jobID)
+	return
+}
+
+func searchJobByID(ctx context.Context, id graphql.ID) (*searchJobResolver, error) {
+	jobID, err := unmarshalSearchJobID(id)
+	if err != nil {
+		return nil, err
+	}
+	job, err := db.SearchJobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user who created the search job and site admins may view it.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, job.UserID); err != nil {
+		return nil, err
+	}
+	return &searchJobResolver{job: job}, nil
+}
+
+func (r *schemaResolver) SearchJobs(ctx context.Context) (*searchJobConnectionResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	jobs, err := db.SearchJobs.ListByUser(ctx, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	return &searchJobConnectionResolver{jobs: jobs}, nil
+}
+
+func (r *schemaResolver) CreateSearchJob(ctx context.Context, args *struct {
+	Query       string
+	PatternType *string
+}) (*searchJobResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	patternType, err := detectSearchType("V2", args.PatternType, args.Query)
+	if err != nil {
+		return nil, err
+	}
+	if patternType == "fuzzy" {
+		return nil, errors.New("fuzzy searches can't be run as search jobs, because their results are ranked")
+	}
+	// Reject invalid queries now, rather than when the job is run.
+	if _, err := newExhaustiveSearchResolver(args.Query, patternType); err != nil {
+		return nil, err
+	}
+
+	job := &types.SearchJob{UserID: a.UID, Query: args.Query, PatternType: patternType}
+	if err := db.SearchJobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return &searchJobResolver{job: job}, nil
+}
+
+func (r *schemaResolver) CancelSearchJob(ctx context.Context, args *struct {
+	SearchJob graphql.ID
+}) (*searchJobResolver, error) {
+	// 🚨 SECURITY: searchJobByID checks that the current user created the
+	// search job or is a site admin.
+	job, err := searchJobByID(ctx, args.SearchJob)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SearchJobs.Cancel(ctx, job.job.ID); err != nil {
+		return nil, err
+	}
+	updated, err := db.SearchJobs.GetByID(ctx, job.job.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &searchJobResolver{job: updated}, nil
+}
+
+type searchJobConnectionResolver struct {
+	jobs []*types.SearchJob
+}
+
+func (r *searchJobConnectionResolver) Nodes() []*searchJobResolver {
+	resolvers := make([]*searchJobResolver, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		resolvers = append(resolvers, &searchJobResolver{job: job})
+	}
+	return resolvers
+}
+
+func (r *searchJobConnectionResolver) TotalCount() int32 {
+	return int32(len(r.jobs))
+}
+
+type searchJobResolver struct {
+	job *types.SearchJob
+}
+
+func (r *searchJobResolver) ID() graphql.ID {
+	return marshalSearchJobID(r.job.ID)
+}
+
+func (r *searchJobResolver) Query() string { return r.job.Query }
+
+func (r *searchJobResolver) State() string { return strings.ToUpper(r.job.State) }
+
+func (r *searchJobResolver) RepositoriesSearched() int32 { return int32(r.job.ReposSearched) }
+
+func (r *searchJobResolver) RepositoriesTotal() (*int32, error) {
+	if r.job.RepoRevs == nil {
+		return nil, nil
+	}
+	var repoRevs []*searchJobRepoRevs
+	if err := json.Unmarshal(r.job.RepoRevs, &repoRevs); err != nil {
+		return nil, err
+	}
+	n := int32(len(repoRevs))
+	return &n, nil
+}
+
+func (r *searchJobResolver) ResultCount() int32 { return int32(r.job.ResultCount) }
+
+func (r *searchJobResolver) IncompleteRepositories() []string {
+	if r.job.IncompleteRepos == nil {
+		return []string{}
+	}
+	return r.job.IncompleteRepos
+}
+
+func (r *searchJobResolver) Error() *string {
+	if r.job.Error == "" {
+		return nil
+	}
+	return &r.job.Error
+}
+
+func (r *searchJobResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.job.CreatedAt}
+}
+
+func (r *searchJobResolver) FinishedAt() *DateTime {
+	if r.job.FinishedAt == nil {
+		return nil
+	}
+	return &DateTime{Time: *r.job.FinishedAt}
+}
+
+func (r *searchJobResolver) ResultsURL() string {
+	return fmt.Sprintf("/.api/search-jobs/%d/results", r.job.ID)
+}
@@ -0,0 +1,111 @@
+package graphqlbackend
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestSearchResolver_maxResults_countAll(t *testing.T) {
+	for _, test := range []struct {
+		query      string
+		exhaustive bool
+		want       int32
+	}{
+		{query: "foo", want: defaultMaxSearchResults},
+		{query: "foo count:10", want: 10},
+		{query: "foo count:all", want: math.MaxInt32},
+		{query: "foo", exhaustive: true, want: math.MaxInt32},
+		{query: "foo count:10", exhaustive: true, want: math.MaxInt32},
+	} {
+		q, err := query.ParseAndCheck(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := (&searchResolver{query: q, exhaustive: test.exhaustive}).maxResults(); got != test.want {
+			t.Errorf("%q (exhaustive: %v): got %d, want %d", test.query, test.exhaustive, got, test.want)
+		}
+	}
+}
+
+func TestMarshalSearchJobResults(t *testing.T) {
+	repo := &types.Repo{Name: "r"}
+	rev := "v1"
+	results := []searchResultResolver{
+		&fileMatchResolver{
+			JPath:        "a.go",
+			JLineMatches: []*lineMatch{{JPreview: "foo()", JLineNumber: 4}},
+			repo:         repo,
+			commitID:     "c1",
+			inputRev:     &rev,
+		},
+		&RepositoryResolver{repo: repo},
+		&commitSearchResultResolver{
+			commit: &GitCommitResolver{repo: &RepositoryResolver{repo: repo}, oid: "c2"},
+			url:    "/r/-/commit/c2",
+		},
+	}
+
+	got, err := marshalSearchJobResults(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"file","repository":"r","revision":"v1","commit":"c1","path":"a.go","lineMatches":[{"lineNumber":4,"preview":"foo()"}]}
+{"type":"repository","repository":"r"}
+{"type":"commit","repository":"r","commit":"c2","url":"/r/-/commit/c2"}
+`
+	if got != want {
+		t.Errorf("got results\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCreateSearchJob(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	var created *types.SearchJob
+	db.Mocks.SearchJobs.Create = func(job *types.SearchJob) error {
+		job.ID = 1
+		job.State = types.SearchJobStateQueued
+		created = job
+		return nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	r := &schemaResolver{}
+
+	if _, err := r.CreateSearchJob(context.Background(), &struct {
+		Query       string
+		PatternType *string
+	}{Query: "foo"}); err == nil {
+		t.Error("expected error for unauthenticated user")
+	}
+
+	job, err := r.CreateSearchJob(ctx, &struct {
+		Query       string
+		PatternType *string
+	}{Query: "foo patternType:regexp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.UserID != 2 || created.PatternType != "regexp" {
+		t.Errorf("got created job %+v", created)
+	}
+	if got, want := job.State(), "QUEUED"; got != want {
+		t.Errorf("got state %q, want %q", got, want)
+	}
+	if got, want := job.ResultsURL(), "/.api/search-jobs/1/results"; got != want {
+		t.Errorf("got results URL %q, want %q", got, want)
+	}
+
+	fuzzy := "fuzzy"
+	if _, err := r.CreateSearchJob(ctx, &struct {
+		Query       string
+		PatternType *string
+	}{Query: "foo", PatternType: &fuzzy}); err == nil {
+		t.Error("expected error for fuzzy search job")
+	}
+}
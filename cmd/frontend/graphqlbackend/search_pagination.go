@@ -134,7 +134,7 @@ func (r *searchResolver) paginatedResults(ctx context.Context) (result *searchRe
 		return alertResult, nil
 	}
 
-	p, err := r.getPatternInfo(nil)
+	p, err := r.getPatternInfo(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +143,7 @@ func (r *searchResolver) paginatedResults(ctx context.Context) (result *searchRe
 		Repos:           repos,
 		Query:           r.query,
 		UseFullDeadline: false,
+		Debug:           r.debug,
 		Zoekt:           r.zoekt,
 		SearcherURLs:    r.searcherURLs,
 	}
@@ -203,6 +204,7 @@ func (r *searchResolver) paginatedResults(ctx context.Context) (result *searchRe
 		results:             results,
 		alert:               alert,
 		cursor:              cursor,
+		contextRepo:         r.contextRepo,
 	}, nil
 }
 
@@ -459,6 +461,7 @@ func sliceSearchResults(results []searchResultResolver, common *searchResultsCom
 	final.common = &searchResultsCommon{
 		limitHit:         false, // irrelevant in paginated search
 		indexUnavailable: common.indexUnavailable,
+		indexFallback:    common.indexFallback,
 		partial:          make(map[api.RepoName]struct{}),
 	}
 	copy := func(repo *types.Repo, targetList *[]*types.Repo, ifInsideList []*types.Repo) {
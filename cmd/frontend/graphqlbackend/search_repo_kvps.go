@@ -0,0 +1,31 @@
+package graphqlbackend
+
+import (
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+// repoKVPFilterPattern matches repo: filter values of the form "has.meta(key)"
+// and "has.meta(key:value)", which match repositories by their key-value pairs
+// instead of by their names.
+var repoKVPFilterPattern = regexp.MustCompile(`^has\.meta\(([^:()]+)(?::([^()]*))?\)$`)
+
+// extractRepoKVPFilters separates the key-value pair filters from the other
+// repo: filter values.
+func extractRepoKVPFilters(patterns []string) (rest []string, filters []db.RepoKVPFilter) {
+	for _, pattern := range patterns {
+		m := repoKVPFilterPattern.FindStringSubmatchIndex(pattern)
+		if m == nil {
+			rest = append(rest, pattern)
+			continue
+		}
+		f := db.RepoKVPFilter{Key: pattern[m[2]:m[3]]}
+		if m[4] >= 0 {
+			value := pattern[m[4]:m[5]]
+			f.Value = &value
+		}
+		filters = append(filters, f)
+	}
+	return rest, filters
+}
@@ -0,0 +1,32 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func TestExtractRepoKVPFilters(t *testing.T) {
+	value := "search"
+	empty := ""
+	rest, filters := extractRepoKVPFilters([]string{
+		"github.com/foo",
+		"has.meta(team:search)",
+		"has.meta(archived)",
+		"has.meta(owner:)",
+		"has.meta(",
+	})
+
+	if want := []string{"github.com/foo", "has.meta("}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("got rest %q, want %q", rest, want)
+	}
+	want := []db.RepoKVPFilter{
+		{Key: "team", Value: &value},
+		{Key: "archived"},
+		{Key: "owner", Value: &empty},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("got filters %+v, want %+v", filters, want)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"math"
 	"regexp"
+	"regexp/syntax"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 )
 
 var mockSearchRepositories func(args *search.Args) ([]searchResultResolver, *searchResultsCommon, error)
@@ -35,6 +38,11 @@ func searchRepositories(ctx context.Context, args *search.Args, limit int32) (re
 		query.FieldFork:        {},
 		query.FieldArchived:    {},
 		query.FieldRepoHasFile: {},
+
+		query.FieldArchivedMirror: {},
+		query.FieldDedupForks:     {},
+		query.FieldMaxRepos:       {},
+		query.FieldOrgExclusions:  {},
 	}
 	// Don't return repo results if the search contains fields that aren't on the whitelist.
 	// Matching repositories based whether they contain files at a certain path (etc.) is not yet implemented.
@@ -44,16 +52,16 @@ func searchRepositories(ctx context.Context, args *search.Args, limit int32) (re
 		}
 	}
 
-	pattern, err := regexp.Compile(args.Pattern.Pattern)
+	matches, err := repoNameMatcher(ctx, args.Pattern)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Filter args.Repos by matching their names against the query pattern.
+	// Filter args.Repos by matching them against the query pattern.
 	common = &searchResultsCommon{}
 	var repos []*search.RepositoryRevisions
 	for _, r := range args.Repos {
-		if pattern.MatchString(string(r.Repo.Name)) {
+		if matches(r.Repo) {
 			repos = append(repos, r)
 		}
 	}
@@ -79,6 +87,41 @@ func searchRepositories(ctx context.Context, args *search.Args, limit int32) (re
 	return results, common, nil
 }
 
+// repoNameMatcher returns a function that reports whether a repository
+// matches the pattern of a type:repo search. Case-insensitive literal patterns
+// are looked up in the names and descriptions of repositories with
+// db.Repos.Search, which uses trigram indexes. Other patterns are matched
+// against the names of repositories.
+func repoNameMatcher(ctx context.Context, p *search.PatternInfo) (func(*types.Repo) bool, error) {
+	if lit, ok := literalPattern(p.Pattern); ok && lit != "" && !p.IsCaseSensitive {
+		found, err := db.Repos.Search(ctx, db.ReposSearchOptions{Query: lit})
+		if err != nil {
+			return nil, err
+		}
+		ids := make(map[api.RepoID]bool, len(found))
+		for _, r := range found {
+			ids[r.ID] = true
+		}
+		return func(r *types.Repo) bool { return ids[r.ID] }, nil
+	}
+
+	pattern, err := regexp.Compile(p.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	return func(r *types.Repo) bool { return pattern.MatchString(string(r.Name)) }, nil
+}
+
+// literalPattern returns the string matched by the regular expression
+// pattern, if it only matches that string.
+func literalPattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil || re.Op != syntax.OpLiteral {
+		return "", false
+	}
+	return string(re.Rune), true
+}
+
 // reposToAdd determines which repositories should be included in the result set based on whether they fit in the subset
 // of repostiories specified in the query's `repohasfile` and `-repohasfile` fields if they exist.
 func reposToAdd(ctx context.Context, args *search.Args, repos []*search.RepositoryRevisions) ([]*search.RepositoryRevisions, error) {
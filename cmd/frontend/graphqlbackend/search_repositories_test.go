@@ -3,8 +3,10 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -15,9 +17,21 @@ func TestSearchRepositories(t *testing.T) {
 	repositories := []*search.RepositoryRevisions{
 		{Repo: &types.Repo{ID: 123, Name: "foo/one"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
 		{Repo: &types.Repo{ID: 456, Name: "foo/no-match"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
-		{Repo: &types.Repo{ID: 789, Name: "bar/one"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+		{Repo: &types.Repo{ID: 789, Name: "bar/one", RepoFields: &types.RepoFields{Description: "The first bar"}}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
 	}
 
+	db.Mocks.Repos.Search = func(ctx context.Context, opt db.ReposSearchOptions) ([]*types.Repo, error) {
+		var repos []*types.Repo
+		for _, r := range repositories {
+			if strings.Contains(strings.ToLower(string(r.Repo.Name)), strings.ToLower(opt.Query)) ||
+				(r.Repo.RepoFields != nil && strings.Contains(strings.ToLower(r.Repo.Description), strings.ToLower(opt.Query))) {
+				repos = append(repos, r.Repo)
+			}
+		}
+		return repos, nil
+	}
+	defer func() { db.Mocks.Repos.Search = nil }()
+
 	zoekt := &searchbackend.Zoekt{Client: &fakeSearcher{}}
 
 	mockSearchFilesInRepos = func(args *search.Args) (matches []*fileMatchResolver, common *searchResultsCommon, err error) {
@@ -92,6 +106,29 @@ func TestSearchRepositories(t *testing.T) {
 		}
 	})
 
+	t.Run("search for all repositories where the repo description includes 'first bar'", func(t *testing.T) {
+		q, err := query.ParseAndCheck("type:repo first bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		args := search.Args{
+			Pattern: &search.PatternInfo{Pattern: "first bar", IsRegExp: true, FileMatchLimit: 1, PathPatternsAreRegExps: true, PathPatternsAreCaseSensitive: false, PatternMatchesContent: true, PatternMatchesPath: true},
+			Repos:   repositories,
+			Query:   q,
+			Zoekt:   zoekt,
+		}
+		res, _, err := searchRepositories(context.Background(), &args, int32(100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 1 {
+			t.Fatalf("expected only one repository result `bar/one`, but got %v", len(res))
+		}
+		if r, _ := res[0].ToRepository(); r == nil || r.repo.Name != "bar/one" {
+			t.Errorf("expected the repository result to be `bar/one`, but got %v", res[0])
+		}
+	})
+
 	t.Run("search for all repositories where the repo name includes 'foo' and the repo has a file path matching 'f.go'", func(t *testing.T) {
 		q, err := query.ParseAndCheck("foo type:repo repohasfile:f.go")
 		if err != nil {
@@ -225,3 +262,19 @@ func repoShouldBeAdded(ctx context.Context, zoekt *searchbackend.Zoekt, repo *se
 	}
 	return len(rsta) == 1, nil
 }
+
+func TestLiteralPattern(t *testing.T) {
+	for pattern, want := range map[string]string{
+		"foo/one":  "foo/one",
+		`foo\.bar`: "foo.bar",
+	} {
+		if have, ok := literalPattern(pattern); !ok || have != want {
+			t.Errorf("literalPattern(%q) = %q, %v, want %q, true", pattern, have, ok, want)
+		}
+	}
+	for _, pattern := range []string{"foo.*", "^foo", "foo|bar", "[", ""} {
+		if have, ok := literalPattern(pattern); ok {
+			t.Errorf("literalPattern(%q) = %q, true, want false", pattern, have)
+		}
+	}
+}
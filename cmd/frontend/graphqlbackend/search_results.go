@@ -13,7 +13,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 
 	"github.com/hashicorp/go-multierror"
@@ -28,11 +27,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/syntax"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // searchResultsCommon contains fields that should be returned by all funcs
@@ -55,6 +57,13 @@ type searchResultsCommon struct {
 	timedout []*types.Repo
 
 	indexUnavailable bool // True if indexed search is enabled but was not available during this search.
+
+	// indexFallback are the indexed repos that were searched without the index
+	// because it was unavailable.
+	indexFallback []*types.Repo
+
+	// timings are the per-repository search timings, only collected for debug searches.
+	timings []*repositorySearchTimingResolver
 }
 
 func (c *searchResultsCommon) LimitHit() bool {
@@ -89,6 +98,19 @@ func (c *searchResultsCommon) IndexUnavailable() bool {
 	return c.indexUnavailable
 }
 
+func (c *searchResultsCommon) IndexUnavailableFallbackCount() int32 {
+	repos := append(types.Repos(nil), c.indexFallback...)
+	dedupSort(&repos)
+	return int32(len(repos))
+}
+
+func (c *searchResultsCommon) RepositorySearchTimings() *[]*repositorySearchTimingResolver {
+	if c.timings == nil {
+		return nil
+	}
+	return &c.timings
+}
+
 func RepositoryResolvers(repos types.Repos) []*RepositoryResolver {
 	dedupSort(&repos)
 	return toRepositoryResolvers(repos)
@@ -106,6 +128,8 @@ func (c *searchResultsCommon) update(other searchResultsCommon) {
 	c.cloning = append(c.cloning, other.cloning...)
 	c.missing = append(c.missing, other.missing...)
 	c.timedout = append(c.timedout, other.timedout...)
+	c.indexFallback = append(c.indexFallback, other.indexFallback...)
+	c.timings = append(c.timings, other.timings...)
 	c.resultCount += other.resultCount
 
 	if c.partial == nil {
@@ -147,6 +171,10 @@ type searchResultsResolver struct {
 	// cursor to return for paginated search requests, or nil if the request
 	// wasn't paginated.
 	cursor *searchCursor
+
+	// contextRepo is the repository that the search was implicitly scoped
+	// to, if any (see searchArgs.ContextRepo).
+	contextRepo api.RepoName
 }
 
 func (sr *searchResultsResolver) Results() []searchResultResolver {
@@ -200,7 +228,7 @@ var commonFileFilters = []struct {
 	},
 }
 
-func (sr *searchResultsResolver) DynamicFilters() []*searchFilterResolver {
+func (sr *searchResultsResolver) DynamicFilters(ctx context.Context) []*searchFilterResolver {
 	filters := map[string]*searchFilterResolver{}
 	repoToMatchCount := make(map[string]int)
 	add := func(value string, label string, count int, limitHit bool, kind string) {
@@ -240,20 +268,16 @@ func (sr *searchResultsResolver) DynamicFilters() []*searchFilterResolver {
 		}
 	}
 
-	addLangFilter := func(fileMatchPath string, lineMatchCount int, limitHit bool) {
-		extensionToLanguageLookup := func(path string) string {
-			language, _ := inventory.GetLanguageByFilename(path)
-			return strings.ToLower(language)
-		}
-		if ext := path.Ext(fileMatchPath); ext != "" {
-			language := extensionToLanguageLookup(fileMatchPath)
-			if language != "" {
-				value := fmt.Sprintf(`lang:%s`, language)
-				add(value, value, lineMatchCount, limitHit, "lang")
-			}
+	addLangFilter := func(fm *fileMatchResolver, lineMatchCount int, limitHit bool) {
+		if language, _ := fm.language(); language != "" {
+			value := fmt.Sprintf(`lang:%s`, strings.ToLower(language))
+			add(value, value, lineMatchCount, limitHit, "lang")
 		}
 	}
 
+	dirs := newDirFilterCounter()
+	repoResultCounts := make(map[api.RepoName]int)
+
 	for _, result := range sr.results {
 		if fm, ok := result.ToFileMatch(); ok {
 			rev := ""
@@ -261,8 +285,10 @@ func (sr *searchResultsResolver) DynamicFilters() []*searchFilterResolver {
 				rev = *fm.inputRev
 			}
 			addRepoFilter(string(fm.repo.Name), rev, len(fm.LineMatches()))
-			addLangFilter(fm.JPath, len(fm.LineMatches()), fm.JLimitHit)
+			addLangFilter(fm, len(fm.LineMatches()), fm.JLimitHit)
 			addFileFilter(fm.JPath, len(fm.LineMatches()), fm.JLimitHit)
+			dirs.add(fm.JPath, len(fm.LineMatches()), fm.JLimitHit)
+			repoResultCounts[fm.repo.Name] += len(fm.LineMatches())
 
 			if len(fm.symbols) > 0 {
 				add("type:symbol", "type:symbol", 1, fm.JLimitHit, "symbol")
@@ -272,6 +298,7 @@ func (sr *searchResultsResolver) DynamicFilters() []*searchFilterResolver {
 			// can only be used with the 'repo:' scope. In that case,
 			// we shouldn't be getting any repositoy name matches back.
 			addRepoFilter(r.Name(), "", 1)
+			repoResultCounts[api.RepoName(r.Name())]++
 		}
 		// Add `case:yes` filter to offer easier access to search results matching with case sensitive set to yes
 		// We use count == 0 and limitHit == false since we can't determine that information without
@@ -279,10 +306,38 @@ func (sr *searchResultsResolver) DynamicFilters() []*searchFilterResolver {
 		add("case:yes", "case:yes", 0, false, "case")
 	}
 
+	for _, f := range dirs.filters() {
+		filters[f.value] = f
+	}
+
+	// The implicit scope of the search to the repository it was run from is
+	// always listed, so users can remove it.
+	if sr.contextRepo != "" {
+		f := contextRepoSearchFilter(sr.contextRepo, repoToMatchCount[string(sr.contextRepo)])
+		_, f.limitHit = sr.searchResultsCommon.partial[sr.contextRepo]
+		filters[f.value] = f
+	}
+
+	groups, err := resolveRepoGroups(ctx)
+	if err != nil {
+		log15.Warn("DynamicFilters: failed to resolve repo groups", "error", err)
+	} else {
+		for _, f := range repoGroupFilters(groups, repoResultCounts) {
+			filters[f.value] = f
+		}
+	}
+
+	// Curated filters are always proposed when they match some results, so
+	// they are listed first and are not subject to the limit of filters.
+	curated := curatedFilters(conf.Get().SearchCuratedFilters, sr.results, groups)
+	for _, f := range curated {
+		delete(filters, f.value)
+	}
+
 	filterSlice := make([]*searchFilterResolver, 0, len(filters))
 	repoFilterSlice := make([]*searchFilterResolver, 0, len(filters)/2) // heuristic - half of all filters are repo filters.
 	for _, f := range filters {
-		if f.kind == "repo" {
+		if f.kind == "repo" || f.kind == "context" {
 			repoFilterSlice = append(repoFilterSlice, f)
 		} else {
 			filterSlice = append(filterSlice, f)
@@ -301,7 +356,100 @@ func (sr *searchResultsResolver) DynamicFilters() []*searchFilterResolver {
 		return allFilters[j].score < allFilters[i].score
 	})
 
-	return allFilters
+	return append(curated, allFilters...)
+}
+
+// minDirFilterFiles is the minimum number of file matches a directory must
+// contain to be proposed as a filter by DynamicFilters.
+const minDirFilterFiles = 2
+
+// dirFilterCounter counts the file matches under each directory of the
+// results, to propose `file:^dir/` filters in DynamicFilters. These are
+// useful in monorepos, where repo filters can't narrow down the results.
+type dirFilterCounter struct {
+	files      int
+	dirs       map[string]*searchFilterResolver
+	dirMatches map[string]int
+}
+
+func newDirFilterCounter() *dirFilterCounter {
+	return &dirFilterCounter{
+		dirs:       make(map[string]*searchFilterResolver),
+		dirMatches: make(map[string]int),
+	}
+}
+
+// add records a file match with the given path in each of its parent
+// directories.
+func (c *dirFilterCounter) add(filePath string, lineMatchCount int, limitHit bool) {
+	c.files++
+	for dir := path.Dir(strings.TrimPrefix(filePath, "/")); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		f, ok := c.dirs[dir]
+		if !ok {
+			value := fmt.Sprintf(`file:^%s/`, regexp.QuoteMeta(dir))
+			f = &searchFilterResolver{value: value, label: dir + "/", kind: "file"}
+			c.dirs[dir] = f
+		}
+		c.dirMatches[dir] += lineMatchCount
+		f.count = int32(c.dirMatches[dir])
+		f.limitHit = f.limitHit || limitHit
+		f.score++ // the number of files in dir
+	}
+}
+
+// filters returns the directory filters worth proposing: those that contain
+// several but not all of the file matches. A directory is omitted if one of
+// its subdirectories contains the same file matches, since the subdirectory
+// is the more specific filter.
+func (c *dirFilterCounter) filters() []*searchFilterResolver {
+	var filters []*searchFilterResolver
+	for dir, f := range c.dirs {
+		if f.score < minDirFilterFiles || f.score == c.files {
+			continue
+		}
+		if c.hasEquivalentSubdir(dir, f.score) {
+			continue
+		}
+		filters = append(filters, f)
+	}
+	return filters
+}
+
+func (c *dirFilterCounter) hasEquivalentSubdir(dir string, files int) bool {
+	for other, f := range c.dirs {
+		if f.score == files && path.Dir(other) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// repoGroupFilters returns `repogroup:` filters for the repo groups that
+// contain the results of several, but not all, repositories. repoResultCounts
+// is the number of matches in each repository with results.
+func repoGroupFilters(groups map[string][]*types.Repo, repoResultCounts map[api.RepoName]int) []*searchFilterResolver {
+	var filters []*searchFilterResolver
+	for name, repos := range groups {
+		var repoCount, matchCount int
+		for _, repo := range repos {
+			if n, ok := repoResultCounts[repo.Name]; ok {
+				repoCount++
+				matchCount += n
+			}
+		}
+		if repoCount < 2 || repoCount == len(repoResultCounts) {
+			continue
+		}
+		value := "repogroup:" + name
+		filters = append(filters, &searchFilterResolver{
+			value: value,
+			label: value,
+			count: int32(matchCount),
+			kind:  "repogroup",
+			score: repoCount,
+		})
+	}
+	return filters
 }
 
 type searchFilterResolver struct {
@@ -316,7 +464,8 @@ type searchFilterResolver struct {
 	// whether the results returned for a repository are incomplete
 	limitHit bool
 
-	// the kind of filter. Should be "repo", "file", or "lang".
+	// the kind of filter. Should be "repo", "repogroup", "file", "lang", "symbol",
+	// "case", "context" or "curated".
 	kind string
 
 	// score is used to select potential filters
@@ -456,7 +605,18 @@ func (r *searchResolver) Results(ctx context.Context) (*searchResultsResolver, e
 	// If the request is a paginated one, we handle it separately. See
 	// paginatedResults for more details.
 	if r.pagination != nil {
-		return r.paginatedResults(ctx)
+		rr, err := r.paginatedResults(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if r.includeCommitInfo {
+			addFileMatchLastCommits(ctx, rr.results)
+		}
+		// Only record the search once, not for every page of results.
+		if r.pagination.cursor == nil {
+			r.addToSearchHistory(ctx, rr)
+		}
+		return rr, nil
 	}
 
 	rr, err := r.resultsWithTimeoutSuggestion(ctx)
@@ -464,6 +624,73 @@ func (r *searchResolver) Results(ctx context.Context) (*searchResultsResolver, e
 		return nil, err
 	}
 
+	if len(rr.results) == 0 && r.hasQuotesInLiteralMode() && retryWithoutQuotes(ctx) {
+		rrWithoutQuotes, err := r.resultsWithoutQuotes(ctx)
+		if err != nil {
+			log15.Warn("failed to search without quotes", "query", r.originalQuery, "error", err)
+		} else if rrWithoutQuotes != nil {
+			rr = rrWithoutQuotes
+		}
+	}
+
+	if r.includeCommitInfo {
+		addFileMatchLastCommits(ctx, rr.results)
+	}
+
+	r.addToSearchHistory(ctx, rr)
+	recordSearchHotSpots(rr.results)
+	return rr, nil
+}
+
+// hasQuotesInLiteralMode returns true if the query is interpreted literally and
+// contains quotes, which the user may have intended to delimit a pattern.
+func (r *searchResolver) hasQuotesInLiteralMode() bool {
+	return strings.Contains(r.originalQuery, `"`) && r.patternType == "literal"
+}
+
+// retryWithoutQuotes returns true if the search.retryWithoutQuotes setting is
+// enabled for the current user.
+func retryWithoutQuotes(ctx context.Context) bool {
+	merged, err := viewerFinalSettings(ctx)
+	if err != nil {
+		log15.Warn("failed to get settings", "error", err)
+		return false
+	}
+	var settings schema.Settings
+	if err := json.Unmarshal([]byte(merged.Contents()), &settings); err != nil {
+		log15.Warn("failed to decode settings", "error", err)
+		return false
+	}
+	return settings.SearchRetryWithoutQuotes
+}
+
+// resultsWithoutQuotes runs the query again with its quotes removed. It
+// returns nil if that has no results either. The results carry an alert which
+// notes that they are for the query without quotes.
+func (r *searchResolver) resultsWithoutQuotes(ctx context.Context) (*searchResultsResolver, error) {
+	patternType := r.patternType
+	unquoted, err := (&schemaResolver{}).Search(&searchArgs{
+		Version:     r.version,
+		PatternType: &patternType,
+		Query:       syntax.ExprString(omitQuotes(r.query)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sr, ok := unquoted.(*searchResolver)
+	if !ok {
+		// The query without quotes is invalid.
+		return nil, nil
+	}
+
+	rr, err := sr.resultsWithTimeoutSuggestion(ctx)
+	if err != nil || len(rr.results) == 0 {
+		return nil, err
+	}
+	rr.alert = &searchAlert{
+		title:       "Showing results without quotes",
+		description: "Your search is interpreted literally and contains quotes, but there are no results that contain the quotes. These are the results of your search with the quotes removed.",
+	}
 	return rr, nil
 }
 
@@ -643,7 +870,11 @@ type getPatternInfoOptions struct {
 }
 
 // getPatternInfo gets the search pattern info for the query in the resolver.
-func (r *searchResolver) getPatternInfo(opts *getPatternInfoOptions) (*search.PatternInfo, error) {
+func (r *searchResolver) getPatternInfo(ctx context.Context, opts *getPatternInfoOptions) (*search.PatternInfo, error) {
+	// Handle content: and -content: patterns. Files whose content matches a
+	// -content: pattern are excluded from the results.
+	contentPatterns, negatedContentPatterns := r.query.RegexpPatterns(query.FieldContent)
+
 	var patternsToCombine []string
 	if opts == nil || !opts.forceFileSearch {
 		for _, v := range r.query.Values(query.FieldDefault) {
@@ -660,6 +891,7 @@ func (r *searchResolver) getPatternInfo(opts *getPatternInfoOptions) (*search.Pa
 			}
 			patternsToCombine = append(patternsToCombine, pattern)
 		}
+		patternsToCombine = append(patternsToCombine, contentPatterns...)
 	} else {
 		// TODO: We must have some pattern that always matches here, or else
 		// cmd/searcher/search/matcher.go:97 would cause a nil regexp panic
@@ -669,8 +901,11 @@ func (r *searchResolver) getPatternInfo(opts *getPatternInfoOptions) (*search.Pa
 		patternsToCombine = append(patternsToCombine, ".")
 	}
 
-	// Handle file: and -file: filters.
+	// Handle file: and -file: filters. Files are matched by their owners
+	// with file:has.owner(owner) after searching (see filterFileMatchesByOwner).
 	includePatterns, excludePatterns := r.query.RegexpPatterns(query.FieldFile)
+	includePatterns, _ = extractFileOwnerFilters(includePatterns)
+	excludePatterns, _ = extractFileOwnerFilters(excludePatterns)
 	filePatternsReposMustInclude, filePatternsReposMustExclude := r.query.RegexpPatterns(query.FieldRepoHasFile)
 
 	if opts != nil && opts.forceFileSearch {
@@ -687,11 +922,19 @@ func (r *searchResolver) getPatternInfo(opts *getPatternInfoOptions) (*search.Pa
 	includePatterns = append(includePatterns, langIncludePatterns...)
 	excludePatterns = append(excludePatterns, langExcludePatterns...)
 
+	exclusions, err := r.searchExclusions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	excludePatterns = append(excludePatterns, exclusions.paths...)
+
 	patternInfo := &search.PatternInfo{
 		IsRegExp:                     true,
 		IsCaseSensitive:              r.query.IsCaseSensitive(),
 		FileMatchLimit:               r.maxResults(),
 		Pattern:                      regexpPatternMatchingExprsInOrder(patternsToCombine),
+		AndPatterns:                  andPatterns(patternsToCombine),
+		NegatedPatterns:              negatedContentPatterns,
 		IncludePatterns:              includePatterns,
 		FilePatternsReposMustInclude: filePatternsReposMustInclude,
 		FilePatternsReposMustExclude: filePatternsReposMustExclude,
@@ -701,6 +944,11 @@ func (r *searchResolver) getPatternInfo(opts *getPatternInfoOptions) (*search.Pa
 	if len(excludePatterns) > 0 {
 		patternInfo.ExcludePattern = unionRegExps(excludePatterns)
 	}
+	if r.query.IsUnicode() {
+		patternInfo.IsUnicode = true
+		patternInfo.Normalization = conf.SearchUnicodeNormalization()
+		patternInfo.NormalizeUnicode()
+	}
 	return patternInfo, nil
 }
 
@@ -713,10 +961,21 @@ var (
 
 func (r *searchResolver) searchTimeoutFieldSet() bool {
 	timeout, _ := r.query.StringValue(query.FieldTimeout)
-	return timeout != "" || r.countIsSet()
+	return timeout != "" || r.countIsSet() || r.maxReposIsSet() || r.exhaustive
+}
+
+func (r *searchResolver) maxReposIsSet() bool {
+	maxRepos, _ := r.query.StringValue(query.FieldMaxRepos)
+	return maxRepos != ""
 }
 
 func (r *searchResolver) withTimeout(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if r.exhaustive {
+		// Exhaustive searches run in the background, with the deadline of
+		// their caller.
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, nil
+	}
 	d := defaultTimeout
 	timeout, _ := r.query.StringValue(query.FieldTimeout)
 	if timeout != "" {
@@ -725,8 +984,8 @@ func (r *searchResolver) withTimeout(ctx context.Context) (context.Context, cont
 		if err != nil {
 			return nil, nil, errors.WithMessage(err, `invalid "timeout:" value (examples: "timeout:2s", "timeout:200ms")`)
 		}
-	} else if r.countIsSet() {
-		// If `count:` is set but `timeout:` is not explicitely set, use the max timeout
+	} else if r.countIsSet() || r.maxReposIsSet() {
+		// If `count:` or `maxRepos:` is set but `timeout:` is not explicitely set, use the max timeout
 		d = maxTimeout
 	}
 	// don't run queries longer than 1 minute.
@@ -739,7 +998,10 @@ func (r *searchResolver) withTimeout(ctx context.Context) (context.Context, cont
 
 func (r *searchResolver) determineResultTypes(args search.Args, forceOnlyResultType string) (resultTypes []string, seenResultTypes map[string]struct{}) {
 	// Determine which types of results to return.
-	if forceOnlyResultType != "" {
+	if r.patternType == "fuzzy" {
+		// Fuzzy search only matches paths.
+		resultTypes = []string{"path"}
+	} else if forceOnlyResultType != "" {
 		resultTypes = []string{forceOnlyResultType}
 	} else if len(r.query.Values(query.FieldReplace)) > 0 {
 		resultTypes = []string{"codemod"}
@@ -773,7 +1035,7 @@ func (r *searchResolver) determineRepos(ctx context.Context, tr *trace.Trace, st
 		}
 		return nil, nil, &searchResultsResolver{alert: alert, start: start}, nil
 	}
-	if overLimit {
+	if overLimit && !r.maxReposIsSet() {
 		alert, err := r.alertForOverRepoLimit(ctx)
 		if err != nil {
 			return nil, nil, nil, err
@@ -806,7 +1068,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		return alertResult, nil
 	}
 
-	p, err := r.getPatternInfo(nil)
+	p, err := r.getPatternInfo(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -815,6 +1077,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		Repos:           repos,
 		Query:           r.query,
 		UseFullDeadline: r.searchTimeoutFieldSet(),
+		Debug:           r.debug,
 		Zoekt:           r.zoekt,
 		SearcherURLs:    r.searcherURLs,
 	}
@@ -822,6 +1085,16 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		return nil, &badRequestError{err}
 	}
 
+	if r.patternType == "fuzzy" {
+		if err := validateFuzzyQuery(r.query); err != nil {
+			return nil, &badRequestError{err}
+		}
+		// Fetch enough paths to rank, the results are limited after ranking.
+		if args.Pattern.FileMatchLimit < fuzzyCandidateLimit {
+			args.Pattern.FileMatchLimit = fuzzyCandidateLimit
+		}
+	}
+
 	err = validateRepoHasFileUsage(r.query)
 	if err != nil {
 		return nil, err
@@ -845,6 +1118,12 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		fileMatchesMu sync.Mutex
 	)
 
+	if r.maxReposIsSet() {
+		// Only the first maxRepos: repositories are searched when more of
+		// them match. The repositories are cached, so this is cheap.
+		_, _, common.limitHit, _ = r.resolveRepositories(ctx, nil)
+	}
+
 	waitGroup := func(required bool) *sync.WaitGroup {
 		if args.UseFullDeadline {
 			// When a custom timeout is specified, all searches are required and get the full timeout.
@@ -882,6 +1161,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, repoResults...)
 					resultsMu.Unlock()
+					r.streamResults(ctx, repoResults)
 				}
 				if repoCommon != nil {
 					commonMu.Lock()
@@ -902,19 +1182,27 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					multiErr = multierror.Append(multiErr, errors.Wrap(err, "symbol search failed"))
 					multiErrMu.Unlock()
 				}
+				var batch []searchResultResolver
 				for _, symbolFileMatch := range symbolFileMatches {
 					key := symbolFileMatch.uri
 					fileMatchesMu.Lock()
 					if m, ok := fileMatches[key]; ok {
 						m.symbols = symbolFileMatch.symbols
+						batch = append(batch, m)
 					} else {
 						fileMatches[key] = symbolFileMatch
 						resultsMu.Lock()
 						results = append(results, symbolFileMatch)
 						resultsMu.Unlock()
+						batch = append(batch, symbolFileMatch)
 					}
 					fileMatchesMu.Unlock()
 				}
+				// The streamed file matches may be merged with the results of
+				// other searches concurrently.
+				fileMatchesMu.Lock()
+				r.streamResults(ctx, batch)
+				fileMatchesMu.Unlock()
 				if symbolsCommon != nil {
 					commonMu.Lock()
 					common.update(*symbolsCommon)
@@ -939,6 +1227,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					multiErr = multierror.Append(multiErr, errors.Wrap(err, "text search failed"))
 					multiErrMu.Unlock()
 				}
+				var batch []searchResultResolver
 				for _, r := range fileResults {
 					key := r.uri
 					fileMatchesMu.Lock()
@@ -947,20 +1236,60 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 						// merge line match results with an existing symbol result
 						m.JLimitHit = m.JLimitHit || r.JLimitHit
 						m.JLineMatches = r.JLineMatches
+						batch = append(batch, m)
 					} else {
 						fileMatches[key] = r
 						resultsMu.Lock()
 						results = append(results, r)
 						resultsMu.Unlock()
+						batch = append(batch, r)
 					}
 					fileMatchesMu.Unlock()
 				}
+				// The streamed file matches may be merged with the results of
+				// other searches concurrently.
+				fileMatchesMu.Lock()
+				r.streamResults(ctx, batch)
+				fileMatchesMu.Unlock()
 				if fileCommon != nil {
 					commonMu.Lock()
 					common.update(*fileCommon)
 					commonMu.Unlock()
 				}
 			})
+		case "filename-only":
+			wg := waitGroup(true)
+			wg.Add(1)
+			goroutine.Go(func() {
+				defer wg.Done()
+
+				filenameResults, filenameCommon, err := searchFilenamesInRepos(ctx, &args, r.filenameOnlyLimit())
+				// Timeouts are reported through searchResultsCommon so don't report an error for them
+				if err != nil && !isContextError(ctx, err) {
+					multiErrMu.Lock()
+					multiErr = multierror.Append(multiErr, errors.Wrap(err, "filename search failed"))
+					multiErrMu.Unlock()
+				}
+				// Files that other searches found already are not repeated.
+				var batch []searchResultResolver
+				fileMatchesMu.Lock()
+				for _, fm := range filenameResults {
+					if _, ok := fileMatches[fm.uri]; !ok {
+						fileMatches[fm.uri] = fm
+						resultsMu.Lock()
+						results = append(results, fm)
+						resultsMu.Unlock()
+						batch = append(batch, fm)
+					}
+				}
+				r.streamResults(ctx, batch)
+				fileMatchesMu.Unlock()
+				if filenameCommon != nil {
+					commonMu.Lock()
+					common.update(*filenameCommon)
+					commonMu.Unlock()
+				}
+			})
 		case "diff":
 			wg := waitGroup(len(resultTypes) == 1)
 			wg.Add(1)
@@ -977,6 +1306,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, diffResults...)
 					resultsMu.Unlock()
+					r.streamResults(ctx, diffResults)
 				}
 				if diffCommon != nil {
 					commonMu.Lock()
@@ -1001,6 +1331,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, commitResults...)
 					resultsMu.Unlock()
+					r.streamResults(ctx, commitResults)
 				}
 				if commitCommon != nil {
 					commonMu.Lock()
@@ -1025,6 +1356,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					resultsMu.Lock()
 					results = append(results, codemodResults...)
 					resultsMu.Unlock()
+					r.streamResults(ctx, codemodResults)
 				}
 				if codemodCommon != nil {
 					commonMu.Lock()
@@ -1058,7 +1390,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		alert = r.alertForMissingRepoRevs(missingRepoRevs)
 	}
 
-	if len(results) == 0 && strings.Contains(r.originalQuery, `"`) && r.patternType == "literal" {
+	if len(results) == 0 && r.hasQuotesInLiteralMode() {
 		alert, err = r.alertForQuotesInQueryInLiteralMode(ctx)
 	}
 
@@ -1069,18 +1401,58 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		multiErr = nil
 	}
 
-	sortResults(results)
+	results = r.filterResults(ctx, results)
+
+	// Exhaustive searches list every file match, so identical file matches
+	// are only collapsed in interactive searches.
+	if !r.exhaustive {
+		// Identical file matches in forks are collapsed unless "dedupforks:no" is given.
+		dedupForksStr, _ := r.query.StringValue(query.FieldDedupForks)
+		if dedupForks := parseYesNoOnly(dedupForksStr); dedupForks != No && dedupForks != False {
+			results = dedupForkFileMatches(results)
+		}
+
+		// Identical files in several searched revisions of a repository are collapsed.
+		results = dedupRevFileMatches(ctx, results)
+	}
+
+	if r.patternType == "fuzzy" {
+		rankFuzzyResults(results, query.FuzzyTerms(r.originalQuery))
+		if max := int(r.maxResults()); len(results) > max {
+			results = results[:max]
+			common.limitHit = true
+		}
+	} else {
+		sortResults(results)
+	}
 
 	resultsResolver := searchResultsResolver{
 		start:               start,
 		searchResultsCommon: common,
 		results:             results,
 		alert:               alert,
+		contextRepo:         r.contextRepo,
 	}
 
 	return &resultsResolver, multiErr.ErrorOrNil()
 }
 
+// filterResults applies the filters of the query that the search backends
+// don't (fully) understand to the results. It may modify results.
+func (r *searchResolver) filterResults(ctx context.Context, results []searchResultResolver) []searchResultResolver {
+	// The lang: filters only restricted the searched paths, so remove file matches
+	// whose contents show they are in another language.
+	langs, negatedLangs := r.query.StringValues(query.FieldLang)
+	results = filterFileMatchesByLanguage(results, langs, negatedLangs)
+
+	// The file:has.owner() filters are not understood by the searchers, so remove file
+	// matches that are not owned by (or are owned by, if negated) the given owners.
+	includeFilePatterns, excludeFilePatterns := r.query.RegexpPatterns(query.FieldFile)
+	_, owners := extractFileOwnerFilters(includeFilePatterns)
+	_, negatedOwners := extractFileOwnerFilters(excludeFilePatterns)
+	return filterFileMatchesByOwner(ctx, results, owners, negatedOwners)
+}
+
 // isContextError returns true if ctx.Err() is not nil or if err
 // is an error caused by context cancelation or timeout.
 func isContextError(ctx context.Context, err error) bool {
@@ -1137,6 +1509,15 @@ func regexpPatternMatchingExprsInOrder(patterns []string) string {
 	return "(" + strings.Join(patterns, ").*?(") + ")" // "?" makes it prefer shorter matches
 }
 
+// andPatterns returns the patterns that text search should match independently
+// within a file, or nil if there are fewer than two.
+func andPatterns(patterns []string) []string {
+	if len(patterns) < 2 {
+		return nil
+	}
+	return patterns
+}
+
 // Validates usage of the `repohasfile` filter
 func validateRepoHasFileUsage(q *query.Query) error {
 	// Query only contains "repohasfile:" and "type:symbol"
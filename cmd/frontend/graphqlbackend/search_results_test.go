@@ -66,10 +66,11 @@ func TestSearchResults(t *testing.T) {
 			calledReposList = true
 
 			want := db.ReposListOptions{
-				OnlyRepoIDs:     true,
-				Enabled:         true,
-				IncludePatterns: []string{"r", "p"},
-				LimitOffset:     limitOffset,
+				OnlyRepoIDs:       true,
+				Enabled:           true,
+				IncludePatterns:   []string{"r", "p"},
+				LimitOffset:       limitOffset,
+				NoArchivedMirrors: true,
 			}
 			if !reflect.DeepEqual(op, want) {
 				t.Fatalf("got %+v, want %+v", op, want)
@@ -100,9 +101,10 @@ func TestSearchResults(t *testing.T) {
 			calledReposList = true
 
 			want := db.ReposListOptions{
-				OnlyRepoIDs: true,
-				Enabled:     true,
-				LimitOffset: limitOffset,
+				OnlyRepoIDs:       true,
+				Enabled:           true,
+				LimitOffset:       limitOffset,
+				NoArchivedMirrors: true,
 			}
 
 			if !reflect.DeepEqual(op, want) {
@@ -171,9 +173,10 @@ func TestSearchResults(t *testing.T) {
 			calledReposList = true
 
 			want := db.ReposListOptions{
-				OnlyRepoIDs: true,
-				Enabled:     true,
-				LimitOffset: limitOffset,
+				OnlyRepoIDs:       true,
+				Enabled:           true,
+				LimitOffset:       limitOffset,
+				NoArchivedMirrors: true,
 			}
 
 			if !reflect.DeepEqual(op, want) {
@@ -235,6 +238,45 @@ func TestSearchResults(t *testing.T) {
 			t.Error("calledSearchSymbols")
 		}
 	})
+
+	t.Run("literal quotes retried without quotes", func(t *testing.T) {
+		db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+			return []*types.Repo{{ID: 1, Name: "repo"}}, nil
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		db.Mocks.Repos.MockGetByName(t, "repo", 1)
+		db.Mocks.Repos.MockGet(t, 1)
+		db.Mocks.Settings.GetLatest = func(context.Context, api.SettingsSubject) (*api.Settings, error) {
+			return &api.Settings{Contents: `{"search.retryWithoutQuotes": true}`}, nil
+		}
+
+		mockSearchRepositories = func(args *search.Args) ([]searchResultResolver, *searchResultsCommon, error) {
+			return nil, &searchResultsCommon{}, nil
+		}
+		defer func() { mockSearchRepositories = nil }()
+
+		var patterns []string
+		mockSearchFilesInRepos = func(args *search.Args) ([]*fileMatchResolver, *searchResultsCommon, error) {
+			patterns = append(patterns, args.Pattern.Pattern)
+			if strings.Contains(args.Pattern.Pattern, `"`) {
+				return nil, &searchResultsCommon{}, nil
+			}
+			return []*fileMatchResolver{
+				{
+					uri:          "git://repo?rev#dir/file",
+					JPath:        "dir/file",
+					JLineMatches: []*lineMatch{{JLineNumber: 123}},
+					repo:         &types.Repo{ID: 1},
+				},
+			}, &searchResultsCommon{}, nil
+		}
+		defer func() { mockSearchFilesInRepos = nil }()
+
+		testCallResults(t, `"foo"`, "V2", []string{"dir/file:123"})
+		if want := []string{`"foo"`, "foo"}; !reflect.DeepEqual(patterns, want) {
+			t.Errorf("got patterns %q, want %q", patterns, want)
+		}
+	})
 }
 
 func BenchmarkSearchResults(b *testing.B) {
@@ -456,6 +498,7 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 		},
 		"p1 p2": {
 			Pattern:                "(p1).*?(p2)",
+			AndPatterns:            []string{"p1", "p2"},
 			IsRegExp:               true,
 			PathPatternsAreRegExps: true,
 		},
@@ -478,6 +521,18 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 			PathPatternsAreRegExps: true,
 			IncludePatterns:        []string{"f1", "f2"},
 		},
+		"p -content:c": {
+			Pattern:                "p",
+			NegatedPatterns:        []string{"c"},
+			IsRegExp:               true,
+			PathPatternsAreRegExps: true,
+		},
+		"content:p1 p2": {
+			Pattern:                "(p2).*?(p1)",
+			AndPatterns:            []string{"p2", "p1"},
+			IsRegExp:               true,
+			PathPatternsAreRegExps: true,
+		},
 		"p -file:f": {
 			Pattern:                "p",
 			IsRegExp:               true,
@@ -523,7 +578,7 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 				t.Fatal(err)
 			}
 			sr := searchResolver{query: query}
-			p, err := sr.getPatternInfo(nil)
+			p, err := sr.getPatternInfo(context.Background(), nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -537,6 +592,13 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 }
 
 func TestSearchResolver_DynamicFilters(t *testing.T) {
+	mockResolveRepoGroups = func() (map[string][]*types.Repo, error) {
+		return map[string][]*types.Repo{
+			"frontend": {{Name: "web"}, {Name: "mobile"}},
+		}, nil
+	}
+	defer func() { mockResolveRepoGroups = nil }()
+
 	repo := &types.Repo{Name: "testRepo"}
 
 	repoMatch := &RepositoryResolver{
@@ -565,6 +627,14 @@ func TestSearchResolver_DynamicFilters(t *testing.T) {
 		inputRev: &rev,
 	}
 
+	dirFileMatch := func(path string) *fileMatchResolver {
+		return &fileMatchResolver{JPath: path, repo: repo}
+	}
+
+	web := &types.Repo{Name: "web"}
+	mobile := &types.Repo{Name: "mobile"}
+	backend := &types.Repo{Name: "backend"}
+
 	type testCase struct {
 		descr                     string
 		searchResults             []searchResultResolver
@@ -620,6 +690,40 @@ func TestSearchResolver_DynamicFilters(t *testing.T) {
 			},
 		},
 
+		{
+			descr: "file matches in several directories",
+			searchResults: []searchResultResolver{
+				dirFileMatch("client/web/src/a.go"),
+				dirFileMatch("client/web/src/b.go"),
+				dirFileMatch("client/shared/c.go"),
+				dirFileMatch("server/d.go"),
+			},
+			expectedDynamicFilterStrs: map[string]struct{}{
+				`repo:^testRepo$`:       {},
+				`lang:go`:               {},
+				`case:yes`:              {},
+				`file:^client/`:         {},
+				`file:^client/web/src/`: {},
+			},
+		},
+
+		{
+			descr: "file matches in repos of a repo group",
+			searchResults: []searchResultResolver{
+				&fileMatchResolver{JPath: "a.md", repo: web},
+				&fileMatchResolver{JPath: "b.md", repo: mobile},
+				&fileMatchResolver{JPath: "c.md", repo: backend},
+			},
+			expectedDynamicFilterStrs: map[string]struct{}{
+				`repo:^web$`:         {},
+				`repo:^mobile$`:      {},
+				`repo:^backend$`:     {},
+				`lang:markdown`:      {},
+				`case:yes`:           {},
+				`repogroup:frontend`: {},
+			},
+		},
+
 		// If there are no search results, no filters should be displayed.
 		{
 			descr:                     "no results",
@@ -630,7 +734,7 @@ func TestSearchResolver_DynamicFilters(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.descr, func(t *testing.T) {
-			actualDynamicFilters := (&searchResultsResolver{results: test.searchResults}).DynamicFilters()
+			actualDynamicFilters := (&searchResultsResolver{results: test.searchResults}).DynamicFilters(context.Background())
 			actualDynamicFilterStrs := make(map[string]struct{})
 
 			for _, filter := range actualDynamicFilters {
@@ -0,0 +1,141 @@
+package graphqlbackend
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/neelance/parallel"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// dedupRevFileMatches collapses the file matches of a file whose contents
+// are identical in several searched revisions of the same repository (such
+// as the tags of a repository) into the first of them, which lists all those
+// revisions as foundInRevs.
+//
+// File matches of the same path with the same line matches in different
+// revisions are candidates, and they are collapsed if their files have the
+// same blob OID. Candidates whose blob OID can't be determined are kept.
+//
+// The order of results is preserved.
+func dedupRevFileMatches(ctx context.Context, results []searchResultResolver) []searchResultResolver {
+	type candidateKey struct {
+		repo api.RepoName
+		hash [sha256.Size]byte
+	}
+
+	var (
+		candidates = map[candidateKey][]*fileMatchResolver{}
+		commits    = map[candidateKey]map[api.CommitID]bool{}
+	)
+	for _, result := range results {
+		fm, ok := result.ToFileMatch()
+		if !ok || len(fm.JLineMatches) == 0 || fm.repo == nil || fm.commitID == "" {
+			continue
+		}
+		k := candidateKey{repo: fm.repo.Name, hash: fm.contentHash()}
+		if commits[k] == nil {
+			commits[k] = map[api.CommitID]bool{}
+		}
+		if commits[k][fm.commitID] {
+			continue // the same revision was searched twice (e.g. by name and by commit)
+		}
+		commits[k][fm.commitID] = true
+		candidates[k] = append(candidates[k], fm)
+	}
+
+	var toStat []*fileMatchResolver
+	for _, group := range candidates {
+		if len(group) > 1 {
+			toStat = append(toStat, group...)
+		}
+	}
+	if len(toStat) == 0 {
+		return results
+	}
+
+	oids := fileMatchBlobOIDs(ctx, toStat)
+
+	collapsed := map[*fileMatchResolver]bool{}
+	for _, group := range candidates {
+		if len(group) < 2 {
+			continue
+		}
+
+		kept := map[git.OID]*fileMatchResolver{}
+		for _, fm := range group {
+			oid, ok := oids[fm]
+			if !ok {
+				continue
+			}
+			first, ok := kept[oid]
+			if !ok {
+				kept[oid] = fm
+				continue
+			}
+			if len(first.foundInRevs) == 0 {
+				first.foundInRevs = []string{first.rev()}
+			}
+			first.foundInRevs = append(first.foundInRevs, fm.rev())
+			collapsed[fm] = true
+		}
+	}
+	if len(collapsed) == 0 {
+		return results
+	}
+
+	deduped := results[:0]
+	for _, result := range results {
+		if fm, ok := result.ToFileMatch(); ok && collapsed[fm] {
+			continue
+		}
+		deduped = append(deduped, result)
+	}
+	return deduped
+}
+
+// fileMatchBlobOIDs returns the blob OIDs of the files of the given file
+// matches. File matches whose blob OIDs couldn't be determined are omitted.
+func fileMatchBlobOIDs(ctx context.Context, fms []*fileMatchResolver) map[*fileMatchResolver]git.OID {
+	var (
+		run  = parallel.NewRun(8) // number of concurrent git commands
+		mu   sync.Mutex
+		oids = make(map[*fileMatchResolver]git.OID, len(fms))
+	)
+	for _, fm := range fms {
+		fm := fm // shadow so it doesn't change in the goroutine
+		run.Acquire()
+		goroutine.Go(func() {
+			defer run.Release()
+
+			fi, err := git.Stat(ctx, gitserver.Repo{Name: fm.repo.Name}, fm.commitID, fm.JPath)
+			if err != nil {
+				log15.Warn("Failed to get blob OID of file match", "repo", fm.repo.Name, "commitID", fm.commitID, "path", fm.JPath, "err", err)
+				return
+			}
+			obj, ok := fi.Sys().(interface{ OID() git.OID })
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			oids[fm] = obj.OID()
+			mu.Unlock()
+		})
+	}
+	_ = run.Wait()
+	return oids
+}
+
+// rev returns the revision that fm was found in, as the user specified it.
+func (fm *fileMatchResolver) rev() string {
+	if fm.inputRev != nil && *fm.inputRev != "" {
+		return *fm.inputRev
+	}
+	return string(fm.commitID)
+}
@@ -0,0 +1,72 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+type blobInfo git.OID
+
+func (oid blobInfo) OID() git.OID { return git.OID(oid) }
+
+func TestDedupRevFileMatches(t *testing.T) {
+	// The blob OIDs of main.go in each commit. v3 changed main.go without
+	// changing the matched lines.
+	blobs := map[api.CommitID]byte{"v1-commit": 1, "v2-commit": 1, "v3-commit": 2, "v4-commit": 1}
+	git.Mocks.Stat = func(commit api.CommitID, path string) (os.FileInfo, error) {
+		b, ok := blobs[commit]
+		if !ok {
+			return nil, errors.New("boom")
+		}
+		return &util.FileInfo{Name_: path, Sys_: blobInfo(git.OID{b})}, nil
+	}
+	defer git.ResetMocks()
+
+	repo := &types.Repo{Name: "github.com/a/foo"}
+	fileMatch := func(rev, path, preview string) *fileMatchResolver {
+		return &fileMatchResolver{
+			JPath:        path,
+			JLineMatches: []*lineMatch{{JPreview: preview, JLineNumber: 1, JOffsetAndLengths: [][2]int32{{0, 3}}}},
+			uri:          "git://" + string(repo.Name) + "?" + rev + "#" + path,
+			repo:         repo,
+			commitID:     api.CommitID(rev + "-commit"),
+			inputRev:     &rev,
+		}
+	}
+
+	var (
+		v1 = fileMatch("v1", "main.go", "foo()")
+		v2 = fileMatch("v2", "main.go", "foo()")
+		v3 = fileMatch("v3", "main.go", "foo()")
+		v4 = fileMatch("v4", "main.go", "foo()")
+		// The blob OID of v5 can't be determined, so it is kept.
+		v5 = fileMatch("v5", "main.go", "foo()")
+		// Matches with other lines are not candidates.
+		changed = fileMatch("v2", "main.go", "foo(1)")
+		other   = fileMatch("v2", "other.go", "foo()")
+	)
+
+	results := []searchResultResolver{v1, other, v2, v3, changed, v4, v5}
+	got := dedupRevFileMatches(context.Background(), results)
+
+	want := []searchResultResolver{v1, other, v3, changed, v5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %d results, want %d", len(got), len(want))
+	}
+	if want := []string{"v1", "v2", "v4"}; !reflect.DeepEqual(v1.FoundInRevs(), want) {
+		t.Errorf("got foundInRevs %q, want %q", v1.FoundInRevs(), want)
+	}
+	for _, fm := range []*fileMatchResolver{v3, v5, changed, other} {
+		if len(fm.FoundInRevs()) != 0 {
+			t.Errorf("%s@%s: got foundInRevs %q, want none", fm.JPath, *fm.inputRev, fm.FoundInRevs())
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxRevRangeCommits is the maximum number of commits of a revision range
+// whose changes are searched.
+const maxRevRangeCommits = 100
+
+// fileMatchRangeChange is a resolver for the GraphQL type
+// `FileMatchRangeChange`.
+type fileMatchRangeChange struct {
+	commit *GitCommitResolver
+	added  bool
+}
+
+func (r *fileMatchRangeChange) Commit() *GitCommitResolver { return r.commit }
+func (r *fileMatchRangeChange) Added() bool                { return r.added }
+
+// searchFilesInRevRange searches the file contents of every commit in the
+// revision range rev ("base..head"). Instead of searching each commit's tree,
+// it pushes the pattern down to `git log -S`, which finds the commits that
+// changed the number of matches, and returns the matching lines each of them
+// added or removed. Added lines are matched in the file as of the commit, and
+// removed lines in the file as of its first parent.
+func searchFilesInRevRange(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo) (matches []*fileMatchResolver, limitHit bool, err error) {
+	if info.Pattern == "" || !info.PatternMatchesContent {
+		// Only file contents are searched in revision ranges.
+		return nil, false, nil
+	}
+	if strings.HasPrefix(rev, "-") {
+		// A revspec starting with "-" would be interpreted as a `git log` flag.
+		return nil, false, fmt.Errorf("invalid revspec: %q", rev)
+	}
+
+	expr := info.Pattern
+	if !info.IsRegExp {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if !info.IsCaseSensitive {
+		expr = "(?i:" + expr + ")"
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rawResults, complete, err := git.RawLogDiffSearch(ctx, gitserverRepo, git.RawLogDiffSearchOptions{
+		Query: git.TextSearchOptions{
+			Pattern:         info.Pattern,
+			IsRegExp:        info.IsRegExp,
+			IsCaseSensitive: info.IsCaseSensitive,
+		},
+		MatchChangedOccurrenceCount: true,
+		Paths: git.PathOptions{
+			IncludePatterns: info.IncludePatterns,
+			ExcludePattern:  info.ExcludePattern,
+			IsCaseSensitive: info.PathPatternsAreCaseSensitive,
+			IsRegExp:        info.PathPatternsAreRegExps,
+		},
+		Diff:              true,
+		OnlyMatchingHunks: true,
+		Args: []string{
+			"--no-prefix",
+			"--unified=0",
+			"--max-count=" + strconv.Itoa(maxRevRangeCommits+1),
+			rev,
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The commits of the range weren't all searched if git log timed out.
+	limitHit = !complete
+	if len(rawResults) > maxRevRangeCommits {
+		limitHit = true
+		rawResults = rawResults[:maxRevRangeCommits]
+	}
+
+	repoResolver := &RepositoryResolver{repo: repo}
+	for _, rawResult := range rawResults {
+		if rawResult.Diff == nil {
+			continue
+		}
+		fileDiffs, err := diff.ParseMultiFileDiff([]byte(rawResult.Diff.Raw))
+		if err != nil {
+			return nil, false, err
+		}
+
+		commit := rawResult.Commit
+		change := func(added bool) *fileMatchRangeChange {
+			return &fileMatchRangeChange{commit: toGitCommitResolver(repoResolver, &commit), added: added}
+		}
+		var parent api.CommitID
+		if len(commit.Parents) > 0 {
+			parent = commit.Parents[0]
+		}
+
+		for _, fileDiff := range fileDiffs {
+			added, removed := revRangeLineMatches(pattern, fileDiff)
+			if len(added) > 0 && fileDiff.NewName != "/dev/null" {
+				matches = append(matches, &fileMatchResolver{
+					JPath:        fileDiff.NewName,
+					JLineMatches: added,
+					uri:          fileMatchURI(repo.Name, string(commit.ID), fileDiff.NewName),
+					repo:         repo,
+					commitID:     commit.ID,
+					rangeChange:  change(true),
+				})
+			}
+			if len(removed) > 0 && fileDiff.OrigName != "/dev/null" && parent != "" {
+				matches = append(matches, &fileMatchResolver{
+					JPath:        fileDiff.OrigName,
+					JLineMatches: removed,
+					uri:          fileMatchURI(repo.Name, string(parent), fileDiff.OrigName),
+					repo:         repo,
+					commitID:     parent,
+					rangeChange:  change(false),
+				})
+			}
+		}
+	}
+
+	return matches, limitHit, nil
+}
+
+// revRangeLineMatches returns the matches of pattern in the lines that the
+// file diff adds and removes, with the line numbers of the new and the
+// original file respectively.
+func revRangeLineMatches(pattern *regexp.Regexp, fileDiff *diff.FileDiff) (added, removed []*lineMatch) {
+	for _, hunk := range fileDiff.Hunks {
+		origLine, newLine := hunk.OrigStartLine, hunk.NewStartLine
+		for _, line := range strings.Split(strings.TrimSuffix(string(hunk.Body), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			switch text := line[1:]; line[0] {
+			case '+':
+				if lm := lineMatchOf(pattern, text, newLine); lm != nil {
+					added = append(added, lm)
+				}
+				newLine++
+			case '-':
+				if lm := lineMatchOf(pattern, text, origLine); lm != nil {
+					removed = append(removed, lm)
+				}
+				origLine++
+			default:
+				origLine++
+				newLine++
+			}
+		}
+	}
+	return added, removed
+}
+
+// lineMatchOf returns the matches of pattern in the line with the given
+// 1-based line number, or nil if there are none.
+func lineMatchOf(pattern *regexp.Regexp, line string, lineNumber int32) *lineMatch {
+	locs := pattern.FindAllStringIndex(line, -1)
+	if len(locs) == 0 {
+		return nil
+	}
+	offsets := make([][2]int32, len(locs))
+	for i, loc := range locs {
+		offset := utf8.RuneCountInString(line[:loc[0]])
+		length := utf8.RuneCountInString(line[loc[0]:loc[1]])
+		offsets[i] = [2]int32{int32(offset), int32(length)}
+	}
+	return &lineMatch{
+		JPreview:          line,
+		JOffsetAndLengths: offsets,
+		JLineNumber:       lineNumber - 1,
+	}
+}
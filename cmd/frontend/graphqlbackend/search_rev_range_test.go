@@ -0,0 +1,83 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestSearchFilesInRevRange(t *testing.T) {
+	git.Mocks.RawLogDiffSearch = func(opt git.RawLogDiffSearchOptions) ([]*git.LogCommitSearchResult, bool, error) {
+		if !opt.MatchChangedOccurrenceCount {
+			t.Error("want the pattern to be pushed down to git log -S")
+		}
+		if want := "v1..v2"; opt.Args[len(opt.Args)-1] != want {
+			t.Errorf("got args %v, want the range %q last", opt.Args, want)
+		}
+		return []*git.LogCommitSearchResult{
+			{
+				Commit: git.Commit{ID: "c2", Parents: []api.CommitID{"c1"}},
+				Diff: &git.Diff{Raw: `diff --git a.go a.go
+--- a.go
++++ a.go
+@@ -3,1 +3,2 @@
+-old foo
++new foo foo
++unrelated
+`},
+			},
+		}, true, nil
+	}
+	defer git.ResetMocks()
+
+	repo := &types.Repo{ID: 1, Name: "repo"}
+	info := &search.PatternInfo{Pattern: "foo", PatternMatchesContent: true}
+	matches, limitHit, err := searchFilesInRevRange(context.Background(), repo, gitserver.Repo{Name: repo.Name}, "v1..v2", info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limitHit {
+		t.Error("limitHit")
+	}
+
+	type match struct {
+		uri      string
+		commitID api.CommitID
+		added    bool
+		changeBy GitObjectID
+		lines    []lineMatch
+	}
+	var got []match
+	for _, fm := range matches {
+		m := match{uri: fm.uri, commitID: fm.commitID, added: fm.RangeChange().Added(), changeBy: fm.RangeChange().Commit().OID()}
+		for _, lm := range fm.JLineMatches {
+			m.lines = append(m.lines, *lm)
+		}
+		got = append(got, m)
+	}
+	want := []match{
+		{
+			uri:      "git://repo?c2#a.go",
+			commitID: "c2",
+			added:    true,
+			changeBy: "c2",
+			lines:    []lineMatch{{JPreview: "new foo foo", JOffsetAndLengths: [][2]int32{{4, 3}, {8, 3}}, JLineNumber: 2}},
+		},
+		{
+			uri:      "git://repo?c1#a.go",
+			commitID: "c1",
+			added:    false,
+			changeBy: "c2",
+			lines:    []lineMatch{{JPreview: "old foo", JOffsetAndLengths: [][2]int32{{4, 3}}, JLineNumber: 2}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// searchStreamDone is the data of the "done" event of a streaming search.
+type searchStreamDone struct {
+	MatchCount          int32              `json:"matchCount"`
+	LimitHit            bool               `json:"limitHit"`
+	Cloning             []string           `json:"cloning,omitempty"`
+	Missing             []string           `json:"missing,omitempty"`
+	Timedout            []string           `json:"timedout,omitempty"`
+	IndexUnavailable    bool               `json:"indexUnavailable,omitempty"`
+	IndexFallbackCount  int32              `json:"indexUnavailableFallbackCount,omitempty"`
+	ElapsedMilliseconds int32              `json:"elapsedMilliseconds"`
+	Alert               *searchStreamAlert `json:"alert,omitempty"`
+}
+
+type searchStreamAlert struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// StreamSearch runs a search for the query (with the given pattern type, or
+// the default one if empty) as the GraphQL search field does, but sends the
+// results to the client as each search backend (zoekt, the searchers, commit
+// search, ...) produces them instead of once all of them are done, so that the
+// results of searches over many repositories are shown immediately. send is
+// called with the name and the JSON-encodable data of each event, never
+// concurrently:
+//
+//   - "results": a batch of results (in the format of search job results). A
+//     file match may be sent again in a later batch with more matches (e.g. a
+//     symbol match with the text matches of the same file); it replaces the
+//     earlier one.
+//   - "done": the summary of the search, sent last.
+//
+// Unlike the results of the GraphQL search field, the streamed results are not
+// sorted, and identical file matches in forks are not collapsed.
+//
+// If send returns an error (e.g. because the client went away), the search is
+// canceled and the error is returned.
+func StreamSearch(ctx context.Context, queryString, patternType string, send func(event string, data interface{}) error) error {
+	args := &searchArgs{Version: "V2", Query: queryString}
+	if patternType != "" {
+		args.PatternType = &patternType
+	}
+	s, err := (&schemaResolver{}).Search(args)
+	if err != nil {
+		return err
+	}
+	sr, ok := s.(*searchResolver)
+	if !ok {
+		if d, ok := s.(*didYouMeanQuotedResolver); ok && d.err != nil {
+			return &badRequestError{d.err}
+		}
+		return &badRequestError{fmt.Errorf("invalid query %q", queryString)}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		sendErr error
+	)
+	sr.stream = func(results []searchResultResolver) {
+		batch := make([]searchJobResult, 0, len(results))
+		for _, result := range results {
+			if r, ok := toSearchJobResult(result); ok {
+				batch = append(batch, r)
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr != nil {
+			return
+		}
+		if sendErr = send("results", batch); sendErr != nil {
+			cancel()
+		}
+	}
+
+	rr, err := sr.resultsWithTimeoutSuggestion(ctx)
+
+	// Results are only streamed while doResults runs, so there are no
+	// concurrent sends anymore.
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return err
+	}
+
+	done := searchStreamDone{
+		MatchCount:          rr.MatchCount(),
+		LimitHit:            rr.limitHit,
+		Cloning:             repoNames(rr.cloning),
+		Missing:             repoNames(rr.missing),
+		Timedout:            repoNames(rr.timedout),
+		IndexUnavailable:    rr.indexUnavailable,
+		IndexFallbackCount:  rr.IndexUnavailableFallbackCount(),
+		ElapsedMilliseconds: rr.ElapsedMilliseconds(),
+	}
+	if rr.alert != nil {
+		done.Alert = &searchStreamAlert{Title: rr.alert.title, Description: rr.alert.description}
+	}
+	return send("done", done)
+}
+
+// streamResults sends a batch of results that a search backend produced to the
+// client of a streaming search, if any. The filters that doResults applies to
+// the final results are applied to the batch first.
+func (r *searchResolver) streamResults(ctx context.Context, results []searchResultResolver) {
+	if r.stream == nil || len(results) == 0 {
+		return
+	}
+
+	// The filters modify their input, which is shared with doResults.
+	results = append([]searchResultResolver(nil), results...)
+	r.stream(r.filterResults(ctx, results))
+}
+
+func repoNames(repos []*types.Repo) []string {
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = string(repo.Name)
+	}
+	return names
+}
@@ -70,7 +70,26 @@ func (r *searchResolver) Suggestions(ctx context.Context, args *searchSuggestion
 
 		var effectiveRepoFieldValues []string
 		if len(r.query.Values(query.FieldDefault)) == 1 && (len(r.query.Fields) == 1 || (len(r.query.Fields) == 2 && len(r.query.Values(query.FieldRepoGroup)) == 1)) {
-			effectiveRepoFieldValues = append(effectiveRepoFieldValues, asString(r.query.Values(query.FieldDefault)[0]))
+			term := asString(r.query.Values(query.FieldDefault)[0])
+
+			// A single literal term is looked up in the names and descriptions of all
+			// repositories with the trigram indexes of the repo table.
+			if lit, ok := literalPattern(term); ok && lit != "" && len(r.query.Fields) == 1 {
+				repos, err := db.Repos.Search(ctx, db.ReposSearchOptions{
+					Query:       lit,
+					LimitOffset: &db.LimitOffset{Limit: maxSearchSuggestions},
+				})
+				resolvers := make([]*searchSuggestionResolver, 0, len(repos))
+				for _, repo := range repos {
+					resolvers = append(resolvers, newSearchResultResolver(
+						&RepositoryResolver{repo: repo},
+						math.MaxInt32,
+					))
+				}
+				return resolvers, err
+			}
+
+			effectiveRepoFieldValues = append(effectiveRepoFieldValues, term)
 		} else if len(r.query.Values(query.FieldRepo)) > 0 && ((len(r.query.Values(query.FieldRepoGroup)) > 0 && len(r.query.Fields) == 2) || (len(r.query.Values(query.FieldRepoGroup)) == 0 && len(r.query.Fields) == 1)) {
 			effectiveRepoFieldValues, _ = r.query.RegexpPatterns(query.FieldRepo)
 		}
@@ -193,7 +212,7 @@ func (r *searchResolver) Suggestions(ctx context.Context, args *searchSuggestion
 			return nil, err
 		}
 
-		p, err := r.getPatternInfo(nil)
+		p, err := r.getPatternInfo(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
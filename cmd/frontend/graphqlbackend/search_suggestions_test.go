@@ -64,21 +64,25 @@ func TestSearchSuggestions(t *testing.T) {
 	})
 
 	t.Run("single term", func(t *testing.T) {
-		var calledReposListAll, calledReposListFoo bool
+		var calledReposListAll, calledReposSearchFoo bool
 		db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
-			wantFoo := db.ReposListOptions{IncludePatterns: []string{"foo"}, OnlyRepoIDs: true, Enabled: true, LimitOffset: limitOffset} // when treating term as repo: field
-			wantAll := db.ReposListOptions{OnlyRepoIDs: true, Enabled: true, LimitOffset: limitOffset}                                   // when treating term as text query
+			wantAll := db.ReposListOptions{OnlyRepoIDs: true, Enabled: true, LimitOffset: limitOffset, NoArchivedMirrors: true} // when treating term as text query
 			if reflect.DeepEqual(op, wantAll) {
 				calledReposListAll = true
 				return []*types.Repo{{Name: "bar-repo"}}, nil
-			} else if reflect.DeepEqual(op, wantFoo) {
-				calledReposListFoo = true
-				return []*types.Repo{{Name: "foo-repo"}}, nil
 			} else {
-				t.Errorf("got %+v, want %+v or %+v", op, wantFoo, wantAll)
+				t.Errorf("got %+v, want %+v", op, wantAll)
 			}
 			return nil, nil
 		}
+		db.Mocks.Repos.Search = func(_ context.Context, op db.ReposSearchOptions) ([]*types.Repo, error) {
+			// when treating term as a repository name or description
+			if want := (db.ReposSearchOptions{Query: "foo", LimitOffset: &db.LimitOffset{Limit: maxSearchSuggestions}}); !reflect.DeepEqual(op, want) {
+				t.Errorf("got %+v, want %+v", op, want)
+			}
+			calledReposSearchFoo = true
+			return []*types.Repo{{Name: "foo-repo"}}, nil
+		}
 		db.Mocks.Repos.MockGetByName(t, "repo", 1)
 		backend.Mocks.Repos.MockResolveRev_NoCheck(t, api.CommitID("deadbeef"))
 		defer func() { db.Mocks = db.MockStores{} }()
@@ -103,8 +107,8 @@ func TestSearchSuggestions(t *testing.T) {
 			if !calledReposListAll {
 				t.Error("!calledReposListAll")
 			}
-			if !calledReposListFoo {
-				t.Error("!calledReposListFoo")
+			if !calledReposSearchFoo {
+				t.Error("!calledReposSearchFoo")
 			}
 			if !calledSearchFilesInRepos {
 				t.Error("!calledSearchFilesInRepos")
@@ -135,8 +139,8 @@ func TestSearchSuggestions(t *testing.T) {
 		db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
 			mu.Lock()
 			defer mu.Unlock()
-			wantReposInGroup := db.ReposListOptions{IncludePatterns: []string{`^foo-repo1$|^repo3$`}, Enabled: true, LimitOffset: limitOffset}    // when treating term as repo: field
-			wantFooRepo3 := db.ReposListOptions{IncludePatterns: []string{"foo", `^foo-repo1$|^repo3$`}, Enabled: true, LimitOffset: limitOffset} // when treating term as repo: field
+			wantReposInGroup := db.ReposListOptions{IncludePatterns: []string{`^foo-repo1$|^repo3$`}, Enabled: true, LimitOffset: limitOffset, NoArchivedMirrors: true}    // when treating term as repo: field
+			wantFooRepo3 := db.ReposListOptions{IncludePatterns: []string{"foo", `^foo-repo1$|^repo3$`}, Enabled: true, LimitOffset: limitOffset, NoArchivedMirrors: true} // when treating term as repo: field
 			if reflect.DeepEqual(op, wantReposInGroup) {
 				calledReposListReposInGroup = true
 				return []*types.Repo{
@@ -210,10 +214,11 @@ func TestSearchSuggestions(t *testing.T) {
 			calledReposList = true
 
 			want := db.ReposListOptions{
-				IncludePatterns: []string{"foo"},
-				OnlyRepoIDs:     true,
-				Enabled:         true,
-				LimitOffset:     limitOffset,
+				IncludePatterns:   []string{"foo"},
+				OnlyRepoIDs:       true,
+				Enabled:           true,
+				LimitOffset:       limitOffset,
+				NoArchivedMirrors: true,
 			}
 			if !reflect.DeepEqual(op, want) {
 				t.Errorf("got %+v, want %+v", op, want)
@@ -296,10 +301,11 @@ func TestSearchSuggestions(t *testing.T) {
 			defer mu.Unlock()
 			calledReposList = true
 			want := db.ReposListOptions{
-				IncludePatterns: []string{"foo"},
-				OnlyRepoIDs:     true,
-				Enabled:         true,
-				LimitOffset:     limitOffset,
+				IncludePatterns:   []string{"foo"},
+				OnlyRepoIDs:       true,
+				Enabled:           true,
+				LimitOffset:       limitOffset,
+				NoArchivedMirrors: true,
 			}
 
 			if !reflect.DeepEqual(op, want) {
@@ -84,9 +84,15 @@ func searchSymbols(ctx context.Context, args *search.Args, limit int) (res []*fi
 			if ctx.Err() == nil {
 				log15.Warn("zoektIndexedRepos failed", "error", err)
 			}
-			common.indexUnavailable = true
+			searcherRepos = indexUnavailableFallback(common, args.Repos)
 			err = nil
 		}
+
+		// Zoekt only has symbols for the indexed HEAD commit, so other
+		// revisions are searched with the symbols service.
+		var unindexed []*search.RepositoryRevisions
+		zoektRepos, unindexed = splitSymbolIndexedRevs(zoektRepos)
+		searcherRepos = append(searcherRepos, unindexed...)
 	}
 
 	common.repos = make([]*types.Repo, len(args.Repos))
@@ -215,6 +221,26 @@ func searchSymbols(ctx context.Context, args *search.Args, limit int) (res []*fi
 	return res2, common, err
 }
 
+// splitSymbolIndexedRevs splits repos that are indexed by zoekt into those
+// whose requested revision is the indexed HEAD commit, whose symbols can be
+// searched with zoekt, and the rest.
+func splitSymbolIndexedRevs(repos []*search.RepositoryRevisions) (indexed, unindexed []*search.RepositoryRevisions) {
+	for _, repo := range repos {
+		if isIndexedHEADRev(repo) {
+			indexed = append(indexed, repo)
+		} else {
+			unindexed = append(unindexed, repo)
+		}
+	}
+	return indexed, unindexed
+}
+
+// isIndexedHEADRev reports whether the single revision requested for repo is
+// the commit of HEAD that zoekt indexed.
+func isIndexedHEADRev(repo *search.RepositoryRevisions) bool {
+	return len(repo.Revs) == 1 && searchesIndexedCommit(repo.Revs, repo.IndexedHEADCommit())
+}
+
 // limitSymbolResults returns a new version of res containing no more than limit symbol matches.
 func limitSymbolResults(res []*fileMatchResolver, limit int) []*fileMatchResolver {
 	res2 := make([]*fileMatchResolver, 0, len(res))
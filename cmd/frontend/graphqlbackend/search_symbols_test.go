@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	lsp "github.com/sourcegraph/go-lsp"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gituri"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -257,3 +260,48 @@ func Test_limitingSymbolResults(t *testing.T) {
 		}
 	})
 }
+
+func TestSplitSymbolIndexedRevs(t *testing.T) {
+	mkRepo := func(name string, revs ...search.RevisionSpecifier) *search.RepositoryRevisions {
+		r := &search.RepositoryRevisions{Repo: &types.Repo{Name: api.RepoName(name)}, Revs: revs}
+		r.SetIndexedHEADCommit("deadbeef")
+		return r
+	}
+
+	repos := []*search.RepositoryRevisions{
+		mkRepo("default", search.RevisionSpecifier{}),
+		mkRepo("head", search.RevisionSpecifier{RevSpec: "HEAD"}),
+		mkRepo("indexed-commit", search.RevisionSpecifier{RevSpec: "deadbeef"}),
+		mkRepo("branch", search.RevisionSpecifier{RevSpec: "feature"}),
+		mkRepo("glob", search.RevisionSpecifier{RefGlob: "refs/heads/*"}),
+		mkRepo("multiple", search.RevisionSpecifier{}, search.RevisionSpecifier{RevSpec: "feature"}),
+		mkRepo("none"),
+	}
+
+	indexed, unindexed := splitSymbolIndexedRevs(repos)
+
+	names := func(repos []*search.RepositoryRevisions) (names []string) {
+		for _, r := range repos {
+			names = append(names, string(r.Repo.Name))
+		}
+		return names
+	}
+	if diff := cmp.Diff([]string{"default", "head", "indexed-commit"}, names(indexed)); diff != "" {
+		t.Errorf("indexed mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"branch", "glob", "multiple", "none"}, names(unindexed)); diff != "" {
+		t.Errorf("unindexed mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSymbolRange_zoektPattern(t *testing.T) {
+	// Symbols from zoekt use their line as the ctags pattern.
+	s := protocol.Symbol{Name: "Foo", Line: 3, Pattern: "/^func Foo() {$/"}
+	want := lsp.Range{
+		Start: lsp.Position{Line: 2, Character: 5},
+		End:   lsp.Position{Line: 2, Character: 8},
+	}
+	if got := symbolRange(s); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
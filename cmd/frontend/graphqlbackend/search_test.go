@@ -3,16 +3,21 @@ package graphqlbackend
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestSearch(t *testing.T) {
@@ -308,6 +313,7 @@ func Test_defaultRepositories(t *testing.T) {
 func Test_detectSearchType(t *testing.T) {
 	typeRegexp := "regexp"
 	typeLiteral := "literal"
+	typeFuzzy := "fuzzy"
 	testCases := []struct {
 		name        string
 		version     string
@@ -323,6 +329,8 @@ func Test_detectSearchType(t *testing.T) {
 		{"V2, override regexp pattern type", "V2", &typeLiteral, "patterntype:regexp", "regexp"},
 		{"V2, override regex variant pattern type", "V2", &typeLiteral, "patterntype:regex", "regexp"},
 		{"V1, override literal pattern type", "V1", &typeRegexp, "patterntype:literal", "literal"},
+		{"V2, fuzzy pattern type", "V2", &typeFuzzy, "", "fuzzy"},
+		{"V2, override fuzzy pattern type", "V2", &typeLiteral, "patterntype:fuzzy", "fuzzy"},
 	}
 
 	for _, test := range testCases {
@@ -337,3 +345,152 @@ func Test_detectSearchType(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterFileMatchesByLanguage(t *testing.T) {
+	fileMatch := func(path string, lines ...string) *fileMatchResolver {
+		fm := &fileMatchResolver{JPath: path}
+		for i, l := range lines {
+			fm.JLineMatches = append(fm.JLineMatches, &lineMatch{JPreview: l, JLineNumber: int32(i)})
+		}
+		return fm
+	}
+	results := []searchResultResolver{
+		fileMatch("a.c", "int x;"),
+		fileMatch("b.h", "int f(void);"),
+		fileMatch("c.h", "namespace foo {"),
+		fileMatch("d.h", "@interface Foo : NSObject"),
+		fileMatch("e.h"),
+	}
+	paths := func(results []searchResultResolver) (paths []string) {
+		for _, r := range results {
+			fm, _ := r.ToFileMatch()
+			paths = append(paths, fm.JPath)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		values, negatedValues []string
+		want                  []string
+	}{
+		{want: []string{"a.c", "b.h", "c.h", "d.h", "e.h"}},
+		{values: []string{"c"}, want: []string{"a.c", "b.h", "e.h"}},
+		{values: []string{"c++"}, want: []string{"b.h", "c.h", "e.h"}},
+		{negatedValues: []string{"objective-c"}, want: []string{"a.c", "b.h", "c.h", "e.h"}},
+	}
+	for _, test := range tests {
+		// The filter reuses the slice it is given.
+		input := append([]searchResultResolver(nil), results...)
+		got := paths(filterFileMatchesByLanguage(input, test.values, test.negatedValues))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("lang:%v -lang:%v: got %v, want %v", test.values, test.negatedValues, got, test.want)
+		}
+	}
+}
+
+func TestSearchResolver_maxRepos(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		MaxReposToSearch:        10,
+		MaxReposToSearchCeiling: 100,
+	}})
+	defer conf.Mock(nil)
+
+	tests := []struct {
+		query      string
+		exhaustive bool
+		want       int
+		wantErr    bool
+	}{
+		{query: "foo", want: 0},
+		{query: "foo maxRepos:5", want: 5},
+		{query: "foo maxRepos:50", want: 50},
+		{query: "foo maxRepos:500", want: 100},
+		{query: "foo maxRepos:0", wantErr: true},
+		{query: "foo maxRepos:many", wantErr: true},
+		{query: "foo", exhaustive: true, want: 100},
+		{query: "foo maxRepos:5", exhaustive: true, want: 5},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s exhaustive:%v", test.query, test.exhaustive), func(t *testing.T) {
+			q, err := query.ParseAndCheck(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := (&searchResolver{query: q, exhaustive: test.exhaustive}).maxRepos()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveRepositories_maxRepos(t *testing.T) {
+	var all []*types.Repo
+	for i := 1; i <= 5; i++ {
+		all = append(all, &types.Repo{ID: api.RepoID(i), Name: api.RepoName(fmt.Sprintf("r%d", i))})
+	}
+	db.Mocks.Repos.List = func(_ context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		if opt.Limit < len(all) {
+			return all[:opt.Limit], nil
+		}
+		return all, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	for _, test := range []struct {
+		maxRepos      int
+		wantRepos     int
+		wantOverLimit bool
+	}{
+		{maxRepos: 3, wantRepos: 3, wantOverLimit: true},
+		{maxRepos: 5, wantRepos: 5, wantOverLimit: false},
+	} {
+		repoRevs, _, overLimit, err := resolveRepositories(context.Background(), resolveRepoOp{maxRepos: test.maxRepos})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repoRevs) != test.wantRepos {
+			t.Errorf("maxRepos:%d: got %d repos, want %d", test.maxRepos, len(repoRevs), test.wantRepos)
+		}
+		if overLimit != test.wantOverLimit {
+			t.Errorf("maxRepos:%d: got overLimit %v, want %v", test.maxRepos, overLimit, test.wantOverLimit)
+		}
+	}
+}
+
+func TestResolveRepositories_pinnedCommit(t *testing.T) {
+	const commit = "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"
+	db.Mocks.Repos.List = func(_ context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		return []*types.Repo{{ID: 1, Name: "r1"}, {ID: 2, Name: "r2"}}, nil
+	}
+	git.Mocks.ResolveRevision = func(spec string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		if strings.HasPrefix(commit, spec) {
+			return commit, nil
+		}
+		return "", &gitserver.RevisionNotFoundError{Spec: spec}
+	}
+	defer func() {
+		db.Mocks = db.MockStores{}
+		git.ResetMocks()
+	}()
+
+	// An abbreviated commit ID is resolved to the full commit ID.
+	repoRevs, _, _, err := resolveRepositories(context.Background(), resolveRepoOp{repoFilters: []string{"r@a1b2c3d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, repoRev := range repoRevs {
+		if got := repoRev.RevSpecs(); !reflect.DeepEqual(got, []string{commit}) {
+			t.Errorf("%s: got revs %q, want %q", repoRev.Repo.Name, got, commit)
+		}
+	}
+
+	// A commit that doesn't exist in any repository is an error.
+	_, _, _, err = resolveRepositories(context.Background(), resolveRepoOp{repoFilters: []string{"r@0000000"}})
+	if e, ok := err.(*missingCommitError); !ok || e.commit != "0000000" {
+		t.Errorf("got error %v, want missing commit error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package graphqlbackend
+
+import (
+	"time"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// The search backends that can be reported in a repositorySearchTimingResolver.
+const (
+	searchBackendZoekt    = "zoekt"
+	searchBackendSearcher = "searcher"
+)
+
+// repositorySearchTimingResolver is a resolver for the GraphQL type
+// `RepositorySearchTiming`. It is only collected for searches run with debug
+// enabled.
+type repositorySearchTimingResolver struct {
+	repo        *types.Repo
+	backend     string
+	duration    time.Duration
+	resultCount int32
+}
+
+func (r *repositorySearchTimingResolver) Repository() *RepositoryResolver {
+	return NewRepositoryResolver(r.repo)
+}
+
+func (r *repositorySearchTimingResolver) Backend() string { return r.backend }
+
+func (r *repositorySearchTimingResolver) DurationMilliseconds() int32 {
+	return int32(r.duration / time.Millisecond)
+}
+
+func (r *repositorySearchTimingResolver) ResultCount() int32 { return r.resultCount }
+
+// zoektRepoSearchTimings returns the timings of the given repos searched with
+// zoekt. Zoekt searches all indexed repos in a single request, so every
+// timing reports the duration of the whole request, but the result count of
+// its repository only.
+func zoektRepoSearchTimings(repos []*types.Repo, matches []*fileMatchResolver, duration time.Duration) []*repositorySearchTimingResolver {
+	counts := make(map[*types.Repo]int32, len(repos))
+	for _, m := range matches {
+		counts[m.repo] += m.resultCount()
+	}
+
+	timings := make([]*repositorySearchTimingResolver, 0, len(repos))
+	for _, repo := range repos {
+		timings = append(timings, &repositorySearchTimingResolver{
+			repo:        repo,
+			backend:     searchBackendZoekt,
+			duration:    duration,
+			resultCount: counts[repo],
+		})
+	}
+	return timings
+}
+
+// logRepoSearchTimings records the given timings in the trace.
+func logRepoSearchTimings(tr *trace.Trace, timings []*repositorySearchTimingResolver) {
+	for _, t := range timings {
+		tr.LogFields(
+			otlog.String("repo", string(t.repo.Name)),
+			otlog.String("backend", t.backend),
+			otlog.Int64("durationMs", int64(t.duration/time.Millisecond)),
+			otlog.Int32("resultCount", t.resultCount),
+		)
+	}
+}
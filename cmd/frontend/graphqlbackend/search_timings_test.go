@@ -0,0 +1,45 @@
+package graphqlbackend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestZoektRepoSearchTimings(t *testing.T) {
+	foo := &types.Repo{ID: 1, Name: "foo"}
+	bar := &types.Repo{ID: 2, Name: "bar"}
+
+	matches := []*fileMatchResolver{
+		{repo: foo, JLineMatches: []*lineMatch{{}, {}}},
+		{repo: foo, JLineMatches: []*lineMatch{{}}},
+	}
+
+	timings := zoektRepoSearchTimings([]*types.Repo{foo, bar}, matches, 1500*time.Millisecond)
+	if len(timings) != 2 {
+		t.Fatalf("got %d timings, want 2", len(timings))
+	}
+
+	for i, want := range []struct {
+		repo        *types.Repo
+		resultCount int32
+	}{
+		{foo, 3},
+		{bar, 0},
+	} {
+		have := timings[i]
+		if have.repo != want.repo {
+			t.Errorf("timing %d: got repo %q, want %q", i, have.repo.Name, want.repo.Name)
+		}
+		if have.ResultCount() != want.resultCount {
+			t.Errorf("timing %d: got result count %d, want %d", i, have.ResultCount(), want.resultCount)
+		}
+		if have.Backend() != searchBackendZoekt {
+			t.Errorf("timing %d: got backend %q, want %q", i, have.Backend(), searchBackendZoekt)
+		}
+		if have.DurationMilliseconds() != 1500 {
+			t.Errorf("timing %d: got duration %dms, want 1500ms", i, have.DurationMilliseconds())
+		}
+	}
+}
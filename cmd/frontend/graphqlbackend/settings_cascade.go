@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // settingsCascade implements the GraphQL type SettingsCascade (and the deprecated type ConfigurationCascade).
@@ -76,6 +77,19 @@ func viewerFinalSettings(ctx context.Context) (*configurationResolver, error) {
 	return cascade.Merged(ctx)
 }
 
+// ViewerFinalSettings returns the decoded final (merged) settings for the viewer.
+func ViewerFinalSettings(ctx context.Context) (*schema.Settings, error) {
+	merged, err := viewerFinalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var settings schema.Settings
+	if err := json.Unmarshal([]byte(merged.Contents()), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
 func (r *settingsCascade) Final(ctx context.Context) (string, error) {
 	var allSettings []string
 	subjects, err := r.Subjects(ctx)
@@ -0,0 +1,66 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+func (r *siteResolver) GitserverShards(ctx context.Context) ([]*gitserverShardResolver, error) {
+	// 🚨 SECURITY: Only site admins may view this information.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	infos, err := gitserver.DefaultClient.DiskInfo(ctx)
+	if err != nil {
+		// Still report the shards that could be reached.
+		log15.Warn("error fetching gitserver disk usage", "err", err)
+	}
+
+	shards := make([]*gitserverShardResolver, 0, len(infos))
+	for addr, info := range infos {
+		shards = append(shards, &gitserverShardResolver{addr: addr, info: info})
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].addr < shards[j].addr })
+	return shards, nil
+}
+
+type gitserverShardResolver struct {
+	addr string
+	info *protocol.DiskInfo
+}
+
+func (r *gitserverShardResolver) Address() string { return r.addr }
+
+func (r *gitserverShardResolver) FreeBytes() float64 { return float64(r.info.FreeBytes) }
+
+func (r *gitserverShardResolver) TotalBytes() float64 { return float64(r.info.TotalBytes) }
+
+func (r *gitserverShardResolver) DesiredPercentFree() int32 { return int32(r.info.DesiredPercentFree) }
+
+func (r *gitserverShardResolver) NearlyFull() bool { return r.info.NearlyFull() }
+
+func (r *gitserverShardResolver) RepositoryCount() int32 { return int32(r.info.RepoCount) }
+
+func (r *gitserverShardResolver) LargestRepositories() []*gitserverRepositorySizeResolver {
+	sizes := make([]*gitserverRepositorySizeResolver, 0, len(r.info.LargestRepos))
+	for _, s := range r.info.LargestRepos {
+		sizes = append(sizes, &gitserverRepositorySizeResolver{size: s})
+	}
+	return sizes
+}
+
+func (r *gitserverShardResolver) ComputedAt() DateTime { return DateTime{Time: r.info.ComputedAt} }
+
+type gitserverRepositorySizeResolver struct {
+	size protocol.RepoSize
+}
+
+func (r *gitserverRepositorySizeResolver) Name() string { return string(r.size.Repo) }
+
+func (r *gitserverRepositorySizeResolver) Bytes() float64 { return float64(r.size.Bytes) }
@@ -0,0 +1,109 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	prometheusapi "github.com/prometheus/client_golang/api"
+	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var prometheusURL = env.Get("PROMETHEUS_URL", "", "URL at which Prometheus can be reached")
+
+// monitoringServices maps the Prometheus job of an alert to the
+// MonitoringService it is reported as. Alerts of other jobs are not
+// reported.
+var monitoringServices = map[string]string{
+	"searcher":          "SEARCH",
+	"indexed-search":    "SEARCH",
+	"zoekt-indexserver": "SEARCH",
+	"zoekt-webserver":   "SEARCH",
+	"repo-updater":      "REPOSITORY_SYNCING",
+	"gitserver":         "GITSERVER",
+}
+
+func (r *siteResolver) Monitoring(ctx context.Context) (*siteMonitoringResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the monitoring information.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if prometheusURL == "" {
+		return nil, nil
+	}
+	client, err := prometheusapi.NewClient(prometheusapi.Config{Address: prometheusURL})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid PROMETHEUS_URL")
+	}
+	return &siteMonitoringResolver{prometheus: prometheus.NewAPI(client)}, nil
+}
+
+type siteMonitoringResolver struct {
+	prometheus prometheus.API
+}
+
+func (r *siteMonitoringResolver) Alerts(ctx context.Context) ([]*monitoringAlertResolver, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := r.prometheus.Alerts(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching alerts from Prometheus")
+	}
+
+	alerts := make([]*monitoringAlertResolver, 0, len(res.Alerts))
+	for _, a := range res.Alerts {
+		if a.State != prometheus.AlertStateFiring {
+			continue
+		}
+		service, ok := monitoringServices[string(a.Labels["job"])]
+		if !ok {
+			continue
+		}
+		alerts = append(alerts, &monitoringAlertResolver{alert: a, service: service})
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].service != alerts[j].service {
+			return alerts[i].service < alerts[j].service
+		}
+		if alerts[i].Name() != alerts[j].Name() {
+			return alerts[i].Name() < alerts[j].Name()
+		}
+		return alerts[i].alert.ActiveAt.Before(alerts[j].alert.ActiveAt)
+	})
+	return alerts, nil
+}
+
+type monitoringAlertResolver struct {
+	alert   prometheus.Alert
+	service string
+}
+
+func (r *monitoringAlertResolver) Name() string { return string(r.alert.Labels["alertname"]) }
+
+func (r *monitoringAlertResolver) Service() string { return r.service }
+
+func (r *monitoringAlertResolver) Severity() *string {
+	if s, ok := r.alert.Labels["severity"]; ok && s != "" {
+		severity := string(s)
+		return &severity
+	}
+	return nil
+}
+
+func (r *monitoringAlertResolver) Summary() *string {
+	for _, name := range []model.LabelName{"summary", "description"} {
+		if s, ok := r.alert.Annotations[name]; ok && s != "" {
+			summary := string(s)
+			return &summary
+		}
+	}
+	return nil
+}
+
+func (r *monitoringAlertResolver) FiringSince() DateTime { return DateTime{Time: r.alert.ActiveAt} }
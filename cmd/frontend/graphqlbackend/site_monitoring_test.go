@@ -0,0 +1,62 @@
+package graphqlbackend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	prometheusapi "github.com/prometheus/client_golang/api"
+	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+func TestSiteMonitoringAlerts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/alerts" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "data": {"alerts": [
+			{"labels": {"alertname": "DiskFull", "job": "gitserver", "severity": "critical"}, "annotations": {"summary": "gitserver disk is full"}, "state": "firing", "activeAt": "2019-10-01T10:00:00Z", "value": "1"},
+			{"labels": {"alertname": "SlowSearch", "job": "searcher"}, "annotations": {"description": "searches are slow"}, "state": "firing", "activeAt": "2019-10-01T09:00:00Z", "value": "1"},
+			{"labels": {"alertname": "SyncFailing", "job": "repo-updater"}, "annotations": {}, "state": "pending", "activeAt": "2019-10-01T09:00:00Z", "value": "1"},
+			{"labels": {"alertname": "HighLatency", "job": "frontend"}, "annotations": {}, "state": "firing", "activeAt": "2019-10-01T09:00:00Z", "value": "1"}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := prometheusapi.NewClient(prometheusapi.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &siteMonitoringResolver{prometheus: prometheus.NewAPI(client)}
+
+	alerts, err := r.Alerts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type alert struct {
+		Name, Service, Severity, Summary string
+	}
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	var got []alert
+	for _, a := range alerts {
+		got = append(got, alert{a.Name(), a.Service(), deref(a.Severity()), deref(a.Summary())})
+	}
+	// Pending alerts and alerts of unreported services are omitted.
+	want := []alert{
+		{"DiskFull", "GITSERVER", "critical", "gitserver disk is full"},
+		{"SlowSearch", "SEARCH", "", "searches are slow"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got alerts %+v, want %+v", got, want)
+	}
+}
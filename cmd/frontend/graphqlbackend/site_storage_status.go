@@ -0,0 +1,92 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/storagequota"
+)
+
+func (r *siteResolver) StorageStatus(ctx context.Context) (*storageStatusResolver, error) {
+	// 🚨 SECURITY: Only site admins may view this information.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return &storageStatusResolver{status: storagequota.Last()}, nil
+}
+
+type storageStatusResolver struct {
+	status *storagequota.Status
+}
+
+func (r *storageStatusResolver) CheckedAt() *DateTime {
+	if r.status == nil {
+		return nil
+	}
+	return &DateTime{Time: r.status.CheckedAt}
+}
+
+func (r *storageStatusResolver) Tables() []*storageTableStatusResolver {
+	if r.status == nil {
+		return []*storageTableStatusResolver{}
+	}
+	tables := make([]*storageTableStatusResolver, 0, len(r.status.Tables))
+	for _, t := range r.status.Tables {
+		tables = append(tables, &storageTableStatusResolver{status: t})
+	}
+	return tables
+}
+
+type storageTableStatusResolver struct {
+	status storagequota.TableStatus
+}
+
+func (r *storageTableStatusResolver) Name() string { return r.status.Table }
+
+func (r *storageTableStatusResolver) RowCount() float64 { return float64(r.status.RowCount) }
+
+func (r *storageTableStatusResolver) SoftQuota() *float64 {
+	if r.status.Quota == 0 {
+		return nil
+	}
+	q := float64(r.status.Quota)
+	return &q
+}
+
+func (r *storageTableStatusResolver) Nearing() bool { return r.status.Nearing() }
+
+func (r *storageTableStatusResolver) Exceeded() bool { return r.status.Exceeded() }
+
+func init() {
+	// Warn site admins about database tables that near or exceed their soft
+	// quota, before a large sync exhausts the database disk.
+	AlertFuncs = append(AlertFuncs, func(args AlertFuncArgs) []*Alert {
+		// Only site admins can act on this alert, so only show it to site admins.
+		if !args.IsSiteAdmin {
+			return nil
+		}
+
+		status := storagequota.Last()
+		if status == nil {
+			return nil
+		}
+
+		var alerts []*Alert
+		for _, t := range status.Tables {
+			switch {
+			case t.Exceeded():
+				alerts = append(alerts, &Alert{
+					TypeValue:    AlertTypeError,
+					MessageValue: fmt.Sprintf("The database table `%s` has %d rows, more than its soft quota of %d. Make sure the database disk has enough space, then raise the quota in [**site configuration**](/site-admin/configuration) (`storage.softQuotas`).", t.Table, t.RowCount, t.Quota),
+				})
+			case t.Nearing():
+				alerts = append(alerts, &Alert{
+					TypeValue:    AlertTypeWarning,
+					MessageValue: fmt.Sprintf("The database table `%s` has %d rows, nearing its soft quota of %d. Make sure the database disk has enough space for more repositories to be synced.", t.Table, t.RowCount, t.Quota),
+				})
+			}
+		}
+		return alerts
+	})
+}
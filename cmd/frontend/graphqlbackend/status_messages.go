@@ -46,6 +46,10 @@ func (r *statusMessageResolver) ToSyncError() (*statusMessageResolver, bool) {
 	return r, r.message.SyncError != nil
 }
 
+func (r *statusMessageResolver) ToUpdateQueueBacklog() (*statusMessageResolver, bool) {
+	return r, r.message.UpdateQueueBacklog != nil
+}
+
 func (r *statusMessageResolver) Message() (string, error) {
 	if r.message.Cloning != nil {
 		return r.message.Cloning.Message, nil
@@ -56,6 +60,9 @@ func (r *statusMessageResolver) Message() (string, error) {
 	if r.message.SyncError != nil {
 		return r.message.SyncError.Message, nil
 	}
+	if r.message.UpdateQueueBacklog != nil {
+		return r.message.UpdateQueueBacklog.Message, nil
+	}
 	return "", errors.New("status message is of unknown type")
 }
 
@@ -68,3 +75,7 @@ func (r *statusMessageResolver) ExternalService(ctx context.Context) (*externalS
 
 	return &externalServiceResolver{externalService: externalService}, nil
 }
+
+func (r *statusMessageResolver) Count() int32 {
+	return int32(r.message.UpdateQueueBacklog.Count)
+}
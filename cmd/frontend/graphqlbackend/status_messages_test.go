@@ -26,6 +26,11 @@ func TestStatusMessages(t *testing.T) {
 					message
 				}
 
+				... on UpdateQueueBacklog {
+					message
+					count
+				}
+
 				... on ExternalServiceSyncError {
 					message
 					externalService {
@@ -117,6 +122,12 @@ func TestStatusMessages(t *testing.T) {
 						Message: "Could not save to database",
 					},
 				},
+				{
+					UpdateQueueBacklog: &protocol.UpdateQueueBacklog{
+						Message: "1234 repositories waiting to be updated...",
+						Count:   1234,
+					},
+				},
 			}}
 			return res, nil
 		}
@@ -144,6 +155,11 @@ func TestStatusMessages(t *testing.T) {
 							{
 								"__typename": "SyncError",
 								"message": "Could not save to database"
+							},
+							{
+								"__typename": "UpdateQueueBacklog",
+								"message": "1234 repositories waiting to be updated...",
+								"count": 1234
 							}
 						]
 					}
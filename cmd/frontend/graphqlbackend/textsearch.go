@@ -19,6 +19,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	otlog "github.com/opentracing/opentracing-go/log"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -63,6 +64,27 @@ type fileMatchResolver struct {
 	// preserve the original revision specifier from the user instead of navigating them to the
 	// absolute commit ID when they select a result.
 	inputRev *string
+
+	// zoektLang is the language of the file as reported by zoekt (as detected
+	// by ctags during indexing), if any.
+	zoektLang string
+
+	// forkDuplicates are the identical file matches in forks that were
+	// collapsed into this one (see dedupForkFileMatches).
+	forkDuplicates []*fileMatchResolver
+
+	// foundInRevs are the searched revisions in which the file has the same
+	// contents as in this file match, whose identical file matches were
+	// collapsed into this one (see dedupRevFileMatches).
+	foundInRevs []string
+
+	// rangeChange is the commit that added or removed the matches, for
+	// matches of a revision range search (see searchFilesInRevRange).
+	rangeChange *fileMatchRangeChange
+
+	// lastCommit is the last commit that modified the file, for matches of
+	// searches with includeCommitInfo (see addFileMatchLastCommits).
+	lastCommit *GitCommitResolver
 }
 
 func (fm *fileMatchResolver) Key() string {
@@ -103,10 +125,26 @@ func (fm *fileMatchResolver) LineMatches() []*lineMatch {
 	return fm.JLineMatches
 }
 
+func (fm *fileMatchResolver) ForkDuplicates() []*fileMatchResolver {
+	return fm.forkDuplicates
+}
+
+func (fm *fileMatchResolver) FoundInRevs() []string {
+	return fm.foundInRevs
+}
+
 func (fm *fileMatchResolver) LimitHit() bool {
 	return fm.JLimitHit
 }
 
+func (fm *fileMatchResolver) RangeChange() *fileMatchRangeChange {
+	return fm.rangeChange
+}
+
+func (fm *fileMatchResolver) LastCommit() *GitCommitResolver {
+	return fm.lastCommit
+}
+
 func (fm *fileMatchResolver) ToRepository() (*RepositoryResolver, bool) { return nil, false }
 func (fm *fileMatchResolver) ToFileMatch() (*fileMatchResolver, bool)   { return fm, true }
 func (fm *fileMatchResolver) ToCommitSearchResult() (*commitSearchResultResolver, bool) {
@@ -129,6 +167,23 @@ func (fm *fileMatchResolver) resultCount() int32 {
 	return 1 // 1 to count "empty" results like type:path results
 }
 
+// language returns the language of the file (and safe == true if it was
+// detected conclusively). The contents of the matched lines are used to
+// disambiguate extensions shared by several languages, e.g. .h for C, C++
+// and Objective-C.
+func (fm *fileMatchResolver) language() (language string, safe bool) {
+	var content strings.Builder
+	for _, lm := range fm.JLineMatches {
+		content.WriteString(lm.JPreview)
+		content.WriteByte('\n')
+	}
+	language, safe = inventory.GetLanguage(fm.JPath, []byte(content.String()))
+	if language == "" && fm.zoektLang != "" {
+		language = inventory.GetLanguageByZoektLanguage(fm.zoektLang)
+	}
+	return language, safe
+}
+
 // LineMatch is the struct used by vscode to receive search results for a line
 type lineMatch struct {
 	JPreview          string     `json:"Preview"`
@@ -157,13 +212,25 @@ func (lm *lineMatch) LimitHit() bool {
 	return lm.JLimitHit
 }
 
-var mockTextSearch func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error)
+// textSearchHints are hints to searcher about how the archive of a repository
+// at a commit will be used, so that it can decide how long to keep the
+// archive cached. See the fields of the same name in protocol.Request.
+type textSearchHints struct {
+	// Indexed is true if the repository is indexed by zoekt.
+	Indexed bool
+
+	// ExpectedReuse is true if the archive will be searched again as part of
+	// the same query.
+	ExpectedReuse bool
+}
+
+var mockTextSearch func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error)
 
 // textSearch searches repo@commit with p.
 // Note: the returned matches do not set fileMatch.uri
-func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error) {
+func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error) {
 	if mockTextSearch != nil {
-		return mockTextSearch(ctx, repo, commit, p, fetchTimeout)
+		return mockTextSearch(ctx, repo, commit, p, fetchTimeout, hints)
 	}
 
 	tr, ctx := trace.New(ctx, "searcher.client", fmt.Sprintf("%s@%s", repo.Name, commit))
@@ -172,22 +239,24 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 		tr.Finish()
 	}()
 
+	pattern := p.Pattern
+	if len(p.AndPatterns) > 0 {
+		// Searcher only returns files that match all of p.AndPatterns, so we
+		// ask it for the matches of each one.
+		pattern = unionRegExps(p.AndPatterns)
+	}
+
 	q := url.Values{
 		"Repo":            []string{string(repo.Name)},
 		"URL":             []string{repo.URL},
 		"Commit":          []string{string(commit)},
-		"Pattern":         []string{p.Pattern},
+		"Pattern":         []string{pattern},
+		"AndPatterns":     p.AndPatterns,
+		"NegatedPatterns": p.NegatedPatterns,
 		"ExcludePattern":  []string{p.ExcludePattern},
 		"IncludePatterns": p.IncludePatterns,
 		"FetchTimeout":    []string{fetchTimeout.String()},
 	}
-	if deadline, ok := ctx.Deadline(); ok {
-		t, err := deadline.MarshalText()
-		if err != nil {
-			return nil, false, err
-		}
-		q.Set("Deadline", string(t))
-	}
 	q.Set("FileMatchLimit", strconv.FormatInt(int64(p.FileMatchLimit), 10))
 	if p.IsRegExp {
 		q.Set("IsRegExp", "true")
@@ -198,6 +267,10 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	if p.IsCaseSensitive {
 		q.Set("IsCaseSensitive", "true")
 	}
+	if p.IsUnicode {
+		q.Set("IsUnicode", "true")
+		q.Set("Normalization", p.Normalization)
+	}
 	if p.PathPatternsAreRegExps {
 		q.Set("PathPatternsAreRegExps", "true")
 	}
@@ -208,7 +281,12 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	// these fields from old frontends that do not (and provide a default in the latter case).
 	q.Set("PatternMatchesContent", strconv.FormatBool(p.PatternMatchesContent))
 	q.Set("PatternMatchesPath", strconv.FormatBool(p.PatternMatchesPath))
-	rawQuery := q.Encode()
+	if hints.Indexed {
+		q.Set("Indexed", "true")
+	}
+	if hints.ExpectedReuse {
+		q.Set("ExpectedReuse", "true")
+	}
 
 	// Searcher caches the file contents for repo@commit since it is
 	// relatively expensive to fetch from gitserver. So we use consistent
@@ -222,6 +300,12 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 		attempt            = 0
 		maxAttempts        = 2
 	)
+	// Retrying a timed out search on the same searcher is pointless.
+	eps, err := searcherURLs.Endpoints()
+	if err != nil {
+		return nil, false, err
+	}
+	retryTimeouts := len(eps) > 1
 	for {
 		attempt++
 
@@ -239,15 +323,36 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 			}
 		}
 
-		url := searcherURL + "?" + rawQuery
+		attemptCtx, cancel := textSearchAttemptContext(ctx, retryTimeouts && attempt < maxAttempts)
+		if deadline, ok := attemptCtx.Deadline(); ok {
+			t, err := deadline.MarshalText()
+			if err != nil {
+				cancel()
+				return nil, false, err
+			}
+			q.Set("Deadline", string(t))
+		}
+
+		url := searcherURL + "?" + q.Encode()
 		tr.LazyPrintf("attempt %d: %s", attempt, url)
-		matches, limitHit, err = textSearchURL(ctx, url)
+		matches, limitHit, err = textSearchURL(attemptCtx, url)
+		cancel()
 		// Useful trace for debugging:
 		//
 		// tr.LazyPrintf("%d matches, limitHit=%v, err=%v, ctx.Err()=%v", len(matches), limitHit, err, ctx.Err())
-		if err == nil || errcode.IsTimeout(err) {
+		if err == nil {
 			return matches, limitHit, err
 		}
+		if errcode.IsTimeout(err) {
+			// If only this attempt timed out, retry the search on another
+			// searcher instance, which may be less busy.
+			if !retryTimeouts || ctx.Err() != nil || attempt == maxAttempts {
+				return matches, limitHit, err
+			}
+			tr.LazyPrintf("attempt timed out")
+			excludedSearchURLs[searcherURL] = true
+			continue
+		}
 
 		// If we are canceled, return that error.
 		if err := ctx.Err(); err != nil {
@@ -265,6 +370,23 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	}
 }
 
+// textSearchRetryShare is the share (1/textSearchRetryShare) of the time left
+// to search a repo that is set aside to retry the search on another searcher
+// instance if the first attempt times out.
+const textSearchRetryShare = 4
+
+// textSearchAttemptContext returns the context for an attempt to search a
+// repo. If canRetry is true, it times out early enough to leave time for a
+// retry.
+func textSearchAttemptContext(ctx context.Context, canRetry bool) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !canRetry || !ok {
+		return context.WithCancel(ctx)
+	}
+	remaining := time.Until(deadline)
+	return context.WithTimeout(ctx, remaining-remaining/textSearchRetryShare)
+}
+
 func textSearchURL(ctx context.Context, url string) ([]*fileMatchResolver, bool, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -330,57 +452,88 @@ func (e *searcherError) Error() string {
 	return e.Message
 }
 
-var mockSearchFilesInRepo func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error)
+var mockSearchFilesInRepo func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, revs []string, info *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error)
 
-func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error) {
+// searchFilesInRepo searches the given revisions of a repository, one after
+// another. Revisions that resolve to the same commit are only searched once
+// (and their matches are reported for the first of them), so that searcher
+// doesn't fetch the same archive from gitserver more than once.
+func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo *types.Repo, gitserverRepo gitserver.Repo, revs []string, info *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error) {
 	if mockSearchFilesInRepo != nil {
-		return mockSearchFilesInRepo(ctx, repo, gitserverRepo, rev, info, fetchTimeout)
+		return mockSearchFilesInRepo(ctx, repo, gitserverRepo, revs, info, fetchTimeout, hints)
 	}
 
-	// Do not trigger a repo-updater lookup (e.g.,
-	// backend.{GitRepo,Repos.ResolveRev}) because that would slow this operation
-	// down by a lot (if we're looping over many repos). This means that it'll fail if a
-	// repo is not on gitserver.
-	commit, err := git.ResolveRevision(ctx, gitserverRepo, nil, rev, &git.ResolveRevisionOptions{NoEnsureRevision: true})
-	if err != nil {
-		return nil, false, err
-	}
+	searched := make(map[api.CommitID]bool, len(revs))
+	for _, rev := range revs {
+		if _, _, ok := (search.RevisionSpecifier{RevSpec: rev}).Range(); ok {
+			rangeMatches, rangeLimitHit, err := searchFilesInRevRange(ctx, repo, gitserverRepo, rev, info)
+			if err != nil {
+				return matches, limitHit, err
+			}
+			matches = append(matches, rangeMatches...)
+			limitHit = limitHit || rangeLimitHit
+			continue
+		}
 
-	shouldBeSearched, err := repoShouldBeSearched(ctx, searcherURLs, info, gitserverRepo, commit, fetchTimeout)
-	if err != nil {
-		return nil, false, err
-	}
-	if !shouldBeSearched {
-		return matches, false, err
-	}
+		// Do not trigger a repo-updater lookup (e.g.,
+		// backend.{GitRepo,Repos.ResolveRev}) because that would slow this operation
+		// down by a lot (if we're looping over many repos). This means that it'll fail if a
+		// repo is not on gitserver.
+		commit, err := git.ResolveRevision(ctx, gitserverRepo, nil, rev, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+		if err != nil {
+			return matches, limitHit, err
+		}
+		if searched[commit] {
+			continue
+		}
+		searched[commit] = true
 
-	matches, limitHit, err = textSearch(ctx, searcherURLs, gitserverRepo, commit, info, fetchTimeout)
+		shouldBeSearched, err := repoShouldBeSearched(ctx, searcherURLs, info, gitserverRepo, commit, fetchTimeout, hints)
+		if err != nil {
+			return matches, limitHit, err
+		}
+		if !shouldBeSearched {
+			continue
+		}
 
-	workspace := fileMatchURI(repo.Name, rev, "")
-	for _, fm := range matches {
-		fm.uri = workspace + fm.JPath
-		fm.repo = repo
-		fm.commitID = commit
-		fm.inputRev = &rev
+		revMatches, revLimitHit, err := textSearch(ctx, searcherURLs, gitserverRepo, commit, info, fetchTimeout, hints)
+
+		rev := rev
+		workspace := fileMatchURI(repo.Name, rev, "")
+		for _, fm := range revMatches {
+			fm.uri = workspace + fm.JPath
+			fm.repo = repo
+			fm.commitID = commit
+			fm.inputRev = &rev
+		}
+		matches = append(matches, revMatches...)
+		limitHit = limitHit || revLimitHit
+
+		if err != nil {
+			return matches, limitHit, err
+		}
 	}
 
-	return matches, limitHit, err
+	return matches, limitHit, nil
 }
 
 // repoShouldBeSearched determines whether a repository should be searched in, based on whether the repository
 // fits in the subset of repositories specified in the query's `repohasfile` and `-repohasfile` flags if they exist.
-func repoShouldBeSearched(ctx context.Context, searcherURLs *endpoint.Map, searchPattern *search.PatternInfo, gitserverRepo gitserver.Repo, commit api.CommitID, fetchTimeout time.Duration) (shouldBeSearched bool, err error) {
+func repoShouldBeSearched(ctx context.Context, searcherURLs *endpoint.Map, searchPattern *search.PatternInfo, gitserverRepo gitserver.Repo, commit api.CommitID, fetchTimeout time.Duration, hints textSearchHints) (shouldBeSearched bool, err error) {
+	// The archive is searched again if the repository should be searched.
+	hints.ExpectedReuse = true
+
 	shouldBeSearched = true
 	flagInQuery := len(searchPattern.FilePatternsReposMustInclude) > 0
 	if flagInQuery {
-		shouldBeSearched, err = repoHasFilesWithNamesMatching(ctx, searcherURLs, true, searchPattern.FilePatternsReposMustInclude, gitserverRepo, commit, fetchTimeout)
+		shouldBeSearched, err = repoHasFilesWithNamesMatching(ctx, searcherURLs, true, searchPattern.FilePatternsReposMustInclude, gitserverRepo, commit, fetchTimeout, hints)
 		if err != nil {
 			return shouldBeSearched, err
 		}
 	}
 	negFlagInQuery := len(searchPattern.FilePatternsReposMustExclude) > 0
 	if negFlagInQuery {
-		shouldBeSearched, err = repoHasFilesWithNamesMatching(ctx, searcherURLs, false, searchPattern.FilePatternsReposMustExclude, gitserverRepo, commit, fetchTimeout)
+		shouldBeSearched, err = repoHasFilesWithNamesMatching(ctx, searcherURLs, false, searchPattern.FilePatternsReposMustExclude, gitserverRepo, commit, fetchTimeout, hints)
 		if err != nil {
 			return shouldBeSearched, err
 		}
@@ -390,10 +543,10 @@ func repoShouldBeSearched(ctx context.Context, searcherURLs *endpoint.Map, searc
 
 // repoHasFilesWithNamesMatching searches in a repository for matches for the patterns in the `repohasfile` or `-repohasfile` flags, and returns
 // whether or not the repoShouldBeSearched in or not, based on whether matches were returned.
-func repoHasFilesWithNamesMatching(ctx context.Context, searcherURLs *endpoint.Map, include bool, repoHasFileFlag []string, gitserverRepo gitserver.Repo, commit api.CommitID, fetchTimeout time.Duration) (bool, error) {
+func repoHasFilesWithNamesMatching(ctx context.Context, searcherURLs *endpoint.Map, include bool, repoHasFileFlag []string, gitserverRepo gitserver.Repo, commit api.CommitID, fetchTimeout time.Duration, hints textSearchHints) (bool, error) {
 	for _, pattern := range repoHasFileFlag {
 		p := search.PatternInfo{IsRegExp: true, FileMatchLimit: 1, IncludePatterns: []string{pattern}, PathPatternsAreRegExps: true, PathPatternsAreCaseSensitive: false, PatternMatchesContent: true, PatternMatchesPath: true}
-		matches, _, err := textSearch(ctx, searcherURLs, gitserverRepo, commit, &p, fetchTimeout)
+		matches, _, err := textSearch(ctx, searcherURLs, gitserverRepo, commit, &p, fetchTimeout, hints)
 		if err != nil {
 			return false, err
 		}
@@ -444,6 +597,10 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 	var (
 		searcherRepos = args.Repos
 		zoektRepos    []*search.RepositoryRevisions
+
+		// indexedSearcherRepos are the repos indexed by zoekt that are
+		// searched by searcher nonetheless.
+		indexedSearcherRepos = map[api.RepoName]bool{}
 	)
 
 	if args.Zoekt.Enabled() {
@@ -454,7 +611,7 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 			if ctx.Err() == nil {
 				log15.Warn("zoektIndexedRepos failed", "error", err)
 			}
-			common.indexUnavailable = true
+			searcherRepos = indexUnavailableFallback(common, args.Repos)
 			err = nil
 		}
 	}
@@ -483,6 +640,12 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 			if !args.Zoekt.Enabled() {
 				return nil, common, fmt.Errorf("invalid index:%q (indexed search is not enabled)", index)
 			}
+			if common.indexUnavailable {
+				tr.LazyPrintf("index:only, but the index is unavailable")
+				common.indexFallback = nil
+				searcherRepos = nil
+				break
+			}
 			common.missing = make([]*types.Repo, len(searcherRepos))
 			for i, r := range searcherRepos {
 				common.missing[i] = r.Repo
@@ -490,7 +653,15 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 			tr.LazyPrintf("index:only, ignoring %d unindexed repos", len(searcherRepos))
 			searcherRepos = nil
 		case No, False:
+			if common.indexUnavailable {
+				// All repos are searched with searcher anyway.
+				common.indexFallback = nil
+				searcherRepos = args.Repos
+			}
 			tr.LazyPrintf("index:no, bypassing zoekt (using searcher) for %d indexed repos", len(zoektRepos))
+			for _, repoRev := range zoektRepos {
+				indexedSearcherRepos[repoRev.Repo.Name] = true
+			}
 			searcherRepos = append(searcherRepos, zoektRepos...)
 			zoektRepos = nil
 		default:
@@ -534,7 +705,9 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 	go func() {
 		// TODO limitHit, handleRepoSearchResult
 		defer wg.Done()
+		start := time.Now()
 		matches, limitHit, reposLimitHit, searchErr := zoektSearchHEAD(ctx, args, zoektRepos, false, time.Since)
+		duration := time.Since(start)
 		mu.Lock()
 		defer mu.Unlock()
 		if ctx.Err() == nil {
@@ -542,6 +715,15 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 				common.searched = append(common.searched, repo.Repo)
 				common.indexed = append(common.indexed, repo.Repo)
 			}
+			if args.Debug && len(zoektRepos) > 0 {
+				repos := make([]*types.Repo, len(zoektRepos))
+				for i, repo := range zoektRepos {
+					repos[i] = repo.Repo
+				}
+				timings := zoektRepoSearchTimings(repos, matches, duration)
+				logRepoSearchTimings(tr, timings)
+				common.timings = append(common.timings, timings...)
+			}
 			for repo := range reposLimitHit {
 				// Repos that aren't included in the result set due to exceeded limits are partially searched
 				// for dynamic filter purposes. Note, reposLimitHit may include repos that did not have any results
@@ -593,7 +775,7 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 		if len(repoRev.Revs) == 0 {
 			continue
 		}
-		if len(repoRev.Revs) >= 2 {
+		if len(repoRev.Revs) >= 2 && hasRefGlobs(repoRev) {
 			return nil, common, errMultipleRevsNotSupported
 		}
 
@@ -609,8 +791,12 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 			defer wg.Done()
 			defer done()
 
-			rev := repoRev.RevSpecs()[0] // TODO(sqs): search multiple revs
-			matches, repoLimitHit, searchErr := searchFilesInRepo(ctx, args.SearcherURLs, repoRev.Repo, repoRev.GitserverRepo(), rev, args.Pattern, fetchTimeout)
+			// Search all revs of the repo in one batch, so that revs which
+			// resolve to the same commit are only searched once.
+			hints := textSearchHints{Indexed: indexedSearcherRepos[repoRev.Repo.Name]}
+			start := time.Now()
+			matches, repoLimitHit, searchErr := searchFilesInRepo(ctx, args.SearcherURLs, repoRev.Repo, repoRev.GitserverRepo(), repoRev.RevSpecs(), args.Pattern, fetchTimeout, hints)
+			duration := time.Since(start)
 			if searchErr != nil {
 				tr.LogFields(otlog.String("repo", string(repoRev.Repo.Name)), otlog.String("searchErr", searchErr.Error()), otlog.Bool("timeout", errcode.IsTimeout(searchErr)), otlog.Bool("temporary", errcode.IsTemporary(searchErr)))
 				log15.Warn("searchFilesInRepo failed", "error", searchErr, "repo", repoRev.Repo.Name)
@@ -620,6 +806,18 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 			if ctx.Err() == nil {
 				common.searched = append(common.searched, repoRev.Repo)
 			}
+			if args.Debug {
+				timing := &repositorySearchTimingResolver{
+					repo:     repoRev.Repo,
+					backend:  searchBackendSearcher,
+					duration: duration,
+				}
+				for _, m := range matches {
+					timing.resultCount += m.resultCount()
+				}
+				logRepoSearchTimings(tr, []*repositorySearchTimingResolver{timing})
+				common.timings = append(common.timings, timing)
+			}
 			if repoLimitHit {
 				// We did not return all results in this repository.
 				common.partial[repoRev.Repo.Name] = struct{}{}
@@ -651,6 +849,17 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 	return flattened, common, nil
 }
 
+// hasRefGlobs reports whether any of the revisions of repoRev is a ref glob,
+// which searcher can't search.
+func hasRefGlobs(repoRev *search.RepositoryRevisions) bool {
+	for _, rev := range repoRev.Revs {
+		if rev.RefGlob != "" || rev.ExcludeRefGlob != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func flattenFileMatches(unflattened [][]*fileMatchResolver, fileMatchLimit int) []*fileMatchResolver {
 	// Return early so we don't have to worry about empty lists in later
 	// calculations.
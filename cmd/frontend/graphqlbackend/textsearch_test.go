@@ -3,9 +3,12 @@ package graphqlbackend
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,8 +23,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 func TestQueryToZoektQuery(t *testing.T) {
@@ -56,6 +62,48 @@ func TestQueryToZoektQuery(t *testing.T) {
 			},
 			Query: "(foo).*?(bar) case:no",
 		},
+		{
+			Name: "and",
+			Pattern: &search.PatternInfo{
+				IsRegExp:                     true,
+				IsCaseSensitive:              false,
+				Pattern:                      "(foo).*?(bar)",
+				AndPatterns:                  []string{"foo", "bar"},
+				IncludePatterns:              nil,
+				ExcludePattern:               "",
+				PathPatternsAreRegExps:       true,
+				PathPatternsAreCaseSensitive: false,
+			},
+			Query: "foo bar case:no",
+		},
+		{
+			Name: "negated",
+			Pattern: &search.PatternInfo{
+				IsRegExp:                     true,
+				IsCaseSensitive:              false,
+				Pattern:                      "foo",
+				NegatedPatterns:              []string{"bar", "b.z"},
+				IncludePatterns:              nil,
+				ExcludePattern:               "",
+				PathPatternsAreRegExps:       true,
+				PathPatternsAreCaseSensitive: false,
+			},
+			Query: "foo -content:bar -content:b.z case:no",
+		},
+		{
+			Name: "onlynegated",
+			Pattern: &search.PatternInfo{
+				IsRegExp:                     true,
+				IsCaseSensitive:              false,
+				Pattern:                      "",
+				NegatedPatterns:              []string{"bar"},
+				IncludePatterns:              nil,
+				ExcludePattern:               "",
+				PathPatternsAreRegExps:       true,
+				PathPatternsAreCaseSensitive: false,
+			},
+			Query: "-content:bar case:no",
+		},
 		{
 			Name: "path",
 			Pattern: &search.PatternInfo{
@@ -224,8 +272,9 @@ func TestQueryToZoektFileOnlyQueries(t *testing.T) {
 }
 
 func TestSearchFilesInRepos(t *testing.T) {
-	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error) {
+	mockSearchFilesInRepo = func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, revs []string, info *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error) {
 		repoName := repo.Name
+		rev := revs[0]
 		switch repoName {
 		case "foo/one":
 			return []*fileMatchResolver{
@@ -257,6 +306,11 @@ func TestSearchFilesInRepos(t *testing.T) {
 	}
 	defer func() { mockSearchFilesInRepo = nil }()
 
+	repoupdater.MockEnqueueRepoUpdate = func(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
+		return &protocol.RepoUpdateResponse{Name: string(repo.Name)}, nil
+	}
+	defer func() { repoupdater.MockEnqueueRepoUpdate = nil }()
+
 	zoekt := &searchbackend.Zoekt{Client: &fakeSearcher{repos: &zoekt.RepoList{}}}
 
 	q, err := query.ParseAndCheck("foo")
@@ -280,11 +334,12 @@ func TestSearchFilesInRepos(t *testing.T) {
 	if len(results) != 2 {
 		t.Errorf("expected two results, got %d", len(results))
 	}
-	if v := toRepoNames(common.cloning); !reflect.DeepEqual(v, []api.RepoName{"foo/cloning"}) {
+	// foo/missing is not cloned yet, so a clone of it is enqueued.
+	sort.Slice(common.cloning, func(i, j int) bool { return common.cloning[i].Name < common.cloning[j].Name }) // to make deterministic
+	if v := toRepoNames(common.cloning); !reflect.DeepEqual(v, []api.RepoName{"foo/cloning", "foo/missing"}) {
 		t.Errorf("unexpected cloning: %v", v)
 	}
-	sort.Slice(common.missing, func(i, j int) bool { return common.missing[i].Name < common.missing[j].Name }) // to make deterministic
-	if v := toRepoNames(common.missing); !reflect.DeepEqual(v, []api.RepoName{"foo/missing", "foo/missing-db"}) {
+	if v := toRepoNames(common.missing); !reflect.DeepEqual(v, []api.RepoName{"foo/missing-db"}) {
 		t.Errorf("unexpected missing: %v", v)
 	}
 	if v := toRepoNames(common.timedout); !reflect.DeepEqual(v, []api.RepoName{"foo/timedout"}) {
@@ -310,7 +365,10 @@ func TestSearchFilesInRepos(t *testing.T) {
 }
 
 func TestRepoShouldBeSearched(t *testing.T) {
-	mockTextSearch = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error) {
+	mockTextSearch = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error) {
+		if !hints.ExpectedReuse {
+			t.Error("expected the archive to be reused after checking repohasfile")
+		}
 		repoName := repo.Name
 		switch repoName {
 		case "foo/one":
@@ -332,7 +390,7 @@ func TestRepoShouldBeSearched(t *testing.T) {
 		FilePatternsReposMustInclude: []string{"main"},
 	}
 
-	shouldBeSearched, err := repoShouldBeSearched(context.Background(), nil, info, gitserver.Repo{Name: "foo/one", URL: "http://example.com/foo/one"}, "1a2b3c", time.Minute)
+	shouldBeSearched, err := repoShouldBeSearched(context.Background(), nil, info, gitserver.Repo{Name: "foo/one", URL: "http://example.com/foo/one"}, "1a2b3c", time.Minute, textSearchHints{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -340,7 +398,7 @@ func TestRepoShouldBeSearched(t *testing.T) {
 		t.Errorf("expected repo to be searched, got shouldn't be searched")
 	}
 
-	shouldBeSearched, err = repoShouldBeSearched(context.Background(), nil, info, gitserver.Repo{Name: "foo/no-filematch", URL: "http://example.com/foo/no-filematch"}, "1a2b3c", time.Minute)
+	shouldBeSearched, err = repoShouldBeSearched(context.Background(), nil, info, gitserver.Repo{Name: "foo/no-filematch", URL: "http://example.com/foo/no-filematch"}, "1a2b3c", time.Minute, textSearchHints{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -349,6 +407,57 @@ func TestRepoShouldBeSearched(t *testing.T) {
 	}
 }
 
+func TestSearchFilesInRepo_revs(t *testing.T) {
+	git.Mocks.ResolveRevision = func(rev string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		switch rev {
+		case "", "master":
+			return "c1", nil
+		case "dev":
+			return "c2", nil
+		default:
+			return "", &gitserver.RevisionNotFoundError{Repo: "foo", Spec: rev}
+		}
+	}
+	defer git.ResetMocks()
+
+	var (
+		searched  []api.CommitID
+		wantHints = textSearchHints{Indexed: true}
+	)
+	mockTextSearch = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration, hints textSearchHints) (matches []*fileMatchResolver, limitHit bool, err error) {
+		if hints != wantHints {
+			t.Errorf("got hints %+v, want %+v", hints, wantHints)
+		}
+		searched = append(searched, commit)
+		return []*fileMatchResolver{{JPath: "main.go"}}, false, nil
+	}
+	defer func() { mockTextSearch = nil }()
+
+	repo := &types.Repo{Name: "foo"}
+	matches, _, err := searchFilesInRepo(context.Background(), nil, repo, gitserver.Repo{Name: "foo"}, []string{"master", "", "dev"}, &search.PatternInfo{Pattern: "foo"}, time.Minute, wantHints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// master and the default branch resolve to the same commit, which is
+	// only searched once.
+	if want := []api.CommitID{"c1", "c2"}; !reflect.DeepEqual(searched, want) {
+		t.Errorf("searched commits %v, want %v", searched, want)
+	}
+	var uris []string
+	for _, fm := range matches {
+		uris = append(uris, fm.uri)
+	}
+	if want := []string{"git://foo?master#main.go", "git://foo?dev#main.go"}; !reflect.DeepEqual(uris, want) {
+		t.Errorf("got match URIs %q, want %q", uris, want)
+	}
+
+	_, _, err = searchFilesInRepo(context.Background(), nil, repo, gitserver.Repo{Name: "foo"}, []string{"master", "missing"}, &search.PatternInfo{Pattern: "foo"}, time.Minute, wantHints)
+	if !gitserver.IsRevisionNotFound(errors.Cause(err)) {
+		t.Errorf("searching a non-existent rev expected to fail with RevisionNotFoundError, got: %v", err)
+	}
+}
+
 func makeRepositoryRevisions(repos ...string) []*search.RepositoryRevisions {
 	r := make([]*search.RepositoryRevisions, len(repos))
 	for i, repospec := range repos {
@@ -653,6 +762,11 @@ func Test_zoektIndexedRepos(t *testing.T) {
 		"foo/unindexed-two",
 	)
 
+	pinned := makeRepositoryRevisions(
+		"foo/indexed-one@deadbeef",
+		"foo/indexed-two@1234567",
+	)
+
 	zoektRepoList := &zoekt.RepoList{
 		Repos: []*zoekt.RepoListEntry{
 			{
@@ -712,6 +826,16 @@ func Test_zoektIndexedRepos(t *testing.T) {
 		repos:     repos[:1],
 		indexed:   makeIndexed(repos[:1]),
 		unindexed: repos[:0],
+	}, {
+		name:      "pinned to indexed commit",
+		repos:     pinned[:1],
+		indexed:   makeIndexed(pinned[:1]),
+		unindexed: pinned[:0],
+	}, {
+		name:      "pinned to other commit",
+		repos:     pinned[1:],
+		indexed:   pinned[:0],
+		unindexed: pinned[1:],
 	}}
 
 	for _, tc := range cases {
@@ -733,6 +857,34 @@ func Test_zoektIndexedRepos(t *testing.T) {
 	}
 }
 
+func Test_indexUnavailableFallback(t *testing.T) {
+	var specs []string
+	for i := 0; i < maxIndexUnavailableFallbackRepos+2; i++ {
+		specs = append(specs, fmt.Sprintf("foo/repo-%d", i))
+	}
+	specs = append(specs, "foo/branch@feature")
+	repos := makeRepositoryRevisions(specs...)
+	for i, r := range repos {
+		r.Repo.ID = api.RepoID(i + 1)
+	}
+
+	common := &searchResultsCommon{}
+	unindexed := indexUnavailableFallback(common, repos)
+
+	// The repos that may be indexed are capped, but the one searched at a
+	// branch is searched without the index anyway.
+	want := append(repos[:maxIndexUnavailableFallbackRepos:maxIndexUnavailableFallbackRepos], repos[len(repos)-1])
+	if !reflect.DeepEqual(unindexed, want) {
+		t.Errorf("unexpected unindexed: %s", cmp.Diff(want, unindexed))
+	}
+	if !common.indexUnavailable {
+		t.Error("indexUnavailable is false")
+	}
+	if got := common.IndexUnavailableFallbackCount(); got != maxIndexUnavailableFallbackRepos {
+		t.Errorf("got fallback count %d, want %d", got, maxIndexUnavailableFallbackRepos)
+	}
+}
+
 func Benchmark_zoektIndexedRepos(b *testing.B) {
 	repoNames := []string{}
 	zoektRepos := []*zoekt.RepoListEntry{}
@@ -762,3 +914,31 @@ func Benchmark_zoektIndexedRepos(b *testing.B) {
 		_, _, _ = zoektIndexedRepos(ctx, z, repos, nil)
 	}
 }
+
+func TestTextSearch_retryTimeout(t *testing.T) {
+	var calls int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// The first searcher doesn't respond in time.
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprint(w, `{"Matches": [{"Path": "main.go"}]}`)
+	})
+	s1, s2 := httptest.NewServer(handler), httptest.NewServer(handler)
+	defer s1.Close()
+	defer s2.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	matches, _, err := textSearch(ctx, endpoint.Static(s1.URL, s2.URL), gitserver.Repo{Name: "foo"}, "deadbeef", &search.PatternInfo{Pattern: "foo"}, time.Second, textSearchHints{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].JPath != "main.go" {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("got %d searcher requests, want 2", calls)
+	}
+}
@@ -128,7 +128,7 @@ func zoektSearchHEAD(ctx context.Context, args *search.Args, repos []*search.Rep
 
 	// If the query has a `repohasfile` or `-repohasfile` flag, we want to construct a new reposet based
 	// on the values passed in to the flag.
-	newRepoSet, err := createNewRepoSetWithRepoHasFileInputs(ctx, args.Pattern, args.Zoekt.Client, *repoSet)
+	newRepoSet, err := createNewRepoSetWithRepoHasFileInputs(ctx, args.Pattern, args.Zoekt, *repoSet)
 	if err != nil {
 		return nil, false, nil, err
 	}
@@ -136,7 +136,7 @@ func zoektSearchHEAD(ctx context.Context, args *search.Args, repos []*search.Rep
 	tr.LazyPrintf("after repohasfile filters: nRepos=%d query=%v", len(newRepoSet.Set), finalQuery)
 
 	t0 := time.Now()
-	resp, err := args.Zoekt.Client.Search(ctx, finalQuery, &searchOpts)
+	resp, err := args.Zoekt.Search(ctx, finalQuery, &searchOpts)
 	if err != nil {
 		return nil, false, nil, err
 	}
@@ -224,6 +224,11 @@ func zoektSearchHEAD(ctx context.Context, args *search.Args, repos []*search.Rep
 								ParentKind: m.SymbolInfo.ParentKind,
 								Path:       file.FileName,
 								Line:       l.LineNumber,
+								Language:   file.Language,
+								// Use the line as the ctags pattern, so that the
+								// symbol range is computed the same way as for
+								// symbols from the symbols service.
+								Pattern: "/^" + string(l.Line) + "$/",
 							},
 							lang:    strings.ToLower(file.Language),
 							baseURI: baseURI,
@@ -244,10 +249,15 @@ func zoektSearchHEAD(ctx context.Context, args *search.Args, repos []*search.Rep
 			JPath:        file.FileName,
 			JLineMatches: lines,
 			JLimitHit:    fileLimitHit,
-			uri:          fileMatchURI(repoRev.Repo.Name, "", file.FileName),
+			uri:          fileMatchURI(repoRev.Repo.Name, inputRev, file.FileName),
 			symbols:      symbols,
 			repo:         repoRev.Repo,
 			commitID:     repoRev.IndexedHEADCommit(),
+			zoektLang:    file.Language,
+		}
+		if inputRev != "" {
+			// The search was pinned to the indexed commit, so link to it.
+			matches[i].inputRev = &inputRev
 		}
 	}
 
@@ -370,11 +380,42 @@ func fileRe(pattern string, queryIsCaseSensitive bool) (zoektquery.Q, error) {
 	return parseRe(pattern, true, queryIsCaseSensitive)
 }
 
+// contentRe is like fileRe, but for patterns that only match file content.
+func contentRe(pattern string, queryIsCaseSensitive bool) (zoektquery.Q, error) {
+	q, err := parseRe(pattern, false, queryIsCaseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	switch q := q.(type) {
+	case *zoektquery.Substring:
+		q.Content = true
+	case *zoektquery.Regexp:
+		q.Content = true
+	}
+	return q, nil
+}
+
 func queryToZoektQuery(query *search.PatternInfo, isSymbol bool) (zoektquery.Q, error) {
 	var and []zoektquery.Q
 
 	var q zoektquery.Q
-	if query.IsRegExp {
+	if query.Pattern == "" && len(query.NegatedPatterns) > 0 && !isSymbol {
+		// Only negated patterns were given, so all files that don't match
+		// them are returned.
+		q = &zoektquery.Const{Value: true}
+	} else if query.IsRegExp && len(query.AndPatterns) > 0 && !isSymbol {
+		// Each pattern is matched independently, so that the matches of
+		// all of them are returned for files that contain all of them.
+		qs := make([]zoektquery.Q, 0, len(query.AndPatterns))
+		for _, p := range query.AndPatterns {
+			pq, err := parseRe(p, false, query.IsCaseSensitive)
+			if err != nil {
+				return nil, err
+			}
+			qs = append(qs, pq)
+		}
+		q = zoektquery.NewAnd(qs...)
+	} else if query.IsRegExp {
 		var err error
 		q, err = parseRe(query.Pattern, false, query.IsCaseSensitive)
 		if err != nil {
@@ -418,6 +459,13 @@ func queryToZoektQuery(query *search.PatternInfo, isSymbol bool) (zoektquery.Q,
 		}
 		and = append(and, &zoektquery.Not{Child: q})
 	}
+	for _, p := range query.NegatedPatterns {
+		q, err := contentRe(p, query.IsCaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, &zoektquery.Not{Child: q})
+	}
 
 	return zoektquery.Simplify(zoektquery.NewAnd(and...)), nil
 }
@@ -443,11 +491,13 @@ func queryToZoektFileOnlyQueries(query *search.PatternInfo, listOfFilePaths []st
 
 // zoektIndexedRepos splits the input repo list into two parts: (1) the
 // repositories `indexed` by Zoekt and (2) the repositories that are
-// `unindexed`.
+// `unindexed`. Repositories whose requested revision is not the commit that
+// Zoekt indexed (e.g. because the search is pinned to another commit, as in
+// "repo:foo@a1b2c3d") are `unindexed`, too.
 func zoektIndexedRepos(ctx context.Context, z *searchbackend.Zoekt, revs []*search.RepositoryRevisions, filter func(*zoekt.Repository) bool) (indexed, unindexed []*search.RepositoryRevisions, err error) {
 	count := 0
 	for _, r := range revs {
-		if len(r.Revs) > 0 && r.Revs[0].RevSpec == "" {
+		if mayBeIndexed(r) {
 			count++
 		}
 	}
@@ -474,15 +524,76 @@ func zoektIndexedRepos(ctx context.Context, z *searchbackend.Zoekt, revs []*sear
 			continue
 		}
 
+		var headCommit api.CommitID
 		for _, branch := range repo.Branches {
 			if branch.Name == "HEAD" {
-				rev.SetIndexedHEADCommit(api.CommitID(branch.Version))
+				headCommit = api.CommitID(branch.Version)
 				break
 			}
 		}
+		if !searchesIndexedCommit(rev.Revs, headCommit) {
+			unindexed = append(unindexed, rev)
+			continue
+		}
 
+		rev.SetIndexedHEADCommit(headCommit)
 		indexed = append(indexed, rev)
 	}
 
 	return indexed, unindexed, nil
 }
+
+// mayBeIndexed reports whether the requested revision of the repository may be
+// searched with Zoekt, depending on the commit that Zoekt indexed.
+func mayBeIndexed(r *search.RepositoryRevisions) bool {
+	return len(r.Revs) > 0 && (r.Revs[0].RevSpec == "" || r.Revs[0].IsCommitID())
+}
+
+// maxIndexUnavailableFallbackRepos is the maximum number of repositories that
+// a search falls back to searching without Zoekt when Zoekt is unavailable.
+// The others are not searched, so that an outage of Zoekt doesn't overload
+// searcher and gitserver with searches of every repository.
+const maxIndexUnavailableFallbackRepos = 50
+
+// indexUnavailableFallback returns the repositories to search without Zoekt
+// when it isn't known which repositories Zoekt indexed because Zoekt is
+// unavailable: the repositories that Zoekt couldn't have searched anyway, and
+// the first maxIndexUnavailableFallbackRepos of the others, which are recorded
+// in common.
+func indexUnavailableFallback(common *searchResultsCommon, revs []*search.RepositoryRevisions) []*search.RepositoryRevisions {
+	common.indexUnavailable = true
+
+	unindexed := make([]*search.RepositoryRevisions, 0, len(revs))
+	fallback := 0
+	for _, r := range revs {
+		if mayBeIndexed(r) {
+			if fallback == maxIndexUnavailableFallbackRepos {
+				continue
+			}
+			fallback++
+			common.indexFallback = append(common.indexFallback, r.Repo)
+		}
+		unindexed = append(unindexed, r)
+	}
+	return unindexed
+}
+
+// searchesIndexedCommit reports whether revs, the revisions requested for a
+// repository, only request the commit of HEAD that Zoekt indexed.
+func searchesIndexedCommit(revs []search.RevisionSpecifier, headCommit api.CommitID) bool {
+	if len(revs) == 0 {
+		return true
+	}
+	if len(revs) != 1 {
+		return false
+	}
+	rev := revs[0]
+	if rev.RefGlob != "" || rev.ExcludeRefGlob != "" {
+		return false
+	}
+	switch rev.RevSpec {
+	case "", "HEAD":
+		return true
+	}
+	return headCommit != "" && rev.RevSpec == string(headCommit)
+}
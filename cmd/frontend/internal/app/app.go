@@ -64,6 +64,7 @@ func NewHandler() http.Handler {
 
 	r.Get(router.GDDORefs).Handler(trace.TraceRoute(errorutil.Handler(serveGDDORefs)))
 	r.Get(router.Editor).Handler(trace.TraceRoute(errorutil.Handler(serveEditor)))
+	r.Get(router.EditorLink).Handler(trace.TraceRoute(errorutil.Handler(serveEditorLink)))
 
 	r.Get(router.DebugHeaders).Handler(trace.TraceRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		r.Header.Del("Cookie")
@@ -12,7 +12,10 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/routevar"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func editorRev(ctx context.Context, repoName api.RepoName, rev string, beExplicit bool) (string, error) {
@@ -184,3 +187,100 @@ func guessRepoNameFromRemoteURL(urlStr string, hostnameToPattern map[string]stri
 		"{path}", strings.TrimPrefix(u.Path, "/"),
 	).Replace(pattern))
 }
+
+// serveEditorLink redirects to a URL that opens the file of the Sourcegraph blob URL given in the
+// "url" query parameter in the viewer's editor. It is the reverse of serveEditor, which opens a
+// file from an editor on Sourcegraph.
+func serveEditorLink(w http.ResponseWriter, r *http.Request) error {
+	blobURL, err := url.Parse(r.URL.Query().Get("url"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Invalid URL: %s", err)
+		return nil
+	}
+
+	settings, err := graphqlbackend.ViewerFinalSettings(r.Context())
+	if err != nil {
+		return err
+	}
+
+	link, err := editorLink(settings, blobURL)
+	if err != nil {
+		// Any error here is a problem with the URL or the user's settings.
+		// We want them to actually read this error message.
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return nil
+	}
+	http.Redirect(w, r, link, http.StatusSeeOther)
+	return nil
+}
+
+var (
+	// blobURLPathRegExp matches the path of a Sourcegraph blob URL, such as
+	// "/github.com/foo/bar@master/-/blob/cmd/main.go".
+	blobURLPathRegExp = regexp.MustCompile(`^/` + routevar.RepoPattern + `(?:@` + routevar.RevPattern + `)?/-/blob/(?P<path>.+)$`)
+
+	// blobURLLineRegExp matches the line and optional column of a Sourcegraph
+	// blob URL fragment, such as "L12:3" or "L12:3-14:1".
+	blobURLLineRegExp = regexp.MustCompile(`^L(\d+)(?::(\d+))?`)
+)
+
+// editorLink returns the URL that opens the file of the given Sourcegraph blob URL in the editor
+// configured in the "openInEditor.scheme" setting. The local path of the file is determined by the
+// first rule in the "openInEditor.pathMappings" setting that matches the repository name.
+//
+// For example, given the blob URL "https://sourcegraph.com/github.com/foo/bar/-/blob/a.go#L3:5"
+// and the path mapping {"repository": "^github\\.com/(.*)$", "localPath": "/home/me/src/$1"},
+// it returns "vscode://file/home/me/src/foo/bar/a.go:3:5".
+func editorLink(settings *schema.Settings, blobURL *url.URL) (string, error) {
+	m := blobURLPathRegExp.FindStringSubmatch(blobURL.Path)
+	if m == nil {
+		return "", fmt.Errorf("URL %q is not a Sourcegraph file URL", blobURL)
+	}
+	repoName, filePath := m[1], m[3]
+
+	// 🚨 SECURITY: The link must not open a file outside of the local clone of
+	// the repository, e.g. for a crafted URL with the path "-/blob/../../etc/passwd".
+	for _, p := range []string{repoName, filePath} {
+		for _, segment := range strings.Split(p, "/") {
+			if segment == ".." {
+				return "", fmt.Errorf("URL %q contains a %q path segment", blobURL, segment)
+			}
+		}
+	}
+
+	var localPath string
+	for _, mapping := range settings.OpenInEditorPathMappings {
+		pattern, err := regexp.Compile(mapping.Repository)
+		if err != nil {
+			return "", fmt.Errorf("invalid repository pattern %q in openInEditor.pathMappings setting: %s", mapping.Repository, err)
+		}
+		if match := pattern.FindStringSubmatchIndex(repoName); match != nil {
+			localPath = string(pattern.ExpandString(nil, mapping.LocalPath, repoName, match))
+			break
+		}
+	}
+	if localPath == "" {
+		return "", fmt.Errorf("no openInEditor.pathMappings setting matches repository %q", repoName)
+	}
+	localPath = path.Join("/", localPath, filePath)
+
+	line, col := 1, 1
+	if m := blobURLLineRegExp.FindStringSubmatch(blobURL.Fragment); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			col, _ = strconv.Atoi(m[2])
+		}
+	}
+
+	switch scheme := settings.OpenInEditorScheme; scheme {
+	case "", "vscode":
+		return (&url.URL{Scheme: "vscode", Host: "file", Path: fmt.Sprintf("%s:%d:%d", localPath, line, col)}).String(), nil
+	case "idea":
+		q := url.Values{"file": {localPath}, "line": {strconv.Itoa(line)}}
+		return (&url.URL{Scheme: "idea", Host: "open", RawQuery: q.Encode()}).String(), nil
+	default:
+		return "", fmt.Errorf("unsupported openInEditor.scheme setting %q", scheme)
+	}
+}
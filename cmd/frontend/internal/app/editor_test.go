@@ -2,12 +2,14 @@ package app
 
 import (
 	"context"
+	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestGuessRepoNameFromRemoteURL(t *testing.T) {
@@ -84,3 +86,41 @@ func TestEditorRev(t *testing.T) {
 		}
 	}
 }
+
+func TestEditorLink(t *testing.T) {
+	pathMappings := []*schema.EditorPathMapping{
+		{Repository: `^github\.com/foo/(.*)$`, LocalPath: "/home/me/src/$1"},
+		{Repository: `^gitlab\.example\.com/`, LocalPath: "/home/me/gitlab"},
+	}
+	cases := []struct {
+		scheme  string
+		blobURL string
+		want    string
+		wantErr bool
+	}{
+		{"", "https://sourcegraph.com/github.com/foo/bar/-/blob/a.go#L3:5", "vscode://file/home/me/src/bar/a.go:3:5", false},
+		{"vscode", "https://sourcegraph.com/github.com/foo/bar@master/-/blob/cmd/main.go#L3:5-4:1", "vscode://file/home/me/src/bar/cmd/main.go:3:5", false},
+		{"vscode", "/gitlab.example.com/a/b@v1.0/-/blob/README.md", "vscode://file/home/me/gitlab/README.md:1:1", false},
+		{"idea", "/github.com/foo/bar/-/blob/a.go#L12", "idea://open?file=%2Fhome%2Fme%2Fsrc%2Fbar%2Fa.go&line=12", false},
+		{"vscode", "/github.com/baz/qux/-/blob/a.go", "", true},
+		{"vscode", "/github.com/foo/bar/-/tree/cmd", "", true},
+		{"emacs", "/github.com/foo/bar/-/blob/a.go", "", true},
+		{"vscode", "/github.com/foo/bar/-/blob/../../../../etc/passwd", "", true},
+		{"vscode", "/github.com/foo/bar/-/blob/cmd/%2e%2e/%2e%2e/%2e%2e/etc/passwd", "", true},
+		{"vscode", "/github.com/foo/../-/blob/etc/passwd", "", true},
+	}
+	for _, c := range cases {
+		blobURL, err := url.Parse(c.blobURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		settings := &schema.Settings{OpenInEditorScheme: c.scheme, OpenInEditorPathMappings: pathMappings}
+		got, err := editorLink(settings, blobURL)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: got error %v, want error %v", c.blobURL, err, c.wantErr)
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want %q", c.blobURL, got, c.want)
+		}
+	}
+}
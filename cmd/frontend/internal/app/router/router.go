@@ -33,8 +33,9 @@ const (
 	OldToolsRedirect = "old-tools-redirect"
 	OldTreeRedirect  = "old-tree-redirect"
 
-	GDDORefs = "gddo.refs"
-	Editor   = "editor"
+	GDDORefs   = "gddo.refs"
+	Editor     = "editor"
+	EditorLink = "editor-link"
 
 	Debug        = "debug"
 	DebugHeaders = "debug.headers"
@@ -74,6 +75,7 @@ func newRouter() *mux.Router {
 
 	base.Path("/-/godoc/refs").Methods("GET").Name(GDDORefs)
 	base.Path("/-/editor").Methods("GET").Name(Editor)
+	base.Path("/-/editor-link").Methods("GET").Name(EditorLink)
 
 	base.Path("/-/debug/headers").Methods("GET").Name(DebugHeaders)
 	base.PathPrefix("/-/debug").Name(Debug)
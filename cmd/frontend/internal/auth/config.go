@@ -1,6 +1,11 @@
 package auth
 
-import "github.com/sourcegraph/sourcegraph/internal/conf"
+import (
+	"fmt"
+	"net"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
 
 func init() {
 	conf.ContributeValidator(validateConfig)
@@ -16,5 +21,13 @@ func validateConfig(c conf.Unified) (problems conf.Problems) {
 		problems = append(problems, conf.NewCriticalProblem("`auth.enableUsernameChanges` must not be true if external auth providers are set in `auth.providers`"))
 	}
 
+	if c.Critical.InternalAPIAuth != nil {
+		for _, network := range c.Critical.InternalAPIAuth.AllowedNetworks {
+			if _, _, err := net.ParseCIDR(network); err != nil {
+				problems = append(problems, conf.NewCriticalProblem(fmt.Sprintf("invalid network %q in `internalAPI.auth`: %s", network, err)))
+			}
+		}
+	}
+
 	return problems
 }
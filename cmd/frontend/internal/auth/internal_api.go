@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// InternalAPIAuthMiddleware rejects requests to the internal API that are not
+// allowed by the `internalAPI.auth` critical configuration. If it is not set,
+// all requests are allowed (the internal API is then only protected by not
+// being reachable from outside the deployment).
+//
+// The service making the request, if it identified itself, is recorded in the
+// request's trace.
+func InternalAPIAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service, err := checkInternalAPIAuth(conf.Get().Critical.InternalAPIAuth, r)
+		if err != nil {
+			log15.Warn("Rejected internal API request.", "path", r.URL.Path, "remoteAddr", r.RemoteAddr, "service", service, "error", err)
+			http.Error(w, "Access to the internal API is forbidden.", http.StatusForbidden)
+			return
+		}
+
+		span, ctx := opentracing.StartSpanFromContext(r.Context(), "internal API request")
+		defer span.Finish()
+		span.SetTag("path", r.URL.Path)
+		span.SetTag("service", service)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// checkInternalAPIAuth returns an error if r is not allowed by c. It returns
+// the name of the service that sent r, if known.
+func checkInternalAPIAuth(c *schema.InternalAPIAuth, r *http.Request) (service string, err error) {
+	service = r.Header.Get(api.InternalServiceHeader)
+	if c == nil {
+		return service, nil
+	}
+
+	if secret := r.Header.Get(api.InternalSecretHeader); secret != "" {
+		for _, s := range c.Services {
+			if s.Name == service && subtle.ConstantTimeCompare([]byte(s.Secret), []byte(secret)) == 1 {
+				return service, nil
+			}
+		}
+		return service, fmt.Errorf("invalid secret for service %q", service)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return service, err
+	}
+	ip := net.ParseIP(host)
+	for _, network := range c.AllowedNetworks {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			continue // reported by validateConfig
+		}
+		if ip != nil && ipNet.Contains(ip) {
+			return service, nil
+		}
+	}
+	return service, fmt.Errorf("no secret and %s is not in an allowed network", host)
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestInternalAPIAuthMiddleware(t *testing.T) {
+	handler := InternalAPIAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello")
+	}))
+
+	authConfig := &schema.InternalAPIAuth{
+		Services: []*schema.InternalAPIService{
+			{Name: "repo-updater", Secret: "0123456789abcdef"},
+		},
+		AllowedNetworks: []string{"10.0.0.0/8"},
+	}
+
+	tests := []struct {
+		name       string
+		auth       *schema.InternalAPIAuth
+		remoteAddr string
+		service    string
+		secret     string
+		wantCode   int
+	}{
+		{
+			name:       "not configured",
+			remoteAddr: "192.168.0.1:1234",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "valid secret",
+			auth:       authConfig,
+			remoteAddr: "192.168.0.1:1234",
+			service:    "repo-updater",
+			secret:     "0123456789abcdef",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "secret of other service",
+			auth:       authConfig,
+			remoteAddr: "10.0.0.1:1234",
+			service:    "searcher",
+			secret:     "0123456789abcdef",
+			wantCode:   http.StatusForbidden,
+		},
+		{
+			name:       "invalid secret",
+			auth:       authConfig,
+			remoteAddr: "10.0.0.1:1234",
+			service:    "repo-updater",
+			secret:     "fedcba9876543210",
+			wantCode:   http.StatusForbidden,
+		},
+		{
+			name:       "allowed network",
+			auth:       authConfig,
+			remoteAddr: "10.1.2.3:1234",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "other network",
+			auth:       authConfig,
+			remoteAddr: "192.168.0.1:1234",
+			service:    "repo-updater",
+			wantCode:   http.StatusForbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{InternalAPIAuth: test.auth}})
+			defer conf.Mock(nil)
+
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/.internal/ping", nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.service != "" {
+				req.Header.Set(api.InternalServiceHeader, test.service)
+			}
+			if test.secret != "" {
+				req.Header.Set(api.InternalSecretHeader, test.secret)
+			}
+			handler.ServeHTTP(rr, req)
+			if rr.Code != test.wantCode {
+				t.Errorf("got %d, want %d", rr.Code, test.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package bg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// PermissionsInvalidations is the queue of pending invalidations of cached
+// repository permissions, processed by InvalidatePermissions.
+var PermissionsInvalidations = newPermsInvalidationQueue()
+
+// permsInvalidationDelay is how long invalidations are accumulated before
+// being processed, so that bursts of them (e.g. one webhook per repository
+// when a user is added to a team) are coalesced into a single round.
+var permsInvalidationDelay = 5 * time.Second
+
+// permsInvalidationQueue is a set of (user, repo) pairs whose cached
+// permissions must be invalidated. Enqueuing a pair that is already pending
+// is a no-op.
+type permsInvalidationQueue struct {
+	mu      sync.Mutex
+	pending map[int32]map[api.RepoID]struct{}
+	notify  chan struct{}
+}
+
+func newPermsInvalidationQueue() *permsInvalidationQueue {
+	return &permsInvalidationQueue{
+		pending: make(map[int32]map[api.RepoID]struct{}),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Enqueue adds the given pairs to the queue. It doesn't block.
+func (q *permsInvalidationQueue) Enqueue(pairs []api.UserRepoPair) {
+	if len(pairs) == 0 {
+		return
+	}
+
+	q.mu.Lock()
+	for _, p := range pairs {
+		repos, ok := q.pending[p.UserID]
+		if !ok {
+			repos = make(map[api.RepoID]struct{})
+			q.pending[p.UserID] = repos
+		}
+		repos[p.RepoID] = struct{}{}
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default: // A round is already due.
+	}
+}
+
+// take removes and returns all pending pairs, grouped by user.
+func (q *permsInvalidationQueue) take() map[int32]map[api.RepoID]struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := q.pending
+	q.pending = make(map[int32]map[api.RepoID]struct{})
+	return pending
+}
+
+// InvalidatePermissions processes the invalidations enqueued in
+// PermissionsInvalidations until ctx is done, calling the authz providers
+// that cache permissions to invalidate them.
+func InvalidatePermissions(ctx context.Context) {
+	q := PermissionsInvalidations
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.notify:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(permsInvalidationDelay):
+		}
+
+		invalidatePermissions(ctx, q.take())
+	}
+}
+
+func invalidatePermissions(ctx context.Context, pending map[int32]map[api.RepoID]struct{}) {
+	var invalidators []authz.Provider
+	_, providers := authz.GetProviders()
+	for _, p := range providers {
+		if _, ok := p.(authz.PermissionsInvalidator); ok {
+			invalidators = append(invalidators, p)
+		}
+	}
+	if len(invalidators) == 0 {
+		return
+	}
+
+	// 🚨 SECURITY: The repositories are only used to find the cached permissions to
+	// invalidate, never returned to a user, so they must not be filtered by the
+	// permissions of any user.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	repos := make(map[api.RepoID]*types.Repo)
+	for userID, repoIDs := range pending {
+		accts, err := db.ExternalAccounts.List(ctx, db.ExternalAccountsListOptions{UserID: userID})
+		if err != nil {
+			log15.Error("bg.InvalidatePermissions: listing external accounts", "user", userID, "error", err)
+			continue
+		}
+
+		userRepos := make([]*types.Repo, 0, len(repoIDs))
+		for id := range repoIDs {
+			r, ok := repos[id]
+			if !ok {
+				if r, err = db.Repos.Get(ctx, id); err != nil {
+					log15.Warn("bg.InvalidatePermissions: getting repository", "repo", id, "error", err)
+				}
+				repos[id] = r
+			}
+			if r != nil {
+				userRepos = append(userRepos, r)
+			}
+		}
+
+		for _, p := range invalidators {
+			acct := providerAccount(p, accts)
+			if acct == nil {
+				// The user has no permissions cached by this provider.
+				continue
+			}
+
+			var ours []*types.Repo
+			for _, r := range userRepos {
+				if r.ExternalRepo.ServiceID == p.ServiceID() && r.ExternalRepo.ServiceType == p.ServiceType() {
+					ours = append(ours, r)
+				}
+			}
+			if len(ours) == 0 {
+				continue
+			}
+
+			err := p.(authz.PermissionsInvalidator).InvalidatePermissions(ctx, acct, ours)
+			if err != nil {
+				log15.Error("bg.InvalidatePermissions", "user", userID, "authzProvider", p.ServiceID(), "error", err)
+			}
+		}
+	}
+}
+
+// providerAccount returns the external account of the given accounts that
+// identifies the user to the authz provider p, if any.
+func providerAccount(p authz.Provider, accts []*extsvc.ExternalAccount) *extsvc.ExternalAccount {
+	for _, acct := range accts {
+		if acct.ServiceID == p.ServiceID() && acct.ServiceType == p.ServiceType() {
+			return acct
+		}
+	}
+	return nil
+}
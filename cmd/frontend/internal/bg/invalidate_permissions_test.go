@@ -0,0 +1,98 @@
+package bg
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestPermsInvalidationQueue(t *testing.T) {
+	q := newPermsInvalidationQueue()
+	q.Enqueue([]api.UserRepoPair{{UserID: 1, RepoID: 1}, {UserID: 1, RepoID: 2}})
+	q.Enqueue([]api.UserRepoPair{{UserID: 1, RepoID: 1}, {UserID: 2, RepoID: 1}})
+
+	select {
+	case <-q.notify:
+	default:
+		t.Fatal("want a round to be due")
+	}
+
+	want := map[int32]map[api.RepoID]struct{}{
+		1: {1: {}, 2: {}},
+		2: {1: {}},
+	}
+	if got := q.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := q.take(); len(got) != 0 {
+		t.Errorf("got %v, want the queue to be empty", got)
+	}
+}
+
+type mockInvalidator struct {
+	serviceID   string
+	invalidated map[string][]api.RepoID
+}
+
+func (m *mockInvalidator) InvalidatePermissions(ctx context.Context, acct *extsvc.ExternalAccount, repos []*types.Repo) error {
+	for _, r := range repos {
+		m.invalidated[acct.AccountID] = append(m.invalidated[acct.AccountID], r.ID)
+	}
+	sort.Slice(m.invalidated[acct.AccountID], func(i, j int) bool {
+		return m.invalidated[acct.AccountID][i] < m.invalidated[acct.AccountID][j]
+	})
+	return nil
+}
+
+func (m *mockInvalidator) RepoPerms(context.Context, *extsvc.ExternalAccount, []*types.Repo) ([]authz.RepoPerms, error) {
+	return nil, nil
+}
+
+func (m *mockInvalidator) FetchAccount(context.Context, *types.User, []*extsvc.ExternalAccount) (*extsvc.ExternalAccount, error) {
+	return nil, nil
+}
+
+func (m *mockInvalidator) ServiceType() string { return "mock" }
+func (m *mockInvalidator) ServiceID() string   { return m.serviceID }
+func (m *mockInvalidator) Validate() []string  { return nil }
+
+func TestInvalidatePermissions(t *testing.T) {
+	p := &mockInvalidator{serviceID: "https://a/", invalidated: map[string][]api.RepoID{}}
+	authz.SetProviders(true, []authz.Provider{p})
+	defer authz.SetProviders(true, nil)
+
+	db.Mocks.ExternalAccounts.List = func(opt db.ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) {
+		if opt.UserID != 1 {
+			return nil, nil
+		}
+		return []*extsvc.ExternalAccount{{
+			UserID:              1,
+			ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "mock", ServiceID: "https://a/", AccountID: "alice"},
+		}}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		serviceID := "https://a/"
+		if id == 3 {
+			serviceID = "https://b/"
+		}
+		return &types.Repo{ID: id, ExternalRepo: api.ExternalRepoSpec{ServiceType: "mock", ServiceID: serviceID}}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	invalidatePermissions(context.Background(), map[int32]map[api.RepoID]struct{}{
+		1: {1: {}, 2: {}, 3: {}},
+		2: {1: {}},
+	})
+
+	want := map[string][]api.RepoID{"alice": {1, 2}}
+	if !reflect.DeepEqual(p.invalidated, want) {
+		t.Errorf("got invalidated %v, want %v", p.invalidated, want)
+	}
+}
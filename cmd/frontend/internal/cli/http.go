@@ -84,6 +84,8 @@ func healthCheckMiddleware(next http.Handler) http.Handler {
 		switch r.URL.Path {
 		case "/healthz", "/__version":
 			fmt.Fprintf(w, version.Version())
+		case "/readyz":
+			httpapi.ServeReadyz(w, r)
 		default:
 			next.ServeHTTP(w, r)
 		}
@@ -95,10 +97,12 @@ func healthCheckMiddleware(next http.Handler) http.Handler {
 func newInternalHTTPHandler(schema *graphql.Schema) http.Handler {
 	internalMux := http.NewServeMux()
 	internalMux.Handle("/.internal/", gziphandler.GzipHandler(
-		withInternalActor(
-			httpapi.NewInternalHandler(
-				router.NewInternal(mux.NewRouter().PathPrefix("/.internal/").Subrouter()),
-				schema,
+		internalauth.InternalAPIAuthMiddleware( // 🚨 SECURITY: auth middleware
+			withInternalActor(
+				httpapi.NewInternalHandler(
+					router.NewInternal(mux.NewRouter().PathPrefix("/.internal/").Subrouter()),
+					schema,
+				),
 			),
 		),
 	))
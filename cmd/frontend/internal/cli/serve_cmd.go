@@ -24,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/mailreply"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/storagequota"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
@@ -158,7 +159,11 @@ func Main(githubWebhook http.Handler) error {
 	goroutine.Go(func() { bg.CheckRedisCacheEvictionPolicy() })
 	goroutine.Go(func() { bg.DeleteOldCacheDataInRedis() })
 	goroutine.Go(func() { bg.DeleteOldEventLogsInPostgres(context.Background()) })
+	goroutine.Go(func() { bg.InvalidatePermissions(context.Background()) })
 	goroutine.Go(mailreply.StartWorker)
+	goroutine.Go(func() { graphqlbackend.WarmSearcherCaches(context.Background()) })
+	goroutine.Go(func() { graphqlbackend.RunSearchJobs(context.Background()) })
+	goroutine.Go(func() { storagequota.Start(context.Background()) })
 	go updatecheck.Start()
 
 	// Parse GraphQL schema and set up resolvers that depend on dbconn.Global
@@ -0,0 +1,198 @@
+package httpapi
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var (
+	graphqlMaxBodySize = parseBodySize("GRAPHQL_MAX_BODY_SIZE", env.Get("GRAPHQL_MAX_BODY_SIZE", "10485760", "maximum size in bytes of the (decompressed) body of GraphQL requests, or 0 for no limit"))
+
+	lsifUploadMaxBodySize = parseBodySize("LSIF_UPLOAD_MAX_BODY_SIZE", env.Get("LSIF_UPLOAD_MAX_BODY_SIZE", "2147483648", "maximum size in bytes of the body of LSIF uploads, or 0 for no limit"))
+)
+
+func parseBodySize(name, value string) int64 {
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		log.Fatalf("invalid value %q for %s: must be a non-negative number of bytes", value, name)
+	}
+	return size
+}
+
+var requestBodyTooLargeCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "http",
+	Name:      "request_body_too_large_total",
+	Help:      "Total number of API requests that were rejected because their body exceeded the maximum size.",
+}, []string{"handler"})
+
+func init() {
+	prometheus.MustRegister(requestBodyTooLargeCounter)
+}
+
+// errRequestBodyTooLarge is returned when reading more than the maximum size
+// from a request body that is limited by limitBody.
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+type limitedBodyKey struct{}
+
+// limitBody wraps h so that requests whose body exceeds maxSize bytes fail
+// with 413 Request Entity Too Large. Requests that declare a larger
+// Content-Length are rejected before h is called. Otherwise, reads from the
+// body fail with errRequestBodyTooLarge once maxSize bytes have been read, and
+// h must respond with 413 if requestBodyTooLarge reports true. A maxSize of 0
+// means that the body size is not limited.
+//
+// If decodeGzip is true, request bodies with a "Content-Encoding: gzip"
+// header are transparently decompressed, and the limit applies to the
+// decompressed body.
+func limitBody(handler string, maxSize int64, decodeGzip bool, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if maxSize > 0 && r.ContentLength > maxSize {
+			requestBodyTooLargeCounter.WithLabelValues(handler).Inc()
+			http.Error(w, errRequestBodyTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		body := &limitedBody{
+			r:         r.Body,
+			closers:   []io.Closer{r.Body},
+			handler:   handler,
+			maxSize:   maxSize,
+			remaining: maxSize,
+		}
+
+		if decodeGzip && strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, "invalid gzip request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			body.r = zr
+			body.closers = append(body.closers, zr)
+
+			// The decompressed body is passed on, whose length is unknown.
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+		}
+
+		r.Body = body
+		r = r.WithContext(context.WithValue(r.Context(), limitedBodyKey{}, body))
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requestBodyTooLarge reports whether the handler of r failed to read the
+// request body because it exceeded the maximum size set by limitBody. It
+// still works after r.Body has been replaced by a reader of the limited body.
+func requestBodyTooLarge(r *http.Request) bool {
+	body, ok := r.Context().Value(limitedBodyKey{}).(*limitedBody)
+	return ok && body.exceeded
+}
+
+// limitedBody is a request body that fails with errRequestBodyTooLarge once
+// more than the maximum size has been read from it, like
+// http.MaxBytesReader.
+type limitedBody struct {
+	r       io.Reader
+	closers []io.Closer
+	handler string
+
+	maxSize   int64 // 0 if the body size is not limited
+	remaining int64 // the number of bytes that may still be read
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, errRequestBodyTooLarge
+	}
+	if b.maxSize == 0 {
+		return b.r.Read(p)
+	}
+
+	// Read one byte more than allowed to tell if the body is too large.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.r.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		b.exceeded = true
+		requestBodyTooLargeCounter.WithLabelValues(b.handler).Inc()
+		return n, errRequestBodyTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	var err error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if cerr := b.closers[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// streamMultipartFile replaces the body of a multipart/form-data request by
+// the contents of its first file part, so that the file can be streamed to
+// another service without buffering the whole request. The form fields
+// before the file part are discarded. Requests of other content types are
+// left unchanged.
+func streamMultipartFile(r *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return nil
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return errors.New("multipart request has no file part")
+		}
+		if err != nil {
+			return err
+		}
+		if part.FileName() == "" {
+			_ = part.Close()
+			continue
+		}
+
+		r.Body = &multipartFileBody{Part: part, body: r.Body}
+		r.Header.Set("Content-Type", "application/octet-stream")
+		r.Header.Del("Content-Length")
+		r.ContentLength = -1
+		return nil
+	}
+}
+
+// multipartFileBody reads a file part of a multipart request and closes the
+// request body when it is closed.
+type multipartFileBody struct {
+	*multipart.Part
+	body io.Closer
+}
+
+func (b *multipartFileBody) Close() error {
+	_ = b.Part.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitBody(t *testing.T) {
+	// echo responds with the body that it read, or with 413 if it was too large.
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if requestBodyTooLarge(r) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write(body)
+	})
+
+	gzipped := func(s string) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(s))
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name       string
+		maxSize    int64
+		decodeGzip bool
+		body       []byte
+		gzip       bool
+		chunked    bool // don't send the Content-Length of the body
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "within limit", maxSize: 5, body: []byte("abcde"), wantStatus: 200, wantBody: "abcde"},
+		{name: "no limit", maxSize: 0, body: []byte("abcdef"), wantStatus: 200, wantBody: "abcdef"},
+		{name: "content length too large", maxSize: 5, body: []byte("abcdef"), wantStatus: 413},
+		{name: "chunked body too large", maxSize: 5, body: []byte("abcdef"), chunked: true, wantStatus: 413},
+		{name: "gzip decoded", maxSize: 50, decodeGzip: true, body: gzipped("abcde"), gzip: true, wantStatus: 200, wantBody: "abcde"},
+		{name: "gzip decoded too large", maxSize: 50, decodeGzip: true, body: gzipped(strings.Repeat("a", 100)), gzip: true, wantStatus: 413},
+		{name: "gzip not decoded", maxSize: 100, body: gzipped("abcde"), gzip: true, wantStatus: 200, wantBody: string(gzipped("abcde"))},
+		{name: "invalid gzip", maxSize: 5, decodeGzip: true, body: []byte("abcde"), gzip: true, wantStatus: 400},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(test.body))
+			if test.chunked {
+				req.ContentLength = -1
+			}
+			if test.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+
+			rec := httptest.NewRecorder()
+			limitBody("test", test.maxSize, test.decodeGzip, echo).ServeHTTP(rec, req)
+
+			if rec.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d (body %q)", rec.Code, test.wantStatus, rec.Body.String())
+			}
+			if test.wantStatus == 200 && rec.Body.String() != test.wantBody {
+				t.Errorf("got body %q, want %q", rec.Body.String(), test.wantBody)
+			}
+		})
+	}
+}
+
+func TestStreamMultipartFile(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("repository", "github.com/foo/bar")
+	fw, err := mw.CreateFormFile("file", "dump.lsif.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write([]byte("dump"))
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if err := streamMultipartFile(req); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "dump" {
+		t.Errorf("got body %q, want %q", body, "dump")
+	}
+	if have, want := req.Header.Get("Content-Type"), "application/octet-stream"; have != want {
+		t.Errorf("got Content-Type %q, want %q", have, want)
+	}
+	if req.ContentLength != -1 {
+		t.Errorf("got ContentLength %d, want -1", req.ContentLength)
+	}
+
+	// Other requests are left unchanged.
+	req = httptest.NewRequest("POST", "/", strings.NewReader("dump"))
+	if err := streamMultipartFile(req); err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != "dump" {
+		t.Errorf("got body %q, want %q", body, "dump")
+	}
+}
@@ -1,15 +1,27 @@
 package httpapi
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
-	"github.com/graph-gophers/graphql-go/relay"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/apiquota"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/impersonation"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
-func serveGraphQL(schema *graphql.Schema) func(w http.ResponseWriter, r *http.Request) (err error) {
-	relayHandler := &relay.Handler{Schema: schema}
+// serveGraphQL serves GraphQL requests against schema. Operations can be sent
+// as full documents or by the hash of a document registered as a persisted
+// query. If rejectUnregistered is true, unauthenticated users may only run
+// registered documents. If enforceQuotas is true, requests are counted
+// against the API quotas of their users (see package apiquota).
+func serveGraphQL(schema *graphql.Schema, rejectUnregistered, enforceQuotas bool) func(w http.ResponseWriter, r *http.Request) (err error) {
+	queries := getPersistedQueries()
 	return func(w http.ResponseWriter, r *http.Request) (err error) {
 		if r.Method != "POST" {
 			// The URL router should not have routed to this handler if method is not POST, but just in
@@ -17,7 +29,118 @@ func serveGraphQL(schema *graphql.Schema) func(w http.ResponseWriter, r *http.Re
 			return errors.New("method must be POST")
 		}
 
-		relayHandler.ServeHTTP(w, r)
-		return nil
+		var params graphQLParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			if requestBodyTooLarge(r) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return nil
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
+
+		query, registered, err := queries.resolve(&params)
+		if err != nil {
+			return writeGraphQLResponse(w, http.StatusOK, &graphql.Response{
+				Errors: []*gqlerrors.QueryError{{Message: err.Error()}},
+			})
+		}
+
+		// 🚨 SECURITY: Unauthenticated users can only run allow-listed operations when
+		// rejectUnregistered is set, to protect the instance from expensive ad-hoc queries.
+		if rejectUnregistered && !registered && !actor.FromContext(r.Context()).IsAuthenticated() {
+			graphqlRejectedCounter.Inc()
+			return writeGraphQLResponse(w, http.StatusForbidden, &graphql.Response{
+				Errors: []*gqlerrors.QueryError{{Message: "operation is not allowed for unauthenticated users"}},
+			})
+		}
+
+		ctx := r.Context()
+		var quota *apiquota.Status
+		if enforceQuotas {
+			var quotaErr error
+			if quota, quotaErr = apiquota.Take(r, 1); quotaErr != nil {
+				// Don't fail requests because quotas can't be checked.
+				log15.Warn("Failed to take API quota points.", "err", quotaErr)
+			}
+		}
+		if quota != nil {
+			setQuotaHeaders(w, quota)
+			if quota.Exceeded() {
+				graphqlQuotaExceededCounter.Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(quota.Reset).Seconds())+1))
+				return writeGraphQLResponse(w, http.StatusTooManyRequests, &graphql.Response{
+					Errors:     []*gqlerrors.QueryError{{Message: "API quota exceeded, retry after it resets at " + quota.Reset.UTC().Format(time.RFC3339)}},
+					Extensions: quotaExtensions(quota),
+				})
+			}
+			ctx = apiquota.WithStatus(ctx, quota)
+		}
+
+		// Site admins that impersonate a user run their requests as that user
+		// (see package impersonation). Quotas are still taken from the site admin.
+		if ctx, err = impersonation.Apply(ctx); err != nil {
+			return err
+		}
+
+		// 🚨 SECURITY: Impersonation is only meant to show what the impersonated
+		// user can see, so it must not be used to perform mutations as that user.
+		if impersonation.Impersonator(ctx) != 0 && !allowedWhileImpersonating(query) {
+			return writeGraphQLResponse(w, http.StatusForbidden, &graphql.Response{
+				Errors: []*gqlerrors.QueryError{{Message: "mutations are not allowed while impersonating a user, except for stopImpersonatingUser"}},
+			})
+		}
+
+		start := time.Now()
+		response := schema.Exec(ctx, query, params.OperationName, params.Variables)
+		graphqlOperationHistogram.WithLabelValues(
+			operationLabel(query, params.OperationName, registered),
+			strconv.FormatBool(len(response.Errors) > 0),
+		).Observe(time.Since(start).Seconds())
+
+		if quota != nil {
+			response.Extensions = quotaExtensions(quota)
+		}
+		return writeGraphQLResponse(w, http.StatusOK, response)
 	}
 }
+
+// setQuotaHeaders reports the API quota status of the request in the same
+// headers as the GitHub API, so that API consumers can throttle themselves.
+func setQuotaHeaders(w http.ResponseWriter, quota *apiquota.Status) {
+	remaining := quota.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(quota.Reset.Unix(), 10))
+}
+
+// quotaExtensions returns the GraphQL response extensions that report the API
+// quota status of the request.
+func quotaExtensions(quota *apiquota.Status) map[string]interface{} {
+	remaining := quota.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	return map[string]interface{}{
+		"quota": map[string]interface{}{
+			"limit":     quota.Limit,
+			"remaining": remaining,
+			"resetAt":   quota.Reset.UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+func writeGraphQLResponse(w http.ResponseWriter, status int, response *graphql.Response) error {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(responseJSON)
+	return err
+}
@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"errors"
+	"strings"
+)
+
+// allowedWhileImpersonating reports whether the GraphQL document may be run
+// while a site admin impersonates a user (see package impersonation). Since
+// such requests act as the impersonated user, the document may not contain
+// mutations or subscriptions, except for a mutation that only selects
+// stopImpersonatingUser.
+//
+// 🚨 SECURITY: This errs on the side of rejecting documents: all operations
+// of the document are checked regardless of the requested operation name, and
+// documents that can't be tokenized are rejected.
+func allowedWhileImpersonating(doc string) bool {
+	tokens, err := graphqlTokens(doc)
+	if err != nil {
+		return false
+	}
+
+	braces, parens := 0, 0
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "{":
+			braces++
+		case "}":
+			braces--
+		case "(":
+			parens++
+		case ")":
+			parens--
+		case "subscription":
+			if braces == 0 && parens == 0 {
+				return false
+			}
+		case "mutation":
+			if braces == 0 && parens == 0 {
+				end, ok := stopImpersonatingMutation(tokens, i+1)
+				if !ok {
+					return false
+				}
+				i = end
+			}
+		}
+	}
+	return true
+}
+
+// stopImpersonatingMutation checks the tokens following the mutation keyword
+// at tokens[start]. If the selection set of the mutation only consists of the
+// stopImpersonatingUser field, it returns the index of the closing brace of
+// the selection set and true.
+func stopImpersonatingMutation(tokens []string, start int) (end int, ok bool) {
+	// Skip the operation name, variable definitions and directives.
+	i, parens := start, 0
+	for ; i < len(tokens) && (parens > 0 || tokens[i] != "{"); i++ {
+		switch tokens[i] {
+		case "(":
+			parens++
+		case ")":
+			parens--
+		}
+	}
+
+	var fields []string
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return i, len(fields) == 1 && fields[0] == "stopImpersonatingUser"
+			}
+		default:
+			if depth == 1 {
+				fields = append(fields, tokens[i])
+			}
+		}
+	}
+	return 0, false
+}
+
+// graphqlTokens splits the GraphQL document into its names and punctuators.
+// Comments and insignificant characters are dropped, and string and number
+// values are replaced by placeholders, so that their contents are never
+// mistaken for names.
+func graphqlTokens(doc string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(doc); {
+		c := doc[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case strings.HasPrefix(doc[i:], "\ufeff"):
+			i += len("\ufeff")
+		case c == '#':
+			for i < len(doc) && doc[i] != '\n' && doc[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(doc[i:], `"""`):
+			end := strings.Index(strings.Replace(doc[i+3:], `\"""`, `xxxx`, -1), `"""`)
+			if end < 0 {
+				return nil, errors.New("unterminated block string")
+			}
+			i += 3 + end + 3
+			tokens = append(tokens, `""`)
+		case c == '"':
+			i++
+			for ; i < len(doc) && doc[i] != '"'; i++ {
+				if doc[i] == '\\' {
+					i++
+				} else if doc[i] == '\n' || doc[i] == '\r' {
+					return nil, errors.New("unterminated string")
+				}
+			}
+			if i >= len(doc) {
+				return nil, errors.New("unterminated string")
+			}
+			i++
+			tokens = append(tokens, `""`)
+		case strings.HasPrefix(doc[i:], "..."):
+			i += 3
+			tokens = append(tokens, "...")
+		case strings.IndexByte("!$():=@[]{|}&", c) >= 0:
+			i++
+			tokens = append(tokens, string(c))
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			j := i + 1
+			for j < len(doc) && (doc[j] == '_' || 'a' <= doc[j] && doc[j] <= 'z' || 'A' <= doc[j] && doc[j] <= 'Z' || '0' <= doc[j] && doc[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, doc[i:j])
+			i = j
+		case c == '-' || '0' <= c && c <= '9':
+			i++
+			for i < len(doc) && strings.IndexByte("0123456789.eE+-", doc[i]) >= 0 {
+				i++
+			}
+			tokens = append(tokens, "0")
+		default:
+			return nil, errors.New("unexpected character")
+		}
+	}
+	return tokens, nil
+}
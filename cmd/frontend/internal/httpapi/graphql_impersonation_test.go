@@ -0,0 +1,33 @@
+package httpapi
+
+import "testing"
+
+func TestAllowedWhileImpersonating(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want bool
+	}{
+		{`{ currentUser { username } }`, true},
+		{`query Search($q: String = "mutation { deleteUser }") { search(query: $q) { results { matchCount } } }`, true},
+		{`# mutation { deleteUser(user: "x") { alwaysNil } }
+query { currentUser { username } }`, true},
+		{`mutation { stopImpersonatingUser { alwaysNil } }`, true},
+		{`mutation Stop { stopImpersonatingUser }`, true},
+		{`query Q { currentUser { username } } mutation M { stopImpersonatingUser { alwaysNil } }`, true},
+
+		{`mutation { deleteUser(user: "x") { alwaysNil } }`, false},
+		{`mutation { stopImpersonatingUser { alwaysNil } deleteUser(user: "x") { alwaysNil } }`, false},
+		{`mutation { s: stopImpersonatingUser { alwaysNil } }`, false},
+		{`mutation { ...F } fragment F on Mutation { deleteUser(user: "x") { alwaysNil } }`, false},
+		{`mutation($v: Input = {a: 1}) { deleteUser(user: "x") { alwaysNil } }`, false},
+		{`query Q { currentUser { username } } mutation M { deleteUser(user: "x") { alwaysNil } }`, false},
+		{`subscription { events }`, false},
+		{`mutation { deleteUser(user: "x) { alwaysNil } }`, false},
+		{`mutation { deleteUser(user: """x""") { alwaysNil } }`, false},
+	}
+	for _, tc := range tests {
+		if got := allowedWhileImpersonating(tc.doc); got != tc.want {
+			t.Errorf("allowedWhileImpersonating(%q) = %t, want %t", tc.doc, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var (
+	persistedQueriesFile = env.Get("GRAPHQL_PERSISTED_QUERIES_FILE", "", "path to a JSON file that maps the hex-encoded SHA-256 hashes of GraphQL documents to the documents that may be requested by their hash")
+
+	rejectUnregisteredOperations, _ = strconv.ParseBool(env.Get("GRAPHQL_REJECT_UNREGISTERED_OPERATIONS", "false", "reject GraphQL operations from unauthenticated users whose documents are not in GRAPHQL_PERSISTED_QUERIES_FILE"))
+)
+
+var (
+	graphqlOperationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "src",
+		Subsystem: "graphql",
+		Name:      "operation_seconds",
+		Help:      "GraphQL operation latencies in seconds. Operations that are not registered as persisted queries share the operation name \"unregistered\".",
+		Buckets:   []float64{0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"operation", "error"})
+
+	graphqlRejectedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "graphql",
+		Name:      "unregistered_operations_rejected_total",
+		Help:      "Total number of GraphQL operations from unauthenticated users that were rejected because they are not registered as persisted queries.",
+	})
+
+	graphqlQuotaExceededCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "graphql",
+		Name:      "quota_exceeded_total",
+		Help:      "Total number of GraphQL requests that were rejected because the API quota of their user, access token or anonymous client was used up.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(graphqlOperationHistogram)
+	prometheus.MustRegister(graphqlRejectedCounter)
+	prometheus.MustRegister(graphqlQuotaExceededCounter)
+}
+
+// persistedQueries is a registry of allow-listed GraphQL documents, keyed by
+// the hex-encoded SHA-256 hash of the document.
+type persistedQueries map[string]string
+
+var (
+	loadPersistedQueriesOnce sync.Once
+	persistedQueriesFromEnv  persistedQueries
+)
+
+// getPersistedQueries returns the registry read from the file named by
+// GRAPHQL_PERSISTED_QUERIES_FILE. The registry is empty if the environment
+// variable is not set.
+func getPersistedQueries() persistedQueries {
+	loadPersistedQueriesOnce.Do(func() {
+		if persistedQueriesFile == "" {
+			return
+		}
+		data, err := ioutil.ReadFile(persistedQueriesFile)
+		if err != nil {
+			log.Fatalf("failed to read GraphQL persisted queries: %s", err)
+		}
+		persistedQueriesFromEnv, err = parsePersistedQueries(data)
+		if err != nil {
+			log.Fatalf("failed to parse GraphQL persisted queries in %s: %s", persistedQueriesFile, err)
+		}
+	})
+	return persistedQueriesFromEnv
+}
+
+// parsePersistedQueries parses a JSON object that maps hashes to documents
+// and verifies that each hash is the hash of its document.
+func parsePersistedQueries(data []byte) (persistedQueries, error) {
+	var p persistedQueries
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	for hash, query := range p {
+		if want := hashQuery(query); hash != want {
+			return nil, fmt.Errorf("hash %q does not match its document (want %q)", hash, want)
+		}
+	}
+	return p, nil
+}
+
+// hashQuery returns the hex-encoded SHA-256 hash of a GraphQL document.
+func hashQuery(query string) string {
+	h := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(h[:])
+}
+
+// errPersistedQueryNotFound is returned for a request that only contains the
+// hash of a document that is not registered. Clients can retry the request
+// with the full document.
+const errPersistedQueryNotFound = "PersistedQueryNotFound"
+
+// graphQLParams are the parameters of a GraphQL request. A request either
+// contains the full document in Query or refers to a persisted query by the
+// hash of its document in the "persistedQuery" extension.
+type graphQLParams struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	Extensions    struct {
+		PersistedQuery *struct {
+			Version    int    `json:"version"`
+			SHA256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// resolve returns the document to execute for the request and whether it is
+// registered in the registry.
+func (p persistedQueries) resolve(params *graphQLParams) (query string, registered bool, err error) {
+	pq := params.Extensions.PersistedQuery
+	if pq == nil {
+		_, registered = p[hashQuery(params.Query)]
+		return params.Query, registered, nil
+	}
+
+	if pq.Version != 1 {
+		return "", false, fmt.Errorf("unsupported persisted query version %d", pq.Version)
+	}
+	if params.Query == "" {
+		query, registered = p[pq.SHA256Hash]
+		if !registered {
+			return "", false, errors.New(errPersistedQueryNotFound)
+		}
+		return query, true, nil
+	}
+	if hashQuery(params.Query) != pq.SHA256Hash {
+		return "", false, fmt.Errorf("persisted query hash %q does not match the query", pq.SHA256Hash)
+	}
+	_, registered = p[pq.SHA256Hash]
+	return params.Query, registered, nil
+}
+
+var operationNameRegexp = regexp.MustCompile(`\b(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+// operationLabel returns the operation name to use in metrics. Only names
+// declared in registered documents are used, to keep the number of distinct
+// labels bounded.
+func operationLabel(query, operationName string, registered bool) string {
+	if !registered {
+		return "unregistered"
+	}
+	for _, m := range operationNameRegexp.FindAllStringSubmatch(query, -1) {
+		if m[1] == operationName {
+			return operationName
+		}
+	}
+	return "unnamed"
+}
@@ -0,0 +1,117 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestParsePersistedQueries(t *testing.T) {
+	query := `query CurrentUser { currentUser { username } }`
+
+	p, err := parsePersistedQueries([]byte(fmt.Sprintf(`{%q: %q}`, hashQuery(query), query)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p[hashQuery(query)]; got != query {
+		t.Errorf("got query %q, want %q", got, query)
+	}
+
+	if _, err := parsePersistedQueries([]byte(fmt.Sprintf(`{"abc": %q}`, query))); err == nil {
+		t.Error("got nil error for mismatched hash, want non-nil")
+	}
+}
+
+func TestPersistedQueries_resolve(t *testing.T) {
+	registeredQuery := `query CurrentUser { currentUser { username } }`
+	otherQuery := `query Search { search { results { matchCount } } }`
+	queries := persistedQueries{hashQuery(registeredQuery): registeredQuery}
+
+	tests := []struct {
+		name           string
+		params         string
+		wantQuery      string
+		wantRegistered bool
+		wantErr        string
+	}{
+		{
+			name:           "registered query",
+			params:         fmt.Sprintf(`{"query": %q}`, registeredQuery),
+			wantQuery:      registeredQuery,
+			wantRegistered: true,
+		},
+		{
+			name:      "unregistered query",
+			params:    fmt.Sprintf(`{"query": %q}`, otherQuery),
+			wantQuery: otherQuery,
+		},
+		{
+			name:           "registered hash",
+			params:         fmt.Sprintf(`{"extensions": {"persistedQuery": {"version": 1, "sha256Hash": %q}}}`, hashQuery(registeredQuery)),
+			wantQuery:      registeredQuery,
+			wantRegistered: true,
+		},
+		{
+			name:    "unregistered hash",
+			params:  fmt.Sprintf(`{"extensions": {"persistedQuery": {"version": 1, "sha256Hash": %q}}}`, hashQuery(otherQuery)),
+			wantErr: errPersistedQueryNotFound,
+		},
+		{
+			name:      "unregistered hash with query",
+			params:    fmt.Sprintf(`{"query": %q, "extensions": {"persistedQuery": {"version": 1, "sha256Hash": %q}}}`, otherQuery, hashQuery(otherQuery)),
+			wantQuery: otherQuery,
+		},
+		{
+			name:    "hash not matching query",
+			params:  fmt.Sprintf(`{"query": %q, "extensions": {"persistedQuery": {"version": 1, "sha256Hash": %q}}}`, otherQuery, hashQuery(registeredQuery)),
+			wantErr: fmt.Sprintf("persisted query hash %q does not match the query", hashQuery(registeredQuery)),
+		},
+		{
+			name:    "unsupported version",
+			params:  fmt.Sprintf(`{"extensions": {"persistedQuery": {"version": 2, "sha256Hash": %q}}}`, hashQuery(registeredQuery)),
+			wantErr: "unsupported persisted query version 2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var params graphQLParams
+			if err := json.Unmarshal([]byte(tc.params), &params); err != nil {
+				t.Fatal(err)
+			}
+
+			query, registered, err := queries.resolve(&params)
+			if have, want := fmt.Sprint(err), fmt.Sprint(tc.wantErr); tc.wantErr != "" && have != want {
+				t.Fatalf("have error %q, want %q", have, want)
+			} else if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if query != tc.wantQuery {
+				t.Errorf("have query %q, want %q", query, tc.wantQuery)
+			}
+			if registered != tc.wantRegistered {
+				t.Errorf("have registered %t, want %t", registered, tc.wantRegistered)
+			}
+		})
+	}
+}
+
+func TestOperationLabel(t *testing.T) {
+	query := `query CurrentUser { currentUser { username } } mutation LogEvent { logEvent { alwaysNil } }`
+
+	for _, tc := range []struct {
+		operationName string
+		registered    bool
+		want          string
+	}{
+		{"CurrentUser", true, "CurrentUser"},
+		{"LogEvent", true, "LogEvent"},
+		{"Other", true, "unnamed"},
+		{"", true, "unnamed"},
+		{"CurrentUser", false, "unregistered"},
+	} {
+		if got := operationLabel(query, tc.operationName, tc.registered); got != tc.want {
+			t.Errorf("operationLabel(%q, %t) = %q, want %q", tc.operationName, tc.registered, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// healthCheckTimeout is how long a dependency may take to respond to a
+// health check before it is considered unavailable.
+const healthCheckTimeout = 5 * time.Second
+
+// errHealthCheckDisabled is returned by the health check of a dependency that
+// is disabled in the site configuration.
+var errHealthCheckDisabled = errors.New("disabled")
+
+// A healthCheck probes a dependency of the frontend.
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+var healthChecks = []healthCheck{
+	{
+		name:  "database",
+		check: func(ctx context.Context) error { return dbconn.Global.PingContext(ctx) },
+	},
+	{
+		name:  "redis-cache",
+		check: func(ctx context.Context) error { return pingRedis(redispool.Cache) },
+	},
+	{
+		name:  "redis-store",
+		check: func(ctx context.Context) error { return pingRedis(redispool.Store) },
+	},
+	{
+		name:  "gitserver",
+		check: func(ctx context.Context) error { return gitserver.DefaultClient.Ping(ctx) },
+	},
+	{
+		name: "zoekt",
+		check: func(ctx context.Context) error {
+			if !search.Indexed().Enabled() {
+				return errHealthCheckDisabled
+			}
+			// The list of indexed repositories is cached and refreshed in the
+			// background, so this only reports an error after zoekt has been
+			// unavailable for a while.
+			_, err := search.Indexed().ListAll(ctx)
+			return err
+		},
+	},
+}
+
+func pingRedis(pool *redis.Pool) error {
+	c := pool.Get()
+	defer c.Close()
+	_, err := c.Do("PING")
+	return err
+}
+
+// healthStatus is the JSON response of the /readyz endpoint.
+type healthStatus struct {
+	Status  string                      `json:"status"` // "ok" or "unavailable"
+	Version string                      `json:"version"`
+	Checks  map[string]dependencyHealth `json:"checks"`
+}
+
+// dependencyHealth is the result of the health check of a dependency.
+type dependencyHealth struct {
+	Status    string  `json:"status"` // "ok", "error", or "disabled"
+	LatencyMS float64 `json:"latencyMs"`
+}
+
+// ServeReadyz reports whether the frontend is ready to serve requests, i.e.
+// whether all of its dependencies (the database, redis, gitserver and zoekt)
+// are available. It responds with HTTP 503 if they are not.
+//
+// Unlike /healthz, which only reports that the frontend process is alive,
+// this probes the dependencies, so it must not be used as a liveness check:
+// an outage of a dependency would restart every frontend.
+func ServeReadyz(w http.ResponseWriter, r *http.Request) {
+	status := healthStatus{
+		Status:  "ok",
+		Version: version.Version(),
+		Checks:  runHealthChecks(r.Context(), healthChecks),
+	}
+	code := http.StatusOK
+	for _, h := range status.Checks {
+		if h.Status == "error" {
+			status.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// runHealthChecks runs the health checks concurrently. A check that doesn't
+// finish within healthCheckTimeout fails.
+//
+// 🚨 SECURITY: The readiness endpoint is served to unauthenticated clients, so
+// the errors (which may contain internal addresses) are only logged.
+func runHealthChecks(ctx context.Context, checks []healthCheck) map[string]dependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	type result struct {
+		name    string
+		err     error
+		latency time.Duration
+	}
+	results := make(chan result, len(checks))
+	for _, c := range checks {
+		go func(c healthCheck) {
+			start := time.Now()
+			err := c.check(ctx)
+			results <- result{name: c.name, err: err, latency: time.Since(start)}
+		}(c)
+	}
+
+	healths := make(map[string]dependencyHealth, len(checks))
+	for _, c := range checks {
+		healths[c.name] = dependencyHealth{Status: "error", LatencyMS: durationMS(healthCheckTimeout)}
+	}
+	for range checks {
+		select {
+		case res := <-results:
+			h := dependencyHealth{Status: "ok", LatencyMS: durationMS(res.latency)}
+			if res.err == errHealthCheckDisabled {
+				h.Status = "disabled"
+			} else if res.err != nil {
+				h.Status = "error"
+				log15.Warn("health check failed", "dependency", res.name, "error", res.err)
+			}
+			healths[res.name] = h
+		case <-ctx.Done():
+			log15.Warn("health checks timed out", "timeout", healthCheckTimeout)
+			return healths
+		}
+	}
+	return healths
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,77 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeReadyz(t *testing.T) {
+	orig := healthChecks
+	defer func() { healthChecks = orig }()
+
+	var gitserverErr error
+	healthChecks = []healthCheck{
+		{name: "database", check: func(context.Context) error { return nil }},
+		{name: "gitserver", check: func(context.Context) error { return gitserverErr }},
+		{name: "zoekt", check: func(context.Context) error { return errHealthCheckDisabled }},
+	}
+
+	serve := func(t *testing.T) (int, healthStatus) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		ServeReadyz(rec, httptest.NewRequest("GET", "/readyz", nil))
+		var status healthStatus
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, status
+	}
+
+	checkStatuses := func(t *testing.T, status healthStatus, want map[string]string) {
+		t.Helper()
+		if len(status.Checks) != len(want) {
+			t.Errorf("got checks %+v, want %v", status.Checks, want)
+		}
+		for name, s := range want {
+			if got := status.Checks[name].Status; got != s {
+				t.Errorf("%s: got status %q, want %q", name, got, s)
+			}
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		gitserverErr = nil
+		code, status := serve(t)
+		if code != http.StatusOK || status.Status != "ok" {
+			t.Errorf("got %d %q, want 200 ok", code, status.Status)
+		}
+		checkStatuses(t, status, map[string]string{"database": "ok", "gitserver": "ok", "zoekt": "disabled"})
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		gitserverErr = errors.New("unreachable")
+		code, status := serve(t)
+		if code != http.StatusServiceUnavailable || status.Status != "unavailable" {
+			t.Errorf("got %d %q, want 503 unavailable", code, status.Status)
+		}
+		checkStatuses(t, status, map[string]string{"database": "ok", "gitserver": "error", "zoekt": "disabled"})
+	})
+}
+
+func TestRunHealthChecks_timeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // behaves like a timeout
+
+	block := make(chan struct{})
+	defer close(block)
+	healths := runHealthChecks(ctx, []healthCheck{
+		{name: "slow", check: func(context.Context) error { <-block; return nil }},
+	})
+	if got := healths["slow"].Status; got != "error" {
+		t.Errorf("got status %q, want error", got)
+	}
+}
@@ -44,6 +44,11 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook http.Handle
 
 	m.Get(apirouter.Telemetry).Handler(trace.TraceRoute(telemetryHandler))
 
+	m.Get(apirouter.SearchJobResults).Handler(trace.TraceRoute(handler(serveSearchJobResults)))
+	m.Get(apirouter.SearchStream).Handler(trace.TraceRoute(handler(serveSearchStream)))
+	m.Get(apirouter.SavedSearchFeed).Handler(trace.TraceRoute(handler(serveSavedSearchFeed(schema))))
+	m.Get(apirouter.RepoIntroductions).Handler(trace.TraceRoute(handler(serveRepoIntroductions)))
+
 	if githubWebhook != nil {
 		m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(githubWebhook))
 	}
@@ -52,14 +57,17 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook http.Handle
 		m.Path("/updates").Methods("GET").Name("updatecheck").Handler(trace.TraceRoute(http.HandlerFunc(updatecheck.Handler)))
 	}
 
-	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(handler(serveGraphQL(schema))))
+	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(limitBody("graphql", graphqlMaxBodySize, true, handler(serveGraphQL(schema, rejectUnregisteredOperations, true)))))
 
 	lsifServerURL, err := url.Parse(lsifServerURLFromEnv)
 	if err != nil {
 		log15.Error("skipping initialization of the LSIF HTTP API because the environment variable LSIF_SERVER_URL is not a valid URL", "parse_error", err, "value", lsifServerURLFromEnv)
 	} else {
 		proxy := httputil.NewSingleHostReverseProxy(lsifServerURL)
-		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(http.HandlerFunc(lsifUploadProxyHandler(proxy))))
+		proxy.ErrorHandler = lsifProxyErrorHandler
+		// LSIF uploads are gzipped dumps that the LSIF server decompresses itself, so they are
+		// passed on as is.
+		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(limitBody("lsif_upload", lsifUploadMaxBodySize, false, http.HandlerFunc(lsifUploadProxyHandler(proxy)))))
 		m.Get(apirouter.LSIF).Handler(trace.TraceRoute(http.HandlerFunc(lsifProxyHandler(proxy))))
 	}
 
@@ -90,10 +98,13 @@ func NewInternalHandler(m *mux.Router, schema *graphql.Schema) http.Handler {
 	m.Get(apirouter.PhabricatorRepoCreate).Handler(trace.TraceRoute(handler(servePhabricatorRepoCreate)))
 	m.Get(apirouter.ReposCreateIfNotExists).Handler(trace.TraceRoute(handler(serveReposCreateIfNotExists)))
 	m.Get(apirouter.ReposUpdateMetadata).Handler(trace.TraceRoute(handler(serveReposUpdateMetadata)))
+	m.Get(apirouter.ReposSetKVP).Handler(trace.TraceRoute(handler(serveReposSetKVP)))
+	m.Get(apirouter.ReposUpdateLastCommit).Handler(trace.TraceRoute(handler(serveReposUpdateLastCommit)))
 	m.Get(apirouter.ReposList).Handler(trace.TraceRoute(handler(serveReposList)))
 	m.Get(apirouter.ReposListEnabled).Handler(trace.TraceRoute(handler(serveReposListEnabled)))
 	m.Get(apirouter.ReposGetByName).Handler(trace.TraceRoute(handler(serveReposGetByName)))
 	m.Get(apirouter.SettingsGetForSubject).Handler(trace.TraceRoute(handler(serveSettingsGetForSubject)))
+	m.Get(apirouter.SettingsWatchSubject).Handler(trace.TraceRoute(handler(serveSettingsWatchSubject)))
 	m.Get(apirouter.SavedQueriesListAll).Handler(trace.TraceRoute(handler(serveSavedQueriesListAll)))
 	m.Get(apirouter.SavedQueriesGetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesGetInfo)))
 	m.Get(apirouter.SavedQueriesSetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesSetInfo)))
@@ -102,13 +113,17 @@ func NewInternalHandler(m *mux.Router, schema *graphql.Schema) http.Handler {
 	m.Get(apirouter.OrgsGetByName).Handler(trace.TraceRoute(handler(serveOrgsGetByName)))
 	m.Get(apirouter.UsersGetByUsername).Handler(trace.TraceRoute(handler(serveUsersGetByUsername)))
 	m.Get(apirouter.UserEmailsGetEmail).Handler(trace.TraceRoute(handler(serveUserEmailsGetEmail)))
+	m.Get(apirouter.UserEmailsGetEmails).Handler(trace.TraceRoute(handler(serveUserEmailsGetEmails)))
 	m.Get(apirouter.ExternalURL).Handler(trace.TraceRoute(handler(serveExternalURL)))
 	m.Get(apirouter.CanSendEmail).Handler(trace.TraceRoute(handler(serveCanSendEmail)))
 	m.Get(apirouter.SendEmail).Handler(trace.TraceRoute(handler(serveSendEmail)))
+	m.Get(apirouter.SendNotification).Handler(trace.TraceRoute(handler(serveSendNotification)))
 	m.Get(apirouter.GitResolveRevision).Handler(trace.TraceRoute(handler(serveGitResolveRevision)))
 	m.Get(apirouter.GitTar).Handler(trace.TraceRoute(handler(serveGitTar)))
+	m.Get(apirouter.GitIsAncestor).Handler(trace.TraceRoute(handler(serveGitIsAncestor)))
+	m.Get(apirouter.AuthzInvalidatePerms).Handler(trace.TraceRoute(handler(serveAuthzInvalidatePerms)))
 	m.Get(apirouter.Telemetry).Handler(trace.TraceRoute(telemetryHandler))
-	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(handler(serveGraphQL(schema))))
+	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(limitBody("graphql_internal", graphqlMaxBodySize, true, handler(serveGraphQL(schema, false, false)))))
 	m.Get(apirouter.Configuration).Handler(trace.TraceRoute(handler(serveConfiguration)))
 	m.Get(apirouter.SearchConfiguration).Handler(trace.TraceRoute(handler(serveSearchConfiguration)))
 	m.Path("/ping").Methods("GET").Name("ping").HandlerFunc(handlePing)
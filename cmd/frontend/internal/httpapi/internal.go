@@ -1,8 +1,12 @@
 package httpapi
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 
@@ -11,6 +15,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/bg"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/searchhotspots"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -78,6 +84,34 @@ func serveReposUpdateMetadata(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveReposSetKVP(w http.ResponseWriter, r *http.Request) error {
+	var req api.ReposSetKVPRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return err
+	}
+	repo, err := db.Repos.GetByName(r.Context(), req.RepoName)
+	if err != nil {
+		return errors.Wrap(err, "Repos.GetByName failed")
+	}
+	if err := db.RepoKVPs.Set(r.Context(), repo.ID, req.Key, req.Value); err != nil {
+		return errors.Wrap(err, "RepoKVPs.Set failed")
+	}
+	return nil
+}
+
+func serveReposUpdateLastCommit(w http.ResponseWriter, r *http.Request) error {
+	var req api.ReposUpdateLastCommitRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return err
+	}
+	if err := db.Repos.UpdateLastCommitAt(r.Context(), req.RepoName, req.LastCommitAt); err != nil {
+		return errors.Wrap(err, "Repos.UpdateLastCommitAt failed")
+	}
+	return nil
+}
+
 func servePhabricatorRepoCreate(w http.ResponseWriter, r *http.Request) error {
 	var repo api.PhabricatorRepoCreateRequest
 	err := json.NewDecoder(r.Body).Decode(&repo)
@@ -176,14 +210,24 @@ func serveConfiguration(w http.ResponseWriter, r *http.Request) error {
 // search specific endpoint is used rather than serving the entire site settings
 // from /.internal/configuration.
 func serveSearchConfiguration(w http.ResponseWriter, r *http.Request) error {
+	excludedPaths, err := db.SearchIndexExclusions.ListPathPatterns(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "SearchIndexExclusions.ListPathPatterns")
+	}
 	opts := struct {
-		LargeFiles []string
-		Symbols    bool
+		LargeFiles    []string
+		ExcludedPaths []string // glob patterns of files that must not be indexed
+		Symbols       bool
+		// UnicodeNormalization is the form ("NFC" or "NFD") that unicode:yes
+		// searches normalize their patterns to.
+		UnicodeNormalization string
 	}{
-		LargeFiles: conf.Get().SearchLargeFiles,
-		Symbols:    conf.SymbolIndexEnabled(),
+		LargeFiles:           conf.Get().SearchLargeFiles,
+		ExcludedPaths:        excludedPaths,
+		Symbols:              conf.SymbolIndexEnabled(),
+		UnicodeNormalization: conf.SearchUnicodeNormalization(),
 	}
-	err := json.NewEncoder(w).Encode(opts)
+	err = json.NewEncoder(w).Encode(opts)
 	if err != nil {
 		return errors.Wrap(err, "encode")
 	}
@@ -206,6 +250,15 @@ func serveReposList(w http.ResponseWriter, r *http.Request) error {
 		return errors.Wrap(err, "listing repos")
 	}
 
+	// zoekt-sourcegraph-indexserver indexes the repositories in the listed
+	// order, so list the most searched ones first (see package
+	// searchhotspots), along with their most searched branches.
+	hotBranches, err := orderByHotSpots(res)
+	if err != nil {
+		// The order is only an optimization.
+		log15.Warn("failed to order repositories by search hot spots", "error", err)
+	}
+
 	// BACKCOMPAT: Add a Name field that serializes to `URI` because
 	// zoekt-sourcegraph-indexserver expects one to exist (with the
 	// repository name). This is a legacy of the rename from "repo URI" to
@@ -215,11 +268,17 @@ func serveReposList(w http.ResponseWriter, r *http.Request) error {
 		// The Repo field has been removed because the only caller of
 		// this handler (zoekt-sourcegraph-indexserver) wasn't using
 		// it.
+
+		// Branches are the most searched branches other than the default
+		// branch, which indexservers that index several branches should
+		// index first.
+		Branches []string `json:",omitempty"`
 	}
 	res2 := make([]repoWithBackcompatURIField, len(res))
 	for i, repo := range res {
 		res2[i] = repoWithBackcompatURIField{
-			Name: string(repo.Name),
+			Name:     string(repo.Name),
+			Branches: hotBranches[repo.Name],
 		}
 	}
 
@@ -232,6 +291,35 @@ func serveReposList(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// orderByHotSpots stably sorts repos so that the search hot spots come
+// first, the most searched first. It returns the most searched branches of
+// each hot spot other than its default branch.
+func orderByHotSpots(repos []*types.Repo) (map[api.RepoName][]string, error) {
+	hotSpots, err := searchhotspots.List(0)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[api.RepoName]int, len(hotSpots))
+	branches := make(map[api.RepoName][]string, len(hotSpots))
+	for i, h := range hotSpots {
+		rank[h.Repo] = i + 1
+		for _, rev := range h.Revs {
+			// Commits, ranges and globs are not branches.
+			if rev.Value == "HEAD" || git.IsAbsoluteRevision(rev.Value) || strings.ContainsAny(rev.Value, "^:*~") || strings.Contains(rev.Value, "..") {
+				continue
+			}
+			branches[h.Repo] = append(branches[h.Repo], rev.Value)
+		}
+	}
+
+	sort.SliceStable(repos, func(i, j int) bool {
+		ri, rj := rank[repos[i].Name], rank[repos[j].Name]
+		return ri != 0 && (rj == 0 || ri < rj)
+	})
+	return branches, nil
+}
+
 func serveReposListEnabled(w http.ResponseWriter, r *http.Request) error {
 	names, err := db.Repos.ListEnabledNames(r.Context())
 	if err != nil {
@@ -335,6 +423,68 @@ func serveSettingsGetForSubject(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+var (
+	// settingsWatchTimeout is how long serveSettingsWatchSubject waits for
+	// settings to change before responding that they didn't.
+	settingsWatchTimeout = 30 * time.Second
+
+	// settingsWatchPollInterval is how often serveSettingsWatchSubject checks
+	// the DB for changed settings. Settings may be changed by any frontend
+	// replica, so it can't rely on in-process notifications.
+	settingsWatchPollInterval = time.Second
+)
+
+// serveSettingsWatchSubject is a long-poll endpoint that responds as soon as
+// the latest settings of a subject differ from the ones known to the client,
+// or after settingsWatchTimeout if they don't change. It lets services react
+// to settings changes without repeatedly requesting the settings.
+func serveSettingsWatchSubject(w http.ResponseWriter, r *http.Request) error {
+	var req api.SettingsWatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), settingsWatchTimeout)
+	defer cancel()
+
+	var resp api.SettingsWatchResponse
+poll:
+	for {
+		settings, err := db.Settings.GetLatest(ctx, req.Subject)
+		if err != nil && ctx.Err() == nil {
+			return errors.Wrap(err, "Settings.GetLatest")
+		}
+		if err == nil && settingsChanged(settings, req.LastID) {
+			resp = api.SettingsWatchResponse{Changed: true, Settings: settings}
+			break
+		}
+
+		select {
+		case <-time.After(settingsWatchPollInterval):
+		case <-ctx.Done():
+			if r.Context().Err() != nil {
+				// The client went away.
+				return nil
+			}
+			break poll
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+// settingsChanged reports whether latest differs from the settings with the
+// given ID.
+func settingsChanged(latest *api.Settings, lastID *int32) bool {
+	if latest == nil {
+		return lastID != nil
+	}
+	return lastID == nil || *lastID != latest.ID
+}
+
 func serveOrgsListUsers(w http.ResponseWriter, r *http.Request) error {
 	var orgID int32
 	err := json.NewDecoder(r.Body).Decode(&orgID)
@@ -403,6 +553,22 @@ func serveUserEmailsGetEmail(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveUserEmailsGetEmails(w http.ResponseWriter, r *http.Request) error {
+	var userIDs []int32
+	err := json.NewDecoder(r.Body).Decode(&userIDs)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	emails, err := db.UserEmails.GetPrimaryEmails(r.Context(), userIDs...)
+	if err != nil {
+		return errors.Wrap(err, "UserEmails.GetPrimaryEmails")
+	}
+	if err := json.NewEncoder(w).Encode(emails); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
 func serveExternalURL(w http.ResponseWriter, r *http.Request) error {
 	if err := json.NewEncoder(w).Encode(globals.ExternalURL().String()); err != nil {
 		return errors.Wrap(err, "Encode")
@@ -443,6 +609,46 @@ func serveGitResolveRevision(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// serveGitIsAncestor answers a batch of ancestry queries on the commits of a
+// repository, so that callers don't need a round trip per pair of commits.
+func serveGitIsAncestor(w http.ResponseWriter, r *http.Request) error {
+	var req api.GitIsAncestorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+
+	repo := gitserver.Repo{Name: req.RepoName}
+	isAncestor := make([]bool, len(req.Pairs))
+	for i, p := range req.Pairs {
+		var err error
+		isAncestor[i], err = git.IsAncestor(r.Context(), repo, p.Ancestor, p.Descendant)
+		if err != nil {
+			return errors.Wrapf(err, "IsAncestor(%s, %s)", p.Ancestor, p.Descendant)
+		}
+	}
+
+	data, err := json.Marshal(isAncestor)
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+	return nil
+}
+
+// serveAuthzInvalidatePerms enqueues the invalidation of a batch of cached
+// repository permissions. It returns before they are invalidated.
+func serveAuthzInvalidatePerms(w http.ResponseWriter, r *http.Request) error {
+	var req api.PermissionsInvalidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	bg.PermissionsInvalidations.Enqueue(req.Pairs)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
 func serveGitTar(w http.ResponseWriter, r *http.Request) error {
 	// used by zoekt-sourcegraph-mirror
 	vars := mux.Vars(r)
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 
@@ -15,7 +17,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 func Test_serveReposList(t *testing.T) {
@@ -100,3 +104,80 @@ func Test_serveReposList(t *testing.T) {
 		}
 	})
 }
+
+func Test_serveSettingsWatchSubject(t *testing.T) {
+	defer func(timeout, interval time.Duration) {
+		settingsWatchTimeout, settingsWatchPollInterval = timeout, interval
+	}(settingsWatchTimeout, settingsWatchPollInterval)
+	settingsWatchTimeout, settingsWatchPollInterval = 100*time.Millisecond, time.Millisecond
+
+	var calls int32
+	db.Mocks.Settings.GetLatest = func(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		// The settings change on the third poll.
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return &api.Settings{ID: 1, Contents: "{}"}, nil
+		}
+		return &api.Settings{ID: 2, Contents: `{"a": 1}`}, nil
+	}
+	defer func() { db.Mocks.Settings.GetLatest = nil }()
+
+	watch := func(t *testing.T, lastID int32) api.SettingsWatchResponse {
+		t.Helper()
+		body, _ := json.Marshal(api.SettingsWatchRequest{Subject: api.SettingsSubject{Site: true}, LastID: &lastID})
+		rr := httptest.NewRecorder()
+		if err := serveSettingsWatchSubject(rr, httptest.NewRequest("POST", "/", bytes.NewReader(body))); err != nil {
+			t.Fatal(err)
+		}
+		var resp api.SettingsWatchResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := watch(t, 1); !resp.Changed || resp.Settings.ID != 2 {
+		t.Errorf("got %+v, want changed settings with ID 2", resp)
+	}
+	if resp := watch(t, 2); resp.Changed {
+		t.Errorf("got %+v, want unchanged settings", resp)
+	}
+}
+
+func Test_serveGitIsAncestor(t *testing.T) {
+	git.Mocks.IsAncestor = func(a, b api.CommitID) (bool, error) {
+		return a < b, nil
+	}
+	defer git.ResetMocks()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := serveGitIsAncestor(w, r); err != nil {
+			t.Errorf("calling serveGitIsAncestor: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	body, err := json.Marshal(api.GitIsAncestorRequest{
+		RepoName: "github.com/foo/bar",
+		Pairs: []api.GitCommitPair{
+			{Ancestor: "a", Descendant: "b"},
+			{Ancestor: "c", Descendant: "b"},
+			{Ancestor: "b", Descendant: "c"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(ts.URL, "application/json; charset=utf8", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("calling http.Post: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got []bool
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("json decoding response: %v", err)
+	}
+	if want := []bool{true, false, true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 var apiURL = url.URL{Scheme: "https", Host: "api.github.com"}
@@ -62,7 +63,30 @@ func lsifUploadProxyHandler(p *httputil.ReverseProxy) func(http.ResponseWriter,
 			}
 		}
 
+		// Uploads can also be sent as the file of a multipart form, which is streamed to the
+		// LSIF server as is.
+		if err := streamMultipartFile(r); err != nil {
+			if requestBodyTooLarge(r) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Invalid multipart upload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		r.URL.Path = "upload"
 		p.ServeHTTP(w, r)
 	}
 }
+
+// lsifProxyErrorHandler responds to requests that could not be proxied to the
+// LSIF server. Uploads that exceed the maximum size fail with 413 Request
+// Entity Too Large.
+func lsifProxyErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	if requestBodyTooLarge(r) {
+		http.Error(w, errRequestBodyTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	log15.Error("LSIF proxy error", "method", r.Method, "path", r.URL.Path, "error", err)
+	w.WriteHeader(http.StatusBadGateway)
+}
@@ -0,0 +1,92 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"golang.org/x/time/rate"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// notificationLimiter limits the rate at which notification emails are sent
+// (by all features together), so that a burst of notifications (e.g. for a
+// saved search with many subscribers) doesn't get the SMTP server to throttle
+// or reject the emails.
+var notificationLimiter = rate.NewLimiter(rate.Limit(10), 50)
+
+// serveSendNotification emails a notification to each of the users in the
+// request. It responds with the delivery status for each of them, so that a
+// user without an email or a failed delivery doesn't fail the whole request.
+func serveSendNotification(w http.ResponseWriter, r *http.Request) error {
+	var req api.SendNotificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	if !conf.CanSendEmail() {
+		return errors.New("sending emails is not configured (in email.smtp)")
+	}
+	if _, err := txemail.ParseTemplate(req.Template); err != nil {
+		return errors.Wrap(err, "ParseTemplate")
+	}
+
+	deliveries, err := sendNotification(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(w).Encode(deliveries); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+// sendNotification sends a separate email to each of the users in req (once,
+// even if they are listed more than once) and returns their delivery status,
+// in the order of req.UserIDs.
+func sendNotification(ctx context.Context, req api.SendNotificationRequest) ([]api.NotificationDelivery, error) {
+	emails, err := db.UserEmails.GetPrimaryEmails(ctx, req.UserIDs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "UserEmails.GetPrimaryEmails")
+	}
+
+	deliveries := make([]api.NotificationDelivery, 0, len(req.UserIDs))
+	seen := make(map[int32]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		d := api.NotificationDelivery{UserID: userID, Email: emails[userID]}
+		switch {
+		case d.Email == "":
+			d.Error = "user has no email address"
+		case notificationLimiter.Wait(ctx) != nil:
+			// The request was canceled or its deadline is too close to wait
+			// for the rate limit.
+			d.Error = "rate limit exceeded"
+		default:
+			err := txemail.Send(ctx, txemail.Message{
+				To:       []string{d.Email},
+				Template: req.Template,
+				Data:     req.Data,
+			})
+			if err != nil {
+				d.Error = err.Error()
+			} else {
+				d.Sent = true
+			}
+		}
+
+		if !d.Sent {
+			log15.Warn("Failed to send notification email.", "source", req.Source, "userID", userID, "error", d.Error)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
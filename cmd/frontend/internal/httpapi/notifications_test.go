@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+)
+
+func TestSendNotification(t *testing.T) {
+	db.Mocks.UserEmails.GetPrimaryEmails = func(ctx context.Context, ids ...int32) (map[int32]string, error) {
+		return map[int32]string{1: "alice@example.com", 2: "bob@example.com"}, nil
+	}
+	defer func() { db.Mocks.UserEmails.GetPrimaryEmails = nil }()
+
+	var sent []string
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		if message.To[0] == "bob@example.com" {
+			return errors.New("mailbox full")
+		}
+		sent = append(sent, message.To...)
+		return nil
+	}
+	defer func() { txemail.MockSend = nil }()
+
+	deliveries, err := sendNotification(context.Background(), api.SendNotificationRequest{
+		Source:  "test",
+		UserIDs: []int32{1, 2, 3, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []api.NotificationDelivery{
+		{UserID: 1, Email: "alice@example.com", Sent: true},
+		{UserID: 2, Email: "bob@example.com", Error: "mailbox full"},
+		{UserID: 3, Error: "user has no email address"},
+	}
+	if !reflect.DeepEqual(deliveries, want) {
+		t.Errorf("got deliveries %+v, want %+v", deliveries, want)
+	}
+	if want := []string{"alice@example.com"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent emails to %v, want %v", sent, want)
+	}
+}
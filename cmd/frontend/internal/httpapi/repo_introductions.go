@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// serveRepoIntroductions writes the record of when each external service first
+// yielded each repository as CSV, latest first, for compliance audits.
+func serveRepoIntroductions(w http.ResponseWriter, r *http.Request) error {
+	// 🚨 SECURITY: Only site admins may view how repositories were added.
+	if err := backend.CheckCurrentUserIsSiteAdmin(r.Context()); err != nil {
+		return err
+	}
+
+	introductions, err := db.RepoIntroductions.List(r.Context(), db.RepoIntroductionsListOptions{})
+	if err != nil {
+		return err
+	}
+
+	externalServices, err := db.ExternalServices.List(r.Context(), db.ExternalServicesListOptions{})
+	if err != nil {
+		return err
+	}
+	externalServicesByID := make(map[int64]*types.ExternalService, len(externalServices))
+	for _, s := range externalServices {
+		externalServicesByID[s.ID] = s
+	}
+
+	usernames := map[int32]string{}
+	for _, i := range introductions {
+		if i.AuthorUserID == nil {
+			continue
+		}
+		if _, ok := usernames[*i.AuthorUserID]; ok {
+			continue
+		}
+		user, err := db.Users.GetByID(r.Context(), *i.AuthorUserID)
+		if errcode.IsNotFound(err) {
+			usernames[*i.AuthorUserID] = ""
+			continue
+		} else if err != nil {
+			return err
+		}
+		usernames[user.ID] = user.Username
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="repository-introductions.csv"`)
+	return writeRepoIntroductionsCSV(w, introductions, externalServicesByID, usernames)
+}
+
+// repoIntroductionsCSVHeader is the header row of the CSV written by
+// writeRepoIntroductionsCSV.
+var repoIntroductionsCSVHeader = []string{
+	"introduced_at",
+	"repository",
+	"repository_id",
+	"external_service_id",
+	"external_service_kind",
+	"external_service_name",
+	"author_user_id",
+	"author_username",
+}
+
+// writeRepoIntroductionsCSV writes the repository introductions to w as CSV.
+// The external services and usernames of deleted external services and users
+// are empty, as are the authors of introductions whose author is unknown.
+func writeRepoIntroductionsCSV(w io.Writer, introductions []*types.RepoIntroduction, externalServices map[int64]*types.ExternalService, usernames map[int32]string) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(repoIntroductionsCSVHeader); err != nil {
+		return err
+	}
+
+	for _, i := range introductions {
+		var kind, name string
+		if s := externalServices[i.ExternalServiceID]; s != nil {
+			kind, name = s.Kind, s.DisplayName
+		}
+
+		var authorID, username string
+		if i.AuthorUserID != nil {
+			authorID, username = strconv.Itoa(int(*i.AuthorUserID)), usernames[*i.AuthorUserID]
+		}
+
+		err := cw.Write([]string{
+			i.CreatedAt.UTC().Format(time.RFC3339),
+			string(i.RepoName),
+			strconv.Itoa(int(i.RepoID)),
+			strconv.FormatInt(i.ExternalServiceID, 10),
+			kind,
+			name,
+			authorID,
+			username,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
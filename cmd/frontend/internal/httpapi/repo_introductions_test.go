@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestWriteRepoIntroductionsCSV(t *testing.T) {
+	now := time.Date(2019, 12, 1, 10, 30, 0, 0, time.UTC)
+	author := int32(7)
+	deletedAuthor := int32(8)
+
+	introductions := []*types.RepoIntroduction{
+		{RepoID: 2, RepoName: "github.com/foo/b", ExternalServiceID: 1, AuthorUserID: &author, CreatedAt: now},
+		{RepoID: 1, RepoName: "github.com/foo/a", ExternalServiceID: 2, AuthorUserID: &deletedAuthor, CreatedAt: now.Add(-time.Hour)},
+		{RepoID: 1, RepoName: "github.com/foo/a", ExternalServiceID: 1, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	externalServices := map[int64]*types.ExternalService{
+		1: {ID: 1, Kind: "GITHUB", DisplayName: "GitHub, public"},
+	}
+	usernames := map[int32]string{author: "alice", deletedAuthor: ""}
+
+	var buf bytes.Buffer
+	if err := writeRepoIntroductionsCSV(&buf, introductions, externalServices, usernames); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `introduced_at,repository,repository_id,external_service_id,external_service_kind,external_service_name,author_user_id,author_username
+2019-12-01T10:30:00Z,github.com/foo/b,2,1,GITHUB,"GitHub, public",7,alice
+2019-12-01T09:30:00Z,github.com/foo/a,1,2,,,8,
+2019-12-01T08:30:00Z,github.com/foo/a,1,1,GITHUB,"GitHub, public",,
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got CSV\n%s\nwant\n%s", got, want)
+	}
+}
@@ -17,6 +17,12 @@ const (
 	RepoRefresh = "repo.refresh"
 	Telemetry   = "telemetry"
 
+	SearchJobResults = "search-jobs.results"
+	SearchStream     = "search.stream"
+	SavedSearchFeed  = "saved-searches.feed"
+
+	RepoIntroductions = "repository-introductions"
+
 	GitHubWebhooks = "github.webhooks"
 
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
@@ -24,16 +30,21 @@ const (
 	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
 	SavedQueriesDeleteInfo = "internal.saved-queries.delete-info"
 	SettingsGetForSubject  = "internal.settings.get-for-subject"
+	SettingsWatchSubject   = "internal.settings.watch-subject"
 	OrgsListUsers          = "internal.orgs.list-users"
 	OrgsGetByName          = "internal.orgs.get-by-name"
 	UsersGetByUsername     = "internal.users.get-by-username"
 	UserEmailsGetEmail     = "internal.user-emails.get-email"
+	UserEmailsGetEmails    = "internal.user-emails.get-emails"
 	ExternalURL            = "internal.app-url"
 	CanSendEmail           = "internal.can-send-email"
 	SendEmail              = "internal.send-email"
+	SendNotification       = "internal.send-notification"
 	Extension              = "internal.extension"
 	GitResolveRevision     = "internal.git.resolve-revision"
 	GitTar                 = "internal.git.tar"
+	GitIsAncestor          = "internal.git.is-ancestor"
+	AuthzInvalidatePerms   = "internal.authz.invalidate-permissions"
 	PhabricatorRepoCreate  = "internal.phabricator.repo.create"
 	ReposCreateIfNotExists = "internal.repos.create-if-not-exists"
 	ReposGetByName         = "internal.repos.get-by-name"
@@ -41,6 +52,8 @@ const (
 	ReposInventory         = "internal.repos.inventory"
 	ReposList              = "internal.repos.list"
 	ReposListEnabled       = "internal.repos.list-enabled"
+	ReposSetKVP            = "internal.repos.set-kvp"
+	ReposUpdateLastCommit  = "internal.repos.update-last-commit"
 	ReposUpdateMetadata    = "internal.repos.update-metadata"
 	Configuration          = "internal.configuration"
 	SearchConfiguration    = "internal.search-configuration"
@@ -63,6 +76,10 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/lsif/{rest:.*}").Methods("POST").Name(LSIF)
+	base.Path("/search-jobs/{SearchJobID:[0-9]+}/results").Methods("GET").Name(SearchJobResults)
+	base.Path("/search/stream").Methods("GET").Name(SearchStream)
+	base.Path("/saved-searches/{SavedSearchID}/feed.atom").Methods("GET").Name(SavedSearchFeed)
+	base.Path("/repository-introductions.csv").Methods("GET").Name(RepoIntroductions)
 
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
@@ -89,16 +106,21 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/saved-queries/set-info").Methods("POST").Name(SavedQueriesSetInfo)
 	base.Path("/saved-queries/delete-info").Methods("POST").Name(SavedQueriesDeleteInfo)
 	base.Path("/settings/get-for-subject").Methods("POST").Name(SettingsGetForSubject)
+	base.Path("/settings/watch-subject").Methods("POST").Name(SettingsWatchSubject)
 	base.Path("/orgs/list-users").Methods("POST").Name(OrgsListUsers)
 	base.Path("/orgs/get-by-name").Methods("POST").Name(OrgsGetByName)
 	base.Path("/users/get-by-username").Methods("POST").Name(UsersGetByUsername)
 	base.Path("/user-emails/get-email").Methods("POST").Name(UserEmailsGetEmail)
+	base.Path("/user-emails/get-emails").Methods("POST").Name(UserEmailsGetEmails)
 	base.Path("/app-url").Methods("POST").Name(ExternalURL)
 	base.Path("/can-send-email").Methods("POST").Name(CanSendEmail)
 	base.Path("/send-email").Methods("POST").Name(SendEmail)
+	base.Path("/send-notification").Methods("POST").Name(SendNotification)
 	base.Path("/extension").Methods("POST").Name(Extension)
 	base.Path("/git/{RepoName:.*}/resolve-revision/{Spec}").Methods("GET").Name(GitResolveRevision)
 	base.Path("/git/{RepoName:.*}/tar/{Commit}").Methods("GET").Name(GitTar)
+	base.Path("/git/is-ancestor").Methods("POST").Name(GitIsAncestor)
+	base.Path("/authz/invalidate-permissions").Methods("POST").Name(AuthzInvalidatePerms)
 	base.Path("/phabricator/repo-create").Methods("POST").Name(PhabricatorRepoCreate)
 	base.Path("/external-services/configs").Methods("POST").Name(ExternalServiceConfigs)
 	base.Path("/external-services/list").Methods("POST").Name(ExternalServicesList)
@@ -108,6 +130,8 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/repos/list").Methods("POST").Name(ReposList)
 	base.Path("/repos/list-enabled").Methods("POST").Name(ReposListEnabled)
 	base.Path("/repos/update-metadata").Methods("POST").Name(ReposUpdateMetadata)
+	base.Path("/repos/set-kvp").Methods("POST").Name(ReposSetKVP)
+	base.Path("/repos/update-last-commit").Methods("POST").Name(ReposUpdateLastCommit)
 	base.Path("/repos/{RepoName:.*}").Methods("POST").Name(ReposGetByName)
 	base.Path("/configuration").Methods("POST").Name(Configuration)
 	base.Path("/search/configuration").Methods("GET").Name(SearchConfiguration)
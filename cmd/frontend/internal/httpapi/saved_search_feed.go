@@ -0,0 +1,256 @@
+package httpapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// savedSearchFeedQuery is the GraphQL query that runs a saved search for its
+// feed. It requests the same results as the query-runner, which sends the
+// saved search's notifications.
+const savedSearchFeedQuery = `query SavedSearchFeed($query: String!) {
+	search(query: $query) {
+		results {
+			results {
+				__typename
+				... on FileMatch {
+					repository { name }
+					file {
+						path
+						url
+						commit { author { person { displayName } date } }
+					}
+					lineMatches { preview lineNumber }
+				}
+				... on CommitSearchResult {
+					messagePreview { value }
+					diffPreview { value }
+					commit {
+						repository { name }
+						subject
+						canonicalURL
+						author { person { displayName } date }
+					}
+				}
+			}
+		}
+	}
+}`
+
+type savedSearchFeedResults struct {
+	Search struct {
+		Results struct {
+			Results []struct {
+				Typename   string `json:"__typename"`
+				Repository struct{ Name string }
+				File       struct {
+					Path   string
+					URL    string
+					Commit struct{ Author feedSignature }
+				}
+				LineMatches []struct {
+					Preview    string
+					LineNumber int
+				}
+				MessagePreview *struct{ Value string }
+				DiffPreview    *struct{ Value string }
+				Commit         struct {
+					Repository   struct{ Name string }
+					Subject      string
+					CanonicalURL string
+					Author       feedSignature
+				}
+			}
+		}
+	}
+}
+
+type feedSignature struct {
+	Person struct{ DisplayName string }
+	Date   time.Time
+}
+
+// atomFeed is an Atom feed (RFC 4287).
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// serveSavedSearchFeed serves the results of a saved search as an Atom feed,
+// so that they can be followed in feed readers and chat integrations. Each
+// commit or diff result is an entry whose ID is the commit's URL, so entries
+// are stable across requests. Each file match is an entry for the file, which
+// is updated when the file's latest commit changes.
+//
+// Feed readers that send If-Modified-Since are told when there are no new
+// entries, without the feed being sent again.
+func serveSavedSearchFeed(schema *graphql.Schema) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		// 🚨 SECURITY: Saved search feeds are only available to authenticated users (e.g. with an
+		// access token), and the saved search is run as the user.
+		if !actor.FromContext(r.Context()).IsAuthenticated() {
+			return &errcode.HTTPErr{Status: http.StatusUnauthorized, Err: backend.ErrNotAuthenticated}
+		}
+
+		// The saved search is identified by its GraphQL ID, which is shown in the URL of its
+		// edit page.
+		var id int32
+		if err := relay.UnmarshalSpec(graphql.ID(mux.Vars(r)["SavedSearchID"]), &id); err != nil {
+			return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+		}
+		ss, err := db.SavedSearches.GetByID(r.Context(), id)
+		if err == sql.ErrNoRows {
+			return &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.New("saved search not found")}
+		} else if err != nil {
+			return err
+		}
+
+		// 🚨 SECURITY: Make sure the current user has permission to get the saved search.
+		if ss.Config.UserID != nil {
+			err = backend.CheckSiteAdminOrSameUser(r.Context(), *ss.Config.UserID)
+		} else if ss.Config.OrgID != nil {
+			err = backend.CheckOrgAccess(r.Context(), *ss.Config.OrgID)
+		} else {
+			err = errors.New("no Org ID or User ID associated with saved search")
+		}
+		if err != nil {
+			return &errcode.HTTPErr{Status: http.StatusNotFound, Err: errors.New("saved search not found")}
+		}
+
+		res := schema.Exec(r.Context(), savedSearchFeedQuery, "SavedSearchFeed", map[string]interface{}{"query": ss.Config.Query})
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("running saved search: %v", res.Errors)
+		}
+		var results savedSearchFeedResults
+		if err := json.Unmarshal(res.Data, &results); err != nil {
+			return err
+		}
+
+		feed := newSavedSearchFeed(globals.ExternalURL(), r.URL.Path, ss.Config.Description, &results)
+		if len(feed.Entries) > 0 {
+			updated, _ := time.Parse(time.RFC3339, feed.Updated)
+			if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !updated.After(since) {
+				w.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+			w.Header().Set("Last-Modified", updated.Format(http.TimeFormat))
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(feed)
+	}
+}
+
+// newSavedSearchFeed returns the Atom feed for the search results, newest
+// entry first. The feed is updated when its newest entry is.
+func newSavedSearchFeed(externalURL *url.URL, path, title string, results *savedSearchFeedResults) *atomFeed {
+	absURL := func(u string) string {
+		ref, err := url.Parse(u)
+		if err != nil {
+			return u
+		}
+		return externalURL.ResolveReference(ref).String()
+	}
+
+	type entry struct {
+		atomEntry
+		updated time.Time
+	}
+	var entries []entry
+	for _, result := range results.Search.Results.Results {
+		switch result.Typename {
+		case "CommitSearchResult":
+			e := entry{updated: result.Commit.Author.Date}
+			e.ID = absURL(result.Commit.CanonicalURL)
+			e.Title = result.Commit.Repository.Name + ": " + result.Commit.Subject
+			e.Author.Name = result.Commit.Author.Person.DisplayName
+			e.Link.Href = e.ID
+			e.Content.Type = "text"
+			if result.DiffPreview != nil {
+				e.Content.Body = result.DiffPreview.Value
+			} else if result.MessagePreview != nil {
+				e.Content.Body = result.MessagePreview.Value
+			}
+			entries = append(entries, e)
+
+		case "FileMatch":
+			e := entry{updated: result.File.Commit.Author.Date}
+			e.ID = absURL(result.File.URL)
+			e.Title = result.Repository.Name + ": " + result.File.Path
+			e.Author.Name = result.File.Commit.Author.Person.DisplayName
+			e.Link.Href = e.ID
+			e.Content.Type = "text"
+			var lines []string
+			for _, m := range result.LineMatches {
+				lines = append(lines, fmt.Sprintf("%d: %s", m.LineNumber+1, m.Preview))
+			}
+			e.Content.Body = strings.Join(lines, "\n")
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].updated.After(entries[j].updated) })
+
+	feed := &atomFeed{
+		ID:    absURL(path),
+		Title: title,
+		Link:  atomLink{Rel: "self", Href: absURL(path)},
+	}
+	var updated time.Time
+	for _, e := range entries {
+		e.Updated = e.updated.UTC().Format(time.RFC3339)
+		feed.Entries = append(feed.Entries, e.atomEntry)
+		if e.updated.After(updated) {
+			updated = e.updated
+		}
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+	return feed
+}
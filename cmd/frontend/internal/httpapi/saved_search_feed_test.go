@@ -0,0 +1,59 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestNewSavedSearchFeed(t *testing.T) {
+	var results savedSearchFeedResults
+	if err := json.Unmarshal([]byte(`{"search": {"results": {"results": [
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "r"},
+			"file": {"path": "a.go", "url": "/r/-/blob/a.go", "commit": {"author": {"person": {"displayName": "alice"}, "date": "2019-01-01T00:00:00Z"}}},
+			"lineMatches": [{"preview": "foo()", "lineNumber": 3}]
+		},
+		{
+			"__typename": "CommitSearchResult",
+			"diffPreview": {"value": "+foo()"},
+			"commit": {"repository": {"name": "r"}, "subject": "add foo", "canonicalURL": "/r/-/commit/c1", "author": {"person": {"displayName": "bob"}, "date": "2019-02-01T00:00:00Z"}}
+		},
+		{"__typename": "Repository"}
+	]}}}`), &results); err != nil {
+		t.Fatal(err)
+	}
+
+	externalURL := &url.URL{Scheme: "https", Host: "sourcegraph.example.com"}
+	feed := newSavedSearchFeed(externalURL, "/.api/saved-searches/1/feed.atom", "foo", &results)
+
+	want := &atomFeed{
+		ID:      "https://sourcegraph.example.com/.api/saved-searches/1/feed.atom",
+		Title:   "foo",
+		Updated: "2019-02-01T00:00:00Z",
+		Link:    atomLink{Rel: "self", Href: "https://sourcegraph.example.com/.api/saved-searches/1/feed.atom"},
+		Entries: []atomEntry{
+			{
+				ID:      "https://sourcegraph.example.com/r/-/commit/c1",
+				Title:   "r: add foo",
+				Updated: "2019-02-01T00:00:00Z",
+				Author:  atomAuthor{Name: "bob"},
+				Link:    atomLink{Href: "https://sourcegraph.example.com/r/-/commit/c1"},
+				Content: atomContent{Type: "text", Body: "+foo()"},
+			},
+			{
+				ID:      "https://sourcegraph.example.com/r/-/blob/a.go",
+				Title:   "r: a.go",
+				Updated: "2019-01-01T00:00:00Z",
+				Author:  atomAuthor{Name: "alice"},
+				Link:    atomLink{Href: "https://sourcegraph.example.com/r/-/blob/a.go"},
+				Content: atomContent{Type: "text", Body: "4: foo()"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(feed, want) {
+		t.Errorf("got feed %+v, want %+v", feed, want)
+	}
+}
@@ -0,0 +1,35 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// serveSearchJobResults writes the results that a search job found so far,
+// as newline-delimited JSON objects.
+func serveSearchJobResults(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(mux.Vars(r)["SearchJobID"], 10, 32)
+	if err != nil {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	job, err := db.SearchJobs.GetByID(r.Context(), int32(id))
+	if err == db.ErrSearchJobNotFound {
+		return &errcode.HTTPErr{Status: http.StatusNotFound, Err: err}
+	} else if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Only the user who created the search job and site admins may download its
+	// results.
+	if err := backend.CheckSiteAdminOrSameUser(r.Context(), job.UserID); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	return db.SearchJobs.WriteResults(r.Context(), job.ID, w)
+}
@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// serveSearchStream runs the search given by the "q" (and optional
+// "patternType") query parameters and streams its results to the client as
+// server-sent events while they are found (see graphqlbackend.StreamSearch).
+func serveSearchStream(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: errors.New("missing query parameter q")}
+	}
+
+	ew := newEventStreamWriter(w)
+	err := graphqlbackend.StreamSearch(r.Context(), q, r.URL.Query().Get("patternType"), ew.send)
+	if err == nil {
+		return nil
+	}
+	if !ew.started {
+		if errcode.IsBadRequest(err) {
+			return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+		}
+		return err
+	}
+
+	// The response status was already sent, so the error is reported as an
+	// event (unless the client went away).
+	if r.Context().Err() == nil {
+		_ = ew.send("error", map[string]string{"message": err.Error()})
+	}
+	return nil
+}
+
+// eventStreamWriter writes server-sent events
+// (https://html.spec.whatwg.org/multipage/server-sent-events.html) with JSON
+// data, flushing each event.
+type eventStreamWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher // nil if w doesn't support flushing
+
+	started bool // whether the response header was written
+}
+
+func newEventStreamWriter(w http.ResponseWriter) *eventStreamWriter {
+	flusher, _ := w.(http.Flusher)
+	return &eventStreamWriter{w: w, flusher: flusher}
+}
+
+func (ew *eventStreamWriter) send(event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if !ew.started {
+		ew.w.Header().Set("Content-Type", "text/event-stream")
+		ew.w.Header().Set("Cache-Control", "no-cache")
+		// Keep proxies (e.g. nginx) from buffering the events.
+		ew.w.Header().Set("X-Accel-Buffering", "no")
+		ew.w.WriteHeader(http.StatusOK)
+		ew.started = true
+	}
+
+	if _, err := fmt.Fprintf(ew.w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		return err
+	}
+	if ew.flusher != nil {
+		ew.flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package httpapi
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEventStreamWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ew := newEventStreamWriter(rec)
+
+	if err := ew.send("results", []map[string]string{{"type": "repository", "repository": "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.send("done", map[string]int{"matchCount": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := rec.Header().Get("Content-Type"), "text/event-stream"; got != want {
+		t.Errorf("got Content-Type %q, want %q", got, want)
+	}
+	if !rec.Flushed {
+		t.Error("events were not flushed")
+	}
+	want := "event: results\ndata: [{\"repository\":\"a\",\"type\":\"repository\"}]\n\n" +
+		"event: done\ndata: {\"matchCount\":1}\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got body %q, want %q", got, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/src-d/enry/v2"
 	"github.com/src-d/enry/v2/data"
@@ -55,6 +56,10 @@ func detect(ctx context.Context, file os.FileInfo, readFile func(ctx context.Con
 // GetLanguageByFilename returns the guessed language for the named file (and safe == true if this
 // is very likely to be correct).
 func GetLanguageByFilename(name string) (language string, safe bool) {
+	// Well-known filenames such as Makefile or Dockerfile are conclusive.
+	if language, safe = enry.GetLanguageByFilename(name); safe {
+		return language, safe
+	}
 	language, safe = enry.GetLanguageByExtension(name)
 	if language == "GCC Machine Description" && filepath.Ext(name) == ".md" {
 		language = "Markdown" // override detection for .md
@@ -62,6 +67,82 @@ func GetLanguageByFilename(name string) (language string, safe bool) {
 	return language, safe
 }
 
+// GetLanguage returns the guessed language for the named file, using (a prefix of) its content to
+// resolve ambiguous extensions (e.g., .h for C, C++ and Objective-C) and files without one (e.g.,
+// scripts with a shebang). If content is empty, it is equivalent to GetLanguageByFilename.
+//
+// Unlike enry.GetLanguage, it never falls back to the statistical classifier, which is unreliable
+// for partial content. It returns safe == true only if the language was conclusively detected.
+func GetLanguage(name string, content []byte) (language string, safe bool) {
+	language, safe = GetLanguageByFilename(name)
+	if safe || len(content) == 0 {
+		return language, safe
+	}
+
+	for _, strategy := range []enry.Strategy{enry.GetLanguagesByModeline, enry.GetLanguagesByShebang} {
+		if languages := strategy(name, content, nil); len(languages) == 1 {
+			return languages[0], true
+		}
+	}
+
+	// The content heuristics only know how to disambiguate some extensions, and only return a
+	// language if one of their rules matched.
+	candidates := enry.GetLanguagesByExtension(name, nil, nil)
+	if len(candidates) > 1 {
+		if languages := enry.GetLanguagesByContent(name, content, candidates); len(languages) == 1 {
+			return languages[0], true
+		}
+	}
+	return language, safe
+}
+
+// GetLanguageFilenames returns the well-known filenames (such as "Makefile") of the given
+// language, as opposed to its extensions returned by enry.GetLanguageExtensions.
+func GetLanguageFilenames(language string) []string {
+	return filenamesByLanguage[language]
+}
+
+// filenamesByLanguage is the inverse of data.LanguagesByFilename for filenames that belong to a
+// single language.
+var filenamesByLanguage = func() map[string][]string {
+	m := map[string][]string{}
+	for filename, languages := range data.LanguagesByFilename {
+		if len(languages) == 1 {
+			m[languages[0]] = append(m[languages[0]], filename)
+		}
+	}
+	for _, filenames := range m {
+		sort.Strings(filenames)
+	}
+	return m
+}()
+
+// zoektLanguages maps the names of languages detected by ctags during indexing, as reported in
+// lowercase by zoekt, to the language names used by enry (and thus lang: filters) where they
+// differ by more than case.
+var zoektLanguages = map[string]string{
+	"asm":           "Assembly",
+	"dosbatch":      "Batchfile",
+	"emacslisp":     "Emacs Lisp",
+	"make":          "Makefile",
+	"matlab":        "MATLAB",
+	"objectivec":    "Objective-C",
+	"ocaml":         "OCaml",
+	"sh":            "Shell",
+	"systemverilog": "SystemVerilog",
+	"vim":           "Vim script",
+}
+
+// GetLanguageByZoektLanguage returns the enry language name for a language name reported by zoekt
+// for an indexed file, or the empty string if it is unknown.
+func GetLanguageByZoektLanguage(zoektLanguage string) string {
+	if language, ok := zoektLanguages[zoektLanguage]; ok {
+		return language
+	}
+	language, _ := enry.GetLanguageByAlias(zoektLanguage)
+	return language
+}
+
 func init() {
 	// Treat .tsx and .jsx as TypeScript and JavaScript, respectively, instead of distinct languages
 	// called "TSX" and "JSX". This is more consistent with user expectations.
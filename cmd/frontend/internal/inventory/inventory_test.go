@@ -112,6 +112,53 @@ func TestGet_readFile(t *testing.T) {
 	}
 }
 
+func TestGetLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     string
+		wantSafe bool
+	}{
+		{name: "a.go", want: "Go", wantSafe: true},
+		{name: "Makefile", want: "Makefile", wantSafe: true},
+		{name: "a/b/Dockerfile", want: "Dockerfile", wantSafe: true},
+		{name: "LICENSE"},
+
+		// Content is only used if the file name isn't conclusive.
+		{name: "a.go", content: "#!/usr/bin/env python", want: "Go", wantSafe: true},
+		{name: "script", content: "#!/usr/bin/env python\nprint(1)", want: "Python", wantSafe: true},
+		{name: "script", content: "print(1)"},
+
+		// The .h extension is shared by C, C++ and Objective-C.
+		{name: "a.h", content: "int f(void);", want: "C"},
+		{name: "a.h", content: "namespace foo {\n}", want: "C++", wantSafe: true},
+		{name: "a.h", content: "@interface Foo : NSObject", want: "Objective-C", wantSafe: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name+" "+test.content, func(t *testing.T) {
+			lang, safe := GetLanguage(test.name, []byte(test.content))
+			if lang != test.want || safe != test.wantSafe {
+				t.Errorf("got %q (safe=%v), want %q (safe=%v)", lang, safe, test.want, test.wantSafe)
+			}
+		})
+	}
+}
+
+func TestGetLanguageByZoektLanguage(t *testing.T) {
+	for zoektLang, want := range map[string]string{
+		"go":         "Go",
+		"c++":        "C++",
+		"sh":         "Shell",
+		"objectivec": "Objective-C",
+		"binary":     "",
+		"":           "",
+	} {
+		if got := GetLanguageByZoektLanguage(zoektLang); got != want {
+			t.Errorf("%q: got %q, want %q", zoektLang, got, want)
+		}
+	}
+}
+
 func BenchmarkGet(b *testing.B) {
 	files, err := readFileTree("prom-repo-tree.txt")
 	if err != nil {
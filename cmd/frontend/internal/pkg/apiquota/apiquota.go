@@ -0,0 +1,154 @@
+// Package apiquota implements quotas on the GraphQL API usage of users,
+// access tokens and anonymous clients, as configured in the "api.quota" site
+// configuration.
+package apiquota
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// pool is the Redis pool that the points used in the current window of each
+// quota are stored in.
+var pool = redispool.Store
+
+// timeNow is mocked in tests.
+var timeNow = time.Now
+
+const defaultWindow = time.Hour
+
+// Status is the state of a quota after points were taken from it.
+type Status struct {
+	// Limit is the number of points per window.
+	Limit int
+	// Remaining is the number of points left in the current window. It is
+	// negative if more points were taken than were left.
+	Remaining int
+	// Reset is when the current window ends.
+	Reset time.Time
+}
+
+// Exceeded reports whether more points were taken from the quota than were
+// left.
+func (s *Status) Exceeded() bool {
+	return s.Remaining < 0
+}
+
+type accessTokenKey struct{}
+
+// WithAccessToken returns a context that records that the request was
+// authenticated with the given access token, whose quota is separate from
+// that of its user.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	sum := sha256.Sum256([]byte(token))
+	return context.WithValue(ctx, accessTokenKey{}, hex.EncodeToString(sum[:8]))
+}
+
+type statusKey struct{}
+
+// WithStatus returns a context that carries the quota status of the request
+// (see FromContext).
+func WithStatus(ctx context.Context, s *Status) context.Context {
+	return context.WithValue(ctx, statusKey{}, s)
+}
+
+// FromContext returns the quota status of the request, as of after its cost
+// was taken from the quota, or nil if quotas are disabled.
+func FromContext(ctx context.Context) *Status {
+	s, _ := ctx.Value(statusKey{}).(*Status)
+	return s
+}
+
+// Take takes cost points from the quota of the access token, user or
+// anonymous client that made the request. It returns a nil status if quotas
+// are disabled.
+func Take(r *http.Request, cost int) (*Status, error) {
+	c := conf.Get().ApiQuota
+	if c == nil {
+		return nil, nil
+	}
+
+	subject, anonymous := requestSubject(r, c.AnonymousClientIPHeader)
+	return take(c, subject, anonymous, cost)
+}
+
+func take(c *schema.APIQuota, subject string, anonymous bool, cost int) (*Status, error) {
+	limit := c.Points
+	if anonymous && c.AnonymousPoints > 0 {
+		limit = c.AnonymousPoints
+	}
+	window := defaultWindow
+	if c.Window != "" {
+		d, err := time.ParseDuration(c.Window)
+		if err != nil {
+			return nil, fmt.Errorf("invalid api.quota window: %s", err)
+		}
+		window = d
+	}
+
+	// Quotas use fixed windows, so all points of a window are counted by a
+	// single key that expires with the window.
+	start := timeNow().Truncate(window)
+	key := fmt.Sprintf("api_quota:%s:%d", subject, start.Unix())
+
+	conn := pool.Get()
+	defer conn.Close()
+
+	used, err := redis.Int(conn.Do("INCRBY", key, cost))
+	if err != nil {
+		return nil, err
+	}
+	if used == cost {
+		if _, err := conn.Do("EXPIRE", key, int(window/time.Second)+1); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Status{
+		Limit:     limit,
+		Remaining: limit - used,
+		Reset:     start.Add(window),
+	}, nil
+}
+
+// requestSubject returns the key of the quota that the request counts
+// against, and whether it was made by an anonymous client. Anonymous clients
+// are identified by the last address in clientIPHeader, if set, and
+// otherwise by the remote address of the request.
+func requestSubject(r *http.Request, clientIPHeader string) (subject string, anonymous bool) {
+	if token, ok := r.Context().Value(accessTokenKey{}).(string); ok {
+		return "token:" + token, false
+	}
+	if a := actor.FromContext(r.Context()); a.IsAuthenticated() {
+		return "user:" + a.UIDString(), false
+	}
+
+	if clientIPHeader != "" {
+		// The trusted proxy appends the address it received the request
+		// from, so only the last address can't be set by the client.
+		if v := r.Header.Get(clientIPHeader); v != "" {
+			addrs := strings.Split(v, ",")
+			if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+				return "anonymous:" + ip, true
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "anonymous:" + host, true
+}
@@ -0,0 +1,107 @@
+package apiquota
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestTake(t *testing.T) {
+	pool = &redis.Pool{
+		MaxIdle: 3,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+	c := pool.Get()
+	defer c.Close()
+
+	// If we are not on CI, skip the test if our redis connection fails.
+	if os.Getenv("CI") == "" {
+		if _, err := c.Do("PING"); err != nil {
+			t.Skip("could not connect to redis", err)
+		}
+	}
+
+	now := time.Date(2019, 11, 1, 10, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	subject := "__test__" + t.Name()
+	for _, start := range []time.Time{now.Truncate(time.Hour), now.Truncate(time.Hour).Add(time.Hour)} {
+		if _, err := c.Do("DEL", fmt.Sprintf("api_quota:%s:%d", subject, start.Unix())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conf := &schema.APIQuota{Points: 3, AnonymousPoints: 1, Window: "1h"}
+
+	for i, want := range []int{2, 0, -2} {
+		cost := 1
+		if i == 2 {
+			cost = 2
+		}
+		s, err := take(conf, subject, false, cost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Remaining != want || s.Limit != 3 {
+			t.Errorf("take %d: got %d/%d points remaining, want %d/3", i, s.Remaining, s.Limit, want)
+		}
+		if s.Exceeded() != (want < 0) {
+			t.Errorf("take %d: got exceeded %t", i, s.Exceeded())
+		}
+		if wantReset := time.Date(2019, 11, 1, 11, 0, 0, 0, time.UTC); !s.Reset.Equal(wantReset) {
+			t.Errorf("take %d: got reset %s, want %s", i, s.Reset, wantReset)
+		}
+	}
+
+	// Anonymous clients have their own limit, and quotas reset with each window.
+	now = now.Add(time.Hour)
+	s, err := take(conf, subject, true, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Remaining != 0 || s.Limit != 1 {
+		t.Errorf("got %d/%d points remaining in next window, want 0/1", s.Remaining, s.Limit)
+	}
+}
+
+func TestRequestSubject(t *testing.T) {
+	r, err := http.NewRequest("POST", "/.api/graphql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.RemoteAddr = "10.0.0.1:1234"
+
+	r.Header.Set("X-Forwarded-For", "192.168.0.1, 172.16.0.1")
+
+	user := r.WithContext(actor.WithActor(context.Background(), actor.FromUser(42)))
+	token := user.WithContext(WithAccessToken(user.Context(), "secret"))
+
+	for _, tc := range []struct {
+		name           string
+		r              *http.Request
+		clientIPHeader string
+		subject        string
+		anonymous      bool
+	}{
+		{"anonymous", r, "", "anonymous:10.0.0.1", true},
+		{"anonymous forwarded", r, "X-Forwarded-For", "anonymous:172.16.0.1", true},
+		{"anonymous missing header", r, "X-Real-IP", "anonymous:10.0.0.1", true},
+		{"user", user, "X-Forwarded-For", "user:42", false},
+		{"token", token, "", "token:2bb80d537b1da3e3", false},
+	} {
+		subject, anonymous := requestSubject(tc.r, tc.clientIPHeader)
+		if subject != tc.subject || anonymous != tc.anonymous {
+			t.Errorf("%s: got subject %q (anonymous %t), want %q (anonymous %t)", tc.name, subject, anonymous, tc.subject, tc.anonymous)
+		}
+	}
+}
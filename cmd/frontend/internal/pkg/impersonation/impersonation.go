@@ -0,0 +1,81 @@
+// Package impersonation lets site admins make their GraphQL requests evaluate
+// repository permissions and settings as another user, to debug what that
+// user can see (e.g. in search results).
+package impersonation
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Duration is how long an impersonation lasts unless it is stopped earlier.
+const Duration = time.Hour
+
+// impersonations maps the ID of each site admin that is impersonating a user
+// to the ID of that user.
+var impersonations = rcache.NewWithTTL("impersonation", int(Duration/time.Second))
+
+type impersonatorKey struct{}
+
+// Start makes the subsequent GraphQL requests of the site admin with the ID
+// adminID act as the user with the ID userID, until Stop is called or
+// Duration passes. Mutations other than stopping the impersonation are
+// rejected for these requests (see package httpapi).
+//
+// 🚨 SECURITY: Callers must check that adminID is the ID of a site admin.
+func Start(adminID, userID int32) {
+	impersonations.Set(strconv.Itoa(int(adminID)), []byte(strconv.Itoa(int(userID))))
+	log15.Warn("Site admin started impersonating user.", "admin", adminID, "user", userID, "duration", Duration)
+}
+
+// Stop stops the impersonation started by the site admin with the ID adminID,
+// if any.
+func Stop(adminID int32) {
+	impersonations.Delete(strconv.Itoa(int(adminID)))
+	log15.Warn("Site admin stopped impersonating user.", "admin", adminID)
+}
+
+// Impersonator returns the ID of the site admin that is impersonating the
+// actor of ctx, or 0 if it is not impersonated.
+func Impersonator(ctx context.Context) int32 {
+	id, _ := ctx.Value(impersonatorKey{}).(int32)
+	return id
+}
+
+// Apply returns a copy of ctx whose actor is the user that the actor of ctx
+// is impersonating, if any. Otherwise it returns ctx unchanged.
+func Apply(ctx context.Context) (context.Context, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() || a.Internal {
+		return ctx, nil
+	}
+
+	b, ok := impersonations.Get(a.UIDString())
+	if !ok {
+		return ctx, nil
+	}
+	userID, err := strconv.ParseInt(string(b), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: The impersonator may have been demoted from site admin
+	// since it started impersonating the user.
+	admin, err := db.Users.GetByID(ctx, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin.SiteAdmin {
+		Stop(a.UID)
+		return ctx, nil
+	}
+
+	ctx = actor.WithActor(ctx, &actor.Actor{UID: int32(userID), FromSessionCookie: a.FromSessionCookie})
+	return context.WithValue(ctx, impersonatorKey{}, a.UID), nil
+}
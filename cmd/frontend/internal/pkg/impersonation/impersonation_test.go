@@ -0,0 +1,58 @@
+package impersonation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+)
+
+func TestApply(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	siteAdmin := true
+	db.Mocks.Users.GetByID = func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, SiteAdmin: siteAdmin}, nil
+	}
+	defer func() { db.Mocks.Users.GetByID = nil }()
+
+	apply := func(a *actor.Actor) (uid, impersonator int32) {
+		ctx, err := Apply(actor.WithActor(context.Background(), a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return actor.FromContext(ctx).UID, Impersonator(ctx)
+	}
+
+	if uid, impersonator := apply(actor.FromUser(1)); uid != 1 || impersonator != 0 {
+		t.Errorf("got actor %d impersonated by %d before impersonation started, want 1 and 0", uid, impersonator)
+	}
+
+	Start(1, 2)
+	if uid, impersonator := apply(actor.FromUser(1)); uid != 2 || impersonator != 1 {
+		t.Errorf("got actor %d impersonated by %d, want 2 and 1", uid, impersonator)
+	}
+	// Other users are unaffected.
+	if uid, impersonator := apply(actor.FromUser(3)); uid != 3 || impersonator != 0 {
+		t.Errorf("got actor %d impersonated by %d for other user, want 3 and 0", uid, impersonator)
+	}
+
+	// Site admins that were demoted stop impersonating users.
+	siteAdmin = false
+	if uid, impersonator := apply(actor.FromUser(1)); uid != 1 || impersonator != 0 {
+		t.Errorf("got actor %d impersonated by %d after demotion, want 1 and 0", uid, impersonator)
+	}
+	siteAdmin = true
+	if uid, _ := apply(actor.FromUser(1)); uid != 1 {
+		t.Errorf("got actor %d after promotion, want impersonation to have stopped", uid)
+	}
+
+	Start(1, 2)
+	Stop(1)
+	if uid, impersonator := apply(actor.FromUser(1)); uid != 1 || impersonator != 0 {
+		t.Errorf("got actor %d impersonated by %d after stop, want 1 and 0", uid, impersonator)
+	}
+}
@@ -41,6 +41,55 @@ func ConvertToLiteral(input string) string {
 	return input
 }
 
+// ConvertToFuzzy converts the input query for fuzzy path search. Each
+// whitespace-separated term of the pattern is converted to a regexp matching
+// the paths that contain its characters in order (e.g. "cmdmain" matches
+// "cmd/frontend/main.go"), and quotes in the terms are matched literally.
+func ConvertToFuzzy(input string) string {
+	fields, terms := splitFuzzy(input)
+	pieces := fields
+	for _, term := range terms {
+		pieces = append(pieces, "/"+fuzzyRegexp(term)+"/")
+	}
+	return strings.Join(pieces, " ")
+}
+
+// FuzzyTerms returns the whitespace-separated terms of the pattern of the
+// input query for fuzzy path search, which paths are ranked against.
+func FuzzyTerms(input string) []string {
+	_, terms := splitFuzzy(input)
+	return terms
+}
+
+func splitFuzzy(input string) (fields, terms []string) {
+	for _, t := range strings.Fields(input) {
+		if fieldRx.MatchString(t) {
+			fields = append(fields, t)
+		} else {
+			terms = append(terms, t)
+		}
+	}
+	return fields, terms
+}
+
+// fuzzyRegexp returns the regexp matching the strings that contain the
+// characters of term in order. It's used as a /.../ pattern in queries, so
+// slashes are escaped too.
+func fuzzyRegexp(term string) string {
+	var b strings.Builder
+	for i, r := range term {
+		if i > 0 {
+			b.WriteString(".*?")
+		}
+		if r == '/' {
+			b.WriteString(`\/`)
+		} else {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
 var tokenRx = regexp.MustCompile(`("([^"\\]|[\\].)*"|\s+|\S+)`)
 
 // tokenize returns a slice of the double-quoted strings, contiguous chunks
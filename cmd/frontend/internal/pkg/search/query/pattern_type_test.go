@@ -55,6 +55,36 @@ func TestConvertToLiteral(t *testing.T) {
 	}
 }
 
+func TestConvertToFuzzy(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{" ", ""},
+		{`a`, `/a/`},
+		{`ab`, `/a.*?b/`},
+		{` ab  c `, `/a.*?b/ /c/`},
+		{`a.go`, `/a.*?\..*?g.*?o/`},
+		{`a/b`, `/a.*?\/.*?b/`},
+		{`"a`, `/".*?a/`},
+		{`repo:r ab`, `repo:r /a.*?b/`},
+		{`ab -file:_test type:path`, `-file:_test type:path /a.*?b/`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			out := ConvertToFuzzy(test.input)
+			if out != test.want {
+				t.Errorf("ConvertToFuzzy (%q) = %q, want %q", test.input, out, test.want)
+			}
+			if _, err := ParseAndCheck(out); err != nil {
+				t.Errorf("ParseAndCheck(%q) failed: %s", out, err)
+			}
+		})
+	}
+}
+
 func TestTokenize(t *testing.T) {
 	tests := []struct {
 		input string
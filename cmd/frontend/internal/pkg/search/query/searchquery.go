@@ -16,8 +16,10 @@ const (
 	FieldRepo               = "repo"
 	FieldRepoGroup          = "repogroup"
 	FieldFile               = "file"
+	FieldContent            = "content"
 	FieldFork               = "fork"
 	FieldArchived           = "archived"
+	FieldArchivedMirror     = "archived-mirror"
 	FieldLang               = "lang"
 	FieldType               = "type"
 	FieldRepoHasFile        = "repohasfile"
@@ -37,6 +39,22 @@ const (
 	FieldMax     = "max"   // Deprecated alias for count
 	FieldTimeout = "timeout"
 	FieldReplace = "replace"
+
+	FieldDedupForks = "dedupforks"
+
+	// Searches that specify `maxRepos:` search at most that number of
+	// repositories, bounded by the maxReposToSearchCeiling site config.
+	FieldMaxRepos = "maxrepos"
+
+	// Searches that specify `orgexclusions:no` also search the repositories
+	// and paths excluded by the search.exclusions settings of the viewer's
+	// organizations.
+	FieldOrgExclusions = "orgexclusions"
+
+	// Searches that specify `unicode:yes` match text with Unicode case
+	// folding, after normalizing it (see the search.unicodeNormalization
+	// site configuration setting).
+	FieldUnicode = "unicode"
 )
 
 var (
@@ -50,6 +68,7 @@ var (
 			FieldRepo:        regexpNegatableFieldType,
 			FieldRepoGroup:   {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldFile:        regexpNegatableFieldType,
+			FieldContent:     regexpNegatableFieldType,
 			FieldFork:        {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldArchived:    {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldLang:        {Literal: types.StringType, Quoted: types.StringType, Negatable: true},
@@ -58,6 +77,7 @@ var (
 
 			FieldRepoHasFile:        regexpNegatableFieldType,
 			FieldRepoHasCommitAfter: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldArchivedMirror:     {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
 			FieldBefore:    stringFieldType,
 			FieldAfter:     stringFieldType,
@@ -71,6 +91,14 @@ var (
 			FieldMax:     {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldTimeout: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldReplace: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+
+			FieldDedupForks: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+
+			FieldMaxRepos: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+
+			FieldOrgExclusions: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+
+			FieldUnicode: {Literal: types.BoolType, Quoted: types.BoolType, Singular: true},
 		},
 		FieldAliases: map[string]string{
 			"r":        FieldRepo,
@@ -167,6 +195,12 @@ func (q *Query) IsCaseSensitive() bool {
 	return q.BoolValue(FieldCase)
 }
 
+// IsUnicode reports whether the query's expressions are matched with Unicode
+// case folding and normalization.
+func (q *Query) IsUnicode() bool {
+	return q.BoolValue(FieldUnicode)
+}
+
 // Values returns the values for the given field.
 func (q *Query) Values(field string) []*types.Value {
 	if _, ok := q.conf.FieldTypes[field]; !ok {
@@ -147,6 +147,17 @@ func TestQuery_CaseInsensitiveFields(t *testing.T) {
 	}
 }
 
+func TestQuery_ArchivedMirror(t *testing.T) {
+	query, err := ParseAndCheck("archived-mirror:only foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := query.StringValue(FieldArchivedMirror); value != "only" {
+		t.Errorf("unexpected value: want \"only\", got %q", value)
+	}
+}
+
 func checkPanic(t *testing.T, msg string, f func()) {
 	t.Helper()
 	defer func() {
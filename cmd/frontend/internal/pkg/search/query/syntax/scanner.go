@@ -124,9 +124,15 @@ func scanDefault(s *scanner) stateFn {
 	return scanSpace
 }
 
+// hyphenatedFields are the field names that contain a '-'. Other text with a
+// '-' before a ':', such as "Content-Type:", is scanned as a literal.
+var hyphenatedFields = map[string]bool{
+	"archived-mirror": true,
+}
+
 func scanText(s *scanner) stateFn {
 	// Characters that may come before a ':' (TokenColon) in a TokenLiteral.
-	preColonChars := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	preColonChars := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-"
 
 	for {
 		if s.eof() {
@@ -138,6 +144,9 @@ func scanText(s *scanner) stateFn {
 			break
 		}
 		if r == ':' {
+			if field := s.input[s.start:s.prevPos]; strings.ContainsRune(field, '-') && !hyphenatedFields[strings.ToLower(field)] {
+				return scanLiteral
+			}
 			// Start of value.
 			s.backup()
 			s.emit(TokenLiteral)
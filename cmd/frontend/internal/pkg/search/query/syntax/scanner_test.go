@@ -89,6 +89,21 @@ func TestScanner(t *testing.T) {
 	}
 }
 
+func TestScanner_HyphenatedFields(t *testing.T) {
+	tests := map[string][]string{
+		"archived-mirror:yes":  {"archived-mirror", ":", "yes", ""},
+		"Archived-Mirror:only": {"Archived-Mirror", ":", "only", ""},
+		"-archived-mirror:yes": {"-", "archived-mirror", ":", "yes", ""},
+		"Content-Type:json":    {"Content-Type:json", ""},
+		"a-b":                  {"a-b", ""},
+	}
+	for input, want := range tests {
+		if got := tokenValues(Scan(input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("token values: %s\ngot  %q\nwant %q", input, got, want)
+		}
+	}
+}
+
 func tokenTypes(tokens []Token) []TokenType {
 	types := make([]TokenType, len(tokens))
 	for i, t := range tokens {
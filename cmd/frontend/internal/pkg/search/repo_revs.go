@@ -1,6 +1,7 @@
 package search
 
 import (
+	"regexp"
 	"strings"
 	"sync"
 
@@ -35,6 +36,34 @@ func (r1 RevisionSpecifier) String() string {
 	return r1.RevSpec
 }
 
+// Range returns the base and head revspecs of the revision range
+// "base..head" that RevSpec specifies, and ok == false if it doesn't specify
+// one. An omitted base or head refers to HEAD, as in git. Symmetric
+// difference ranges ("base...head") are not supported.
+func (r1 RevisionSpecifier) Range() (base, head string, ok bool) {
+	i := strings.Index(r1.RevSpec, "..")
+	if i == -1 || strings.Contains(r1.RevSpec, "...") {
+		return "", "", false
+	}
+	base, head = r1.RevSpec[:i], r1.RevSpec[i+len(".."):]
+	if base == "" {
+		base = "HEAD"
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+	return base, head, true
+}
+
+// commitIDPattern matches full and abbreviated Git commit IDs.
+var commitIDPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// IsCommitID reports whether RevSpec pins a single commit by its (possibly
+// abbreviated) ID, as in "repo:foo@a1b2c3d".
+func (r1 RevisionSpecifier) IsCommitID() bool {
+	return commitIDPattern.MatchString(r1.RevSpec)
+}
+
 // Less compares two revspecOrRefGlob entities, suitable for use
 // with sort.Slice()
 //
@@ -65,3 +65,43 @@ func TestRepositoryRevisions(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestRevisionSpecifier_Range(t *testing.T) {
+	tests := map[string]struct {
+		base, head string
+		ok         bool
+	}{
+		"rev":       {},
+		"v1..v2":    {base: "v1", head: "v2", ok: true},
+		"v1..":      {base: "v1", head: "HEAD", ok: true},
+		"..v2":      {base: "HEAD", head: "v2", ok: true},
+		"v1...v2":   {},
+		"HEAD~10..": {base: "HEAD~10", head: "HEAD", ok: true},
+	}
+	for revSpec, want := range tests {
+		t.Run(revSpec, func(t *testing.T) {
+			base, head, ok := RevisionSpecifier{RevSpec: revSpec}.Range()
+			if base != want.base || head != want.head || ok != want.ok {
+				t.Errorf("got (%q, %q, %v), want (%q, %q, %v)", base, head, ok, want.base, want.head, want.ok)
+			}
+		})
+	}
+}
+
+func TestRevisionSpecifier_IsCommitID(t *testing.T) {
+	tests := map[string]bool{
+		"":         false,
+		"master":   false,
+		"a1b2c3":   false, // too short
+		"a1b2c3d":  true,
+		"A1B2C3D":  false,
+		"a1b2c3d^": false,
+		"deadbeefdeadbeefdeadbeefdeadbeefdeadbeef":  true,
+		"deadbeefdeadbeefdeadbeefdeadbeefdeadbeef0": false,
+	}
+	for revSpec, want := range tests {
+		if got := (RevisionSpecifier{RevSpec: revSpec}).IsCommitID(); got != want {
+			t.Errorf("%q: got %v, want %v", revSpec, got, want)
+		}
+	}
+}
@@ -7,17 +7,36 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"golang.org/x/text/unicode/norm"
 )
 
 // PatternInfo is the struct used by vscode pass on search queries. Keep it in
 // sync with pkg/searcher/protocol.PatternInfo.
 type PatternInfo struct {
-	Pattern         string
+	Pattern string
+
+	// AndPatterns, if non-empty, are the patterns Pattern was combined from.
+	// Text search matches each of them independently, returning files that
+	// contain matches for all of them (instead of lines that contain matches
+	// for Pattern). Other search types only use Pattern.
+	AndPatterns []string
+
+	// NegatedPatterns are patterns the content of the returned files must
+	// not match (from -content:). They are interpreted the same way as
+	// Pattern. Files excluded by them do not count towards FileMatchLimit,
+	// so a hit limit still means that more files would have been returned.
+	NegatedPatterns []string
+
 	IsRegExp        bool
 	IsWordMatch     bool
 	IsCaseSensitive bool
 	FileMatchLimit  int32
 
+	// IsUnicode is whether text is matched with Unicode case folding, after
+	// normalizing it to Normalization ("NFC" or "NFD"). See NormalizeUnicode.
+	IsUnicode     bool
+	Normalization string
+
 	// We do not support IsMultiline
 	// IsMultiline     bool
 	IncludePatterns []string
@@ -34,7 +53,29 @@ type PatternInfo struct {
 }
 
 func (p *PatternInfo) IsEmpty() bool {
-	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0
+	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 && len(p.NegatedPatterns) == 0
+}
+
+// NormalizeUnicode converts the content patterns of p to the Unicode
+// normalization form p.Normalization, so that they match text that searcher
+// and zoekt normalized the same way. It does nothing unless p.IsUnicode is
+// set.
+func (p *PatternInfo) NormalizeUnicode() {
+	if !p.IsUnicode {
+		return
+	}
+
+	form := norm.NFC
+	if p.Normalization == "NFD" {
+		form = norm.NFD
+	}
+	p.Pattern = form.String(p.Pattern)
+	for i := range p.AndPatterns {
+		p.AndPatterns[i] = form.String(p.AndPatterns[i])
+	}
+	for i := range p.NegatedPatterns {
+		p.NegatedPatterns[i] = form.String(p.NegatedPatterns[i])
+	}
 }
 
 // Validate returns a non-nil error if PatternInfo is not valid.
@@ -43,6 +84,16 @@ func (p *PatternInfo) Validate() error {
 		if _, err := syntax.Parse(p.Pattern, syntax.Perl); err != nil {
 			return err
 		}
+		for _, expr := range p.AndPatterns {
+			if _, err := syntax.Parse(expr, syntax.Perl); err != nil {
+				return err
+			}
+		}
+		for _, expr := range p.NegatedPatterns {
+			if _, err := syntax.Parse(expr, syntax.Perl); err != nil {
+				return err
+			}
+		}
 	}
 
 	if p.PathPatternsAreRegExps {
@@ -82,6 +133,10 @@ type Args struct {
 	// to true if the user requests a specific timeout or maximum result size.
 	UseFullDeadline bool
 
+	// Debug indicates that per-repository timing information should be
+	// collected while searching.
+	Debug bool
+
 	Zoekt        *searchbackend.Zoekt
 	SearcherURLs *endpoint.Map
 }
@@ -0,0 +1,258 @@
+// Package searchhotspots aggregates how often repositories, and directories
+// and revisions in them, appear in search results, so that site admins can see
+// which code is searched the most and so that searcher caches and indexes can
+// be prepared for it.
+//
+// Only aggregate counts of searches are stored, never queries, users or file
+// names: paths are truncated to their leading directories, and counts below
+// MinSearches are never reported, so that individual searches can't be
+// recovered from the statistics.
+package searchhotspots
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+)
+
+// pool is the Redis pool that the daily counts are stored in.
+var pool = redispool.Store
+
+// timeNow is mocked in tests.
+var timeNow = time.Now
+
+const (
+	// WindowDays is the number of days (including today) that counts are
+	// aggregated over.
+	WindowDays = 7
+
+	// MinSearches is the number of searches that a repository, directory or
+	// revision must appear in the results of to be reported.
+	MinSearches = 5
+
+	// pathDepth is the number of leading directories of file paths that
+	// counts are aggregated by.
+	pathDepth = 2
+
+	// maxReposPerSearch is the number of repositories above which the results
+	// of a search are not recorded: broad searches say little about which
+	// code is hot, and would drown out the searches that do.
+	maxReposPerSearch = 50
+
+	// maxRepos and maxValues are the number of repositories, and of
+	// directories and revisions per repository, that are reported.
+	maxRepos  = 1000
+	maxValues = 20
+
+	keyPrefix = "search_hotspots:"
+)
+
+// Hit is a search result in a repository.
+type Hit struct {
+	Repo api.RepoName
+	// Rev is the revision that the result was found in, as specified in the
+	// query, or "" for the default branch.
+	Rev string
+	// Path is the path of the file of the result, or "" if the result is not
+	// a file (e.g. a repository or commit result).
+	Path string
+}
+
+// Count is the number of searches that a value appeared in the results of.
+type Count struct {
+	Value    string
+	Searches int
+}
+
+// HotSpot is a repository that appeared in the results of at least
+// MinSearches searches in the last WindowDays days.
+type HotSpot struct {
+	Repo     api.RepoName
+	Searches int
+
+	// Paths are the directories (truncated to their leading directories, ""
+	// for the root) of the repository that were the most often in results.
+	Paths []Count
+
+	// Revs are the revisions of the repository that were the most often
+	// searched ("HEAD" for the default branch).
+	Revs []Count
+}
+
+// Record records the hits of a single search. Each repository, directory and
+// revision is counted at most once per search.
+func Record(hits []Hit) error {
+	type key struct {
+		repo  api.RepoName
+		value string
+	}
+	var (
+		repos = map[api.RepoName]bool{}
+		dirs  = map[key]bool{}
+		revs  = map[key]bool{}
+	)
+	for _, h := range hits {
+		repos[h.Repo] = true
+		if h.Path != "" {
+			dirs[key{h.Repo, leadingDirs(h.Path)}] = true
+		}
+		rev := h.Rev
+		if rev == "" {
+			rev = "HEAD"
+		}
+		revs[key{h.Repo, rev}] = true
+	}
+	if len(repos) == 0 || len(repos) > maxReposPerSearch {
+		return nil
+	}
+
+	day := timeNow().UTC()
+	expire := map[string]bool{}
+	incr := func(c redis.Conn, key, member string) error {
+		expire[key] = true
+		return c.Send("ZINCRBY", key, 1, member)
+	}
+
+	c := pool.Get()
+	defer c.Close()
+
+	if err := c.Send("MULTI"); err != nil {
+		return err
+	}
+	for repo := range repos {
+		if err := incr(c, reposKey(day), string(repo)); err != nil {
+			return err
+		}
+	}
+	for k := range dirs {
+		if err := incr(c, pathsKey(day, k.repo), k.value); err != nil {
+			return err
+		}
+	}
+	for k := range revs {
+		if err := incr(c, revsKey(day, k.repo), k.value); err != nil {
+			return err
+		}
+	}
+	// Keep each day's counts for as long as they are in the window.
+	ttl := int((WindowDays + 1) * 24 * time.Hour / time.Second)
+	for key := range expire {
+		if err := c.Send("EXPIRE", key, ttl); err != nil {
+			return err
+		}
+	}
+	_, err := c.Do("EXEC")
+	return err
+}
+
+// List returns the first hot spots (all of them if first is 0), the most
+// searched first.
+func List(first int) ([]*HotSpot, error) {
+	c := pool.Get()
+	defer c.Close()
+
+	days := windowDays()
+
+	repos, err := sum(c, keys(days, reposKey), maxRepos)
+	if err != nil {
+		return nil, err
+	}
+	if first > 0 && len(repos) > first {
+		repos = repos[:first]
+	}
+
+	hotSpots := make([]*HotSpot, 0, len(repos))
+	for _, repo := range repos {
+		name := api.RepoName(repo.Value)
+		h := &HotSpot{Repo: name, Searches: repo.Searches}
+		if h.Paths, err = sum(c, keys(days, func(day time.Time) string { return pathsKey(day, name) }), maxValues); err != nil {
+			return nil, err
+		}
+		if h.Revs, err = sum(c, keys(days, func(day time.Time) string { return revsKey(day, name) }), maxValues); err != nil {
+			return nil, err
+		}
+		hotSpots = append(hotSpots, h)
+	}
+	return hotSpots, nil
+}
+
+// sum returns the values of the sorted sets with the given keys whose scores
+// sum up to at least MinSearches, the highest first. At most limit values
+// are returned, and only the limit highest values of each set are summed up.
+func sum(c redis.Conn, keys []string, limit int) ([]Count, error) {
+	searches := map[string]int{}
+	for _, key := range keys {
+		scores, err := redis.IntMap(c.Do("ZREVRANGE", key, 0, limit-1, "WITHSCORES"))
+		if err != nil {
+			return nil, err
+		}
+		for value, n := range scores {
+			searches[value] += n
+		}
+	}
+
+	counts := make([]Count, 0, len(searches))
+	for value, n := range searches {
+		if n >= MinSearches {
+			counts = append(counts, Count{Value: value, Searches: n})
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Searches != counts[j].Searches {
+			return counts[i].Searches > counts[j].Searches
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts, nil
+}
+
+// leadingDirs returns the first pathDepth directories of the directory of
+// the file at p.
+func leadingDirs(p string) string {
+	dir := path.Dir(strings.Trim(p, "/"))
+	if dir == "." {
+		return ""
+	}
+	if parts := strings.SplitN(dir, "/", pathDepth+1); len(parts) > pathDepth {
+		dir = strings.Join(parts[:pathDepth], "/")
+	}
+	return dir
+}
+
+// windowDays returns the days in the window, today first.
+func windowDays() []time.Time {
+	today := timeNow().UTC()
+	days := make([]time.Time, WindowDays)
+	for i := range days {
+		days[i] = today.AddDate(0, 0, -i)
+	}
+	return days
+}
+
+func keys(days []time.Time, key func(day time.Time) string) []string {
+	ks := make([]string, len(days))
+	for i, day := range days {
+		ks[i] = key(day)
+	}
+	return ks
+}
+
+func reposKey(day time.Time) string {
+	return keyPrefix + day.Format("2006-01-02") + ":repos"
+}
+
+func pathsKey(day time.Time, repo api.RepoName) string {
+	return keyPrefix + day.Format("2006-01-02") + ":paths:" + string(repo)
+}
+
+func revsKey(day time.Time, repo api.RepoName) string {
+	return keyPrefix + day.Format("2006-01-02") + ":revs:" + string(repo)
+}
@@ -0,0 +1,117 @@
+package searchhotspots
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestRecordAndList(t *testing.T) {
+	pool = &redis.Pool{
+		MaxIdle: 3,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+	c := pool.Get()
+	defer c.Close()
+
+	// If we are not on CI, skip the test if our redis connection fails.
+	if os.Getenv("CI") == "" {
+		if _, err := c.Do("PING"); err != nil {
+			t.Skip("could not connect to redis", err)
+		}
+	}
+
+	now := time.Date(2019, 11, 8, 10, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var (
+		hot  = api.RepoName("__test__/hot")
+		cold = api.RepoName("__test__/cold")
+	)
+	for _, day := range append(windowDays(), now.AddDate(0, 0, -WindowDays)) {
+		for _, key := range []string{reposKey(day), pathsKey(day, hot), revsKey(day, hot), pathsKey(day, cold), revsKey(day, cold)} {
+			if _, err := c.Do("DEL", key); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	record := func(hits ...Hit) {
+		t.Helper()
+		if err := Record(hits); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Searches spread over the window are summed up.
+	for i := 0; i < MinSearches; i++ {
+		now = time.Date(2019, 11, 8-i, 10, 30, 0, 0, time.UTC)
+		hits := []Hit{
+			{Repo: hot, Path: "cmd/frontend/internal/app.go"},
+			{Repo: hot, Path: "cmd/frontend/main.go"}, // same directory, counted once
+			{Repo: hot, Rev: "v1", Path: "README.md"},
+		}
+		if i > 0 {
+			hits = append(hits, Hit{Repo: cold, Path: "main.go"})
+		}
+		record(hits...)
+	}
+	// Searches before the window are not.
+	now = time.Date(2019, 11, 8-WindowDays, 10, 30, 0, 0, time.UTC)
+	record(Hit{Repo: cold, Path: "main.go"})
+	now = time.Date(2019, 11, 8, 10, 30, 0, 0, time.UTC)
+	record(Hit{Repo: hot, Rev: "v2"})
+
+	// Searches with results in too many repositories are not recorded.
+	broad := []Hit{{Repo: cold}}
+	for i := 0; i < maxReposPerSearch; i++ {
+		broad = append(broad, Hit{Repo: api.RepoName(fmt.Sprintf("__test__/broad-%d", i))})
+	}
+	record(broad...)
+
+	hotSpots, err := List(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[api.RepoName]*HotSpot{}
+	for _, h := range hotSpots {
+		got[h.Repo] = h
+	}
+
+	want := &HotSpot{
+		Repo:     hot,
+		Searches: MinSearches + 1,
+		Paths:    []Count{{Value: "", Searches: MinSearches}, {Value: "cmd/frontend", Searches: MinSearches}},
+		Revs:     []Count{{Value: "HEAD", Searches: MinSearches}, {Value: "v1", Searches: MinSearches}},
+	}
+	if !reflect.DeepEqual(got[hot], want) {
+		t.Errorf("got hot spot %+v, want %+v", got[hot], want)
+	}
+	// The cold repository was in the results of MinSearches+1 searches, but
+	// one of them was before the window and one was too broad.
+	if got[cold] != nil {
+		t.Errorf("got hot spot %+v, want none for cold repository", got[cold])
+	}
+}
+
+func TestLeadingDirs(t *testing.T) {
+	for path, want := range map[string]string{
+		"main.go":          "",
+		"/main.go":         "",
+		"cmd/main.go":      "cmd",
+		"cmd/a/main.go":    "cmd/a",
+		"cmd/a/b/c/foo.go": "cmd/a",
+	} {
+		if got := leadingDirs(path); got != want {
+			t.Errorf("leadingDirs(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package storagequota accounts for the row counts of the database tables
+// that grow with the number of repositories synced from code hosts, and
+// compares them with the soft quotas of the "storage.softQuotas" site
+// configuration.
+package storagequota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// checkInterval is how often the row counts are recorded.
+const checkInterval = 10 * time.Minute
+
+// nearingFraction is the fraction of its quota above which a table is
+// reported as nearing the quota.
+const nearingFraction = 0.9
+
+// defaultQuotas are the soft quotas of the tables that have none configured.
+var defaultQuotas = map[string]int64{
+	"repo":       500000,
+	"changesets": 100000,
+}
+
+// TableStatus is the row count of a table and its soft quota.
+type TableStatus struct {
+	Table    string
+	RowCount int64
+	// Quota is the soft quota of the table, or 0 if it has none.
+	Quota int64
+}
+
+// Exceeded returns whether the table has more rows than its quota.
+func (s TableStatus) Exceeded() bool {
+	return s.Quota > 0 && s.RowCount > s.Quota
+}
+
+// Nearing returns whether the table has almost as many rows as its quota,
+// but not more.
+func (s TableStatus) Nearing() bool {
+	return s.Quota > 0 && !s.Exceeded() && float64(s.RowCount) >= nearingFraction*float64(s.Quota)
+}
+
+// Status is the result of the last accounting of the row counts.
+type Status struct {
+	// CheckedAt is when the rows were counted.
+	CheckedAt time.Time
+	Tables    []TableStatus
+}
+
+var (
+	mu        sync.Mutex
+	checkedAt time.Time
+	rowCounts []db.TableRowCount
+)
+
+// Last returns the row counts of the last accounting compared with the
+// currently configured quotas, or nil if the rows haven't been counted yet.
+func Last() *Status {
+	mu.Lock()
+	t, counts := checkedAt, rowCounts
+	mu.Unlock()
+
+	if counts == nil {
+		return nil
+	}
+	return newStatus(t, counts, conf.Get().StorageSoftQuotas)
+}
+
+func newStatus(checkedAt time.Time, counts []db.TableRowCount, quotas *schema.StorageSoftQuotas) *Status {
+	s := &Status{CheckedAt: checkedAt, Tables: make([]TableStatus, 0, len(counts))}
+	for _, c := range counts {
+		s.Tables = append(s.Tables, TableStatus{
+			Table:    c.Table,
+			RowCount: c.RowCount,
+			Quota:    quota(c.Table, quotas),
+		})
+	}
+	return s
+}
+
+// quota returns the soft quota of the table, or 0 if it has none.
+func quota(table string, quotas *schema.StorageSoftQuotas) int64 {
+	var configured int
+	if quotas != nil {
+		switch table {
+		case "repo":
+			configured = quotas.Repos
+		case "changesets":
+			configured = quotas.Changesets
+		}
+	}
+
+	switch {
+	case configured < 0:
+		return 0
+	case configured > 0:
+		return int64(configured)
+	default:
+		return defaultQuotas[table]
+	}
+}
+
+// Start counts the rows of the tables periodically.
+func Start(ctx context.Context) {
+	for {
+		if err := check(ctx); err != nil {
+			log15.Error("counting rows of database tables for storage quotas", "error", err)
+		}
+		time.Sleep(checkInterval)
+	}
+}
+
+func check(ctx context.Context) error {
+	counts, err := db.Storage.RowCounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	checkedAt, rowCounts = time.Now(), counts
+	mu.Unlock()
+
+	for _, t := range Last().Tables {
+		if t.Exceeded() {
+			log15.Warn("database table exceeds its soft quota (storage.softQuotas in site configuration)", "table", t.Table, "rows", t.RowCount, "quota", t.Quota)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package storagequota
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewStatus(t *testing.T) {
+	now := time.Now()
+	counts := []db.TableRowCount{
+		{Table: "repo", RowCount: 950},
+		{Table: "changesets", RowCount: 200000},
+	}
+
+	for _, test := range []struct {
+		name   string
+		quotas *schema.StorageSoftQuotas
+		want   []TableStatus
+	}{
+		{
+			name: "defaults",
+			want: []TableStatus{
+				{Table: "repo", RowCount: 950, Quota: 500000},
+				{Table: "changesets", RowCount: 200000, Quota: 100000},
+			},
+		},
+		{
+			name:   "configured",
+			quotas: &schema.StorageSoftQuotas{Repos: 1000, Changesets: -1},
+			want: []TableStatus{
+				{Table: "repo", RowCount: 950, Quota: 1000},
+				{Table: "changesets", RowCount: 200000},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := newStatus(now, counts, test.quotas)
+			if !got.CheckedAt.Equal(now) {
+				t.Errorf("got CheckedAt %v, want %v", got.CheckedAt, now)
+			}
+			if !reflect.DeepEqual(got.Tables, test.want) {
+				t.Errorf("got tables %+v, want %+v", got.Tables, test.want)
+			}
+		})
+	}
+}
+
+func TestTableStatus(t *testing.T) {
+	for _, test := range []struct {
+		status            TableStatus
+		nearing, exceeded bool
+	}{
+		{status: TableStatus{RowCount: 10, Quota: 100}},
+		{status: TableStatus{RowCount: 90, Quota: 100}, nearing: true},
+		{status: TableStatus{RowCount: 100, Quota: 100}, nearing: true},
+		{status: TableStatus{RowCount: 101, Quota: 100}, exceeded: true},
+		{status: TableStatus{RowCount: 1000000}},
+	} {
+		if got := test.status.Nearing(); got != test.nearing {
+			t.Errorf("%+v: got Nearing %v, want %v", test.status, got, test.nearing)
+		}
+		if got := test.status.Exceeded(); got != test.exceeded {
+			t.Errorf("%+v: got Exceeded %v, want %v", test.status, got, test.exceeded)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// SearchIndexExclusion is a repository or a file path pattern that is excluded
+// from indexed search.
+type SearchIndexExclusion struct {
+	ID          int32      // the globally unique DB ID
+	RepoID      api.RepoID // if nonzero, this repository is excluded. RepoID/PathPattern are mutually exclusive.
+	PathPattern string     // if non-empty, files matching this glob pattern are excluded in all repositories
+	IndexBytes  int64      // the size of the repository's index when it was excluded (0 if it wasn't indexed)
+	CreatedAt   time.Time
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The states of a SearchJob.
+const (
+	SearchJobStateQueued     = "queued"
+	SearchJobStateProcessing = "processing"
+	SearchJobStateCompleted  = "completed"
+	SearchJobStateErrored    = "errored"
+	SearchJobStateCanceled   = "canceled"
+)
+
+// SearchJob is an exhaustive search that runs in the background, without the
+// timeout and result limit of interactive searches.
+type SearchJob struct {
+	ID          int32 // the globally unique DB ID
+	UserID      int32 // the user who created the job, whose permissions apply to the search
+	Query       string
+	PatternType string
+	State       string
+
+	// RepoRevs is the JSON-encoded list of the repository revisions that the
+	// job searches, which are resolved once when the job starts (nil until
+	// then).
+	RepoRevs json.RawMessage
+
+	ReposSearched   int      // the number of RepoRevs searched so far
+	ResultCount     int      // the number of results found so far
+	IncompleteRepos []string // the repositories that could not be searched completely
+	Error           string   // the error that the job failed with, if State is SearchJobStateErrored
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
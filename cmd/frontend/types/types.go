@@ -39,12 +39,29 @@ type Repo struct {
 	//
 	// Previously, this was called RepoURI.
 	Name api.RepoName
+	// VisibilityOverride is the visibility of this repository on Sourcegraph
+	// as set by a site admin, regardless of its visibility on the code host.
+	// It is empty if there is no override.
+	VisibilityOverride RepoVisibility
 
 	// RepoFields contains fields that are loaded from the DB only when necessary.
 	// This is to reduce memory usage when loading thousands of repos.
 	*RepoFields
 }
 
+// RepoVisibility is the visibility of a repository on Sourcegraph.
+type RepoVisibility string
+
+const (
+	// RepoVisibilityPublic repositories are visible to everyone, including
+	// anonymous users.
+	RepoVisibilityPublic RepoVisibility = "public"
+	// RepoVisibilityInternal repositories are visible to all signed-in users.
+	RepoVisibilityInternal RepoVisibility = "internal"
+	// RepoVisibilityPrivate repositories are only visible to site admins.
+	RepoVisibilityPrivate RepoVisibility = "private"
+)
+
 // Repos is an utility type of a list of repos.
 type Repos []*Repo
 
@@ -63,6 +80,27 @@ type ExternalService struct {
 	DeletedAt   *time.Time
 }
 
+// ExternalServiceConfigRevision is a configuration that an external service
+// had. Every configuration change creates a new revision, numbered from 1.
+type ExternalServiceConfigRevision struct {
+	ExternalServiceID int64
+	Revision          int32
+	AuthorUserID      *int32 // nil if the configuration was changed by the application
+	Config            string
+	CreatedAt         time.Time
+}
+
+// RepoIntroduction records when an external service first yielded a
+// repository, and who last configured the external service then.
+type RepoIntroduction struct {
+	ID                int64
+	RepoID            api.RepoID
+	RepoName          api.RepoName // the name of the repository when it was introduced
+	ExternalServiceID int64
+	AuthorUserID      *int32 // nil if unknown
+	CreatedAt         time.Time
+}
+
 type GlobalState struct {
 	SiteID      string
 	Initialized bool // whether the initial site admin account has been created
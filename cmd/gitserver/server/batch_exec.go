@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/repotrackutil"
+)
+
+// batchExecConcurrency is the number of commands of a batch exec request
+// that run concurrently.
+var batchExecConcurrency = 8
+
+// batchExecMaxOutput is the maximum number of bytes of stdout that are
+// buffered for each command of a batch exec request. Commands that write more
+// are killed, and their result is marked as truncated.
+var batchExecMaxOutput = 10 * 1024 * 1024
+
+func (s *Server) handleBatchExec(w http.ResponseWriter, r *http.Request) {
+	var req protocol.BatchExecRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	results := make(chan *protocol.BatchExecResult)
+	go func() {
+		defer close(results)
+
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, batchExecConcurrency)
+		)
+		for i := range req.Requests {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				res := s.batchExec(ctx, &req.Requests[i], req.Timeout)
+				res.Index = i
+				select {
+				case results <- res:
+				case <-ctx.Done():
+				}
+			}(i)
+		}
+		wg.Wait()
+	}()
+
+	// Stream each result as soon as it is available, so that clients with a
+	// context deadline receive as many results as possible.
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher := hackilyGetHTTPFlusher(w)
+	enc := json.NewEncoder(w)
+	for res := range results {
+		if err := enc.Encode(res); err != nil {
+			return // the client went away
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// batchExec runs the command of a request of a batch exec request. Unlike
+// exec, it doesn't clone repositories that are not cloned.
+func (s *Server) batchExec(ctx context.Context, req *protocol.ExecRequest, timeout time.Duration) *protocol.BatchExecResult {
+	req.Repo = protocol.NormalizeRepo(req.Repo)
+	dir := s.dir(req.Repo)
+	if cloneProgress, cloneInProgress := s.locker.Status(dir); cloneInProgress {
+		return &protocol.BatchExecResult{NotFound: &protocol.NotFoundPayload{CloneInProgress: true, CloneProgress: cloneProgress}}
+	}
+	if !repoCloned(dir) {
+		return &protocol.BatchExecResult{NotFound: &protocol.NotFoundPayload{}}
+	}
+
+	_ = s.ensureRevision(ctx, req.Repo, req.URL, req.EnsureRevision, dir)
+
+	if max := shortGitCommandTimeout(req.Args); timeout <= 0 || timeout > max {
+		timeout = max
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmdName string
+	if len(req.Args) > 0 {
+		cmdName = req.Args[0]
+	}
+	repo := repotrackutil.GetTrackedRepo(req.Repo)
+	execRunning.WithLabelValues(cmdName, repo).Inc()
+	start := time.Now()
+
+	var stderr bytes.Buffer
+	stdout := &limitedBuffer{max: batchExecMaxOutput, onLimit: cancel}
+	cmd := exec.CommandContext(cmdCtx, "git", req.Args...)
+	cmd.Dir = string(dir)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	exitStatus, err := runCommand(cmdCtx, cmd)
+
+	execRunning.WithLabelValues(cmdName, repo).Dec()
+	execDuration.WithLabelValues(cmdName, repo, strconv.Itoa(exitStatus)).Observe(time.Since(start).Seconds())
+
+	res := &protocol.BatchExecResult{
+		Stdout:     stdout.Bytes(),
+		Complete:   true,
+		ExitStatus: exitStatus,
+	}
+	if stdout.truncated {
+		// The command was killed because it wrote too much, so its output
+		// is partial but not erroneous.
+		res.Complete = false
+		res.Truncated = true
+		return res
+	}
+	if cmdCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		// The command was killed because it timed out, so its output is
+		// partial but not erroneous.
+		res.Complete = false
+		return res
+	}
+	res.Error = errorString(err)
+	if res.Stderr = stderr.String(); len(res.Stderr) > 1024 {
+		res.Stderr = res.Stderr[:1024]
+	}
+	return res
+}
+
+// limitedBuffer is a buffer that keeps the first max bytes written to it and
+// discards the rest. onLimit is called once when more than max bytes are
+// written.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	onLimit   func()
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.buf.Len(); len(p) > remaining {
+		b.buf.Write(p[:remaining])
+		if !b.truncated {
+			b.truncated = true
+			b.onLimit()
+		}
+		// Report the whole write as successful, so that the command is
+		// killed by onLimit instead of failing on a short write.
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte { return b.buf.Bytes() }
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestHandleBatchExec(t *testing.T) {
+	s := &Server{ReposDir: "/testroot", skipCloneForTests: true}
+	h := s.Handler()
+
+	repoCloned = func(dir GitDir) bool {
+		return dir == s.dir("github.com/gorilla/mux") || dir == s.dir("github.com/gorilla/schema")
+	}
+
+	runCommandMock = func(ctx context.Context, cmd *exec.Cmd) (int, error) {
+		switch cmd.Args[1] {
+		case "testcommand":
+			cmd.Stdout.Write([]byte("teststdout"))
+			cmd.Stderr.Write([]byte("teststderr"))
+			return 0, nil
+		case "testerror":
+			return 1, errors.New("testerror")
+		case "testlargeoutput":
+			cmd.Stdout.Write([]byte("0123456789"))
+			cmd.Stdout.Write([]byte("0123456789"))
+			return -1, ctx.Err()
+		case "testtimeout":
+			cmd.Stdout.Write([]byte("partial"))
+			<-ctx.Done()
+			return -1, ctx.Err()
+		}
+		return 0, nil
+	}
+	defer func() { runCommandMock = nil }()
+
+	defer func(max int) { batchExecMaxOutput = max }(batchExecMaxOutput)
+	batchExecMaxOutput = 15
+
+	body, err := json.Marshal(&protocol.BatchExecRequest{
+		Requests: []protocol.ExecRequest{
+			{Repo: "github.com/gorilla/mux", Args: []string{"testcommand"}},
+			{Repo: "github.com/gorilla/schema", Args: []string{"testerror"}},
+			{Repo: "github.com/gorilla/mux", Args: []string{"testtimeout"}},
+			{Repo: "github.com/gorilla/doesnotexist", Args: []string{"testcommand"}},
+			{Repo: "github.com/gorilla/mux", Args: []string{"testlargeoutput"}},
+		},
+		Timeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/batch-exec", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var results []*protocol.BatchExecResult
+	dec := json.NewDecoder(w.Body)
+	for {
+		var res protocol.BatchExecResult
+		if err := dec.Decode(&res); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, &res)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+
+	want := []*protocol.BatchExecResult{
+		{Index: 0, Stdout: []byte("teststdout"), Stderr: "teststderr", Complete: true},
+		{Index: 1, Complete: true, ExitStatus: 1, Error: "testerror"},
+		{Index: 2, Stdout: []byte("partial"), Complete: false, ExitStatus: -1},
+		{Index: 3, NotFound: &protocol.NotFoundPayload{}},
+		{Index: 4, Stdout: []byte("012345678901234"), Complete: false, Truncated: true, ExitStatus: -1},
+	}
+	if !reflect.DeepEqual(results, want) {
+		for i := range results {
+			t.Logf("got result %+v", results[i])
+		}
+		t.Errorf("got %d results, want %+v", len(results), want)
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// diskInfoLargestRepos is the number of repos reported in
+// protocol.DiskInfo.LargestRepos.
+const diskInfoLargestRepos = 10
+
+// repoSizesMaxAge is how long the computed repo sizes are reused before
+// walking s.ReposDir again. Computing them requires a walk of every file on
+// disk, so we avoid doing it for every request.
+var repoSizesMaxAge = 10 * time.Minute
+
+// repoSizes is a snapshot of the sizes of all repos cloned on disk.
+type repoSizes struct {
+	count      int
+	largest    []protocol.RepoSize
+	computedAt time.Time
+}
+
+// diskInfo returns the disk usage of s.ReposDir.
+func (s *Server) diskInfo() (*protocol.DiskInfo, error) {
+	mountPoint, err := findMountPoint(s.ReposDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding mount point for dir containing repos")
+	}
+	free, err := s.DiskSizer.BytesFreeOnDisk(mountPoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding the amount of space free on disk")
+	}
+	total, err := s.DiskSizer.DiskSizeBytes(mountPoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting disk size")
+	}
+
+	sizes, err := s.cachedRepoSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.DiskInfo{
+		FreeBytes:          free,
+		TotalBytes:         total,
+		DesiredPercentFree: s.DesiredPercentFree,
+		RepoCount:          sizes.count,
+		LargestRepos:       sizes.largest,
+		ComputedAt:         sizes.computedAt,
+	}, nil
+}
+
+// cachedRepoSizes returns the sizes of the repos on disk, computing them if
+// they are older than repoSizesMaxAge.
+func (s *Server) cachedRepoSizes() (*repoSizes, error) {
+	s.repoSizesMu.Lock()
+	defer s.repoSizesMu.Unlock()
+
+	if s.repoSizes != nil && time.Since(s.repoSizes.computedAt) < repoSizesMaxAge {
+		return s.repoSizes, nil
+	}
+
+	dirs, err := s.findGitDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make([]protocol.RepoSize, 0, len(dirs))
+	for _, d := range dirs {
+		size, err := dirSize(string(d))
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, protocol.RepoSize{Repo: s.name(d), Bytes: size})
+	}
+
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].Bytes > sizes[j].Bytes })
+	if len(sizes) > diskInfoLargestRepos {
+		sizes = sizes[:diskInfoLargestRepos]
+	}
+
+	s.repoSizes = &repoSizes{
+		count:      len(dirs),
+		largest:    sizes,
+		computedAt: time.Now(),
+	}
+	return s.repoSizes, nil
+}
+
+func (s *Server) handleDiskInfo(w http.ResponseWriter, r *http.Request) {
+	info, err := s.diskInfo()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
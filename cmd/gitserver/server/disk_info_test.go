@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestServer_handleDiskInfo(t *testing.T) {
+	root, cleanup := tmpDir(t)
+	defer cleanup()
+
+	mkFiles(t, root,
+		"github.com/foo/small/.git/HEAD",
+		"github.com/foo/large/.git/HEAD",
+		"example.org/medium/.git/HEAD",
+		".tmp/ignored/.git/HEAD",
+	)
+	writeFile(t, filepath.Join(root, "github.com/foo/large/.git/HEAD"), make([]byte, 300))
+	writeFile(t, filepath.Join(root, "example.org/medium/.git/HEAD"), make([]byte, 200))
+	writeFile(t, filepath.Join(root, "github.com/foo/small/.git/HEAD"), make([]byte, 100))
+
+	s := &Server{
+		ReposDir:           root,
+		DesiredPercentFree: 10,
+		DiskSizer: &fakeDiskSizer{
+			bytesFree: 50,
+			diskSize:  1000,
+		},
+	}
+	h := s.Handler()
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/disk-info", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("http non-200 status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var info protocol.DiskInfo
+	if err := json.NewDecoder(rr.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.FreeBytes != 50 || info.TotalBytes != 1000 || info.DesiredPercentFree != 10 {
+		t.Errorf("unexpected disk usage: %+v", info)
+	}
+	if info.RepoCount != 3 {
+		t.Errorf("got repo count %d, want 3", info.RepoCount)
+	}
+	want := []protocol.RepoSize{
+		{Repo: "github.com/foo/large", Bytes: 300},
+		{Repo: "example.org/medium", Bytes: 200},
+		{Repo: "github.com/foo/small", Bytes: 100},
+	}
+	if !reflect.DeepEqual(info.LargestRepos, want) {
+		t.Errorf("got largest repos %+v, want %+v", info.LargestRepos, want)
+	}
+	if !info.NearlyFull() {
+		t.Error("expected shard with 5% free to be nearly full")
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func (s *Server) handleSubmodules(w http.ResponseWriter, r *http.Request) {
+	var req protocol.SubmodulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp protocol.SubmodulesResponse
+	if dir := s.dir(req.Repo); repoCloned(dir) {
+		submodules, err := repoSubmodules(r.Context(), dir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Submodules = submodules
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// repoSubmodules returns the submodules declared in the .gitmodules file at
+// HEAD, ordered by name. Submodules without a URL are omitted.
+func repoSubmodules(ctx context.Context, dir GitDir) ([]protocol.Submodule, error) {
+	// A repository without a .gitmodules file (or without commits) has no
+	// submodules.
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD:.gitmodules")
+	cmd.Dir = string(dir)
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return nil, nil
+	}
+
+	cmd = exec.Command("git", "config", "--blob", "HEAD:.gitmodules", "--null", "--list")
+	cmd.Dir = string(dir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("git %s failed: %s (%q)", cmd.Args, err, stderr.Bytes())
+	}
+	return parseGitmodules(stdout.String()), nil
+}
+
+// parseGitmodules parses the NUL-separated output of
+// `git config --null --list` for a .gitmodules file.
+func parseGitmodules(out string) []protocol.Submodule {
+	byName := map[string]*protocol.Submodule{}
+	for _, entry := range strings.Split(out, "\x00") {
+		// Each entry is "submodule.<name>.<key>\n<value>". The name may contain
+		// dots, but the key may not.
+		kv := strings.SplitN(entry, "\n", 2)
+		if len(kv) != 2 || !strings.HasPrefix(kv[0], "submodule.") {
+			continue
+		}
+		i := strings.LastIndex(kv[0], ".")
+		name, key := kv[0][len("submodule."):i], kv[0][i+1:]
+		if name == "" {
+			continue
+		}
+		sm, ok := byName[name]
+		if !ok {
+			sm = &protocol.Submodule{Name: name}
+			byName[name] = sm
+		}
+		switch key {
+		case "path":
+			sm.Path = kv[1]
+		case "url":
+			sm.URL = kv[1]
+		}
+	}
+
+	submodules := make([]protocol.Submodule, 0, len(byName))
+	for _, sm := range byName {
+		if sm.URL == "" {
+			continue
+		}
+		submodules = append(submodules, *sm)
+	}
+	sort.Slice(submodules, func(i, j int) bool { return submodules[i].Name < submodules[j].Name })
+	return submodules
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestServer_handleSubmodules(t *testing.T) {
+	root, cleanup := tmpDir(t)
+	defer cleanup()
+
+	cmd := func(dir, name string, arg ...string) {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = []string{
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		}
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("%s %s failed: %s (%s)", name, strings.Join(arg, " "), err, out)
+		}
+	}
+
+	// A repository with a .gitmodules file, and one without.
+	super := filepath.Join(root, "example.com", "super")
+	mkFiles(t, super, ".gitmodules")
+	cmd(super, "git", "init", ".")
+	writeFile(t, filepath.Join(super, ".gitmodules"), []byte(`[submodule "lib"]
+	path = vendor/lib
+	url = https://example.com/lib.git
+[submodule "docs.v2"]
+	path = docs
+	url = ../docs
+[submodule "nourl"]
+	path = nourl
+`))
+	cmd(super, "git", "add", ".gitmodules")
+	cmd(super, "git", "commit", "-m", "submodules")
+
+	plain := filepath.Join(root, "example.com", "plain")
+	mkFiles(t, plain, "README")
+	cmd(plain, "git", "init", ".")
+	cmd(plain, "git", "add", "README")
+	cmd(plain, "git", "commit", "-m", "readme")
+
+	s := &Server{ReposDir: root}
+	h := s.Handler()
+
+	getSubmodules := func(t *testing.T, repo api.RepoName) []protocol.Submodule {
+		t.Helper()
+		body, err := json.Marshal(protocol.SubmodulesRequest{Repo: repo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("POST", "/submodules", bytes.NewReader(body)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("http non-200 status %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp protocol.SubmodulesResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Submodules
+	}
+
+	t.Run("with .gitmodules", func(t *testing.T) {
+		want := []protocol.Submodule{
+			{Name: "docs.v2", Path: "docs", URL: "../docs"},
+			{Name: "lib", Path: "vendor/lib", URL: "https://example.com/lib.git"},
+		}
+		if got := getSubmodules(t, "example.com/super"); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("without .gitmodules", func(t *testing.T) {
+		if got := getSubmodules(t, "example.com/plain"); len(got) != 0 {
+			t.Errorf("got %+v, want no submodules", got)
+		}
+	})
+
+	t.Run("not cloned", func(t *testing.T) {
+		if got := getSubmodules(t, "example.com/missing"); len(got) != 0 {
+			t.Errorf("got %+v, want no submodules", got)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/txemail"
@@ -34,6 +35,10 @@ func (n *notifier) emailNotify(ctx context.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 		defer cancel()
 
+		// The recipients are notified with one request per variant of the
+		// email, which only differ in the ownership of the saved search.
+		var ownerships []string
+		userIDsByOwnership := map[string][]int32{}
 		for _, recipient := range n.recipients {
 			ownership := "the" // example: "new search results have been found for {{.Ownership}} saved search"
 			if n.spec.Subject.User != nil && *n.spec.Subject.User == recipient.spec.userID {
@@ -42,12 +47,18 @@ func (n *notifier) emailNotify(ctx context.Context) {
 			if n.spec.Subject.Org != nil {
 				ownership = "your organization's"
 			}
-
-			plural := ""
-			if n.results.Data.Search.Results.ApproximateResultCount != "1" {
-				plural = "s"
+			if _, ok := userIDsByOwnership[ownership]; !ok {
+				ownerships = append(ownerships, ownership)
 			}
-			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, struct {
+			userIDsByOwnership[ownership] = append(userIDsByOwnership[ownership], recipient.spec.userID)
+		}
+
+		plural := ""
+		if n.results.Data.Search.Results.ApproximateResultCount != "1" {
+			plural = "s"
+		}
+		for _, ownership := range ownerships {
+			if err := sendEmail(ctx, userIDsByOwnership[ownership], "results", newSearchResultsEmailTemplates, struct {
 				URL                    string
 				Description            string
 				Query                  string
@@ -62,7 +73,7 @@ func (n *notifier) emailNotify(ctx context.Context) {
 				Ownership:              ownership,
 				PluralResults:          plural,
 			}); err != nil {
-				log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
+				log15.Error("Failed to send email notification for new saved search results.", "userIDs", userIDsByOwnership[ownership], "error", err)
 			}
 		}
 	}()
@@ -116,7 +127,7 @@ func emailNotifySubscribeUnsubscribe(ctx context.Context, recipient *recipient,
 		ownership = "your organization's"
 	}
 
-	return sendEmail(ctx, recipient.spec.userID, eventType, template, struct {
+	return sendEmail(ctx, []int32{recipient.spec.userID}, eventType, template, struct {
 		Ownership   string
 		Description string
 	}{
@@ -125,24 +136,29 @@ func emailNotifySubscribeUnsubscribe(ctx context.Context, recipient *recipient,
 	})
 }
 
-func sendEmail(ctx context.Context, userID int32, eventType string, template txtypes.Templates, data interface{}) error {
-	email, err := api.InternalClient.UserEmailsGetEmail(ctx, userID)
+// sendEmail emails the notification to each of the given users. Failing to
+// notify one user doesn't stop notifying the others, and all errors are
+// returned.
+func sendEmail(ctx context.Context, userIDs []int32, eventType string, template txtypes.Templates, data interface{}) error {
+	deliveries, err := api.InternalClient.SendNotification(ctx, api.SendNotificationRequest{
+		Source:   "saved-search",
+		UserIDs:  userIDs,
+		Template: template,
+		Data:     data,
+	})
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("InternalClient.UserEmailsGetEmail for userID=%d", userID))
-	}
-	if email == nil {
-		return fmt.Errorf("unable to send email to user ID %d with unknown email address", userID)
+		return errors.Wrap(err, fmt.Sprintf("InternalClient.SendNotification for userIDs=%v", userIDs))
 	}
 
-	if err := api.InternalClient.SendEmail(ctx, txtypes.Message{
-		To:       []string{*email},
-		Template: template,
-		Data:     data,
-	}); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("InternalClient.SendEmail to email=%q userID=%d", *email, userID))
+	var errs *multierror.Error
+	for _, d := range deliveries {
+		if !d.Sent {
+			errs = multierror.Append(errs, fmt.Errorf("sending email to userID=%d: %s", d.UserID, d.Error))
+			continue
+		}
+		logEvent(d.UserID, d.Email, "SavedSearchEmailNotificationSent", eventType)
 	}
-	logEvent(userID, *email, "SavedSearchEmailNotificationSent", eventType)
-	return nil
+	return errs.ErrorOrNil()
 }
 
 var notifySubscribedTemplate = txemail.MustValidate(txtypes.Templates{
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"runtime"
 	"time"
@@ -137,7 +138,14 @@ func search(ctx context.Context, query string) (*gqlSearchResponse, error) {
 		return nil, errors.Wrap(err, "constructing frontend URL")
 	}
 
-	resp, err := ctxhttp.Post(ctx, nil, url, "application/json", &buf)
+	req, err := http.NewRequest("POST", url, &buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	api.SetInternalAuthHeaders(req.Header)
+
+	resp, err := ctxhttp.Do(ctx, nil, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "Post")
 	}
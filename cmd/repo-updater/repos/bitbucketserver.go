@@ -121,12 +121,60 @@ func (s BitbucketServerSource) LoadChangesets(ctx context.Context, cs ...*Change
 			return err
 		}
 
+		err = s.client.LoadPullRequestActivities(ctx, pr)
+		if err != nil {
+			return err
+		}
+
 		cs[i].Changeset.Metadata = pr
 	}
 
 	return nil
 }
 
+// CreateChangeset creates a pull request of the head branch into the base
+// branch of the Changeset's repo on the codehost, and sets the Changeset's
+// ExternalID and Metadata to the created pull request.
+func (s BitbucketServerSource) CreateChangeset(ctx context.Context, c *Changeset, title, body, baseRef, headRef string) error {
+	repo := c.Repo.Metadata.(*bitbucketserver.Repo)
+
+	pr := &bitbucketserver.PullRequest{Title: title, Description: body}
+	pr.ToRef.ID = baseRef
+	pr.ToRef.Repository.Slug = repo.Slug
+	pr.ToRef.Repository.Project.Key = repo.Project.Key
+	pr.FromRef.ID = headRef
+	pr.FromRef.Repository.Slug = repo.Slug
+	pr.FromRef.Repository.Project.Key = repo.Project.Key
+
+	if err := s.client.CreatePullRequest(ctx, pr); err != nil {
+		return err
+	}
+
+	c.Changeset.ExternalID = strconv.Itoa(pr.ID)
+	c.Changeset.ExternalServiceType = c.Repo.ExternalRepo.ServiceType
+	c.Changeset.Metadata = pr
+
+	return nil
+}
+
+// CloseChangeset declines the pull request of the Changeset on the codehost.
+func (s BitbucketServerSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketserver.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Server pull request")
+	}
+
+	// The pull request is declined at the version that was last synced, so
+	// that it's not declined if it changed since.
+	declined := *pr
+	if err := s.client.DeclinePullRequest(ctx, &declined); err != nil {
+		return err
+	}
+
+	c.Changeset.Metadata = &declined
+	return nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s BitbucketServerSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
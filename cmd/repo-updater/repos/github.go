@@ -101,6 +101,16 @@ func newGithubSource(svc *ExternalService, c *schema.GitHubConnection, cf *httpc
 		}
 	}
 
+	client, searchClient := github.NewClient(apiURL, c.Token, cli), github.NewClient(apiURL, c.Token, cli)
+	if i := c.GithubAppInstallation; i != nil {
+		if client, err = github.NewAppInstallationClient(apiURL, int64(i.AppID), int64(i.InstallationID), []byte(i.PrivateKey), cli); err != nil {
+			return nil, err
+		}
+		// Both clients share the installation access token, since they're
+		// created from the same installation.
+		searchClient = client
+	}
+
 	return &GithubSource{
 		svc:              svc,
 		config:           c,
@@ -108,8 +118,8 @@ func newGithubSource(svc *ExternalService, c *schema.GitHubConnection, cf *httpc
 		excludePatterns:  excludePatterns,
 		baseURL:          baseURL,
 		githubDotCom:     githubDotCom,
-		client:           github.NewClient(apiURL, c.Token, cli),
-		searchClient:     github.NewClient(apiURL, c.Token, cli),
+		client:           client,
+		searchClient:     searchClient,
 		originalHostname: originalHostname,
 	}, nil
 }
@@ -135,7 +145,7 @@ func (s GithubSource) ListRepos(ctx context.Context, results chan SourceResult)
 			continue
 		}
 		if !seen[res.repo.DatabaseID] && !s.excludes(res.repo) {
-			results <- SourceResult{Source: s, Repo: s.makeRepo(res.repo)}
+			results <- SourceResult{Source: s, Repo: s.makeRepo(ctx, res.repo)}
 			seen[res.repo.DatabaseID] = true
 		}
 	}
@@ -147,6 +157,8 @@ func (s GithubSource) ExternalServices() ExternalServices {
 }
 
 // LoadChangesets loads the latest state of the given Changesets from the codehost.
+// Changesets whose pull requests weren't updated since their Metadata was
+// loaded keep it, so that only the updated ones are fully loaded.
 func (s GithubSource) LoadChangesets(ctx context.Context, cs ...*Changeset) error {
 	prs := make([]*github.PullRequest, len(cs))
 	for i := range cs {
@@ -160,20 +172,78 @@ func (s GithubSource) LoadChangesets(ctx context.Context, cs ...*Changeset) erro
 			RepoWithOwner: repo.NameWithOwner,
 			Number:        number,
 		}
+		if pr, ok := cs[i].Changeset.Metadata.(*github.PullRequest); ok {
+			prs[i].UpdatedAt = pr.UpdatedAt
+		}
 	}
 
-	err := s.client.LoadPullRequests(ctx, prs...)
+	updated, err := s.client.UpdatedPullRequests(ctx, prs...)
 	if err != nil {
 		return err
 	}
 
+	if len(updated) > 0 {
+		if err = s.client.LoadPullRequests(ctx, updated...); err != nil {
+			return err
+		}
+	}
+
+	isUpdated := make(map[*github.PullRequest]bool, len(updated))
+	for _, pr := range updated {
+		isUpdated[pr] = true
+	}
+
 	for i := range cs {
-		cs[i].Changeset.Metadata = prs[i]
+		if isUpdated[prs[i]] {
+			cs[i].Changeset.Metadata = prs[i]
+		}
 	}
 
 	return nil
 }
 
+// CommentOnChangeset adds a comment with the given body to the pull request of
+// the Changeset on the codehost.
+func (s GithubSource) CommentOnChangeset(ctx context.Context, c *Changeset, body string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+	return s.client.CreatePullRequestComment(ctx, pr, body)
+}
+
+// ReviewChangeset submits a review of the pull request of the Changeset on the
+// codehost.
+func (s GithubSource) ReviewChangeset(ctx context.Context, c *Changeset, event ChangesetReviewEvent, body string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	var e string
+	switch event {
+	case ChangesetReviewEventApprove:
+		e = github.PullRequestReviewEventApprove
+	case ChangesetReviewEventRequestChanges:
+		e = github.PullRequestReviewEventRequestChanges
+	case ChangesetReviewEventComment:
+		e = github.PullRequestReviewEventComment
+	default:
+		return errors.Errorf("unknown review event %q", event)
+	}
+
+	return s.client.SubmitPullRequestReview(ctx, pr, e, body)
+}
+
+// CloseChangeset closes the pull request of the Changeset on the codehost.
+func (s GithubSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+	return s.client.ClosePullRequest(ctx, pr)
+}
+
 // GetRepo returns the Github repository with the given name and owner
 // ("org/repo-name")
 func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo, error) {
@@ -181,10 +251,10 @@ func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo,
 	if err != nil {
 		return nil, err
 	}
-	return s.makeRepo(r), nil
+	return s.makeRepo(ctx, r), nil
 }
 
-func (s GithubSource) makeRepo(r *github.Repository) *Repo {
+func (s GithubSource) makeRepo(ctx context.Context, r *github.Repository) *Repo {
 	urn := s.svc.URN()
 	return &Repo{
 		Name: string(reposource.GitHubRepoName(
@@ -205,7 +275,7 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
-				CloneURL: s.authenticatedRemoteURL(r),
+				CloneURL: s.authenticatedRemoteURL(ctx, r),
 			},
 		},
 		Metadata: r,
@@ -213,22 +283,36 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 }
 
 // authenticatedRemoteURL returns the repository's Git remote URL with the configured
-// GitHub personal access token inserted in the URL userinfo.
-func (s *GithubSource) authenticatedRemoteURL(repo *github.Repository) string {
+// GitHub personal access token inserted in the URL userinfo. For GitHub App installations,
+// the current installation access token is inserted instead, which is refreshed whenever
+// the repository is synced again.
+func (s *GithubSource) authenticatedRemoteURL(ctx context.Context, repo *github.Repository) string {
 	if s.config.GitURLType == "ssh" {
 		url := fmt.Sprintf("git@%s:%s.git", s.originalHostname, repo.NameWithOwner)
 		return url
 	}
 
-	if s.config.Token == "" {
+	var user *url.Userinfo
+	switch {
+	case s.config.GithubAppInstallation != nil:
+		token, err := s.client.InstallationToken(ctx)
+		if err != nil {
+			log15.Warn("Error creating GitHub App installation access token for Git remote URL.", "url", repo.URL, "error", err)
+			return repo.URL
+		}
+		user = url.UserPassword("x-access-token", token)
+	case s.config.Token != "":
+		user = url.User(s.config.Token)
+	default:
 		return repo.URL
 	}
+
 	u, err := url.Parse(repo.URL)
 	if err != nil {
 		log15.Warn("Error adding authentication to GitHub repository Git remote URL.", "url", repo.URL, "error", err)
 		return repo.URL
 	}
-	u.User = url.User(s.config.Token)
+	u.User = user
 	return u.String()
 }
 
@@ -282,12 +366,61 @@ func (s *GithubSource) paginate(ctx context.Context, results chan *githubResult,
 	}
 }
 
+// graphqlRepositoryPager is a function that returns the page of repositories after the given
+// `cursor` from the GitHub GraphQL API, along with the `endCursor` of the page, which is empty
+// if there is no next page.
+type graphqlRepositoryPager func(cursor string) (repos []*github.Repository, endCursor string, cost int, err error)
+
+// useGraphQLListing returns true if the connection is configured to list repositories with the
+// GitHub GraphQL API.
+func (s *GithubSource) useGraphQLListing() bool {
+	return s.config.RepositoryListingAPI == "graphql"
+}
+
+// paginateGraphQL returns all the repositories from the given graphqlRepositoryPager.
+//
+// It returns false without sending any results if the request for the first page fails, so
+// that the caller can fall back to the REST API. This is the case on older GitHub Enterprise
+// instances whose GraphQL API is missing or doesn't support the listing queries.
+func (s *GithubSource) paginateGraphQL(ctx context.Context, method string, results chan *githubResult, pager graphqlRepositoryPager) bool {
+	var cursor string
+	var failed bool
+	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
+		defer func() {
+			if err != nil && page == 1 {
+				log15.Warn("github sync: listing repositories with the GraphQL API failed. falling back to the REST API", "method", method, "error", err)
+				failed, err = true, nil
+			}
+
+			remaining, reset, retry, _ := s.client.RateLimit.Get()
+			log15.Debug(
+				"github sync: "+method,
+				"repos", len(repos),
+				"rateLimitCost", cost,
+				"rateLimitRemaining", remaining,
+				"rateLimitReset", reset,
+				"retryAfter", retry,
+			)
+		}()
+		repos, cursor, cost, err = pager(cursor)
+		return repos, cursor != "", cost, err
+	})
+	return !failed
+}
+
 // listOrg handles the `org` config option.
 // It returns all the repositories belonging to the given organization
-// by hitting the /orgs/:org/repos endpoint.
+// by hitting the /orgs/:org/repos endpoint, or with the GraphQL API if
+// the connection is configured to use it.
 //
 // It returns an error if the request fails on the first page.
 func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *githubResult) {
+	if s.useGraphQLListing() && s.paginateGraphQL(ctx, "ListOrgRepositoriesGraphQL", results, func(cursor string) ([]*github.Repository, string, int, error) {
+		return s.client.ListOrgRepositoriesGraphQL(ctx, org, cursor)
+	}) {
+		return
+	}
+
 	var oerr error
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
@@ -320,10 +453,17 @@ func (s *GithubSource) listOrg(ctx context.Context, org string, results chan *gi
 }
 
 // listUser returns all the repositories belonging to the given user
-// by hitting the /users/:user/repos endpoint.
+// by hitting the /users/:user/repos endpoint, or with the GraphQL API if
+// the connection is configured to use it.
 //
 // It returns an error if the request fails on the first page.
 func (s *GithubSource) listUser(ctx context.Context, user string, results chan *githubResult) (fail error) {
+	if s.useGraphQLListing() && s.paginateGraphQL(ctx, "ListUserRepositoriesGraphQL", results, func(cursor string) ([]*github.Repository, string, int, error) {
+		return s.client.ListUserRepositoriesGraphQL(ctx, user, cursor)
+	}) {
+		return nil
+	}
+
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
 			if err != nil && page == 1 {
@@ -430,11 +570,17 @@ func (s *GithubSource) listPublic(ctx context.Context, results chan *githubResul
 
 // listAffiliated handles the `affiliated` keyword of the `repositoryQuery` config option.
 // It returns the repositories affiliated with the client token by hitting the /user/repos
-// endpoint.
+// endpoint, or with the GraphQL API if the connection is configured to use it.
 //
 // Affiliation is present if the user: (1) owns the repo, (2) is apart of an org that
 // the repo belongs to, or (3) is a collaborator.
 func (s *GithubSource) listAffiliated(ctx context.Context, results chan *githubResult) {
+	if s.useGraphQLListing() && s.paginateGraphQL(ctx, "ListAffiliatedRepositoriesGraphQL", results, func(cursor string) ([]*github.Repository, string, int, error) {
+		return s.client.ListAffiliatedRepositoriesGraphQL(ctx, cursor)
+	}) {
+		return
+	}
+
 	s.paginate(ctx, results, func(page int) (repos []*github.Repository, hasNext bool, cost int, err error) {
 		defer func() {
 			remaining, reset, retry, _ := s.client.RateLimit.Get()
@@ -451,6 +597,24 @@ func (s *GithubSource) listAffiliated(ctx context.Context, results chan *githubR
 	})
 }
 
+// listInstallation handles the `installation` keyword of the `repositoryQuery` config option.
+// It returns the repositories that the configured GitHub App installation has access to by
+// hitting the /installation/repositories endpoint.
+func (s *GithubSource) listInstallation(ctx context.Context, results chan *githubResult) {
+	if s.config.GithubAppInstallation == nil {
+		results <- &githubResult{err: errors.New(`the "installation" repositoryQuery requires "githubAppInstallation" to be configured`)}
+		return
+	}
+
+	s.paginate(ctx, results, func(page int) ([]*github.Repository, bool, int, error) {
+		repos, hasNext, cost, err := s.client.ListInstallationRepositories(ctx, page)
+		if err != nil {
+			return nil, false, 0, errors.Wrapf(err, "failed to list GitHub App installation repositories: page=%d", page)
+		}
+		return repos, hasNext, cost, nil
+	})
+}
+
 // listSearch handles the `repositoryQuery` config option when a keyword is not present.
 // It returns the repositories resulting from from GitHub's advanced repository search
 // by hitting the /search/repositories endpoint.
@@ -517,8 +681,9 @@ func matchOrg(q string) string {
 // The supported keywords to select repositories are:
 // - `public`: public repositories (from endpoint: /repositories)
 // - `affiliated`: repositories affiliated with client token (from endpoint: /user/repos)
+// - `installation`: repositories of the GitHub App installation (from endpoint: /installation/repositories)
 // - `none`: disables `repositoryQuery`
-// Inputs other than these three keywords will be queried using
+// Inputs other than these four keywords will be queried using
 // GitHub advanced repository search (endpoint: /search/repositories)
 func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, results chan *githubResult) {
 	switch query {
@@ -528,6 +693,9 @@ func (s *GithubSource) listRepositoryQuery(ctx context.Context, query string, re
 	case "affiliated":
 		s.listAffiliated(ctx, results)
 		return
+	case "installation":
+		s.listInstallation(ctx, results)
+		return
 	case "none":
 		// nothing
 		return
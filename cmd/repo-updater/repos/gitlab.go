@@ -80,13 +80,21 @@ func newGitLabSource(svc *ExternalService, c *schema.GitLabConnection, cf *httpc
 		return nil, err
 	}
 
+	provider := gitlab.NewClientProvider(baseURL, cli)
+	var client *gitlab.Client
+	if c.TokenType == "oauth" {
+		client = provider.GetOAuthClient(c.Token)
+	} else {
+		client = provider.GetPATClient(c.Token, "")
+	}
+
 	return &GitLabSource{
 		svc:                 svc,
 		config:              c,
 		exclude:             exclude,
 		baseURL:             baseURL,
 		nameTransformations: nts,
-		client:              gitlab.NewClientProvider(baseURL, cli).GetPATClient(c.Token, ""),
+		client:              client,
 	}, nil
 }
 
@@ -145,8 +153,13 @@ func (s *GitLabSource) authenticatedRemoteURL(proj *gitlab.Project) string {
 		log15.Warn("Error adding authentication to GitLab repository Git remote URL.", "url", proj.HTTPURLToRepo, "error", err)
 		return proj.HTTPURLToRepo
 	}
-	// Any username works; "git" is not special.
-	u.User = url.UserPassword("git", s.config.Token)
+	// Any username works for personal access tokens; "git" is not special. OAuth tokens
+	// require the "oauth2" username.
+	username := "git"
+	if s.config.TokenType == "oauth" {
+		username = "oauth2"
+	}
+	u.User = url.UserPassword(username, s.config.Token)
 	return u.String()
 }
 
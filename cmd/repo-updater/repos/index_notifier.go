@@ -0,0 +1,49 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+var indexerNotifyURL = env.Get("INDEXER_NOTIFY_URL", "", "URL of the search indexer endpoint that is sent a POST request with the name of each repository whose update fetched new commits, so that it is reindexed right away (e.g. http://indexed-search:6072/enqueueindex).")
+
+// fetchedNewCommits returns true if the update that produced resp fetched new
+// commits (or cloned the repo), which then need to be indexed.
+func fetchedNewCommits(resp *gitserverprotocol.RepoUpdateResponse) bool {
+	if resp.Error != "" {
+		return false
+	}
+	if resp.CloneInProgress {
+		return true
+	}
+	// gitserver records when the refs of a repo last changed after every
+	// fetch, so they only changed in the last fetch if that happened after
+	// it.
+	return resp.LastFetched != nil && resp.LastChanged != nil && !resp.LastChanged.Before(*resp.LastFetched)
+}
+
+// notifyIndexer notifies the search indexer that the given repo has new
+// commits, so that it doesn't have to wait for its next pass over all repos
+// to reindex it. It is a no-op if INDEXER_NOTIFY_URL is not set.
+var notifyIndexer = func(ctx context.Context, name api.RepoName) error {
+	if indexerNotifyURL == "" {
+		return nil
+	}
+
+	resp, err := ctxhttp.PostForm(ctx, nil, indexerNotifyURL, url.Values{"repo": {string(name)}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("indexer notification for %s failed with status %d", name, resp.StatusCode)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package repos_test
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"testing"
+	"time"
 
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -53,11 +55,56 @@ func TestIntegration(t *testing.T) {
 		{"DBStore/ListExternalServices/ByRepo", testStoreListExternalServicesByRepos(store)},
 		{"DBStore/UpsertExternalServices", testStoreUpsertExternalServices(store)},
 		{"DBStore/UpsertRepos", testStoreUpsertRepos(store)},
+		{"DBStore/UpsertRepos/RecordIntroductions", testStoreRecordRepoIntroductions(db)},
 		{"DBStore/ListRepos", testStoreListRepos(store)},
 		{"DBStore/ListRepos/Pagination", testStoreListReposPagination(store)},
+		{"DBStore/ListReposPages", testStoreListReposPages(store)},
 		{"DBStore/Syncer/Sync", testSyncerSync(store)},
 		{"DBStore/Syncer/SyncSubset", testSyncSubset(store)},
+		{"Replicas", testReplicas(db)},
 	} {
 		t.Run(tc.name, tc.test)
 	}
 }
+
+func testReplicas(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		r1, r2 := repos.NewReplicas(db), repos.NewReplicas(db)
+		if err := r1.Join(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer r1.Leave()
+		if err := r2.Join(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer r2.Leave()
+
+		// r1 hasn't refreshed since r2 joined, so it only knows itself.
+		if !r1.IsLeader() || r2.IsLeader() {
+			t.Fatalf("want r1 to be the leader, have r1=%t r2=%t", r1.IsLeader(), r2.IsLeader())
+		}
+
+		rs := repos.Repos{{ID: 1}, {ID: 2}}
+		if owned, others := r2.Partition(rs); len(owned) != 1 || len(others) != 1 {
+			t.Fatalf("want r2 to own one of two repos, have owned=%v others=%v", owned, others)
+		}
+
+		// Once r1 is gone, r2 takes over all the work.
+		r1.Leave()
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go r2.Run(runCtx, 10*time.Millisecond)
+		deadline := time.Now().Add(5 * time.Second)
+		for !r2.IsLeader() {
+			if time.Now().After(deadline) {
+				t.Fatal("r2 did not become the leader")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if owned, _ := r2.Partition(rs); len(owned) != 2 {
+			t.Fatalf("want r2 to own all repos, have %v", owned)
+		}
+	}
+}
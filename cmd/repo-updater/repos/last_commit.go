@@ -0,0 +1,35 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// recordLastCommit records the committer date of the latest commit on the
+// default branch of the given repo in the frontend, which uses it to evaluate
+// repohascommitafter: search filters without running git for every repo. It
+// is a no-op for empty repos.
+var recordLastCommit = func(ctx context.Context, name api.RepoName) error {
+	repo := gitserver.Repo{Name: name}
+	commitID, err := git.ResolveRevision(ctx, repo, nil, "HEAD", &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		if gitserver.IsRevisionNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	commit, err := git.GetCommit(ctx, repo, nil, commitID)
+	if err != nil {
+		return err
+	}
+
+	lastCommitAt := commit.Author.Date
+	if commit.Committer != nil {
+		lastCommitAt = commit.Committer.Date
+	}
+	return api.InternalClient.ReposUpdateLastCommit(ctx, name, lastCommitAt)
+}
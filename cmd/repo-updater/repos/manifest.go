@@ -0,0 +1,239 @@
+package repos
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A ManifestSource yields the repositories listed in the manifest of a single
+// Manifest connection configured in Sourcegraph via the external services
+// configuration. The manifest is fetched again on every sync, so that exactly
+// the repositories it lists are mirrored.
+type ManifestSource struct {
+	svc    *ExternalService
+	conn   *schema.ManifestConnection
+	client httpcli.Doer
+}
+
+// ManifestRepo is an entry of a repository manifest, which is stored as the
+// metadata of the repositories yielded by a ManifestSource.
+type ManifestRepo struct {
+	Name     string            `json:"name"`
+	CloneURL string            `json:"cloneURL"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewManifestSource returns a new ManifestSource from the given external service.
+func NewManifestSource(svc *ExternalService, cf *httpcli.Factory) (*ManifestSource, error) {
+	var c schema.ManifestConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = NewHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ManifestSource{svc: svc, conn: &c, client: cli}, nil
+}
+
+// ListRepos returns all repositories listed in the manifest of the connection.
+func (s ManifestSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	rs, err := s.manifestRepos(ctx)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	urn := s.svc.URN()
+	for _, r := range rs {
+		results <- SourceResult{Source: s, Repo: s.makeRepo(urn, r)}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s ManifestSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s ManifestSource) makeRepo(urn string, r *ManifestRepo) *Repo {
+	serviceID := s.conn.Url
+	if serviceID == "" {
+		serviceID = urn
+	}
+
+	return &Repo{
+		Name: r.Name,
+		URI:  r.Name,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          r.Name,
+			ServiceType: "manifest",
+			ServiceID:   serviceID,
+		},
+		Enabled: true,
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: r.CloneURL,
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// manifestRepos returns the validated entries of the manifest.
+func (s ManifestSource) manifestRepos(ctx context.Context) ([]*ManifestRepo, error) {
+	rc, err := s.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rs []*ManifestRepo
+	switch s.conn.Format {
+	case "", "csv":
+		rs, err = parseCSVManifest(rc)
+	case "json":
+		err = json.NewDecoder(rc).Decode(&rs)
+	default:
+		return nil, errors.Errorf("unknown manifest format %q", s.conn.Format)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse manifest")
+	}
+
+	seen := make(map[string]bool, len(rs))
+	for i, r := range rs {
+		if err := validateManifestRepo(r); err != nil {
+			return nil, errors.Wrapf(err, "manifest entry %d", i)
+		}
+		if seen[strings.ToLower(r.Name)] {
+			return nil, errors.Errorf("manifest entry %d: duplicate name %q", i, r.Name)
+		}
+		seen[strings.ToLower(r.Name)] = true
+	}
+
+	return rs, nil
+}
+
+// open returns the contents of the manifest, which is either inlined in the
+// configuration or fetched from its URL.
+func (s ManifestSource) open(ctx context.Context) (io.ReadCloser, error) {
+	if s.conn.Url == "" {
+		return ioutil.NopCloser(strings.NewReader(s.conn.Manifest)), nil
+	}
+
+	req, err := http.NewRequest("GET", s.conn.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.conn.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.conn.Token)
+	}
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("unexpected response status %q fetching manifest", resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// parseCSVManifest parses a CSV manifest, the first row of which names its
+// columns. The name and clone_url columns are required, all others are
+// metadata.
+func parseCSVManifest(r io.Reader) ([]*ManifestRepo, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	nameCol, cloneURLCol := -1, -1
+	for i, col := range header {
+		header[i] = strings.TrimSpace(col)
+		switch header[i] {
+		case "name":
+			nameCol = i
+		case "clone_url":
+			cloneURLCol = i
+		}
+	}
+	if nameCol == -1 || cloneURLCol == -1 {
+		return nil, errors.New(`header must name the "name" and "clone_url" columns`)
+	}
+
+	var rs []*ManifestRepo
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		r := &ManifestRepo{
+			Name:     record[nameCol],
+			CloneURL: record[cloneURLCol],
+		}
+		for i, v := range record {
+			if i == nameCol || i == cloneURLCol || v == "" {
+				continue
+			}
+			if r.Metadata == nil {
+				r.Metadata = make(map[string]string, len(record)-2)
+			}
+			r.Metadata[header[i]] = v
+		}
+		rs = append(rs, r)
+	}
+
+	return rs, nil
+}
+
+func validateManifestRepo(r *ManifestRepo) error {
+	r.Name = strings.Trim(strings.TrimSpace(r.Name), "/")
+	if r.Name == "" {
+		return errors.New("name is empty")
+	}
+
+	u, err := url.Parse(strings.TrimSpace(r.CloneURL))
+	if err != nil {
+		return errors.Wrapf(err, "invalid clone URL of %q", r.Name)
+	}
+
+	switch u.Scheme {
+	case "git", "http", "https", "ssh":
+		r.CloneURL = u.String()
+		return nil
+	default:
+		return errors.Errorf("clone URL %q of %q: scheme %q not one of git, http, https or ssh", r.CloneURL, r.Name, u.Scheme)
+	}
+}
@@ -0,0 +1,123 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestManifestSource(t *testing.T) {
+	var body string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer s.Close()
+
+	repo := func(serviceID, name, cloneURL string, metadata map[string]string) *Repo {
+		return &Repo{
+			Name:    name,
+			URI:     name,
+			Enabled: true,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceType: "manifest",
+				ServiceID:   serviceID,
+			},
+			Sources: map[string]*SourceInfo{
+				"extsvc:manifest:1": {
+					ID:       "extsvc:manifest:1",
+					CloneURL: cloneURL,
+				},
+			},
+			Metadata: &ManifestRepo{Name: name, CloneURL: cloneURL, Metadata: metadata},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		config string
+		body   string
+		want   []*Repo
+		err    string
+	}{{
+		name:   "csv",
+		config: fmt.Sprintf(`{"url": %q, "token": "secret"}`, s.URL),
+		body: strings.Join([]string{
+			"name, clone_url, team, tier",
+			"# comments are ignored",
+			"/git.example.com/a/,https://git.example.com/a.git,search,1",
+			"git.example.com/b,ssh://git@git.example.com/b.git,,",
+		}, "\n"),
+		want: []*Repo{
+			repo(s.URL, "git.example.com/a", "https://git.example.com/a.git", map[string]string{"team": "search", "tier": "1"}),
+			repo(s.URL, "git.example.com/b", "ssh://git@git.example.com/b.git", nil),
+		},
+	}, {
+		name:   "json",
+		config: fmt.Sprintf(`{"url": %q, "token": "secret", "format": "json"}`, s.URL),
+		body:   `[{"name": "git.example.com/a", "cloneURL": "git://git.example.com/a", "metadata": {"team": "search"}}]`,
+		want: []*Repo{
+			repo(s.URL, "git.example.com/a", "git://git.example.com/a", map[string]string{"team": "search"}),
+		},
+	}, {
+		name:   "inline",
+		config: `{"manifest": "name,clone_url\ngit.example.com/a,https://git.example.com/a.git"}`,
+		want: []*Repo{
+			repo("extsvc:manifest:1", "git.example.com/a", "https://git.example.com/a.git", nil),
+		},
+	}, {
+		name:   "unauthorized",
+		config: fmt.Sprintf(`{"url": %q}`, s.URL),
+		err:    `unexpected response status "401 Unauthorized" fetching manifest`,
+	}, {
+		name:   "missing columns",
+		config: `{"manifest": "name,url\na,https://git.example.com/a.git"}`,
+		err:    `header must name the "name" and "clone_url" columns`,
+	}, {
+		name:   "invalid scheme",
+		config: `{"manifest": "name,clone_url\na,file:///repos/a"}`,
+		err:    `manifest entry 0: clone URL "file:///repos/a" of "a": scheme "file" not one of git, http, https or ssh`,
+	}, {
+		name:   "duplicate",
+		config: `{"manifest": "name,clone_url\na,https://git.example.com/a.git\nA,https://git.example.com/A.git"}`,
+		err:    `manifest entry 1: duplicate name "A"`,
+	}, {
+		name:   "empty name",
+		config: `{"format": "json", "manifest": "[{\"cloneURL\": \"https://git.example.com/a.git\"}]"}`,
+		err:    "manifest entry 0: name is empty",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body = tc.body
+
+			source, err := NewManifestSource(&ExternalService{
+				ID:     1,
+				Kind:   "MANIFEST",
+				Config: tc.config,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), source)
+			if got := fmt.Sprintf("%v", err); !strings.Contains(got, tc.err) {
+				t.Fatalf("got error %v, want %v", got, tc.err)
+			}
+			if !reflect.DeepEqual(repos, tc.want) {
+				t.Fatal("unexpected repos", cmp.Diff(tc.want, repos))
+			}
+		})
+	}
+}
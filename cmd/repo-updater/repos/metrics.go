@@ -78,6 +78,42 @@ var (
 		Name:      "sched_manual_fetch",
 		Help:      "Incremented each time the scheduler updates a repository due to user traffic.",
 	})
+	schedPriorityFetch = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_priority_fetch",
+		Help:      "Incremented each time the scheduler updates a repository because a user requested it.",
+	})
+	schedSkippedClone = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_skipped_clone",
+		Help:      "Incremented each time the scheduler skips cloning a repository because its gitserver is nearly full.",
+	})
+	schedIndexerNotifyError = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_indexer_notify_error",
+		Help:      "Incremented each time the scheduler fails to notify the search indexer of a repository with new commits.",
+	})
+	schedLastCommitError = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_last_commit_error",
+		Help:      "Incremented each time the scheduler fails to record the date of the last commit of a repository with new commits.",
+	})
+	schedSubmodulesError = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_submodules_error",
+		Help:      "Incremented each time the scheduler fails to record the submodules of a repository with new commits.",
+	})
+	schedPersistError = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_persist_error",
+		Help:      "Incremented each time the scheduler fails to persist the update schedule of repositories.",
+	})
 	schedKnownRepos = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "src",
 		Subsystem: "repoupdater",
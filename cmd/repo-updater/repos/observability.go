@@ -108,7 +108,7 @@ func (o *observedSource) ListRepos(ctx context.Context, results chan SourceResul
 	defer func(began time.Time) {
 		secs := time.Since(began).Seconds()
 		o.metrics.ListRepos.Observe(secs, count, &err)
-		log(o.log, "source.list-repos", &err)
+		observe(ctx, o.log, "source.list-repos", time.Since(began), &err)
 	}(time.Now())
 
 	uncounted := make(chan SourceResult)
@@ -163,6 +163,8 @@ type StoreMetrics struct {
 	UpsertExternalServices *OperationMetrics
 	ListExternalServices   *OperationMetrics
 	ListAllRepoNames       *OperationMetrics
+	ListScheduleStates     *OperationMetrics
+	UpsertScheduleStates   *OperationMetrics
 }
 
 // NewStoreMetrics returns StoreMetrics that need to be registered
@@ -309,6 +311,46 @@ func NewStoreMetrics() StoreMetrics {
 				Help:      "Total number of errors when listing repo names",
 			}, []string{}),
 		},
+		ListScheduleStates: &OperationMetrics{
+			Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_list_schedule_states_duration_seconds",
+				Help:      "Time spent listing repo update schedule states",
+			}, []string{}),
+			Count: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_list_schedule_states_total",
+				Help:      "Total number of listed repo update schedule states",
+			}, []string{}),
+			Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_list_schedule_states_errors_total",
+				Help:      "Total number of errors when listing repo update schedule states",
+			}, []string{}),
+		},
+		UpsertScheduleStates: &OperationMetrics{
+			Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_upsert_schedule_states_duration_seconds",
+				Help:      "Time spent upserting repo update schedule states",
+			}, []string{}),
+			Count: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_upsert_schedule_states_total",
+				Help:      "Total number of upserted repo update schedule states",
+			}, []string{}),
+			Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_upsert_schedule_states_errors_total",
+				Help:      "Total number of errors when upserting repo update schedule states",
+			}, []string{}),
+		},
 	}
 }
 
@@ -320,7 +362,7 @@ func (o *ObservedStore) Transact(ctx context.Context) (s TxStore, err error) {
 	defer func(began time.Time) {
 		secs := time.Since(began).Seconds()
 		o.metrics.Transact.Observe(secs, 1, &err)
-		log(o.log, "store.transact", &err)
+		observe(ctx, o.log, "store.transact", time.Since(began), &err)
 		if err != nil {
 			tr.SetError(err)
 			// Finish is called in Done in the non-error case
@@ -357,12 +399,13 @@ func (o *ObservedStore) Done(errs ...*error) {
 				done = true
 				tr.SetError(*err)
 				o.metrics.Done.Observe(secs, 1, err)
-				log(o.log, "store.done", err)
+				observe(o.txctx, o.log, "store.done", time.Since(began), err)
 			}
 		}
 
 		if !done {
 			o.metrics.Done.Observe(secs, 1, nil)
+			trace.ObserveExemplar(o.txctx, "store.done", time.Since(began), nil)
 		}
 
 		tr.Finish()
@@ -384,7 +427,7 @@ func (o *ObservedStore) ListExternalServices(ctx context.Context, args StoreList
 		count := float64(len(es))
 
 		o.metrics.ListExternalServices.Observe(secs, count, &err)
-		log(o.log, "store.list-external-services", &err,
+		observe(ctx, o.log, "store.list-external-services", time.Since(began), &err,
 			"args", fmt.Sprintf("%+v", args),
 			"count", len(es),
 		)
@@ -415,7 +458,7 @@ func (o *ObservedStore) UpsertExternalServices(ctx context.Context, svcs ...*Ext
 		count := float64(len(svcs))
 
 		o.metrics.UpsertExternalServices.Observe(secs, count, &err)
-		log(o.log, "store.upsert-external-services", &err,
+		observe(ctx, o.log, "store.upsert-external-services", time.Since(began), &err,
 			"count", len(svcs),
 			"names", ExternalServices(svcs).DisplayNames(),
 		)
@@ -434,6 +477,7 @@ func (o *ObservedStore) ListRepos(ctx context.Context, args StoreListReposArgs)
 		otlog.Object("args.names", args.Names),
 		otlog.Object("args.ids", args.IDs),
 		otlog.Object("args.kinds", args.Kinds),
+		otlog.Uint32("args.after-id", args.AfterID),
 	)
 
 	defer func(began time.Time) {
@@ -441,7 +485,7 @@ func (o *ObservedStore) ListRepos(ctx context.Context, args StoreListReposArgs)
 		count := float64(len(rs))
 
 		o.metrics.ListRepos.Observe(secs, count, &err)
-		log(o.log, "store.list-repos", &err,
+		observe(ctx, o.log, "store.list-repos", time.Since(began), &err,
 			"args", fmt.Sprintf("%+v", args),
 			"count", len(rs),
 		)
@@ -463,7 +507,7 @@ func (o *ObservedStore) ListAllRepoNames(ctx context.Context) (names []api.RepoN
 		count := float64(len(names))
 
 		o.metrics.ListAllRepoNames.Observe(secs, count, &err)
-		log(o.log, "store.list-all-repo-names", &err, "count", len(names))
+		observe(ctx, o.log, "store.list-all-repo-names", time.Since(began), &err, "count", len(names))
 
 		tr.LogFields(otlog.Int("count", len(names)))
 		tr.SetError(err)
@@ -483,7 +527,7 @@ func (o *ObservedStore) UpsertRepos(ctx context.Context, repos ...*Repo) (err er
 		count := float64(len(repos))
 
 		o.metrics.UpsertRepos.Observe(secs, count, &err)
-		log(o.log, "store.upsert-repos", &err, "count", len(repos))
+		observe(ctx, o.log, "store.upsert-repos", time.Since(began), &err, "count", len(repos))
 
 		tr.SetError(err)
 		tr.Finish()
@@ -492,6 +536,44 @@ func (o *ObservedStore) UpsertRepos(ctx context.Context, repos ...*Repo) (err er
 	return o.store.UpsertRepos(ctx, repos...)
 }
 
+// ListScheduleStates calls into the inner Store and registers the observed results.
+func (o *ObservedStore) ListScheduleStates(ctx context.Context) (states []*ScheduleState, err error) {
+	tr, ctx := o.trace(ctx, "Store.ListScheduleStates")
+
+	defer func(began time.Time) {
+		secs := time.Since(began).Seconds()
+		count := float64(len(states))
+
+		o.metrics.ListScheduleStates.Observe(secs, count, &err)
+		observe(ctx, o.log, "store.list-schedule-states", time.Since(began), &err, "count", len(states))
+
+		tr.LogFields(otlog.Int("count", len(states)))
+		tr.SetError(err)
+		tr.Finish()
+	}(time.Now())
+
+	return o.store.ListScheduleStates(ctx)
+}
+
+// UpsertScheduleStates calls into the inner Store and registers the observed results.
+func (o *ObservedStore) UpsertScheduleStates(ctx context.Context, states ...*ScheduleState) (err error) {
+	tr, ctx := o.trace(ctx, "Store.UpsertScheduleStates")
+	tr.LogFields(otlog.Int("count", len(states)))
+
+	defer func(began time.Time) {
+		secs := time.Since(began).Seconds()
+		count := float64(len(states))
+
+		o.metrics.UpsertScheduleStates.Observe(secs, count, &err)
+		observe(ctx, o.log, "store.upsert-schedule-states", time.Since(began), &err, "count", len(states))
+
+		tr.SetError(err)
+		tr.Finish()
+	}(time.Now())
+
+	return o.store.UpsertScheduleStates(ctx, states...)
+}
+
 func (o *ObservedStore) trace(ctx context.Context, family string) (*trace.Trace, context.Context) {
 	txctx := o.txctx
 	if txctx == nil {
@@ -501,6 +583,17 @@ func (o *ObservedStore) trace(ctx context.Context, family string) (*trace.Trace,
 	return tr, trace.ContextWithTrace(ctx, tr)
 }
 
+// observe records an exemplar of the operation if it took long, and logs its
+// error along with the trace of the span in ctx.
+func observe(ctx context.Context, lg ErrorLogger, msg string, took time.Duration, err *error, kvs ...interface{}) {
+	var e error
+	if err != nil {
+		e = *err
+	}
+	trace.ObserveExemplar(ctx, msg, took, e)
+	log(lg, msg, err, append(kvs, "trace", trace.SpanURLFromContext(ctx))...)
+}
+
 func log(lg ErrorLogger, msg string, err *error, ctx ...interface{}) {
 	if err == nil || *err == nil {
 		return
@@ -0,0 +1,284 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/segmentio/fasthash/fnv1"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxReplicaSlots is the maximum number of repo-updater replicas that can
+// share a database.
+const maxReplicaSlots = 64
+
+// replicaLockNamespace is the key of the Postgres advisory locks of the
+// replica slots. Postgres advisory lock ids are a global namespace within one
+// database. It is kept positive so that it can be compared to the unsigned
+// classid column of pg_locks.
+var replicaLockNamespace = int32(fnv1.HashString32("repo-updater-replicas") & 0x7fffffff)
+
+// Replicas coordinates the repo-updater replicas that share a database with
+// Postgres advisory locks.
+//
+// Every replica holds a session-level advisory lock on a slot for as long as
+// it runs, so that the live replicas can be listed from pg_locks. A replica
+// that goes away (or loses its database connection) releases its slot when
+// its session ends, and the other replicas take over its work the next time
+// they refresh.
+//
+// The replica with the lowest slot is the leader, which runs the work that
+// must not run concurrently, like syncing the repositories of all external
+// services. Repositories are partitioned by ID among the live replicas, so
+// that each replica only schedules the git fetches of its own partition.
+type Replicas struct {
+	db *sql.DB
+
+	mu   sync.RWMutex
+	conn *sql.Conn // the session holding the slot's lock, nil if not joined
+	slot int32
+	live []int32 // the sorted slots of the live replicas
+}
+
+// NewReplicas returns Replicas coordinating through the given database. Join
+// must be called before it's used.
+func NewReplicas(db *sql.DB) *Replicas {
+	return &Replicas{db: db}
+}
+
+// Join acquires the first free replica slot.
+func (r *Replicas) Join(ctx context.Context) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "replicas.join.conn")
+	}
+
+	for slot := int32(0); slot < maxReplicaSlots; slot++ {
+		q := sqlf.Sprintf(lockReplicaSlotQueryFmtstr, replicaLockNamespace, slot)
+
+		var locked bool
+		if err = conn.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&locked); err != nil {
+			conn.Close()
+			return errors.Wrap(err, "replicas.join.lock")
+		}
+
+		if locked {
+			r.mu.Lock()
+			r.conn, r.slot = conn, slot
+			r.mu.Unlock()
+			return r.refresh(ctx)
+		}
+	}
+
+	conn.Close()
+	return errors.Errorf("all %d repo-updater replica slots are taken", maxReplicaSlots)
+}
+
+const lockReplicaSlotQueryFmtstr = `
+-- source: cmd/repo-updater/repos/replicas.go:Replicas.Join
+SELECT pg_try_advisory_lock(%s, %s)
+`
+
+// Leave releases the replica slot, if any.
+func (r *Replicas) Leave() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn != nil {
+		// Closing the connection ends the session, which releases the lock.
+		r.conn.Close()
+	}
+	r.conn, r.live = nil, nil
+}
+
+// Run refreshes the live replicas at the given interval until ctx is done,
+// rejoining if the replica's slot was lost.
+func (r *Replicas) Run(ctx context.Context, interval time.Duration) {
+	defer r.Leave()
+
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+
+		if err := r.refresh(ctx); err != nil {
+			log15.Error("Replicas: refresh failed, rejoining", "error", err)
+			r.Leave()
+			if err = r.Join(ctx); err != nil {
+				log15.Error("Replicas: rejoin failed", "error", err)
+			}
+		}
+	}
+}
+
+// refresh lists the live replicas. It uses the session of the replica's slot,
+// so that it fails if the slot's lock was lost with the session.
+func (r *Replicas) refresh(ctx context.Context) error {
+	r.mu.RLock()
+	conn, slot := r.conn, r.slot
+	r.mu.RUnlock()
+
+	if conn == nil {
+		return errors.New("replicas.refresh: not joined")
+	}
+
+	q := sqlf.Sprintf(listReplicaSlotsQueryFmtstr, replicaLockNamespace)
+	rows, err := conn.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return errors.Wrap(err, "replicas.refresh.list")
+	}
+	defer rows.Close()
+
+	var (
+		live   []int32
+		joined bool
+	)
+	for rows.Next() {
+		var s int64
+		if err = rows.Scan(&s); err != nil {
+			return errors.Wrap(err, "replicas.refresh.scan")
+		}
+		live = append(live, int32(s))
+		joined = joined || int32(s) == slot
+	}
+	if err = rows.Err(); err != nil {
+		return errors.Wrap(err, "replicas.refresh.rows")
+	}
+	if !joined {
+		return errors.Errorf("replicas.refresh: lost replica slot %d", slot)
+	}
+
+	r.mu.Lock()
+	r.live = live
+	r.mu.Unlock()
+	return nil
+}
+
+const listReplicaSlotsQueryFmtstr = `
+-- source: cmd/repo-updater/repos/replicas.go:Replicas.refresh
+SELECT objid FROM pg_locks
+WHERE locktype = 'advisory'
+AND database = (SELECT oid FROM pg_database WHERE datname = current_database())
+AND classid = %s
+AND objsubid = 2
+AND granted
+ORDER BY objid
+`
+
+// IsLeader returns true if this replica is the leader.
+func (r *Replicas) IsLeader() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.live) > 0 && r.live[0] == r.slot
+}
+
+// slots returns the sorted slots of the live replicas.
+func (r *Replicas) slots() []int32 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]int32(nil), r.live...)
+}
+
+// Partition splits rs into the repos in the partition of this replica and
+// the ones in the partitions of the others. All repos are in the others if
+// this replica isn't joined.
+func (r *Replicas) Partition(rs Repos) (owned, others Repos) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, repo := range rs {
+		if n := uint32(len(r.live)); n > 0 && r.live[repo.ID%n] == r.slot {
+			owned = append(owned, repo)
+		} else {
+			others = append(others, repo)
+		}
+	}
+	return owned, others
+}
+
+// RunAsLeader calls f whenever this replica becomes the leader, with a
+// context that is canceled once it stops being the leader. Leadership is
+// checked at the given interval, which should be the one Run is called with.
+func (r *Replicas) RunAsLeader(ctx context.Context, interval time.Duration, f func(context.Context)) {
+	for ctx.Err() == nil {
+		if !r.IsLeader() {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		leaderCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			for leaderCtx.Err() == nil && r.IsLeader() {
+				select {
+				case <-time.After(interval):
+				case <-leaderCtx.Done():
+				}
+			}
+			cancel()
+		}()
+
+		log15.Info("Replicas: became leader")
+		f(leaderCtx)
+		cancel()
+	}
+}
+
+// RunPartitionScheduler limits the schedule of sched to the repos in the
+// partition of this replica. The repos are reloaded from the store whenever
+// the live replicas change and, if there are several of them, at the given
+// interval, since only the leader is sent the repos it syncs.
+func RunPartitionScheduler(ctx context.Context, r *Replicas, sched *updateScheduler, store Store, interval time.Duration) {
+	var (
+		slots      []int32
+		lastLoaded time.Time
+	)
+
+	for ctx.Err() == nil {
+		current := r.slots()
+		changed := !equalSlots(slots, current)
+		due := len(current) > 1 && time.Since(lastLoaded) >= interval
+
+		if (changed || due) && !conf.Get().DisableAutoGitUpdates {
+			rs, err := store.ListRepos(ctx, StoreListReposArgs{})
+			if err != nil {
+				log15.Error("RunPartitionScheduler: listing repos failed", "error", err)
+			} else {
+				owned, others := r.Partition(rs)
+				sched.Remove(others...)
+				sched.Set(owned...)
+				slots, lastLoaded = current, time.Now()
+			}
+		}
+
+		select {
+		case <-time.After(partitionSchedulerInterval):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// partitionSchedulerInterval is how often RunPartitionScheduler checks
+// whether the live replicas changed.
+var partitionSchedulerInterval = 10 * time.Second
+
+func equalSlots(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
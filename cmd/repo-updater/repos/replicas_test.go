@@ -0,0 +1,77 @@
+package repos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplicas_Partition(t *testing.T) {
+	var rs Repos
+	for id := uint32(1); id <= 6; id++ {
+		rs = append(rs, &Repo{ID: id})
+	}
+	ids := func(rs Repos) (ids []uint32) {
+		for _, r := range rs {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		name   string
+		slot   int32
+		live   []int32
+		leader bool
+		owned  []uint32
+		others []uint32
+	}{
+		{
+			name:   "not joined",
+			slot:   0,
+			live:   nil,
+			leader: false,
+			owned:  nil,
+			others: []uint32{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:   "single replica",
+			slot:   3,
+			live:   []int32{3},
+			leader: true,
+			owned:  []uint32{1, 2, 3, 4, 5, 6},
+			others: nil,
+		},
+		{
+			name:   "leader of two",
+			slot:   0,
+			live:   []int32{0, 2},
+			leader: true,
+			owned:  []uint32{2, 4, 6},
+			others: []uint32{1, 3, 5},
+		},
+		{
+			name:   "follower of three",
+			slot:   4,
+			live:   []int32{1, 2, 4},
+			leader: false,
+			owned:  []uint32{2, 5},
+			others: []uint32{1, 3, 4, 6},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Replicas{slot: tc.slot, live: tc.live}
+
+			if have, want := r.IsLeader(), tc.leader; have != want {
+				t.Errorf("IsLeader: have %t, want %t", have, want)
+			}
+
+			owned, others := r.Partition(rs)
+			if have, want := ids(owned), tc.owned; !reflect.DeepEqual(have, want) {
+				t.Errorf("owned: have %v, want %v", have, want)
+			}
+			if have, want := ids(others), tc.others; !reflect.DeepEqual(have, want) {
+				t.Errorf("others: have %v, want %v", have, want)
+			}
+		})
+	}
+}
@@ -91,6 +91,7 @@ const (
 // This heuristic is simple to compute and has nice backoff properties.
 //
 // When it is time for a repo to update, the scheduler inserts the repo into a queue.
+// The submodules of a repo are inserted before the repo (see submoduleGraph).
 //
 // A worker continuously dequeues repos and sends updates to gitserver, but its concurrency
 // is limited by the gitMaxConcurrentClones site configuration.
@@ -103,6 +104,7 @@ type updateScheduler struct {
 
 	updateQueue *updateQueue
 	schedule    *schedule
+	submodules  *submoduleGraph
 }
 
 // A configuredRepo2 represents the configuration data for a given repo from
@@ -131,9 +133,12 @@ func NewUpdateScheduler() *updateScheduler {
 			notifyEnqueue: make(chan struct{}, notifyChanBuffer),
 		},
 		schedule: &schedule{
-			index:  make(map[uint32]*scheduledRepoUpdate),
-			wakeup: make(chan struct{}, notifyChanBuffer),
+			index:    make(map[uint32]*scheduledRepoUpdate),
+			wakeup:   make(chan struct{}, notifyChanBuffer),
+			restored: make(map[uint32]*ScheduleState),
+			dirty:    make(map[uint32]struct{}),
 		},
+		submodules: newSubmoduleGraph(),
 	}
 }
 
@@ -164,8 +169,9 @@ func (s *updateScheduler) runSchedule() {
 		}
 
 		schedAutoFetch.Inc()
-		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow)
+		s.enqueue(repoUpdate.Repo, priorityLow)
 		repoUpdate.Due = timeNow().Add(repoUpdate.Interval)
+		s.schedule.markDirty(repoUpdate.Repo.ID)
 		heap.Fix(s.schedule, 0)
 	}
 }
@@ -200,23 +206,54 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 				defer s.updateQueue.remove(repo, true)
 
 				resp, err := requestRepoUpdate(ctx, repo, 1*time.Second)
-				if err != nil {
+				if err == errShardNearlyFull {
+					schedSkippedClone.Inc()
+					log15.Debug("skipping clone of repo on nearly full gitserver", "uri", repo.Name)
+				} else if err != nil {
 					schedError.Inc()
 					log15.Warn("error requesting repo update", "uri", repo.Name, "err", err)
 				}
+				if err != errShardNearlyFull && ctx.Err() == nil {
+					s.schedule.updateErrorCount(repo, err != nil)
+				}
 				if resp != nil && resp.LastFetched != nil && resp.LastChanged != nil {
 					// This is the heuristic that is described in the updateScheduler documentation.
 					// Update that documentation if you update this logic.
 					interval := resp.LastFetched.Sub(*resp.LastChanged) / 2
 					s.schedule.updateInterval(repo, interval)
 				}
+				if err == nil && resp != nil && fetchedNewCommits(resp) {
+					if err := notifyIndexer(ctx, repo.Name); err != nil {
+						schedIndexerNotifyError.Inc()
+						log15.Warn("error notifying indexer of repo update", "uri", repo.Name, "err", err)
+					}
+				}
+				if err == nil && resp != nil && resp.Cloned && fetchedNewCommits(resp) {
+					if err := recordLastCommit(ctx, repo.Name); err != nil {
+						schedLastCommitError.Inc()
+						log15.Warn("error recording last commit of repo", "uri", repo.Name, "err", err)
+					}
+					if err := s.updateSubmodules(ctx, repo); err != nil {
+						schedSubmodulesError.Inc()
+						log15.Warn("error recording submodules of repo", "uri", repo.Name, "err", err)
+					}
+				}
 			}(ctx, repo, cancel)
 		}
 	}
 }
 
-// requestRepoUpdate sends a request to gitserver to request an update.
+// requestRepoUpdate sends a request to gitserver to request an update. It
+// returns errShardNearlyFull instead of cloning a repo onto a gitserver that
+// is running out of disk space.
 var requestRepoUpdate = func(ctx context.Context, repo *configuredRepo2, since time.Duration) (*gitserverprotocol.RepoUpdateResponse, error) {
+	if defaultShardCapacity.nearlyFull(ctx, gitserver.DefaultClient.AddrForRepo(ctx, repo.Name)) {
+		if cloned, err := repoCloned(ctx, repo.Name); err != nil {
+			return nil, err
+		} else if !cloned {
+			return nil, errShardNearlyFull
+		}
+	}
 	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL}, since)
 }
 
@@ -242,7 +279,9 @@ func (s *updateScheduler) Update(rs ...*Repo) {
 
 	known := 0
 	for _, r := range rs {
-		if r.IsDeleted() {
+		// Archived mirrors no longer exist on their code host, so there is
+		// nothing to fetch.
+		if r.IsDeleted() || r.ArchivedMirror {
 			s.remove(r)
 		} else {
 			known++
@@ -259,20 +298,37 @@ func (s *updateScheduler) Set(rs ...*Repo) {
 	s.Update(rs...)
 	known := 0
 	for _, r := range rs {
-		if !r.IsDeleted() {
+		if !r.IsDeleted() && !r.ArchivedMirror {
 			known++
 		}
 	}
 	schedKnownRepos.Set(float64(known))
 }
 
+// Remove removes the given repos from the schedule, e.g. because another
+// replica schedules them.
+func (s *updateScheduler) Remove(rs ...*Repo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range rs {
+		s.remove(r)
+	}
+}
+
 func (s *updateScheduler) upsert(r *Repo) {
 	repo := configuredRepo2FromRepo(r)
 
-	updated := s.schedule.upsert(repo)
-	log15.Debug("scheduler.schedule.upserted", "repo", r.Name, "updated", updated)
+	updated, restored := s.schedule.upsert(repo)
+	log15.Debug("scheduler.schedule.upserted", "repo", r.Name, "updated", updated, "restored", restored)
 
-	updated = s.updateQueue.enqueue(repo, priorityLow)
+	// Repos with a restored schedule are fetched when they are due, so that
+	// restarts don't cause a fetch of every repo.
+	if restored {
+		return
+	}
+
+	updated = s.enqueue(repo, priorityLow)
 	log15.Debug("scheduler.updateQueue.enqueued", "repo", r.Name, "updated", updated)
 }
 
@@ -286,6 +342,8 @@ func (s *updateScheduler) remove(r *Repo) {
 	if s.updateQueue.remove(repo, false) {
 		log15.Debug("scheduler.updateQueue.removed", "repo", r.Name)
 	}
+
+	s.submodules.remove(repo.ID)
 }
 
 func configuredRepo2FromRepo(r *Repo) *configuredRepo2 {
@@ -318,13 +376,15 @@ func (s *updateScheduler) updateSource(source string, newList sourceRepoMap) {
 			s.schedule.remove(repo)
 			updating := false // don't immediately remove repos that are already updating; they will automatically get removed when the update finishes
 			s.updateQueue.remove(repo, updating)
+			s.submodules.remove(repo.ID)
 		}
 	}
 
 	// Schedule enabled repos.
 	for _, updatedRepo := range newList {
-		s.schedule.upsert(updatedRepo)
-		s.updateQueue.enqueue(updatedRepo, priorityLow)
+		if _, restored := s.schedule.upsert(updatedRepo); !restored {
+			s.enqueue(updatedRepo, priorityLow)
+		}
 	}
 
 	s.sourceRepos[source] = newList
@@ -342,7 +402,20 @@ func (s *updateScheduler) UpdateOnce(id uint32, name api.RepoName, url string) {
 		URL:  url,
 	}
 	schedManualFetch.Inc()
-	s.updateQueue.enqueue(repo, priorityHigh)
+	s.enqueue(repo, priorityHigh)
+}
+
+// PriorityUpdateOnce is like UpdateOnce, but the update is enqueued ahead of
+// all scheduled and on-demand updates. It is meant for updates that a user
+// explicitly requested.
+func (s *updateScheduler) PriorityUpdateOnce(id uint32, name api.RepoName, url string) {
+	repo := &configuredRepo2{
+		ID:   id,
+		Name: name,
+		URL:  url,
+	}
+	schedPriorityFetch.Inc()
+	s.enqueue(repo, priorityUser)
 }
 
 // DebugDump returns the state of the update scheduler for debugging.
@@ -351,6 +424,7 @@ func (s *updateScheduler) DebugDump() interface{} {
 		UpdateQueue []*repoUpdate
 		Schedule    []*scheduledRepoUpdate
 		SourceRepos map[string][]configuredRepo2
+		Persistence schedulePersistence
 	}{
 		SourceRepos: map[string][]configuredRepo2{},
 	}
@@ -378,6 +452,7 @@ func (s *updateScheduler) DebugDump() interface{} {
 		updateCopy := *update
 		schedule.heap[i] = &updateCopy
 	}
+	data.Persistence = s.schedule.persistence()
 	s.schedule.mu.Unlock()
 
 	for len(schedule.heap) > 0 {
@@ -433,9 +508,25 @@ func (s *updateScheduler) ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerI
 	}
 	s.updateQueue.mu.Unlock()
 
+	result.Submodules, result.Supermodules = s.submodules.edges(id)
+
 	return &result
 }
 
+// QueuedCount returns the number of repos in the update queue that are
+// waiting to be updated.
+func (s *updateScheduler) QueuedCount() (queued int) {
+	s.updateQueue.mu.Lock()
+	defer s.updateQueue.mu.Unlock()
+
+	for _, update := range s.updateQueue.heap {
+		if !update.Updating {
+			queued++
+		}
+	}
+	return queued
+}
+
 // updateQueue is a priority queue of repos to update.
 // A repo can't have more than one location in the queue.
 type updateQueue struct {
@@ -454,9 +545,18 @@ type updateQueue struct {
 
 type priority int
 
+// The priorities are the lanes of the queue: updates are dequeued from the
+// highest priority lane first.
 const (
+	// priorityLow is for scheduled updates and updates of new repos.
 	priorityLow priority = iota
+	// priorityHigh is for on-demand updates, e.g. of repos that are searched
+	// or viewed but not cloned yet.
 	priorityHigh
+	// priorityUser is for updates that a user explicitly requested, e.g. by
+	// refreshing a repo in the UI, so that they don't wait for the on-demand
+	// updates.
+	priorityUser
 )
 
 // repoUpdate is a repository that has been queued for an update.
@@ -619,6 +719,16 @@ type schedule struct {
 	// timer sends a value on the wakeup channel when it is time
 	timer  *time.Timer
 	wakeup chan struct{}
+
+	// restored holds the persisted schedule of repos that have not been
+	// inserted into the schedule since it was loaded from the store.
+	restored map[uint32]*ScheduleState
+
+	// dirty is the set of repos whose schedule changed since it was last
+	// persisted. dirtySince is the time of the oldest of these changes.
+	dirty       map[uint32]struct{}
+	dirtySince  time.Time
+	persistedAt time.Time
 }
 
 // scheduledRepoUpdate is the update schedule for a single repo.
@@ -627,10 +737,14 @@ type scheduledRepoUpdate struct {
 	Interval time.Duration    // how regularly the repo is updated
 	Due      time.Time        // the next time that the repo will be enqueued for a update
 	Index    int              `json:"-"` // the index in the heap
+
+	ErrorCount int // the number of consecutive failed updates
 }
 
-// upsert inserts or updates a repo in the schedule.
-func (s *schedule) upsert(repo *configuredRepo2) (updated bool) {
+// upsert inserts or updates a repo in the schedule. A repo that is inserted
+// uses the schedule loaded from the store if there is one, in which case
+// restored is true.
+func (s *schedule) upsert(repo *configuredRepo2) (updated, restored bool) {
 	if repo.ID == 0 {
 		panic("repo.id is zero")
 	}
@@ -640,18 +754,29 @@ func (s *schedule) upsert(repo *configuredRepo2) (updated bool) {
 
 	if update := s.index[repo.ID]; update != nil {
 		update.Repo = repo
-		return true
+		return true, false
 	}
 
-	heap.Push(s, &scheduledRepoUpdate{
+	update := &scheduledRepoUpdate{
 		Repo:     repo,
 		Interval: minDelay,
 		Due:      timeNow().Add(minDelay),
-	})
+	}
+	if st := s.restored[repo.ID]; st != nil {
+		delete(s.restored, repo.ID)
+		update.Interval = st.Interval
+		update.Due = st.Due
+		update.ErrorCount = st.ErrorCount
+		restored = true
+	} else {
+		s.markDirty(repo.ID)
+	}
+
+	heap.Push(s, update)
 
 	s.rescheduleTimer()
 
-	return false
+	return false, restored
 }
 
 // updateInterval updates the update interval of a repo in the schedule.
@@ -673,12 +798,35 @@ func (s *schedule) updateInterval(repo *configuredRepo2, interval time.Duration)
 		}
 		update.Due = timeNow().Add(update.Interval)
 		log15.Debug("updated repo", "repo", repo.Name, "due", update.Due.Sub(timeNow()))
+		s.markDirty(repo.ID)
 		heap.Fix(s, update.Index)
 		s.rescheduleTimer()
 	}
 	s.mu.Unlock()
 }
 
+// updateErrorCount increments the number of consecutive failed updates of a
+// repo if failed is true, and resets it otherwise. It does nothing if the repo
+// is not in the schedule.
+func (s *schedule) updateErrorCount(repo *configuredRepo2, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update := s.index[repo.ID]
+	if update == nil {
+		return
+	}
+
+	errorCount := 0
+	if failed {
+		errorCount = update.ErrorCount + 1
+	}
+	if errorCount != update.ErrorCount {
+		update.ErrorCount = errorCount
+		s.markDirty(repo.ID)
+	}
+}
+
 // remove removes a repo from the schedule.
 func (s *schedule) remove(repo *configuredRepo2) (removed bool) {
 	if repo.ID == 0 {
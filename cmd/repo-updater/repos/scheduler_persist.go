@@ -0,0 +1,164 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// schedulePersistInterval is how often changes to the schedule are
+	// written to the store.
+	schedulePersistInterval = 30 * time.Second
+
+	// schedulePersistBatchSize is the maximum number of schedule states that
+	// are written to the store at once.
+	schedulePersistBatchSize = 1000
+
+	// schedulePersistShutdownTimeout is how long the last write of the
+	// schedule, when RunSchedulePersister stops, may take.
+	schedulePersistShutdownTimeout = 10 * time.Second
+)
+
+// LoadScheduleStates loads the schedule persisted in the store, so that repos
+// added to the scheduler afterwards keep the interval, due time and error
+// count they had before repo-updater restarted.
+func (s *updateScheduler) LoadScheduleStates(ctx context.Context, store Store) error {
+	states, err := store.ListScheduleStates(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.schedule.mu.Lock()
+	defer s.schedule.mu.Unlock()
+
+	for _, st := range states {
+		s.schedule.restored[st.RepoID] = st
+	}
+
+	log15.Debug("scheduler.loaded-schedule-states", "count", len(states))
+	return nil
+}
+
+// RunSchedulePersister periodically writes the changes to the schedule of the
+// given scheduler to the store until ctx is canceled. The changes made since
+// the last write are written once more before it returns, so that they
+// survive restarts.
+func RunSchedulePersister(ctx context.Context, scheduler *updateScheduler, store Store) {
+	for {
+		select {
+		case <-time.After(schedulePersistInterval):
+		case <-ctx.Done():
+			// ctx is done, so give the last write a context of its own.
+			ctx, cancel := context.WithTimeout(context.Background(), schedulePersistShutdownTimeout)
+			defer cancel()
+			if err := scheduler.persist(ctx, store); err != nil {
+				schedPersistError.Inc()
+				log15.Error("error persisting repo update schedule on shutdown", "err", err)
+			}
+			return
+		}
+
+		if err := scheduler.persist(ctx, store); err != nil {
+			schedPersistError.Inc()
+			log15.Error("error persisting repo update schedule", "err", err)
+		}
+	}
+}
+
+// persist writes the schedule of all repos that changed since it was last
+// persisted to the store, in batches.
+func (s *updateScheduler) persist(ctx context.Context, store Store) error {
+	states, since := s.schedule.takeDirty()
+
+	for i := 0; i < len(states); i += schedulePersistBatchSize {
+		j := i + schedulePersistBatchSize
+		if j > len(states) {
+			j = len(states)
+		}
+
+		if err := store.UpsertScheduleStates(ctx, states[i:j]...); err != nil {
+			// Retry the states that were not written the next time.
+			s.schedule.restoreDirty(states[i:], since)
+			return err
+		}
+	}
+
+	s.schedule.mu.Lock()
+	s.schedule.persistedAt = timeNow()
+	s.schedule.mu.Unlock()
+
+	return nil
+}
+
+// schedulePersistence describes how far the persisted schedule lags behind
+// the schedule in memory.
+type schedulePersistence struct {
+	PersistedAt time.Time     // the last time that the schedule was persisted
+	Unpersisted int           // the number of repos with unpersisted changes
+	Lag         time.Duration // the age of the oldest unpersisted change
+}
+
+// persistence returns the persistence state of the schedule.
+// The caller must hold the lock on s.mu.
+func (s *schedule) persistence() schedulePersistence {
+	p := schedulePersistence{
+		PersistedAt: s.persistedAt,
+		Unpersisted: len(s.dirty),
+	}
+	if len(s.dirty) > 0 {
+		p.Lag = timeNow().Sub(s.dirtySince)
+	}
+	return p
+}
+
+// markDirty records that the schedule of a repo changed.
+// The caller must hold the lock on s.mu.
+func (s *schedule) markDirty(id uint32) {
+	if len(s.dirty) == 0 {
+		s.dirtySince = timeNow()
+	}
+	s.dirty[id] = struct{}{}
+}
+
+// takeDirty returns the schedule states of all repos that changed since the
+// last call, along with the time of the oldest change.
+func (s *schedule) takeDirty() (states []*ScheduleState, since time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states = make([]*ScheduleState, 0, len(s.dirty))
+	for id := range s.dirty {
+		update := s.index[id]
+		if update == nil {
+			// The repo was removed from the schedule.
+			continue
+		}
+		states = append(states, &ScheduleState{
+			RepoID:     id,
+			Interval:   update.Interval,
+			Due:        update.Due,
+			ErrorCount: update.ErrorCount,
+		})
+	}
+
+	since = s.dirtySince
+	s.dirty = make(map[uint32]struct{})
+	return states, since
+}
+
+// restoreDirty marks the given states as changed again after they failed to
+// be persisted. Repos that changed again in the meantime are persisted with
+// their current schedule.
+func (s *schedule) restoreDirty(states []*ScheduleState, since time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.dirty) == 0 || since.Before(s.dirtySince) {
+		s.dirtySince = since
+	}
+	for _, st := range states {
+		s.dirty[st.RepoID] = struct{}{}
+	}
+}
@@ -0,0 +1,109 @@
+package repos
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/pkg/errors"
+)
+
+func TestUpdateScheduler_persist(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	ctx := context.Background()
+
+	store := new(FakeStore)
+	rs := Repos{{Name: "a"}, {Name: "b"}}
+	if err := store.UpsertRepos(ctx, rs...); err != nil {
+		t.Fatal(err)
+	}
+	a, b := configuredRepo2FromRepo(rs[0]), configuredRepo2FromRepo(rs[1])
+
+	s := NewUpdateScheduler()
+	s.Update(rs...)
+	s.schedule.updateInterval(a, time.Hour)
+	s.schedule.updateErrorCount(b, true)
+
+	mockTime(defaultTime.Add(time.Minute))
+
+	if have, want := s.schedule.persistence(), (schedulePersistence{Unpersisted: 2, Lag: time.Minute}); have != want {
+		t.Fatalf("have persistence %+v, want %+v", have, want)
+	}
+
+	if err := s.persist(ctx, store); err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := s.schedule.persistence(), (schedulePersistence{PersistedAt: defaultTime.Add(time.Minute)}); have != want {
+		t.Fatalf("have persistence %+v, want %+v", have, want)
+	}
+
+	want := []*ScheduleState{
+		{RepoID: a.ID, Interval: time.Hour, Due: defaultTime.Add(time.Hour)},
+		{RepoID: b.ID, Interval: minDelay, Due: defaultTime.Add(minDelay), ErrorCount: 1},
+	}
+	have, err := store.ListScheduleStates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("\nexpected schedule states\n%s\ngot\n%s", spew.Sdump(want), spew.Sdump(have))
+	}
+
+	// A new scheduler restores the persisted schedule and doesn't enqueue
+	// the restored repos right away.
+	s = NewUpdateScheduler()
+	if err := s.LoadScheduleStates(ctx, store); err != nil {
+		t.Fatal(err)
+	}
+	s.Update(rs...)
+
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: b, Interval: minDelay, Due: defaultTime.Add(minDelay), ErrorCount: 1},
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Hour)},
+	})
+	verifyQueue(t, s, nil)
+}
+
+func TestUpdateScheduler_persistError(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	ctx := context.Background()
+
+	store := new(FakeStore)
+	rs := Repos{{Name: "a"}}
+	if err := store.UpsertRepos(ctx, rs...); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewUpdateScheduler()
+	s.Update(rs...)
+
+	store.UpsertScheduleStatesError = errors.New("boom")
+	if err := s.persist(ctx, store); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// The changes that failed to be persisted are retried.
+	if have, want := s.schedule.persistence(), (schedulePersistence{Unpersisted: 1}); have != want {
+		t.Fatalf("have persistence %+v, want %+v", have, want)
+	}
+
+	store.UpsertScheduleStatesError = nil
+	if err := s.persist(ctx, store); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := store.ListScheduleStates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("expected 1 schedule state, got %d", len(states))
+	}
+}
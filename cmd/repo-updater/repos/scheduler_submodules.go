@@ -0,0 +1,218 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// submoduleGraph records which scheduled repos are submodules of which other
+// scheduled repos, so that the scheduler can fetch the submodules of a repo
+// before (or together with) the repo itself. Without this, a supermodule is
+// often fetched with a new submodule commit that its submodule's clone does
+// not have yet.
+//
+// The graph of a repo is recorded when the repo is fetched with new commits,
+// from the .gitmodules file of its default branch. Submodules that are not
+// scheduled repos are ignored.
+type submoduleGraph struct {
+	mu sync.Mutex
+
+	// submodules maps the ID of a supermodule to its submodules.
+	submodules map[uint32][]*configuredRepo2
+}
+
+func newSubmoduleGraph() *submoduleGraph {
+	return &submoduleGraph{submodules: make(map[uint32][]*configuredRepo2)}
+}
+
+// set records the submodules of the repo with the given ID.
+func (g *submoduleGraph) set(id uint32, submodules []*configuredRepo2) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(submodules) == 0 {
+		delete(g.submodules, id)
+	} else {
+		g.submodules[id] = submodules
+	}
+}
+
+// remove removes the repo with the given ID and its submodules from the
+// graph. It remains a submodule of other repos until they are fetched again.
+func (g *submoduleGraph) remove(id uint32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.submodules, id)
+}
+
+// dependencies returns the submodules of the repo with the given ID and,
+// recursively, their submodules, deepest first. The repo itself is not
+// returned, even if it is its own (indirect) submodule.
+func (g *submoduleGraph) dependencies(id uint32) []*configuredRepo2 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.submodules[id]) == 0 {
+		return nil
+	}
+
+	var deps []*configuredRepo2
+	visited := map[uint32]bool{id: true}
+	var visit func(id uint32)
+	visit = func(id uint32) {
+		for _, sm := range g.submodules[id] {
+			if visited[sm.ID] {
+				continue
+			}
+			visited[sm.ID] = true
+			visit(sm.ID)
+			deps = append(deps, sm)
+		}
+	}
+	visit(id)
+	return deps
+}
+
+// edges returns the IDs of the direct submodules and supermodules of the repo
+// with the given ID, in ascending order.
+func (g *submoduleGraph) edges(id uint32) (submodules, supermodules []uint32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, sm := range g.submodules[id] {
+		submodules = append(submodules, sm.ID)
+	}
+	for superID, sms := range g.submodules {
+		for _, sm := range sms {
+			if sm.ID == id {
+				supermodules = append(supermodules, superID)
+				break
+			}
+		}
+	}
+	sort.Slice(submodules, func(i, j int) bool { return submodules[i] < submodules[j] })
+	sort.Slice(supermodules, func(i, j int) bool { return supermodules[i] < supermodules[j] })
+	return submodules, supermodules
+}
+
+// enqueue adds the repo to the update queue with the given priority, after
+// its submodules. Submodules that are already in the queue with at least
+// that priority keep their position, so they are fetched together with the
+// repo rather than strictly before it.
+func (s *updateScheduler) enqueue(repo *configuredRepo2, p priority) (updated bool) {
+	for _, sm := range s.submodules.dependencies(repo.ID) {
+		s.updateQueue.enqueue(sm, p)
+	}
+	return s.updateQueue.enqueue(repo, p)
+}
+
+// listSubmodules returns the submodules declared in the .gitmodules file of
+// the default branch of the given repo.
+var listSubmodules = func(ctx context.Context, name api.RepoName) ([]gitserverprotocol.Submodule, error) {
+	return gitserver.DefaultClient.Submodules(ctx, name)
+}
+
+// updateSubmodules records the submodules of the repo, which was fetched with
+// new commits, in the submodule graph.
+func (s *updateScheduler) updateSubmodules(ctx context.Context, repo *configuredRepo2) error {
+	submodules, err := listSubmodules(ctx, repo.Name)
+	if err != nil {
+		return err
+	}
+	if len(submodules) == 0 {
+		s.submodules.set(repo.ID, nil)
+		return nil
+	}
+
+	// Index the scheduled repos by their normalized clone URL and name.
+	known := map[string]*configuredRepo2{}
+	s.schedule.mu.Lock()
+	for _, update := range s.schedule.index {
+		if key := normalizeSubmoduleURL(update.Repo.URL); key != "" {
+			known[key] = update.Repo
+		}
+		known[strings.ToLower(string(update.Repo.Name))] = update.Repo
+	}
+	s.schedule.mu.Unlock()
+
+	var deps []*configuredRepo2
+	seen := map[uint32]bool{}
+	for _, sm := range submodules {
+		target := known[normalizeSubmoduleURL(resolveSubmoduleURL(repo.URL, sm.URL))]
+		if target == nil || target.ID == repo.ID || seen[target.ID] {
+			continue
+		}
+		seen[target.ID] = true
+		deps = append(deps, target)
+	}
+	s.submodules.set(repo.ID, deps)
+	return nil
+}
+
+// resolveSubmoduleURL resolves the URL of a submodule, which git allows to be
+// relative to the URL of its superproject (e.g. "../other.git").
+func resolveSubmoduleURL(superURL, submoduleURL string) string {
+	if !strings.HasPrefix(submoduleURL, "./") && !strings.HasPrefix(submoduleURL, "../") {
+		return submoduleURL
+	}
+
+	// Relative URLs are resolved against the superproject's URL as if it were
+	// a directory, so "../other" is a sibling of the superproject.
+	if host, p, ok := splitSCPLikeURL(superURL); ok {
+		return host + ":" + strings.TrimPrefix(path.Join("/"+p, submoduleURL), "/")
+	}
+	u, err := url.Parse(superURL)
+	if err != nil {
+		return ""
+	}
+	u.Path = path.Join("/", u.Path, submoduleURL)
+	return u.String()
+}
+
+// normalizeSubmoduleURL returns the host and path of a git remote URL (e.g.
+// "github.com/foo/bar"), without credentials, port, or ".git" suffix, so that
+// the different URLs of the same repo compare equal. It returns "" if the URL
+// can't be parsed.
+func normalizeSubmoduleURL(rawurl string) string {
+	var host, p string
+	if h, sp, ok := splitSCPLikeURL(rawurl); ok {
+		host, p = h, sp
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	} else {
+		u, err := url.Parse(rawurl)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		host, p = u.Hostname(), u.Path
+	}
+
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	if host == "" || p == "" {
+		return ""
+	}
+	return strings.ToLower(host + "/" + p)
+}
+
+// splitSCPLikeURL splits an scp-like git remote URL (e.g.
+// "git@github.com:foo/bar.git") into its host (including the user) and path.
+func splitSCPLikeURL(rawurl string) (host, p string, ok bool) {
+	if strings.Contains(rawurl, "://") {
+		return "", "", false
+	}
+	i := strings.Index(rawurl, ":")
+	if i <= 0 || strings.Contains(rawurl[:i], "/") {
+		return "", "", false
+	}
+	return rawurl[:i], rawurl[i+1:], true
+}
@@ -4,6 +4,7 @@ import (
 	"container/heap"
 	"context"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -188,6 +189,41 @@ func TestUpdateQueue_enqueue(t *testing.T) {
 			},
 			expectedNotifications: 2,
 		},
+		{
+			name: "enqueue high b then user a",
+			calls: []*enqueueCall{
+				{repo: b, priority: priorityHigh},
+				{repo: a, priority: priorityUser},
+			},
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     &a,
+					Priority: priorityUser,
+					Seq:      2,
+				},
+				{
+					Repo:     &b,
+					Priority: priorityHigh,
+					Seq:      1,
+				},
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name: "enqueue high a then user a",
+			calls: []*enqueueCall{
+				{repo: a, priority: priorityHigh},
+				{repo: a, priority: priorityUser},
+			},
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     &a,
+					Priority: priorityUser,
+					Seq:      2,
+				},
+			},
+			expectedNotifications: 2,
+		},
 		{
 			name: "repo is updated if not already updating",
 			calls: []*enqueueCall{
@@ -530,6 +566,24 @@ func TestUpdateQueue_acquireNext(t *testing.T) {
 	}
 }
 
+func TestUpdateScheduler_QueuedCount(t *testing.T) {
+	s := NewUpdateScheduler()
+	if have := s.QueuedCount(); have != 0 {
+		t.Fatalf("have %d queued, want 0", have)
+	}
+
+	setupInitialQueue(s, []*repoUpdate{
+		{Repo: &configuredRepo2{ID: 1, Name: "a", URL: "a.com"}, Updating: true, Seq: 1},
+		{Repo: &configuredRepo2{ID: 2, Name: "b", URL: "b.com"}, Seq: 2},
+		{Repo: &configuredRepo2{ID: 3, Name: "c", URL: "c.com"}, Seq: 3},
+	})
+
+	// Repos that are already updating aren't waiting anymore.
+	if have := s.QueuedCount(); have != 2 {
+		t.Fatalf("have %d queued, want 2", have)
+	}
+}
+
 func setupInitialQueue(s *updateScheduler, initialQueue []*repoUpdate) {
 	for _, update := range initialQueue {
 		heap.Push(s.updateQueue, update)
@@ -1157,6 +1211,8 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 		finalQueue             []*repoUpdate
 		timeAfterFuncDelays    []time.Duration
 		expectedNotifications  func(s *updateScheduler) []chan struct{}
+		indexerNotifications   []api.RepoName
+		lastCommitRecordings   []api.RepoName
 	}{
 		{
 			name: "empty queue",
@@ -1218,6 +1274,40 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 				return []chan struct{}{s.schedule.wakeup}
 			},
 		},
+		{
+			name:                   "indexer notified of new commits",
+			gitMaxConcurrentClones: 1,
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+				{Repo: b, Seq: 2},
+				{Repo: c, Seq: 3},
+			},
+			mockRequestRepoUpdates: []*mockRequestRepoUpdate{
+				{
+					repo: a,
+					resp: &gitserverprotocol.RepoUpdateResponse{
+						Cloned:      true,
+						LastFetched: timePtr(defaultTime),
+						LastChanged: timePtr(defaultTime),
+					},
+				},
+				{
+					repo: b,
+					resp: &gitserverprotocol.RepoUpdateResponse{CloneInProgress: true},
+				},
+				{
+					repo: c,
+					resp: &gitserverprotocol.RepoUpdateResponse{
+						Cloned:      true,
+						LastFetched: timePtr(defaultTime),
+						LastChanged: timePtr(defaultTime),
+						Error:       "fetch failed",
+					},
+				},
+			},
+			indexerNotifications: []api.RepoName{"a", "b"},
+			lastCommitRecordings: []api.RepoName{"a"},
+		},
 	}
 
 	for _, test := range tests {
@@ -1254,6 +1344,35 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 			}
 			defer func() { requestRepoUpdate = nil }()
 
+			var (
+				indexerNotificationsMu sync.Mutex
+				indexerNotifications   []api.RepoName
+			)
+			notifyIndexer = func(ctx context.Context, name api.RepoName) error {
+				indexerNotificationsMu.Lock()
+				defer indexerNotificationsMu.Unlock()
+				indexerNotifications = append(indexerNotifications, name)
+				return nil
+			}
+			defer func() { notifyIndexer = nil }()
+
+			var (
+				lastCommitRecordingsMu sync.Mutex
+				lastCommitRecordings   []api.RepoName
+			)
+			recordLastCommit = func(ctx context.Context, name api.RepoName) error {
+				lastCommitRecordingsMu.Lock()
+				defer lastCommitRecordingsMu.Unlock()
+				lastCommitRecordings = append(lastCommitRecordings, name)
+				return nil
+			}
+			defer func() { recordLastCommit = nil }()
+
+			listSubmodules = func(ctx context.Context, name api.RepoName) ([]gitserverprotocol.Submodule, error) {
+				return nil, nil
+			}
+			defer func() { listSubmodules = nil }()
+
 			s := NewUpdateScheduler()
 
 			// unbuffer the channel
@@ -1286,6 +1405,18 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 			verifyQueue(t, s, test.finalQueue)
 			verifyRecording(t, s, test.timeAfterFuncDelays, test.expectedNotifications, r)
 
+			indexerNotificationsMu.Lock()
+			if !reflect.DeepEqual(indexerNotifications, test.indexerNotifications) {
+				t.Errorf("\nexpected indexer notifications\n%s\ngot\n%s", spew.Sdump(test.indexerNotifications), spew.Sdump(indexerNotifications))
+			}
+			indexerNotificationsMu.Unlock()
+
+			lastCommitRecordingsMu.Lock()
+			if !reflect.DeepEqual(lastCommitRecordings, test.lastCommitRecordings) {
+				t.Errorf("\nexpected last commit recordings\n%s\ngot\n%s", spew.Sdump(test.lastCommitRecordings), spew.Sdump(lastCommitRecordings))
+			}
+			lastCommitRecordingsMu.Unlock()
+
 			// Cancel the context.
 			cancel()
 
@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// errShardNearlyFull is returned by requestRepoUpdate when a repo was not
+// cloned because its gitserver is nearly out of disk space.
+var errShardNearlyFull = errors.New("gitserver is nearly full")
+
+// shardCapacityMaxAge is how long the disk usage of the gitservers is cached
+// by shardCapacity.
+const shardCapacityMaxAge = time.Minute
+
+// defaultShardCapacity is the shardCapacity used by requestRepoUpdate.
+var defaultShardCapacity = &shardCapacity{diskInfo: gitserver.DefaultClient.DiskInfo}
+
+// shardCapacity caches the disk usage of all gitservers, so that the
+// scheduler can avoid cloning repos onto gitservers that are nearly full
+// without asking them before every update.
+type shardCapacity struct {
+	diskInfo func(context.Context) (map[string]*gitserverprotocol.DiskInfo, error)
+
+	mu        sync.Mutex
+	infos     map[string]*gitserverprotocol.DiskInfo
+	fetchedAt time.Time
+	fetching  bool
+}
+
+// nearlyFull returns true if the gitserver at the given address is nearly
+// out of disk space. Gitservers whose disk usage is unknown are assumed to
+// have enough space. The disk usage is fetched without holding the lock, and
+// while it is being fetched, concurrent calls use the cached disk usage
+// instead of waiting for it.
+func (c *shardCapacity) nearlyFull(ctx context.Context, addr string) bool {
+	c.mu.Lock()
+	fetch := (c.infos == nil || time.Since(c.fetchedAt) > shardCapacityMaxAge) && !c.fetching
+	if fetch {
+		c.fetching = true
+	}
+	infos := c.infos
+	c.mu.Unlock()
+
+	if fetch {
+		var err error
+		if infos, err = c.diskInfo(ctx); err != nil {
+			log15.Warn("error fetching gitserver disk usage", "err", err)
+		}
+
+		c.mu.Lock()
+		// Keep the disk usage of the reachable gitservers even on error.
+		c.infos, c.fetchedAt, c.fetching = infos, time.Now(), false
+		c.mu.Unlock()
+	}
+
+	info, ok := infos[addr]
+	return ok && info.NearlyFull()
+}
+
+// repoCloned returns true if the given repo is cloned on its gitserver.
+var repoCloned = func(ctx context.Context, name api.RepoName) (bool, error) {
+	resp, err := gitserver.DefaultClient.RepoInfo(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	info, ok := resp.Results[name]
+	return ok && info.Cloned, nil
+}
@@ -0,0 +1,71 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestShardCapacity(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	c := &shardCapacity{
+		diskInfo: func(context.Context) (map[string]*gitserverprotocol.DiskInfo, error) {
+			calls++
+			return map[string]*gitserverprotocol.DiskInfo{
+				"full":  {FreeBytes: 5, TotalBytes: 100, DesiredPercentFree: 10},
+				"roomy": {FreeBytes: 50, TotalBytes: 100, DesiredPercentFree: 10},
+			}, errors.New("gitserver-3 unreachable")
+		},
+	}
+
+	for addr, want := range map[string]bool{
+		"full":    true,
+		"roomy":   false,
+		"unknown": false,
+	} {
+		if have := c.nearlyFull(ctx, addr); have != want {
+			t.Errorf("nearlyFull(%q): have %t, want %t", addr, have, want)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("disk usage fetched %d times, want 1", calls)
+	}
+}
+
+func TestShardCapacity_ConcurrentFetch(t *testing.T) {
+	ctx := context.Background()
+
+	fetching, done := make(chan struct{}), make(chan struct{})
+	c := &shardCapacity{
+		diskInfo: func(context.Context) (map[string]*gitserverprotocol.DiskInfo, error) {
+			close(fetching)
+			<-done
+			return map[string]*gitserverprotocol.DiskInfo{
+				"full": {FreeBytes: 5, TotalBytes: 100, DesiredPercentFree: 10},
+			}, nil
+		},
+	}
+
+	result := make(chan bool)
+	go func() { result <- c.nearlyFull(ctx, "full") }()
+	<-fetching
+
+	// The disk usage is still being fetched, so it's unknown instead of
+	// blocking the call.
+	if c.nearlyFull(ctx, "full") {
+		t.Error("nearlyFull during fetch: have true, want false")
+	}
+
+	close(done)
+	if !<-result {
+		t.Error("nearlyFull after fetch: have false, want true")
+	}
+	if !c.nearlyFull(ctx, "full") {
+		t.Error("nearlyFull with cached disk usage: have false, want true")
+	}
+}
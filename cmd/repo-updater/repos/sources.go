@@ -67,6 +67,8 @@ func NewSource(svc *ExternalService, cf *httpcli.Factory) (Source, error) {
 		return NewPhabricatorSource(svc, cf)
 	case "awscodecommit":
 		return NewAWSCodeCommitSource(svc, cf)
+	case "manifest":
+		return NewManifestSource(svc, cf)
 	case "other":
 		return NewOtherSource(svc, cf)
 	default:
@@ -92,6 +94,42 @@ type ChangesetSource interface {
 	LoadChangesets(context.Context, ...*Changeset) error
 }
 
+// A ChangesetReviewSource can comment on and review Changesets on their codehost.
+type ChangesetReviewSource interface {
+	ChangesetSource
+	// CommentOnChangeset adds a comment with the given body to the Changeset.
+	CommentOnChangeset(ctx context.Context, c *Changeset, body string) error
+	// ReviewChangeset submits a review of the Changeset with the given event and body.
+	ReviewChangeset(ctx context.Context, c *Changeset, event ChangesetReviewEvent, body string) error
+}
+
+// A ChangesetCreateSource can create Changesets on their codehost.
+type ChangesetCreateSource interface {
+	ChangesetSource
+	// CreateChangeset creates a pull request of the headRef branch into the
+	// baseRef branch of the Changeset's repo with the given title and body,
+	// and sets the Changeset's ExternalID and Metadata to it.
+	CreateChangeset(ctx context.Context, c *Changeset, title, body, baseRef, headRef string) error
+}
+
+// A ChangesetCloseSource can close Changesets on their codehost.
+type ChangesetCloseSource interface {
+	ChangesetSource
+	// CloseChangeset closes the Changeset without merging it.
+	CloseChangeset(ctx context.Context, c *Changeset) error
+}
+
+// ChangesetReviewEvent is the kind of a review submitted with a
+// ChangesetReviewSource.
+type ChangesetReviewEvent string
+
+// Valid ChangesetReviewEvent values.
+const (
+	ChangesetReviewEventApprove        ChangesetReviewEvent = "APPROVE"
+	ChangesetReviewEventRequestChanges ChangesetReviewEvent = "REQUEST_CHANGES"
+	ChangesetReviewEventComment        ChangesetReviewEvent = "COMMENT"
+)
+
 // A SourceResult is sent by a Source over a channel for each repository it
 // yields when listing repositories
 type SourceResult struct {
@@ -134,27 +172,41 @@ func sourceErrorFormatFunc(es []error) string {
 // Sources is a list of Sources that implements the Source interface.
 type Sources []Source
 
+// maxConcurrentSourcesPerKind is the maximum number of sources of the same
+// external service kind that Sources.ListRepos lists concurrently.
+var maxConcurrentSourcesPerKind = 2
+
 // ListRepos lists all the repos of all the sources and returns the
-// aggregate result.
+// aggregate result. Each source reports its own errors in its SourceResults,
+// so a failing source doesn't prevent listing the others. Sources that were
+// interrupted by ctx being done report its error, even if they stopped
+// listing without one, so that their partial listings aren't mistaken for
+// complete ones.
 func (srcs Sources) ListRepos(ctx context.Context, results chan SourceResult) {
 	if len(srcs) == 0 {
 		return
 	}
 
-	// Group sources by external service kind so that we execute requests
-	// serially to each code host. This is to comply with abuse rate limits of GitHub,
-	// but we do it for any source to be conservative.
+	// Group sources by external service kind so that we bound the number of
+	// concurrent requests to each code host. This is to comply with abuse rate
+	// limits of GitHub, but we do it for any source to be conservative.
 	// See https://developer.github.com/v3/guides/best-practices-for-integrators/#dealing-with-abuse-rate-limits)
 
 	var wg sync.WaitGroup
 	for _, sources := range group(srcs) {
-		wg.Add(1)
-		go func(sources Sources) {
-			defer wg.Done()
-			for _, src := range sources {
+		sem := make(chan struct{}, maxConcurrentSourcesPerKind)
+		for _, src := range sources {
+			wg.Add(1)
+			go func(src Source) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
 				src.ListRepos(ctx, results)
-			}
-		}(sources)
+				if err := ctx.Err(); err != nil {
+					results <- SourceResult{Source: src, Err: err}
+				}
+			}(src)
+		}
 	}
 
 	wg.Wait()
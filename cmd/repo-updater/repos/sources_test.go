@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -908,3 +909,85 @@ func getAWSEnv(envVar string) string {
 	}
 	return s
 }
+
+// concurrencySource is a Source that records the maximum number of sources
+// of its kind listing repos at the same time.
+type concurrencySource struct {
+	svc       *ExternalService
+	mu        *sync.Mutex
+	active    map[string]int
+	maxActive map[string]int
+	err       error
+}
+
+func (s concurrencySource) ListRepos(ctx context.Context, results chan SourceResult) {
+	s.mu.Lock()
+	s.active[s.svc.Kind]++
+	if s.active[s.svc.Kind] > s.maxActive[s.svc.Kind] {
+		s.maxActive[s.svc.Kind] = s.active[s.svc.Kind]
+	}
+	s.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	s.active[s.svc.Kind]--
+	s.mu.Unlock()
+
+	if s.err != nil {
+		results <- SourceResult{Source: s, Err: s.err}
+	}
+}
+
+func (s concurrencySource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func TestSources_ListRepos_Concurrency(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		active    = map[string]int{}
+		maxActive = map[string]int{}
+		srcs      Sources
+	)
+
+	for i := 0; i < 6; i++ {
+		for _, kind := range []string{"GITHUB", "GITLAB"} {
+			var err error
+			if i == 0 {
+				err = fmt.Errorf("%s token expired", kind)
+			}
+			srcs = append(srcs, concurrencySource{
+				svc:       &ExternalService{ID: int64(len(srcs) + 1), Kind: kind},
+				mu:        &mu,
+				active:    active,
+				maxActive: maxActive,
+				err:       err,
+			})
+		}
+	}
+
+	results := make(chan SourceResult)
+	go func() {
+		srcs.ListRepos(context.Background(), results)
+		close(results)
+	}()
+
+	var errs []string
+	for res := range results {
+		if res.Err != nil {
+			errs = append(errs, res.Err.Error())
+		}
+	}
+	sort.Strings(errs)
+
+	if want := []string{"GITHUB token expired", "GITLAB token expired"}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("got errors %q, want %q", errs, want)
+	}
+
+	for kind, n := range maxActive {
+		if n > maxConcurrentSourcesPerKind {
+			t.Errorf("%s: %d sources listed concurrently, want at most %d", kind, n, maxConcurrentSourcesPerKind)
+		}
+	}
+}
@@ -29,6 +29,9 @@ type Store interface {
 	UpsertRepos(ctx context.Context, repos ...*Repo) error
 
 	ListAllRepoNames(context.Context) ([]api.RepoName, error)
+
+	ListScheduleStates(context.Context) ([]*ScheduleState, error)
+	UpsertScheduleStates(ctx context.Context, states ...*ScheduleState) error
 }
 
 // StoreListReposArgs is a query arguments type used by
@@ -46,6 +49,9 @@ type StoreListReposArgs struct {
 	Limit int64
 	// PerPage determines the number of repos returned on each page. Zero means it defaults to 10000.
 	PerPage int64
+	// AfterID lists only repos with an ID greater than it. Since repos are listed in ID order,
+	// it can be set to the ID of the last repo of a page to list the next one.
+	AfterID uint32
 
 	// UseOr decides between ANDing or ORing the predicates together.
 	UseOr bool
@@ -310,6 +316,30 @@ func (s DBStore) ListRepos(ctx context.Context, args StoreListReposArgs) (repos
 	)
 }
 
+// ListReposPages calls fn with successive pages of at most perPage repos that match the given
+// arguments, in ID order, until all of them have been listed or fn returns an error. Unlike
+// ListRepos, it doesn't hold all listed repos in memory at once. The Limit and AfterID fields of
+// args are ignored.
+func ListReposPages(ctx context.Context, s Store, args StoreListReposArgs, perPage int64, fn func(Repos) error) error {
+	args.Limit, args.PerPage, args.AfterID = perPage, perPage, 0
+	for {
+		page, err := s.ListRepos(ctx, args)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if int64(len(page)) < perPage {
+			return nil
+		}
+		args.AfterID = page[len(page)-1].ID
+	}
+}
+
 const listReposQueryFmtstr = `
 -- source: cmd/repo-updater/repos/store.go:DBStore.ListRepos
 SELECT
@@ -328,7 +358,8 @@ SELECT
   archived,
   fork,
   sources,
-  metadata
+  metadata,
+  archived_mirror
 FROM repo
 WHERE id > %s
 AND %s
@@ -386,6 +417,9 @@ func listReposQuery(args StoreListReposArgs) paginatedQuery {
 	}
 
 	return func(cursor, limit int64) *sqlf.Query {
+		if after := int64(args.AfterID); cursor < after {
+			cursor = after
+		}
 		return sqlf.Sprintf(
 			listReposQueryFmtstr,
 			cursor,
@@ -427,6 +461,104 @@ func listAllRepoNamesQuery(cursor, limit int64) *sqlf.Query {
 	return sqlf.Sprintf(listAllRepoNamesQueryFmtstr, cursor, limit)
 }
 
+// ListScheduleStates lists the persisted update schedule states of all repos.
+func (s DBStore) ListScheduleStates(ctx context.Context) (states []*ScheduleState, _ error) {
+	return states, s.paginate(ctx, 0, 0, listScheduleStatesQuery,
+		func(sc scanner) (last, count int64, err error) {
+			var st ScheduleState
+			if err = scanScheduleState(&st, sc); err != nil {
+				return 0, 0, err
+			}
+			states = append(states, &st)
+			return int64(st.RepoID), 1, nil
+		},
+	)
+}
+
+const listScheduleStatesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.ListScheduleStates
+SELECT
+  repo_id,
+  interval_seconds,
+  due_at,
+  error_count
+FROM repo_update_schedule
+WHERE repo_id > %s
+ORDER BY repo_id ASC LIMIT %s
+`
+
+func listScheduleStatesQuery(cursor, limit int64) *sqlf.Query {
+	return sqlf.Sprintf(listScheduleStatesQueryFmtstr, cursor, limit)
+}
+
+// UpsertScheduleStates updates or inserts the given update schedule states.
+// States of repos that don't exist anymore are ignored.
+func (s DBStore) UpsertScheduleStates(ctx context.Context, states ...*ScheduleState) error {
+	if len(states) == 0 {
+		return nil
+	}
+
+	type record struct {
+		RepoID          uint32    `json:"repo_id"`
+		IntervalSeconds int64     `json:"interval_seconds"`
+		DueAt           time.Time `json:"due_at"`
+		ErrorCount      int       `json:"error_count"`
+	}
+
+	records := make([]record, 0, len(states))
+	for _, st := range states {
+		records = append(records, record{
+			RepoID:          st.RepoID,
+			IntervalSeconds: int64(st.Interval / time.Second),
+			DueAt:           st.Due.UTC(),
+			ErrorCount:      st.ErrorCount,
+		})
+	}
+
+	batch, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(upsertScheduleStatesQueryFmtstr, string(batch))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	// Nothing to scan
+	return rows.Close()
+}
+
+const upsertScheduleStatesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.UpsertScheduleStates
+INSERT INTO repo_update_schedule (
+  repo_id,
+  interval_seconds,
+  due_at,
+  error_count,
+  updated_at
+)
+SELECT
+  batch.repo_id,
+  batch.interval_seconds,
+  batch.due_at,
+  batch.error_count,
+  now()
+FROM json_to_recordset(%s) AS batch (
+  repo_id          integer,
+  interval_seconds integer,
+  due_at           timestamptz,
+  error_count      integer
+)
+JOIN repo ON repo.id = batch.repo_id
+ON CONFLICT (repo_id) DO UPDATE
+SET
+  interval_seconds = excluded.interval_seconds,
+  due_at           = excluded.due_at,
+  error_count      = excluded.error_count,
+  updated_at       = excluded.updated_at
+`
+
 // a paginatedQuery returns a query with the given pagination
 // parameters
 type paginatedQuery func(cursor, limit int64) *sqlf.Query
@@ -528,9 +660,66 @@ func (s *DBStore) UpsertRepos(ctx context.Context, repos ...*Repo) (err error) {
 		}
 	}
 
-	return nil
+	return s.recordRepoIntroductions(ctx, append(updates, inserts...))
 }
 
+// recordRepoIntroductions records in the append-only repo_introductions table
+// which external services yield the given repos, the first time they do. The
+// author of an introduction is the user who last changed the configuration of
+// the external service.
+func (s *DBStore) recordRepoIntroductions(ctx context.Context, repos []*Repo) error {
+	type record struct {
+		RepoID            uint32 `json:"repo_id"`
+		ExternalServiceID int64  `json:"external_service_id"`
+	}
+
+	var records []record
+	for _, r := range repos {
+		for _, info := range r.Sources {
+			if info == nil {
+				continue
+			}
+			if id := info.ExternalServiceID(); id != -1 {
+				records = append(records, record{RepoID: r.ID, ExternalServiceID: id})
+			}
+		}
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	batch, err := json.Marshal(records)
+	if err != nil {
+		return errors.Wrap(err, "record-introductions")
+	}
+
+	q := sqlf.Sprintf(recordRepoIntroductionsQueryFmtstr, string(batch))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return errors.Wrap(err, "record-introductions")
+	}
+	return errors.Wrap(rows.Close(), "record-introductions")
+}
+
+const recordRepoIntroductionsQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.recordRepoIntroductions
+INSERT INTO repo_introductions (repo_id, repo_name, external_service_id, author_user_id)
+SELECT
+  repo.id,
+  repo.name,
+  batch.external_service_id,
+  (
+    SELECT author_user_id FROM external_service_config_revisions rev
+    WHERE rev.external_service_id = batch.external_service_id
+    ORDER BY rev.revision DESC
+    LIMIT 1
+  )
+FROM json_to_recordset(%s) AS batch(repo_id integer, external_service_id bigint)
+JOIN repo ON repo.id = batch.repo_id
+ON CONFLICT (repo_id, external_service_id) DO NOTHING
+`
+
 func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 	type record struct {
 		ID                  uint32          `json:"id"`
@@ -549,6 +738,7 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 		Fork                bool            `json:"fork"`
 		Sources             json.RawMessage `json:"sources"`
 		Metadata            json.RawMessage `json:"metadata"`
+		ArchivedMirror      bool            `json:"archived_mirror"`
 	}
 
 	records := make([]record, 0, len(repos))
@@ -580,6 +770,7 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			Fork:                r.Fork,
 			Sources:             sources,
 			Metadata:            metadata,
+			ArchivedMirror:      r.ArchivedMirror,
 		})
 	}
 
@@ -623,7 +814,8 @@ WITH batch AS (
       archived              boolean,
       fork                  boolean,
       sources               jsonb,
-      metadata              jsonb
+      metadata              jsonb,
+      archived_mirror       boolean
     )
   )
   WITH ORDINALITY
@@ -647,7 +839,8 @@ updated AS (
     archived              = batch.archived,
     fork                  = batch.fork,
     sources               = batch.sources,
-    metadata              = batch.metadata
+    metadata              = batch.metadata,
+    archived_mirror       = batch.archived_mirror
   FROM batch
   WHERE repo.id = batch.id
   RETURNING repo.*
@@ -668,7 +861,8 @@ SELECT
   updated.archived,
   updated.fork,
   updated.sources,
-  updated.metadata
+  updated.metadata,
+  updated.archived_mirror
 FROM updated
 LEFT JOIN batch ON batch.id = updated.id
 ORDER BY batch.ordinality
@@ -698,7 +892,8 @@ inserted AS (
     archived,
     fork,
     sources,
-    metadata
+    metadata,
+    archived_mirror
   )
   SELECT
     name,
@@ -715,7 +910,8 @@ inserted AS (
     archived,
     fork,
     sources,
-    metadata
+    metadata,
+    archived_mirror
   FROM batch
   RETURNING repo.*
 )
@@ -735,7 +931,8 @@ SELECT
   inserted.archived,
   inserted.fork,
   inserted.sources,
-  inserted.metadata
+  inserted.metadata,
+  inserted.archived_mirror
 FROM inserted
 LEFT JOIN batch ON batch.name = inserted.name
 ORDER BY batch.ordinality
@@ -832,6 +1029,7 @@ func scanRepo(r *Repo, s scanner) error {
 		&r.Fork,
 		&sources,
 		&metadata,
+		&r.ArchivedMirror,
 	)
 	if err != nil {
 		return err
@@ -855,6 +1053,8 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(awscodecommit.Repository)
 	case "gitolite":
 		r.Metadata = new(gitolite.Repo)
+	case "manifest":
+		r.Metadata = new(ManifestRepo)
 	default:
 		return nil
 	}
@@ -865,3 +1065,15 @@ func scanRepo(r *Repo, s scanner) error {
 
 	return nil
 }
+
+func scanScheduleState(st *ScheduleState, s scanner) error {
+	var intervalSeconds int64
+	err := s.Scan(
+		&st.RepoID,
+		&intervalSeconds,
+		&st.Due,
+		&st.ErrorCount,
+	)
+	st.Interval = time.Duration(intervalSeconds) * time.Second
+	return err
+}
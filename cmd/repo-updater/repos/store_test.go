@@ -2,6 +2,7 @@ package repos_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"sort"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/kylelemons/godebug/pretty"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
@@ -37,6 +39,7 @@ func TestFakeStore(t *testing.T) {
 		{"UpsertExternalServices", testStoreUpsertExternalServices},
 		{"ListRepos", testStoreListRepos},
 		{"ListRepos_Pagination", testStoreListReposPagination},
+		{"ListReposPages", testStoreListReposPages},
 		{"UpsertRepos", testStoreUpsertRepos},
 	} {
 		t.Run(tc.name, tc.test(repos.NewObservedStore(
@@ -651,6 +654,77 @@ func testStoreUpsertRepos(store repos.Store) func(*testing.T) {
 	}
 }
 
+func testStoreRecordRepoIntroductions(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+
+		store := repos.NewDBStore(tx, sql.TxOptions{})
+
+		type introduction struct {
+			RepoName          string
+			ExternalServiceID int64
+		}
+
+		introductions := func(t *testing.T) []introduction {
+			t.Helper()
+			rows, err := tx.QueryContext(ctx, "SELECT repo_name, external_service_id FROM repo_introductions ORDER BY external_service_id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			var have []introduction
+			for rows.Next() {
+				var i introduction
+				if err := rows.Scan(&i.RepoName, &i.ExternalServiceID); err != nil {
+					t.Fatal(err)
+				}
+				have = append(have, i)
+			}
+			return have
+		}
+
+		repo := &repos.Repo{
+			Name: "github.com/foo/introduced",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "introduced",
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+			Sources: map[string]*repos.SourceInfo{
+				"extsvc:github:1": {ID: "extsvc:github:1"},
+			},
+		}
+
+		if err := store.UpsertRepos(ctx, repo); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []introduction{{RepoName: "github.com/foo/introduced", ExternalServiceID: 1}}
+		if have := introductions(t); !reflect.DeepEqual(have, want) {
+			t.Fatalf("got introductions %+v, want %+v", have, want)
+		}
+
+		// Only the first time an external service yields a repo is recorded.
+		repo.Name = "github.com/foo/renamed"
+		repo.Sources["extsvc:github:2"] = &repos.SourceInfo{ID: "extsvc:github:2"}
+		if err := store.UpsertRepos(ctx, repo); err != nil {
+			t.Fatal(err)
+		}
+
+		want = append(want, introduction{RepoName: "github.com/foo/renamed", ExternalServiceID: 2})
+		if have := introductions(t); !reflect.DeepEqual(have, want) {
+			t.Fatalf("got introductions %+v, want %+v", have, want)
+		}
+	}
+}
+
 func testStoreListRepos(store repos.Store) func(*testing.T) {
 	clock := repos.NewFakeClock(time.Now(), 0)
 	now := clock.Now()
@@ -995,6 +1069,63 @@ func testStoreListReposPagination(store repos.Store) func(*testing.T) {
 	}
 }
 
+func testStoreListReposPages(store repos.Store) func(*testing.T) {
+	github := repos.Repo{
+		Name: "foo/bar",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "AAAAA==",
+			ServiceType: "github",
+			ServiceID:   "http://github.com",
+		},
+		Sources: map[string]*repos.SourceInfo{
+			"extsvc:1": {
+				ID:       "extsvc:1",
+				CloneURL: "git@github.com:foo/bar.git",
+			},
+		},
+		Metadata: new(github.Repository),
+	}
+
+	return func(t *testing.T) {
+		ctx := context.Background()
+		t.Run("", transact(ctx, store, func(t testing.TB, tx repos.Store) {
+			stored := mkRepos(7, &github)
+			if err := tx.UpsertRepos(ctx, stored...); err != nil {
+				t.Fatalf("UpsertRepos error: %s", err)
+			}
+
+			sort.Sort(stored)
+
+			for perPage := int64(1); perPage <= int64(len(stored))+1; perPage++ {
+				var listed repos.Repos
+				err := repos.ListReposPages(ctx, tx, repos.StoreListReposArgs{}, perPage, func(page repos.Repos) error {
+					if int64(len(page)) > perPage {
+						t.Errorf("perPage=%d: got page of %d repos", perPage, len(page))
+					}
+					listed = append(listed, page...)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("perPage=%d: unexpected error: %v", perPage, err)
+				}
+				if !reflect.DeepEqual(listed, stored) {
+					t.Fatalf("perPage=%d: %s", perPage, pretty.Compare(listed, stored))
+				}
+			}
+
+			boom := errors.New("boom")
+			calls := 0
+			err := repos.ListReposPages(ctx, tx, repos.StoreListReposArgs{}, 2, func(repos.Repos) error {
+				calls++
+				return boom
+			})
+			if err != boom || calls != 1 {
+				t.Errorf("got error %v after %d calls, want %v after 1 call", err, calls, boom)
+			}
+		}))
+	}
+}
+
 func testDBStoreTransact(store *repos.DBStore) func(*testing.T) {
 	return func(t *testing.T) {
 		ctx := context.Background()
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -39,6 +40,11 @@ type Syncer struct {
 	// Sourcegraph.com
 	FailFullSync bool
 
+	// ArchiveDeleted if non-nil is called on each sync. When it returns true,
+	// repos removed from their code host are kept as archived mirrors instead
+	// of being deleted.
+	ArchiveDeleted func() bool
+
 	// Synced is sent Repos that were synced by Sync (only if Synced is non-nil)
 	Synced chan Repos
 
@@ -60,7 +66,16 @@ type Syncer struct {
 	syncSignal signal
 }
 
-// Run runs the Sync at the specified interval.
+// syncShutdownTimeout is how long a Sync that is in flight when Run is
+// stopped may take to store the repos it listed before it is canceled.
+const syncShutdownTimeout = 20 * time.Second
+
+// Run runs the Sync at the specified interval until ctx is done.
+//
+// A Sync that is in flight when ctx is done is checkpointed rather than
+// abandoned: it stops listing repos, and stores the repos of the external
+// services that were listed completely while keeping the stored repos of the
+// others. It is canceled if that takes longer than syncShutdownTimeout.
 func (s *Syncer) Run(ctx context.Context, interval time.Duration) error {
 	for ctx.Err() == nil {
 		if s.PreSync != nil {
@@ -69,19 +84,51 @@ func (s *Syncer) Run(ctx context.Context, interval time.Duration) error {
 			}
 		}
 
-		if err := s.Sync(ctx); err != nil && s.Logger != nil {
+		if err := s.checkpointedSync(ctx); err != nil && s.Logger != nil {
 			s.Logger.Error("Syncer", "error", err)
 		}
 
 		select {
 		case <-time.After(interval):
 		case <-s.syncSignal.Watch():
+		case <-ctx.Done():
 		}
 	}
 
 	return ctx.Err()
 }
 
+// checkpointedSync runs a Sync whose listing of repos stops when ctx is done,
+// but whose storing of the listed repos is only canceled syncShutdownTimeout
+// later.
+func (s *Syncer) checkpointedSync(ctx context.Context) error {
+	syncCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		if s.Logger != nil {
+			s.Logger.Info("Syncer: stopping, storing the repos listed so far")
+		}
+
+		select {
+		case <-time.After(syncShutdownTimeout):
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return s.sync(syncCtx, ctx.Done())
+}
+
 // TriggerSync will run Sync as soon as the current Sync has finished running
 // or if no Sync is running.
 func (s *Syncer) TriggerSync() {
@@ -89,12 +136,31 @@ func (s *Syncer) TriggerSync() {
 }
 
 // Sync synchronizes the repositories.
-func (s *Syncer) Sync(ctx context.Context) (err error) {
-	var diff Diff
+func (s *Syncer) Sync(ctx context.Context) error {
+	return s.sync(ctx, nil)
+}
+
+// sync synchronizes the repositories. When stop is closed, listing repos
+// stops and the external services that weren't listed completely are handled
+// like ones that failed to list their repos.
+func (s *Syncer) sync(ctx context.Context, stop <-chan struct{}) (err error) {
+	var (
+		diff      Diff
+		sourceErr error
+	)
 
 	ctx, save := s.observe(ctx, "Syncer.Sync", "")
 	defer save(&diff, &err)
-	defer s.setOrResetLastSyncErr(&err)
+	defer func() {
+		// The errors of the external services that failed to list their
+		// repos are only reported by LastSyncError if the sync succeeded
+		// otherwise.
+		if err == nil {
+			s.setOrResetLastSyncErr(&sourceErr)
+		} else {
+			s.setOrResetLastSyncErr(&err)
+		}
+	}()
 
 	if s.FailFullSync {
 		return errors.New("Syncer is not enabled")
@@ -110,9 +176,13 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 		}
 	}
 
-	var sourced Repos
-	if sourced, err = s.sourced(ctx, streamingInserter); err != nil {
-		return errors.Wrap(err, "syncer.sync.sourced")
+	// External services that fail to list their repos don't prevent syncing
+	// the others. Their errors are reported as the LastSyncError once the
+	// sync is done, but don't fail it.
+	sourced, sourceErr := s.sourced(ctx, stop, streamingInserter)
+	failed, ok := failedExternalServices(sourceErr)
+	if !ok {
+		return errors.Wrap(sourceErr, "syncer.sync.sourced")
 	}
 
 	store := s.Store
@@ -130,6 +200,8 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 		return errors.Wrap(err, "syncer.sync.store.list-repos")
 	}
 
+	sourced = keepFailedSources(sourced, stored, failed)
+
 	diff = NewDiff(sourced, stored)
 	upserts := s.upserts(diff)
 
@@ -144,6 +216,80 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 	return nil
 }
 
+// failedExternalServices returns the URNs of the external services that
+// failed to list their repos, given the error returned by Syncer.sourced. It
+// returns false if the error isn't made of SourceErrors of known external
+// services, in which case the sync can't proceed.
+func failedExternalServices(err error) (map[string]bool, bool) {
+	failed := map[string]bool{}
+	if err == nil {
+		return failed, true
+	}
+
+	multiErr, ok := err.(*multierror.Error)
+	if !ok {
+		return nil, false
+	}
+
+	for _, e := range multiErr.Errors {
+		sourceErr, ok := e.(*SourceError)
+		if !ok || sourceErr.ExtSvc == nil {
+			return nil, false
+		}
+		failed[sourceErr.ExtSvc.URN()] = true
+	}
+
+	return failed, true
+}
+
+// keepFailedSources adds to sourced the stored repos yielded by the given
+// failed external services, so that they are neither deleted nor lose those
+// sources because their external service couldn't be listed in this sync.
+func keepFailedSources(sourced, stored Repos, failed map[string]bool) Repos {
+	if len(failed) == 0 {
+		return sourced
+	}
+
+	byID := make(map[api.ExternalRepoSpec]*Repo, len(sourced))
+	for _, r := range sourced {
+		byID[r.ExternalRepo] = r
+	}
+
+	for _, old := range stored {
+		if !old.ExternalRepo.IsSet() {
+			continue
+		}
+
+		kept := map[string]*SourceInfo{}
+		for urn, info := range old.Sources {
+			if failed[urn] {
+				kept[urn] = info
+			}
+		}
+
+		if len(kept) == 0 {
+			continue
+		}
+
+		if r := byID[old.ExternalRepo]; r != nil {
+			if r.Sources == nil {
+				r.Sources = map[string]*SourceInfo{}
+			}
+			for urn, info := range kept {
+				r.Sources[urn] = info
+			}
+			continue
+		}
+
+		r := old.Clone()
+		r.Sources = kept
+		byID[r.ExternalRepo] = r
+		sourced = append(sourced, r)
+	}
+
+	return sourced
+}
+
 // SyncSubset runs the syncer on a subset of the stored repositories. It will
 // only sync the repositories with the same name or external service spec as
 // sourcedSubset repositories.
@@ -220,8 +366,16 @@ func (s *Syncer) upserts(diff Diff) []*Repo {
 	now := s.Now()
 	upserts := make([]*Repo, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Modified))
 
+	archive := s.ArchiveDeleted != nil && s.ArchiveDeleted()
 	for _, repo := range diff.Deleted {
-		repo.UpdatedAt, repo.DeletedAt = now, now
+		if archive {
+			if repo.ArchivedMirror {
+				continue // already archived
+			}
+			repo.UpdatedAt, repo.ArchivedMirror = now, true
+		} else {
+			repo.UpdatedAt, repo.DeletedAt = now, now
+		}
 		repo.Sources = map[string]*SourceInfo{}
 		repo.Enabled = true
 		upserts = append(upserts, repo)
@@ -358,21 +512,38 @@ func merge(o, n *Repo) {
 	o.Update(n)
 }
 
-func (s *Syncer) sourced(ctx context.Context, observe ...func(*Repo)) ([]*Repo, error) {
+// sourced lists the repos of all external services, until stop is closed
+// (if it is non-nil). The errors of the external services that couldn't be
+// listed are returned as SourceErrors in a *multierror.Error, alongside the
+// repos of all the others.
+func (s *Syncer) sourced(ctx context.Context, stop <-chan struct{}, observe ...func(*Repo)) ([]*Repo, error) {
 	svcs, err := s.Store.ListExternalServices(ctx, StoreListExternalServicesArgs{})
 	if err != nil {
 		return nil, err
 	}
 
-	srcs, err := s.Sourcer(svcs...)
-	if err != nil {
-		return nil, err
-	}
+	// Sources that can't be created are skipped by the Sourcer and reported
+	// along with the ones that fail to list their repos.
+	srcs, srcErr := s.Sourcer(svcs...)
 
 	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
 	defer cancel()
 
-	return listAll(ctx, srcs, observe...)
+	if stop != nil {
+		go func() {
+			select {
+			case <-stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	sourced, err := listAll(ctx, srcs, observe...)
+	if srcErr == nil {
+		return sourced, err
+	}
+	return sourced, multierror.Append(srcErr, err)
 }
 
 func (s *Syncer) makeNewRepoInserter(ctx context.Context) (func(*Repo), error) {
@@ -401,14 +572,16 @@ func (s *Syncer) makeNewRepoInserter(ctx context.Context) (func(*Repo), error) {
 }
 
 func (s *Syncer) storedExternalIDs(ctx context.Context) (map[api.ExternalRepoSpec]struct{}, error) {
-	stored, err := s.Store.ListRepos(ctx, StoreListReposArgs{})
+	ids := make(map[api.ExternalRepoSpec]struct{})
+	err := ListReposPages(ctx, s.Store, StoreListReposArgs{}, 10000, func(page Repos) error {
+		for _, r := range page {
+			ids[r.ExternalRepo] = struct{}{}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "syncer.storedExternalIDs")
 	}
-	ids := make(map[api.ExternalRepoSpec]struct{}, len(stored))
-	for _, r := range stored {
-		ids[r.ExternalRepo] = struct{}{}
-	}
 	return ids, nil
 }
 
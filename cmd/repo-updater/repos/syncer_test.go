@@ -34,6 +34,8 @@ func TestSyncer_Sync(t *testing.T) {
 		sourcer repos.Sourcer
 		store   repos.Store
 		err     string
+		// lastSyncErr is the wanted LastSyncError, if it differs from err.
+		lastSyncErr string
 	}{
 		{
 			name:    "sourcer error aborts sync",
@@ -42,13 +44,14 @@ func TestSyncer_Sync(t *testing.T) {
 			err:     "syncer.sync.sourced: 1 error occurred:\n\t* boom\n\n",
 		},
 		{
-			name: "sources partial errors aborts sync",
+			name: "sources partial errors are reported without aborting sync",
 			sourcer: repos.NewFakeSourcer(nil,
 				repos.NewFakeSource(&github, nil),
 				repos.NewFakeSource(&gitlab, errors.New("boom")),
 			),
-			store: new(repos.FakeStore),
-			err:   "syncer.sync.sourced: 1 error occurred:\n\t* boom\n\n",
+			store:       new(repos.FakeStore),
+			err:         "<nil>",
+			lastSyncErr: "1 error occurred:\n\t* boom\n\n",
 		},
 		{
 			name:    "store list error aborts sync",
@@ -80,13 +83,116 @@ func TestSyncer_Sync(t *testing.T) {
 				t.Errorf("have error %q, want %q", have, want)
 			}
 
-			if have, want := fmt.Sprint(syncer.LastSyncError()), tc.err; have != want {
+			wantLastSyncErr := tc.err
+			if tc.lastSyncErr != "" {
+				wantLastSyncErr = tc.lastSyncErr
+			}
+			if have, want := fmt.Sprint(syncer.LastSyncError()), wantLastSyncErr; have != want {
 				t.Errorf("have LastSyncError %q, want %q", have, want)
 			}
 		})
 	}
 }
 
+func TestSyncer_Run_checkpoint(t *testing.T) {
+	t.Parallel()
+
+	github := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+	gitlab := &repos.ExternalService{ID: 2, Kind: "GITLAB"}
+
+	repo := func(svc *repos.ExternalService, name string) *repos.Repo {
+		return (&repos.Repo{
+			Name:    name,
+			Enabled: true,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceID:   "https://" + strings.ToLower(svc.Kind) + ".com/",
+				ServiceType: strings.ToLower(svc.Kind),
+			},
+		}).With(repos.Opt.RepoSources(svc.URN()))
+	}
+
+	ctx := context.Background()
+	store := new(repos.FakeStore)
+	if err := store.UpsertRepos(ctx, repo(gitlab, "gitlab.com/org/stored")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The GitHub source lists all its repos, but the listing of the GitLab
+	// source is interrupted by the shutdown.
+	listed := make(chan struct{})
+	interrupted := &interruptedSource{
+		svc:     gitlab,
+		repo:    repo(gitlab, "gitlab.com/org/listed"),
+		started: make(chan struct{}),
+	}
+	complete := &notifyingSource{
+		Source: repos.NewFakeSource(github, nil, repo(github, "github.com/org/foo")),
+		done:   listed,
+	}
+
+	clock := repos.NewFakeClock(time.Now(), time.Second)
+	syncer := &repos.Syncer{
+		Store:            store,
+		Sourcer:          repos.NewFakeSourcer(nil, complete, interrupted),
+		DisableStreaming: true,
+		Now:              clock.Now,
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-listed
+		<-interrupted.started
+		cancel()
+	}()
+
+	if err := syncer.Run(runCtx, time.Hour); err != context.Canceled {
+		t.Fatalf("have error %v, want %v", err, context.Canceled)
+	}
+
+	stored, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := stored.Names()
+	sort.Strings(have)
+	// The stored repos of the interrupted source are kept.
+	want := []string{"github.com/org/foo", "gitlab.com/org/listed", "gitlab.com/org/stored"}
+	if !cmp.Equal(have, want) {
+		t.Fatalf("stored repos:\n%s", cmp.Diff(want, have))
+	}
+}
+
+// notifyingSource closes done once its Source listed all its repos.
+type notifyingSource struct {
+	repos.Source
+	done chan struct{}
+}
+
+func (s *notifyingSource) ListRepos(ctx context.Context, results chan repos.SourceResult) {
+	s.Source.ListRepos(ctx, results)
+	close(s.done)
+}
+
+// interruptedSource lists a repo and then waits for ctx to be done, like a
+// source with many repos. It returns without reporting an error.
+type interruptedSource struct {
+	svc     *repos.ExternalService
+	repo    *repos.Repo
+	started chan struct{}
+}
+
+func (s *interruptedSource) ListRepos(ctx context.Context, results chan repos.SourceResult) {
+	results <- repos.SourceResult{Source: s, Repo: s.repo.Clone()}
+	close(s.started)
+	<-ctx.Done()
+}
+
+func (s *interruptedSource) ExternalServices() repos.ExternalServices {
+	return repos.ExternalServices{s.svc}
+}
+
 func testSyncerSync(s repos.Store) func(*testing.T) {
 	githubService := &repos.ExternalService{
 		ID:   1,
@@ -224,6 +330,8 @@ func testSyncerSync(s repos.Store) func(*testing.T) {
 		now     func() time.Time
 		diff    repos.Diff
 		err     string
+
+		archiveDeleted bool
 	}
 
 	var testCases []testCase
@@ -283,6 +391,30 @@ func testSyncerSync(s repos.Store) func(*testing.T) {
 				)}},
 				err: "<nil>",
 			},
+			testCase{
+				name: "repos of failing sources are kept",
+				sourcer: repos.NewFakeSourcer(nil,
+					repos.NewFakeSource(tc.svc.Clone(), errors.New("boom")),
+					repos.NewFakeSource(svcdup.Clone(), nil, tc.repo.Clone()),
+				),
+				store: s,
+				stored: repos.Repos{
+					tc.repo.With(repos.Opt.RepoSources(tc.svc.URN(), svcdup.URN())),
+					tc.repo.With(
+						repos.Opt.RepoName("only-from-failing-source"),
+						repos.Opt.RepoExternalID("only-from-failing-source"),
+					),
+				},
+				now: clock.Now,
+				diff: repos.Diff{Unmodified: repos.Repos{
+					tc.repo.With(repos.Opt.RepoSources(tc.svc.URN(), svcdup.URN())),
+					tc.repo.With(
+						repos.Opt.RepoName("only-from-failing-source"),
+						repos.Opt.RepoExternalID("only-from-failing-source"),
+					),
+				}},
+				err: "<nil>",
+			},
 			testCase{
 				name: "enabled field is not updateable",
 				sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(tc.svc.Clone(), nil, tc.repo.With(func(r *repos.Repo) {
@@ -336,6 +468,36 @@ func testSyncerSync(s repos.Store) func(*testing.T) {
 				)}},
 				err: "<nil>",
 			},
+			testCase{
+				name:    "deleted ALL repo sources with archived mirrors",
+				sourcer: repos.NewFakeSourcer(nil),
+				store:   s,
+				stored: repos.Repos{tc.repo.With(
+					repos.Opt.RepoSources(tc.svc.URN(), svcdup.URN()),
+				)},
+				now: clock.Now,
+				diff: repos.Diff{Deleted: repos.Repos{tc.repo.With(
+					repos.Opt.RepoArchivedMirrorAt(clock.Time(1)),
+				)}},
+				err:            "<nil>",
+				archiveDeleted: true,
+			},
+			testCase{
+				name: "archived mirror is restored when sourced again",
+				sourcer: repos.NewFakeSourcer(nil,
+					repos.NewFakeSource(tc.svc.Clone(), nil, tc.repo.Clone()),
+				),
+				store: s,
+				stored: repos.Repos{tc.repo.With(
+					repos.Opt.RepoArchivedMirrorAt(clock.Time(0)),
+				)},
+				now: clock.Now,
+				diff: repos.Diff{Modified: repos.Repos{tc.repo.With(
+					repos.Opt.RepoModifiedAt(clock.Time(1)),
+				)}},
+				err:            "<nil>",
+				archiveDeleted: true,
+			},
 			testCase{
 				name:    "renamed repo is detected via external_id",
 				sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(tc.svc.Clone(), nil, tc.repo.Clone())),
@@ -589,9 +751,10 @@ func testSyncerSync(s repos.Store) func(*testing.T) {
 				}
 
 				syncer := &repos.Syncer{
-					Store:   st,
-					Sourcer: tc.sourcer,
-					Now:     now,
+					Store:          st,
+					Sourcer:        tc.sourcer,
+					Now:            now,
+					ArchiveDeleted: func() bool { return tc.archiveDeleted },
 				}
 				err := syncer.Sync(ctx)
 
@@ -599,13 +762,13 @@ func testSyncerSync(s repos.Store) func(*testing.T) {
 					t.Errorf("have error %q, want %q", have, want)
 				}
 
-				if err != nil {
-					return
-				}
-
 				if st != nil {
 					var want, have repos.Repos
 					want.Concat(tc.diff.Added, tc.diff.Modified, tc.diff.Unmodified)
+					if tc.archiveDeleted {
+						// Archived mirrors are kept in the store.
+						want.Concat(tc.diff.Deleted)
+					}
 					have, _ = st.ListRepos(ctx, repos.StoreListReposArgs{})
 
 					want = want.With(repos.Opt.RepoID(0))
@@ -72,12 +72,15 @@ type FakeStore struct {
 	ListReposError              error // error to be returned in ListRepos
 	UpsertReposError            error // error to be returned in UpsertRepos
 	ListAllRepoNamesError       error // error to be returned in ListAllRepoNames
-
-	svcIDSeq  int64
-	repoIDSeq uint32
-	svcByID   map[int64]*ExternalService
-	repoByID  map[uint32]*Repo
-	parent    *FakeStore
+	ListScheduleStatesError     error // error to be returned in ListScheduleStates
+	UpsertScheduleStatesError   error // error to be returned in UpsertScheduleStates
+
+	svcIDSeq     int64
+	repoIDSeq    uint32
+	svcByID      map[int64]*ExternalService
+	repoByID     map[uint32]*Repo
+	scheduleByID map[uint32]*ScheduleState
+	parent       *FakeStore
 }
 
 // Transact returns a TxStore whose methods operate within the context of a transaction.
@@ -97,6 +100,12 @@ func (s *FakeStore) Transact(ctx context.Context) (TxStore, error) {
 		repoByID[r.ID] = clone
 	}
 
+	scheduleByID := make(map[uint32]*ScheduleState, len(s.scheduleByID))
+	for id, st := range s.scheduleByID {
+		clone := *st
+		scheduleByID[id] = &clone
+	}
+
 	return &FakeStore{
 		ListExternalServicesError:   s.ListExternalServicesError,
 		UpsertExternalServicesError: s.UpsertExternalServicesError,
@@ -104,12 +113,15 @@ func (s *FakeStore) Transact(ctx context.Context) (TxStore, error) {
 		ListReposError:              s.ListReposError,
 		UpsertReposError:            s.UpsertReposError,
 		ListAllRepoNamesError:       s.ListAllRepoNamesError,
-
-		svcIDSeq:  s.svcIDSeq,
-		svcByID:   svcByID,
-		repoIDSeq: s.repoIDSeq,
-		repoByID:  repoByID,
-		parent:    s,
+		ListScheduleStatesError:     s.ListScheduleStatesError,
+		UpsertScheduleStatesError:   s.UpsertScheduleStatesError,
+
+		svcIDSeq:     s.svcIDSeq,
+		svcByID:      svcByID,
+		repoIDSeq:    s.repoIDSeq,
+		repoByID:     repoByID,
+		scheduleByID: scheduleByID,
+		parent:       s,
 	}, nil
 }
 
@@ -234,7 +246,7 @@ func (s FakeStore) ListRepos(ctx context.Context, args StoreListReposArgs) ([]*R
 	set := make(map[*Repo]bool, len(s.repoByID))
 	repos := make(Repos, 0, len(s.repoByID))
 	for _, r := range s.repoByID {
-		if set[r] {
+		if set[r] || r.ID <= args.AfterID {
 			continue
 		}
 
@@ -282,6 +294,44 @@ func (s FakeStore) ListAllRepoNames(ctx context.Context) ([]api.RepoName, error)
 	return names, nil
 }
 
+// ListScheduleStates lists all update schedule states in the store.
+func (s FakeStore) ListScheduleStates(ctx context.Context) ([]*ScheduleState, error) {
+	if s.ListScheduleStatesError != nil {
+		return nil, s.ListScheduleStatesError
+	}
+
+	states := make([]*ScheduleState, 0, len(s.scheduleByID))
+	for _, st := range s.scheduleByID {
+		clone := *st
+		states = append(states, &clone)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].RepoID < states[j].RepoID })
+
+	return states, nil
+}
+
+// UpsertScheduleStates upserts the given update schedule states of repos in the store.
+func (s *FakeStore) UpsertScheduleStates(ctx context.Context, states ...*ScheduleState) error {
+	if s.UpsertScheduleStatesError != nil {
+		return s.UpsertScheduleStatesError
+	}
+
+	if s.scheduleByID == nil {
+		s.scheduleByID = make(map[uint32]*ScheduleState, len(states))
+	}
+
+	for _, st := range states {
+		if _, ok := s.repoByID[st.RepoID]; !ok {
+			continue
+		}
+		clone := *st
+		s.scheduleByID[st.RepoID] = &clone
+	}
+
+	return nil
+}
+
 func evalOr(bs ...bool) bool {
 	if len(bs) == 0 {
 		return true
@@ -327,6 +377,7 @@ func (s *FakeStore) UpsertRepos(ctx context.Context, upserts ...*Repo) error {
 
 	for _, r := range deletes {
 		delete(s.repoByID, r.ID)
+		delete(s.scheduleByID, r.ID)
 	}
 
 	for _, r := range updates {
@@ -449,6 +500,7 @@ var Opt = struct {
 	RepoCreatedAt             func(time.Time) func(*Repo)
 	RepoModifiedAt            func(time.Time) func(*Repo)
 	RepoDeletedAt             func(time.Time) func(*Repo)
+	RepoArchivedMirrorAt      func(time.Time) func(*Repo)
 	RepoEnabled               func(bool) func(*Repo)
 	RepoSources               func(...string) func(*Repo)
 	RepoMetadata              func(interface{}) func(*Repo)
@@ -503,6 +555,13 @@ var Opt = struct {
 			r.Sources = map[string]*SourceInfo{}
 		}
 	},
+	RepoArchivedMirrorAt: func(ts time.Time) func(*Repo) {
+		return func(r *Repo) {
+			r.UpdatedAt = ts
+			r.ArchivedMirror = true
+			r.Sources = map[string]*SourceInfo{}
+		}
+	},
 	RepoEnabled: func(enabled bool) func(*Repo) {
 		return func(r *Repo) {
 			r.Enabled = enabled
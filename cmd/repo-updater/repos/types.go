@@ -95,6 +95,8 @@ func (e ExternalService) Configuration() (cfg interface{}, _ error) {
 		cfg = &schema.GitoliteConnection{}
 	case "phabricator":
 		cfg = &schema.PhabricatorConnection{}
+	case "manifest":
+		cfg = &schema.ManifestConnection{}
 	case "other":
 		cfg = &schema.OtherExternalServiceConnection{}
 	default:
@@ -465,6 +467,8 @@ func (e ExternalService) schema() string {
 		return schema.GitoliteSchemaJSON
 	case "phabricator":
 		return schema.PhabricatorSchemaJSON
+	case "manifest":
+		return schema.ManifestSchemaJSON
 	case "other":
 		return schema.OtherExternalServiceSchemaJSON
 	default:
@@ -547,6 +551,10 @@ type Repo struct {
 	Enabled bool
 	// Archived is whether the repository has been archived.
 	Archived bool
+	// ArchivedMirror is whether the repository was removed from its code host
+	// and is only kept on Sourcegraph as an archived mirror. Archived mirrors
+	// remain searchable, but are never updated.
+	ArchivedMirror bool
 	// CreatedAt is when this repository was created on Sourcegraph.
 	CreatedAt time.Time
 	// UpdatedAt is when this repository's metadata was last updated on Sourcegraph.
@@ -640,6 +648,10 @@ func (r *Repo) Update(n *Repo) (modified bool) {
 		r.Fork, modified = n.Fork, true
 	}
 
+	if r.ArchivedMirror != n.ArchivedMirror {
+		r.ArchivedMirror, modified = n.ArchivedMirror, true
+	}
+
 	if !reflect.DeepEqual(r.Sources, n.Sources) {
 		r.Sources, modified = n.Sources, true
 	}
@@ -908,3 +920,12 @@ func (es ExternalServices) With(opts ...func(*ExternalService)) ExternalServices
 	clone.Apply(opts...)
 	return clone
 }
+
+// A ScheduleState is the persisted update schedule of a single repo, which
+// lets the scheduler keep its backoff across restarts.
+type ScheduleState struct {
+	RepoID     uint32
+	Interval   time.Duration // how regularly the repo is updated
+	Due        time.Time     // the next time that the repo will be enqueued for an update
+	ErrorCount int           // the number of consecutive failed updates
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -46,7 +47,9 @@ func NewHandlerMetrics() HandlerMetrics {
 }
 
 // ObservedHandler returns a decorator that wraps an http.Handler
-// with logging, Prometheus metrics and tracing.
+// with logging, Prometheus metrics and tracing. Requests are traced
+// according to the "tracing.sampling" policy of the site, with their
+// URL paths as route names.
 func ObservedHandler(
 	log log15.Logger,
 	m HandlerMetrics,
@@ -81,8 +84,14 @@ type observedHandler struct {
 func (h *observedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	rr := &responseRecorder{w, http.StatusOK, 0}
 
+	done := func(time.Duration) {}
+	if span := opentracing.SpanFromContext(r.Context()); span != nil {
+		done = trace.SampleRequest(span, r, r.URL.Path)
+	}
+
 	defer func(begin time.Time) {
 		took := time.Since(begin)
+		done(took)
 
 		h.log.Debug(
 			"http.request",
@@ -90,6 +99,7 @@ func (h *observedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"route", r.URL.Path,
 			"code", rr.code,
 			"duration", took,
+			"trace", trace.SpanURLFromContext(r.Context()),
 		)
 
 		var err error
@@ -97,6 +107,8 @@ func (h *observedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			err = errors.New(http.StatusText(rr.code))
 		}
 
+		trace.ObserveExemplar(r.Context(), "http.request "+r.URL.Path, took, err)
+
 		h.metrics.ServeHTTP.Observe(
 			took.Seconds(),
 			1,
@@ -32,7 +32,9 @@ type Server struct {
 	}
 	Scheduler interface {
 		UpdateOnce(id uint32, name api.RepoName, url string)
+		PriorityUpdateOnce(id uint32, name api.RepoName, url string)
 		ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerInfoResult
+		QueuedCount() int
 	}
 	GitserverClient interface {
 		ListCloned(context.Context) ([]string, error)
@@ -50,6 +52,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-lookup", s.handleRepoLookup)
 	mux.HandleFunc("/repo-external-services", s.handleRepoExternalServices)
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
+	mux.HandleFunc("/enqueue-priority-update", s.handleEnqueuePriorityUpdate)
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
 	mux.HandleFunc("/status-messages", s.handleStatusMessages)
@@ -237,12 +240,23 @@ func (s *Server) handleRepoLookup(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleEnqueueRepoUpdate(w http.ResponseWriter, r *http.Request) {
+	s.serveEnqueueRepoUpdate(w, r, s.Scheduler.UpdateOnce)
+}
+
+// handleEnqueuePriorityUpdate is like handleEnqueueRepoUpdate, but the update
+// jumps ahead of all scheduled and on-demand updates. It's meant for updates
+// that a user explicitly requested.
+func (s *Server) handleEnqueuePriorityUpdate(w http.ResponseWriter, r *http.Request) {
+	s.serveEnqueueRepoUpdate(w, r, s.Scheduler.PriorityUpdateOnce)
+}
+
+func (s *Server) serveEnqueueRepoUpdate(w http.ResponseWriter, r *http.Request, updateOnce func(id uint32, name api.RepoName, url string)) {
 	var req protocol.RepoUpdateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond(w, http.StatusBadRequest, err)
 		return
 	}
-	result, status, err := s.enqueueRepoUpdate(r.Context(), &req)
+	result, status, err := s.enqueueRepoUpdate(r.Context(), &req, updateOnce)
 	if err != nil {
 		log15.Error("enqueueRepoUpdate failed", "req", req, "error", err)
 		respond(w, status, err)
@@ -251,7 +265,7 @@ func (s *Server) handleEnqueueRepoUpdate(w http.ResponseWriter, r *http.Request)
 	respond(w, status, result)
 }
 
-func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdateRequest) (resp *protocol.RepoUpdateResponse, httpStatus int, err error) {
+func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdateRequest, updateOnce func(id uint32, name api.RepoName, url string)) (resp *protocol.RepoUpdateResponse, httpStatus int, err error) {
 	tr, ctx := trace.New(ctx, "enqueueRepoUpdate", req.String())
 	defer func() {
 		log15.Debug("enqueueRepoUpdate", "httpStatus", httpStatus, "resp", resp, "error", err)
@@ -277,7 +291,12 @@ func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdate
 			req.URL = urls[0]
 		}
 	}
-	s.Scheduler.UpdateOnce(repo.ID, req.Repo, req.URL)
+
+	// Archived mirrors no longer exist on their code host, so there is
+	// nothing to fetch.
+	if !repo.ArchivedMirror {
+		updateOnce(repo.ID, req.Repo, req.URL)
+	}
 
 	return &protocol.RepoUpdateResponse{
 		ID:   repo.ID,
@@ -452,6 +471,11 @@ func (s *Server) shouldGetGithubDotComRepo(args protocol.RepoLookupArgs) bool {
 	return strings.HasPrefix(repoName, "github.com/")
 }
 
+// updateQueueBacklogThreshold is the number of repositories waiting in the
+// update queue at which a status message is shown. Smaller backlogs are
+// normal and are worked off quickly.
+const updateQueueBacklogThreshold = 100
+
 func (s *Server) handleStatusMessages(w http.ResponseWriter, r *http.Request) {
 	resp := protocol.StatusMessagesResponse{
 		Messages: []protocol.StatusMessage{},
@@ -471,6 +495,17 @@ func (s *Server) handleStatusMessages(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	if s.Scheduler != nil {
+		if queued := s.Scheduler.QueuedCount(); queued >= updateQueueBacklogThreshold {
+			resp.Messages = append(resp.Messages, protocol.StatusMessage{
+				UpdateQueueBacklog: &protocol.UpdateQueueBacklog{
+					Message: fmt.Sprintf("%d repositories waiting to be updated...", queued),
+					Count:   queued,
+				},
+			})
+		}
+	}
+
 	if e := s.Syncer.LastSyncError(); e != nil {
 		if multiErr, ok := errors.Cause(e).(*multierror.Error); ok {
 			for _, e := range multiErr.Errors {
@@ -452,6 +452,39 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 	}
 }
 
+func TestServer_EnqueuePriorityRepoUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &repos.Repo{
+		Name: "github.com/foo/bar",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "bar",
+			ServiceType: "github",
+			ServiceID:   "http://github.com",
+		},
+		Metadata: new(github.Repository),
+	}
+	store := new(repos.FakeStore)
+	must(store.UpsertRepos(ctx, repo))
+
+	scheduler := &fakeScheduler{}
+	s := &Server{Store: store, Scheduler: scheduler}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	cli := repoupdater.Client{URL: srv.URL}
+
+	res, err := cli.EnqueuePriorityRepoUpdate(ctx, gitserver.Repo{Name: api.RepoName(repo.Name)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&protocol.RepoUpdateResponse{ID: repo.ID, Name: repo.Name}); !reflect.DeepEqual(res, want) {
+		t.Errorf("response: %s", cmp.Diff(res, want))
+	}
+	if have, want := scheduler.priorityUpdates, []api.RepoName{api.RepoName(repo.Name)}; !reflect.DeepEqual(have, want) {
+		t.Errorf("priority updates: %s", cmp.Diff(have, want))
+	}
+}
+
 func TestServer_RepoExternalServices(t *testing.T) {
 	service1 := &repos.ExternalService{
 		ID:          1,
@@ -557,6 +590,7 @@ func TestServer_StatusMessages(t *testing.T) {
 		gitserverCloned []string
 		sourcerErr      error
 		listRepoErr     error
+		queued          int
 		res             *protocol.StatusMessagesResponse
 		err             string
 	}{
@@ -648,6 +682,31 @@ func TestServer_StatusMessages(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "small update queue backlog",
+			gitserverCloned: []string{"foobar"},
+			stored:          []*repos.Repo{{Name: "foobar"}},
+			queued:          updateQueueBacklogThreshold - 1,
+			res: &protocol.StatusMessagesResponse{
+				Messages: []protocol.StatusMessage{},
+			},
+		},
+		{
+			name:            "large update queue backlog",
+			gitserverCloned: []string{"foobar"},
+			stored:          []*repos.Repo{{Name: "foobar"}},
+			queued:          1234,
+			res: &protocol.StatusMessagesResponse{
+				Messages: []protocol.StatusMessage{
+					{
+						UpdateQueueBacklog: &protocol.UpdateQueueBacklog{
+							Message: "1234 repositories waiting to be updated...",
+							Count:   1234,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:        "one syncer err",
 			listRepoErr: errors.New("could not connect to database"),
@@ -697,6 +756,7 @@ func TestServer_StatusMessages(t *testing.T) {
 			s := &Server{
 				Syncer:          syncer,
 				Store:           store,
+				Scheduler:       &fakeScheduler{queued: tc.queued},
 				GitserverClient: gitserverClient,
 			}
 
@@ -1039,13 +1099,20 @@ func (s *fakeGithubDotComSource) GetRepo(ctx context.Context, nameWithOwner stri
 }
 
 type fakeScheduler struct {
-	queue repos.Repos
+	queue  repos.Repos
+	queued int
+
+	priorityUpdates []api.RepoName
 }
 
 func (s *fakeScheduler) UpdateOnce(_ uint32, _ api.RepoName, _ string) {}
+func (s *fakeScheduler) PriorityUpdateOnce(_ uint32, name api.RepoName, _ string) {
+	s.priorityUpdates = append(s.priorityUpdates, name)
+}
 func (s *fakeScheduler) ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerInfoResult {
 	return &protocol.RepoUpdateSchedulerInfoResult{}
 }
+func (s *fakeScheduler) QueuedCount() int { return s.queued }
 
 type fakeGitserverClient struct {
 	listClonedResponse []string
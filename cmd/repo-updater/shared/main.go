@@ -7,7 +7,11 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -31,10 +35,21 @@ import (
 
 const port = "3182"
 
+// replicasRefreshInterval is how often the live repo-updater replicas are
+// listed, which bounds how long the work of a replica that went away stalls.
+const replicasRefreshInterval = 10 * time.Second
+
+// httpShutdownTimeout is how long in-flight requests may take to complete
+// when shutting down.
+const httpShutdownTimeout = 5 * time.Second
+
 func Main(newPreSync repos.NewPreSync) {
 	streamingSyncer, _ := strconv.ParseBool(env.Get("SRC_STREAMING_SYNCER_ENABLED", "true", "Use the new, streaming repo metadata syncer."))
 
-	ctx := context.Background()
+	// ctx is canceled on shutdown, which stops all background work.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	env.Lock()
 	env.HandleHelpFlag()
 	tracer.Init()
@@ -68,6 +83,22 @@ func Main(newPreSync repos.NewPreSync) {
 		log.Fatalf("failed to initialize db store: %v", err)
 	}
 
+	// Several repo-updater replicas can share the database. Only the leader
+	// syncs repositories, and the git fetches are partitioned among them.
+	replicas := repos.NewReplicas(db)
+	if err := replicas.Join(ctx); err != nil {
+		log.Fatalf("failed to join repo-updater replicas: %v", err)
+	}
+	// The replica slot is only released once the work in flight is
+	// checkpointed on shutdown, so that no other replica becomes the leader
+	// and syncs concurrently.
+	replicasCtx, cancelReplicas := context.WithCancel(context.Background())
+	defer cancelReplicas()
+	go replicas.Run(replicasCtx, replicasRefreshInterval)
+
+	// running tracks the workers that checkpoint their work on shutdown.
+	var running sync.WaitGroup
+
 	var store repos.Store
 	{
 		m := repos.NewStoreMetrics()
@@ -79,6 +110,8 @@ func Main(newPreSync repos.NewPreSync) {
 			m.ListExternalServices,
 			m.UpsertExternalServices,
 			m.ListAllRepoNames,
+			m.ListScheduleStates,
+			m.UpsertScheduleStates,
 		} {
 			om.MustRegister(prometheus.DefaultRegisterer)
 		}
@@ -101,6 +134,9 @@ func Main(newPreSync repos.NewPreSync) {
 	}
 
 	scheduler := repos.NewUpdateScheduler()
+	if err := scheduler.LoadScheduleStates(ctx, store); err != nil {
+		log15.Error("failed to load persisted repo update schedule", "err", err)
+	}
 	server := repoupdater.Server{
 		Store:           store,
 		Scheduler:       scheduler,
@@ -134,6 +170,9 @@ func Main(newPreSync repos.NewPreSync) {
 		DisableStreaming: !streamingSyncer,
 		Logger:           log15.Root(),
 		Now:              clock,
+		ArchiveDeleted: func() bool {
+			return conf.Get().ArchiveDeletedRepositories
+		},
 	}
 
 	if newPreSync != nil {
@@ -145,8 +184,15 @@ func Main(newPreSync repos.NewPreSync) {
 	} else {
 		syncer.Synced = make(chan repos.Repos)
 		syncer.SubsetSynced = make(chan repos.Repos)
-		go watchSyncer(ctx, syncer, scheduler, gps)
-		go func() { log.Fatal(syncer.Run(ctx, repos.GetUpdateInterval())) }()
+		go watchSyncer(ctx, syncer, replicas, scheduler, gps)
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			replicas.RunAsLeader(ctx, replicasRefreshInterval, func(ctx context.Context) {
+				_ = syncer.Run(ctx, repos.GetUpdateInterval())
+			})
+		}()
+		go repos.RunPartitionScheduler(ctx, replicas, scheduler, store, repos.GetUpdateInterval())
 	}
 	server.Syncer = syncer
 
@@ -159,6 +205,11 @@ func Main(newPreSync repos.NewPreSync) {
 
 	// Git fetches scheduler
 	go repos.RunScheduler(ctx, scheduler)
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		repos.RunSchedulePersister(ctx, scheduler, store)
+	}()
 	log15.Debug("started scheduler")
 
 	host := ""
@@ -169,7 +220,11 @@ func Main(newPreSync repos.NewPreSync) {
 	addr := net.JoinHostPort(host, port)
 	log15.Info("server listening", "addr", addr)
 	srv := &http.Server{Addr: addr, Handler: handler}
-	go func() { log.Fatal(srv.ListenAndServe()) }()
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
 
 	go debugserver.Start(debugserver.Endpoint{
 		Name: "Repo Updater State",
@@ -185,22 +240,53 @@ func Main(newPreSync repos.NewPreSync) {
 		}),
 	})
 
-	select {}
+	// Listen for shutdown signals. When we receive one, stop accepting new
+	// work and checkpoint the work in flight, but exit immediately if we
+	// receive another one.
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	<-c
+	go func() {
+		<-c
+		os.Exit(1)
+	}()
+
+	log15.Info("repo-updater: shutting down")
+
+	// Stop accepting requests, such as the ones that enqueue repo updates.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log15.Error("repo-updater: graceful HTTP server shutdown failed", "error", err)
+	}
+	cancelShutdown()
+
+	// Stop the schedulers and syncers, and wait for the sync in flight and
+	// the schedule to be stored (see Syncer.Run and RunSchedulePersister).
+	cancel()
+	running.Wait()
+
+	cancelReplicas()
+	replicas.Leave()
+
+	log15.Info("repo-updater: shut down")
 }
 
 type scheduler interface {
 	Set(...*repos.Repo)
 	Update(...*repos.Repo)
+	Remove(...*repos.Repo)
 }
 
-func watchSyncer(ctx context.Context, syncer *repos.Syncer, sched scheduler, gps *repos.GitolitePhabricatorMetadataSyncer) {
+func watchSyncer(ctx context.Context, syncer *repos.Syncer, replicas *repos.Replicas, sched scheduler, gps *repos.GitolitePhabricatorMetadataSyncer) {
 	log15.Debug("started new repo syncer updates scheduler relay thread")
 
 	for {
 		select {
 		case rs := <-syncer.Synced:
 			if !conf.Get().DisableAutoGitUpdates {
-				sched.Set(rs...)
+				owned, others := replicas.Partition(rs)
+				sched.Remove(others...)
+				sched.Set(owned...)
 			}
 
 			go func() {
@@ -211,7 +297,8 @@ func watchSyncer(ctx context.Context, syncer *repos.Syncer, sched scheduler, gps
 
 		case rs := <-syncer.SubsetSynced:
 			if !conf.Get().DisableAutoGitUpdates {
-				sched.Update(rs...)
+				owned, _ := replicas.Partition(rs)
+				sched.Update(owned...)
 			}
 		}
 	}
@@ -42,6 +42,18 @@ type Request struct {
 	// The deadline for the search request.
 	// It is parsed with time.Time.UnmarshalText.
 	Deadline string
+
+	// Indexed is a hint that the repository is indexed by zoekt, so that
+	// searcher is only asked to search it in unusual cases (e.g. for
+	// index:no queries). Unless ExpectedReuse is set, the archives of
+	// indexed repositories are the first to be evicted from the cache, since
+	// they are unlikely to be searched again.
+	Indexed bool
+
+	// ExpectedReuse is a hint that the same archive will be searched again
+	// as part of the same query (e.g. after checking its repohasfile:
+	// filters), so it should stay cached even if Indexed is set.
+	ExpectedReuse bool
 }
 
 // GitserverRepo returns the repository information necessary to perform gitserver requests.
@@ -54,6 +66,18 @@ type PatternInfo struct {
 	// is true, otherwise a fixed string. eg "route variable"
 	Pattern string
 
+	// AndPatterns is a list of patterns that must *all* match the content of
+	// the returned files, independently of each other. It is usually the
+	// list of alternatives Pattern is a union of, so that the matches of each
+	// one are returned, but only for files which contain all of them. The
+	// patterns are interpreted the same way as Pattern.
+	AndPatterns []string
+
+	// NegatedPatterns is a list of patterns that must *not* match the content
+	// of the returned files. They are interpreted the same way as Pattern.
+	// Files excluded by them do not count towards FileMatchLimit.
+	NegatedPatterns []string
+
 	// IsRegExp if true will treat the Pattern as a regular expression.
 	IsRegExp bool
 
@@ -64,6 +88,16 @@ type PatternInfo struct {
 	// when finding matches.
 	IsCaseSensitive bool
 
+	// IsUnicode if true will match the text with Unicode case folding
+	// (instead of only ASCII case folding) if IsCaseSensitive is false, and
+	// normalize the patterns and text to Normalization before matching.
+	IsUnicode bool
+
+	// Normalization is the Unicode normalization form ("NFC" or "NFD") that
+	// the patterns and text are normalized to if IsUnicode is true. It
+	// defaults to "NFC".
+	Normalization string
+
 	// ExcludePattern is a pattern that may not match the returned files' paths.
 	// eg '**/node_modules'
 	ExcludePattern string
@@ -100,6 +134,12 @@ type PatternInfo struct {
 
 func (p *PatternInfo) String() string {
 	args := []string{fmt.Sprintf("%q", p.Pattern)}
+	for _, and := range p.AndPatterns {
+		args = append(args, fmt.Sprintf("and:%q", and))
+	}
+	for _, not := range p.NegatedPatterns {
+		args = append(args, fmt.Sprintf("not:%q", not))
+	}
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
@@ -109,6 +149,12 @@ func (p *PatternInfo) String() string {
 	if p.IsCaseSensitive {
 		args = append(args, "case")
 	}
+	if p.IsUnicode {
+		args = append(args, "unicode")
+		if p.Normalization != "" {
+			args = append(args, "norm:"+p.Normalization)
+		}
+	}
 	if !p.PatternMatchesContent {
 		args = append(args, "nocontent")
 	}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"regexp"
 	"regexp/syntax"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,6 +22,7 @@ import (
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	otlog "github.com/opentracing/opentracing-go/log"
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
@@ -56,9 +58,29 @@ type readerGrep struct {
 	// re is the regexp to match, or nil if empty ("match all files' content").
 	re *regexp.Regexp
 
-	// ignoreCase if true means we need to do case insensitive matching.
+	// andRes are regexps which must all match a file's content (or path) for
+	// any of re's matches in it to be returned.
+	andRes []*regexp.Regexp
+
+	// negatedRes are regexps which must not match a file's content for it to
+	// be returned.
+	negatedRes []*regexp.Regexp
+
+	// ignoreCase if true means we need to do case insensitive matching by
+	// lowercasing ASCII letters. It is false for Unicode case insensitive
+	// matching, which is done by the regexps.
 	ignoreCase bool
 
+	// normalize if true means the content and paths of files are converted
+	// to normForm before matching. The patterns already are.
+	normalize bool
+	normForm  norm.Form
+
+	// normBuf is reused between file searches to avoid re-allocating. It
+	// holds the normalized content of a file if it wasn't normalized
+	// already.
+	normBuf []byte
+
 	// transformBuf is reused between file searches to avoid
 	// re-allocating. It is only used if we need to transform the input
 	// before matching. For example we lower case the input in the case of
@@ -83,32 +105,11 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		literalSubstring []byte
 	)
 	if p.Pattern != "" {
-		expr := p.Pattern
-		if !p.IsRegExp {
-			expr = regexp.QuoteMeta(expr)
-		}
-		if p.IsWordMatch {
-			expr = `\b` + expr + `\b`
-		}
-		if p.IsRegExp {
-			// We don't do the search line by line, therefore we want the
-			// regex engine to consider newlines for anchors (^$).
-			expr = "(?m:" + expr + ")"
-		}
-		if !p.IsCaseSensitive {
-			// We don't just use (?i) because regexp library doesn't seem
-			// to contain good optimizations for case insensitive
-			// search. Instead we lowercase the input and pattern.
-			re, err := syntax.Parse(expr, syntax.Perl)
-			if err != nil {
-				return nil, err
-			}
-			lowerRegexpASCII(re)
-			expr = re.String()
-		}
-
-		var err error
-		re, err = regexp.Compile(expr)
+		var (
+			expr string
+			err  error
+		)
+		re, expr, err = compilePattern(p, p.Pattern)
 		if err != nil {
 			return nil, err
 		}
@@ -125,6 +126,24 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		}
 	}
 
+	var andRes []*regexp.Regexp
+	for _, pattern := range p.AndPatterns {
+		andRe, _, err := compilePattern(p, pattern)
+		if err != nil {
+			return nil, err
+		}
+		andRes = append(andRes, andRe)
+	}
+
+	var negatedRes []*regexp.Regexp
+	for _, pattern := range p.NegatedPatterns {
+		negatedRe, _, err := compilePattern(p, pattern)
+		if err != nil {
+			return nil, err
+		}
+		negatedRes = append(negatedRes, negatedRe)
+	}
+
 	pathOptions := pathmatch.CompileOptions{
 		RegExp:        p.PathPatternsAreRegExps,
 		CaseSensitive: p.PathPatternsAreCaseSensitive,
@@ -134,12 +153,81 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		return nil, err
 	}
 
-	return &readerGrep{
+	rg := &readerGrep{
 		re:               re,
-		ignoreCase:       !p.IsCaseSensitive,
+		andRes:           andRes,
+		negatedRes:       negatedRes,
+		ignoreCase:       !p.IsCaseSensitive && !p.IsUnicode,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
-	}, nil
+	}
+	if p.IsUnicode {
+		rg.normalize = true
+		if rg.normForm, err = normForm(p.Normalization); err != nil {
+			return nil, err
+		}
+	}
+	return rg, nil
+}
+
+// normForm returns the Unicode normalization form with the given name, which
+// defaults to NFC.
+func normForm(name string) (norm.Form, error) {
+	switch name {
+	case "", "NFC":
+		return norm.NFC, nil
+	case "NFD":
+		return norm.NFD, nil
+	default:
+		return 0, errors.New("unsupported Unicode normalization form " + strconv.Quote(name))
+	}
+}
+
+// compilePattern compiles pattern, which is interpreted according to the
+// options in p. It also returns the expression that was compiled.
+func compilePattern(p *protocol.PatternInfo, pattern string) (*regexp.Regexp, string, error) {
+	expr := pattern
+	if p.IsUnicode {
+		// The regexp metacharacters are ASCII, so normalizing doesn't change
+		// the meaning of the pattern.
+		form, err := normForm(p.Normalization)
+		if err != nil {
+			return nil, "", err
+		}
+		expr = form.String(expr)
+	}
+	if !p.IsRegExp {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if p.IsWordMatch {
+		expr = `\b` + expr + `\b`
+	}
+	if p.IsRegExp {
+		// We don't do the search line by line, therefore we want the
+		// regex engine to consider newlines for anchors (^$).
+		expr = "(?m:" + expr + ")"
+	}
+	if !p.IsCaseSensitive && p.IsUnicode {
+		// Unicode case folding can't be done by lowercasing the input
+		// byte-wise, so we leave it to the regexp engine.
+		expr = "(?i)" + expr
+	} else if !p.IsCaseSensitive {
+		// We don't just use (?i) because regexp library doesn't seem
+		// to contain good optimizations for case insensitive
+		// search. Instead we lowercase the input and pattern.
+		re, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, "", err
+		}
+		lowerRegexpASCII(re)
+		expr = re.String()
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, "", err
+	}
+	return re, expr, nil
 }
 
 // Copy returns a copied version of rg that is safe to use from another
@@ -147,7 +235,11 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 func (rg *readerGrep) Copy() *readerGrep {
 	return &readerGrep{
 		re:               rg.re,
+		andRes:           rg.andRes,
+		negatedRes:       rg.negatedRes,
 		ignoreCase:       rg.ignoreCase,
+		normalize:        rg.normalize,
+		normForm:         rg.normForm,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
 	}
@@ -162,6 +254,14 @@ func (rg *readerGrep) matchString(s string) bool {
 	if rg.ignoreCase {
 		s = strings.ToLower(s)
 	}
+	if rg.normalize {
+		s = rg.normForm.String(s)
+	}
+	for _, andRe := range rg.andRes {
+		if !andRe.MatchString(s) {
+			return false
+		}
+	}
 	return rg.re.MatchString(s)
 }
 
@@ -170,22 +270,10 @@ func (rg *readerGrep) matchString(s string) bool {
 // NOTE: This is not safe to use concurrently.
 func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []protocol.LineMatch, limitHit bool, err error) {
 	// fileMatchBuf is what we run match on, fileBuf is the original
-	// data (for Preview).
-	fileBuf := zf.DataFor(f)
-	fileMatchBuf := fileBuf
-
-	// If we are ignoring case, we transform the input instead of
-	// relying on the regular expression engine which can be
-	// slow. compile has already lowercased the pattern. We also
-	// trade some correctness for perf by using a non-utf8 aware
-	// lowercase function.
-	if rg.ignoreCase {
-		if rg.transformBuf == nil {
-			rg.transformBuf = make([]byte, zf.MaxLen)
-		}
-		fileMatchBuf = rg.transformBuf[:len(fileBuf)]
-		bytesToLowerASCII(fileMatchBuf, fileBuf)
-	}
+	// data (for Preview). If we normalize, the previews are of the
+	// normalized data, so that the match offsets are valid for them.
+	fileBuf := rg.normalized(zf.DataFor(f))
+	fileMatchBuf := rg.transform(zf, fileBuf)
 
 	// Most files will not have a match and we bound the number of matched
 	// files we return. So we can avoid the overhead of parsing out new lines
@@ -197,6 +285,11 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 	if !bytes.Contains(fileMatchBuf, rg.literalSubstring) {
 		return nil, false, nil
 	}
+	for _, andRe := range rg.andRes {
+		if !andRe.Match(fileMatchBuf) {
+			return nil, false, nil
+		}
+	}
 
 	locs := rg.re.FindAllIndex(fileMatchBuf, maxLineMatches+1)
 	lastStart := 0
@@ -240,6 +333,50 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 	return matches, limitHit, nil
 }
 
+// transform returns the data of a file in zf to run the regexps on.
+//
+// If we are ignoring case, we transform the input instead of relying on the
+// regular expression engine which can be slow. compile has already
+// lowercased the pattern. We also trade some correctness for perf by using a
+// non-utf8 aware lowercase function.
+func (rg *readerGrep) transform(zf *store.ZipFile, fileBuf []byte) []byte {
+	if !rg.ignoreCase {
+		return fileBuf
+	}
+	if rg.transformBuf == nil {
+		rg.transformBuf = make([]byte, zf.MaxLen)
+	}
+	fileMatchBuf := rg.transformBuf[:len(fileBuf)]
+	bytesToLowerASCII(fileMatchBuf, fileBuf)
+	return fileMatchBuf
+}
+
+// normalized returns b converted to rg's Unicode normalization form, if rg
+// normalizes. It reuses rg.normBuf if b isn't normalized already.
+func (rg *readerGrep) normalized(b []byte) []byte {
+	if !rg.normalize || rg.normForm.IsNormal(b) {
+		return b
+	}
+	rg.normBuf = rg.normForm.Append(rg.normBuf[:0], b...)
+	return rg.normBuf
+}
+
+// excluded reports whether the content of f matches any of rg's negated
+// patterns, in which case f must not be returned (not even if its path
+// matches).
+func (rg *readerGrep) excluded(zf *store.ZipFile, f *store.SrcFile) bool {
+	if len(rg.negatedRes) == 0 {
+		return false
+	}
+	fileMatchBuf := rg.transform(zf, rg.normalized(zf.DataFor(f)))
+	for _, negatedRe := range rg.negatedRes {
+		if negatedRe.Match(fileMatchBuf) {
+			return true
+		}
+	}
+	return false
+}
+
 func hydrateLineNumbers(fileBuf []byte, lastLineNumber, lastMatchIndex, lineStart int, match []int) (lineNumber, matchIndex int) {
 	lineNumber = lastLineNumber + bytes.Count(fileBuf[lastMatchIndex:match[0]], []byte{'\n'})
 	return lineNumber, lineStart
@@ -273,17 +410,23 @@ func appendMatches(matches []protocol.LineMatch, fileBuf []byte, matchLineBuf []
 		if limit < 0 {
 			limit = len(fileBuf)
 		}
-		matches = append(matches, protocol.LineMatch{
-			// we are not allowed to use the fileBuf data after the ZipFile has been Closed,
-			// which currently occurs before Preview has been serialized.
-			// TODO: consider moving the call to Close until after we are
-			// done with Preview, and stop making a copy here.
-			// Special care must be taken to call Close on all possible paths, including error paths.
-			Preview:          string(fileBuf[:limit]),
-			LineNumber:       lineNumber,
-			OffsetAndLengths: [][2]int{{offset, length}},
-			LimitHit:         false, // We will always return false for this field since we no longer limit the number of offsets per line.
-		})
+		if n := len(matches); n > 0 && matches[n-1].LineNumber == lineNumber {
+			// Another match on the same line, so we add its range to the
+			// line's existing LineMatch.
+			matches[n-1].OffsetAndLengths = append(matches[n-1].OffsetAndLengths, [2]int{offset, length})
+		} else {
+			matches = append(matches, protocol.LineMatch{
+				// we are not allowed to use the fileBuf data after the ZipFile has been Closed,
+				// which currently occurs before Preview has been serialized.
+				// TODO: consider moving the call to Close until after we are
+				// done with Preview, and stop making a copy here.
+				// Special care must be taken to call Close on all possible paths, including error paths.
+				Preview:          string(fileBuf[:limit]),
+				LineNumber:       lineNumber,
+				OffsetAndLengths: [][2]int{{offset, length}},
+				LimitHit:         false, // We will always return false for this field since we no longer limit the number of offsets per line.
+			})
+		}
 
 		if eol >= 0 {
 			fileBuf = fileBuf[eol+1:]
@@ -352,8 +495,9 @@ func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, file
 	if rg.re == nil || (patternMatchesPaths && !patternMatchesContent) {
 		// Fast path for only matching file paths (or with a nil pattern, which matches all files,
 		// so is effectively matching only on file paths).
-		for _, f := range files {
-			if rg.matchPath.MatchPath(f.Name) && rg.matchString(f.Name) {
+		for i := range files {
+			f := &files[i]
+			if rg.matchPath.MatchPath(f.Name) && rg.matchString(f.Name) && !rg.excluded(zf, f) {
 				if len(matches) < fileMatchLimit {
 					matches = append(matches, protocol.FileMatch{Path: f.Name})
 				} else {
@@ -399,7 +543,7 @@ func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, file
 				filesmu.Unlock()
 
 				// decide whether to process, record that decision
-				if !rg.matchPath.MatchPath(f.Name) {
+				if !rg.matchPath.MatchPath(f.Name) || rg.excluded(zf, f) {
 					atomic.AddUint32(&filesSkipped, 1)
 					continue
 				}
@@ -605,6 +749,11 @@ func lowerRegexpASCII(re *syntax.Regexp) {
 func longestLiteral(re *syntax.Regexp) string {
 	switch re.Op {
 	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			// The literal matches text in any case (e.g. with Unicode case
+			// folding), so it isn't guaranteed to appear as it is.
+			return ""
+		}
 		return string(re.Rune)
 	case syntax.OpCapture, syntax.OpPlus:
 		return longestLiteral(re.Sub[0])
@@ -352,6 +352,7 @@ func TestLongestLiteral(t *testing.T) {
 		"([abB-Z]|FoO)": "",
 		`[@-\[]`:        "",
 		`\S`:            "",
+		"(?i)foo":       "",
 	}
 
 	metaLiteral := "AddSuballocation(dump->guid(), system_allocator_name)"
@@ -522,6 +523,86 @@ func TestPathMatches(t *testing.T) {
 	}
 }
 
+func TestUnicodeMatches(t *testing.T) {
+	zipData, err := createZip(map[string]string{
+		"precomposed": "x := caf\u00e9\n",
+		"decomposed":  "x := cafe\u0301\n",
+		"upper":       "x := CAF\u00c9\n",
+		"ascii":       "x := cafe\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		p    protocol.PatternInfo
+		want []string
+		// wantLength is the length of the matches, in runes.
+		wantLength int
+	}{
+		{
+			name:       "ASCII case folding",
+			p:          protocol.PatternInfo{Pattern: "Caf\u00e9"},
+			want:       []string{"precomposed"},
+			wantLength: 4,
+		},
+		{
+			name:       "Unicode case folding",
+			p:          protocol.PatternInfo{Pattern: "Caf\u00e9", IsUnicode: true},
+			want:       []string{"decomposed", "precomposed", "upper"},
+			wantLength: 4,
+		},
+		{
+			name:       "Unicode case sensitive",
+			p:          protocol.PatternInfo{Pattern: "cafe\u0301", IsUnicode: true, IsCaseSensitive: true},
+			want:       []string{"decomposed", "precomposed"},
+			wantLength: 4,
+		},
+		{
+			name: "NFD",
+			p:    protocol.PatternInfo{Pattern: "caf\u00e9", IsUnicode: true, Normalization: "NFD"},
+			want: []string{"decomposed", "precomposed", "upper"},
+			// The e and the combining acute accent are separate runes.
+			wantLength: 5,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rg, err := compile(&test.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fileMatches, _, err := concurrentFind(context.Background(), rg, zf, 10, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, len(fileMatches))
+			for i, fm := range fileMatches {
+				got[i] = fm.Path
+				// The previews are normalized, so the offsets are valid for
+				// them.
+				want := [2]int{5, test.wantLength}
+				if lm := fm.LineMatches[0]; lm.OffsetAndLengths[0] != want {
+					t.Errorf("%s: got offset and length %v in %q, want %v", fm.Path, lm.OffsetAndLengths[0], lm.Preview, want)
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got file matches %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := compile(&protocol.PatternInfo{Pattern: "x", IsUnicode: true, Normalization: "NFKC"}); err == nil {
+		t.Error("expected an error for an unsupported normalization form")
+	}
+}
+
 func createZip(files map[string]string) ([]byte, error) {
 	buf := new(bytes.Buffer)
 	zw := zip.NewWriter(buf)
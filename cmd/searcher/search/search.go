@@ -129,6 +129,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 	span.SetTag("isRegExp", strconv.FormatBool(p.IsRegExp))
 	span.SetTag("isWordMatch", strconv.FormatBool(p.IsWordMatch))
 	span.SetTag("isCaseSensitive", strconv.FormatBool(p.IsCaseSensitive))
+	span.SetTag("isUnicode", strconv.FormatBool(p.IsUnicode))
 	span.SetTag("pathPatternsAreRegExps", strconv.FormatBool(p.PathPatternsAreRegExps))
 	span.SetTag("pathPatternsAreCaseSensitive", strconv.FormatBool(p.PathPatternsAreCaseSensitive))
 	span.SetTag("fileMatchLimit", p.FileMatchLimit)
@@ -196,12 +197,22 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 		return path, zf, err
 	}
 
-	_, zf, err := store.GetZipFileWithRetry(getZf)
+	path, zf, err := store.GetZipFileWithRetry(getZf)
 	if err != nil {
 		return nil, false, false, err
 	}
 	defer zf.Close()
 
+	if p.Indexed && !p.ExpectedReuse {
+		// The frontend usually searches this repository with zoekt, so make
+		// room for the archives of unindexed repositories first.
+		if err := s.Store.Demote(path); err != nil {
+			log15.Warn("failed to demote archive", "repo", p.Repo, "commit", p.Commit, "err", err)
+		} else {
+			archiveDemoted.Inc()
+		}
+	}
+
 	nFiles := uint64(len(zf.Files))
 	bytes := int64(len(zf.Data))
 	tr.LazyPrintf("files=%d bytes=%d", nFiles, bytes)
@@ -223,8 +234,8 @@ func validateParams(p *protocol.Request) error {
 	if len(p.Commit) != 40 {
 		return errors.Errorf("Commit must be resolved (Commit=%q)", p.Commit)
 	}
-	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 {
-		return errors.New("At least one of pattern and include/exclude pattners must be non-empty")
+	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 && len(p.NegatedPatterns) == 0 {
+		return errors.New("At least one of pattern, negated patterns and include/exclude pattners must be non-empty")
 	}
 	return nil
 }
@@ -252,6 +263,12 @@ var (
 		Help:      "Observes the number of files when an archive is searched.",
 		Buckets:   []float64{100, 1000, 10000, 50000, 100000},
 	})
+	archiveDemoted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "searcher",
+		Subsystem: "service",
+		Name:      "archive_demoted_total",
+		Help:      "Number of searched archives that were demoted in the cache because the frontend doesn't expect to search them again.",
+	})
 	requestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "searcher",
 		Subsystem: "service",
@@ -264,6 +281,7 @@ func init() {
 	prometheus.MustRegister(running)
 	prometheus.MustRegister(archiveSize)
 	prometheus.MustRegister(archiveFiles)
+	prometheus.MustRegister(archiveDemoted)
 	prometheus.MustRegister(requestTotal)
 }
 
@@ -14,10 +14,12 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/search"
@@ -92,6 +94,32 @@ main.go:5:func main() {
 		// special uppercase chars in pattern.
 		{protocol.PatternInfo{Pattern: `printL\B`, IsRegExp: true}, `
 main.go:6:	fmt.Println("Hello world")
+`},
+
+		{protocol.PatternInfo{Pattern: "(hello)|(println)", AndPatterns: []string{"hello", "println"}, IsRegExp: true}, `
+main.go:6:	fmt.Println("Hello world")
+`},
+		{protocol.PatternInfo{Pattern: "(main)|(fmt)", AndPatterns: []string{"main", "fmt"}, IsRegExp: true}, `
+main.go:1:package main
+main.go:3:import "fmt"
+main.go:5:func main() {
+main.go:6:	fmt.Println("Hello world")
+`},
+
+		{protocol.PatternInfo{Pattern: "world", NegatedPatterns: []string{"fmt"}}, `
+README.md:1:# Hello World
+README.md:3:Hello world example in go
+`},
+		{protocol.PatternInfo{Pattern: "world", NegatedPatterns: []string{"EXAMPLE"}}, `
+main.go:6:	fmt.Println("Hello world")
+`},
+		{protocol.PatternInfo{Pattern: "world", NegatedPatterns: []string{"EXAMPLE"}, IsCaseSensitive: true}, `
+README.md:3:Hello world example in go
+main.go:6:	fmt.Println("Hello world")
+`},
+		{protocol.PatternInfo{Pattern: "", NegatedPatterns: []string{"world"}}, `
+abc.txt
+milton.png
 `},
 
 		{protocol.PatternInfo{Pattern: "world", ExcludePattern: "README.md"}, `
@@ -344,12 +372,65 @@ func TestSearch_badrequest(t *testing.T) {
 	}
 }
 
+func TestSearch_demoteIndexed(t *testing.T) {
+	store, cleanup, err := newStore(map[string]string{"main.go": "package main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	ts := httptest.NewServer(&search.Service{Store: store})
+	defer ts.Close()
+
+	archiveModTime := func() time.Time {
+		paths, err := filepath.Glob(filepath.Join(store.Path, "*.zip"))
+		if err != nil || len(paths) != 1 {
+			t.Fatalf("expected one archive in the cache, got %v (err %v)", paths, err)
+		}
+		fi, err := os.Stat(paths[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.ModTime()
+	}
+
+	cases := []struct {
+		name          string
+		indexed       bool
+		expectedReuse bool
+		wantDemoted   bool
+	}{
+		{name: "unindexed"},
+		{name: "indexed", indexed: true, wantDemoted: true},
+		{name: "indexed and reused", indexed: true, expectedReuse: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := protocol.Request{
+				Repo:          "foo",
+				Commit:        "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+				PatternInfo:   protocol.PatternInfo{Pattern: "main", PatternMatchesContent: true},
+				FetchTimeout:  "500ms",
+				Indexed:       tc.indexed,
+				ExpectedReuse: tc.expectedReuse,
+			}
+			if _, err := doSearch(ts.URL, &req); err != nil {
+				t.Fatal(err)
+			}
+			if demoted := archiveModTime().Before(time.Now().Add(-time.Hour)); demoted != tc.wantDemoted {
+				t.Errorf("got demoted %v, want %v", demoted, tc.wantDemoted)
+			}
+		})
+	}
+}
+
 func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 	form := url.Values{
 		"Repo":            []string{string(p.Repo)},
 		"URL":             []string{string(p.URL)},
 		"Commit":          []string{string(p.Commit)},
 		"Pattern":         []string{p.Pattern},
+		"AndPatterns":     p.AndPatterns,
+		"NegatedPatterns": p.NegatedPatterns,
 		"IncludePatterns": p.IncludePatterns,
 		"ExcludePattern":  []string{p.ExcludePattern},
 	}
@@ -374,6 +455,12 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 	if p.PatternMatchesPath {
 		form.Set("PatternMatchesPath", "true")
 	}
+	if p.Indexed {
+		form.Set("Indexed", "true")
+	}
+	if p.ExpectedReuse {
+		form.Set("ExpectedReuse", "true")
+	}
 	resp, err := http.PostForm(u, form)
 	if err != nil {
 		return nil, err
@@ -36,6 +36,7 @@ var defaultEnv = map[string]string{
 	"GITHUB_BASE_URL":       "http://127.0.0.1:3180", // points to github-proxy
 
 	"GRAFANA_SERVER_URL": "http://127.0.0.1:3370",
+	"PROMETHEUS_URL":     "http://127.0.0.1:9090",
 
 	// Limit our cache size to 100GB, same as prod. We should probably update
 	// searcher/symbols to ensure this value isn't larger than the volume for
@@ -33,6 +33,10 @@ func githubProvider(a *schema.GitHubAuthorization, instanceURL, token string) (a
 		return nil, nil
 	}
 
+	if token == "" {
+		return nil, fmt.Errorf("GitHub authorization for %q requires a token, GitHub App installations are not supported", instanceURL)
+	}
+
 	ghURL, err := url.Parse(instanceURL)
 	if err != nil {
 		return nil, fmt.Errorf("Could not parse URL for GitHub instance %q: %s", instanceURL, err)
@@ -27,6 +27,7 @@ type Provider struct {
 }
 
 var _ authz.Provider = ((*Provider)(nil))
+var _ authz.PermissionsInvalidator = ((*Provider)(nil))
 
 var clock = func() time.Time { return time.Now().UTC().Truncate(time.Microsecond) }
 
@@ -128,6 +129,21 @@ func (p *Provider) UpdatePermissions(ctx context.Context, u *types.User) error {
 	return p.store.UpdatePermissions(ctx, ps, p.update(u.Username))
 }
 
+// InvalidatePermissions satisfies the authz.PermissionsInvalidator interface.
+// Permissions are cached per user, so all of the user's cached permissions are
+// expired, regardless of the given repos.
+func (p *Provider) InvalidatePermissions(ctx context.Context, acct *extsvc.ExternalAccount, _ []*types.Repo) error {
+	if acct == nil {
+		return nil
+	}
+
+	return p.store.ExpirePermissions(ctx, &Permissions{
+		UserID: acct.UserID,
+		Perm:   authz.Read,
+		Type:   "repos",
+	})
+}
+
 // update returns a PermissionsUpdateFunc that fetches the IDs of
 // all the repos the user with the given userName is authorized to
 // see.
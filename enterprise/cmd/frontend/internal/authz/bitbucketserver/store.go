@@ -184,6 +184,41 @@ func (s *store) UpdatePermissions(
 	return nil
 }
 
+// ExpirePermissions marks the stored Permissions as expired, so that they're
+// updated the next time they're loaded. Until then, they can still be used
+// for as long as their hard TTL allows.
+func (s *store) ExpirePermissions(ctx context.Context, p *Permissions) (err error) {
+	ctx, save := s.observe(ctx, "ExpirePermissions", "")
+	defer func() { save(&err, p.tracingFields()...) }()
+
+	q := expireQuery(p, s.clock().Add(-s.ttl))
+
+	var rows *sql.Rows
+	rows, err = s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+func expireQuery(p *Permissions, expiredAt time.Time) *sqlf.Query {
+	return sqlf.Sprintf(
+		expireQueryFmtStr,
+		expiredAt.UTC(),
+		p.UserID,
+		p.Perm.String(),
+		p.Type,
+	)
+}
+
+const expireQueryFmtStr = `
+-- source: enterprise/cmd/frontend/internal/authz/bitbucketserver/store.go:store.ExpirePermissions
+UPDATE user_permissions
+SET updated_at = LEAST(updated_at, %s)
+WHERE user_id = %s AND permission = %s AND object_type = %s
+`
+
 // StalePermissionsError is returned by LoadPermissions when the stored
 // permissions are stale (e.g. the first time a user needs them and they haven't
 // been fetched yet). Callers should pass this error up to the user and show it
@@ -43,6 +43,7 @@ func NewProvider(githubURL *url.URL, baseToken string, cacheTTL time.Duration, m
 }
 
 var _ authz.Provider = ((*Provider)(nil))
+var _ authz.PermissionsInvalidator = ((*Provider)(nil))
 
 // RepoPerms implements the authz.Provider interface.
 //
@@ -276,6 +277,26 @@ func (p *Provider) setCachedUserRepos(ctx context.Context, userAccount *extsvc.E
 	return nil
 }
 
+// InvalidatePermissions implements the authz.PermissionsInvalidator interface. It deletes the
+// cached permissions of the user on the given repos.
+func (p *Provider) InvalidatePermissions(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) error {
+	if userAccount == nil {
+		return nil
+	}
+
+	for _, repo := range repos {
+		rkey, err := json.Marshal(userRepoCacheKey{
+			User: userAccount.AccountID,
+			Repo: repo.ExternalRepo.ID,
+		})
+		if err != nil {
+			return err
+		}
+		p.cache.Delete(string(rkey))
+	}
+	return nil
+}
+
 // getCachedUserRepos accepts a user account and set of repos and returns a map from repo ID to
 // true/false indicating whether the user can access the repo. The returned map may be incomplete
 // (i.e., not every input repo may be represented in the key set) due to cache incompleteness.
@@ -92,3 +92,58 @@ func TestProvider_RepoPerms_cacheTTL(t *testing.T) {
 		githubMock.getRepositoriesByNodeIDCount = 0
 	}
 }
+
+// TestProvider_InvalidatePermissions tests that invalidated permissions are fetched again from
+// GitHub, and that the others are still served from the cache.
+func TestProvider_InvalidatePermissions(t *testing.T) {
+	githubMock := newMockGitHub([]*github.Repository{
+		{ID: "u0/r0", IsPrivate: true},
+		{ID: "u0/r1", IsPrivate: true},
+	}, map[string][]string{
+		"t0": {"u0/r0", "u0/r1"},
+	})
+	github.GetRepositoryByNodeIDMock = githubMock.GetRepositoryByNodeID
+	defer func() { github.GetRepositoryByNodeIDMock = nil }()
+	github.GetRepositoriesByNodeIDFromAPIMock = githubMock.GetRepositoriesByNodeIDFromAPI
+	defer func() { github.GetRepositoriesByNodeIDFromAPIMock = nil }()
+
+	provider := NewProvider(mustURL(t, "https://github.com"), "base-token", 3*time.Hour, make(authz.MockCache))
+	ctx := context.Background()
+
+	userAccount := ua("u0", "t0")
+	repos := []*types.Repo{
+		rp("r0", "u0/r0", "https://github.com/"),
+		rp("r1", "u0/r1", "https://github.com/"),
+	}
+	if _, err := provider.RepoPerms(ctx, userAccount, repos); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := provider.InvalidatePermissions(ctx, userAccount, repos[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	githubMock.getRepositoriesByNodeIDCount = 0
+	cached, err := provider.getCachedUserRepos(ctx, userAccount, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"u0/r1": true}; !reflect.DeepEqual(cached, want) {
+		t.Errorf("got cached user repos %v, want %v", cached, want)
+	}
+
+	gotPerms, err := provider.RepoPerms(ctx, userAccount, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPerms := []authz.RepoPerms{
+		{Repo: repos[0], Perms: authz.Read},
+		{Repo: repos[1], Perms: authz.Read},
+	}
+	if !reflect.DeepEqual(gotPerms, wantPerms) {
+		t.Errorf("got perms %s, want %s", spew.Sdump(gotPerms), spew.Sdump(wantPerms))
+	}
+	if want, got := 1, githubMock.getRepositoriesByNodeIDCount; want != got {
+		t.Errorf("expected %d cache misses, but got %d", want, got)
+	}
+}
@@ -20,10 +20,18 @@ func main() {
 	}
 
 	shared.Main(func(db *sql.DB, rs repos.Store, cf *httpcli.Factory) func(context.Context) error {
+		store := a8n.NewStore(db)
+
+		doer, err := cf.Doer()
+		if err != nil {
+			log.Fatalf("Failed to create HTTP client for campaign webhooks: %v", err)
+		}
+
 		syncer := &a8n.ChangesetSyncer{
-			Store:       a8n.NewStore(db),
+			Store:       store,
 			ReposStore:  rs,
 			HTTPFactory: cf,
+			Notifier:    &a8n.CampaignNotifier{Store: store, Doer: doer},
 		}
 
 		return syncer.Sync
@@ -0,0 +1,137 @@
+package a8n
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// A ClosedChangeset is the outcome of closing a campaign for one of its open
+// changesets.
+type ClosedChangeset struct {
+	Changeset *a8n.Changeset
+	// Closed is whether the changeset was closed on its code host.
+	Closed bool
+	// Detached is whether the changeset was detached from the campaign and
+	// left open on its code host.
+	Detached bool
+	// Err is the error that occurred while closing the changeset on its code
+	// host, if any. Changesets that failed to close stay attached to the
+	// campaign, so that closing it again retries them.
+	Err error
+}
+
+// CloseCampaign closes the given campaign. If closeChangesets is true, its
+// open changesets are closed on their code hosts. Otherwise they're detached
+// from the campaign and left open, so that they can be managed on their code
+// hosts or added to another campaign. The outcome for each open changeset is
+// returned.
+func (s *ChangesetSyncer) CloseCampaign(ctx context.Context, campaign *a8n.Campaign, closeChangesets bool) (_ []*ClosedChangeset, err error) {
+	cs, _, err := s.Store.ListChangesets(ctx, ListChangesetsOpts{CampaignID: campaign.ID, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	var open []*a8n.Changeset
+	for _, c := range cs {
+		if state, err := c.State(); err == nil && state == a8n.ChangesetStateOpen {
+			open = append(open, c)
+		}
+	}
+
+	results := make([]*ClosedChangeset, 0, len(open))
+	if closeChangesets {
+		errs, err := s.closeChangesets(ctx, open)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range open {
+			err := errs[c.ID]
+			results = append(results, &ClosedChangeset{Changeset: c, Closed: err == nil, Err: err})
+		}
+	}
+
+	tx, err := s.Store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Done(&err)
+
+	if !closeChangesets && len(open) > 0 {
+		detached := make(map[int64]bool, len(open))
+		for _, c := range open {
+			campaignIDs := c.CampaignIDs[:0]
+			for _, id := range c.CampaignIDs {
+				if id != campaign.ID {
+					campaignIDs = append(campaignIDs, id)
+				}
+			}
+			c.CampaignIDs = campaignIDs
+			detached[c.ID] = true
+			results = append(results, &ClosedChangeset{Changeset: c, Detached: true})
+		}
+
+		if err = tx.UpdateChangesets(ctx, open...); err != nil {
+			return nil, err
+		}
+
+		ids := campaign.ChangesetIDs[:0]
+		for _, id := range campaign.ChangesetIDs {
+			if !detached[id] {
+				ids = append(ids, id)
+			}
+		}
+		campaign.ChangesetIDs = ids
+	}
+
+	if !campaign.Closed() {
+		campaign.ClosedAt = s.Store.now()
+	}
+	if err = tx.UpdateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// closeChangesets closes the given changesets on their code hosts and syncs
+// them afterwards, so that their new state is recorded. Failing to close one
+// changeset doesn't stop closing the others; the errors are returned by
+// changeset ID.
+func (s *ChangesetSyncer) closeChangesets(ctx context.Context, cs []*a8n.Changeset) (map[int64]error, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	// Changesets whose repository is gone are not part of any batch.
+	errs := make(map[int64]error, len(cs))
+	for _, c := range cs {
+		errs[c.ID] = errors.New("repository not found")
+	}
+
+	batches, err := s.batchChangesets(ctx, cs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range batches {
+		src, ok := b.ChangesetSource.(repos.ChangesetCloseSource)
+		for _, c := range b.Changesets {
+			if !ok {
+				errs[c.Changeset.ID] = errors.New("code host doesn't support closing changesets")
+			} else if err := src.CloseChangeset(ctx, c); err != nil {
+				errs[c.Changeset.ID] = err
+			} else {
+				delete(errs, c.Changeset.ID)
+			}
+		}
+	}
+
+	if err := s.SyncChangesets(ctx, cs...); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,94 @@
+package a8n
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+// Ran in integration_test.go
+func testCloseCampaign(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		tx, done := dbtest.NewTx(t, db)
+		defer done()
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		s := NewStoreWithClock(tx, func() time.Time { return now })
+		ctx := context.Background()
+
+		var changesets []*a8n.Changeset
+		for i, state := range []string{"OPEN", "MERGED", "OPEN"} {
+			changesets = append(changesets, &a8n.Changeset{
+				RepoID:              42,
+				Metadata:            &github.PullRequest{State: state},
+				ExternalID:          fmt.Sprintf("close-%d", i),
+				ExternalServiceType: "github",
+			})
+		}
+		if err := s.CreateChangesets(ctx, changesets...); err != nil {
+			t.Fatal(err)
+		}
+
+		campaign := &a8n.Campaign{
+			Name:            "Close me",
+			AuthorID:        23,
+			NamespaceUserID: 23,
+		}
+		for _, c := range changesets {
+			campaign.ChangesetIDs = append(campaign.ChangesetIDs, c.ID)
+		}
+		if err := s.CreateCampaign(ctx, campaign); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range changesets {
+			c.CampaignIDs = []int64{campaign.ID}
+		}
+		if err := s.UpdateChangesets(ctx, changesets...); err != nil {
+			t.Fatal(err)
+		}
+
+		// Detaching open changesets doesn't touch their code hosts.
+		syncer := &ChangesetSyncer{Store: s}
+		closed, err := syncer.CloseCampaign(ctx, campaign, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var detached []int64
+		for _, c := range closed {
+			if !c.Detached || c.Closed || c.Err != nil {
+				t.Errorf("changeset %d: got %+v, want detached", c.Changeset.ID, c)
+			}
+			detached = append(detached, c.Changeset.ID)
+		}
+		if diff := cmp.Diff(detached, []int64{changesets[0].ID, changesets[2].ID}); diff != "" {
+			t.Errorf("detached changesets: %s", diff)
+		}
+
+		have, err := s.GetCampaign(ctx, GetCampaignOpts{ID: campaign.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.ClosedAt.Equal(now) {
+			t.Errorf("campaign closed at %v, want %v", have.ClosedAt, now)
+		}
+		if diff := cmp.Diff(have.ChangesetIDs, []int64{changesets[1].ID}); diff != "" {
+			t.Errorf("campaign changesets: %s", diff)
+		}
+
+		cs, _, err := s.ListChangesets(ctx, ListChangesetsOpts{CampaignID: campaign.ID, Limit: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cs) != 1 || cs[0].ID != changesets[1].ID {
+			t.Errorf("got %d changesets attached to the campaign, want only the merged one", len(cs))
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 )
 
@@ -124,6 +125,63 @@ func CalcCounts(start, end time.Time, cs []*a8n.Changeset, es ...Event) ([]*Chan
 	return counts, nil
 }
 
+// LabelChangesetCounts are the ChangesetCounts of the changesets in all
+// campaigns that have a given label.
+type LabelChangesetCounts struct {
+	Label  string
+	Counts *ChangesetCounts
+}
+
+// CalcCountsByLabel calculates the ChangesetCounts at the given point in time
+// of the changesets in the given Campaigns, grouped by the labels of the
+// campaigns. A changeset that is in several campaigns with the same label is
+// only counted once for that label. The results are sorted by label.
+func CalcCountsByLabel(at time.Time, campaigns []*a8n.Campaign, cs []*a8n.Changeset, es ...Event) ([]*LabelChangesetCounts, error) {
+	byID := make(map[int64]*a8n.Changeset, len(cs))
+	for _, c := range cs {
+		byID[c.ID] = c
+	}
+
+	changesetsByLabel := make(map[string]map[int64]*a8n.Changeset)
+	for _, campaign := range campaigns {
+		for _, label := range campaign.Labels {
+			set, ok := changesetsByLabel[label]
+			if !ok {
+				set = make(map[int64]*a8n.Changeset)
+				changesetsByLabel[label] = set
+			}
+			for _, id := range campaign.ChangesetIDs {
+				if c, ok := byID[id]; ok {
+					set[id] = c
+				}
+			}
+		}
+	}
+
+	labels := make([]string, 0, len(changesetsByLabel))
+	for label := range changesetsByLabel {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	counts := make([]*LabelChangesetCounts, 0, len(labels))
+	for _, label := range labels {
+		set := changesetsByLabel[label]
+		labelChangesets := make([]*a8n.Changeset, 0, len(set))
+		for _, c := range set {
+			labelChangesets = append(labelChangesets, c)
+		}
+
+		cc, err := CalcCounts(at, at, labelChangesets, es...)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, &LabelChangesetCounts{Label: label, Counts: cc[0]})
+	}
+
+	return counts, nil
+}
+
 func computeCounts(c *ChangesetCounts, csEvents Events) error {
 	var (
 		// Since "Merged" and "Closed" are exclusive events and cancel each others
@@ -155,21 +213,21 @@ func computeCounts(c *ChangesetCounts, csEvents Events) error {
 		currentReviewState := computeReviewState(lastReviewByAuthor)
 
 		switch e.Type() {
-		case a8n.ChangesetEventKindGitHubClosed:
+		case a8n.ChangesetEventKindGitHubClosed, a8n.ChangesetEventKindBitbucketServerDeclined:
 			c.Open--
 			c.Closed++
 			closed = true
 
 			c.AddReviewState(currentReviewState, -1)
 
-		case a8n.ChangesetEventKindGitHubReopened:
+		case a8n.ChangesetEventKindGitHubReopened, a8n.ChangesetEventKindBitbucketServerReopened:
 			c.Open++
 			c.Closed--
 			closed = false
 
 			c.AddReviewState(currentReviewState, 1)
 
-		case a8n.ChangesetEventKindGitHubMerged:
+		case a8n.ChangesetEventKindGitHubMerged, a8n.ChangesetEventKindBitbucketServerMerged:
 			// If it was closed, all "review counts" have been updated by the
 			// closed events and we just need to reverse these two counts
 			if closed {
@@ -187,7 +245,9 @@ func computeCounts(c *ChangesetCounts, csEvents Events) error {
 			// other events
 			return nil
 
-		case a8n.ChangesetEventKindGitHubReviewed:
+		case a8n.ChangesetEventKindGitHubReviewed,
+			a8n.ChangesetEventKindBitbucketServerApproved,
+			a8n.ChangesetEventKindBitbucketServerReviewed:
 			s, err := reviewState(e)
 			if err != nil {
 				return err
@@ -216,6 +276,23 @@ func computeCounts(c *ChangesetCounts, csEvents Events) error {
 				// Increase the counts for new review state
 				c.AddReviewState(newReviewState, 1)
 			}
+
+		case a8n.ChangesetEventKindBitbucketServerUnapproved:
+			author, err := reviewAuthor(e)
+			if err != nil {
+				return err
+			}
+
+			// Withdrawing an approval on Bitbucket Server takes back the
+			// last review of the author.
+			oldReviewState := currentReviewState
+			delete(lastReviewByAuthor, author)
+			newReviewState := computeReviewState(lastReviewByAuthor)
+
+			if newReviewState != oldReviewState {
+				c.AddReviewState(oldReviewState, -1)
+				c.AddReviewState(newReviewState, 1)
+			}
 		}
 	}
 
@@ -246,16 +323,24 @@ func reviewState(e Event) (a8n.ChangesetReviewState, error) {
 		return s, errors.New("Reviewed event not ChangesetEvent")
 	}
 
-	review, ok := changesetEvent.Metadata.(*github.PullRequestReview)
-	if !ok {
+	switch m := changesetEvent.Metadata.(type) {
+	case *github.PullRequestReview:
+		s = a8n.ChangesetReviewState(m.State)
+		if !s.Valid() {
+			return s, fmt.Errorf("invalid review state: %s", m.State)
+		}
+		return s, nil
+	case *bitbucketserver.Activity:
+		switch m.Action {
+		case bitbucketserver.ActivityActionApproved:
+			return a8n.ChangesetReviewStateApproved, nil
+		case bitbucketserver.ActivityActionReviewed:
+			return a8n.ChangesetReviewStateChangesRequested, nil
+		}
+		return s, fmt.Errorf("invalid review activity action: %s", m.Action)
+	default:
 		return s, errors.New("ChangesetEvent metadata event not PullRequestReview")
 	}
-
-	s = a8n.ChangesetReviewState(review.State)
-	if !s.Valid() {
-		return s, fmt.Errorf("invalid review state: %s", review.State)
-	}
-	return s, nil
 }
 
 func reviewAuthor(e Event) (string, error) {
@@ -264,12 +349,16 @@ func reviewAuthor(e Event) (string, error) {
 		return "", errors.New("Reviewed event not ChangesetEvent")
 	}
 
-	review, ok := changesetEvent.Metadata.(*github.PullRequestReview)
-	if !ok {
+	var login string
+	switch m := changesetEvent.Metadata.(type) {
+	case *github.PullRequestReview:
+		login = m.Author.Login
+	case *bitbucketserver.Activity:
+		login = m.User.Name
+	default:
 		return "", errors.New("ChangesetEvent metadata event not PullRequestReview")
 	}
 
-	login := review.Author.Login
 	if login == "" {
 		return "", errors.New("review author is blank")
 	}
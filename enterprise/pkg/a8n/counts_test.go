@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 )
 
@@ -523,6 +524,41 @@ func TestCalcCounts(t *testing.T) {
 				{Time: daysAgo(0), Total: 1, Open: 1, OpenChangesRequested: 1},
 			},
 		},
+		{
+			name: "bitbucketserver changeset declined and reopened",
+			changesets: []*a8n.Changeset{
+				bbsChangeset(1, daysAgo(3)),
+			},
+			start: daysAgo(3),
+			events: []Event{
+				bbsActivity(1, daysAgo(2), "user1", bitbucketserver.ActivityActionDeclined),
+				bbsActivity(1, daysAgo(1), "user1", bitbucketserver.ActivityActionReopened),
+			},
+			want: []*ChangesetCounts{
+				{Time: daysAgo(3), Total: 1, Open: 1, OpenPending: 1},
+				{Time: daysAgo(2), Total: 1, Closed: 1},
+				{Time: daysAgo(1), Total: 1, Open: 1, OpenPending: 1},
+				{Time: daysAgo(0), Total: 1, Open: 1, OpenPending: 1},
+			},
+		},
+		{
+			name: "bitbucketserver changeset approved, approval withdrawn, then needs work",
+			changesets: []*a8n.Changeset{
+				bbsChangeset(1, daysAgo(3)),
+			},
+			start: daysAgo(3),
+			events: []Event{
+				bbsActivity(1, daysAgo(3), "user1", bitbucketserver.ActivityActionApproved),
+				bbsActivity(1, daysAgo(2), "user1", bitbucketserver.ActivityActionUnapproved),
+				bbsActivity(1, daysAgo(1), "user2", bitbucketserver.ActivityActionReviewed),
+			},
+			want: []*ChangesetCounts{
+				{Time: daysAgo(3), Total: 1, Open: 1, OpenApproved: 1},
+				{Time: daysAgo(2), Total: 1, Open: 1, OpenPending: 1},
+				{Time: daysAgo(1), Total: 1, Open: 1, OpenChangesRequested: 1},
+				{Time: daysAgo(0), Total: 1, Open: 1, OpenChangesRequested: 1},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -543,6 +579,39 @@ func TestCalcCounts(t *testing.T) {
 	}
 }
 
+func TestCalcCountsByLabel(t *testing.T) {
+	now := time.Now().Truncate(time.Microsecond)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	changesets := []*a8n.Changeset{
+		ghChangeset(1, daysAgo(3)),
+		ghChangeset(2, daysAgo(3)),
+		ghChangeset(3, daysAgo(3)),
+	}
+	campaigns := []*a8n.Campaign{
+		{ID: 1, Labels: []string{"eslint", "frontend"}, ChangesetIDs: []int64{1, 2}},
+		{ID: 2, Labels: []string{"frontend"}, ChangesetIDs: []int64{2, 3}},
+		{ID: 3, ChangesetIDs: []int64{3}},
+	}
+	events := []Event{
+		fakeEvent{t: daysAgo(2), kind: a8n.ChangesetEventKindGitHubMerged, id: 1},
+		fakeEvent{t: daysAgo(1), kind: a8n.ChangesetEventKindGitHubClosed, id: 3},
+	}
+
+	have, err := CalcCountsByLabel(now, campaigns, changesets, events...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*LabelChangesetCounts{
+		{Label: "eslint", Counts: &ChangesetCounts{Time: now, Total: 2, Merged: 1, Open: 1, OpenPending: 1}},
+		{Label: "frontend", Counts: &ChangesetCounts{Time: now, Total: 3, Merged: 1, Closed: 1, Open: 1, OpenPending: 1}},
+	}
+	if diff := cmp.Diff(have, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
 type fakeEvent struct {
 	t    time.Time
 	kind a8n.ChangesetEventKind
@@ -570,3 +639,24 @@ func ghReview(id int64, t time.Time, login, state string) *a8n.ChangesetEvent {
 		},
 	}
 }
+
+func bbsChangeset(id int64, t time.Time) *a8n.Changeset {
+	return &a8n.Changeset{ID: id, Metadata: &bitbucketserver.PullRequest{CreatedDate: timeToUnixMilli(t)}}
+}
+
+func bbsActivity(id int64, t time.Time, username string, action bitbucketserver.ActivityAction) *a8n.ChangesetEvent {
+	a := &bitbucketserver.Activity{
+		CreatedDate: timeToUnixMilli(t),
+		User:        bitbucketserver.User{Name: username},
+		Action:      action,
+	}
+	return &a8n.ChangesetEvent{
+		ChangesetID: id,
+		Kind:        a8n.ChangesetEventKindFor(a),
+		Metadata:    a,
+	}
+}
+
+func timeToUnixMilli(t time.Time) int {
+	return int(t.UnixNano() / int64(time.Millisecond))
+}
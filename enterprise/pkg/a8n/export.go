@@ -0,0 +1,73 @@
+package a8n
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// changesetsCSVHeader is the header row of the CSV written by
+// WriteChangesetsCSV.
+var changesetsCSVHeader = []string{
+	"id",
+	"repository",
+	"external_id",
+	"url",
+	"title",
+	"state",
+	"review_state",
+	"created_at",
+	"updated_at",
+	"sync_failures",
+	"sync_error",
+}
+
+// WriteChangesetsCSV writes the states of the given Changesets to w as CSV,
+// for import into program-management tools. repoNames maps the IDs of the
+// repos of the Changesets to their names. Changesets that were never synced
+// with their code host have empty titles, URLs and states.
+func WriteChangesetsCSV(w io.Writer, cs []*a8n.Changeset, repoNames map[int32]string) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(changesetsCSVHeader); err != nil {
+		return err
+	}
+
+	for _, c := range cs {
+		title, _ := c.Title()
+		url, _ := c.URL()
+		state, _ := c.State()
+		reviewState, _ := c.ReviewState()
+		if state != a8n.ChangesetStateOpen {
+			reviewState = ""
+		}
+
+		var createdAt string
+		if t := c.ExternalCreatedAt(); !t.IsZero() {
+			createdAt = t.UTC().Format(time.RFC3339)
+		}
+
+		err := cw.Write([]string{
+			strconv.FormatInt(c.ID, 10),
+			repoNames[c.RepoID],
+			c.ExternalID,
+			url,
+			title,
+			string(state),
+			string(reviewState),
+			createdAt,
+			c.UpdatedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(int(c.NumFailures)),
+			c.FailureMessage,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
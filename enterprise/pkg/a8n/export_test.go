@@ -0,0 +1,55 @@
+package a8n
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestWriteChangesetsCSV(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	cs := []*a8n.Changeset{
+		{
+			ID:         1,
+			RepoID:     42,
+			ExternalID: "12",
+			UpdatedAt:  now,
+			Metadata: &github.PullRequest{
+				Title:     "Upgrade ES-Lint, again",
+				State:     "MERGED",
+				URL:       "https://github.com/sourcegraph/sourcegraph/pull/12",
+				CreatedAt: now.Add(-time.Hour),
+			},
+		},
+		{
+			ID:             2,
+			RepoID:         43,
+			ExternalID:     "7",
+			UpdatedAt:      now,
+			NumFailures:    1,
+			FailureMessage: "not found",
+		},
+	}
+
+	repoNames := map[int32]string{
+		42: "github.com/sourcegraph/sourcegraph",
+		43: "github.com/sourcegraph/go-diff",
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChangesetsCSV(&buf, cs, repoNames); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `id,repository,external_id,url,title,state,review_state,created_at,updated_at,sync_failures,sync_error
+1,github.com/sourcegraph/sourcegraph,12,https://github.com/sourcegraph/sourcegraph/pull/12,"Upgrade ES-Lint, again",MERGED,,2019-10-01T11:00:00Z,2019-10-01T12:00:00Z,0,
+2,github.com/sourcegraph/go-diff,7,,,,,,2019-10-01T12:00:00Z,1,not found
+`
+	if have := buf.String(); have != want {
+		t.Errorf("have:\n%s\nwant:\n%s", have, want)
+	}
+}
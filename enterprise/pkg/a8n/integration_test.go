@@ -21,4 +21,5 @@ func TestIntegration(t *testing.T) {
 
 	t.Run("Store", testStore(db))
 	t.Run("GitHubWebhook", testGitHubWebhook(db))
+	t.Run("CloseCampaign", testCloseCampaign(db))
 }
@@ -0,0 +1,264 @@
+package a8n
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// CampaignEvent is the kind of state transition of a campaign that its
+// webhook is notified about.
+type CampaignEvent string
+
+// CampaignEvent constants.
+const (
+	// CampaignEventCompleted is sent when the last open changeset of a
+	// campaign was merged.
+	CampaignEventCompleted CampaignEvent = "campaign.completed"
+	// CampaignEventChangesetFailed is sent when a changeset of a campaign
+	// that synced fine before failed to sync with its code host.
+	CampaignEventChangesetFailed CampaignEvent = "changeset.failed"
+)
+
+// CampaignWebhookPayload is the JSON payload of a campaign webhook
+// notification.
+type CampaignWebhookPayload struct {
+	Event     CampaignEvent                `json:"event"`
+	Timestamp time.Time                    `json:"timestamp"`
+	Campaign  CampaignWebhookCampaign      `json:"campaign"`
+	Changeset *CampaignWebhookChangeset    `json:"changeset,omitempty"`
+	Counts    map[a8n.ChangesetState]int32 `json:"counts"`
+}
+
+// CampaignWebhookCampaign is the campaign a CampaignWebhookPayload is about.
+type CampaignWebhookCampaign struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// CampaignWebhookChangeset is the changeset a CampaignWebhookPayload is
+// about, if any.
+type CampaignWebhookChangeset struct {
+	ID          int64  `json:"id"`
+	ExternalID  string `json:"externalID"`
+	ExternalURL string `json:"externalURL,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CampaignWebhookSignatureHeader is the header of campaign webhook requests
+// that holds the hex encoded HMAC-SHA256 of their body, keyed with the
+// secret of the campaign and prefixed with "sha256=".
+const CampaignWebhookSignatureHeader = "X-Sourcegraph-Signature"
+
+// SignCampaignWebhookPayload returns the value of the
+// CampaignWebhookSignatureHeader for the given payload and secret.
+func SignCampaignWebhookPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// A CampaignNotifier notifies the webhooks of campaigns about the state
+// transitions of their changesets.
+type CampaignNotifier struct {
+	Store *Store
+	// Doer sends the webhook requests. http.DefaultClient is used if nil.
+	Doer httpcli.Doer
+}
+
+// changesetSnapshot is the state of a Changeset before it's synced, which
+// is compared to its state afterwards to find transitions.
+type changesetSnapshot struct {
+	state  a8n.ChangesetState
+	failed bool
+}
+
+func snapshotChangesets(cs []*a8n.Changeset) map[int64]changesetSnapshot {
+	snapshots := make(map[int64]changesetSnapshot, len(cs))
+	for _, c := range cs {
+		// Changesets that were never synced have no state yet.
+		state, _ := c.State()
+		snapshots[c.ID] = changesetSnapshot{state: state, failed: c.Failed()}
+	}
+	return snapshots
+}
+
+// notifySynced notifies the webhooks of the campaigns of the given
+// Changesets about the transitions since they were snapshotted. Failing
+// deliveries are logged, but don't fail the sync.
+func (n *CampaignNotifier) notifySynced(ctx context.Context, before map[int64]changesetSnapshot, cs []*a8n.Changeset) error {
+	var (
+		failed    []*a8n.Changeset
+		merged    = map[int64]bool{}
+		campaigns = map[int64]bool{}
+	)
+
+	for _, c := range cs {
+		prev, ok := before[c.ID]
+		if !ok {
+			continue
+		}
+
+		if c.Failed() && !prev.failed {
+			failed = append(failed, c)
+			for _, id := range c.CampaignIDs {
+				campaigns[id] = true
+			}
+		}
+
+		if state, err := c.State(); err == nil && state == a8n.ChangesetStateMerged && prev.state != state {
+			for _, id := range c.CampaignIDs {
+				merged[id], campaigns[id] = true, true
+			}
+		}
+	}
+
+	if len(campaigns) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(campaigns))
+	for id := range campaigns {
+		ids = append(ids, id)
+	}
+
+	all, _, err := n.Store.ListCampaigns(ctx, ListCampaignsOpts{IDs: ids, Limit: -1})
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int64]*a8n.Campaign, len(all))
+	for _, c := range all {
+		if c.WebhookURL != "" {
+			byID[c.ID] = c
+		}
+	}
+
+	counts := make(map[int64]map[a8n.ChangesetState]int32, len(byID))
+	for id := range byID {
+		if counts[id], err = n.countChangesets(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	now := n.Store.now()
+	for _, c := range failed {
+		for _, id := range c.CampaignIDs {
+			campaign, ok := byID[id]
+			if !ok {
+				continue
+			}
+
+			url, _ := c.URL()
+			n.deliver(ctx, campaign, &CampaignWebhookPayload{
+				Event:     CampaignEventChangesetFailed,
+				Timestamp: now,
+				Campaign:  CampaignWebhookCampaign{ID: campaign.ID, Name: campaign.Name},
+				Changeset: &CampaignWebhookChangeset{
+					ID:          c.ID,
+					ExternalID:  c.ExternalID,
+					ExternalURL: url,
+					Error:       c.FailureMessage,
+				},
+				Counts: counts[id],
+			})
+		}
+	}
+
+	for id := range merged {
+		campaign, ok := byID[id]
+		if !ok {
+			continue
+		}
+
+		// The campaign is only completed once all of its changesets are
+		// merged, not when some were closed without merging.
+		if c := counts[id]; c[a8n.ChangesetStateOpen] > 0 || c[a8n.ChangesetStateClosed] > 0 {
+			continue
+		}
+
+		n.deliver(ctx, campaign, &CampaignWebhookPayload{
+			Event:     CampaignEventCompleted,
+			Timestamp: now,
+			Campaign:  CampaignWebhookCampaign{ID: campaign.ID, Name: campaign.Name},
+			Counts:    counts[id],
+		})
+	}
+
+	return nil
+}
+
+func (n *CampaignNotifier) countChangesets(ctx context.Context, campaignID int64) (map[a8n.ChangesetState]int32, error) {
+	cs, _, err := n.Store.ListChangesets(ctx, ListChangesetsOpts{CampaignID: campaignID, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[a8n.ChangesetState]int32{
+		a8n.ChangesetStateOpen:   0,
+		a8n.ChangesetStateClosed: 0,
+		a8n.ChangesetStateMerged: 0,
+	}
+	for _, c := range cs {
+		if state, err := c.State(); err == nil {
+			counts[state]++
+		}
+	}
+	return counts, nil
+}
+
+func (n *CampaignNotifier) deliver(ctx context.Context, c *a8n.Campaign, p *CampaignWebhookPayload) {
+	if err := n.send(ctx, c, p); err != nil {
+		log15.Warn("CampaignNotifier: delivering webhook failed", "campaign_id", c.ID, "event", p.Event, "error", err)
+	}
+}
+
+func (n *CampaignNotifier) send(ctx context.Context, c *a8n.Campaign, p *CampaignWebhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", c.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sourcegraph-Event", string(p.Event))
+	if c.WebhookSecret != "" {
+		req.Header.Set(CampaignWebhookSignatureHeader, SignCampaignWebhookPayload(body, c.WebhookSecret))
+	}
+
+	doer := n.Doer
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+
+	resp, err := doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return nil
+}
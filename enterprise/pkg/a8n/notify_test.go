@@ -0,0 +1,89 @@
+package a8n
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+func TestSignCampaignWebhookPayload(t *testing.T) {
+	have := SignCampaignWebhookPayload([]byte(`{"event":"campaign.completed"}`), "secret")
+	want := "sha256=2b645f5bb3d785f1ca6ba1e41674aa98be73405a07a788dafd4bce9700fae973"
+	if have != want {
+		t.Errorf("have %q, want %q", have, want)
+	}
+}
+
+func TestCampaignNotifierSend(t *testing.T) {
+	campaign := &a8n.Campaign{
+		ID:            1,
+		Name:          "Upgrade ES-Lint",
+		WebhookURL:    "https://pm.example.com/hooks/sourcegraph",
+		WebhookSecret: "secret",
+	}
+
+	payload := &CampaignWebhookPayload{
+		Event:     CampaignEventCompleted,
+		Timestamp: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC),
+		Campaign:  CampaignWebhookCampaign{ID: campaign.ID, Name: campaign.Name},
+		Counts:    map[a8n.ChangesetState]int32{a8n.ChangesetStateMerged: 2},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var delivered *CampaignWebhookPayload
+			n := &CampaignNotifier{Doer: httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if have, want := req.URL.String(), campaign.WebhookURL; have != want {
+					t.Errorf("URL: have %q, want %q", have, want)
+				}
+
+				if have, want := req.Header.Get("X-Sourcegraph-Event"), string(payload.Event); have != want {
+					t.Errorf("event header: have %q, want %q", have, want)
+				}
+
+				body, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				have := req.Header.Get(CampaignWebhookSignatureHeader)
+				if want := SignCampaignWebhookPayload(body, campaign.WebhookSecret); have != want {
+					t.Errorf("signature: have %q, want %q", have, want)
+				}
+
+				if err := json.Unmarshal(body, &delivered); err != nil {
+					t.Fatal(err)
+				}
+
+				return &http.Response{
+					Status:     http.StatusText(tc.status),
+					StatusCode: tc.status,
+					Body:       ioutil.NopCloser(http.NoBody),
+				}, nil
+			})}
+
+			err := n.send(context.Background(), campaign, payload)
+			if have, want := err != nil, tc.wantErr; have != want {
+				t.Fatalf("have error %v, want error %v", err, want)
+			}
+
+			if diff := cmp.Diff(delivered, payload); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
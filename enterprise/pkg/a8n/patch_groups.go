@@ -0,0 +1,178 @@
+package a8n
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// patchGroupDiffConcurrency is the number of Changeset diffs that are
+// computed concurrently when grouping Changesets by their diffs.
+const patchGroupDiffConcurrency = 8
+
+// A PatchGroup is a group of Changesets whose diffs are identical except for
+// the paths of the changed files, e.g. because they make the same change to
+// the configuration files of many repositories.
+type PatchGroup struct {
+	// Hash is the hex-encoded SHA-256 hash of the normalized diff of the
+	// Changesets.
+	Hash string
+	// Changesets of the group, ordered by when they were created. The first
+	// one is the representative of the group.
+	Changesets []*a8n.Changeset
+}
+
+// DiffFunc returns the diff between the base and the head commit in a repo.
+type DiffFunc func(ctx context.Context, repo api.RepoName, base, head api.CommitID) ([]byte, error)
+
+// GitserverDiff is a DiffFunc that runs git diff on gitserver. Like the diff
+// of a pull request, it contains the changes of head since its merge base
+// with base.
+func GitserverDiff(ctx context.Context, repo api.RepoName, base, head api.CommitID) ([]byte, error) {
+	if !git.IsAbsoluteRevision(string(base)) || !git.IsAbsoluteRevision(string(head)) {
+		return nil, errors.Errorf("invalid commits %q and %q", base, head)
+	}
+	rdr, err := git.ExecReader(ctx, gitserver.Repo{Name: repo}, []string{
+		"diff",
+		string(base) + "..." + string(head),
+		"--",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	return ioutil.ReadAll(rdr)
+}
+
+// GroupChangesetsByPatch groups the Changesets by their diffs, which are
+// computed with getDiff. repoNames maps the IDs of the repos of the
+// Changesets to their names. Changesets whose commits aren't known or whose
+// diffs can't be computed are left out. The groups are ordered by size,
+// largest first.
+func GroupChangesetsByPatch(ctx context.Context, cs []*a8n.Changeset, repoNames map[int32]string, getDiff DiffFunc) ([]*PatchGroup, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		groups = map[string]*PatchGroup{}
+		sem    = make(chan struct{}, patchGroupDiffConcurrency)
+	)
+
+	for _, c := range cs {
+		repo, ok := repoNames[c.RepoID]
+		if !ok {
+			continue
+		}
+		base, head, err := c.Commits()
+		if err != nil || base == "" || head == "" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c *a8n.Changeset) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			d, err := getDiff(ctx, api.RepoName(repo), base, head)
+			if err == nil && len(d) > 0 {
+				var hash string
+				if hash, err = patchHash(d); err == nil {
+					mu.Lock()
+					g, ok := groups[hash]
+					if !ok {
+						g = &PatchGroup{Hash: hash}
+						groups[hash] = g
+					}
+					g.Changesets = append(g.Changesets, c)
+					mu.Unlock()
+				}
+			}
+			if err != nil && ctx.Err() == nil {
+				log15.Warn("GroupChangesetsByPatch: computing changeset diff failed", "changeset_id", c.ID, "repo", repo, "error", err)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	gs := make([]*PatchGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Slice(g.Changesets, func(i, j int) bool {
+			a, b := g.Changesets[i], g.Changesets[j]
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		})
+		gs = append(gs, g)
+	}
+	sort.Slice(gs, func(i, j int) bool {
+		if len(gs[i].Changesets) != len(gs[j].Changesets) {
+			return len(gs[i].Changesets) > len(gs[j].Changesets)
+		}
+		return gs[i].Changesets[0].ID < gs[j].Changesets[0].ID
+	})
+	return gs, nil
+}
+
+// patchHash returns the hash of the normalized multi-file diff d, which only
+// contains what the diff changes, but not where: the paths of the files, the
+// index lines with their blob hashes, and the line numbers and section
+// headings of the hunks are dropped, and the files are sorted by their
+// contents.
+func patchHash(d []byte) (string, error) {
+	fileDiffs, err := diff.ParseMultiFileDiff(d)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing diff")
+	}
+
+	files := make([]string, 0, len(fileDiffs))
+	for _, fd := range fileDiffs {
+		var buf bytes.Buffer
+		switch {
+		case fd.OrigName == "/dev/null":
+			buf.WriteString("added\n")
+		case fd.NewName == "/dev/null":
+			buf.WriteString("deleted\n")
+		}
+		for _, line := range fd.Extended {
+			// Mode changes are kept; the other extended header lines
+			// contain paths or blob hashes.
+			if strings.HasPrefix(line, "old mode ") || strings.HasPrefix(line, "new mode ") ||
+				strings.HasPrefix(line, "new file mode ") || strings.HasPrefix(line, "deleted file mode ") {
+				fmt.Fprintln(&buf, line)
+			}
+		}
+		for _, h := range fd.Hunks {
+			buf.WriteString("@@\n")
+			buf.Write(h.Body)
+		}
+		files = append(files, buf.String())
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(hash, "%d\n%s", len(f), f)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,133 @@
+package a8n
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+const testPatchA = `diff --git a/.eslintrc b/.eslintrc
+index 1111111..2222222 100644
+--- a/.eslintrc
++++ b/.eslintrc
+@@ -1,3 +1,3 @@ root
+ {
+-  "extends": "eslint:recommended"
++  "extends": "@sourcegraph/eslint-config"
+ }
+`
+
+// testPatchAMoved is testPatchA applied to a file at another path and line.
+const testPatchAMoved = `diff --git a/web/.eslintrc b/web/.eslintrc
+index 3333333..4444444 100644
+--- a/web/.eslintrc
++++ b/web/.eslintrc
+@@ -10,3 +10,3 @@
+ {
+-  "extends": "eslint:recommended"
++  "extends": "@sourcegraph/eslint-config"
+ }
+`
+
+const testPatchB = `diff --git a/.eslintrc b/.eslintrc
+index 1111111..5555555 100644
+--- a/.eslintrc
++++ b/.eslintrc
+@@ -1,3 +1,3 @@
+ {
+-  "extends": "eslint:recommended"
++  "extends": "prettier"
+ }
+`
+
+func TestPatchHash(t *testing.T) {
+	hash := func(d string) string {
+		t.Helper()
+		h, err := patchHash([]byte(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	if a, moved := hash(testPatchA), hash(testPatchAMoved); a != moved {
+		t.Errorf("hashes of the same patch at different paths differ: %s != %s", a, moved)
+	}
+	if a, b := hash(testPatchA), hash(testPatchB); a == b {
+		t.Errorf("hashes of different patches are equal: %s", a)
+	}
+	if ab, ba := hash(testPatchA+testPatchAMoved), hash(testPatchAMoved+testPatchA); ab != ba {
+		t.Errorf("hashes of the same files in a different order differ: %s != %s", ab, ba)
+	}
+}
+
+func TestGroupChangesetsByPatch(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	changeset := func(id int64, repoID int32, createdAt time.Time, head string) *a8n.Changeset {
+		return &a8n.Changeset{
+			ID:        id,
+			RepoID:    repoID,
+			CreatedAt: createdAt,
+			Metadata:  &github.PullRequest{BaseRefOid: "base", HeadRefOid: head},
+		}
+	}
+
+	cs := []*a8n.Changeset{
+		changeset(1, 42, now.Add(time.Minute), "a"),
+		changeset(2, 43, now, "a-moved"),
+		changeset(3, 44, now, "b"),
+		changeset(4, 45, now, "a"),
+		// Not synced since its commits were recorded.
+		{ID: 5, RepoID: 46, Metadata: &github.PullRequest{}},
+		// Its diff can't be computed.
+		changeset(6, 47, now, "missing"),
+	}
+	repoNames := map[int32]string{
+		42: "github.com/sourcegraph/a",
+		43: "github.com/sourcegraph/b",
+		44: "github.com/sourcegraph/c",
+		45: "github.com/sourcegraph/d",
+		46: "github.com/sourcegraph/e",
+		47: "github.com/sourcegraph/f",
+	}
+	patches := map[api.CommitID]string{
+		"a":       testPatchA,
+		"a-moved": testPatchAMoved,
+		"b":       testPatchB,
+	}
+
+	getDiff := func(ctx context.Context, repo api.RepoName, base, head api.CommitID) ([]byte, error) {
+		p, ok := patches[head]
+		if !ok {
+			return nil, errors.New("revision not found")
+		}
+		return []byte(p), nil
+	}
+
+	groups, err := GroupChangesetsByPatch(context.Background(), cs, repoNames, getDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var have [][]int64
+	for _, g := range groups {
+		var ids []int64
+		for _, c := range g.Changesets {
+			ids = append(ids, c.ID)
+		}
+		have = append(have, ids)
+	}
+
+	// The representative of a group is the changeset created first.
+	want := [][]int64{{2, 4, 1}, {3}}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("wrong groups. want=%v, have=%v", want, have)
+	}
+}
@@ -1,7 +1,9 @@
 package resolvers
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"path"
 	"sync"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	ee "github.com/sourcegraph/sourcegraph/enterprise/pkg/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 )
@@ -39,7 +42,11 @@ func (r *campaignsConnectionResolver) Nodes(ctx context.Context) ([]graphqlbacke
 }
 
 func (r *campaignsConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
-	opts := ee.CountCampaignsOpts{ChangesetID: r.opts.ChangesetID}
+	opts := ee.CountCampaignsOpts{
+		ChangesetID:      r.opts.ChangesetID,
+		Labels:           r.opts.Labels,
+		CampaignUserOpts: r.opts.CampaignUserOpts,
+	}
 	count, err := r.store.CountCampaigns(ctx, opts)
 	return int32(count), err
 }
@@ -118,6 +125,49 @@ func (r *campaignResolver) UpdatedAt() graphqlbackend.DateTime {
 	return graphqlbackend.DateTime{Time: r.Campaign.UpdatedAt}
 }
 
+func (r *campaignResolver) ClosedAt() *graphqlbackend.DateTime {
+	if !r.Campaign.Closed() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.Campaign.ClosedAt}
+}
+
+func (r *campaignResolver) Labels() []string {
+	return r.Campaign.Labels
+}
+
+func (r *campaignResolver) WebhookURL() *string {
+	if r.Campaign.WebhookURL == "" {
+		return nil
+	}
+	return &r.Campaign.WebhookURL
+}
+
+func (r *campaignResolver) ChangesetsCSV(ctx context.Context) (string, error) {
+	// 🚨 SECURITY: Only site admins may export the changesets for now
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return "", err
+	}
+
+	opts := ee.ListChangesetsOpts{CampaignID: r.Campaign.ID, Limit: -1}
+	cs, _, err := r.store.ListChangesets(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	repoNames, err := r.repoNames(ctx, cs)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := ee.WriteChangesetsCSV(&buf, cs, repoNames); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
 func (r *campaignResolver) Changesets(ctx context.Context, args struct {
 	graphqlutil.ConnectionArgs
 }) graphqlbackend.ChangesetsConnectionResolver {
@@ -130,6 +180,57 @@ func (r *campaignResolver) Changesets(ctx context.Context, args struct {
 	}
 }
 
+func (r *campaignResolver) PatchGroups(ctx context.Context) ([]graphqlbackend.PatchGroupResolver, error) {
+	// 🚨 SECURITY: Only site admins may access the patch groups for now, since
+	// the diffs are computed without checking repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opts := ee.ListChangesetsOpts{CampaignID: r.Campaign.ID, Limit: -1}
+	cs, _, err := r.store.ListChangesets(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	repoNames, err := r.repoNames(ctx, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := ee.GroupChangesetsByPatch(ctx, cs, repoNames, ee.GitserverDiff)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.PatchGroupResolver, 0, len(groups))
+	for _, g := range groups {
+		resolvers = append(resolvers, &patchGroupResolver{store: r.store, group: g})
+	}
+	return resolvers, nil
+}
+
+// repoNames returns the names of the repos of the Changesets by their IDs.
+func (r *campaignResolver) repoNames(ctx context.Context, cs []*a8n.Changeset) (map[int32]string, error) {
+	var repoIDs []uint32
+	for _, c := range cs {
+		repoIDs = append(repoIDs, uint32(c.RepoID))
+	}
+
+	repoNames := make(map[int32]string, len(repoIDs))
+	if len(repoIDs) > 0 {
+		store := repos.NewDBStore(r.store.DB(), sql.TxOptions{})
+		rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range rs {
+			repoNames[int32(repo.ID)] = repo.Name
+		}
+	}
+	return repoNames, nil
+}
+
 func (r *campaignResolver) ChangesetCountsOverTime(
 	ctx context.Context,
 	args *graphqlbackend.ChangesetCountsArgs,
@@ -187,3 +288,24 @@ func (r *campaignResolver) ChangesetCountsOverTime(
 
 	return resolvers, nil
 }
+
+type patchGroupResolver struct {
+	store *ee.Store
+	group *ee.PatchGroup
+}
+
+func (r *patchGroupResolver) Hash() string { return r.group.Hash }
+
+func (r *patchGroupResolver) Representative() graphqlbackend.ChangesetResolver {
+	return &changesetResolver{store: r.store, Changeset: r.group.Changesets[0]}
+}
+
+func (r *patchGroupResolver) Changesets() []graphqlbackend.ChangesetResolver {
+	resolvers := make([]graphqlbackend.ChangesetResolver, 0, len(r.group.Changesets))
+	for _, c := range r.group.Changesets {
+		resolvers = append(resolvers, &changesetResolver{store: r.store, Changeset: c})
+	}
+	return resolvers
+}
+
+func (r *patchGroupResolver) Count() int32 { return int32(len(r.group.Changesets)) }
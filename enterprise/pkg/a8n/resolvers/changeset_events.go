@@ -102,3 +102,12 @@ func (r *changesetCountsResolver) Open() int32                 { return r.counts
 func (r *changesetCountsResolver) OpenApproved() int32         { return r.counts.OpenApproved }
 func (r *changesetCountsResolver) OpenChangesRequested() int32 { return r.counts.OpenChangesRequested }
 func (r *changesetCountsResolver) OpenPending() int32          { return r.counts.OpenPending }
+
+type labelChangesetCountsResolver struct {
+	counts *ee.LabelChangesetCounts
+}
+
+func (r *labelChangesetCountsResolver) Label() string { return r.counts.Label }
+func (r *labelChangesetCountsResolver) Counts() graphqlbackend.ChangesetCountsResolver {
+	return &changesetCountsResolver{counts: r.counts.Counts}
+}
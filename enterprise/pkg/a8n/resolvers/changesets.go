@@ -179,3 +179,21 @@ func (r *changesetResolver) Events(ctx context.Context, args *struct {
 		},
 	}, nil
 }
+
+func (r *changesetResolver) SyncFailures() int32 {
+	return r.Changeset.NumFailures
+}
+
+func (r *changesetResolver) SyncError() *string {
+	if r.Changeset.FailureMessage == "" {
+		return nil
+	}
+	return &r.Changeset.FailureMessage
+}
+
+func (r *changesetResolver) NextSyncRetryAt() *graphqlbackend.DateTime {
+	if r.Changeset.NextRetryAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.Changeset.NextRetryAt}
+}
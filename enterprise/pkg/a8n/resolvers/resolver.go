@@ -3,6 +3,10 @@ package resolvers
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -11,10 +15,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	ee "github.com/sourcegraph/sourcegraph/enterprise/pkg/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 )
 
@@ -104,6 +111,10 @@ func (r *Resolver) AddChangesetsToCampaign(ctx context.Context, args *graphqlbac
 		return nil, err
 	}
 
+	if campaign.Closed() {
+		return nil, errors.New("changesets can't be added to a closed campaign")
+	}
+
 	changesets, _, err := tx.ListChangesets(ctx, ee.ListChangesetsOpts{IDs: changesetIDs})
 	if err != nil {
 		return nil, err
@@ -186,6 +197,12 @@ func (r *Resolver) UpdateCampaign(ctx context.Context, args *graphqlbackend.Upda
 		return nil, err
 	}
 
+	// Reject the update if the campaign was updated since the client read
+	// it. DateTime only has a precision of seconds.
+	if args.Input.UpdatedAt != nil && !campaign.UpdatedAt.Truncate(time.Second).Equal(args.Input.UpdatedAt.Time) {
+		return nil, ee.ConflictError{CampaignID: campaign.ID}
+	}
+
 	if args.Input.Name != nil {
 		campaign.Name = *args.Input.Name
 	}
@@ -194,6 +211,25 @@ func (r *Resolver) UpdateCampaign(ctx context.Context, args *graphqlbackend.Upda
 		campaign.Description = *args.Input.Description
 	}
 
+	if args.Input.Labels != nil {
+		labels, err := normalizeCampaignLabels(*args.Input.Labels)
+		if err != nil {
+			return nil, err
+		}
+		campaign.Labels = labels
+	}
+
+	if args.Input.WebhookURL != nil {
+		if err := validateCampaignWebhookURL(*args.Input.WebhookURL); err != nil {
+			return nil, err
+		}
+		campaign.WebhookURL = *args.Input.WebhookURL
+	}
+
+	if args.Input.WebhookSecret != nil {
+		campaign.WebhookSecret = *args.Input.WebhookSecret
+	}
+
 	if err := tx.UpdateCampaign(ctx, campaign); err != nil {
 		return nil, err
 	}
@@ -220,20 +256,261 @@ func (r *Resolver) DeleteCampaign(ctx context.Context, args *graphqlbackend.Dele
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
-func (r *Resolver) Campaigns(ctx context.Context, args *graphqlutil.ConnectionArgs) (graphqlbackend.CampaignsConnectionResolver, error) {
+func (r *Resolver) CloseCampaign(ctx context.Context, args *graphqlbackend.CloseCampaignArgs) (graphqlbackend.CloseCampaignResultResolver, error) {
+	// 🚨 SECURITY: Only site admins may close campaigns for now, since
+	// changesets are closed with the access token of the code host connection.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	campaignID, err := unmarshalCampaignID(args.Campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := r.store.GetCampaign(ctx, ee.GetCampaignOpts{ID: campaignID})
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := r.changesetSyncer().CloseCampaign(ctx, campaign, args.CloseChangesets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &closeCampaignResultResolver{store: r.store, campaign: campaign, changesets: closed}, nil
+}
+
+type closeCampaignResultResolver struct {
+	store      *ee.Store
+	campaign   *a8n.Campaign
+	changesets []*ee.ClosedChangeset
+}
+
+func (r *closeCampaignResultResolver) Campaign() graphqlbackend.CampaignResolver {
+	return &campaignResolver{store: r.store, Campaign: r.campaign}
+}
+
+func (r *closeCampaignResultResolver) Changesets() []graphqlbackend.ClosedChangesetResolver {
+	resolvers := make([]graphqlbackend.ClosedChangesetResolver, 0, len(r.changesets))
+	for _, c := range r.changesets {
+		resolvers = append(resolvers, &closedChangesetResolver{store: r.store, closed: c})
+	}
+	return resolvers
+}
+
+type closedChangesetResolver struct {
+	store  *ee.Store
+	closed *ee.ClosedChangeset
+}
+
+func (r *closedChangesetResolver) Changeset() graphqlbackend.ChangesetResolver {
+	return &changesetResolver{store: r.store, Changeset: r.closed.Changeset}
+}
+
+func (r *closedChangesetResolver) Closed() bool   { return r.closed.Closed }
+func (r *closedChangesetResolver) Detached() bool { return r.closed.Detached }
+
+func (r *closedChangesetResolver) Error() *string {
+	if r.closed.Err == nil {
+		return nil
+	}
+	msg := r.closed.Err.Error()
+	return &msg
+}
+
+// validateCampaignWebhookURL returns an error if the given webhook URL of a
+// campaign is neither empty nor an absolute HTTP(S) URL.
+func validateCampaignWebhookURL(rawurl string) error {
+	if rawurl == "" {
+		return nil
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return errors.Wrap(err, "invalid campaign webhook URL")
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid campaign webhook URL %q: must be an absolute http or https URL", rawurl)
+	}
+
+	return nil
+}
+
+// normalizeCampaignLabels trims the whitespace around the given campaign
+// labels and removes duplicates, keeping their order. Empty labels are
+// rejected.
+func normalizeCampaignLabels(labels []string) ([]string, error) {
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return nil, errors.New("campaign labels must not be empty")
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	return normalized, nil
+}
+
+func (r *Resolver) Campaigns(ctx context.Context, args *graphqlbackend.ListCampaignArgs) (graphqlbackend.CampaignsConnectionResolver, error) {
 	// 🚨 SECURITY: Only site admins may read campaigns for now
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 		return nil, err
 	}
 
+	opts := ee.ListCampaignsOpts{
+		Limit: int(args.GetFirst()),
+	}
+	if args.Labels != nil {
+		opts.Labels = *args.Labels
+	}
+
 	return &campaignsConnectionResolver{
 		store: r.store,
-		opts: ee.ListCampaignsOpts{
-			Limit: int(args.GetFirst()),
-		},
+		opts:  opts,
 	}, nil
 }
 
+func (r *Resolver) ViewerCampaigns(ctx context.Context, args *graphqlbackend.ListViewerCampaignsArgs) (graphqlbackend.CampaignsConnectionResolver, error) {
+	// 🚨 SECURITY: Any authenticated user may list the campaigns they are
+	// involved in, since the list is scoped to the viewer.
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v", backend.ErrNotAuthenticated)
+	}
+
+	opts := ee.ListCampaignsOpts{
+		Limit: int(args.GetFirst()),
+	}
+
+	switch args.Role {
+	case "AUTHOR":
+		opts.AuthorID = user.ID
+	case "REVIEWER":
+		if opts.Reviewers, err = viewerCodeHostUsers(ctx, user); err != nil {
+			return nil, err
+		}
+	case "PARTICIPANT":
+		if opts.Participants, err = viewerCodeHostUsers(ctx, user); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown campaign role %q", args.Role)
+	}
+
+	return &campaignsConnectionResolver{
+		store: r.store,
+		opts:  opts,
+	}, nil
+}
+
+// viewerCodeHostUsers returns the users of the code hosts that the given
+// Sourcegraph user is known as. The returned slice is never nil, so that a
+// user without code host accounts matches no campaigns.
+func viewerCodeHostUsers(ctx context.Context, user *types.User) ([]ee.CodeHostUser, error) {
+	// Like the Bitbucket Server authz provider, we assume that the usernames
+	// of Sourcegraph and Bitbucket Server match.
+	users := []ee.CodeHostUser{{
+		ServiceType: bitbucketserver.ServiceType,
+		Login:       user.Username,
+	}}
+
+	accts, err := db.ExternalAccounts.List(ctx, db.ExternalAccountsListOptions{
+		UserID:      user.ID,
+		ServiceType: github.ServiceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acct := range accts {
+		data, _, err := github.GetExternalAccountData(&acct.ExternalAccountData)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil || data.Login == nil {
+			continue
+		}
+		users = append(users, ee.CodeHostUser{
+			ServiceType: github.ServiceType,
+			Login:       *data.Login,
+		})
+	}
+
+	return users, nil
+}
+
+func (r *Resolver) ChangesetCountsByLabel(ctx context.Context, args *graphqlbackend.ChangesetCountsByLabelArgs) ([]graphqlbackend.LabelChangesetCountsResolver, error) {
+	// 🚨 SECURITY: Only site admins may access the counts for now
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	campaignsOpts := ee.ListCampaignsOpts{Limit: -1}
+	if args.Labels != nil {
+		campaignsOpts.Labels = *args.Labels
+	}
+	campaigns, _, err := r.store.ListCampaigns(ctx, campaignsOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	changesetIDs := map[int64]bool{}
+	for _, c := range campaigns {
+		if len(c.Labels) == 0 {
+			continue
+		}
+		for _, id := range c.ChangesetIDs {
+			changesetIDs[id] = true
+		}
+	}
+
+	resolvers := []graphqlbackend.LabelChangesetCountsResolver{}
+	if len(changesetIDs) == 0 {
+		return resolvers, nil
+	}
+
+	ids := make([]int64, 0, len(changesetIDs))
+	for id := range changesetIDs {
+		ids = append(ids, id)
+	}
+
+	cs, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{IDs: ids, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	es, _, err := r.store.ListChangesetEvents(ctx, ee.ListChangesetEventsOpts{
+		ChangesetIDs: ids,
+		Limit:        -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ee.Event, len(es))
+	for i, e := range es {
+		events[i] = e
+	}
+
+	counts, err := ee.CalcCountsByLabel(time.Now().UTC(), campaigns, cs, events...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range counts {
+		resolvers = append(resolvers, &labelChangesetCountsResolver{counts: c})
+	}
+
+	return resolvers, nil
+}
+
 func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.CreateChangesetsArgs) (_ []graphqlbackend.ChangesetResolver, err error) {
 	// 🚨 SECURITY: Only site admins may create changesets for now
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
@@ -307,6 +584,7 @@ func (r *Resolver) CreateChangesets(ctx context.Context, args *graphqlbackend.Cr
 		ReposStore:  store,
 		Store:       r.store,
 		HTTPFactory: r.httpFactory,
+		Notifier:    &ee.CampaignNotifier{Store: r.store},
 	}
 	if err = syncer.SyncChangesets(ctx, cs...); err != nil {
 		return nil, err
@@ -337,3 +615,122 @@ func (r *Resolver) Changesets(ctx context.Context, args *graphqlutil.ConnectionA
 		},
 	}, nil
 }
+
+func (r *Resolver) RetryChangeset(ctx context.Context, args *graphqlbackend.RetryChangesetArgs) (graphqlbackend.ChangesetResolver, error) {
+	// 🚨 SECURITY: Only site admins may retry changesets for now
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	changesetID, err := unmarshalChangesetID(args.Changeset)
+	if err != nil {
+		return nil, err
+	}
+
+	changeset, err := r.store.GetChangeset(ctx, ee.GetChangesetOpts{ID: changesetID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.changesetSyncer().SyncChangesets(ctx, changeset); err != nil {
+		return nil, err
+	}
+
+	return &changesetResolver{store: r.store, Changeset: changeset}, nil
+}
+
+func (r *Resolver) CommentOnChangesets(ctx context.Context, args *graphqlbackend.CommentOnChangesetsArgs) ([]graphqlbackend.ChangesetResolver, error) {
+	// 🚨 SECURITY: Only site admins may comment on changesets for now, since
+	// comments are posted with the access token of the code host connection.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if args.Body == "" {
+		return nil, errors.New("empty comment body")
+	}
+
+	changesets, err := r.changesetsByIDs(ctx, args.Changesets)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.changesetSyncer().CommentOnChangesets(ctx, args.Body, changesets...); err != nil {
+		return nil, err
+	}
+
+	return r.changesetResolvers(changesets), nil
+}
+
+func (r *Resolver) ReviewChangesets(ctx context.Context, args *graphqlbackend.ReviewChangesetsArgs) ([]graphqlbackend.ChangesetResolver, error) {
+	// 🚨 SECURITY: Only site admins may review changesets for now, since
+	// reviews are submitted with the access token of the code host connection.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var body string
+	if args.Body != nil {
+		body = *args.Body
+	}
+
+	changesets, err := r.changesetsByIDs(ctx, args.Changesets)
+	if err != nil {
+		return nil, err
+	}
+
+	event := repos.ChangesetReviewEvent(args.Event)
+	if err = r.changesetSyncer().ReviewChangesets(ctx, event, body, changesets...); err != nil {
+		return nil, err
+	}
+
+	return r.changesetResolvers(changesets), nil
+}
+
+func (r *Resolver) changesetsByIDs(ctx context.Context, ids []graphql.ID) ([]*a8n.Changeset, error) {
+	changesetIDs := make([]int64, 0, len(ids))
+	set := map[int64]struct{}{}
+	for _, changesetID := range ids {
+		id, err := unmarshalChangesetID(changesetID)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := set[id]; !ok {
+			changesetIDs = append(changesetIDs, id)
+			set[id] = struct{}{}
+		}
+	}
+
+	changesets, _, err := r.store.ListChangesets(ctx, ee.ListChangesetsOpts{IDs: changesetIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range changesets {
+		delete(set, c.ID)
+	}
+
+	if len(set) > 0 {
+		return nil, errors.Errorf("changesets %v not found", set)
+	}
+
+	return changesets, nil
+}
+
+func (r *Resolver) changesetSyncer() *ee.ChangesetSyncer {
+	return &ee.ChangesetSyncer{
+		ReposStore:  repos.NewDBStore(r.store.DB(), sql.TxOptions{}),
+		Store:       r.store,
+		HTTPFactory: r.httpFactory,
+		Notifier:    &ee.CampaignNotifier{Store: r.store},
+	}
+}
+
+func (r *Resolver) changesetResolvers(changesets []*a8n.Changeset) []graphqlbackend.ChangesetResolver {
+	resolvers := make([]graphqlbackend.ChangesetResolver, 0, len(changesets))
+	for _, c := range changesets {
+		resolvers = append(resolvers, &changesetResolver{store: r.store, Changeset: c})
+	}
+	return resolvers
+}
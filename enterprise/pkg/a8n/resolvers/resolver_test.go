@@ -128,6 +128,7 @@ func TestCampaigns(t *testing.T) {
 		CreatedAt   string
 		UpdatedAt   string
 		Namespace   UserOrg
+		Labels      []string
 	}
 
 	var campaigns struct{ Admin, Org Campaign }
@@ -149,7 +150,7 @@ func TestCampaigns(t *testing.T) {
 		fragment u on User { id, databaseID, siteAdmin }
 		fragment o on Org  { id, name }
 		fragment c on Campaign {
-			id, name, description, createdAt, updatedAt
+			id, name, description, createdAt, updatedAt, labels
 			author    { ...u }
 			namespace {
 				... on User { ...u }
@@ -186,7 +187,7 @@ func TestCampaigns(t *testing.T) {
 		fragment u on User { id, databaseID, siteAdmin }
 		fragment o on Org  { id, name }
 		fragment c on Campaign {
-			id, name, description, createdAt, updatedAt
+			id, name, description, createdAt, updatedAt, labels
 			author    { ...u }
 			namespace {
 				... on User { ...u }
@@ -226,11 +227,13 @@ func TestCampaigns(t *testing.T) {
 
 	campaigns.Admin.Name = "Updated Admin Campaign Name"
 	campaigns.Admin.Description = "Updated Admin Campaign Description"
+	campaigns.Admin.Labels = []string{"migration", "q1"}
 	updateInput := map[string]interface{}{
 		"input": map[string]interface{}{
 			"id":          campaigns.Admin.ID,
 			"name":        campaigns.Admin.Name,
 			"description": campaigns.Admin.Description,
+			"labels":      []string{" migration ", "q1", "migration"},
 		},
 	}
 	var updated struct {
@@ -241,7 +244,7 @@ func TestCampaigns(t *testing.T) {
 		fragment u on User { id, databaseID, siteAdmin }
 		fragment o on Org  { id, name }
 		fragment c on Campaign {
-			id, name, description, createdAt, updatedAt
+			id, name, description, createdAt, updatedAt, labels
 			author    { ...u }
 			namespace {
 				... on User { ...u }
@@ -258,6 +261,44 @@ func TestCampaigns(t *testing.T) {
 		t.Errorf("wrong campaign updated. diff=%s", cmp.Diff(haveUpdated, wantUpdated))
 	}
 
+	// An update based on a stale read of the campaign is rejected.
+	staleInput := map[string]interface{}{
+		"input": map[string]interface{}{
+			"id":        campaigns.Admin.ID,
+			"name":      "Stale Admin Campaign Name",
+			"updatedAt": "2006-01-02T15:04:05Z",
+		},
+	}
+	errs := exec(ctx, t, s, staleInput, &updated, `
+		mutation($input: UpdateCampaignInput!){
+			updateCampaign(input: $input) { id }
+		}
+	`)
+	if len(errs) != 1 || errs[0].Extensions["code"] != "ErrConflict" {
+		t.Errorf("stale update: have errors %v, want a conflict", errs)
+	}
+
+	var labeled struct {
+		Campaigns struct {
+			Nodes      []struct{ ID string }
+			TotalCount int
+		}
+	}
+
+	mustExec(ctx, t, s, nil, &labeled, `
+		query {
+			campaigns(labels: ["q1"]) { nodes { id }, totalCount }
+		}
+	`)
+
+	if have, want := labeled.Campaigns.TotalCount, 1; have != want {
+		t.Errorf("have %d campaigns labeled q1, want %d", have, want)
+	}
+
+	if len(labeled.Campaigns.Nodes) != 1 || labeled.Campaigns.Nodes[0].ID != campaigns.Admin.ID {
+		t.Errorf("wrong campaigns labeled q1: %+v", labeled.Campaigns.Nodes)
+	}
+
 	store := repos.NewDBStore(dbconn.Global, sql.TxOptions{})
 	githubExtSvc := &repos.ExternalService{
 		Kind:        "GITHUB",
@@ -407,7 +448,7 @@ func TestCampaigns(t *testing.T) {
 				},
 				ReviewState: "CHANGES_REQUESTED",
 				Events: ChangesetEventConnection{
-					TotalCount: 0,
+					TotalCount: 2,
 				},
 			},
 		}
@@ -0,0 +1,71 @@
+package a8n
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+// A Backoff is an exponential backoff policy for retrying changesets that
+// failed to sync with their code host.
+type Backoff struct {
+	// Min is the delay before the first retry.
+	Min time.Duration
+	// Max is the upper bound of the delay between retries.
+	Max time.Duration
+}
+
+// DefaultBackoff is the Backoff used by the ChangesetSyncer unless configured
+// otherwise.
+var DefaultBackoff = Backoff{Min: time.Minute, Max: 8 * time.Hour}
+
+// Delay returns how long to wait before retrying after the given number of
+// consecutive failures. The delay doubles with each failure, starting at Min
+// and capped at Max.
+func (b Backoff) Delay(failures int32) time.Duration {
+	d := b.Min
+	for i := int32(1); i < failures && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// recordSyncFailure updates the retry state of the given Changeset after a
+// failed sync. Transient errors schedule the next retry according to the
+// given Backoff, while permanent ones leave it to be retried manually.
+func recordSyncFailure(c *a8n.Changeset, err error, b Backoff, now time.Time) {
+	c.NumFailures++
+	c.FailureMessage = err.Error()
+
+	if isPermanentSyncError(err) {
+		c.NextRetryAt = time.Time{}
+	} else {
+		c.NextRetryAt = now.Add(b.Delay(c.NumFailures))
+	}
+}
+
+// isPermanentSyncError returns true if the given error, returned by a code
+// host while syncing a changeset, is unlikely to go away by itself, such as
+// when the changeset was deleted or the credentials are invalid.
+func isPermanentSyncError(err error) bool {
+	if errcode.IsNotFound(err) || errcode.IsUnauthorized(err) || github.IsNotFound(err) {
+		return true
+	}
+
+	if github.IsRateLimitExceeded(err) {
+		return false
+	}
+
+	switch github.HTTPErrorCode(err) {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,198 @@
+package a8n
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Min: time.Minute, Max: time.Hour}
+
+	for _, tc := range []struct {
+		failures int32
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{1000, time.Hour},
+	} {
+		if have := b.Delay(tc.failures); have != tc.want {
+			t.Errorf("Delay(%d): have %s, want %s", tc.failures, have, tc.want)
+		}
+	}
+}
+
+func TestRecordSyncFailure(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	b := Backoff{Min: time.Minute, Max: time.Hour}
+
+	t.Run("transient", func(t *testing.T) {
+		c := &a8n.Changeset{NumFailures: 2}
+		err := &github.APIError{Code: http.StatusBadGateway, Message: "bad gateway"}
+
+		recordSyncFailure(c, err, b, now)
+
+		if have, want := c.NumFailures, int32(3); have != want {
+			t.Errorf("NumFailures: have %d, want %d", have, want)
+		}
+		if have, want := c.FailureMessage, err.Error(); have != want {
+			t.Errorf("FailureMessage: have %q, want %q", have, want)
+		}
+		if have, want := c.NextRetryAt, now.Add(4*time.Minute); !have.Equal(want) {
+			t.Errorf("NextRetryAt: have %s, want %s", have, want)
+		}
+		if c.SyncDue(now) {
+			t.Error("SyncDue before NextRetryAt")
+		}
+		if !c.SyncDue(c.NextRetryAt) {
+			t.Error("not SyncDue at NextRetryAt")
+		}
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		for _, err := range []error{
+			&github.APIError{Code: http.StatusNotFound},
+			&github.APIError{Code: http.StatusUnauthorized},
+			&errcode.Mock{Message: "gone", IsNotFound: true},
+		} {
+			c := &a8n.Changeset{}
+
+			recordSyncFailure(c, err, b, now)
+
+			if !c.NextRetryAt.IsZero() {
+				t.Errorf("%v: NextRetryAt should be zero, have %s", err, c.NextRetryAt)
+			}
+			if c.SyncDue(now.Add(24 * time.Hour)) {
+				t.Errorf("%v: permanent failure should not be SyncDue", err)
+			}
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		c := &a8n.Changeset{}
+		err := &github.APIError{Code: http.StatusForbidden, Message: "API rate limit exceeded"}
+
+		recordSyncFailure(c, err, b, now)
+
+		if c.NextRetryAt.IsZero() {
+			t.Error("rate limit errors should be retried")
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		c := &a8n.Changeset{}
+
+		recordSyncFailure(c, errors.New("connection reset"), b, now)
+
+		if have, want := c.NextRetryAt, now.Add(time.Minute); !have.Equal(want) {
+			t.Errorf("NextRetryAt: have %s, want %s", have, want)
+		}
+	})
+}
+
+// fakeChangesetSource fails to load a batch of Changesets if it contains one
+// of the Changesets in errs.
+type fakeChangesetSource struct {
+	errs  map[int64]error
+	calls int
+}
+
+func (s *fakeChangesetSource) LoadChangesets(ctx context.Context, cs ...*repos.Changeset) error {
+	s.calls++
+	for _, c := range cs {
+		if err, ok := s.errs[c.Changeset.ID]; ok {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestLoadChangesets(t *testing.T) {
+	notFound := &github.APIError{Code: http.StatusNotFound, Message: "not found"}
+
+	var cs []*repos.Changeset
+	for id := int64(1); id <= 3; id++ {
+		cs = append(cs, &repos.Changeset{Changeset: &a8n.Changeset{ID: id}})
+	}
+
+	t.Run("batch succeeds", func(t *testing.T) {
+		src := &fakeChangesetSource{}
+
+		errs, err := loadChangesets(context.Background(), src, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 0 {
+			t.Errorf("have errors %v, want none", errs)
+		}
+		if have, want := src.calls, 1; have != want {
+			t.Errorf("calls: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("one changeset fails", func(t *testing.T) {
+		src := &fakeChangesetSource{errs: map[int64]error{2: notFound}}
+
+		errs, err := loadChangesets(context.Background(), src, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := len(errs), 1; have != want {
+			t.Fatalf("errors: have %d, want %d", have, want)
+		}
+		if have := errs[cs[1]]; have != notFound {
+			t.Errorf("error of changeset 2: have %v, want %v", have, notFound)
+		}
+		if have, want := src.calls, 1+len(cs); have != want {
+			t.Errorf("calls: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		rateLimited := &github.APIError{Code: http.StatusForbidden, Message: "API rate limit exceeded"}
+		src := &fakeChangesetSource{errs: map[int64]error{2: rateLimited}}
+
+		errs, err := loadChangesets(context.Background(), src, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := len(errs), len(cs); have != want {
+			t.Errorf("errors: have %d, want %d", have, want)
+		}
+		if have, want := src.calls, 1; have != want {
+			t.Errorf("calls: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		src := &fakeChangesetSource{errs: map[int64]error{1: context.Canceled}}
+
+		errs, err := loadChangesets(ctx, src, cs)
+
+		if err != context.Canceled {
+			t.Errorf("have error %v, want %v", err, context.Canceled)
+		}
+		if len(errs) != 0 {
+			t.Errorf("have errors %v, want none", errs)
+		}
+		if have, want := src.calls, 1; have != want {
+			t.Errorf("calls: have %d, want %d", have, want)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package a8n
+
+import (
+	"context"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/a8n"
+)
+
+// CommentOnChangesets adds a comment with the given body to each of the given
+// changesets on their code host. The changesets are synced afterwards, so that
+// the new comments are recorded as changeset events.
+func (s *ChangesetSyncer) CommentOnChangesets(ctx context.Context, body string, cs ...*a8n.Changeset) error {
+	return s.actOnChangesets(ctx, cs, func(src repos.ChangesetReviewSource, c *repos.Changeset) error {
+		return src.CommentOnChangeset(ctx, c, body)
+	})
+}
+
+// ReviewChangesets submits a review with the given event and body of each of
+// the given changesets on their code host. The changesets are synced
+// afterwards, so that the new reviews are recorded as changeset events.
+func (s *ChangesetSyncer) ReviewChangesets(ctx context.Context, event repos.ChangesetReviewEvent, body string, cs ...*a8n.Changeset) error {
+	return s.actOnChangesets(ctx, cs, func(src repos.ChangesetReviewSource, c *repos.Changeset) error {
+		return src.ReviewChangeset(ctx, c, event, body)
+	})
+}
+
+// actOnChangesets calls act for each of the given changesets with the source
+// of its code host and syncs the changesets afterwards. Failing to act on one
+// changeset doesn't stop acting on the others, and all errors are returned.
+func (s *ChangesetSyncer) actOnChangesets(ctx context.Context, cs []*a8n.Changeset, act func(repos.ChangesetReviewSource, *repos.Changeset) error) error {
+	if len(cs) == 0 {
+		return nil
+	}
+
+	batches, err := s.batchChangesets(ctx, cs...)
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, b := range batches {
+		src, ok := b.ChangesetSource.(repos.ChangesetReviewSource)
+		if !ok {
+			for _, c := range b.Changesets {
+				errs = multierror.Append(errs, errors.Errorf("changeset %d: code host doesn't support comments and reviews", c.Changeset.ID))
+			}
+			continue
+		}
+
+		for _, c := range b.Changesets {
+			if err := act(src, c); err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "changeset %d", c.Changeset.ID))
+			}
+		}
+	}
+
+	if err := s.SyncChangesets(ctx, cs...); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
@@ -92,6 +93,29 @@ func (e AlreadyExistError) Error() string {
 	return fmt.Sprintf("Changesets already exist: %v", e.ChangesetIDs)
 }
 
+// ConflictError is returned by UpdateCampaign and UpdateChangesets when the
+// given records were updated or deleted by someone else since they were read,
+// i.e. when their UpdatedAt doesn't match the one in the database anymore.
+// These records are left untouched, so that concurrent updates don't silently
+// overwrite each other. Callers should read them again and retry.
+type ConflictError struct {
+	CampaignID   int64
+	ChangesetIDs []int64
+}
+
+func (e ConflictError) Error() string {
+	if e.CampaignID != 0 {
+		return fmt.Sprintf("Campaign was modified concurrently: %d", e.CampaignID)
+	}
+	return fmt.Sprintf("Changesets were modified concurrently: %v", e.ChangesetIDs)
+}
+
+// Extensions implements the GraphQL error extensions, which allows clients to
+// tell conflicts apart from other errors.
+func (e ConflictError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "ErrConflict"}
+}
+
 // CreateChangesets creates the given Changesets. If a subset of the given
 // Changesets with the same RepoID and ExternalID already exists in the
 // database, it overwrites the fields of the affected changeset pointers with
@@ -147,7 +171,11 @@ WITH batch AS (
       metadata              jsonb,
       campaign_ids          jsonb,
       external_id           text,
-      external_service_type text
+      external_service_type text,
+      num_failures          integer,
+      failure_message       text,
+      next_retry_at         timestamptz,
+      previous_updated_at   timestamptz
     )
   )
   WITH ORDINALITY
@@ -164,7 +192,10 @@ changed AS (
     metadata,
     campaign_ids,
     external_id,
-    external_service_type
+    external_service_type,
+    num_failures,
+    failure_message,
+    next_retry_at
   )
   SELECT
     repo_id,
@@ -173,7 +204,10 @@ changed AS (
     metadata,
     campaign_ids,
     external_id,
-    external_service_type
+    external_service_type,
+    num_failures,
+    failure_message,
+    next_retry_at
   FROM batch
   ON CONFLICT ON CONSTRAINT
     changesets_repo_external_id_unique
@@ -191,7 +225,10 @@ SELECT
   COALESCE(changed.metadata, existing.metadata) AS metadata,
   COALESCE(changed.campaign_ids, existing.campaign_ids) AS campaign_ids,
   COALESCE(changed.external_id, existing.external_id) AS external_id,
-  COALESCE(changed.external_service_type, existing.external_service_type) AS external_service_type
+  COALESCE(changed.external_service_type, existing.external_service_type) AS external_service_type,
+  COALESCE(changed.num_failures, existing.num_failures) AS num_failures,
+  COALESCE(changed.failure_message, existing.failure_message) AS failure_message,
+  COALESCE(changed.next_retry_at, existing.next_retry_at) AS next_retry_at
 FROM changed
 RIGHT JOIN batch ON batch.repo_id = changed.repo_id
 AND batch.external_id = changed.external_id
@@ -211,10 +248,13 @@ func (s *Store) createChangesetsQuery(cs []*a8n.Changeset) (*sqlf.Query, error)
 			c.UpdatedAt = c.CreatedAt
 		}
 	}
-	return batchChangesetsQuery(createChangesetsQueryFmtstr, cs)
+	return batchChangesetsQuery(createChangesetsQueryFmtstr, cs, nil)
 }
 
-func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, error) {
+// batchChangesetsQuery returns the given query of the batch of Changesets.
+// If previousUpdatedAt is non-nil, it holds the UpdatedAt of each Changeset
+// that it was read with.
+func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset, previousUpdatedAt []time.Time) (*sqlf.Query, error) {
 	type record struct {
 		ID                  int64           `json:"id"`
 		RepoID              int32           `json:"repo_id"`
@@ -224,11 +264,15 @@ func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, erro
 		CampaignIDs         json.RawMessage `json:"campaign_ids"`
 		ExternalID          string          `json:"external_id"`
 		ExternalServiceType string          `json:"external_service_type"`
+		NumFailures         int32           `json:"num_failures"`
+		FailureMessage      *string         `json:"failure_message"`
+		NextRetryAt         *time.Time      `json:"next_retry_at"`
+		PreviousUpdatedAt   *time.Time      `json:"previous_updated_at"`
 	}
 
 	records := make([]record, 0, len(cs))
 
-	for _, c := range cs {
+	for i, c := range cs {
 		metadata, err := metadataColumn(c.Metadata)
 		if err != nil {
 			return nil, err
@@ -239,6 +283,11 @@ func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, erro
 			return nil, err
 		}
 
+		var previous *time.Time
+		if previousUpdatedAt != nil {
+			previous = &previousUpdatedAt[i]
+		}
+
 		records = append(records, record{
 			ID:                  c.ID,
 			RepoID:              c.RepoID,
@@ -248,6 +297,10 @@ func batchChangesetsQuery(fmtstr string, cs []*a8n.Changeset) (*sqlf.Query, erro
 			CampaignIDs:         campaignIDs,
 			ExternalID:          c.ExternalID,
 			ExternalServiceType: c.ExternalServiceType,
+			NumFailures:         c.NumFailures,
+			FailureMessage:      nullStringColumn(c.FailureMessage),
+			NextRetryAt:         nullTimeColumn(c.NextRetryAt),
+			PreviousUpdatedAt:   previous,
 		})
 	}
 
@@ -333,7 +386,10 @@ SELECT
   metadata,
   campaign_ids,
   external_id,
-  external_service_type
+  external_service_type,
+  num_failures,
+  failure_message,
+  next_retry_at
 FROM changesets
 WHERE %s
 LIMIT 1
@@ -382,7 +438,7 @@ func (s *Store) ListChangesets(ctx context.Context, opts ListChangesetsOpts) (cs
 		return int64(c.ID), 1, err
 	})
 
-	if len(cs) == opts.Limit {
+	if opts.Limit != 0 && len(cs) == opts.Limit {
 		next = cs[len(cs)-1].ID
 		cs = cs[:len(cs)-1]
 	}
@@ -400,11 +456,13 @@ SELECT
   metadata,
   campaign_ids,
   external_id,
-  external_service_type
+  external_service_type,
+  num_failures,
+  failure_message,
+  next_retry_at
 FROM changesets
 WHERE %s
 ORDER BY id ASC
-LIMIT %s
 `
 
 const defaultListLimit = 50
@@ -415,6 +473,11 @@ func listChangesetsQuery(opts *ListChangesetsOpts) *sqlf.Query {
 	}
 	opts.Limit++
 
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
 	preds := []*sqlf.Query{
 		sqlf.Sprintf("id >= %s", opts.Cursor),
 	}
@@ -434,25 +497,57 @@ func listChangesetsQuery(opts *ListChangesetsOpts) *sqlf.Query {
 	}
 
 	return sqlf.Sprintf(
-		listChangesetsQueryFmtstr,
+		listChangesetsQueryFmtstr+limitClause,
 		sqlf.Join(preds, "\n AND "),
-		opts.Limit,
 	)
 }
 
-// UpdateChangesets updates the given Changesets.
+// UpdateChangesets updates the given Changesets. Changesets that were
+// updated since they were read are not updated, and a ConflictError with
+// their IDs is returned.
 func (s *Store) UpdateChangesets(ctx context.Context, cs ...*a8n.Changeset) error {
-	q, err := s.updateChangesetsQuery(cs)
+	previousUpdatedAt := make([]time.Time, len(cs))
+	for i, c := range cs {
+		previousUpdatedAt[i] = c.UpdatedAt
+	}
+
+	q, err := s.updateChangesetsQuery(cs, previousUpdatedAt)
 	if err != nil {
 		return err
 	}
 
-	i := -1
-	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
-		i++
-		err = scanChangeset(cs[i], sc)
-		return int64(cs[i].ID), 1, err
+	pending := make(map[int64]*a8n.Changeset, len(cs))
+	for _, c := range cs {
+		pending[c.ID] = c
+	}
+
+	err = s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+		var c a8n.Changeset
+		if err = scanChangeset(&c, sc); err != nil {
+			return 0, 0, err
+		}
+		if p, ok := pending[c.ID]; ok {
+			*p = c
+			delete(pending, c.ID)
+		}
+		return c.ID, 1, nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	conflict := ConflictError{ChangesetIDs: make([]int64, 0, len(pending))}
+	for i, c := range cs {
+		if _, ok := pending[c.ID]; ok {
+			c.UpdatedAt = previousUpdatedAt[i]
+			conflict.ChangesetIDs = append(conflict.ChangesetIDs, c.ID)
+		}
+	}
+	return conflict
 }
 
 const updateChangesetsQueryFmtstr = changesetBatchQueryPrefix + `,
@@ -466,9 +561,13 @@ changed AS (
     metadata              = batch.metadata,
     campaign_ids          = batch.campaign_ids,
     external_id           = batch.external_id,
-    external_service_type = batch.external_service_type
+    external_service_type = batch.external_service_type,
+    num_failures          = batch.num_failures,
+    failure_message       = batch.failure_message,
+    next_retry_at         = batch.next_retry_at
   FROM batch
   WHERE changesets.id = batch.id
+  AND changesets.updated_at = batch.previous_updated_at
   RETURNING changesets.*
 )
 ` + batchChangesetsQuerySuffix
@@ -482,19 +581,22 @@ SELECT
   changed.metadata,
   changed.campaign_ids,
   changed.external_id,
-  changed.external_service_type
+  changed.external_service_type,
+  changed.num_failures,
+  changed.failure_message,
+  changed.next_retry_at
 FROM changed
 LEFT JOIN batch ON batch.repo_id = changed.repo_id
 AND batch.external_id = changed.external_id
 ORDER BY batch.ordinality
 `
 
-func (s *Store) updateChangesetsQuery(cs []*a8n.Changeset) (*sqlf.Query, error) {
+func (s *Store) updateChangesetsQuery(cs []*a8n.Changeset, previousUpdatedAt []time.Time) (*sqlf.Query, error) {
 	now := s.now()
 	for _, c := range cs {
 		c.UpdatedAt = now
 	}
-	return batchChangesetsQuery(updateChangesetsQueryFmtstr, cs)
+	return batchChangesetsQuery(updateChangesetsQueryFmtstr, cs, previousUpdatedAt)
 }
 
 // GetChangesetEventOpts captures the query options needed for getting a ChangesetEvent
@@ -826,9 +928,13 @@ INSERT INTO campaigns (
   namespace_org_id,
   created_at,
   updated_at,
-  changeset_ids
+  changeset_ids,
+  labels,
+  webhook_url,
+  webhook_secret,
+  closed_at
 )
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
   id,
   name,
@@ -838,7 +944,11 @@ RETURNING
   namespace_org_id,
   created_at,
   updated_at,
-  changeset_ids
+  changeset_ids,
+  labels,
+  webhook_url,
+  webhook_secret,
+  closed_at
 `
 
 func (s *Store) createCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
@@ -865,6 +975,10 @@ func (s *Store) createCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
 		c.CreatedAt,
 		c.UpdatedAt,
 		changesetIDs,
+		stringArrayColumn(c.Labels),
+		c.WebhookURL,
+		c.WebhookSecret,
+		nullTimeColumn(c.ClosedAt),
 	), nil
 }
 
@@ -875,17 +989,51 @@ func nullInt32Column(n int32) *int32 {
 	return &n
 }
 
-// UpdateCampaign updates the given Campaign.
+func nullStringColumn(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringArrayColumn(ss []string) interface{} {
+	if ss == nil {
+		ss = []string{}
+	}
+	return pq.Array(ss)
+}
+
+func nullTimeColumn(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// UpdateCampaign updates the given Campaign. If it was updated since it was
+// read, it's not updated and a ConflictError is returned.
 func (s *Store) UpdateCampaign(ctx context.Context, c *a8n.Campaign) error {
-	q, err := s.updateCampaignQuery(c)
+	previousUpdatedAt := c.UpdatedAt
+
+	q, err := s.updateCampaignQuery(c, previousUpdatedAt)
 	if err != nil {
 		return err
 	}
 
-	return s.exec(ctx, q, func(sc scanner) (last, count int64, err error) {
+	_, count, err := s.query(ctx, q, func(sc scanner) (last, count int64, err error) {
 		err = scanCampaign(c, sc)
 		return int64(c.ID), 1, err
 	})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		c.UpdatedAt = previousUpdatedAt
+		return ConflictError{CampaignID: c.ID}
+	}
+
+	return nil
 }
 
 var updateCampaignQueryFmtstr = `
@@ -898,9 +1046,14 @@ SET (
   namespace_user_id,
   namespace_org_id,
   updated_at,
-  changeset_ids
-) = (%s, %s, %s, %s, %s, %s, %s)
+  changeset_ids,
+  labels,
+  webhook_url,
+  webhook_secret,
+  closed_at
+) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
+AND updated_at = %s
 RETURNING
   id,
   name,
@@ -910,10 +1063,14 @@ RETURNING
   namespace_org_id,
   created_at,
   updated_at,
-  changeset_ids
+  changeset_ids,
+  labels,
+  webhook_url,
+  webhook_secret,
+  closed_at
 `
 
-func (s *Store) updateCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
+func (s *Store) updateCampaignQuery(c *a8n.Campaign, previousUpdatedAt time.Time) (*sqlf.Query, error) {
 	changesetIDs, err := jsonSetColumn(c.ChangesetIDs)
 	if err != nil {
 		return nil, err
@@ -930,7 +1087,12 @@ func (s *Store) updateCampaignQuery(c *a8n.Campaign) (*sqlf.Query, error) {
 		nullInt32Column(c.NamespaceOrgID),
 		c.UpdatedAt,
 		changesetIDs,
+		stringArrayColumn(c.Labels),
+		c.WebhookURL,
+		c.WebhookSecret,
+		nullTimeColumn(c.ClosedAt),
 		c.ID,
+		previousUpdatedAt,
 	), nil
 }
 
@@ -954,6 +1116,9 @@ DELETE FROM campaigns WHERE id = %s
 // counting campaigns.
 type CountCampaignsOpts struct {
 	ChangesetID int64
+	// Labels only counts the campaigns that have all of the given labels.
+	Labels []string
+	CampaignUserOpts
 }
 
 // CountCampaigns returns the number of campaigns in the database.
@@ -978,6 +1143,12 @@ func countCampaignsQuery(opts *CountCampaignsOpts) *sqlf.Query {
 		preds = append(preds, sqlf.Sprintf("changeset_ids ? %s", opts.ChangesetID))
 	}
 
+	if len(opts.Labels) > 0 {
+		preds = append(preds, sqlf.Sprintf("labels @> %s", pq.Array(opts.Labels)))
+	}
+
+	preds = append(preds, opts.CampaignUserOpts.preds()...)
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -1020,7 +1191,11 @@ SELECT
   namespace_org_id,
   created_at,
   updated_at,
-  changeset_ids
+  changeset_ids,
+  labels,
+  webhook_url,
+  webhook_secret,
+  closed_at
 FROM campaigns
 WHERE %s
 LIMIT 1
@@ -1042,9 +1217,13 @@ func getCampaignQuery(opts *GetCampaignOpts) *sqlf.Query {
 // ListCampaignsOpts captures the query options needed for
 // listing campaigns.
 type ListCampaignsOpts struct {
+	IDs         []int64
 	ChangesetID int64
-	Cursor      int64
-	Limit       int
+	// Labels only lists the campaigns that have all of the given labels.
+	Labels []string
+	CampaignUserOpts
+	Cursor int64
+	Limit  int
 }
 
 // ListCampaigns lists Campaigns with the given filters.
@@ -1061,7 +1240,7 @@ func (s *Store) ListCampaigns(ctx context.Context, opts ListCampaignsOpts) (cs [
 		return int64(c.ID), 1, err
 	})
 
-	if len(cs) == opts.Limit {
+	if opts.Limit != 0 && len(cs) == opts.Limit {
 		next = cs[len(cs)-1].ID
 		cs = cs[:len(cs)-1]
 	}
@@ -1080,11 +1259,14 @@ SELECT
   namespace_org_id,
   created_at,
   updated_at,
-  changeset_ids
+  changeset_ids,
+  labels,
+  webhook_url,
+  webhook_secret,
+  closed_at
 FROM campaigns
 WHERE %s
 ORDER BY id ASC
-LIMIT %s
 `
 
 func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
@@ -1093,21 +1275,193 @@ func listCampaignsQuery(opts *ListCampaignsOpts) *sqlf.Query {
 	}
 	opts.Limit++
 
+	var limitClause string
+	if opts.Limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %d", opts.Limit)
+	}
+
 	preds := []*sqlf.Query{
 		sqlf.Sprintf("id >= %s", opts.Cursor),
 	}
 
+	if len(opts.IDs) > 0 {
+		ids := make([]*sqlf.Query, 0, len(opts.IDs))
+		for _, id := range opts.IDs {
+			if id != 0 {
+				ids = append(ids, sqlf.Sprintf("%d", id))
+			}
+		}
+		preds = append(preds, sqlf.Sprintf("id IN (%s)", sqlf.Join(ids, ",")))
+	}
+
 	if opts.ChangesetID != 0 {
 		preds = append(preds, sqlf.Sprintf("changeset_ids ? %s", opts.ChangesetID))
 	}
 
+	if len(opts.Labels) > 0 {
+		preds = append(preds, sqlf.Sprintf("labels @> %s", pq.Array(opts.Labels)))
+	}
+
+	preds = append(preds, opts.CampaignUserOpts.preds()...)
+
 	return sqlf.Sprintf(
-		listCampaignsQueryFmtstr,
+		listCampaignsQueryFmtstr+limitClause,
 		sqlf.Join(preds, "\n AND "),
-		opts.Limit,
 	)
 }
 
+// CodeHostUser is a user of the code hosts of a type (e.g. "github"),
+// identified by their login.
+type CodeHostUser struct {
+	ServiceType string
+	Login       string
+}
+
+// CampaignUserOpts captures the query options needed for filtering
+// campaigns by the users involved in them.
+type CampaignUserOpts struct {
+	// AuthorID only matches the campaigns authored by the user with the
+	// given ID.
+	AuthorID int32
+	// Reviewers, if non-nil, only matches the campaigns with a changeset
+	// whose review was requested from or submitted by one of the users.
+	Reviewers []CodeHostUser
+	// Participants, if non-nil, only matches the campaigns with a changeset
+	// that one of the users authored, reviewed or commented on.
+	Participants []CodeHostUser
+}
+
+func (opts *CampaignUserOpts) preds() (preds []*sqlf.Query) {
+	if opts.AuthorID != 0 {
+		preds = append(preds, sqlf.Sprintf("author_id = %s", opts.AuthorID))
+	}
+
+	if opts.Reviewers != nil {
+		preds = append(preds, sqlf.Sprintf(campaignChangesetsPredFmtstr, changesetReviewersPred(opts.Reviewers)))
+	}
+
+	if opts.Participants != nil {
+		preds = append(preds, sqlf.Sprintf(campaignChangesetsPredFmtstr, changesetParticipantsPred(opts.Participants)))
+	}
+
+	return preds
+}
+
+var campaignChangesetsPredFmtstr = `
+EXISTS (
+  SELECT 1 FROM changesets
+  WHERE changesets.campaign_ids ? campaigns.id::text
+  AND %s
+)
+`
+
+// changesetReviewersPred returns the predicate matching the changesets whose
+// review was requested from or submitted by one of the users.
+func changesetReviewersPred(users []CodeHostUser) *sqlf.Query {
+	githubLogins, bitbucketServerLogins := codeHostLogins(users)
+
+	var preds []*sqlf.Query
+	if len(githubLogins) > 0 {
+		preds = append(preds, sqlf.Sprintf(githubReviewersPredFmtstr, pq.Array(githubLogins), pq.Array(githubLogins)))
+	}
+
+	if len(bitbucketServerLogins) > 0 {
+		preds = append(preds, sqlf.Sprintf(bitbucketServerReviewersPredFmtstr, pq.Array(bitbucketServerLogins)))
+	}
+
+	if len(preds) == 0 {
+		return sqlf.Sprintf("FALSE")
+	}
+
+	return sqlf.Sprintf("(%s)", sqlf.Join(preds, "\n OR "))
+}
+
+// changesetParticipantsPred returns the predicate matching the changesets
+// that one of the users authored, reviewed or commented on.
+func changesetParticipantsPred(users []CodeHostUser) *sqlf.Query {
+	githubLogins, bitbucketServerLogins := codeHostLogins(users)
+
+	preds := []*sqlf.Query{changesetReviewersPred(users)}
+	if len(githubLogins) > 0 {
+		preds = append(preds, sqlf.Sprintf(githubParticipantsPredFmtstr, pq.Array(githubLogins), pq.Array(githubLogins)))
+	}
+
+	if len(bitbucketServerLogins) > 0 {
+		preds = append(preds, sqlf.Sprintf(bitbucketServerParticipantsPredFmtstr, pq.Array(bitbucketServerLogins), pq.Array(bitbucketServerLogins)))
+	}
+
+	return sqlf.Sprintf("(%s)", sqlf.Join(preds, "\n OR "))
+}
+
+func codeHostLogins(users []CodeHostUser) (githubLogins, bitbucketServerLogins []string) {
+	for _, u := range users {
+		switch u.ServiceType {
+		case github.ServiceType:
+			githubLogins = append(githubLogins, u.Login)
+		case bitbucketserver.ServiceType:
+			bitbucketServerLogins = append(bitbucketServerLogins, u.Login)
+		}
+	}
+	return githubLogins, bitbucketServerLogins
+}
+
+// metadataArrayElements returns the SQL expression for the elements of the
+// JSON array in the given field of a changeset's metadata. A field that is
+// not an array (e.g. null) has no elements.
+func metadataArrayElements(field string) string {
+	return fmt.Sprintf(
+		"jsonb_array_elements(CASE jsonb_typeof(changesets.metadata->'%[1]s') WHEN 'array' THEN changesets.metadata->'%[1]s' ELSE '[]' END)",
+		field,
+	)
+}
+
+var githubReviewersPredFmtstr = `
+(
+  changesets.external_service_type = 'github'
+  AND EXISTS (
+    SELECT 1 FROM ` + metadataArrayElements("TimelineItems") + ` AS item
+    WHERE (item->>'Type' = 'ReviewRequestedEvent' AND item->'Item'->'RequestedReviewer'->>'Login' = ANY(%s))
+    OR (item->>'Type' = 'PullRequestReview' AND item->'Item'->'Author'->>'Login' = ANY(%s))
+  )
+)
+`
+
+var githubParticipantsPredFmtstr = `
+(
+  changesets.external_service_type = 'github'
+  AND (
+    changesets.metadata->'Author'->>'Login' = ANY(%s)
+    OR EXISTS (
+      SELECT 1 FROM ` + metadataArrayElements("Participants") + ` AS participant
+      WHERE participant->>'Login' = ANY(%s)
+    )
+  )
+)
+`
+
+var bitbucketServerReviewersPredFmtstr = `
+(
+  changesets.external_service_type = 'bitbucketServer'
+  AND EXISTS (
+    SELECT 1 FROM ` + metadataArrayElements("reviewers") + ` AS reviewer
+    WHERE reviewer->'user'->>'name' = ANY(%s)
+  )
+)
+`
+
+var bitbucketServerParticipantsPredFmtstr = `
+(
+  changesets.external_service_type = 'bitbucketServer'
+  AND (
+    changesets.metadata->'author'->'user'->>'name' = ANY(%s)
+    OR EXISTS (
+      SELECT 1 FROM ` + metadataArrayElements("participants") + ` AS participant
+      WHERE participant->'user'->>'name' = ANY(%s)
+    )
+  )
+)
+`
+
 func (s *Store) exec(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
 	_, _, err := s.query(ctx, q, sc)
 	return err
@@ -1163,6 +1517,9 @@ func scanChangeset(t *a8n.Changeset, s scanner) error {
 		&dbutil.JSONInt64Set{Set: &t.CampaignIDs},
 		&t.ExternalID,
 		&t.ExternalServiceType,
+		&t.NumFailures,
+		&dbutil.NullString{S: &t.FailureMessage},
+		&dbutil.NullTime{Time: &t.NextRetryAt},
 	)
 	if err != nil {
 		return err
@@ -1225,6 +1582,17 @@ func scanChangesetEvent(e *a8n.ChangesetEvent, s scanner) error {
 		e.Metadata = new(github.ReviewRequestedEvent)
 	case a8n.ChangesetEventKindGitHubUnassigned:
 		e.Metadata = new(github.UnassignedEvent)
+	case a8n.ChangesetEventKindBitbucketServerApproved,
+		a8n.ChangesetEventKindBitbucketServerCommented,
+		a8n.ChangesetEventKindBitbucketServerDeclined,
+		a8n.ChangesetEventKindBitbucketServerMerged,
+		a8n.ChangesetEventKindBitbucketServerOpened,
+		a8n.ChangesetEventKindBitbucketServerReopened,
+		a8n.ChangesetEventKindBitbucketServerRescoped,
+		a8n.ChangesetEventKindBitbucketServerReviewed,
+		a8n.ChangesetEventKindBitbucketServerUnapproved,
+		a8n.ChangesetEventKindBitbucketServerUpdated:
+		e.Metadata = new(bitbucketserver.Activity)
 	default:
 		panic(errors.Errorf("unknown changeset event kind for %T", e))
 	}
@@ -1237,7 +1605,9 @@ func scanChangesetEvent(e *a8n.ChangesetEvent, s scanner) error {
 }
 
 func scanCampaign(c *a8n.Campaign, s scanner) error {
-	return s.Scan(
+	var labels pq.StringArray
+
+	err := s.Scan(
 		&c.ID,
 		&c.Name,
 		&c.Description,
@@ -1247,7 +1617,22 @@ func scanCampaign(c *a8n.Campaign, s scanner) error {
 		&c.CreatedAt,
 		&c.UpdatedAt,
 		&dbutil.JSONInt64Set{Set: &c.ChangesetIDs},
+		&labels,
+		&c.WebhookURL,
+		&c.WebhookSecret,
+		&dbutil.NullTime{Time: &c.ClosedAt},
 	)
+	if err != nil {
+		return err
+	}
+
+	// Keep an empty list nil, like a Campaign that was never stored.
+	c.Labels = nil
+	if len(labels) > 0 {
+		c.Labels = labels
+	}
+
+	return nil
 }
 
 func metadataColumn(metadata interface{}) (msg json.RawMessage, err error) {
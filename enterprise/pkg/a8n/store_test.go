@@ -3,6 +3,7 @@ package a8n
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"testing"
@@ -42,6 +43,9 @@ func testStore(db *sql.DB) func(*testing.T) {
 
 					if i%2 == 0 {
 						c.NamespaceOrgID = 23
+						c.Labels = []string{"eslint", fmt.Sprintf("wave-%d", i)}
+						c.WebhookURL = "https://pm.example.com/hooks/sourcegraph"
+						c.WebhookSecret = "s3cr3t"
 					} else {
 						c.NamespaceUserID = 42
 					}
@@ -88,6 +92,15 @@ func testStore(db *sql.DB) func(*testing.T) {
 				if have, want := count, int64(1); have != want {
 					t.Fatalf("have count: %d, want: %d", have, want)
 				}
+
+				count, err = s.CountCampaigns(ctx, CountCampaignsOpts{Labels: []string{"eslint"}})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if have, want := count, int64(2); have != want {
+					t.Fatalf("have count: %d, want: %d", have, want)
+				}
 			})
 
 			t.Run("List", func(t *testing.T) {
@@ -142,6 +155,47 @@ func testStore(db *sql.DB) func(*testing.T) {
 					}
 				}
 
+				{
+					opts := ListCampaignsOpts{Labels: []string{"eslint", "wave-2"}}
+					have, _, err := s.ListCampaigns(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					want := campaigns[2:3]
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					}
+				}
+
+				{
+					opts := ListCampaignsOpts{IDs: []int64{campaigns[0].ID, campaigns[2].ID}}
+					have, _, err := s.ListCampaigns(ctx, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					want := []*a8n.Campaign{campaigns[0], campaigns[2]}
+					if diff := cmp.Diff(have, want); diff != "" {
+						t.Fatalf("opts: %+v, diff: %s", opts, diff)
+					}
+				}
+
+				{
+					have, next, err := s.ListCampaigns(ctx, ListCampaignsOpts{Limit: -1})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if next != 0 {
+						t.Fatalf("have next %v, want 0", next)
+					}
+
+					if diff := cmp.Diff(have, campaigns); diff != "" {
+						t.Fatal(diff)
+					}
+				}
+
 				{
 					var cursor int64
 					for i := 1; i <= len(campaigns); i++ {
@@ -176,10 +230,11 @@ func testStore(db *sql.DB) func(*testing.T) {
 					}
 
 					now = now.Add(time.Second)
+					have := c.Clone()
+
 					want := c
 					want.UpdatedAt = now
 
-					have := c.Clone()
 					if err := s.UpdateCampaign(ctx, have); err != nil {
 						t.Fatal(err)
 					}
@@ -228,6 +283,34 @@ func testStore(db *sql.DB) func(*testing.T) {
 				}
 			})
 
+			t.Run("Update conflict", func(t *testing.T) {
+				want := campaigns[0]
+
+				// A campaign that was read before its last update.
+				have := want.Clone()
+				have.Name += "-stale"
+				have.UpdatedAt = have.UpdatedAt.Add(-time.Second)
+				stale := have.UpdatedAt
+
+				err := s.UpdateCampaign(ctx, have)
+				if diff := cmp.Diff(err, ConflictError{CampaignID: want.ID}); diff != "" {
+					t.Fatal(diff)
+				}
+
+				if !have.UpdatedAt.Equal(stale) {
+					t.Fatalf("UpdatedAt changed: have %s, want %s", have.UpdatedAt, stale)
+				}
+
+				stored, err := s.GetCampaign(ctx, GetCampaignOpts{ID: want.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(stored, want); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+
 			t.Run("Get", func(t *testing.T) {
 				t.Run("ByID", func(t *testing.T) {
 					want := campaigns[0]
@@ -611,6 +694,34 @@ func testStore(db *sql.DB) func(*testing.T) {
 					t.Fatal(diff)
 				}
 			})
+
+			t.Run("Update conflict", func(t *testing.T) {
+				fresh := changesets[0].Clone()
+
+				// A changeset that was read before its last update.
+				stale := changesets[1].Clone()
+				stale.ExternalID += "-stale"
+				stale.UpdatedAt = stale.UpdatedAt.Add(-time.Second)
+
+				err := s.UpdateChangesets(ctx, fresh, stale)
+				if diff := cmp.Diff(err, ConflictError{ChangesetIDs: []int64{stale.ID}}); diff != "" {
+					t.Fatal(diff)
+				}
+
+				// Changesets without a conflict are still updated.
+				if diff := cmp.Diff(fresh, changesets[0]); diff != "" {
+					t.Fatal(diff)
+				}
+
+				have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: stale.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(have, changesets[1]); diff != "" {
+					t.Fatal(diff)
+				}
+			})
 		})
 
 		t.Run("ChangesetEvents", func(t *testing.T) {
@@ -849,5 +960,166 @@ func testStore(db *sql.DB) func(*testing.T) {
 				})
 			})
 		})
+
+		t.Run("CampaignsByUser", func(t *testing.T) {
+			campaigns := make([]*a8n.Campaign, 0, 3)
+			for i, authorID := range []int32{4242, 4343, 4343} {
+				c := &a8n.Campaign{
+					Name:            fmt.Sprintf("Campaign by user %d", i),
+					AuthorID:        authorID,
+					NamespaceUserID: 42,
+				}
+				if err := s.CreateCampaign(ctx, c); err != nil {
+					t.Fatal(err)
+				}
+				campaigns = append(campaigns, c)
+			}
+
+			githubActor := func(login string) github.Actor {
+				return github.Actor{Login: login}
+			}
+
+			var bbsPR bitbucketserver.PullRequest
+			err := json.Unmarshal([]byte(`{
+				"id": 1,
+				"author": {"user": {"name": "alice"}},
+				"reviewers": [{"user": {"name": "bob"}}]
+			}`), &bbsPR)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			changesets := []*a8n.Changeset{
+				{
+					RepoID:      42,
+					CreatedAt:   now,
+					UpdatedAt:   now,
+					CampaignIDs: []int64{campaigns[0].ID},
+					Metadata: &github.PullRequest{
+						Author: githubActor("alice-gh"),
+						TimelineItems: []github.TimelineItem{{
+							Type: "ReviewRequestedEvent",
+							Item: &github.ReviewRequestedEvent{
+								Actor:             githubActor("alice-gh"),
+								RequestedReviewer: githubActor("bob-gh"),
+							},
+						}},
+					},
+					ExternalID:          "campaigns-by-user-0",
+					ExternalServiceType: github.ServiceType,
+				},
+				{
+					RepoID:      42,
+					CreatedAt:   now,
+					UpdatedAt:   now,
+					CampaignIDs: []int64{campaigns[1].ID},
+					Metadata: &github.PullRequest{
+						Author:       githubActor("dave-gh"),
+						Participants: []github.Actor{githubActor("carol-gh")},
+						TimelineItems: []github.TimelineItem{{
+							Type: "PullRequestReview",
+							Item: &github.PullRequestReview{Author: githubActor("bob-gh")},
+						}},
+					},
+					ExternalID:          "campaigns-by-user-1",
+					ExternalServiceType: github.ServiceType,
+				},
+				{
+					RepoID:              42,
+					CreatedAt:           now,
+					UpdatedAt:           now,
+					CampaignIDs:         []int64{campaigns[2].ID},
+					Metadata:            &bbsPR,
+					ExternalID:          "campaigns-by-user-2",
+					ExternalServiceType: bitbucketserver.ServiceType,
+				},
+			}
+			if err := s.CreateChangesets(ctx, changesets...); err != nil {
+				t.Fatal(err)
+			}
+
+			gh := func(login string) CodeHostUser {
+				return CodeHostUser{ServiceType: github.ServiceType, Login: login}
+			}
+			bbs := func(login string) CodeHostUser {
+				return CodeHostUser{ServiceType: bitbucketserver.ServiceType, Login: login}
+			}
+
+			for _, tc := range []struct {
+				name string
+				opts CampaignUserOpts
+				want []*a8n.Campaign
+			}{
+				{
+					name: "author",
+					opts: CampaignUserOpts{AuthorID: 4343},
+					want: campaigns[1:],
+				},
+				{
+					name: "github reviewer",
+					opts: CampaignUserOpts{Reviewers: []CodeHostUser{gh("bob-gh")}},
+					want: campaigns[:2],
+				},
+				{
+					name: "bitbucket server reviewer",
+					opts: CampaignUserOpts{Reviewers: []CodeHostUser{bbs("bob")}},
+					want: campaigns[2:],
+				},
+				{
+					name: "author is not a reviewer",
+					opts: CampaignUserOpts{Reviewers: []CodeHostUser{gh("alice-gh"), bbs("alice")}},
+				},
+				{
+					name: "login of other code host",
+					opts: CampaignUserOpts{Reviewers: []CodeHostUser{bbs("bob-gh")}},
+				},
+				{
+					name: "no code host users",
+					opts: CampaignUserOpts{Reviewers: []CodeHostUser{}, Participants: []CodeHostUser{}},
+				},
+				{
+					name: "github participants",
+					opts: CampaignUserOpts{Participants: []CodeHostUser{gh("alice-gh"), gh("carol-gh")}},
+					want: campaigns[:2],
+				},
+				{
+					name: "reviewers participate",
+					opts: CampaignUserOpts{Participants: []CodeHostUser{bbs("bob")}},
+					want: campaigns[2:],
+				},
+				{
+					name: "author and participant",
+					opts: CampaignUserOpts{AuthorID: 4343, Participants: []CodeHostUser{bbs("alice")}},
+					want: campaigns[2:],
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					have, _, err := s.ListCampaigns(ctx, ListCampaignsOpts{CampaignUserOpts: tc.opts})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(campaignIDs(have), campaignIDs(tc.want)); diff != "" {
+						t.Fatal(diff)
+					}
+
+					count, err := s.CountCampaigns(ctx, CountCampaignsOpts{CampaignUserOpts: tc.opts})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if have, want := count, int64(len(tc.want)); have != want {
+						t.Fatalf("have count: %d, want: %d", have, want)
+					}
+				})
+			}
+		})
+	}
+}
+
+func campaignIDs(cs []*a8n.Campaign) (ids []int64) {
+	for _, c := range cs {
+		ids = append(ids, c.ID)
 	}
+	return ids
 }
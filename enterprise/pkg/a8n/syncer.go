@@ -2,10 +2,12 @@ package a8n
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -16,17 +18,32 @@ type ChangesetSyncer struct {
 	Store       *Store
 	ReposStore  repos.Store
 	HTTPFactory *httpcli.Factory
+	// Backoff determines when changesets that failed to sync are retried.
+	// DefaultBackoff is used if it's the zero value.
+	Backoff Backoff
+	// Notifier is notified about the state transitions of the synced
+	// changesets, if not nil.
+	Notifier *CampaignNotifier
 }
 
 // Sync refreshes the metadata of all changesets and updates them in the
-// database
+// database. Changesets that failed to sync before are only retried once
+// their backoff elapsed.
 func (s *ChangesetSyncer) Sync(ctx context.Context) error {
-	cs, err := s.listAllChangesets(ctx)
+	all, err := s.listAllChangesets(ctx)
 	if err != nil {
 		log15.Error("ChangesetSyncer.listAllChangesets", "error", err)
 		return err
 	}
 
+	now := s.Store.now()
+	cs := all[:0]
+	for _, c := range all {
+		if c.SyncDue(now) {
+			cs = append(cs, c)
+		}
+	}
+
 	if err := s.SyncChangesets(ctx, cs...); err != nil {
 		log15.Error("ChangesetSyncer", "error", err)
 		return err
@@ -35,12 +52,124 @@ func (s *ChangesetSyncer) Sync(ctx context.Context) error {
 }
 
 // SyncChangesets refreshes the metadata of the given changesets and
-// updates them in the database
+// updates them in the database. Changesets that can't be loaded from their
+// code host have the failure recorded and their next retry scheduled instead
+// of failing the whole sync.
 func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*a8n.Changeset) (err error) {
 	if len(cs) == 0 {
 		return nil
 	}
 
+	batches, err := s.batchChangesets(ctx, cs...)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.Backoff
+	if backoff == (Backoff{}) {
+		backoff = DefaultBackoff
+	}
+
+	before := snapshotChangesets(cs)
+
+	var events []*a8n.ChangesetEvent
+	for _, b := range batches {
+		errs, err := loadChangesets(ctx, b.ChangesetSource, b.Changesets)
+		if err != nil {
+			// The sync was canceled, which says nothing about the changesets.
+			return err
+		}
+		now := s.Store.now()
+
+		for _, c := range b.Changesets {
+			if err, ok := errs[c]; ok {
+				log15.Warn("ChangesetSyncer: loading changeset failed", "changeset_id", c.Changeset.ID, "error", err)
+				recordSyncFailure(c.Changeset, err, backoff, now)
+				continue
+			}
+
+			c.NumFailures = 0
+			c.FailureMessage = ""
+			c.NextRetryAt = time.Time{}
+			events = append(events, c.Events()...)
+		}
+	}
+
+	if err = s.updateChangesets(ctx, cs, events); err != nil {
+		return err
+	}
+
+	if s.Notifier != nil {
+		if err := s.Notifier.notifySynced(ctx, before, cs); err != nil {
+			log15.Error("ChangesetSyncer: notifying campaign webhooks failed", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// loadChangesets loads the given Changesets from src. If loading them in one
+// batch fails, they're loaded one at a time instead, so that the error of a
+// single Changeset, e.g. one that was deleted on its code host, isn't
+// recorded on the others. It returns the errors of the Changesets that failed
+// to load, or the error of ctx if it's done, in which case no Changeset
+// should be considered failed.
+func loadChangesets(ctx context.Context, src repos.ChangesetSource, cs []*repos.Changeset) (map[*repos.Changeset]error, error) {
+	err := src.LoadChangesets(ctx, cs...)
+	if err == nil {
+		return nil, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	errs := make(map[*repos.Changeset]error, len(cs))
+	for i, c := range cs {
+		// Loading the remaining Changesets one at a time would only send more
+		// requests to a code host that is rate limiting us.
+		if len(cs) == 1 || github.IsRateLimitExceeded(err) {
+			for _, c := range cs[i:] {
+				errs[c] = err
+			}
+			break
+		}
+
+		if err = src.LoadChangesets(ctx, c); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			errs[c] = err
+		}
+	}
+	return errs, nil
+}
+
+func (s *ChangesetSyncer) updateChangesets(ctx context.Context, cs []*a8n.Changeset, events []*a8n.ChangesetEvent) (err error) {
+	tx, err := s.Store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Done(&err)
+
+	if err = tx.UpdateChangesets(ctx, cs...); err != nil {
+		return err
+	}
+
+	return tx.UpsertChangesetEvents(ctx, events...)
+}
+
+// A changesetBatch is a batch of Changesets whose repos are synced from the
+// same external service, together with the source of that service.
+type changesetBatch struct {
+	repos.ChangesetSource
+	Changesets []*repos.Changeset
+}
+
+// batchChangesets groups the given Changesets by the external service their
+// repos are synced from. Changesets whose repo isn't in the database are left
+// out.
+func (s *ChangesetSyncer) batchChangesets(ctx context.Context, cs ...*a8n.Changeset) (map[int64]*changesetBatch, error) {
 	var repoIDs []uint32
 	repoSet := map[uint32]*repos.Repo{}
 
@@ -54,7 +183,7 @@ func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*a8n.Changes
 
 	rs, err := s.ReposStore.ListRepos(ctx, repos.StoreListReposArgs{IDs: repoIDs})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, r := range rs {
@@ -70,7 +199,7 @@ func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*a8n.Changes
 
 	es, err := s.ReposStore.ListExternalServices(ctx, repos.StoreListExternalServicesArgs{RepoIDs: repoIDs})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	byRepo := make(map[uint32]int64, len(rs))
@@ -84,24 +213,19 @@ func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*a8n.Changes
 		}
 	}
 
-	type batch struct {
-		repos.ChangesetSource
-		Changesets []*repos.Changeset
-	}
-
-	batches := make(map[int64]*batch, len(es))
+	batches := make(map[int64]*changesetBatch, len(es))
 	for _, e := range es {
 		src, err := repos.NewSource(e, s.HTTPFactory)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		css, ok := src.(repos.ChangesetSource)
 		if !ok {
-			return errors.Errorf("unsupported repo type %q", e.Kind)
+			return nil, errors.Errorf("unsupported repo type %q", e.Kind)
 		}
 
-		batches[e.ID] = &batch{ChangesetSource: css}
+		batches[e.ID] = &changesetBatch{ChangesetSource: css}
 	}
 
 	for _, c := range cs {
@@ -116,29 +240,7 @@ func (s *ChangesetSyncer) SyncChangesets(ctx context.Context, cs ...*a8n.Changes
 		})
 	}
 
-	var events []*a8n.ChangesetEvent
-	for _, b := range batches {
-		if err = b.LoadChangesets(ctx, b.Changesets...); err != nil {
-			return err
-		}
-
-		for _, c := range b.Changesets {
-			events = append(events, c.Events()...)
-		}
-	}
-
-	tx, err := s.Store.Transact(ctx)
-	if err != nil {
-		return err
-	}
-
-	defer tx.Done(&err)
-
-	if err = tx.UpdateChangesets(ctx, cs...); err != nil {
-		return err
-	}
-
-	return tx.UpsertChangesetEvents(ctx, events...)
+	return batches, nil
 }
 
 func (s *ChangesetSyncer) listAllChangesets(ctx context.Context) (all []*a8n.Changeset, err error) {
@@ -13,13 +13,16 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/a8n"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
 // GitHubWebhook receives GitHub organization webhook events that are
 // relevant to a8n, normalizes those events into ChangesetEvents
-// and upserts them to the database.
+// and upserts them to the database. It also receives the installation
+// events of GitHub Apps, which add or remove the external services of
+// their installations.
 type GitHubWebhook struct {
 	Store *Store
 	Repos repos.Store
@@ -34,6 +37,13 @@ func (h *GitHubWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if ie, ok := e.(*gh.InstallationEvent); ok {
+		if err := h.syncInstallation(r.Context(), ie); err != nil {
+			respond(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
 	pr, ev := h.convertEvent(e)
 	if pr == 0 || ev == nil {
 		respond(w, http.StatusOK, nil) // Nothing to do
@@ -66,9 +76,13 @@ func (h *GitHubWebhook) parseEvent(r *http.Request) (interface{}, *httpError) {
 	var secrets [][]byte
 	for _, e := range es {
 		c, _ := e.Configuration()
-		for _, hook := range c.(*schema.GitHubConnection).Webhooks {
+		gc := c.(*schema.GitHubConnection)
+		for _, hook := range gc.Webhooks {
 			secrets = append(secrets, []byte(hook.Secret))
 		}
+		if i := gc.GithubAppInstallation; i != nil && i.WebhookSecret != "" {
+			secrets = append(secrets, []byte(i.WebhookSecret))
+		}
 	}
 
 	sig := r.Header.Get("X-Hub-Signature")
@@ -133,6 +147,80 @@ func (h *GitHubWebhook) convertEvent(theirs interface{}) (pr int64, ours interfa
 	return
 }
 
+// syncInstallation adds an external service for a new installation of a
+// GitHub App that is already configured in another external service, with
+// that service's config and the new installation ID. It deletes the external
+// services of removed installations.
+func (h *GitHubWebhook) syncInstallation(ctx context.Context, e *gh.InstallationEvent) error {
+	appID, installationID := e.GetInstallation().GetAppID(), e.GetInstallation().GetID()
+
+	args := repos.StoreListExternalServicesArgs{Kinds: []string{"GITHUB"}}
+	es, err := h.Repos.ListExternalServices(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	var (
+		template  *repos.ExternalService
+		installed []*repos.ExternalService
+	)
+
+	for _, svc := range es {
+		c, err := svc.Configuration()
+		if err != nil {
+			continue
+		}
+
+		i := c.(*schema.GitHubConnection).GithubAppInstallation
+		if i == nil || int64(i.AppID) != appID {
+			continue
+		}
+
+		if int64(i.InstallationID) == installationID {
+			installed = append(installed, svc)
+		} else if template == nil {
+			template = svc
+		}
+	}
+
+	now := h.Now()
+
+	switch e.GetAction() {
+	case "created":
+		// Nothing to do if the installation is already configured, or if
+		// the App isn't configured in any external service.
+		if len(installed) > 0 || template == nil {
+			return nil
+		}
+
+		config, err := jsonc.Edit(template.Config, installationID, "githubAppInstallation", "installationID")
+		if err != nil {
+			return err
+		}
+
+		svc := &repos.ExternalService{
+			Kind:        template.Kind,
+			DisplayName: fmt.Sprintf("GitHub App installation %d (%s)", installationID, e.GetInstallation().GetAccount().GetLogin()),
+			Config:      config,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		log15.Info("GitHubWebhook: adding external service for GitHub App installation", "app_id", appID, "installation_id", installationID)
+		return h.Repos.UpsertExternalServices(ctx, svc)
+
+	case "deleted":
+		for _, svc := range installed {
+			svc.UpdatedAt, svc.DeletedAt = now, now
+		}
+
+		log15.Info("GitHubWebhook: deleting external services of GitHub App installation", "app_id", appID, "installation_id", installationID, "count", len(installed))
+		return h.Repos.UpsertExternalServices(ctx, installed...)
+	}
+
+	return nil
+}
+
 func (h *GitHubWebhook) upsertChangesetEvent(
 	ctx context.Context,
 	pr int64,
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 )
@@ -19,12 +20,30 @@ type Campaign struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	ChangesetIDs    []int64
+
+	// Labels of the campaign itself, used to group and filter campaigns.
+	Labels []string
+
+	// WebhookURL is where notifications about state transitions of the
+	// campaign are sent to, if not empty. Their payloads are signed with
+	// WebhookSecret.
+	WebhookURL    string
+	WebhookSecret string
+
+	// ClosedAt is when the campaign was closed, or zero if it's open. See
+	// Closed.
+	ClosedAt time.Time
 }
 
+// Closed returns whether the campaign was closed. Changesets can't be added
+// to closed campaigns.
+func (c *Campaign) Closed() bool { return !c.ClosedAt.IsZero() }
+
 // Clone returns a clone of a Campaign.
 func (c *Campaign) Clone() *Campaign {
 	cc := *c
 	cc.ChangesetIDs = c.ChangesetIDs[:len(c.ChangesetIDs):len(c.ChangesetIDs)]
+	cc.Labels = c.Labels[:len(c.Labels):len(c.Labels)]
 	return &cc
 }
 
@@ -85,6 +104,16 @@ type Changeset struct {
 	CampaignIDs         []int64
 	ExternalID          string
 	ExternalServiceType string
+
+	// NumFailures is the number of consecutive times syncing this Changeset
+	// with its code host failed.
+	NumFailures int32
+	// FailureMessage is the error message of the last failed sync.
+	FailureMessage string
+	// NextRetryAt is when the next sync of a failed Changeset is due. It's
+	// zero when the last failure was deemed permanent and is only retried
+	// manually.
+	NextRetryAt time.Time
 }
 
 // Clone returns a clone of a Changeset.
@@ -94,6 +123,21 @@ func (t *Changeset) Clone() *Changeset {
 	return &tt
 }
 
+// Failed returns true if the last sync of the Changeset failed.
+func (t *Changeset) Failed() bool {
+	return t.NumFailures > 0
+}
+
+// SyncDue returns true if the Changeset should be synced at the given time,
+// which is always the case unless its last sync failed and its next retry
+// isn't due yet.
+func (t *Changeset) SyncDue(now time.Time) bool {
+	if !t.Failed() {
+		return true
+	}
+	return !t.NextRetryAt.IsZero() && !t.NextRetryAt.After(now)
+}
+
 // Title of the Changeset.
 func (t *Changeset) Title() (string, error) {
 	switch m := t.Metadata.(type) {
@@ -138,7 +182,13 @@ func (t *Changeset) State() (s ChangesetState, err error) {
 	case *github.PullRequest:
 		s = ChangesetState(m.State)
 	case *bitbucketserver.PullRequest:
-		s = ChangesetState(m.State)
+		// Bitbucket Server pull requests that were closed without merging
+		// are declined.
+		if m.State == "DECLINED" {
+			s = ChangesetStateClosed
+		} else {
+			s = ChangesetState(m.State)
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -166,6 +216,20 @@ func (t *Changeset) URL() (s string, err error) {
 	}
 }
 
+// Commits returns the commits at the tips of the base and head branches of a
+// Changeset as of its last sync. They are empty if they aren't known, e.g.
+// because the Changeset wasn't synced since they were first recorded.
+func (t *Changeset) Commits() (base, head api.CommitID, err error) {
+	switch m := t.Metadata.(type) {
+	case *github.PullRequest:
+		return api.CommitID(m.BaseRefOid), api.CommitID(m.HeadRefOid), nil
+	case *bitbucketserver.PullRequest:
+		return api.CommitID(m.ToRef.LatestCommit), api.CommitID(m.FromRef.LatestCommit), nil
+	default:
+		return "", "", errors.New("unknown changeset type")
+	}
+}
+
 // ReviewState of a Changeset.
 func (t *Changeset) ReviewState() (s ChangesetReviewState, err error) {
 	states := map[ChangesetReviewState]bool{}
@@ -219,6 +283,21 @@ func (t *Changeset) Events() (events []*ChangesetEvent) {
 				events = append(events, &ev)
 			}
 		}
+	case *bitbucketserver.PullRequest:
+		events = make([]*ChangesetEvent, 0, len(m.Activities))
+		for _, a := range m.Activities {
+			// Activities with actions we don't know of are skipped rather
+			// than stored with an invalid kind.
+			if _, ok := bitbucketServerActivityKinds[a.Action]; !ok {
+				continue
+			}
+			events = append(events, &ChangesetEvent{
+				ChangesetID: t.ID,
+				Key:         a.Key(),
+				Kind:        ChangesetEventKindFor(a),
+				Metadata:    a,
+			})
+		}
 	}
 	return events
 }
@@ -324,6 +403,8 @@ func (e *ChangesetEvent) Actor() string {
 		a = e.Actor.Login
 	case *github.UnassignedEvent:
 		a = e.Actor.Login
+	case *bitbucketserver.Activity:
+		a = e.User.Name
 	}
 
 	return a
@@ -385,6 +466,11 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		t = e.CreatedAt
 	case *github.UnassignedEvent:
 		t = e.CreatedAt
+	case *bitbucketserver.Activity:
+		t = unixMilliToTime(int64(e.CreatedDate))
+		if e.Comment != nil && e.Comment.UpdatedDate > e.CreatedDate {
+			t = unixMilliToTime(int64(e.Comment.UpdatedDate))
+		}
 	}
 
 	return t
@@ -629,6 +715,16 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) {
 		if e.CreatedAt.IsZero() {
 			e.CreatedAt = o.CreatedAt
 		}
+
+	case *bitbucketserver.Activity:
+		o := o.Metadata.(*bitbucketserver.Activity)
+
+		// Activities don't change, except for the comments of COMMENTED
+		// activities, which can be edited.
+		if o.Comment != nil && (e.Comment == nil || e.Comment.Version < o.Comment.Version) {
+			e.Comment = o.Comment
+		}
+
 	default:
 		panic(errors.Errorf("unknown changeset event metadata %T", e))
 	}
@@ -734,11 +830,29 @@ func ChangesetEventKindFor(e interface{}) ChangesetEventKind {
 		return ChangesetEventKindGitHubReviewRequested
 	case *github.UnassignedEvent:
 		return ChangesetEventKindGitHubUnassigned
+	case *bitbucketserver.Activity:
+		if k, ok := bitbucketServerActivityKinds[e.Action]; ok {
+			return k
+		}
+		panic(errors.Errorf("unknown changeset event kind for Bitbucket Server activity action %q", e.Action))
 	default:
 		panic(errors.Errorf("unknown changeset event kind for %T", e))
 	}
 }
 
+var bitbucketServerActivityKinds = map[bitbucketserver.ActivityAction]ChangesetEventKind{
+	bitbucketserver.ActivityActionApproved:   ChangesetEventKindBitbucketServerApproved,
+	bitbucketserver.ActivityActionCommented:  ChangesetEventKindBitbucketServerCommented,
+	bitbucketserver.ActivityActionDeclined:   ChangesetEventKindBitbucketServerDeclined,
+	bitbucketserver.ActivityActionMerged:     ChangesetEventKindBitbucketServerMerged,
+	bitbucketserver.ActivityActionOpened:     ChangesetEventKindBitbucketServerOpened,
+	bitbucketserver.ActivityActionReopened:   ChangesetEventKindBitbucketServerReopened,
+	bitbucketserver.ActivityActionRescoped:   ChangesetEventKindBitbucketServerRescoped,
+	bitbucketserver.ActivityActionReviewed:   ChangesetEventKindBitbucketServerReviewed,
+	bitbucketserver.ActivityActionUnapproved: ChangesetEventKindBitbucketServerUnapproved,
+	bitbucketserver.ActivityActionUpdated:    ChangesetEventKindBitbucketServerUpdated,
+}
+
 // ChangesetEventKind defines the kind of a ChangesetEvent. This type is unexported
 // so that users of ChangesetEvent can't instantiate it with a Kind being an arbitrary
 // string.
@@ -759,16 +873,16 @@ const (
 	ChangesetEventKindGitHubReviewCommented      ChangesetEventKind = "github:review_commented"
 	ChangesetEventKindGitHubUnassigned           ChangesetEventKind = "github:unassigned"
 
-	// TODO: Full set of Bitbucket Server pull request actions:
-	//   - APPROVED
-	//   - COMMENTED
-	//   - DECLINED
-	//   - MERGED
-	//   - OPENED
-	//   - REOPENED
-	//   - RESCOPED
-	//   - UNAPPROVED
-	//   - UPDATED
+	ChangesetEventKindBitbucketServerApproved   ChangesetEventKind = "bitbucketserver:approved"
+	ChangesetEventKindBitbucketServerCommented  ChangesetEventKind = "bitbucketserver:commented"
+	ChangesetEventKindBitbucketServerDeclined   ChangesetEventKind = "bitbucketserver:declined"
+	ChangesetEventKindBitbucketServerMerged     ChangesetEventKind = "bitbucketserver:merged"
+	ChangesetEventKindBitbucketServerOpened     ChangesetEventKind = "bitbucketserver:opened"
+	ChangesetEventKindBitbucketServerReopened   ChangesetEventKind = "bitbucketserver:reopened"
+	ChangesetEventKindBitbucketServerRescoped   ChangesetEventKind = "bitbucketserver:rescoped"
+	ChangesetEventKindBitbucketServerReviewed   ChangesetEventKind = "bitbucketserver:reviewed"
+	ChangesetEventKindBitbucketServerUnapproved ChangesetEventKind = "bitbucketserver:unapproved"
+	ChangesetEventKindBitbucketServerUpdated    ChangesetEventKind = "bitbucketserver:updated"
 )
 
 func unixMilliToTime(ms int64) time.Time {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 )
 
@@ -76,6 +77,61 @@ func TestChangesetMetadata(t *testing.T) {
 	}
 }
 
+func TestChangesetBitbucketServerPullRequest(t *testing.T) {
+	user := bitbucketserver.User{Name: "milton"}
+	pr := &bitbucketserver.PullRequest{
+		ID:    3,
+		State: "DECLINED",
+		Activities: []*bitbucketserver.Activity{
+			{ID: 1, CreatedDate: 1569855734169, User: user, Action: bitbucketserver.ActivityActionOpened},
+			{ID: 2, CreatedDate: 1569855834169, User: user, Action: "SOMETHING_NEW"},
+			{ID: 3, CreatedDate: 1569855934169, User: user, Action: bitbucketserver.ActivityActionDeclined},
+		},
+	}
+	pr.FromRef.LatestCommit = "1f63e719a65cad47a0a272d3d6eef05f4da427bb"
+	pr.ToRef.LatestCommit = "0f5577eaf11a136541b8c667273b6bc5eba51a8b"
+	changeset := &Changeset{ID: 42, Metadata: pr}
+
+	state, err := changeset.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := ChangesetStateClosed, state; want != have {
+		t.Errorf("changeset state wrong. want=%q, have=%q", want, have)
+	}
+
+	base, head, err := changeset.Commits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(base) != pr.ToRef.LatestCommit || string(head) != pr.FromRef.LatestCommit {
+		t.Errorf("changeset commits wrong. want=(%s, %s), have=(%s, %s)", pr.ToRef.LatestCommit, pr.FromRef.LatestCommit, base, head)
+	}
+
+	events := changeset.Events()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 without the unknown activity", len(events))
+	}
+	for i, want := range []struct {
+		key  string
+		kind ChangesetEventKind
+	}{
+		{key: "1", kind: ChangesetEventKindBitbucketServerOpened},
+		{key: "3", kind: ChangesetEventKindBitbucketServerDeclined},
+	} {
+		e := events[i]
+		if e.ChangesetID != 42 || e.Key != want.key || e.Kind != want.kind {
+			t.Errorf("event %d wrong. want=(42, %q, %q), have=(%d, %q, %q)", i, want.key, want.kind, e.ChangesetID, e.Key, e.Kind)
+		}
+		if have := e.Actor(); have != "milton" {
+			t.Errorf("event %d actor wrong. want=%q, have=%q", i, "milton", have)
+		}
+	}
+	if want, have := unixMilliToTime(1569855934169), events[1].Timestamp(); !want.Equal(have) {
+		t.Errorf("event timestamp wrong. want=%s, have=%s", want, have)
+	}
+}
+
 func TestChangesetEventsReviewState(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Microsecond)
 	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
@@ -129,6 +129,19 @@ type Settings struct {
 	CreatedAt    time.Time       // the date when this settings value was created
 }
 
+// SettingsWatchRequest is a request to wait until the latest settings of a
+// subject change.
+type SettingsWatchRequest struct {
+	Subject SettingsSubject
+	LastID  *int32 // the ID of the latest settings known to the client (nil if it knows of none)
+}
+
+// SettingsWatchResponse is the response to a SettingsWatchRequest.
+type SettingsWatchResponse struct {
+	Changed  bool      // whether the latest settings changed before the request timed out
+	Settings *Settings // the latest settings if Changed (nil if the subject has no settings)
+}
+
 // ExternalService represents an complete external service record.
 type ExternalService struct {
 	ID          int64
@@ -1,5 +1,11 @@
 package api
 
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
 // RepoCreateOrUpdateRequest is a request to create or update a repository.
 //
 // The request handler determines if the request refers to an existing repository (and should therefore update
@@ -40,6 +46,43 @@ type ReposUpdateMetadataRequest struct {
 	Archived    bool   `json:"Archived"`
 }
 
+type ReposSetKVPRequest struct {
+	RepoName `json:"repo"`
+	Key      string  `json:"key"`
+	Value    *string `json:"value"`
+}
+
+type ReposUpdateLastCommitRequest struct {
+	RepoName     `json:"repo"`
+	LastCommitAt time.Time `json:"lastCommitAt"`
+}
+
+// GitIsAncestorRequest is a batch of ancestry queries on the commits of a
+// repository.
+type GitIsAncestorRequest struct {
+	RepoName `json:"repo"`
+	Pairs    []GitCommitPair `json:"pairs"`
+}
+
+// GitCommitPair is a pair of commits, asking whether Ancestor is an ancestor
+// of Descendant.
+type GitCommitPair struct {
+	Ancestor   CommitID `json:"ancestor"`
+	Descendant CommitID `json:"descendant"`
+}
+
+// PermissionsInvalidateRequest is a batch of cached repository permissions
+// to invalidate.
+type PermissionsInvalidateRequest struct {
+	Pairs []UserRepoPair `json:"pairs"`
+}
+
+// UserRepoPair identifies the permissions of a user on a repository.
+type UserRepoPair struct {
+	UserID int32  `json:"userID"`
+	RepoID RepoID `json:"repoID"`
+}
+
 type PhabricatorRepoCreateRequest struct {
 	RepoName `json:"repo"`
 	Callsign string `json:"callsign"`
@@ -56,3 +99,28 @@ type ExternalServicesListRequest struct {
 	Kind  string   `json:"kind"`
 	Kinds []string `json:"kinds"`
 }
+
+// SendNotificationRequest is a request to email a notification to users.
+type SendNotificationRequest struct {
+	// Source is the feature that sends the notification (e.g.
+	// "saved-search"). It is only used for logging.
+	Source string `json:"source"`
+
+	// UserIDs are the users to notify. Each user is sent a separate email at
+	// their primary email address.
+	UserIDs []int32 `json:"userIDs"`
+
+	// Template and Data are the templates of the email and the data they are
+	// rendered with, as for txemail.Message.
+	Template txtypes.Templates `json:"template"`
+	Data     interface{}       `json:"data"`
+}
+
+// NotificationDelivery is the delivery status of a notification for one of
+// its recipients.
+type NotificationDelivery struct {
+	UserID int32  `json:"userID"`
+	Email  string `json:"email,omitempty"` // empty if the user has no email
+	Sent   bool   `json:"sent"`
+	Error  string `json:"error,omitempty"` // why the email wasn't sent
+}
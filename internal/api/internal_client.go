@@ -17,7 +17,29 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 )
 
-var frontendInternal = env.Get("SRC_FRONTEND_INTERNAL", "sourcegraph-frontend-internal", "HTTP address for internal frontend HTTP API.")
+var (
+	frontendInternal  = env.Get("SRC_FRONTEND_INTERNAL", "sourcegraph-frontend-internal", "HTTP address for internal frontend HTTP API.")
+	internalAPISecret = env.Get("SRC_INTERNAL_API_SECRET", "", "Secret this service sends to the internal frontend HTTP API to authenticate itself (see `internalAPI.auth` in critical configuration).")
+)
+
+const (
+	// InternalServiceHeader is the HTTP request header in which services
+	// send their name to the internal API.
+	InternalServiceHeader = "X-Sourcegraph-Service"
+
+	// InternalSecretHeader is the HTTP request header in which services send
+	// their secret (SRC_INTERNAL_API_SECRET) to the internal API.
+	InternalSecretHeader = "X-Sourcegraph-Internal-Secret"
+)
+
+// SetInternalAuthHeaders sets the headers which identify and authenticate
+// this service to the internal API on h.
+func SetInternalAuthHeaders(h http.Header) {
+	h.Set(InternalServiceHeader, env.MyName)
+	if internalAPISecret != "" {
+		h.Set(InternalSecretHeader, internalAPISecret)
+	}
+}
 
 type internalClient struct {
 	// URL is the root to the internal API frontend server.
@@ -30,7 +52,12 @@ var InternalClient = &internalClient{URL: "http://" + frontendInternal}
 // the endpoint, indicating that the frontend is available.
 func (c *internalClient) WaitForFrontend(ctx context.Context) error {
 	ping := func(ctx context.Context) error {
-		resp, err := ctxhttp.Get(ctx, nil, c.URL+"/.internal/ping")
+		req, err := http.NewRequest("GET", c.URL+"/.internal/ping", nil)
+		if err != nil {
+			return err
+		}
+		SetInternalAuthHeaders(req.Header)
+		resp, err := ctxhttp.Do(ctx, nil, req)
 		if err != nil {
 			return err
 		}
@@ -166,6 +193,53 @@ func (c *internalClient) SettingsGetForSubject(ctx context.Context, subject Sett
 	return parsed, settings, err
 }
 
+// SettingsWatchSubject waits until the latest settings of the subject differ
+// from the settings with ID lastID (nil if none are known) and returns them.
+// If they don't change before the frontend times out the request, changed is
+// false and it should be called again.
+func (c *internalClient) SettingsWatchSubject(ctx context.Context, subject SettingsSubject, lastID *int32) (changed bool, parsed *schema.Settings, settings *Settings, err error) {
+	var resp SettingsWatchResponse
+	err = c.postInternal(ctx, "settings/watch-subject", SettingsWatchRequest{Subject: subject, LastID: lastID}, &resp)
+	if err != nil || !resp.Changed {
+		return false, nil, nil, err
+	}
+	if resp.Settings != nil {
+		if err := jsonc.Unmarshal(resp.Settings.Contents, &parsed); err != nil {
+			return false, nil, nil, err
+		}
+	}
+	return true, parsed, resp.Settings, nil
+}
+
+// WatchSettings calls onChange with the latest settings of the subject (nil if
+// it has none) and then again every time they change, until ctx is done. It
+// lets services react to settings changes right away instead of polling them.
+func (c *internalClient) WatchSettings(ctx context.Context, subject SettingsSubject, onChange func(parsed *schema.Settings, settings *Settings)) {
+	// No settings have ID -1, so the first request returns right away.
+	noID := int32(-1)
+	lastID := &noID
+	for ctx.Err() == nil {
+		changed, parsed, settings, err := c.SettingsWatchSubject(ctx, subject, lastID)
+		if err != nil {
+			// Keep trying, the frontend may be restarting.
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		lastID = nil
+		if settings != nil {
+			lastID = &settings.ID
+		}
+		onChange(parsed, settings)
+	}
+}
+
 var MockOrgsListUsers func(orgID int32) (users []int32, err error)
 
 func (c *internalClient) OrgsListUsers(ctx context.Context, orgID int32) (users []int32, err error) {
@@ -203,6 +277,17 @@ func (c *internalClient) UserEmailsGetEmail(ctx context.Context, userID int32) (
 	return email, nil
 }
 
+// UserEmailsGetEmails returns the primary email of each of the given users.
+// Users without an email are left out.
+func (c *internalClient) UserEmailsGetEmails(ctx context.Context, userIDs []int32) (map[int32]string, error) {
+	var emails map[int32]string
+	err := c.postInternal(ctx, "user-emails/get-emails", userIDs, &emails)
+	if err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
 // TODO(slimsag): In the future, once we're no longer using environment
 // variables to build ExternalURL, remove this in favor of services just reading it
 // directly from the configuration file.
@@ -231,6 +316,18 @@ func (c *internalClient) SendEmail(ctx context.Context, message txtypes.Message)
 	return c.postInternal(ctx, "send-email", &message, nil)
 }
 
+// SendNotification emails a notification to each of the users in the
+// request, and returns the delivery status for each of them. Sending is rate
+// limited, and failing to notify one user doesn't stop notifying the others.
+func (c *internalClient) SendNotification(ctx context.Context, req SendNotificationRequest) ([]NotificationDelivery, error) {
+	var deliveries []NotificationDelivery
+	err := c.postInternal(ctx, "send-notification", &req, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
 func (c *internalClient) ReposCreateIfNotExists(ctx context.Context, op RepoCreateOrUpdateRequest) (*Repo, error) {
 	var repo Repo
 	err := c.postInternal(ctx, "repos/create-if-not-exists", op, &repo)
@@ -268,6 +365,25 @@ func (c *internalClient) ReposUpdateMetadata(ctx context.Context, repo RepoName,
 	}, nil)
 }
 
+// ReposSetKVP sets the value of the key-value pair of the repository with the
+// given key, adding the pair if it doesn't exist yet.
+func (c *internalClient) ReposSetKVP(ctx context.Context, repo RepoName, key string, value *string) error {
+	return c.postInternal(ctx, "repos/set-kvp", ReposSetKVPRequest{
+		RepoName: repo,
+		Key:      key,
+		Value:    value,
+	}, nil)
+}
+
+// ReposUpdateLastCommit records the committer date of the latest commit on the
+// default branch of the repository.
+func (c *internalClient) ReposUpdateLastCommit(ctx context.Context, repo RepoName, lastCommitAt time.Time) error {
+	return c.postInternal(ctx, "repos/update-last-commit", ReposUpdateLastCommitRequest{
+		RepoName:     repo,
+		LastCommitAt: lastCommitAt,
+	}, nil)
+}
+
 func (c *internalClient) ReposGetByName(ctx context.Context, repoName RepoName) (*Repo, error) {
 	var repo Repo
 	err := c.postInternal(ctx, "repos/"+string(repoName), nil, &repo)
@@ -277,6 +393,29 @@ func (c *internalClient) ReposGetByName(ctx context.Context, repoName RepoName)
 	return &repo, nil
 }
 
+// GitIsAncestor reports, for each of the given pairs of commits of the
+// repository, whether the first commit is an ancestor of the second.
+func (c *internalClient) GitIsAncestor(ctx context.Context, repo RepoName, pairs []GitCommitPair) ([]bool, error) {
+	var isAncestor []bool
+	err := c.postInternal(ctx, "git/is-ancestor", GitIsAncestorRequest{
+		RepoName: repo,
+		Pairs:    pairs,
+	}, &isAncestor)
+	if err != nil {
+		return nil, err
+	}
+	return isAncestor, nil
+}
+
+// PermissionsInvalidate enqueues the invalidation of the cached permissions
+// of the given users on the given repositories. The invalidations are
+// processed asynchronously.
+func (c *internalClient) PermissionsInvalidate(ctx context.Context, pairs []UserRepoPair) error {
+	return c.postInternal(ctx, "authz/invalidate-permissions", PermissionsInvalidateRequest{
+		Pairs: pairs,
+	}, nil)
+}
+
 func (c *internalClient) PhabricatorRepoCreate(ctx context.Context, repo RepoName, callsign, url string) error {
 	return c.postInternal(ctx, "phabricator/repo-create", PhabricatorRepoCreateRequest{
 		RepoName: repo,
@@ -326,7 +465,14 @@ func (c *internalClient) post(ctx context.Context, route string, reqBody, respBo
 		}
 	}
 
-	resp, err := ctxhttp.Post(ctx, nil, c.URL+route, "application/json", bytes.NewBuffer(data))
+	req, err := http.NewRequest("POST", c.URL+route, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetInternalAuthHeaders(req.Header)
+
+	resp, err := ctxhttp.Do(ctx, nil, req)
 	if err != nil {
 		return err
 	}
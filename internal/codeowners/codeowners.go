@@ -0,0 +1,126 @@
+// Package codeowners parses CODEOWNERS files, which assign owners to the
+// files in a repository.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// A Rule assigns owners to the files that match a pattern.
+type Rule struct {
+	// Pattern is the gitignore-style pattern of the rule, as written in the
+	// CODEOWNERS file.
+	Pattern string
+	// Owners are the users, teams and email addresses that own the files
+	// matched by Pattern. Rules without owners mark files as unowned.
+	Owners []string
+
+	re *regexp.Regexp
+}
+
+// A Ruleset is the list of rules in a CODEOWNERS file.
+type Ruleset struct {
+	Rules []*Rule
+}
+
+// Parse parses the contents of a CODEOWNERS file.
+func Parse(data []byte) (*Ruleset, error) {
+	var rs Ruleset
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; s.Scan(); lineNumber++ {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %s", lineNumber, fields[0], err)
+		}
+
+		rs.Rules = append(rs.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			re:      re,
+		})
+	}
+
+	return &rs, s.Err()
+}
+
+// Match returns the owners of the file at path, which is relative to the root
+// of the repository. The last rule that matches the path takes precedence.
+func (rs *Ruleset) Match(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].re.MatchString(path) {
+			return rs.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// compilePattern compiles a gitignore-style pattern into a regexp that
+// matches the paths of the files it applies to, including the files in the
+// directories it matches.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	// Patterns with a slash at the beginning or in the middle are relative
+	// to the root of the repository. Others match at any depth.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
+
+// OwnerMatches reports whether owner, as written in a CODEOWNERS file, is the
+// owner named by query. The comparison is case-insensitive and ignores the
+// leading @ of user and team names.
+func OwnerMatches(owner, query string) bool {
+	return strings.EqualFold(strings.TrimPrefix(owner, "@"), strings.TrimPrefix(query, "@"))
+}
@@ -0,0 +1,74 @@
+package codeowners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleset_Match(t *testing.T) {
+	rs, err := Parse([]byte(`
+# Default owners
+*                   @sourcegraph/everyone
+
+*.go                @sourcegraph/go    # Go code
+/docs/              docs@sourcegraph.com
+cmd/frontend        @sourcegraph/web
+**/testdata/**      @sourcegraph/qa
+internal/*/README.md @alice
+/vendor/
+build?.sh           @bob
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string][]string{
+		"README.md":                       {"@sourcegraph/everyone"},
+		"main.go":                         {"@sourcegraph/go"},
+		"internal/foo/foo.go":             {"@sourcegraph/go"},
+		"docs/index.md":                   {"docs@sourcegraph.com"},
+		"docs/dev/index.md":               {"docs@sourcegraph.com"},
+		"internal/docs/index.md":          {"@sourcegraph/everyone"},
+		"cmd/frontend/main.go":            {"@sourcegraph/web"},
+		"cmd/frontend2/main.go":           {"@sourcegraph/go"},
+		"internal/a/testdata/golden/x.go": {"@sourcegraph/qa"},
+		"testdata/x":                      {"@sourcegraph/qa"},
+		"internal/foo/README.md":          {"@alice"},
+		"internal/foo/bar/README.md":      {"@sourcegraph/everyone"},
+		"vendor/lib.go":                   nil,
+		"build1.sh":                       {"@bob"},
+		"build10.sh":                      {"@sourcegraph/everyone"},
+		"/main.go":                        {"@sourcegraph/go"},
+	} {
+		if have := rs.Match(path); !reflect.DeepEqual(have, want) && !(len(have) == 0 && len(want) == 0) {
+			t.Errorf("Match(%q) = %q, want %q", path, have, want)
+		}
+	}
+}
+
+func TestRuleset_Match_noRules(t *testing.T) {
+	rs, err := Parse(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := rs.Match("main.go"); have != nil {
+		t.Errorf("Match = %q, want nil", have)
+	}
+}
+
+func TestOwnerMatches(t *testing.T) {
+	for _, tc := range []struct {
+		owner, query string
+		want         bool
+	}{
+		{"@sourcegraph/web", "sourcegraph/web", true},
+		{"@sourcegraph/web", "@Sourcegraph/Web", true},
+		{"docs@sourcegraph.com", "docs@sourcegraph.com", true},
+		{"@sourcegraph/web", "sourcegraph/go", false},
+		{"@alice", "alice", true},
+	} {
+		if have := OwnerMatches(tc.owner, tc.query); have != tc.want {
+			t.Errorf("OwnerMatches(%q, %q) = %t, want %t", tc.owner, tc.query, have, tc.want)
+		}
+	}
+}
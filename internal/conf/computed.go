@@ -211,6 +211,15 @@ func SymbolIndexEnabled() bool {
 	return enabled
 }
 
+// SearchUnicodeNormalization returns the Unicode normalization form ("NFC" or
+// "NFD") that searches with unicode:yes normalize text to.
+func SearchUnicodeNormalization() string {
+	if v := Get().SearchUnicodeNormalization; v != "" {
+		return v
+	}
+	return "NFC"
+}
+
 func UsingExternalURL() bool {
 	url := Get().Critical.ExternalURL
 	return !(url == "" || strings.HasPrefix(url, "http://localhost") || strings.HasPrefix(url, "https://localhost") || strings.HasPrefix(url, "http://127.0.0.1") || strings.HasPrefix(url, "https://127.0.0.1")) // CI:LOCALHOST_OK
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	tracepkg "github.com/sourcegraph/sourcegraph/internal/trace"
 
 	"golang.org/x/net/trace"
 
@@ -80,6 +81,7 @@ func Start(extra ...Endpoint) {
 				<a href="metrics">Metrics</a><br>
 				<a href="debug/requests">Requests</a><br>
 				<a href="debug/events">Events</a><br>
+				<a href="debug/slow">Slow operations</a><br>
 			`))
 		for _, e := range extra {
 			fmt.Fprintf(w, `<a href="%s">%s</a><br>`, strings.TrimPrefix(e.Path, "/"), e.Name)
@@ -102,6 +104,7 @@ func Start(extra ...Endpoint) {
 	pp.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
 	pp.Handle("/debug/requests", http.HandlerFunc(trace.Traces))
 	pp.Handle("/debug/events", http.HandlerFunc(trace.Events))
+	pp.Handle("/debug/slow", http.HandlerFunc(tracepkg.ExemplarsHandler))
 	pp.Handle("/metrics", promhttp.Handler())
 	for _, e := range extra {
 		pp.Handle(e.Path, e.Handler)
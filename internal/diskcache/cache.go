@@ -282,6 +282,17 @@ func (s *Store) Evict(maxCacheSizeBytes int64) (stats EvictStats, err error) {
 	return stats, nil
 }
 
+// Demote makes the cache item at path, as returned by Open, the first to be
+// evicted by Evict, unless it is opened again before. It is used for items
+// that are unlikely to be needed again.
+func (s *Store) Demote(path string) error {
+	return os.Chtimes(path, demotedTime, demotedTime)
+}
+
+// demotedTime is the modification time of demoted cache items, which is
+// older than that of all other items.
+var demotedTime = time.Unix(0, 0)
+
 func copyAndClose(dst io.WriteCloser, src io.ReadCloser) error {
 	_, err := io.Copy(dst, src)
 	if err1 := src.Close(); err == nil {
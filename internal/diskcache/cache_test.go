@@ -61,3 +61,57 @@ func TestOpen(t *testing.T) {
 		t.Fatal("Item was not properly evicted")
 	}
 }
+
+func TestDemote(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diskcache_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &Store{
+		Dir:       dir,
+		Component: "test",
+	}
+
+	open := func(key string) *File {
+		f, err := store.Open(context.Background(), key, func(ctx context.Context) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader([]byte("foobar"))), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		return f
+	}
+
+	a := open("a")
+	b := open("b")
+	if err := store.Demote(b.Path); err != nil {
+		t.Fatal(err)
+	}
+
+	// b was used last, but it is evicted first since it was demoted.
+	if _, err := store.Evict(int64(len("foobar"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(a.Path); err != nil {
+		t.Errorf("expected a to be kept: %v", err)
+	}
+	if _, err := os.Stat(b.Path); !os.IsNotExist(err) {
+		t.Errorf("expected b to be evicted, got %v", err)
+	}
+
+	// Opening a demoted item again promotes it.
+	b = open("b")
+	if err := store.Demote(b.Path); err != nil {
+		t.Fatal(err)
+	}
+	b = open("b")
+	if _, err := store.Evict(int64(len("foobar"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(b.Path); err != nil {
+		t.Errorf("expected b to be kept after it was opened again: %v", err)
+	}
+}
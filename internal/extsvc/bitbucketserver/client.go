@@ -384,6 +384,100 @@ func (c *Client) LoadPullRequest(ctx context.Context, pr *PullRequest) error {
 	return c.send(ctx, "GET", path, nil, nil, pr)
 }
 
+// CreatePullRequest creates the given PullRequest from its FromRef into its
+// ToRef, which must reference branches by their ID (e.g. refs/heads/master),
+// and loads the created PullRequest into it.
+func (c *Client) CreatePullRequest(ctx context.Context, pr *PullRequest) error {
+	if pr.ToRef.Repository.Slug == "" {
+		return errors.New("repository slug empty")
+	}
+	if pr.ToRef.Repository.Project.Key == "" {
+		return errors.New("project key empty")
+	}
+	if pr.FromRef.ID == "" || pr.ToRef.ID == "" {
+		return errors.New("ref ID empty")
+	}
+
+	// The ID, state and links of the pull request are set by Bitbucket
+	// Server, so only the fields that it accepts are sent.
+	payload := struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		FromRef     Ref    `json:"fromRef"`
+		ToRef       Ref    `json:"toRef"`
+	}{
+		Title:       pr.Title,
+		Description: pr.Description,
+		FromRef:     pr.FromRef,
+		ToRef:       pr.ToRef,
+	}
+
+	path := fmt.Sprintf(
+		"rest/api/1.0/projects/%s/repos/%s/pull-requests",
+		pr.ToRef.Repository.Project.Key,
+		pr.ToRef.Repository.Slug,
+	)
+	return c.send(ctx, "POST", path, nil, payload, pr)
+}
+
+// DeclinePullRequest declines (i.e. closes without merging) the given
+// PullRequest, whose Version must be the current version of the pull request,
+// and loads the declined PullRequest into it.
+func (c *Client) DeclinePullRequest(ctx context.Context, pr *PullRequest) error {
+	if pr.ToRef.Repository.Slug == "" {
+		return errors.New("repository slug empty")
+	}
+	if pr.ToRef.Repository.Project.Key == "" {
+		return errors.New("project key empty")
+	}
+
+	path := fmt.Sprintf(
+		"rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/decline",
+		pr.ToRef.Repository.Project.Key,
+		pr.ToRef.Repository.Slug,
+		pr.ID,
+	)
+	qry := url.Values{"version": {strconv.Itoa(pr.Version)}}
+	return c.send(ctx, "POST", path, qry, nil, pr)
+}
+
+// LoadPullRequestActivities loads all the activities of the given PullRequest,
+// oldest first, into its Activities.
+func (c *Client) LoadPullRequestActivities(ctx context.Context, pr *PullRequest) error {
+	if pr.ToRef.Repository.Slug == "" {
+		return errors.New("repository slug empty")
+	}
+	if pr.ToRef.Repository.Project.Key == "" {
+		return errors.New("project key empty")
+	}
+
+	path := fmt.Sprintf(
+		"rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/activities",
+		pr.ToRef.Repository.Project.Key,
+		pr.ToRef.Repository.Slug,
+		pr.ID,
+	)
+
+	var activities []*Activity
+	t := &PageToken{Limit: 1000}
+	for t.HasMore() {
+		var page []*Activity
+		next, err := c.page(ctx, path, nil, t, &page)
+		if err != nil {
+			return err
+		}
+		activities, t = append(activities, page...), next
+	}
+
+	// Bitbucket Server returns the most recent activities first.
+	for i, j := 0, len(activities)-1; i < j; i, j = i+1, j-1 {
+		activities[i], activities[j] = activities[j], activities[i]
+	}
+	pr.Activities = activities
+
+	return nil
+}
+
 func (c *Client) Repo(ctx context.Context, projectKey, repoSlug string) (*Repo, error) {
 	u := fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s", projectKey, repoSlug)
 	req, err := http.NewRequest("GET", u, nil)
@@ -733,8 +827,9 @@ type Project struct {
 }
 
 type Ref struct {
-	ID         string `json:"id"`
-	Repository struct {
+	ID           string `json:"id"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
 		Slug    string `json:"slug"`
 		Project struct {
 			Key string `json:"key"`
@@ -779,6 +874,55 @@ type PullRequest struct {
 			Href string `json:"href"`
 		} `json:"self"`
 	} `json:"links"`
+	Activities []*Activity `json:"activities,omitempty"`
+}
+
+// ActivityAction is the action of an Activity on a PullRequest.
+type ActivityAction string
+
+// Known ActivityAction values.
+const (
+	ActivityActionApproved   ActivityAction = "APPROVED"
+	ActivityActionCommented  ActivityAction = "COMMENTED"
+	ActivityActionDeclined   ActivityAction = "DECLINED"
+	ActivityActionMerged     ActivityAction = "MERGED"
+	ActivityActionOpened     ActivityAction = "OPENED"
+	ActivityActionReopened   ActivityAction = "REOPENED"
+	ActivityActionRescoped   ActivityAction = "RESCOPED"
+	ActivityActionReviewed   ActivityAction = "REVIEWED"
+	ActivityActionUnapproved ActivityAction = "UNAPPROVED"
+	ActivityActionUpdated    ActivityAction = "UPDATED"
+)
+
+// An Activity is an event in the lifetime of a PullRequest, as listed by its
+// activities API.
+type Activity struct {
+	ID            int            `json:"id"`
+	CreatedDate   int            `json:"createdDate"`
+	User          User           `json:"user"`
+	Action        ActivityAction `json:"action"`
+	CommentAction string         `json:"commentAction,omitempty"`
+	Comment       *Comment       `json:"comment,omitempty"`
+
+	// The commits of the pull request before and after a RESCOPED activity.
+	FromHash         string `json:"fromHash,omitempty"`
+	PreviousFromHash string `json:"previousFromHash,omitempty"`
+	ToHash           string `json:"toHash,omitempty"`
+	PreviousToHash   string `json:"previousToHash,omitempty"`
+}
+
+// Key is a unique key identifying this activity in the context of its pull
+// request.
+func (a *Activity) Key() string { return strconv.Itoa(a.ID) }
+
+// A Comment on a PullRequest.
+type Comment struct {
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Text        string `json:"text"`
+	Author      User   `json:"author"`
+	CreatedDate int    `json:"createdDate"`
+	UpdatedDate int    `json:"updatedDate"`
 }
 
 // IsNotFound reports whether err is a Bitbucket Server API not found error.
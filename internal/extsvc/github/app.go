@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+// appInstallation is an installation of a GitHub App, as which a Client
+// authenticates with installation access tokens.
+//
+// See https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/.
+type appInstallation struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	now            func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// installationTokenRefreshMargin is how long before it expires an
+// installation access token is refreshed. Tokens are valid for an hour, and
+// callers may hold on to one for a while (e.g. in a clone URL), so it mustn't
+// be handed out right before it expires.
+const installationTokenRefreshMargin = 10 * time.Minute
+
+// NewAppInstallationClient creates a new GitHub API client that authenticates
+// as the given installation of a GitHub App. It creates installation access
+// tokens with the App's PEM-encoded private key, and refreshes them before they
+// expire.
+//
+// apiURL must point to the base URL of the GitHub API. See the docstring for Client.apiURL.
+func NewAppInstallationClient(apiURL *url.URL, appID, installationID int64, privateKey []byte, cli httpcli.Doer) (*Client, error) {
+	key, err := parseAppPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewClient(apiURL, "", cli)
+	c.installation = &appInstallation{
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		now:            time.Now,
+	}
+	return c, nil
+}
+
+func parseAppPrivateKey(privateKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("invalid GitHub App private key: no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid GitHub App private key")
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid GitHub App private key: not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// String returns an identifier of the installation, which is used in cache
+// keys instead of its access tokens, because those change.
+func (i *appInstallation) String() string {
+	return fmt.Sprintf("app:%d:installation:%d", i.appID, i.installationID)
+}
+
+// jwt returns a JSON Web Token authenticating as the App, signed with its
+// private key.
+func (i *appInstallation) jwt(now time.Time) (string, error) {
+	claims, err := json.Marshal(struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+		Issuer    int64 `json:"iss"`
+	}{
+		// Allow for clock drift between us and GitHub, whose maximum JWT
+		// lifetime is 10 minutes.
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(9 * time.Minute).Unix(),
+		Issuer:    i.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	payload := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return payload + "." + enc.EncodeToString(sig), nil
+}
+
+// InstallationToken returns a valid access token of the GitHub App
+// installation that the client authenticates as, creating a new one if the
+// current one is about to expire. It returns an error if the client doesn't
+// authenticate as a GitHub App installation.
+func (c *Client) InstallationToken(ctx context.Context) (string, error) {
+	i := c.installation
+	if i == nil {
+		return "", errors.New("GitHub client doesn't authenticate as a GitHub App installation")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	if i.token != "" && now.Add(installationTokenRefreshMargin).Before(i.expiresAt) {
+		return i.token, nil
+	}
+
+	jwt, err := i.jwt(now)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("app/installations/%d/access_tokens", i.installationID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := c.do(ctx, jwt, req, &result); err != nil {
+		return "", errors.Wrapf(err, "creating access token of GitHub App installation %d", i.installationID)
+	}
+
+	i.token, i.expiresAt = result.Token, result.ExpiresAt
+	return i.token, nil
+}
+
+// ListInstallationRepositories lists the GitHub repositories that the GitHub
+// App installation that the client authenticates as has access to. page is the
+// page of results to return. Pages are 1-indexed (so the first call should be
+// for page 1).
+func (c *Client) ListInstallationRepositories(ctx context.Context, page int) (repos []*Repository, hasNextPage bool, rateLimitCost int, err error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("installation/repositories?page=%d&per_page=100", page), nil)
+	if err != nil {
+		return nil, false, 1, err
+	}
+	// See requestGet for the other preview APIs.
+	req.Header.Add("Accept", "application/vnd.github.machine-man-preview+json,application/vnd.github.jean-grey-preview+json,application/vnd.github.mercy-preview+json")
+
+	var result struct {
+		TotalCount   int              `json:"total_count"`
+		Repositories []restRepository `json:"repositories"`
+	}
+	if err := c.do(ctx, "", req, &result); err != nil {
+		return nil, false, 1, err
+	}
+
+	repos = make([]*Repository, 0, len(result.Repositories))
+	for _, restRepo := range result.Repositories {
+		repos = append(repos, convertRestRepo(restRepo))
+	}
+	// 🚨 SECURITY: must forward token here to ensure caching by token
+	c.addRepositoriesToCache("", repos)
+
+	return repos, page*100 < result.TotalCount, 1, nil
+}
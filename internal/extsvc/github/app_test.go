@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+func TestClient_InstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	created := 0
+	cli := httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if want := "/api/v3/app/installations/42/access_tokens"; req.URL.Path != want || req.Method != "POST" {
+			t.Fatalf("got request %s %s, want POST %s", req.Method, req.URL.Path, want)
+		}
+		if err := verifyAppJWT(&key.PublicKey, strings.TrimPrefix(req.Header.Get("Authorization"), "bearer "), 7, now); err != nil {
+			t.Fatal(err)
+		}
+		created++
+		body := fmt.Sprintf(`{"token": "t%d", "expires_at": %q}`, created, now.Add(time.Hour).Format(time.RFC3339))
+		return &http.Response{
+			Request:    req,
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+
+	c, err := NewAppInstallationClient(&url.URL{Scheme: "https", Host: "ghe.example.com", Path: "/api/v3"}, 7, 42, privateKey, cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.installation.now = func() time.Time { return now }
+
+	for _, tc := range []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{0, "t1"},
+		{30 * time.Minute, "t1"},
+		{55 * time.Minute, "t2"}, // about to expire
+	} {
+		now = now.Add(tc.elapsed)
+		token, err := c.InstallationToken(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != tc.want {
+			t.Errorf("after %s: got token %q, want %q", tc.elapsed, token, tc.want)
+		}
+		now = now.Add(-tc.elapsed)
+	}
+
+	if _, err := NewClient(&url.URL{Scheme: "https", Host: "ghe.example.com"}, "pat", cli).InstallationToken(context.Background()); err == nil {
+		t.Error("want an error for a client that doesn't authenticate as an installation")
+	}
+}
+
+// verifyAppJWT verifies that jwt authenticates as the GitHub App with the
+// given ID and public key at the given time.
+func verifyAppJWT(key *rsa.PublicKey, jwt string, appID int64, now time.Time) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed JWT %q", jwt)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+		Issuer    int64 `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return err
+	}
+	if claims.Issuer != appID {
+		return fmt.Errorf("got issuer %d, want %d", claims.Issuer, appID)
+	}
+	if n := now.Unix(); n < claims.IssuedAt || n >= claims.ExpiresAt || claims.ExpiresAt-claims.IssuedAt > 10*60 {
+		return fmt.Errorf("JWT valid from %d to %d, not at %d", claims.IssuedAt, claims.ExpiresAt, n)
+	}
+	return nil
+}
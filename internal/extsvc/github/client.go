@@ -60,6 +60,10 @@ type Client struct {
 	// unauthenticated requests.
 	defaultToken string
 
+	// installation is the GitHub App installation that the client authenticates as if it has no
+	// defaultToken, or nil.
+	installation *appInstallation
+
 	// httpClient is the HTTP client used to make requests to the GitHub API.
 	httpClient httpcli.Doer
 
@@ -166,6 +170,10 @@ func NewClient(apiURL *url.URL, defaultToken string, cli httpcli.Doer) *Client {
 // repoCache directly.
 func (c *Client) cache(explicitToken string) *rcache.Cache {
 	token := firstNonEmpty(explicitToken, c.defaultToken)
+	if token == "" && c.installation != nil {
+		// Installation access tokens are short-lived, so cache by installation instead.
+		token = c.installation.String()
+	}
 
 	c.repoCacheMu.RLock()
 	if cache, ok := c.repoCache[token]; ok {
@@ -190,6 +198,11 @@ func (c *Client) do(ctx context.Context, token string, req *http.Request, result
 	req.URL.Path = path.Join(c.apiURL.Path, req.URL.Path)
 	req.URL = c.apiURL.ResolveReference(req.URL)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token == "" && c.defaultToken == "" && c.installation != nil {
+		if token, err = c.InstallationToken(ctx); err != nil {
+			return err
+		}
+	}
 	if token != "" {
 		req.Header.Set("Authorization", "bearer "+token)
 	} else if c.defaultToken != "" {
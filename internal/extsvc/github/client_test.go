@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/pkg/errors"
@@ -161,6 +162,65 @@ func TestClient_LoadPullRequests(t *testing.T) {
 	}
 }
 
+func TestClient_UpdatedPullRequests(t *testing.T) {
+	var queries []string
+	doer := httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct{ Query string }
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		queries = append(queries, body.Query)
+
+		data := `{"data": {"sourcegraph_sourcegraph": {
+			"sourcegraph_sourcegraph_1": {"updatedAt": "2019-11-01T10:00:00Z"},
+			"sourcegraph_sourcegraph_2": {"updatedAt": "2019-11-02T10:00:00Z"}
+		}}}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader(data)),
+		}, nil
+	})
+
+	uri, err := url.Parse("https://github.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := NewClient(uri, "", doer)
+
+	loadedAt := time.Date(2019, 11, 1, 10, 0, 0, 0, time.UTC)
+	var (
+		unchanged = &PullRequest{RepoWithOwner: "sourcegraph/sourcegraph", Number: 1, UpdatedAt: loadedAt}
+		changed   = &PullRequest{RepoWithOwner: "sourcegraph/sourcegraph", Number: 2, UpdatedAt: loadedAt}
+		unloaded  = &PullRequest{RepoWithOwner: "sourcegraph/sourcegraph", Number: 3}
+	)
+
+	updated, err := cli.UpdatedPullRequests(context.Background(), unchanged, changed, unloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []*PullRequest{unloaded, changed}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("got updated pull requests %+v, want %+v", updated, want)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(queries))
+	}
+	if strings.Contains(queries[0], "pullRequest(number: 3)") {
+		t.Errorf("pull request that was never loaded was queried: %s", queries[0])
+	}
+
+	// Pull requests that were never loaded don't need to be queried.
+	queries = nil
+	if updated, err = cli.UpdatedPullRequests(context.Background(), unloaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || len(queries) != 0 {
+		t.Errorf("got %d updated pull requests and %d queries, want 1 and 0", len(updated), len(queries))
+	}
+}
+
 func assertGolden(t testing.TB, path string, update bool, want interface{}) {
 	t.Helper()
 
@@ -0,0 +1,26 @@
+package github
+
+import "context"
+
+// Org is a GitHub organization.
+type Org struct {
+	Login       string `json:"login,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+var MockGetAuthenticatedUserOrgs func(ctx context.Context, token string) ([]*Org, error)
+
+// GetAuthenticatedUserOrgs returns the first 100 organizations that the currently authenticated
+// user is a member of.
+func (c *Client) GetAuthenticatedUserOrgs(ctx context.Context, token string) ([]*Org, error) {
+	if MockGetAuthenticatedUserOrgs != nil {
+		return MockGetAuthenticatedUserOrgs(ctx, token)
+	}
+
+	var orgs []*Org
+	err := c.requestGet(ctx, token, "/user/orgs?per_page=100", &orgs)
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
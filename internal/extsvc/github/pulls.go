@@ -79,6 +79,10 @@ type PullRequest struct {
 	TimelineItems []TimelineItem
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+	// BaseRefOid and HeadRefOid are the commits at the tips of the base and
+	// head branches of the PullRequest when it was last loaded.
+	BaseRefOid string
+	HeadRefOid string
 }
 
 // AssignedEvent represents an 'assigned' event on a PullRequest.
@@ -336,25 +340,28 @@ func (i *TimelineItem) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, i.Item)
 }
 
-// LoadPullRequests loads a list of PullRequests from Github.
-func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) error {
-	type repository struct {
-		Owner string
-		Name  string
-		PRs   map[string]*PullRequest
-	}
+// pullRequestsRepository groups the pull requests of a batched GraphQL query
+// by their repository, keyed by their GraphQL alias.
+type pullRequestsRepository struct {
+	Owner string
+	Name  string
+	PRs   map[string]*PullRequest
+}
 
-	labeled := map[string]*repository{}
+// labelPullRequests groups the given PullRequests by their repository, keyed
+// by the GraphQL aliases under which they are queried.
+func labelPullRequests(prs []*PullRequest) (map[string]*pullRequestsRepository, error) {
+	labeled := map[string]*pullRequestsRepository{}
 	for _, pr := range prs {
 		owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		repoLabel := owner + "_" + repo
 		r, ok := labeled[repoLabel]
 		if !ok {
-			r = &repository{
+			r = &pullRequestsRepository{
 				Owner: owner,
 				Name:  repo,
 				PRs:   map[string]*PullRequest{},
@@ -365,6 +372,81 @@ func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) erro
 		prLabel := repoLabel + "_" + strconv.FormatInt(pr.Number, 10)
 		r.PRs[prLabel] = pr
 	}
+	return labeled, nil
+}
+
+// writePullRequestsQuery writes the body of a query of the labeled pull
+// requests, each of which selects the "pr" fragment.
+func writePullRequestsQuery(q *strings.Builder, labeled map[string]*pullRequestsRepository) {
+	q.WriteString("query {\n")
+
+	for repoLabel, r := range labeled {
+		q.WriteString(fmt.Sprintf("%s: repository(owner: %q, name: %q) {\n",
+			repoLabel, r.Owner, r.Name))
+
+		for prLabel, pr := range r.PRs {
+			q.WriteString(fmt.Sprintf("%s: pullRequest(number: %d) { ...pr }\n",
+				prLabel, pr.Number,
+			))
+		}
+
+		q.WriteString("}\n")
+	}
+
+	q.WriteString("}")
+}
+
+// UpdatedPullRequests returns the given PullRequests that were updated on
+// GitHub since they were loaded, i.e. whose UpdatedAt is zero or differs from
+// the one GitHub reports. Only the updatedAt field of each pull request is
+// queried, which costs a fraction of the rate limit points of loading them
+// with LoadPullRequests, so callers that poll many pull requests should only
+// load the ones returned by this method.
+func (c *Client) UpdatedPullRequests(ctx context.Context, prs ...*PullRequest) ([]*PullRequest, error) {
+	var updated, loaded []*PullRequest
+	for _, pr := range prs {
+		if pr.UpdatedAt.IsZero() {
+			updated = append(updated, pr)
+		} else {
+			loaded = append(loaded, pr)
+		}
+	}
+
+	if len(loaded) == 0 {
+		return updated, nil
+	}
+
+	labeled, err := labelPullRequests(loaded)
+	if err != nil {
+		return nil, err
+	}
+
+	var q strings.Builder
+	q.WriteString("fragment pr on PullRequest { updatedAt }\n")
+	writePullRequestsQuery(&q, labeled)
+
+	var results map[string]map[string]*struct{ UpdatedAt time.Time }
+	if err := c.requestGraphQL(ctx, "", q.String(), nil, &results); err != nil {
+		return nil, err
+	}
+
+	for repoLabel, r := range labeled {
+		for prLabel, pr := range r.PRs {
+			if res := results[repoLabel][prLabel]; res == nil || !res.UpdatedAt.Equal(pr.UpdatedAt) {
+				updated = append(updated, pr)
+			}
+		}
+	}
+
+	return updated, nil
+}
+
+// LoadPullRequests loads a list of PullRequests from Github.
+func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) error {
+	labeled, err := labelPullRequests(prs)
+	if err != nil {
+		return err
+	}
 
 	var q strings.Builder
 	q.WriteString(`
@@ -390,6 +472,7 @@ func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) erro
     }
     fragment pr on PullRequest {
       id, title, body, state, url, number, createdAt, updatedAt
+      baseRefOid, headRefOid
       author { ...actor }
       participants(first: 100) { nodes { ...actor } }
       timelineItems(
@@ -494,23 +577,8 @@ func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) erro
         }
       }
     }
-    query {
-   `)
-
-	for repoLabel, r := range labeled {
-		q.WriteString(fmt.Sprintf("%s: repository(owner: %q, name: %q) {\n",
-			repoLabel, r.Owner, r.Name))
-
-		for prLabel, pr := range r.PRs {
-			q.WriteString(fmt.Sprintf("%s: pullRequest(number: %d) { ...pr }\n",
-				prLabel, pr.Number,
-			))
-		}
-
-		q.WriteString("}\n")
-	}
-
-	q.WriteString("}")
+    `)
+	writePullRequestsQuery(&q, labeled)
 
 	var results map[string]map[string]*struct {
 		PullRequest
@@ -518,7 +586,7 @@ func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) erro
 		TimelineItems struct{ Nodes []TimelineItem }
 	}
 
-	err := c.requestGraphQL(ctx, "", q.String(), nil, &results)
+	err = c.requestGraphQL(ctx, "", q.String(), nil, &results)
 	if err != nil {
 		return err
 	}
@@ -533,3 +601,58 @@ func (c *Client) LoadPullRequests(ctx context.Context, prs ...*PullRequest) erro
 
 	return nil
 }
+
+// Pull request review events accepted by SubmitPullRequestReview.
+const (
+	PullRequestReviewEventApprove        = "APPROVE"
+	PullRequestReviewEventRequestChanges = "REQUEST_CHANGES"
+	PullRequestReviewEventComment        = "COMMENT"
+)
+
+// CreatePullRequestComment adds a comment with the given body to the given
+// PullRequest, whose ID must be set.
+func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest, body string) error {
+	q := `
+    mutation($input: AddCommentInput!) {
+      addComment(input: $input) { subject { id } }
+    }`
+
+	input := map[string]interface{}{"input": map[string]interface{}{
+		"subjectId": pr.ID,
+		"body":      body,
+	}}
+
+	return c.requestGraphQL(ctx, "", q, input, nil)
+}
+
+// SubmitPullRequestReview submits a review of the given PullRequest, whose ID
+// must be set. The event is one of the PullRequestReviewEvent constants.
+func (c *Client) SubmitPullRequestReview(ctx context.Context, pr *PullRequest, event, body string) error {
+	q := `
+    mutation($input: AddPullRequestReviewInput!) {
+      addPullRequestReview(input: $input) { pullRequestReview { id } }
+    }`
+
+	input := map[string]interface{}{"input": map[string]interface{}{
+		"pullRequestId": pr.ID,
+		"event":         event,
+		"body":          body,
+	}}
+
+	return c.requestGraphQL(ctx, "", q, input, nil)
+}
+
+// ClosePullRequest closes the given PullRequest, whose ID must be set,
+// without merging it.
+func (c *Client) ClosePullRequest(ctx context.Context, pr *PullRequest) error {
+	q := `
+    mutation($input: ClosePullRequestInput!) {
+      closePullRequest(input: $input) { pullRequest { id } }
+    }`
+
+	input := map[string]interface{}{"input": map[string]interface{}{
+		"pullRequestId": pr.ID,
+	}}
+
+	return c.requestGraphQL(ctx, "", q, input, nil)
+}
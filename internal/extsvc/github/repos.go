@@ -26,15 +26,17 @@ func SplitRepositoryNameWithOwner(nameWithOwner string) (owner, repo string, err
 
 // Repository is a GitHub repository.
 type Repository struct {
-	ID               string // ID of repository (GitHub GraphQL ID, not GitHub database ID)
-	DatabaseID       int64  // The integer database id
-	NameWithOwner    string // full name of repository ("owner/name")
-	Description      string // description of repository
-	URL              string // the web URL of this repository ("https://github.com/foo/bar")
-	IsPrivate        bool   // whether the repository is private
-	IsFork           bool   // whether the repository is a fork of another repository
-	IsArchived       bool   // whether the repository is archived on the code host
-	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+	ID               string   // ID of repository (GitHub GraphQL ID, not GitHub database ID)
+	DatabaseID       int64    // The integer database id
+	NameWithOwner    string   // full name of repository ("owner/name")
+	Description      string   // description of repository
+	URL              string   // the web URL of this repository ("https://github.com/foo/bar")
+	IsPrivate        bool     // whether the repository is private
+	IsFork           bool     // whether the repository is a fork of another repository
+	IsArchived       bool     // whether the repository is archived on the code host
+	ViewerPermission string   // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+	Language         string   // the primary language of the repository, or empty if unknown
+	Topics           []string // the topics of the repository. Only the graphql repository listing populates this.
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -251,6 +253,7 @@ type restRepository struct {
 	Private     bool
 	Fork        bool
 	Archived    bool
+	Language    string
 	Permissions restRepositoryPermissions `json:"permissions"`
 }
 
@@ -283,6 +286,7 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsFork:           restRepo.Fork,
 		IsArchived:       restRepo.Archived,
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
+		Language:         restRepo.Language,
 	}
 }
 
@@ -507,6 +511,148 @@ func (c *Client) ListUserRepositories(ctx context.Context, user string, page int
 	return repos, len(repos) > 0, 1, err
 }
 
+// graphqlListedRepository is a repository returned by the GraphQL repository listing
+// queries, which also request the repository's primary language and topics.
+type graphqlListedRepository struct {
+	Repository
+	PrimaryLanguage *struct {
+		Name string
+	}
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string
+			}
+		}
+	}
+}
+
+// ListOrgRepositoriesGraphQL lists GitHub repositories from the specified organization with the
+// GraphQL API, which returns more repositories per request than the REST API. cursor is the
+// endCursor of the previous page, or empty for the first page. The returned endCursor is empty
+// if there are no more pages.
+func (c *Client) ListOrgRepositoriesGraphQL(ctx context.Context, org, cursor string) (repos []*Repository, endCursor string, rateLimitCost int, err error) {
+	return c.listRepositoriesGraphQL(ctx, `
+query OrgRepositories($login: String!, $cursor: String) {
+	owner: organization(login: $login) {
+		repositories(first: 100, after: $cursor, orderBy: {field: CREATED_AT, direction: DESC}) {
+			...RepositoryConnectionFields
+		}
+	}
+	rateLimit {
+		cost
+	}
+}`, map[string]interface{}{"login": org, "cursor": cursor})
+}
+
+// ListUserRepositoriesGraphQL lists GitHub repositories owned by the specified user with the
+// GraphQL API. See ListOrgRepositoriesGraphQL for the pagination.
+func (c *Client) ListUserRepositoriesGraphQL(ctx context.Context, user, cursor string) (repos []*Repository, endCursor string, rateLimitCost int, err error) {
+	return c.listRepositoriesGraphQL(ctx, `
+query UserRepositories($login: String!, $cursor: String) {
+	owner: user(login: $login) {
+		repositories(first: 100, after: $cursor, ownerAffiliations: [OWNER], orderBy: {field: CREATED_AT, direction: DESC}) {
+			...RepositoryConnectionFields
+		}
+	}
+	rateLimit {
+		cost
+	}
+}`, map[string]interface{}{"login": user, "cursor": cursor})
+}
+
+// ListAffiliatedRepositoriesGraphQL lists GitHub repositories affiliated with the client token
+// with the GraphQL API, like ListAffiliatedRepositories. See ListOrgRepositoriesGraphQL for the
+// pagination.
+func (c *Client) ListAffiliatedRepositoriesGraphQL(ctx context.Context, cursor string) (repos []*Repository, endCursor string, rateLimitCost int, err error) {
+	repos, endCursor, rateLimitCost, err = c.listRepositoriesGraphQL(ctx, `
+query AffiliatedRepositories($cursor: String) {
+	owner: viewer {
+		repositories(first: 100, after: $cursor, affiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER], orderBy: {field: CREATED_AT, direction: DESC}) {
+			...RepositoryConnectionFields
+		}
+	}
+	rateLimit {
+		cost
+	}
+}`, map[string]interface{}{"cursor": cursor})
+	if err == nil {
+		// 🚨 SECURITY: must forward token here to ensure caching by token
+		c.addRepositoriesToCache("", repos)
+	}
+	return repos, endCursor, rateLimitCost, err
+}
+
+// listRepositoriesGraphQL runs a GraphQL repository listing query, which must select the
+// repositories of an owner aliased as "owner" with the RepositoryConnectionFields fragment.
+func (c *Client) listRepositoriesGraphQL(ctx context.Context, query string, vars map[string]interface{}) (repos []*Repository, endCursor string, rateLimitCost int, err error) {
+	var result struct {
+		Owner *struct {
+			Repositories struct {
+				Nodes    []*graphqlListedRepository
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			}
+		}
+		RateLimit *struct {
+			Cost int
+		}
+	}
+	query += `
+fragment RepositoryConnectionFields on RepositoryConnection {
+	nodes {
+		...RepositoryFields
+		primaryLanguage {
+			name
+		}
+		repositoryTopics(first: 20) {
+			nodes {
+				topic {
+					name
+				}
+			}
+		}
+	}
+	pageInfo {
+		hasNextPage
+		endCursor
+	}
+}
+` + c.repositoryFieldsGraphQLFragment()
+	if err := c.requestGraphQL(ctx, "", query, vars, &result); err != nil {
+		return nil, "", 0, err
+	}
+	if result.Owner == nil {
+		return nil, "", 0, ErrNotFound
+	}
+
+	repos = make([]*Repository, 0, len(result.Owner.Repositories.Nodes))
+	for _, r := range result.Owner.Repositories.Nodes {
+		if r == nil {
+			continue
+		}
+		repo := r.Repository
+		if r.PrimaryLanguage != nil {
+			repo.Language = r.PrimaryLanguage.Name
+		}
+		for _, n := range r.RepositoryTopics.Nodes {
+			repo.Topics = append(repo.Topics, n.Topic.Name)
+		}
+		repos = append(repos, &repo)
+	}
+
+	if result.Owner.Repositories.PageInfo.HasNextPage {
+		endCursor = result.Owner.Repositories.PageInfo.EndCursor
+	}
+	rateLimitCost = 1
+	if result.RateLimit != nil {
+		rateLimitCost = result.RateLimit.Cost
+	}
+	return repos, endCursor, rateLimitCost, nil
+}
+
 type restSearchResponse struct {
 	TotalCount        int              `json:"total_count"`
 	IncompleteResults bool             `json:"incomplete_results"`
@@ -414,6 +414,81 @@ func TestClient_ListOrgRepositories(t *testing.T) {
 	}
 }
 
+func TestClient_ListOrgRepositoriesGraphQL(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `
+{
+  "data": {
+    "owner": {
+      "repositories": {
+        "nodes": [
+          {
+            "id": "i",
+            "nameWithOwner": "o/r",
+            "url": "https://github.example.com/o/r",
+            "isArchived": true,
+            "primaryLanguage": {"name": "Go"},
+            "repositoryTopics": {"nodes": [{"topic": {"name": "search"}}, {"topic": {"name": "code"}}]}
+          },
+          {
+            "id": "j",
+            "nameWithOwner": "o/b",
+            "url": "https://github.example.com/o/b",
+            "primaryLanguage": null,
+            "repositoryTopics": {"nodes": []}
+          }
+        ],
+        "pageInfo": {"hasNextPage": true, "endCursor": "c2"}
+      }
+    },
+    "rateLimit": {"cost": 2}
+  }
+}
+`}
+
+	c := newTestClient(t, &mock)
+	wantRepos := []*Repository{
+		{
+			ID:            "i",
+			NameWithOwner: "o/r",
+			URL:           "https://github.example.com/o/r",
+			IsArchived:    true,
+			Language:      "Go",
+			Topics:        []string{"search", "code"},
+		},
+		{
+			ID:            "j",
+			NameWithOwner: "o/b",
+			URL:           "https://github.example.com/o/b",
+		},
+	}
+
+	repos, endCursor, cost, err := c.ListOrgRepositoriesGraphQL(context.Background(), "o", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repoListsAreEqual(repos, wantRepos) {
+		t.Errorf("got repositories:\n%s\nwant:\n%s", stringForRepoList(repos), stringForRepoList(wantRepos))
+	}
+	if want := "c2"; endCursor != want {
+		t.Errorf("got endCursor %q, want %q", endCursor, want)
+	}
+	if want := 2; cost != want {
+		t.Errorf("got rateLimitCost %d, want %d", cost, want)
+	}
+}
+
+func TestClient_ListOrgRepositoriesGraphQL_notFound(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `{"data": {"owner": null}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to an Organization with the login of 'u'."}]}`,
+	}
+	c := newTestClient(t, &mock)
+
+	if _, _, _, err := c.ListOrgRepositoriesGraphQL(context.Background(), "u", ""); err == nil {
+		t.Error("expected error for missing organization")
+	}
+}
+
 func stringForRepoList(repos []*Repository) string {
 	repoStrings := []string{}
 	for _, repo := range repos {
@@ -427,7 +502,7 @@ func repoListsAreEqual(a []*Repository, b []*Repository) bool {
 		return false
 	}
 	for i := 0; i < len(a); i++ {
-		if *a[i] != *b[i] {
+		if !reflect.DeepEqual(a[i], b[i]) {
 			return false
 		}
 	}
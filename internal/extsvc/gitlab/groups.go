@@ -0,0 +1,40 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peterhellberg/link"
+)
+
+// Group is a GitLab group.
+type Group struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	FullPath string `json:"full_path"`
+	WebURL   string `json:"web_url"`
+}
+
+// ListGroups returns a page of groups from the GitLab API (e.g., "groups?min_access_level=10"),
+// and the URL of the next page, if any.
+func (c *Client) ListGroups(ctx context.Context, urlStr string) (groups []*Group, nextPageURL *string, err error) {
+	if MockListGroups != nil {
+		return MockListGroups(c, ctx, urlStr)
+	}
+
+	req, err := http.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	respHeader, err := c.do(ctx, req, &groups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get URL to next page. See https://docs.gitlab.com/ee/api/README.html#pagination-link-header.
+	if l := link.Parse(respHeader.Get("Link"))["next"]; l != nil {
+		nextPageURL = &l.URI
+	}
+
+	return groups, nextPageURL, nil
+}
@@ -16,3 +16,6 @@ var MockGetProject func(c *Client, ctx context.Context, op GetProjectOp) (*Proje
 
 // MockListTree, if non-nil, will be called instead of Client.ListTree
 var MockListTree func(c *Client, ctx context.Context, op ListTreeOp) ([]*Tree, error)
+
+// MockListGroups, if non-nil, will be called instead of Client.ListGroups
+var MockListGroups func(c *Client, ctx context.Context, urlStr string) (groups []*Group, nextPageURL *string, err error)
@@ -86,8 +86,8 @@ type Client struct {
 	UserAgent string
 }
 
-// addrForRepo returns the gitserver address to use for the given repo name.
-func (c *Client) addrForRepo(ctx context.Context, repo api.RepoName) string {
+// AddrForRepo returns the gitserver address to use for the given repo name.
+func (c *Client) AddrForRepo(ctx context.Context, repo api.RepoName) string {
 	repo = protocol.NormalizeRepo(repo) // in case the caller didn't already normalize it
 	return c.addrForKey(ctx, string(repo))
 }
@@ -151,7 +151,7 @@ func (c *Client) ArchiveURL(ctx context.Context, repo Repo, opt ArchiveOptions)
 
 	return &url.URL{
 		Scheme:   "http",
-		Host:     c.addrForRepo(ctx, repo.Name),
+		Host:     c.AddrForRepo(ctx, repo.Name),
 		Path:     "/archive",
 		RawQuery: q.Encode(),
 	}
@@ -358,6 +358,104 @@ func (c *Cmd) CombinedOutput(ctx context.Context) ([]byte, error) {
 
 func (c *Cmd) String() string { return fmt.Sprintf("%q", c.Args) }
 
+// BatchExec runs each of the commands in its repository. The commands of the
+// repositories on the same gitserver are run with a single request to it (see
+// protocol.BatchExecRequest), and each command may run for at most timeout.
+// Repositories that are not cloned are not cloned on demand.
+//
+// onResult is called exactly once for each command, with its index in cmds,
+// as soon as its result is received. If the command timed out or its output
+// was truncated, complete is false and stdout is the output it wrote until
+// then. onResult is called concurrently for the commands of different
+// gitservers.
+func (c *Client) BatchExec(ctx context.Context, cmds []*Cmd, timeout time.Duration, onResult func(i int, stdout []byte, complete bool, err error)) {
+	type shard struct {
+		req     protocol.BatchExecRequest
+		indexes []int // the index in cmds of each request
+	}
+	shards := make(map[string]*shard)
+	for i, cmd := range cmds {
+		repoName := protocol.NormalizeRepo(cmd.Repo.Name)
+		addr := c.AddrForRepo(ctx, repoName)
+		s := shards[addr]
+		if s == nil {
+			s = &shard{req: protocol.BatchExecRequest{Timeout: timeout}}
+			shards[addr] = s
+		}
+		s.req.Requests = append(s.req.Requests, protocol.ExecRequest{
+			Repo:           repoName,
+			URL:            cmd.Repo.URL,
+			EnsureRevision: cmd.EnsureRevision,
+			Args:           cmd.Args[1:],
+		})
+		s.indexes = append(s.indexes, i)
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range shards {
+		wg.Add(1)
+		go func(s *shard) {
+			defer wg.Done()
+
+			received := make([]bool, len(s.indexes))
+			err := c.batchExecShard(ctx, &s.req, func(res *protocol.BatchExecResult) {
+				if res.Index < 0 || res.Index >= len(received) || received[res.Index] {
+					return
+				}
+				received[res.Index] = true
+
+				i := s.indexes[res.Index]
+				cmds[i].ExitStatus = res.ExitStatus
+				var err error
+				if res.NotFound != nil {
+					err = &vcs.RepoNotExistError{Repo: s.req.Requests[res.Index].Repo, CloneInProgress: res.NotFound.CloneInProgress, CloneProgress: res.NotFound.CloneProgress}
+				} else if res.Complete {
+					err = execError(res.Error, strconv.Itoa(res.ExitStatus), res.Stderr)
+				}
+				onResult(i, res.Stdout, res.Complete, err)
+			})
+			if err == nil {
+				err = errors.New("gitserver: batch exec response is missing results")
+			}
+			for j, ok := range received {
+				if !ok {
+					onResult(s.indexes[j], nil, false, err)
+				}
+			}
+		}(s)
+	}
+	wg.Wait()
+}
+
+// batchExecShard sends the batch exec request to the gitserver of its
+// repositories, and calls onResult with each result it streams back.
+func (c *Client) batchExecShard(ctx context.Context, req *protocol.BatchExecRequest, onResult func(*protocol.BatchExecResult)) error {
+	if err := ctx.Err(); err != nil {
+		deadlineExceededCounter.Inc()
+		return err
+	}
+
+	resp, err := c.httpPost(ctx, req.Requests[0].Repo, "batch-exec", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var res protocol.BatchExecResult
+		if err := dec.Decode(&res); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		onResult(&res)
+	}
+}
+
 // StdoutReader returns an io.ReadCloser of stdout of c. If the command has a
 // non-zero return value, Read returns a non io.EOF error. Do not pass in a
 // started command.
@@ -381,20 +479,28 @@ type cmdReader struct {
 func (c *cmdReader) Read(p []byte) (int, error) {
 	n, err := c.rc.Read(p)
 	if err == io.EOF {
-		stderr := c.trailer.Get("X-Exec-Stderr")
-		if len(stderr) > 100 {
-			stderr = stderr[:100] + "... (truncated)"
-		}
-		if errorMsg := c.trailer.Get("X-Exec-Error"); errorMsg != "" {
-			return 0, fmt.Errorf("%s (stderr: %q)", errorMsg, stderr)
-		}
-		if exitStatus := c.trailer.Get("X-Exec-Exit-Status"); exitStatus != "0" {
-			return 0, fmt.Errorf("non-zero exit status: %s (stderr: %q)", exitStatus, stderr)
+		if err := execError(c.trailer.Get("X-Exec-Error"), c.trailer.Get("X-Exec-Exit-Status"), c.trailer.Get("X-Exec-Stderr")); err != nil {
+			return 0, err
 		}
 	}
 	return n, err
 }
 
+// execError returns the error of a command that finished with the given
+// error message, exit status and stderr, or nil if it succeeded.
+func execError(errorMsg, exitStatus, stderr string) error {
+	if len(stderr) > 100 {
+		stderr = stderr[:100] + "... (truncated)"
+	}
+	if errorMsg != "" {
+		return fmt.Errorf("%s (stderr: %q)", errorMsg, stderr)
+	}
+	if exitStatus != "0" {
+		return fmt.Errorf("non-zero exit status: %s (stderr: %q)", exitStatus, stderr)
+	}
+	return nil
+}
+
 func (c *cmdReader) Close() error {
 	return c.rc.Close()
 }
@@ -414,6 +520,15 @@ func (c *Client) WaitForGitServers(ctx context.Context) error {
 	}
 }
 
+// Ping returns an error if any of the gitservers is not reachable. Unlike
+// WaitForGitServers, it doesn't retry.
+func (c *Client) Ping(ctx context.Context) error {
+	if errs := c.pingAll(ctx); len(errs) > 0 {
+		return &multierror.Error{Errors: errs}
+	}
+	return nil
+}
+
 func (c *Client) pingAll(ctx context.Context) []error {
 	addrs := c.Addrs(ctx)
 
@@ -498,6 +613,56 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 	return repos, err
 }
 
+// DiskInfo returns the disk usage of every gitserver, keyed by address. If
+// some gitservers could not be reached, the disk usage of the others is
+// returned along with an error.
+func (c *Client) DiskInfo(ctx context.Context) (map[string]*protocol.DiskInfo, error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		err   error
+		infos = make(map[string]*protocol.DiskInfo)
+	)
+	for _, addr := range c.Addrs(ctx) {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			info, e := c.diskInfoOne(ctx, addr)
+			mu.Lock()
+			if e != nil {
+				err = e
+			} else {
+				infos[addr] = info
+			}
+			mu.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+	return infos, err
+}
+
+func (c *Client) diskInfoOne(ctx context.Context, addr string) (*protocol.DiskInfo, error) {
+	req, err := http.NewRequest("GET", "http://"+addr+"/disk-info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return nil, fmt.Errorf("DiskInfo: http status %d from %s: %s", resp.StatusCode, addr, body)
+	}
+
+	var info protocol.DiskInfo
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return &info, err
+}
+
 // GetGitolitePhabricatorMetadata returns Phabricator metadata for a Gitolite repository fetched via
 // a user-provided command.
 func (c *Client) GetGitolitePhabricatorMetadata(ctx context.Context, gitoliteHost string, repoName api.RepoName) (*protocol.GitolitePhabricatorMetadataResponse, error) {
@@ -652,6 +817,34 @@ func (c *Client) IsRepoCloned(ctx context.Context, repo api.RepoName) (bool, err
 	return cloned, nil
 }
 
+// Submodules returns the submodules declared in the .gitmodules file of the
+// repository's default branch. A repository that is not cloned has no
+// submodules.
+func (c *Client) Submodules(ctx context.Context, repo api.RepoName) ([]protocol.Submodule, error) {
+	req := &protocol.SubmodulesRequest{
+		Repo: repo,
+	}
+	resp, err := c.httpPost(ctx, repo, "submodules", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "Submodules",
+			Err: errors.Errorf("Submodules: http status %d", resp.StatusCode),
+		}
+	}
+
+	var res protocol.SubmodulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Submodules, nil
+}
+
 // RepoInfo retrieves information about one or more repositories on gitserver.
 //
 // The repository not existing is not an error; in that case, RepoInfoResponse.Results[i].Cloned
@@ -664,7 +857,7 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	shards := make(map[string]*protocol.RepoInfoRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
 	for _, r := range repos {
-		addr := c.addrForRepo(ctx, r)
+		addr := c.AddrForRepo(ctx, r)
 		shard := shards[addr]
 
 		if shard == nil {
@@ -771,7 +964,7 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 
 	uri := op
 	if !strings.HasPrefix(op, "http") {
-		uri = "http://" + c.addrForRepo(ctx, repo) + "/" + op
+		uri = "http://" + c.AddrForRepo(ctx, repo) + "/" + op
 	}
 
 	req, err := http.NewRequest(method, uri, bytes.NewReader(reqBody))
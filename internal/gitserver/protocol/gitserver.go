@@ -20,6 +20,41 @@ type ExecRequest struct {
 	Opt            *RemoteOpts `json:"opt"`
 }
 
+// BatchExecRequest is a request to execute a command inside each of several
+// git repositories on the same gitserver, so that operations over many
+// repositories (such as commit searches) don't need a request per
+// repository. Repositories that are not cloned are not cloned on demand.
+type BatchExecRequest struct {
+	Requests []ExecRequest `json:"requests"`
+
+	// Timeout is how long each command may run. A command that times out is
+	// killed, and the output it wrote until then is returned as incomplete.
+	Timeout time.Duration `json:"timeout"`
+}
+
+// BatchExecResult is the result of a command of a BatchExecRequest. The
+// response to a BatchExecRequest is a stream of JSON-encoded results, in the
+// order that the commands finished.
+type BatchExecResult struct {
+	// Index is the index of the command in BatchExecRequest.Requests.
+	Index int `json:"index"`
+
+	Stdout []byte `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"` // truncated to 1024 bytes
+
+	// Complete is false if the command timed out or its output was
+	// truncated, in which case Stdout is the output it wrote until then.
+	Complete bool `json:"complete"`
+	// Truncated is true if the command was killed because its output
+	// exceeded the maximum size that gitserver buffers per command.
+	Truncated  bool   `json:"truncated,omitempty"`
+	ExitStatus int    `json:"exitStatus"`
+	Error      string `json:"error,omitempty"`
+
+	// NotFound is set if the repository is not cloned.
+	NotFound *NotFoundPayload `json:"notFound,omitempty"`
+}
+
 // RemoteOpts configures interactions with a remote repository.
 type RemoteOpts struct {
 	SSH   *SSHConfig   `json:"ssh"`   // SSH configuration for communication with the remote
@@ -127,6 +162,56 @@ type RepoInfoResponse struct {
 	Results map[api.RepoName]*RepoInfo
 }
 
+// SubmodulesRequest is a request for the submodules of a repository on
+// gitserver, as declared in the .gitmodules file of its default branch.
+type SubmodulesRequest struct {
+	// Repo is the repository whose submodules are returned.
+	Repo api.RepoName
+}
+
+// SubmodulesResponse is the response to a SubmodulesRequest. If the
+// repository is not cloned or has no .gitmodules file, Submodules is empty.
+type SubmodulesResponse struct {
+	Submodules []Submodule
+}
+
+// Submodule is a submodule declared in a .gitmodules file.
+type Submodule struct {
+	Name string // the submodule's name (the "submodule.<name>" section)
+	Path string // the path of the submodule in the superproject
+	URL  string // the submodule's URL, possibly relative to the superproject's URL
+}
+
+// DiskInfo is the response to a request for the disk usage of a gitserver
+// shard.
+type DiskInfo struct {
+	// FreeBytes is the number of bytes free on the disk holding the repos.
+	FreeBytes uint64
+	// TotalBytes is the size of the disk holding the repos.
+	TotalBytes uint64
+	// DesiredPercentFree is the percentage of disk space the gitserver tries
+	// to keep free by evicting repos.
+	DesiredPercentFree int
+	// RepoCount is the number of repos cloned on the shard.
+	RepoCount int
+	// LargestRepos are the largest repos cloned on the shard, largest first.
+	LargestRepos []RepoSize
+	// ComputedAt is when the repo sizes were last computed.
+	ComputedAt time.Time
+}
+
+// NearlyFull returns true if less disk space than DesiredPercentFree is
+// available, meaning that the gitserver is evicting repos to free up space.
+func (d *DiskInfo) NearlyFull() bool {
+	return float64(d.FreeBytes) < float64(d.DesiredPercentFree)/100*float64(d.TotalBytes)
+}
+
+// RepoSize is the disk usage of a single repository.
+type RepoSize struct {
+	Repo  api.RepoName
+	Bytes int64
+}
+
 // CreateCommitFromPatchRequest is the request information needed for creating
 // the simulated staging area git object for a repo.
 type CreateCommitFromPatchRequest struct {
@@ -65,6 +65,11 @@ type Params struct {
 
 	// Metadata provides optional metadata about the code we're highlighting.
 	Metadata Metadata
+
+	// FirstLine is the line number of the first line of Content in its file,
+	// used to number the lines of the returned table when Content is only a
+	// range of lines of the file. It defaults to 1.
+	FirstLine int
 }
 
 // Metadata contains metadata about a request to highlight code. It is used to
@@ -114,6 +119,11 @@ func Code(ctx context.Context, p Params) (h template.HTML, aborted bool, err err
 	}
 	code := string(p.Content)
 
+	firstLine := p.FirstLine
+	if firstLine < 1 {
+		firstLine = 1
+	}
+
 	themechoice := "Sourcegraph"
 	if p.IsLightTheme {
 		themechoice = "Sourcegraph (light)"
@@ -166,7 +176,7 @@ func Code(ctx context.Context, p Params) (h template.HTML, aborted bool, err err
 		prometheusStatus = "timeout"
 
 		// Timeout, so render plain table.
-		table, err2 := generatePlainTable(code)
+		table, err2 := generatePlainTable(code, firstLine)
 		return table, true, err2
 	} else if err != nil {
 		log15.Error(
@@ -194,13 +204,13 @@ func Code(ctx context.Context, p Params) (h template.HTML, aborted bool, err err
 			// user an error.
 			tr.LogFields(otlog.Bool(problem, true))
 			prometheusStatus = problem
-			table, err2 := generatePlainTable(code)
+			table, err2 := generatePlainTable(code, firstLine)
 			return table, false, err2
 		}
 		return "", false, err
 	}
 	// Note: resp.Data is properly HTML escaped by syntect_server
-	table, err := preSpansToTable(resp.Data)
+	table, err := preSpansToTable(resp.Data, firstLine)
 	if err != nil {
 		return "", false, err
 	}
@@ -246,7 +256,7 @@ func firstCharacters(s string, n int) string {
 // 	</tr>
 // 	</table>
 //
-func preSpansToTable(h string) (string, error) {
+func preSpansToTable(h string, firstLine int) (string, error) {
 	doc, err := html.Parse(strings.NewReader(h))
 	if err != nil {
 		return "", err
@@ -284,7 +294,7 @@ func preSpansToTable(h string) (string, error) {
 
 		tdLineNumber := &html.Node{Type: html.ElementNode, DataAtom: atom.Td, Data: atom.Td.String()}
 		tdLineNumber.Attr = append(tdLineNumber.Attr, html.Attribute{Key: "class", Val: "line"})
-		tdLineNumber.Attr = append(tdLineNumber.Attr, html.Attribute{Key: "data-line", Val: fmt.Sprint(rows)})
+		tdLineNumber.Attr = append(tdLineNumber.Attr, html.Attribute{Key: "data-line", Val: fmt.Sprint(firstLine - 1 + rows)})
 		tr.AppendChild(tdLineNumber)
 		codeTd := &html.Node{Type: html.ElementNode, DataAtom: atom.Td, Data: atom.Td.String()}
 		tr.AppendChild(codeTd)
@@ -340,7 +350,7 @@ func preSpansToTable(h string) (string, error) {
 	return buf.String(), nil
 }
 
-func generatePlainTable(code string) (template.HTML, error) {
+func generatePlainTable(code string, firstLine int) (template.HTML, error) {
 	table := &html.Node{Type: html.ElementNode, DataAtom: atom.Table, Data: atom.Table.String()}
 	for row, line := range strings.Split(code, "\n") {
 		line = strings.TrimSuffix(line, "\r") // CRLF files
@@ -352,7 +362,7 @@ func generatePlainTable(code string) (template.HTML, error) {
 
 		tdLineNumber := &html.Node{Type: html.ElementNode, DataAtom: atom.Td, Data: atom.Td.String()}
 		tdLineNumber.Attr = append(tdLineNumber.Attr, html.Attribute{Key: "class", Val: "line"})
-		tdLineNumber.Attr = append(tdLineNumber.Attr, html.Attribute{Key: "data-line", Val: fmt.Sprint(row + 1)})
+		tdLineNumber.Attr = append(tdLineNumber.Attr, html.Attribute{Key: "data-line", Val: fmt.Sprint(firstLine + row)})
 		tr.AppendChild(tdLineNumber)
 
 		codeCell := &html.Node{Type: html.ElementNode, DataAtom: atom.Td, Data: atom.Td.String()}
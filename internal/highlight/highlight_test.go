@@ -12,7 +12,7 @@ func TestPreSpansToTable_Simple(t *testing.T) {
 
 `
 	want := `<table><tr><td class="line" data-line="1"></td><td class="code"><div><span>package</span></div></td></tr><tr><td class="line" data-line="2"></td><td class="code"><div></div></td></tr></table>`
-	got, err := preSpansToTable(input)
+	got, err := preSpansToTable(input, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -43,7 +43,7 @@ func TestPreSpansToTable_Complex(t *testing.T) {
 </span></div></td></tr><tr><td class="line" data-line="7"></td><td class="code"><div><span style="color:#323232;">
 </span></div></td></tr><tr><td class="line" data-line="8"></td><td class="code"><div><span style="color:#323232;">
 </span></div></td></tr><tr><td class="line" data-line="9"></td><td class="code"><div></div></td></tr></table>`
-	got, err := preSpansToTable(input)
+	got, err := preSpansToTable(input, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -60,7 +60,20 @@ line 2
 	want := template.HTML(`<table><tr><td class="line" data-line="1"></td><td class="code"><span>line 1</span></td></tr><tr><td class="line" data-line="2"></td><td class="code"><span>line 2</span></td></tr><tr><td class="line" data-line="3"></td><td class="code"><span>
 </span></td></tr><tr><td class="line" data-line="4"></td><td class="code"><span>
 </span></td></tr></table>`)
-	got, err := generatePlainTable(input)
+	got, err := generatePlainTable(input, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("\ngot:\n%s\nwant:\n%s\n", got, want)
+	}
+}
+
+func TestGeneratePlainTableFirstLine(t *testing.T) {
+	input := `line 41
+line 42`
+	want := template.HTML(`<table><tr><td class="line" data-line="41"></td><td class="code"><span>line 41</span></td></tr><tr><td class="line" data-line="42"></td><td class="code"><span>line 42</span></td></tr></table>`)
+	got, err := generatePlainTable(input, 41)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -77,7 +90,7 @@ func TestGeneratePlainTableSecurity(t *testing.T) {
 	want := template.HTML(`<table><tr><td class="line" data-line="1"></td><td class="code"><span>&lt;strong&gt;line 1&lt;/strong&gt;</span></td></tr><tr><td class="line" data-line="2"></td><td class="code"><span>&lt;script&gt;alert(&#34;line 2&#34;)&lt;/script&gt;</span></td></tr><tr><td class="line" data-line="3"></td><td class="code"><span>
 </span></td></tr><tr><td class="line" data-line="4"></td><td class="code"><span>
 </span></td></tr></table>`)
-	got, err := generatePlainTable(input)
+	got, err := generatePlainTable(input, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -93,7 +106,7 @@ func TestIssue6892(t *testing.T) {
 </pre>`
 	want := `<table><tr><td class="line" data-line="1"></td><td class="code"><div><span>
 </span></div></td></tr><tr><td class="line" data-line="2"></td><td class="code"><div><span style="color:#9b9b9b;">import</span></div></td></tr><tr><td class="line" data-line="3"></td><td class="code"><div></div></td></tr></table>`
-	got, err := preSpansToTable(input)
+	got, err := preSpansToTable(input, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
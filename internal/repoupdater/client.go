@@ -150,13 +150,31 @@ func (c *Client) EnqueueRepoUpdate(ctx context.Context, repo gitserver.Repo) (*p
 	if MockEnqueueRepoUpdate != nil {
 		return MockEnqueueRepoUpdate(ctx, repo)
 	}
+	return c.enqueueRepoUpdate(ctx, "enqueue-repo-update", repo)
+}
+
+// MockEnqueuePriorityRepoUpdate mocks (*Client).EnqueuePriorityRepoUpdate for
+// tests.
+var MockEnqueuePriorityRepoUpdate func(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error)
+
+// EnqueuePriorityRepoUpdate is like EnqueueRepoUpdate, but the update jumps
+// ahead of the updates that repo-updater schedules and the ones requested with
+// EnqueueRepoUpdate. It should only be used for updates that a user explicitly
+// requested, e.g. by refreshing a repository in the UI.
+func (c *Client) EnqueuePriorityRepoUpdate(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
+	if MockEnqueuePriorityRepoUpdate != nil {
+		return MockEnqueuePriorityRepoUpdate(ctx, repo)
+	}
+	return c.enqueueRepoUpdate(ctx, "enqueue-priority-update", repo)
+}
 
+func (c *Client) enqueueRepoUpdate(ctx context.Context, method string, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
 	req := &protocol.RepoUpdateRequest{
 		Repo: repo.Name,
 		URL:  repo.URL,
 	}
 
-	resp, err := c.httpPost(ctx, "enqueue-repo-update", req)
+	resp, err := c.httpPost(ctx, method, req)
 	if err != nil {
 		return nil, err
 	}
@@ -19,6 +19,12 @@ type RepoUpdateSchedulerInfoArgs struct {
 type RepoUpdateSchedulerInfoResult struct {
 	Schedule *RepoScheduleState `json:",omitempty"`
 	Queue    *RepoQueueState    `json:",omitempty"`
+
+	// Submodules and Supermodules are the IDs of the repos that are submodules
+	// of the repo, and of which the repo is a submodule. The scheduler fetches
+	// a repo's submodules before or together with the repo.
+	Submodules   []uint32 `json:",omitempty"`
+	Supermodules []uint32 `json:",omitempty"`
 }
 
 type RepoScheduleState struct {
@@ -187,10 +193,16 @@ type SyncError struct {
 	Message string
 }
 
+type UpdateQueueBacklog struct {
+	Message string
+	Count   int
+}
+
 type StatusMessage struct {
 	Cloning                  *CloningProgress          `json:"cloning"`
 	ExternalServiceSyncError *ExternalServiceSyncError `json:"external_service_sync_error"`
 	SyncError                *SyncError                `json:"sync_error"`
+	UpdateQueueBacklog       *UpdateQueueBacklog       `json:"update_queue_backlog"`
 }
 
 type StatusMessagesResponse struct {
@@ -0,0 +1,179 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// searchCacheTTL is how long the response of a search is served from the
+// cache. A search request often runs the same Zoekt query several times in
+// quick succession (e.g. for the suggestions, results and stats of the same
+// user input), so a short TTL suffices.
+const searchCacheTTL = 10 * time.Second
+
+// searchCacheSize is the maximum number of cached responses.
+const searchCacheSize = 100
+
+var searchCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "zoekt",
+	Name:      "search_cache_requests_total",
+	Help:      "Zoekt searches by whether their response was served from the cache.",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(searchCacheRequests)
+}
+
+// searchCache caches the responses of identical Zoekt searches for a short
+// time.
+type searchCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*searchCacheEntry
+}
+
+type searchCacheEntry struct {
+	resp    *zoekt.SearchResult
+	expires time.Time
+}
+
+// searchCacheNow is mocked in tests.
+var searchCacheNow = time.Now
+
+func (c *searchCache) get(key [sha256.Size]byte) *zoekt.SearchResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !searchCacheNow().Before(e.expires) {
+		return nil
+	}
+
+	// Callers may modify the fields of the response (such as truncating
+	// Files), so each gets its own copy.
+	resp := *e.resp
+	return &resp
+}
+
+func (c *searchCache) set(key [sha256.Size]byte, resp *zoekt.SearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := searchCacheNow()
+	if c.entries == nil {
+		c.entries = make(map[[sha256.Size]byte]*searchCacheEntry)
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= searchCacheSize {
+		// Evict the expired entries, or else the one that expires first.
+		var oldest [sha256.Size]byte
+		var oldestExpires time.Time
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			} else if oldestExpires.IsZero() || e.expires.Before(oldestExpires) {
+				oldest, oldestExpires = k, e.expires
+			}
+		}
+		if len(c.entries) >= searchCacheSize {
+			delete(c.entries, oldest)
+		}
+	}
+
+	resp2 := *resp
+	c.entries[key] = &searchCacheEntry{resp: &resp2, expires: now.Add(searchCacheTTL)}
+}
+
+// Search runs the query on Client, serving the responses of identical
+// searches (with the same query, repositories and options) from a cache
+// for a short time, unless DisableCache is set.
+func (c *Zoekt) Search(ctx context.Context, q zoektquery.Q, opts *zoekt.SearchOptions) (*zoekt.SearchResult, error) {
+	if c.DisableCache {
+		return c.Client.Search(ctx, q, opts)
+	}
+
+	key := searchCacheKey(q, opts)
+	if resp := c.searchCache.get(key); resp != nil {
+		searchCacheRequests.WithLabelValues("hit").Inc()
+		return resp, nil
+	}
+	searchCacheRequests.WithLabelValues("miss").Inc()
+
+	resp, err := c.Client.Search(ctx, q, opts)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() == nil {
+		// The response of a canceled search may be incomplete.
+		c.searchCache.set(key, resp)
+	}
+	return resp, nil
+}
+
+// List runs the query on Client. It is not cached, unlike ListAll.
+func (c *Zoekt) List(ctx context.Context, q zoektquery.Q) (*zoekt.RepoList, error) {
+	return c.Client.List(ctx, q)
+}
+
+// searchCacheKey returns the key of the search of the simplified query
+// with the given options.
+func searchCacheKey(q zoektquery.Q, opts *zoekt.SearchOptions) [sha256.Size]byte {
+	h := sha256.New()
+	writeQueryKey(h, zoektquery.Simplify(q))
+	if opts != nil {
+		fmt.Fprintf(h, "\n%+v", *opts)
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// writeQueryKey writes a canonical representation of the query to w. Unlike
+// q.String(), it includes all the repositories of repo sets, in order.
+func writeQueryKey(w io.Writer, q zoektquery.Q) {
+	switch q := q.(type) {
+	case *zoektquery.And:
+		writeQueryListKey(w, "and", q.Children)
+	case *zoektquery.Or:
+		writeQueryListKey(w, "or", q.Children)
+	case *zoektquery.Not:
+		io.WriteString(w, "(not ")
+		writeQueryKey(w, q.Child)
+		io.WriteString(w, ")")
+	case *zoektquery.Type:
+		fmt.Fprintf(w, "(type %d ", q.Type)
+		writeQueryKey(w, q.Child)
+		io.WriteString(w, ")")
+	case *zoektquery.RepoSet:
+		repos := make([]string, 0, len(q.Set))
+		for repo, ok := range q.Set {
+			if ok {
+				repos = append(repos, repo)
+			}
+		}
+		sort.Strings(repos)
+		fmt.Fprintf(w, "(reposet %s)", strings.Join(repos, " "))
+	default:
+		io.WriteString(w, q.String())
+	}
+}
+
+func writeQueryListKey(w io.Writer, op string, children []zoektquery.Q) {
+	io.WriteString(w, "("+op)
+	for _, c := range children {
+		io.WriteString(w, " ")
+		writeQueryKey(w, c)
+	}
+	io.WriteString(w, ")")
+}
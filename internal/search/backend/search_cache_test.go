@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+)
+
+type countingSearcher struct {
+	zoekt.Searcher
+	searches int
+}
+
+func (s *countingSearcher) Search(ctx context.Context, q zoektquery.Q, opts *zoekt.SearchOptions) (*zoekt.SearchResult, error) {
+	s.searches++
+	return &zoekt.SearchResult{Files: []zoekt.FileMatch{{FileName: "a"}, {FileName: "b"}}}, nil
+}
+
+func TestZoekt_Search_cache(t *testing.T) {
+	now := time.Now()
+	searchCacheNow = func() time.Time { return now }
+	defer func() { searchCacheNow = time.Now }()
+
+	searcher := &countingSearcher{}
+	z := &Zoekt{Client: searcher}
+	ctx := context.Background()
+
+	query := func(repos ...string) zoektquery.Q {
+		set := map[string]bool{}
+		for _, r := range repos {
+			set[r] = true
+		}
+		return zoektquery.NewAnd(&zoektquery.RepoSet{Set: set}, &zoektquery.Substring{Pattern: "foo"})
+	}
+	opts := &zoekt.SearchOptions{MaxWallTime: time.Second}
+
+	search := func(q zoektquery.Q, opts *zoekt.SearchOptions, wantSearches int) {
+		t.Helper()
+		resp, err := z.Search(ctx, q, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Files) != 2 {
+			t.Errorf("got %d files, want 2", len(resp.Files))
+		}
+		// Callers may truncate the files of the response.
+		resp.Files = resp.Files[:1]
+		if searcher.searches != wantSearches {
+			t.Errorf("got %d searches, want %d", searcher.searches, wantSearches)
+		}
+	}
+
+	search(query("a", "b"), opts, 1)
+	search(query("b", "a"), opts, 1)
+	search(query("a", "c"), opts, 2)
+	search(query("a", "b"), &zoekt.SearchOptions{MaxWallTime: 2 * time.Second}, 3)
+
+	now = now.Add(searchCacheTTL)
+	search(query("a", "b"), opts, 4)
+
+	z.DisableCache = true
+	search(query("a", "b"), opts, 5)
+}
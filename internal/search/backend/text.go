@@ -19,10 +19,12 @@ import (
 type Zoekt struct {
 	Client zoekt.Searcher
 
-	// DisableCache when true prevents caching of Client.List. Useful in
-	// tests.
+	// DisableCache when true prevents caching of Client.List and
+	// Client.Search. Useful in tests.
 	DisableCache bool
 
+	searchCache searchCache
+
 	mu       sync.RWMutex
 	state    int32 // 0 not running, 1 running, 2 stopped
 	set      map[string]*zoekt.Repository
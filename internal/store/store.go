@@ -171,6 +171,16 @@ func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.
 	}
 }
 
+// Demote makes the archive at path, as returned by PrepareZip, the first to be
+// evicted from the cache, unless it is prepared again before. It is a hint
+// that the archive is unlikely to be searched again.
+func (s *Store) Demote(path string) error {
+	// Ensure we have initialized
+	s.Start()
+
+	return s.cache.Demote(path)
+}
+
 // fetch fetches an archive from the network and stores it on disk. It does
 // not populate the in-memory cache. You should probably be calling
 // prepareZip.
@@ -0,0 +1,115 @@
+package trace
+
+import (
+	"context"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var slowThreshold = mustParseDuration(env.Get("SRC_SLOW_OPERATION_THRESHOLD", "1s", "Operations that take at least this long are listed with their traces on the slow operations debug page."))
+
+func mustParseDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		log.Fatalf("failed to parse duration %q: %s", s, err)
+	}
+	return d
+}
+
+// maxExemplars is the number of most recent exemplars that are kept.
+const maxExemplars = 200
+
+// An Exemplar is a slow observation of an operation that is also recorded in
+// Prometheus metrics, with a link to its trace. Exemplars make it possible to
+// go from a latency alert to the traces of the operations behind it.
+type Exemplar struct {
+	Operation string
+	Duration  time.Duration
+	Time      time.Time
+	TraceURL  string
+	Error     string
+}
+
+var exemplars struct {
+	mu   sync.Mutex
+	all  []Exemplar
+	next int
+}
+
+// SpanURLFromContext returns the URL to the tracing UI for the span in the
+// context, or the empty string if the context has no span. It is meant to be
+// included in structured logs.
+func SpanURLFromContext(ctx context.Context) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	return SpanURL(span)
+}
+
+// ObserveExemplar records the observation of an operation that took d as an
+// exemplar if it was slow, along with the trace of the span in the context.
+func ObserveExemplar(ctx context.Context, operation string, d time.Duration, err error) {
+	if d < slowThreshold {
+		return
+	}
+
+	e := Exemplar{
+		Operation: operation,
+		Duration:  d,
+		Time:      time.Now(),
+		TraceURL:  SpanURLFromContext(ctx),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	exemplars.mu.Lock()
+	defer exemplars.mu.Unlock()
+	if len(exemplars.all) < maxExemplars {
+		exemplars.all = append(exemplars.all, e)
+	} else {
+		exemplars.all[exemplars.next] = e
+	}
+	exemplars.next = (exemplars.next + 1) % maxExemplars
+}
+
+// Exemplars returns the most recent exemplars, slowest first.
+func Exemplars() []Exemplar {
+	exemplars.mu.Lock()
+	all := append([]Exemplar(nil), exemplars.all...)
+	exemplars.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Duration > all[j].Duration })
+	return all
+}
+
+var exemplarsTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<title>Slow operations</title>
+<p>The slowest of the {{.Max}} most recent operations that took at least {{.Threshold}}.</p>
+<table>
+<tr><th>Operation</th><th>Duration</th><th>Time</th><th>Trace</th><th>Error</th></tr>
+{{range .Exemplars}}<tr><td>{{.Operation}}</td><td>{{.Duration}}</td><td>{{.Time.Format "2006-01-02 15:04:05"}}</td><td><a href="{{.TraceURL}}">{{.TraceURL}}</a></td><td>{{.Error}}</td></tr>
+{{end}}</table>
+`))
+
+// ExemplarsHandler serves a page listing the most recent exemplars, slowest
+// first, with links to their traces.
+func ExemplarsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := exemplarsTemplate.Execute(w, map[string]interface{}{
+		"Max":       maxExemplars,
+		"Threshold": slowThreshold,
+		"Exemplars": Exemplars(),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
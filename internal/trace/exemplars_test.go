@@ -0,0 +1,33 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestObserveExemplar(t *testing.T) {
+	defer func(d time.Duration) { slowThreshold = d }(slowThreshold)
+	slowThreshold = time.Second
+
+	ctx := context.Background()
+	ObserveExemplar(ctx, "fast", time.Millisecond, nil)
+	for i := 0; i < maxExemplars; i++ {
+		ObserveExemplar(ctx, "slow", time.Second, nil)
+	}
+	ObserveExemplar(ctx, "slowest", time.Minute, errors.New("boom"))
+
+	got := Exemplars()
+	if len(got) != maxExemplars {
+		t.Fatalf("got %d exemplars, want %d", len(got), maxExemplars)
+	}
+	if got[0].Operation != "slowest" || got[0].Error != "boom" {
+		t.Errorf("got slowest exemplar %+v, want operation slowest with error boom", got[0])
+	}
+	for _, e := range got {
+		if e.Operation == "fast" {
+			t.Errorf("got exemplar for fast operation")
+		}
+	}
+}
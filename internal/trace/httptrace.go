@@ -29,6 +29,7 @@ type key int
 const (
 	routeNameKey key = iota
 	userKey      key = iota
+	samplingKey  key = iota
 )
 
 var metricLabels = []string{"route", "method", "code", "repo"}
@@ -95,12 +96,12 @@ func Middleware(next http.Handler) http.Handler {
 
 		// start new span
 		span := opentracing.StartSpan("", ext.RPCServerOption(wireContext))
-		ext.HTTPUrl.Set(span, r.URL.String())
-		ext.HTTPMethod.Set(span, r.Method)
-		span.SetTag("http.referer", r.Header.Get("referer"))
+		sampling := newRequestSampling(span, r, wireContext != nil)
+		setRequestTags(span, r)
 		defer span.Finish()
 		rw.Header().Set("X-Trace", SpanURL(span))
 		ctx = opentracing.ContextWithSpan(ctx, span)
+		ctx = context.WithValue(ctx, samplingKey, sampling)
 
 		routeName := "unknown"
 		ctx = context.WithValue(ctx, routeNameKey, &routeName)
@@ -111,14 +112,11 @@ func Middleware(next http.Handler) http.Handler {
 		m := httpsnoop.CaptureMetrics(next, rw, r.WithContext(ctx))
 
 		if routeName == "graphql" {
-			// We use the query to denote the type of a GraphQL request, e.g. /.api/graphql?Repositories
-			if r.URL.RawQuery != "" {
-				routeName = "graphql: " + r.URL.RawQuery
-			} else {
-				routeName = "graphql: unknown"
-			}
+			routeName = graphQLRouteName(r)
 		}
 
+		sampling.finish(routeName, userID, m.Duration)
+
 		// route name is only known after the request has been handled
 		span.SetOperationName("Serve: " + routeName)
 		span.SetTag("Route", routeName)
@@ -162,17 +160,40 @@ func Middleware(next http.Handler) http.Handler {
 	}))
 }
 
+// graphQLRouteName returns the route name of a GraphQL request. We use the
+// query to denote the type of a GraphQL request, e.g. /.api/graphql?Repositories
+func graphQLRouteName(r *http.Request) string {
+	if r.URL.RawQuery != "" {
+		return "graphql: " + r.URL.RawQuery
+	}
+	return "graphql: unknown"
+}
+
 func TraceRoute(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		if p, ok := r.Context().Value(routeNameKey).(*string); ok {
 			if routeName := mux.CurrentRoute(r).GetName(); routeName != "" {
 				*p = routeName
 			}
+			decideSampling(r, *p)
 		}
 		next.ServeHTTP(rw, r)
 	})
 }
 
+// decideSampling samples the request once its route is known. Its user is
+// known by then, because the auth middlewares run before routing.
+func decideSampling(r *http.Request, routeName string) {
+	if routeName == "graphql" {
+		routeName = graphQLRouteName(r)
+	}
+	var userID int32
+	if p, ok := r.Context().Value(userKey).(*int32); ok {
+		userID = *p
+	}
+	requestSamplingFromContext(r.Context()).decide(routeName, userID)
+}
+
 func TraceUser(ctx context.Context, userID int32) {
 	if p, ok := ctx.Value(userKey).(*int32); ok {
 		*p = userID
@@ -184,6 +205,7 @@ func TraceUser(ctx context.Context, userID int32) {
 func SetRouteName(r *http.Request, routeName string) {
 	if p, ok := r.Context().Value(routeNameKey).(*string); ok {
 		*p = routeName
+		decideSampling(r, routeName)
 	}
 }
 
@@ -0,0 +1,183 @@
+package trace
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// samplingPolicy decides which requests are traced, as configured in the
+// "tracing.sampling" critical configuration.
+type samplingPolicy struct {
+	rate                 float64
+	searchRate           float64
+	routes               map[string]float64
+	users                map[int32]bool
+	slowRequestThreshold time.Duration
+}
+
+var (
+	samplingPolicyMu sync.RWMutex
+	// currentSamplingPolicy is nil if all requests are traced.
+	currentSamplingPolicy *samplingPolicy
+)
+
+func init() {
+	go conf.Watch(func() {
+		p := newSamplingPolicy(conf.Get().Critical.TracingSampling)
+		samplingPolicyMu.Lock()
+		currentSamplingPolicy = p
+		samplingPolicyMu.Unlock()
+	})
+}
+
+func getSamplingPolicy() *samplingPolicy {
+	samplingPolicyMu.RLock()
+	defer samplingPolicyMu.RUnlock()
+	return currentSamplingPolicy
+}
+
+func newSamplingPolicy(c *schema.TracingSampling) *samplingPolicy {
+	if c == nil {
+		return nil
+	}
+
+	p := &samplingPolicy{
+		rate:       c.Rate,
+		searchRate: c.SearchRate,
+		routes:     c.Routes,
+		users:      make(map[int32]bool, len(c.UserIDs)),
+	}
+	if p.searchRate == 0 {
+		p.searchRate = p.rate
+	}
+	for _, id := range c.UserIDs {
+		p.users[int32(id)] = true
+	}
+	if c.SlowRequestThreshold != "" {
+		d, err := time.ParseDuration(c.SlowRequestThreshold)
+		if err != nil {
+			log15.Error("tracing.sampling: invalid slowRequestThreshold", "error", err)
+		}
+		p.slowRequestThreshold = d
+	}
+	return p
+}
+
+// sample reports whether a request to the given route by the given user (or
+// 0 if anonymous) is traced.
+func (p *samplingPolicy) sample(route string, userID int32) bool {
+	if userID != 0 && p.users[userID] {
+		return true
+	}
+
+	rate, ok := p.routes[route]
+	if !ok {
+		rate = p.rate
+		if isSearchRoute(route) {
+			rate = p.searchRate
+		}
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// slow reports whether a request that took the given duration is slow, so
+// that its root span is recorded even if it wasn't sampled.
+func (p *samplingPolicy) slow(took time.Duration) bool {
+	return p.slowRequestThreshold > 0 && took >= p.slowRequestThreshold
+}
+
+func isSearchRoute(route string) bool {
+	return route == "graphql: Search" || route == "search"
+}
+
+// requestSampling is the sampling state of the root span of a request, which
+// is decided once the route and user of the request are known.
+type requestSampling struct {
+	span    opentracing.Span
+	r       *http.Request
+	policy  *samplingPolicy
+	decided bool
+	sampled bool
+}
+
+// newRequestSampling returns the sampling state of the span of the request,
+// which is unsampled until the request is sampled. It returns nil if there is
+// no sampling policy, or if the sampling decision was made upstream (as
+// indicated by the request having a parent span).
+func newRequestSampling(span opentracing.Span, r *http.Request, hasParent bool) *requestSampling {
+	policy := getSamplingPolicy()
+	if policy == nil || hasParent {
+		return nil
+	}
+
+	ext.SamplingPriority.Set(span, 0)
+	return &requestSampling{span: span, r: r, policy: policy}
+}
+
+// decide samples the request according to the policy, unless that was
+// decided before.
+func (s *requestSampling) decide(route string, userID int32) {
+	if s == nil || s.decided {
+		return
+	}
+	s.decided = true
+	if s.policy.sample(route, userID) {
+		s.setSampled()
+	}
+}
+
+// finish records the root span of a request that wasn't sampled if the
+// request was slow, or if no sampling decision was made for it.
+func (s *requestSampling) finish(route string, userID int32, took time.Duration) {
+	if s == nil || s.sampled {
+		return
+	}
+	if !s.decided {
+		s.decide(route, userID)
+	}
+	if !s.sampled && s.policy.slow(took) {
+		s.setSampled()
+		s.span.SetTag("sampling.slow", true)
+	}
+}
+
+func (s *requestSampling) setSampled() {
+	s.sampled = true
+	ext.SamplingPriority.Set(s.span, 1)
+	// Tags set while the span was unsampled were dropped.
+	setRequestTags(s.span, s.r)
+}
+
+func setRequestTags(span opentracing.Span, r *http.Request) {
+	ext.HTTPUrl.Set(span, r.URL.String())
+	ext.HTTPMethod.Set(span, r.Method)
+	span.SetTag("http.referer", r.Header.Get("referer"))
+}
+
+// SampleRequest applies the tracing sampling policy to span, the span of a
+// request to the given route of a service without named routes or users
+// (such as repo-updater). It returns a function that must be called with the
+// duration of the request when it's done, to record the span of a slow
+// request that wasn't sampled.
+func SampleRequest(span opentracing.Span, r *http.Request, route string) (done func(took time.Duration)) {
+	_, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+	s := newRequestSampling(span, r, err == nil)
+	s.decide(route, 0)
+	return func(took time.Duration) {
+		s.finish(route, 0, took)
+	}
+}
+
+func requestSamplingFromContext(ctx context.Context) *requestSampling {
+	s, _ := ctx.Value(samplingKey).(*requestSampling)
+	return s
+}
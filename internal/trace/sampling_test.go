@@ -0,0 +1,106 @@
+package trace
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSamplingPolicy(t *testing.T) {
+	if p := newSamplingPolicy(nil); p != nil {
+		t.Fatalf("have policy %+v without config, want nil", p)
+	}
+
+	p := newSamplingPolicy(&schema.TracingSampling{
+		Rate:                 0,
+		Routes:               map[string]float64{"repo.tree": 1, "graphql: Search": 0},
+		UserIDs:              []int{42},
+		SlowRequestThreshold: "2s",
+	})
+
+	for _, tc := range []struct {
+		route  string
+		userID int32
+		want   bool
+	}{
+		{"repo.blob", 0, false},
+		{"repo.tree", 0, true},
+		{"repo.blob", 42, true},
+		{"graphql: Search", 42, true},
+		{"graphql: Search", 1, false},
+	} {
+		if have := p.sample(tc.route, tc.userID); have != tc.want {
+			t.Errorf("sample(%q, %d) = %t, want %t", tc.route, tc.userID, have, tc.want)
+		}
+	}
+
+	if p.slow(time.Second) || !p.slow(3*time.Second) {
+		t.Errorf("unexpected slow requests with threshold %s", p.slowRequestThreshold)
+	}
+
+	// searchRate applies to search routes, rate to all others.
+	p = newSamplingPolicy(&schema.TracingSampling{Rate: 1, SearchRate: 0.000001})
+	if !p.sample("repo.blob", 0) {
+		t.Error("request not sampled with rate 1")
+	}
+	for i := 0; i < 10; i++ {
+		if p.sample("search", 0) {
+			t.Error("search request sampled with low searchRate")
+		}
+	}
+}
+
+func TestRequestSampling(t *testing.T) {
+	tracer := mocktracer.New()
+	r, err := http.NewRequest("GET", "https://sourcegraph.example.com/foo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := newSamplingPolicy(&schema.TracingSampling{
+		Rate:                 0,
+		Routes:               map[string]float64{"sampled": 1},
+		SlowRequestThreshold: "1s",
+	})
+
+	priority := func(s *requestSampling) interface{} {
+		return s.span.(*mocktracer.MockSpan).Tag(string(ext.SamplingPriority))
+	}
+
+	for _, tc := range []struct {
+		name   string
+		route  string
+		took   time.Duration
+		want   interface{}
+		status bool
+	}{
+		{name: "not sampled", route: "other", took: time.Millisecond, want: uint16(0)},
+		{name: "sampled route", route: "sampled", took: time.Millisecond, want: uint16(1), status: true},
+		{name: "slow", route: "other", took: time.Minute, want: uint16(1), status: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &requestSampling{span: tracer.StartSpan("test"), r: r, policy: policy}
+			ext.SamplingPriority.Set(s.span, 0)
+
+			s.decide(tc.route, 0)
+			s.finish(tc.route, 0, tc.took)
+
+			if have := priority(s); have != tc.want {
+				t.Errorf("have sampling priority %v, want %v", have, tc.want)
+			}
+			if s.sampled != tc.status {
+				t.Errorf("have sampled %t, want %t", s.sampled, tc.status)
+			}
+		})
+	}
+
+	// Nil sampling states, e.g. of requests whose sampling was decided
+	// upstream, are ignored.
+	var s *requestSampling
+	s.decide("sampled", 0)
+	s.finish("sampled", 0, time.Minute)
+}
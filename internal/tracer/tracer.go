@@ -137,6 +137,8 @@ func Init(options ...Option) {
 			// Default sampler configuration for when it is not specified via
 			// JAEGER_SAMPLER_* env vars. In most cases, this is sufficient
 			// enough to connect Sourcegraph to Jaeger without any env vars.
+			// Which requests are traced is then controlled with the
+			// "tracing.sampling" critical configuration (see trace.Middleware).
 			cfg.Sampler.Type = jaeger.SamplerTypeConst
 			cfg.Sampler.Param = 1
 		}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -38,7 +40,69 @@ func ReadFile(ctx context.Context, repo gitserver.Repo, commit api.CommitID, nam
 	return b, nil
 }
 
+// ReadFileLines returns the lines startLine through endLine (1-based and
+// inclusive) of the named file at commit. It stops reading the file after
+// endLine, so that the rest of a large file isn't transferred from gitserver.
+func ReadFileLines(ctx context.Context, repo gitserver.Repo, commit api.CommitID, name string, startLine, endLine int) ([]byte, error) {
+	if Mocks.ReadFile != nil {
+		data, err := Mocks.ReadFile(commit, name)
+		if err != nil {
+			return nil, err
+		}
+		return readLines(bytes.NewReader(data), startLine, endLine)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: ReadFileLines")
+	span.SetTag("Name", name)
+	span.SetTag("StartLine", startLine)
+	span.SetTag("EndLine", endLine)
+	defer span.Finish()
+
+	if startLine < 1 || endLine < startLine {
+		return nil, errors.Errorf("invalid line range %d-%d", startLine, endLine)
+	}
+	if err := checkSpecArgSafety(string(commit)); err != nil {
+		return nil, err
+	}
+
+	name = util.Rel(name)
+	return readFile(ctx, repo, commit, name, func(r io.Reader) ([]byte, error) {
+		return readLines(r, startLine, endLine)
+	})
+}
+
+// readLines reads the lines startLine through endLine (1-based and
+// inclusive) from r, without reading past endLine.
+func readLines(r io.Reader, startLine, endLine int) ([]byte, error) {
+	br := bufio.NewReader(r)
+	var buf bytes.Buffer
+	for line := 1; line <= endLine; line++ {
+		b, err := br.ReadBytes('\n')
+		if line >= startLine {
+			buf.Write(b)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
 func readFileBytes(ctx context.Context, repo gitserver.Repo, commit api.CommitID, name string, maxBytes int64) ([]byte, error) {
+	return readFile(ctx, repo, commit, name, func(r io.Reader) ([]byte, error) {
+		if maxBytes > 0 {
+			r = io.LimitReader(r, maxBytes)
+		}
+		return ioutil.ReadAll(r)
+	})
+}
+
+// readFile runs git show for the named file at commit and returns what read
+// reads from its output.
+func readFile(ctx context.Context, repo gitserver.Repo, commit api.CommitID, name string, read func(io.Reader) ([]byte, error)) ([]byte, error) {
 	ensureAbsCommit(commit)
 
 	cmd := gitserver.DefaultClient.Command("git", "show", string(commit)+":"+name)
@@ -49,11 +113,7 @@ func readFileBytes(ctx context.Context, repo gitserver.Repo, commit api.CommitID
 	}
 	defer stdout.Close()
 
-	r := io.Reader(stdout)
-	if maxBytes > 0 {
-		r = io.LimitReader(r, maxBytes)
-	}
-	data, err := ioutil.ReadAll(r)
+	data, err := read(stdout)
 	if err != nil {
 		if strings.Contains(err.Error(), "exists on disk, but not in") || strings.Contains(err.Error(), "does not exist") {
 			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
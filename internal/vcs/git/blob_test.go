@@ -51,3 +51,44 @@ func TestReadFile(t *testing.T) {
 		}
 	})
 }
+
+func TestReadFileLines(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"printf 'a\\nb\\nc\\nd\\n' > file1",
+		"git add file1",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+	commitID, err := git.ResolveRevision(context.Background(), repo, nil, "HEAD", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		start, end int
+		want       string
+	}{
+		{start: 1, end: 1, want: "a\n"},
+		{start: 2, end: 3, want: "b\nc\n"},
+		{start: 3, end: 10, want: "c\nd\n"},
+		{start: 5, end: 6, want: ""},
+	} {
+		data, err := git.ReadFileLines(ctx, repo, commitID, "file1", tc.start, tc.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tc.want {
+			t.Errorf("lines %d-%d: got %q, want %q", tc.start, tc.end, data, tc.want)
+		}
+	}
+
+	if _, err := git.ReadFileLines(ctx, repo, commitID, "filexyz", 1, 2); !os.IsNotExist(err) {
+		t.Errorf("got err %v, want os.IsNotExist", err)
+	}
+	if _, err := git.ReadFileLines(ctx, repo, commitID, "file1", 3, 2); err == nil {
+		t.Error("expected error for invalid line range")
+	}
+}
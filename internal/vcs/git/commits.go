@@ -1,9 +1,11 @@
 package git
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -243,6 +245,73 @@ func CommitCount(ctx context.Context, repo gitserver.Repo, opt CommitsOptions) (
 	return uint(n), err
 }
 
+// LastCommits returns the last commit that modified each of the given paths
+// in the history of commit, using a single git log command for all paths.
+// Paths that weren't modified in that history (such as paths that don't
+// exist) are omitted from the returned map.
+func LastCommits(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (map[string]*Commit, error) {
+	if Mocks.LastCommits != nil {
+		return Mocks.LastCommits(commit, paths)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: LastCommits")
+	span.SetTag("Commit", commit)
+	span.SetTag("Paths", len(paths))
+	defer span.Finish()
+
+	ensureAbsCommit(commit)
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	// Each commit is prefixed with a record separator, and followed by the
+	// NUL-separated paths it modified.
+	args := []string{"log", "--format=format:%x1e" + strings.TrimPrefix(logFormatWithoutRefs, "--format=format:"), "--name-only", "-z", string(commit), "--"}
+	want := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		if !want[p] {
+			want[p] = true
+			args = append(args, ":(literal)"+p)
+		}
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+	stdout, err := gitserver.StdoutReader(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+
+	// Stop reading the log as soon as the last commit of every path is
+	// known, which avoids walking the whole history in the common case.
+	commits := make(map[string]*Commit, len(want))
+	r := bufio.NewReader(stdout)
+	for len(commits) < len(want) {
+		entry, err := r.ReadBytes('\x1e')
+		if err != nil && err != io.EOF {
+			return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed", cmd.Args))
+		}
+
+		if entry = bytes.TrimSuffix(entry, []byte{'\x1e'}); len(entry) > 0 {
+			c, _, rest, perr := parseCommitFromLog(entry)
+			if perr != nil {
+				return nil, perr
+			}
+			for _, p := range bytes.Split(bytes.TrimPrefix(rest, []byte{'\n'}), []byte{'\x00'}) {
+				if name := string(p); want[name] && commits[name] == nil {
+					commits[name] = c
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+	return commits, nil
+}
+
 const (
 	partsPerCommit = 10 // number of \x00-separated fields per commit
 
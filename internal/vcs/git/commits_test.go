@@ -377,3 +377,47 @@ func TestRepository_Commits_options_path(t *testing.T) {
 		}
 	}
 }
+
+func TestRepository_LastCommits(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"echo a > a && echo b > b && mkdir d && echo c > 'd/c*'",
+		"git add -A",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m first --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"echo b2 > b",
+		"git add b",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit -m second --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+	)
+
+	first := &git.Commit{
+		ID:        "74b491411bcc414f14599c1fa7faa652b6000762",
+		Author:    git.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+		Committer: &git.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+		Message:   "first",
+	}
+	second := &git.Commit{
+		ID:        "614aa62fce2fe48886902e37a417fc40d485fcf3",
+		Author:    git.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+		Committer: &git.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+		Message:   "second",
+		Parents:   []api.CommitID{first.ID},
+	}
+
+	// Pathspec magic in paths (such as "*") is taken literally, and paths
+	// that weren't modified are omitted.
+	commits, err := git.LastCommits(ctx, repo, second.ID, []string{"a", "b", "d/c*", "doesnt-exist", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*git.Commit{"a": first, "b": second, "d/c*": first}
+	if len(commits) != len(want) {
+		t.Errorf("got %d commits, want %d", len(commits), len(want))
+	}
+	for path, wantC := range want {
+		if !CommitsEqual(commits[path], wantC) {
+			t.Errorf("%s: got commit %+v, want %+v", path, commits[path], wantC)
+		}
+	}
+}
@@ -147,6 +147,169 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 		tr.Finish()
 	}()
 
+	s, err := newRawLogDiffSearch(repo, opt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Time out the first `git log` operation prior to the parent context timeout, so we still have time to `git
+	// show` the results it returns. These proportions are untuned guesses.
+	//
+	// TODO(sqs): this can be made much more efficient in many ways
+	withTimeout := func(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+		if deadline.IsZero() {
+			return ctx, func() {}
+		}
+		return context.WithTimeout(ctx, timeout)
+	}
+	// Run `git log` oneline command and read list of matching commits.
+	onelineCmd := gitserver.DefaultClient.Command("git", s.onelineArgs...)
+	onelineCmd.Repo = repo
+	logTimeout := time.Until(deadline) / 2
+	tr.LazyPrintf("git log %v with timeout %s", onelineCmd.Args, logTimeout)
+	ctxLog, cancel := withTimeout(ctx, logTimeout)
+	data, complete, err := readUntilTimeout(ctxLog, onelineCmd)
+	tr.LazyPrintf("git log done: data %d bytes, complete=%v, err=%v", len(data), complete, err)
+	cancel()
+	commitOIDs, complete, err := s.parseOnelineLog(data, complete, err)
+	if err != nil || len(commitOIDs) == 0 {
+		return nil, complete, err
+	}
+
+	// Now fetch the full commit data for all of the commits.
+	showArgs, err := s.showArgs(commitOIDs)
+	if err != nil {
+		return nil, false, err
+	}
+	showCmd := gitserver.DefaultClient.Command("git", showArgs...)
+	showCmd.Repo = repo
+	var complete2 bool
+	showTimeout := time.Duration(float64(time.Until(deadline)) * 0.8) // leave time for the filterAndResolveRef calls (HACK(sqs): hacky heuristic!)
+	tr.LazyPrintf("git show %v with timeout %s", showCmd.Args, showTimeout)
+	ctxShow, cancel := withTimeout(ctx, showTimeout)
+	data, complete2, err = readUntilTimeout(ctxShow, showCmd)
+	tr.LazyPrintf("git show done: data %d bytes, complete=%v, err=%v", len(data), complete2, err)
+	cancel()
+	if err != nil {
+		return nil, complete, err
+	}
+	return s.parseShow(ctx, data, complete && complete2)
+}
+
+// RawLogDiffSearchInRepos is like RawLogDiffSearch, but searches each of the repositories with
+// the options at the same index in opts. The git commands of the repositories on the same
+// gitserver are run with a single request to it (see gitserver.Client.BatchExec), instead of with
+// requests per repository.
+//
+// onResults is called exactly once for each repository, with its index in repos, as soon as its
+// results are available. It is called concurrently.
+func RawLogDiffSearchInRepos(ctx context.Context, repos []gitserver.Repo, opts []RawLogDiffSearchOptions, onResults func(i int, results []*LogCommitSearchResult, complete bool, err error)) {
+	if Mocks.RawLogDiffSearch != nil {
+		for i := range repos {
+			results, complete, err := Mocks.RawLogDiffSearch(opts[i])
+			onResults(i, results, complete, err)
+		}
+		return
+	}
+
+	tr, ctx := trace.New(ctx, "Git: RawLogDiffSearchInRepos", fmt.Sprintf("%d repos", len(repos)))
+	defer tr.Finish()
+
+	// timeout returns the fraction of the time left until the deadline of ctx, or 0 (the
+	// gitserver's default timeout) if ctx has no deadline. The fractions are the same as in
+	// RawLogDiffSearch.
+	deadline, _ := ctx.Deadline()
+	timeout := func(fraction float64) time.Duration {
+		if deadline.IsZero() {
+			return 0
+		}
+		return time.Duration(float64(time.Until(deadline)) * fraction)
+	}
+
+	searches := make([]*rawLogDiffSearch, len(repos))
+	var (
+		onelineCmds    []*gitserver.Cmd
+		onelineIndexes []int // the index in repos of each command
+	)
+	for i, repo := range repos {
+		s, err := newRawLogDiffSearch(repo, opts[i])
+		if err != nil {
+			onResults(i, nil, false, err)
+			continue
+		}
+		searches[i] = s
+
+		cmd := gitserver.DefaultClient.Command("git", s.onelineArgs...)
+		cmd.Repo = repo
+		onelineCmds = append(onelineCmds, cmd)
+		onelineIndexes = append(onelineIndexes, i)
+	}
+
+	// Run the `git log` oneline commands, and then the `git show` commands of the repositories
+	// that have matching commits.
+	var (
+		mu            sync.Mutex
+		showCmds      []*gitserver.Cmd
+		showIndexes   []int  // the index in repos of each command
+		showCompletes []bool // whether the output of the oneline command was complete
+	)
+	gitserver.DefaultClient.BatchExec(ctx, onelineCmds, timeout(0.5), func(j int, data []byte, complete bool, err error) {
+		i := onelineIndexes[j]
+		s := searches[i]
+		if err != nil && complete {
+			err = commandFailedError(onelineCmds[j], data, err)
+		}
+		commitOIDs, complete, err := s.parseOnelineLog(data, complete, err)
+		if err != nil || len(commitOIDs) == 0 {
+			onResults(i, nil, complete, err)
+			return
+		}
+		showArgs, err := s.showArgs(commitOIDs)
+		if err != nil {
+			onResults(i, nil, false, err)
+			return
+		}
+
+		cmd := gitserver.DefaultClient.Command("git", showArgs...)
+		cmd.Repo = s.repo
+		mu.Lock()
+		showCmds = append(showCmds, cmd)
+		showIndexes = append(showIndexes, i)
+		showCompletes = append(showCompletes, complete)
+		mu.Unlock()
+	})
+	tr.LazyPrintf("git log done: %d repos with matching commits", len(showCmds))
+
+	gitserver.DefaultClient.BatchExec(ctx, showCmds, timeout(0.8), func(j int, data []byte, complete bool, err error) {
+		i := showIndexes[j]
+		if err != nil {
+			if complete {
+				err = commandFailedError(showCmds[j], data, err)
+			}
+			onResults(i, nil, showCompletes[j], err)
+			return
+		}
+		results, complete, err := searches[i].parseShow(ctx, data, showCompletes[j] && complete)
+		onResults(i, results, complete, err)
+	})
+}
+
+// rawLogDiffSearch is a RawLogDiffSearch in a repository, split into the steps around the git
+// commands that it runs, so that the commands of several repositories can be run in batches.
+type rawLogDiffSearch struct {
+	repo gitserver.Repo
+	opt  RawLogDiffSearchOptions
+
+	onelineArgs    []string       // the args of the `git log` command that lists matching commits
+	query          *regexp.Regexp // the query, to filter and highlight the diffs (nil if none)
+	pathMatcher    pathmatch.PathMatcher
+	hasPathFilters bool
+
+	// commitSourceRefs maps each matching commit to its source ref (see `git log --source`).
+	commitSourceRefs map[string]string
+}
+
+func newRawLogDiffSearch(repo gitserver.Repo, opt RawLogDiffSearchOptions) (*rawLogDiffSearch, error) {
 	if opt.FormatArgs == nil {
 		if opt.Diff {
 			opt.FormatArgs = validRawLogDiffSearchFormatArgs[0] // with --patch
@@ -155,81 +318,29 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 		}
 	}
 	if opt.FormatArgs != nil && !isValidRawLogDiffSearchFormatArgs(opt.FormatArgs) {
-		return nil, false, fmt.Errorf("invalid FormatArgs: %q", opt.FormatArgs)
+		return nil, fmt.Errorf("invalid FormatArgs: %q", opt.FormatArgs)
 	}
 	for _, arg := range opt.Args {
 		if arg == "--" {
-			return nil, false, fmt.Errorf("invalid Args (must not contain \"--\" element): %q", opt.Args)
+			return nil, fmt.Errorf("invalid Args (must not contain \"--\" element): %q", opt.Args)
 		}
 	}
 
 	if opt.Query.IsCaseSensitive != opt.Paths.IsCaseSensitive {
 		// These options can't be set separately in `git log`, so fail.
-		return nil, false, fmt.Errorf("invalid options: Query.IsCaseSensitive != Paths.IsCaseSensitive")
+		return nil, fmt.Errorf("invalid options: Query.IsCaseSensitive != Paths.IsCaseSensitive")
 	}
 
-	appendCommonQueryArgs := func(args *[]string) {
-		if opt.Query.Pattern != "" {
-			var queryArg string
-			if opt.MatchChangedOccurrenceCount {
-				queryArg = "-S"
-			} else {
-				queryArg = "-G"
-			}
-			*args = append(*args, queryArg+opt.Query.Pattern)
-			if !opt.Query.IsCaseSensitive {
-				*args = append(*args, "--regexp-ignore-case")
-			}
-			if opt.Query.IsRegExp {
-				*args = append(*args, "--pickaxe-regex")
-			}
-		}
-		if opt.Paths.IsRegExp {
-			*args = append(*args, "--extended-regexp")
-		}
+	s := &rawLogDiffSearch{
+		repo:           repo,
+		opt:            opt,
+		hasPathFilters: opt.Paths.ExcludePattern != "" || len(opt.Paths.IncludePatterns) > 0,
 	}
 
 	args := []string{"log"}
 	args = append(args, opt.Args...)
 	if !isWhitelistedGitCmd(args) {
-		return nil, false, fmt.Errorf("command failed: %q is not a whitelisted git command", args)
-	}
-
-	appendCommonDashDashArgs := func(args *[]string) {
-		// If we have exclude paths, we need to effectively unset the --max-count because we can't
-		// filter out changes that match the exclude path (because there's no way to use full
-		// regexps in git pathspecs).
-		//
-		// TODO(sqs): use git pathspec %(...) extensions to reduce the number of cases where this is
-		// necessary; see https://git-scm.com/docs/gitglossary.html#def_pathspec.
-		var addMaxCount500 bool
-		if opt.Paths.ExcludePattern != "" {
-			addMaxCount500 = true
-		}
-
-		// Args we append after this don't need to be checked for whitelisting because "--"
-		// precedes them.
-		var pathspecs []string
-		for _, p := range opt.Paths.IncludePatterns {
-			// Roughly try to convert IncludePatterns (regexps) to git pathspecs (globs).
-			glob, equiv := regexpToGlobBestEffort(p)
-			if !opt.Paths.IsCaseSensitive && glob != "" {
-				// This relies on regexpToGlobBestEffort not returning `:`-prefixed globs.
-				glob = ":(icase)" + glob
-			}
-			if !equiv {
-				addMaxCount500 = true
-			}
-			if glob != "" {
-				pathspecs = append(pathspecs, glob)
-			}
-		}
-
-		if addMaxCount500 {
-			*args = append(*args, "--max-count=500") // TODO(sqs): 500 is arbitrary high number
-		}
-		*args = append(*args, "--")
-		*args = append(*args, pathspecs...)
+		return nil, fmt.Errorf("command failed: %q is not a whitelisted git command", args)
 	}
 
 	// We need to get `git log --source` (the ref by which we reached each commit), but
@@ -237,8 +348,8 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 	// https://stackoverflow.com/questions/12712775/git-get-source-information-in-format.
 	// So we first must run `git log --oneline --source ...` (which does have that info),
 	// and then later we will go look up each commit's patch and other info.
-	onelineArgs := append([]string{}, args...)
-	onelineArgs = append(onelineArgs,
+	s.onelineArgs = append([]string{}, args...)
+	s.onelineArgs = append(s.onelineArgs,
 		"-z",
 		"--no-abbrev-commit",
 		"--format=oneline",
@@ -247,28 +358,95 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 		"--no-patch",
 		"--no-merges",
 	)
-	appendCommonQueryArgs(&onelineArgs)
-	appendCommonDashDashArgs(&onelineArgs)
+	s.appendCommonQueryArgs(&s.onelineArgs)
+	s.appendCommonDashDashArgs(&s.onelineArgs)
 
-	// Time out the first `git log` operation prior to the parent context timeout, so we still have time to `git
-	// show` the results it returns. These proportions are untuned guesses.
+	// Even though we've already searched using the query, we need to
+	// search the returned diff again to filter to only matching hunks
+	// and to highlight matches.
+	if pattern := opt.Query.Pattern; pattern != "" {
+		if !opt.Query.IsRegExp {
+			pattern = regexp.QuoteMeta(pattern)
+		}
+		if !opt.Query.IsCaseSensitive {
+			pattern = "(?i:" + pattern + ")"
+		}
+		var err error
+		s.query, err = regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	s.pathMatcher, err = compilePathMatcher(opt.Paths)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *rawLogDiffSearch) appendCommonQueryArgs(args *[]string) {
+	if s.opt.Query.Pattern != "" {
+		var queryArg string
+		if s.opt.MatchChangedOccurrenceCount {
+			queryArg = "-S"
+		} else {
+			queryArg = "-G"
+		}
+		*args = append(*args, queryArg+s.opt.Query.Pattern)
+		if !s.opt.Query.IsCaseSensitive {
+			*args = append(*args, "--regexp-ignore-case")
+		}
+		if s.opt.Query.IsRegExp {
+			*args = append(*args, "--pickaxe-regex")
+		}
+	}
+	if s.opt.Paths.IsRegExp {
+		*args = append(*args, "--extended-regexp")
+	}
+}
+
+func (s *rawLogDiffSearch) appendCommonDashDashArgs(args *[]string) {
+	// If we have exclude paths, we need to effectively unset the --max-count because we can't
+	// filter out changes that match the exclude path (because there's no way to use full
+	// regexps in git pathspecs).
 	//
-	// TODO(sqs): this can be made much more efficient in many ways
-	withTimeout := func(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-		if deadline.IsZero() {
-			return ctx, func() {}
+	// TODO(sqs): use git pathspec %(...) extensions to reduce the number of cases where this is
+	// necessary; see https://git-scm.com/docs/gitglossary.html#def_pathspec.
+	var addMaxCount500 bool
+	if s.opt.Paths.ExcludePattern != "" {
+		addMaxCount500 = true
+	}
+
+	// Args we append after this don't need to be checked for whitelisting because "--"
+	// precedes them.
+	var pathspecs []string
+	for _, p := range s.opt.Paths.IncludePatterns {
+		// Roughly try to convert IncludePatterns (regexps) to git pathspecs (globs).
+		glob, equiv := regexpToGlobBestEffort(p)
+		if !s.opt.Paths.IsCaseSensitive && glob != "" {
+			// This relies on regexpToGlobBestEffort not returning `:`-prefixed globs.
+			glob = ":(icase)" + glob
+		}
+		if !equiv {
+			addMaxCount500 = true
+		}
+		if glob != "" {
+			pathspecs = append(pathspecs, glob)
 		}
-		return context.WithTimeout(ctx, timeout)
 	}
-	// Run `git log` oneline command and read list of matching commits.
-	onelineCmd := gitserver.DefaultClient.Command("git", onelineArgs...)
-	onelineCmd.Repo = repo
-	logTimeout := time.Until(deadline) / 2
-	tr.LazyPrintf("git log %v with timeout %s", onelineCmd.Args, logTimeout)
-	ctxLog, cancel := withTimeout(ctx, logTimeout)
-	data, complete, err := readUntilTimeout(ctxLog, onelineCmd)
-	tr.LazyPrintf("git log done: data %d bytes, complete=%v, err=%v", len(data), complete, err)
-	cancel()
+
+	if addMaxCount500 {
+		*args = append(*args, "--max-count=500") // TODO(sqs): 500 is arbitrary high number
+	}
+	*args = append(*args, "--")
+	*args = append(*args, pathspecs...)
+}
+
+// parseOnelineLog parses the output of the `git log` oneline command, which finished with err,
+// and returns the IDs of the matching commits.
+func (s *rawLogDiffSearch) parseOnelineLog(data []byte, complete bool, err error) (commitOIDs []string, _ bool, _ error) {
 	if err != nil {
 		// Don't fail if the repository is empty.
 		if strings.Contains(err.Error(), "does not have any commits yet") {
@@ -286,69 +464,42 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 		}
 	}
 	// Build a map of commit -> source ref.
-	commitSourceRefs := make(map[string]string, len(onelineCommits))
+	s.commitSourceRefs = make(map[string]string, len(onelineCommits))
 	for _, c := range onelineCommits {
-		commitSourceRefs[c.sha1] = c.sourceRef
-	}
-
-	// Even though we've already searched using the query, we need to
-	// search the returned diff again to filter to only matching hunks
-	// and to highlight matches.
-	var query *regexp.Regexp
-	if pattern := opt.Query.Pattern; pattern != "" {
-		if !opt.Query.IsRegExp {
-			pattern = regexp.QuoteMeta(pattern)
-		}
-		if !opt.Query.IsCaseSensitive {
-			pattern = "(?i:" + pattern + ")"
-		}
-		var err error
-		query, err = regexp.Compile(pattern)
-		if err != nil {
-			return nil, false, err
-		}
-	}
-
-	pathMatcher, err := compilePathMatcher(opt.Paths)
-	if err != nil {
-		return nil, false, err
+		s.commitSourceRefs[c.sha1] = c.sourceRef
 	}
 
-	// Now fetch the full commit data for all of the commits.
-	commitOIDs := make([]string, len(onelineCommits))
+	commitOIDs = make([]string, len(onelineCommits))
 	for i, c := range onelineCommits {
 		commitOIDs[i] = c.sha1
 	}
+	return commitOIDs, complete, nil
+}
+
+// showArgs returns the args of the `git show` command that fetches the full commit data of the
+// given commits.
+func (s *rawLogDiffSearch) showArgs(commitOIDs []string) ([]string, error) {
 	showArgs := append([]string{}, "show")
 	showArgs = append(showArgs, "--no-patch") // will be overridden if opt.FormatArgs has --patch
-	showArgs = append(showArgs, opt.FormatArgs...)
-	showArgs = append(showArgs, opt.Args...)
+	showArgs = append(showArgs, s.opt.FormatArgs...)
+	showArgs = append(showArgs, s.opt.Args...)
 	showArgs = append(showArgs, commitOIDs...)
 	// Need --patch (TODO(sqs): or just --raw, which is smaller) if we are filtering by file paths,
 	// because we post-filter by path since we need to support regexps. Just the commit message
 	// alone would be insufficient for our post-filtering.
-	hasPathFilters := opt.Paths.ExcludePattern != "" || len(opt.Paths.IncludePatterns) > 0
-	if hasPathFilters {
+	if s.hasPathFilters {
 		showArgs = append(showArgs, "--patch")
 	}
-	appendCommonQueryArgs(&showArgs)
-	appendCommonDashDashArgs(&showArgs)
+	s.appendCommonQueryArgs(&showArgs)
+	s.appendCommonDashDashArgs(&showArgs)
 	if !isWhitelistedGitCmd(showArgs) {
-		return nil, false, fmt.Errorf("command failed: %q is not a whitelisted git command", showArgs)
-	}
-	showCmd := gitserver.DefaultClient.Command("git", showArgs...)
-	showCmd.Repo = repo
-	var complete2 bool
-	showTimeout := time.Duration(float64(time.Until(deadline)) * 0.8) // leave time for the filterAndResolveRef calls (HACK(sqs): hacky heuristic!)
-	tr.LazyPrintf("git show %v with timeout %s", showCmd.Args, showTimeout)
-	ctxShow, cancel := withTimeout(ctx, showTimeout)
-	data, complete2, err = readUntilTimeout(ctxShow, showCmd)
-	tr.LazyPrintf("git show done: data %d bytes, complete=%v, err=%v", len(data), complete2, err)
-	cancel()
-	if err != nil {
-		return nil, complete, err
+		return nil, fmt.Errorf("command failed: %q is not a whitelisted git command", showArgs)
 	}
-	complete = complete && complete2
+	return showArgs, nil
+}
+
+// parseShow parses the output of the `git show` command into the search results.
+func (s *rawLogDiffSearch) parseShow(ctx context.Context, data []byte, complete bool) (results []*LogCommitSearchResult, _ bool, _ error) {
 	var cache refResolveCache
 	for len(data) > 0 {
 		var commit *Commit
@@ -368,11 +519,11 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 		result := &LogCommitSearchResult{
 			Commit:     *commit,
 			Refs:       refs,
-			SourceRefs: []string{commitSourceRefs[string(commit.ID)]},
+			SourceRefs: []string{s.commitSourceRefs[string(commit.ID)]},
 		}
-		result.Refs, err = filterAndResolveRefs(ctx, repo, result.Refs, &cache)
+		result.Refs, err = filterAndResolveRefs(ctx, s.repo, result.Refs, &cache)
 		if err == nil {
-			result.SourceRefs, err = filterAndResolveRefs(ctx, repo, result.SourceRefs, &cache)
+			result.SourceRefs, err = filterAndResolveRefs(ctx, s.repo, result.SourceRefs, &cache)
 		}
 		sort.Strings(result.Refs)
 		sort.Strings(result.SourceRefs)
@@ -391,7 +542,7 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 			if len(data) >= 1 {
 				data = data[1:]
 			}
-			if hasPathFilters {
+			if s.hasPathFilters {
 				hasMatch = false // patch was empty for the filtered paths, don't add to results
 			}
 		} else if len(data) >= 1 && data[0] == '\n' {
@@ -410,7 +561,7 @@ func RawLogDiffSearch(ctx context.Context, repo gitserver.Repo, opt RawLogDiffSe
 			}
 
 			var err error
-			rawDiff, result.DiffHighlights, err = filterAndHighlightDiff(rawDiff, query, opt.OnlyMatchingHunks, pathMatcher)
+			rawDiff, result.DiffHighlights, err = filterAndHighlightDiff(rawDiff, s.query, s.opt.OnlyMatchingHunks, s.pathMatcher)
 			if err != nil {
 				return nil, false, err
 			}
@@ -2,12 +2,14 @@ package git_test
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -159,3 +161,66 @@ func TestRepository_RawLogDiffSearch_emptyCommit(t *testing.T) {
 		}
 	}
 }
+
+func TestRawLogDiffSearchInRepos(t *testing.T) {
+	t.Parallel()
+
+	repos := []gitserver.Repo{
+		MakeGitRepository(t,
+			"echo root > f",
+			"git add f",
+			"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m root --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		),
+		MakeGitRepository(t,
+			"echo other > f",
+			"git add f",
+			"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m other --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		),
+		{Name: "doesnotexist"},
+	}
+	opt := git.RawLogDiffSearchOptions{
+		Query: git.TextSearchOptions{Pattern: "root"},
+		Diff:  true,
+	}
+
+	var mu sync.Mutex
+	calls := map[int]int{}
+	got := map[int][]*git.LogCommitSearchResult{}
+	errs := map[int]error{}
+	git.RawLogDiffSearchInRepos(ctx, repos, []git.RawLogDiffSearchOptions{opt, opt, opt}, func(i int, results []*git.LogCommitSearchResult, complete bool, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[i]++
+		if err == nil && !complete {
+			t.Errorf("repo %d: !complete", i)
+		}
+		for _, r := range results {
+			r.DiffHighlights = nil // Highlights is tested separately
+		}
+		got[i] = results
+		errs[i] = err
+	})
+
+	if want := map[int]int{0: 1, 1: 1, 2: 1}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("got calls %v, want %v", calls, want)
+	}
+	want, _, err := git.RawLogDiffSearch(ctx, repos[0], opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range want {
+		r.DiffHighlights = nil
+	}
+	if len(want) != 1 {
+		t.Fatalf("got %d results from RawLogDiffSearch, want 1", len(want))
+	}
+	if errs[0] != nil || !cmp.Equal(want, got[0]) {
+		t.Errorf("repo 0: err %v, mismatch (-want +got):\n%s", errs[0], cmp.Diff(want, got[0]))
+	}
+	if errs[1] != nil || len(got[1]) != 0 {
+		t.Errorf("repo 1: got err %v and %d results, want no results", errs[1], len(got[1]))
+	}
+	if !vcs.IsRepoNotExist(errs[2]) {
+		t.Errorf("repo 2: got err %v, want repository not found", errs[2])
+	}
+}
@@ -86,20 +86,26 @@ func readUntilTimeout(ctx context.Context, cmd *gitserver.Cmd) (data []byte, com
 		if err == nil {
 			complete = true
 		} else if err != context.DeadlineExceeded {
-			data = bytes.TrimSpace(data)
-			if isBadObjectErr(string(data), "") || isInvalidRevisionRangeError(string(data), "") {
-				return nil, true, &gitserver.RevisionNotFoundError{Repo: cmd.Repo.Name, Spec: "UNKNOWN"}
-			}
-			if len(data) > 100 {
-				data = append(data[:100], []byte("... (truncated)")...)
-			}
-			return nil, true, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, data))
+			return nil, true, commandFailedError(cmd, data, err)
 		}
 	}
 
 	return data, complete, nil
 }
 
+// commandFailedError returns the error to report for cmd, which failed with
+// err after writing data to stdout.
+func commandFailedError(cmd *gitserver.Cmd, data []byte, err error) error {
+	data = bytes.TrimSpace(data)
+	if isBadObjectErr(string(data), "") || isInvalidRevisionRangeError(string(data), "") {
+		return &gitserver.RevisionNotFoundError{Repo: cmd.Repo.Name, Spec: "UNKNOWN"}
+	}
+	if len(data) > 100 {
+		data = append(data[:100], []byte("... (truncated)")...)
+	}
+	return errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, data))
+}
+
 var (
 	// gitCmdWhitelist are commands and arguments that are allowed to execute when calling ExecSafe.
 	gitCmdWhitelist = map[string][]string{
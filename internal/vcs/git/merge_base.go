@@ -26,3 +26,29 @@ func MergeBase(ctx context.Context, repo gitserver.Repo, a, b api.CommitID) (api
 	}
 	return api.CommitID(bytes.TrimSpace(out)), nil
 }
+
+// IsAncestor returns true if commit a is an ancestor of commit b. A commit is
+// considered an ancestor of itself.
+func IsAncestor(ctx context.Context, repo gitserver.Repo, a, b api.CommitID) (bool, error) {
+	if Mocks.IsAncestor != nil {
+		return Mocks.IsAncestor(a, b)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: IsAncestor")
+	span.SetTag("A", a)
+	span.SetTag("B", b)
+	defer span.Finish()
+
+	cmd := gitserver.DefaultClient.Command("git", "merge-base", "--is-ancestor", "--", string(a), string(b))
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		// Exit status of 1 and no output means that a is not an ancestor of
+		// b. This is not a fatal error.
+		if cmd.ExitStatus == 1 && len(bytes.TrimSpace(out)) == 0 {
+			return false, nil
+		}
+		return false, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+	return true, nil
+}
@@ -71,3 +71,53 @@ func TestMerger_MergeBase(t *testing.T) {
 		}
 	}
 }
+
+func TestIsAncestor(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"echo line1 > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git tag testbase",
+		"git checkout -b b2",
+		"echo line2 >> f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout master",
+		"echo line3 > h",
+		"git add h",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m qux --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+
+	tests := []struct {
+		a, b string // can be any revspec; is resolved during the test
+		want bool
+	}{
+		{a: "testbase", b: "master", want: true},
+		{a: "testbase", b: "b2", want: true},
+		{a: "master", b: "master", want: true},
+		{a: "master", b: "b2", want: false},
+		{a: "b2", b: "testbase", want: false},
+	}
+
+	for _, test := range tests {
+		a, err := git.ResolveRevision(ctx, repo, nil, test.a, nil)
+		if err != nil {
+			t.Fatalf("ResolveRevision(%q): %s", test.a, err)
+		}
+		b, err := git.ResolveRevision(ctx, repo, nil, test.b, nil)
+		if err != nil {
+			t.Fatalf("ResolveRevision(%q): %s", test.b, err)
+		}
+
+		got, err := git.IsAncestor(ctx, repo, a, b)
+		if err != nil {
+			t.Errorf("IsAncestor(%s, %s): %s", test.a, test.b, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("IsAncestor(%s, %s): got %t, want %t", test.a, test.b, got, test.want)
+		}
+	}
+}
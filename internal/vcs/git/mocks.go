@@ -12,6 +12,8 @@ import (
 // (The emptyMocks is used by ResetMocks to zero out Mocks without needing to use a named type.)
 var Mocks, emptyMocks struct {
 	GetCommit        func(api.CommitID) (*Commit, error)
+	IsAncestor       func(a, b api.CommitID) (bool, error)
+	LastCommits      func(commit api.CommitID, paths []string) (map[string]*Commit, error)
 	ExecSafe         func(params []string) (stdout, stderr []byte, exitCode int, err error)
 	RawLogDiffSearch func(opt RawLogDiffSearchOptions) ([]*LogCommitSearchResult, bool, error)
 	ReadFile         func(commit api.CommitID, name string) ([]byte, error)
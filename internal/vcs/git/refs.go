@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	opentracing "github.com/opentracing/opentracing-go"
@@ -205,6 +206,53 @@ func GetBehindAhead(ctx context.Context, repo gitserver.Repo, left, right string
 	return &BehindAhead{Behind: uint32(b), Ahead: uint32(a)}, nil
 }
 
+// maxBehindAheadConcurrency is the maximum number of concurrent git rev-list
+// commands run by GetBehindAheadMany.
+const maxBehindAheadConcurrency = 8
+
+// GetBehindAheadMany returns the behind/ahead commit counts information for each of revs vs. base
+// (all Git revspecs). The i-th element of the result holds the counts for revs[i].
+func GetBehindAheadMany(ctx context.Context, repo gitserver.Repo, base string, revs []string) ([]*BehindAhead, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: BehindAheadMany")
+	span.SetTag("revs", len(revs))
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(base); err != nil {
+		return nil, err
+	}
+	for _, rev := range revs {
+		if err := checkSpecArgSafety(rev); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, maxBehindAheadConcurrency)
+		counts = make([]*BehindAhead, len(revs))
+		errs   = make([]error, len(revs))
+	)
+	for i, rev := range revs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, rev string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			counts[i], errs[i] = GetBehindAhead(ctx, repo, base, rev)
+		}(i, rev)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(err, "behind/ahead counts for %q", revs[i])
+		}
+	}
+	return counts, nil
+}
+
 // ListTags returns a list of all tags in the repository.
 func ListTags(ctx context.Context, repo gitserver.Repo) ([]*Tag, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: Tags")
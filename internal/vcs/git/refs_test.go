@@ -186,6 +186,38 @@ func TestRepository_Branches_BehindAheadCounts(t *testing.T) {
 	}
 }
 
+func TestGetBehindAheadMany(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo0 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git branch old_work",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo2 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout -b dev",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo3 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout old_work",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo4 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+
+	counts, err := git.GetBehindAheadMany(ctx, repo, "refs/heads/master", []string{"refs/heads/old_work", "refs/heads/dev", "refs/heads/master"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*git.BehindAhead{
+		{Behind: 2, Ahead: 1},
+		{Behind: 0, Ahead: 1},
+		{Behind: 0, Ahead: 0},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("got counts %v, want %v", AsJSON(counts), AsJSON(want))
+	}
+
+	if _, err := git.GetBehindAheadMany(ctx, repo, "refs/heads/master", []string{"-foo"}); err == nil {
+		t.Error("got nil error for unsafe revspec, want non-nil")
+	}
+}
+
 func TestRepository_Branches_IncludeCommit(t *testing.T) {
 	t.Parallel()
 
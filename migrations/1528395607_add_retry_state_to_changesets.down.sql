@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changesets DROP COLUMN IF EXISTS next_retry_at;
+ALTER TABLE changesets DROP COLUMN IF EXISTS failure_message;
+ALTER TABLE changesets DROP COLUMN IF EXISTS num_failures;
+
+COMMIT;
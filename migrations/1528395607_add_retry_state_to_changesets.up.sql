@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS num_failures integer NOT NULL DEFAULT 0;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS failure_message text;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS next_retry_at timestamp with time zone;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS archived_mirror boolean NOT NULL DEFAULT false;
+
+COMMIT;
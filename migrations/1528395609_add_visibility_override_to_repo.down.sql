@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE repo DROP CONSTRAINT IF EXISTS repo_visibility_override_check;
+ALTER TABLE repo DROP COLUMN IF EXISTS visibility_override;
+
+COMMIT;
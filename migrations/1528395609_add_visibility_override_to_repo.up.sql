@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS visibility_override text;
+ALTER TABLE repo ADD CONSTRAINT repo_visibility_override_check CHECK (visibility_override IN ('public', 'private', 'internal'));
+
+COMMIT;
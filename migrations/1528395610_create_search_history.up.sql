@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS search_history (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query text NOT NULL,
+    version text NOT NULL,
+    pattern_type text NOT NULL,
+    result_count integer NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS search_history_user_id_created_at ON search_history(user_id, created_at DESC);
+
+COMMIT;
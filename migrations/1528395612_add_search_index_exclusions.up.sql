@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS search_index_exclusions (
+  id serial PRIMARY KEY,
+  repo_id integer REFERENCES repo(id) ON DELETE CASCADE,
+  path_pattern text,
+  index_bytes bigint NOT NULL DEFAULT 0,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT repo_id_or_path_pattern CHECK ((repo_id IS NULL) <> (path_pattern IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS search_index_exclusions_repo_id ON search_index_exclusions(repo_id);
+CREATE UNIQUE INDEX IF NOT EXISTS search_index_exclusions_path_pattern ON search_index_exclusions(path_pattern);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE repo DROP COLUMN IF EXISTS last_commit_at;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS last_commit_at timestamp with time zone;
+
+COMMIT;
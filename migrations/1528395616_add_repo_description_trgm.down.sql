@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS repo_description_trgm;
+
+COMMIT;
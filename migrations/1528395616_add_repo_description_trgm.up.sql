@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS repo_description_trgm ON repo USING gin (lower(description) gin_trgm_ops);
+
+COMMIT;
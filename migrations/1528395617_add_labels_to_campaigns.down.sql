@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS campaigns_labels_gin_idx;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS labels;
+
+COMMIT;
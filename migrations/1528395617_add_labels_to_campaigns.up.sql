@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS labels text[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS campaigns_labels_gin_idx ON campaigns USING gin (labels);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS webhook_url;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS webhook_secret;
+
+COMMIT;
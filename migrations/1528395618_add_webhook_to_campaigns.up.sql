@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS webhook_url text NOT NULL DEFAULT '';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS webhook_secret text NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS search_job_results;
+DROP TABLE IF EXISTS search_jobs;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS search_jobs (
+  id serial PRIMARY KEY,
+  user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  query text NOT NULL,
+  pattern_type text NOT NULL,
+  state text NOT NULL DEFAULT 'queued',
+  repo_revs jsonb,
+  repos_searched integer NOT NULL DEFAULT 0,
+  result_count integer NOT NULL DEFAULT 0,
+  incomplete_repos text[] NOT NULL DEFAULT '{}',
+  error text,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  updated_at timestamp with time zone NOT NULL DEFAULT now(),
+  finished_at timestamp with time zone,
+  CONSTRAINT search_jobs_state_check CHECK (state IN ('queued', 'processing', 'completed', 'errored', 'canceled'))
+);
+
+CREATE INDEX IF NOT EXISTS search_jobs_user_id ON search_jobs(user_id);
+CREATE INDEX IF NOT EXISTS search_jobs_state ON search_jobs(state);
+
+CREATE TABLE IF NOT EXISTS search_job_results (
+  search_job_id integer NOT NULL REFERENCES search_jobs(id) ON DELETE CASCADE,
+  repo_offset integer NOT NULL,
+  results text NOT NULL,
+  PRIMARY KEY (search_job_id, repo_offset)
+);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS external_service_config_revisions (
+  id bigserial PRIMARY KEY,
+  external_service_id bigint NOT NULL REFERENCES external_services(id) ON DELETE CASCADE,
+  revision integer NOT NULL,
+  author_user_id integer REFERENCES users(id) ON DELETE SET NULL,
+  config text NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  UNIQUE (external_service_id, revision)
+);
+
+-- The current configuration of existing external services is their first
+-- recorded revision.
+INSERT INTO external_service_config_revisions(external_service_id, revision, config, created_at)
+SELECT id, 1, config, updated_at FROM external_services;
+
+COMMIT;
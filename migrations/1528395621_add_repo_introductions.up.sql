@@ -0,0 +1,27 @@
+BEGIN;
+
+-- repo_introductions is an append-only record of when each external service
+-- first yielded each repository. It deliberately has no foreign keys, so the
+-- record outlives the repositories, external services and users it refers to.
+CREATE TABLE IF NOT EXISTS repo_introductions (
+  id bigserial PRIMARY KEY,
+  repo_id integer NOT NULL,
+  repo_name citext NOT NULL,
+  external_service_id bigint NOT NULL,
+  author_user_id integer,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  UNIQUE (repo_id, external_service_id)
+);
+
+CREATE INDEX IF NOT EXISTS repo_introductions_external_service_id_idx ON repo_introductions(external_service_id);
+
+-- Backfill the repositories that existing external services yield. When they
+-- were first seen is approximated by when the repository was created, and who
+-- configured the external service then is unknown.
+INSERT INTO repo_introductions(repo_id, repo_name, external_service_id, created_at)
+SELECT repo.id, repo.name, split_part(sources.key, ':', 3)::bigint, repo.created_at
+FROM repo, jsonb_each(repo.sources) AS sources
+WHERE repo.deleted_at IS NULL AND sources.key LIKE 'extsvc:%'
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS closed_at timestamp with time zone;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS changeset_title_template text NOT NULL DEFAULT '';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS changeset_body_template text NOT NULL DEFAULT '';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS changeset_labels text[] NOT NULL DEFAULT '{}';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS changeset_assignees text[] NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS changeset_title_template;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS changeset_body_template;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS changeset_labels;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS changeset_assignees;
+
+COMMIT;
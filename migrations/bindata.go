@@ -50,6 +50,40 @@
 // 1528395605_drop_recent_searches.up.sql (55B)
 // 1528395606_lsif_add_visible_at_tip_flag.down.sql (361B)
 // 1528395606_lsif_add_visible_at_tip_flag.up.sql (273B)
+// 1528395607_add_retry_state_to_changesets.down.sql (198B)
+// 1528395607_add_retry_state_to_changesets.up.sql (264B)
+// 1528395608_add_archived_mirror_to_repo.down.sql (73B)
+// 1528395608_add_archived_mirror_to_repo.up.sql (107B)
+// 1528395609_add_visibility_override_to_repo.down.sql (152B)
+// 1528395609_add_visibility_override_to_repo.up.sql (214B)
+// 1528395610_create_search_history.down.sql (54B)
+// 1528395610_create_search_history.up.sql (452B)
+// 1528395611_add_changeset_templates_to_campaigns.down.sql (283B)
+// 1528395611_add_changeset_templates_to_campaigns.up.sql (403B)
+// 1528395612_add_search_index_exclusions.down.sql (63B)
+// 1528395612_add_search_index_exclusions.up.sql (585B)
+// 1528395613_add_repo_kvps.down.sql (49B)
+// 1528395613_add_repo_kvps.up.sql (189B)
+// 1528395614_add_repo_last_commit_at.down.sql (72B)
+// 1528395614_add_repo_last_commit_at.up.sql (100B)
+// 1528395615_add_repo_update_schedule.down.sql (60B)
+// 1528395615_add_repo_update_schedule.up.sql (323B)
+// 1528395616_add_repo_description_trgm.down.sql (61B)
+// 1528395616_add_repo_description_trgm.up.sql (119B)
+// 1528395617_add_labels_to_campaigns.down.sql (116B)
+// 1528395617_add_labels_to_campaigns.up.sql (186B)
+// 1528395618_add_webhook_to_campaigns.down.sql (134B)
+// 1528395618_add_webhook_to_campaigns.up.sql (190B)
+// 1528395619_add_search_jobs.down.sql (92B)
+// 1528395619_add_search_jobs.up.sql (1.067kB)
+// 1528395620_add_external_service_config_revisions.up.sql (674B)
+// 1528395620_add_external_service_config_revisions.down.sql (73B)
+// 1528395621_add_repo_introductions.down.sql (58B)
+// 1528395621_add_repo_introductions.up.sql (1.18kB)
+// 1528395622_add_campaigns_closed_at.down.sql (72B)
+// 1528395622_add_campaigns_closed_at.up.sql (100B)
+// 1528395623_drop_changeset_templates_from_campaigns.down.sql (403B)
+// 1528395623_drop_changeset_templates_from_campaigns.up.sql (283B)
 
 package migrations
 
@@ -1118,6 +1152,686 @@ func _1528395606_lsif_add_visible_at_tip_flagUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395607_add_retry_state_to_changesetsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xce\x48\xcc\x4b\x4f\x2d\x4e\x2d\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\xc8\x4b\xad\x28\x89\x2f\x4a\x2d\x29\xaa\x8c\x4f\x2c\xb1\x26\x4d\x6f\x5a\x62\x66\x4e\x69\x51\x6a\x7c\x6e\x6a\x71\x71\x62\x7a\x2a\x89\xba\xf3\x4a\x73\xe3\xa1\x26\x14\x5b\x73\x71\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x06\x00\x11\x53\x0b\x35\xc6\x00\x00\x00")
+
+func _1528395607_add_retry_state_to_changesetsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395607_add_retry_state_to_changesetsDownSql,
+		"1528395607_add_retry_state_to_changesets.down.sql",
+	)
+}
+
+func _1528395607_add_retry_state_to_changesetsDownSql() (*asset, error) {
+	bytes, err := _1528395607_add_retry_state_to_changesetsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395607_add_retry_state_to_changesets.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd8, 0x37, 0x84, 0xc5, 0xf0, 0xdb, 0x8c, 0x7a, 0x38, 0x45, 0xc2, 0xed, 0x6e, 0x6e, 0x81, 0x88, 0xf9, 0xee, 0x65, 0x3e, 0x58, 0xea, 0x17, 0x7a, 0x6a, 0x87, 0xf2, 0x4f, 0xfc, 0xe8, 0x45, 0x9b}}
+	return a, nil
+}
+
+var __1528395607_add_retry_state_to_changesetsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcc\x41\x6a\xc5\x20\x14\x46\xe1\xb9\xab\xf8\x97\xd0\xb9\x23\x93\x98\x22\x18\x03\x8d\x81\xce\x44\xca\x6d\x22\x54\x5b\xf4\x86\xa6\x5d\xfd\x83\xf0\x36\x90\xe1\xe1\xc0\xd7\xe9\x57\xe3\xa4\x10\xca\x7a\xfd\x06\xaf\x3a\xab\xf1\xb1\xc7\xb2\x51\x23\x6e\x50\xc3\x80\x7e\xb6\xeb\xe4\x60\x46\xb8\xd9\x43\xbf\x9b\xc5\x2f\x28\x47\x0e\x9f\x31\x7d\x1d\x95\x1a\x52\x61\xda\xa8\x5e\xdf\xad\xd6\x62\xd0\xa3\x5a\xad\xc7\x8b\xbc\x0d\x3f\xd1\x90\xa9\xb5\xb8\x11\x98\x4e\xbe\xaf\x14\x3a\x39\x54\xe2\xfa\x17\x22\x83\x53\xa6\xc6\x31\xff\xe0\x37\xf1\x7e\x25\xfe\xbf\x0b\x49\x21\xfa\x79\x9a\x8c\x97\xe2\x31\x00\xf4\xa4\x63\x4f\x08\x01\x00\x00")
+
+func _1528395607_add_retry_state_to_changesetsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395607_add_retry_state_to_changesetsUpSql,
+		"1528395607_add_retry_state_to_changesets.up.sql",
+	)
+}
+
+func _1528395607_add_retry_state_to_changesetsUpSql() (*asset, error) {
+	bytes, err := _1528395607_add_retry_state_to_changesetsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395607_add_retry_state_to_changesets.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcd, 0xae, 0x3, 0x4e, 0x32, 0x12, 0xc7, 0x5b, 0xb9, 0x88, 0xc3, 0x52, 0xa, 0x26, 0x1f, 0x51, 0x8c, 0x10, 0x88, 0xfb, 0x90, 0x63, 0xc4, 0xa1, 0xaf, 0x3f, 0x1a, 0x12, 0x98, 0xd6, 0x8a, 0x51}}
+	return a, nil
+}
+
+var __1528395608_add_archived_mirror_to_repoDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x49\x00\xb6\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x72\x63\x68\x69\x76\x65\x64\x5f\x6d\x69\x72\x72\x6f\x72\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x66\x76\x5a\xb8\x49\x00\x00\x00")
+
+func _1528395608_add_archived_mirror_to_repoDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395608_add_archived_mirror_to_repoDownSql,
+		"1528395608_add_archived_mirror_to_repo.down.sql",
+	)
+}
+
+func _1528395608_add_archived_mirror_to_repoDownSql() (*asset, error) {
+	bytes, err := _1528395608_add_archived_mirror_to_repoDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395608_add_archived_mirror_to_repo.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x47, 0xe0, 0xab, 0xdf, 0x93, 0x94, 0x85, 0xf1, 0x34, 0x5d, 0x64, 0x54, 0x8d, 0xd8, 0xe4, 0xdf, 0x1a, 0x18, 0x92, 0x94, 0xf, 0xa, 0x23, 0x90, 0xbe, 0x95, 0x5c, 0xe7, 0x92, 0x3b, 0x30, 0x85}}
+	return a, nil
+}
+
+var __1528395608_add_archived_mirror_to_repoUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6b\x00\x94\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x61\x72\x63\x68\x69\x76\x65\x64\x5f\x6d\x69\x72\x72\x6f\x72\x20\x62\x6f\x6f\x6c\x65\x61\x6e\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x66\x61\x6c\x73\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x58\xce\xe7\xe2\x6b\x00\x00\x00")
+
+func _1528395608_add_archived_mirror_to_repoUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395608_add_archived_mirror_to_repoUpSql,
+		"1528395608_add_archived_mirror_to_repo.up.sql",
+	)
+}
+
+func _1528395608_add_archived_mirror_to_repoUpSql() (*asset, error) {
+	bytes, err := _1528395608_add_archived_mirror_to_repoUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395608_add_archived_mirror_to_repo.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x30, 0xa5, 0x9, 0x3c, 0x50, 0xba, 0x11, 0x1e, 0x6f, 0xc7, 0x50, 0xa2, 0x66, 0x3f, 0x9, 0x3c, 0xc0, 0xaa, 0x88, 0xf4, 0x6a, 0x9, 0x7a, 0x12, 0x26, 0x91, 0xca, 0xb5, 0xd9, 0x2e, 0x9, 0x26}}
+	return a, nil
+}
+
+var __1528395609_add_visibility_override_to_repoDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4a\x2d\xc8\x57\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x0b\x0e\x09\x72\xf4\xf4\x0b\x51\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x06\x4b\xc7\x97\x65\x16\x67\x26\x65\xe6\x64\x96\x54\xc6\xe7\x97\xa5\x16\x15\x65\xa6\xa4\xc6\x27\x67\xa4\x26\x67\x5b\xe3\x34\xca\x27\xd4\xd7\x0f\xc9\x18\x2c\x26\x58\x73\x71\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x06\x00\x2e\x9d\xca\x86\x98\x00\x00\x00")
+
+func _1528395609_add_visibility_override_to_repoDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395609_add_visibility_override_to_repoDownSql,
+		"1528395609_add_visibility_override_to_repo.down.sql",
+	)
+}
+
+func _1528395609_add_visibility_override_to_repoDownSql() (*asset, error) {
+	bytes, err := _1528395609_add_visibility_override_to_repoDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395609_add_visibility_override_to_repo.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbe, 0xce, 0x9b, 0x3d, 0xcc, 0x51, 0xc, 0x4c, 0x85, 0xad, 0xa2, 0x45, 0xf8, 0x1a, 0x4e, 0xd0, 0x85, 0x90, 0xb3, 0x4a, 0x96, 0xd3, 0xe8, 0x5b, 0x84, 0xe3, 0x9e, 0x73, 0xde, 0x21, 0x46, 0x69}}
+	return a, nil
+}
+
+var __1528395609_add_visibility_override_to_repoUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xcc\xd1\x0a\x82\x30\x14\xc6\xf1\xfb\x3d\xc5\xb9\x9b\x42\x6f\xb0\xab\x39\x57\x8d\x74\x82\x9e\xa0\x3b\x51\x3b\xd0\x21\x51\x59\x4b\xea\xed\xa3\xae\xed\xee\xe3\x83\xff\x2f\xb3\x07\xe7\x95\x10\xba\x40\x5b\x03\xea\xac\xb0\x10\x68\x99\x41\xe7\x39\x98\xaa\x38\x97\x1e\xdc\x1e\x7c\x85\x60\x2f\xae\xc1\x06\x56\x7e\x70\xcf\x23\xc7\x77\x3b\xaf\x14\x02\x5f\x09\x22\xbd\xa2\xfa\x67\xf8\x06\x6b\xed\x3c\xfe\xbe\x76\x23\x6f\x87\x1b\x0d\x77\x30\x47\x6b\x4e\x90\x6c\xf9\xce\x43\x22\x97\x67\x3f\xf2\x20\x77\x20\x97\xc0\x6b\x17\xe9\x3b\x79\x8a\x14\xa6\x6e\x94\x69\xaa\x84\x30\x55\x59\x3a\x54\xe2\x33\x00\x81\x0b\x56\xc6\xd6\x00\x00\x00")
+
+func _1528395609_add_visibility_override_to_repoUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395609_add_visibility_override_to_repoUpSql,
+		"1528395609_add_visibility_override_to_repo.up.sql",
+	)
+}
+
+func _1528395609_add_visibility_override_to_repoUpSql() (*asset, error) {
+	bytes, err := _1528395609_add_visibility_override_to_repoUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395609_add_visibility_override_to_repo.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa8, 0x8e, 0x6f, 0xc0, 0xb2, 0x38, 0xc3, 0xcc, 0x27, 0x74, 0xa6, 0x28, 0x5, 0xd9, 0xa4, 0xc8, 0x78, 0x8a, 0xf1, 0x82, 0x63, 0xc1, 0xe3, 0xa4, 0xad, 0x2e, 0x8f, 0xe6, 0x15, 0xf7, 0xac, 0x82}}
+	return a, nil
+}
+
+var __1528395610_create_search_historyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x36\x00\xc9\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x61\x72\x63\x68\x5f\x68\x69\x73\x74\x6f\x72\x79\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xea\x94\xc8\x31\x36\x00\x00\x00")
+
+func _1528395610_create_search_historyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395610_create_search_historyDownSql,
+		"1528395610_create_search_history.down.sql",
+	)
+}
+
+func _1528395610_create_search_historyDownSql() (*asset, error) {
+	bytes, err := _1528395610_create_search_historyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395610_create_search_history.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7b, 0xe2, 0x7b, 0x2c, 0x41, 0x63, 0x83, 0x5a, 0xef, 0x4, 0xa3, 0x4d, 0xac, 0xb0, 0xb5, 0xfc, 0x68, 0x11, 0xd8, 0xe6, 0xa, 0xd, 0x78, 0xfd, 0xad, 0x28, 0x35, 0x5a, 0x66, 0x97, 0x70, 0xc5}}
+	return a, nil
+}
+
+var __1528395610_create_search_historyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x90\xd1\x6a\xf2\x40\x10\x85\xef\xf3\x14\xe7\x32\x82\x6f\xe0\x55\x4c\xc6\x9f\xf0\xc7\x4d\x49\x56\xd0\xab\x65\x6b\x06\x5d\xd0\x8d\xdd\x9d\xd4\xda\xa7\x2f\x44\xb1\xd2\x4a\x2f\x87\xef\x9b\x99\xc3\x99\xd3\xbf\x52\xcd\x92\x24\x6f\x28\xd3\x04\x9d\xcd\x2b\x42\xb9\x80\xaa\x35\x68\x5d\xb6\xba\x45\x64\x1b\xb6\x7b\xb3\x77\x51\xfa\x70\x41\x9a\x00\x80\xeb\xf0\xea\x76\x91\x83\xb3\x07\xbc\x34\xe5\x32\x6b\x36\xf8\x4f\x9b\xe9\x48\x87\xc8\xc1\xb8\x0e\xce\x0b\xef\x38\x8c\xd7\xd4\xaa\xaa\xd0\xd0\x82\x1a\x52\x39\xb5\xa3\x13\x53\xd7\x4d\x50\x2b\x14\x54\x91\x26\xe4\x59\x9b\x67\x05\x5d\x8f\xbc\x0d\x1c\x2e\x10\xfe\x90\xfb\xfe\x15\xbc\x73\x88\xae\xf7\xcf\xd0\xc9\x8a\x70\xf0\x46\x2e\x27\x7e\xc6\x03\xc7\xe1\x20\x66\xdb\x0f\x5e\x7e\xa5\xbb\x2a\xdb\xc0\x56\xb8\x33\x56\x20\xee\xc8\x51\xec\xf1\x84\xb3\x93\xfd\x38\xe2\xb3\xf7\x7c\xdf\x40\x41\x8b\x6c\x55\x69\xf8\xfe\x9c\x4e\x92\xc9\x77\x91\xa5\x2a\x68\xfd\x67\x91\xe6\x56\x92\x79\x78\x58\xab\x1f\x52\x7a\x93\xa6\x8f\xb1\x0a\x6a\xf3\xf1\x55\xbd\x5c\x96\x7a\x96\x7c\x0d\x00\x17\x19\xd8\x21\xc4\x01\x00\x00")
+
+func _1528395610_create_search_historyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395610_create_search_historyUpSql,
+		"1528395610_create_search_history.up.sql",
+	)
+}
+
+func _1528395610_create_search_historyUpSql() (*asset, error) {
+	bytes, err := _1528395610_create_search_historyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395610_create_search_history.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6e, 0x12, 0x8d, 0xa0, 0xef, 0x3e, 0xcf, 0xe, 0xed, 0x25, 0xe5, 0xd0, 0xe7, 0xcd, 0x13, 0x6d, 0x6b, 0xf, 0x7c, 0xa8, 0x24, 0xe5, 0x66, 0xcd, 0xf3, 0xc1, 0x78, 0x5c, 0xb2, 0xe1, 0xad, 0xd6}}
+	return a, nil
+}
+
+var __1528395611_add_changeset_templates_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xcc\x51\x0e\x82\x30\x0c\x00\xd0\xff\x9e\xa2\xf7\xd8\x17\xe0\x34\x4b\x36\x66\x60\x26\xfe\x91\x82\xcd\x5c\x32\x26\x49\xfb\xe3\xed\xbd\x03\x1e\xe0\xbd\xde\xde\xdc\x68\x00\x3a\x9f\xec\x84\xa9\xeb\xbd\xc5\x8d\xf6\x83\x4a\x6e\x82\x97\x29\xde\x71\x88\xfe\x11\x46\x74\x57\xb4\x4f\x37\xa7\x19\xb7\x37\xb5\xcc\xc2\xba\x68\xd1\xca\x8b\xf2\x7e\x54\x52\x36\x27\x97\xf5\xf3\xfa\xfe\x9d\x54\x5a\xb9\xca\x59\x4d\x22\x25\x37\x66\x31\x00\x43\x0c\xc1\x25\x03\xbf\x01\x00\xd0\x85\xe9\xea\x1b\x01\x00\x00")
+
+func _1528395611_add_changeset_templates_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395611_add_changeset_templates_to_campaignsDownSql,
+		"1528395611_add_changeset_templates_to_campaigns.down.sql",
+	)
+}
+
+func _1528395611_add_changeset_templates_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395611_add_changeset_templates_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395611_add_changeset_templates_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdc, 0xe0, 0x56, 0x1f, 0x6a, 0x2c, 0x87, 0x7e, 0xe9, 0xfe, 0x6e, 0x3a, 0xba, 0x89, 0x28, 0x6f, 0xf8, 0xdb, 0x61, 0x4, 0x32, 0x5d, 0xff, 0x67, 0xf7, 0x7d, 0xd2, 0x5d, 0x7b, 0x69, 0xf1, 0xeb}}
+	return a, nil
+}
+
+var __1528395611_add_changeset_templates_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\xce\xc1\x0a\x82\x40\x10\xc6\xf1\xfb\x3e\xc5\xdc\x7c\x08\x4f\xab\xae\xb1\xb0\xae\x90\x23\x04\x11\x32\xda\x60\xc2\x6a\xc2\xce\xa1\x88\xde\x3d\xf0\x1c\x04\x45\xf7\x8f\xff\xef\xcb\xcc\xce\xfa\x54\x29\xed\xd0\xec\x01\x75\xe6\x0c\x0c\x34\xaf\x34\x8d\x4b\x04\x5d\x14\x90\xd7\xae\xad\x3c\xd8\x12\x7c\x8d\x60\x0e\xb6\xc1\x06\x86\x0b\x2d\x23\x47\x96\x4e\x26\x09\xdc\x09\xcf\x6b\x20\x61\x10\xbe\xc9\x36\xf4\xad\x73\x50\x98\x52\xb7\x0e\x21\x49\xd2\xef\x85\xfe\x7a\xbe\xff\x15\x08\xd4\x73\x88\xdb\xf5\xe3\xe9\x4d\xfb\xf1\xfc\xa5\x4e\x31\x4e\xe3\xc2\xfc\x09\x50\x79\x5d\x55\x16\x53\xf5\x1a\x00\x30\xec\x08\x2f\x93\x01\x00\x00")
+
+func _1528395611_add_changeset_templates_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395611_add_changeset_templates_to_campaignsUpSql,
+		"1528395611_add_changeset_templates_to_campaigns.up.sql",
+	)
+}
+
+func _1528395611_add_changeset_templates_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395611_add_changeset_templates_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395611_add_changeset_templates_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x46, 0xef, 0x7e, 0x46, 0xe2, 0x9a, 0xb1, 0x26, 0x1b, 0x63, 0x81, 0xa5, 0x86, 0xd, 0xa5, 0x89, 0xce, 0xbf, 0x6e, 0x25, 0x4e, 0x2f, 0x36, 0xe7, 0xae, 0x45, 0xcd, 0x12, 0x6d, 0xbc, 0x26}}
+	return a, nil
+}
+
+var __1528395612_add_search_index_exclusionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3f\x00\xc0\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x61\x72\x63\x68\x5f\x69\x6e\x64\x65\x78\x5f\x65\x78\x63\x6c\x75\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xe6\x97\xee\x3d\x3f\x00\x00\x00")
+
+func _1528395612_add_search_index_exclusionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395612_add_search_index_exclusionsDownSql,
+		"1528395612_add_search_index_exclusions.down.sql",
+	)
+}
+
+func _1528395612_add_search_index_exclusionsDownSql() (*asset, error) {
+	bytes, err := _1528395612_add_search_index_exclusionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395612_add_search_index_exclusions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb7, 0xb3, 0xbf, 0x49, 0xb0, 0x75, 0xb4, 0x4f, 0x14, 0x35, 0x3a, 0xa0, 0x43, 0xda, 0x3a, 0x99, 0xaa, 0x0, 0x83, 0x79, 0xa3, 0xfd, 0xb5, 0x12, 0x7f, 0x26, 0x0, 0xa0, 0xe4, 0xa0, 0xdc, 0x66}}
+	return a, nil
+}
+
+var __1528395612_add_search_index_exclusionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x90\x41\x4b\xc3\x40\x10\x85\xef\xf9\x15\xef\x98\x80\x07\xef\x15\x21\x4d\xa6\xba\x34\xdd\x68\xb2\x81\xf6\xb4\xa4\xcd\xd0\x2e\xb4\x9b\xb0\x59\x69\xf5\xd7\x4b\x22\x41\x8b\x28\x82\xc7\xdd\x79\x6f\xde\x37\x6f\x4e\x0f\x42\xce\x82\x20\x29\x28\x56\x04\x15\xcf\x33\x82\x58\x40\xe6\x0a\xb4\x16\xa5\x2a\xd1\x73\xed\x76\x07\x6d\x6c\xc3\x17\xcd\x97\xdd\xf1\xa5\x37\xad\xed\x11\x06\x80\x69\xd0\xb3\x33\xf5\x11\x4f\x85\x58\xc5\xc5\x06\x4b\xda\xdc\x04\x80\xe3\xae\xd5\xa6\x81\xb1\x9e\xf7\xec\x50\xd0\x82\x0a\x92\x09\x95\xe3\x28\x34\x4d\x84\x5c\x22\xa5\x8c\x14\x21\x89\xcb\x24\x4e\x69\x30\x76\xb5\x3f\xe8\xae\xf6\x9e\x9d\x85\xe7\x8b\x1f\x3e\x3f\xb2\xb7\xaf\x9e\x7b\x6c\xcd\xde\x58\x3f\x02\xca\x2a\xcb\x90\xd2\x22\xae\x32\x85\xdb\x41\xb8\x73\x5c\x7b\x6e\x74\xed\xe1\xcd\x89\x7b\x5f\x9f\x3a\x9c\x8d\x3f\x8c\x4f\xbc\xb5\x96\xbf\x3b\x6d\x7b\x0e\xa3\xc1\x9d\xe4\xb2\x54\x45\x2c\xa4\x9a\xf8\x75\xeb\xf4\x15\x51\xf2\x48\xc9\x12\x61\x38\xdd\x27\xca\x71\x57\x84\xbb\x7b\x84\x57\xca\x69\x12\x05\xd1\x67\xbf\x95\x14\xcf\x15\x41\xc8\x94\xd6\x7f\xab\x59\x4f\x49\xb9\xfc\x49\x32\xc1\x44\xb3\x7f\xc4\x5c\xb1\xff\x92\xf5\x55\x37\x1e\x96\xaf\x56\x42\xcd\x82\xf7\x01\x00\xe0\x17\x45\xd3\x49\x02\x00\x00")
+
+func _1528395612_add_search_index_exclusionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395612_add_search_index_exclusionsUpSql,
+		"1528395612_add_search_index_exclusions.up.sql",
+	)
+}
+
+func _1528395612_add_search_index_exclusionsUpSql() (*asset, error) {
+	bytes, err := _1528395612_add_search_index_exclusionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395612_add_search_index_exclusions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd3, 0xdc, 0xf9, 0x4, 0x63, 0x4f, 0xa6, 0xf8, 0xba, 0x63, 0x92, 0x18, 0xf9, 0x62, 0x4d, 0xc3, 0x4d, 0x23, 0xe0, 0x9f, 0xa3, 0xa5, 0xec, 0xc3, 0xea, 0x61, 0x6b, 0xe4, 0xa6, 0x21, 0x6d, 0x86}}
+	return a, nil
+}
+
+var __1528395613_add_repo_kvpsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x31\x00\xce\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x6b\x76\x70\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x4c\x11\x6d\xb5\x31\x00\x00\x00")
+
+func _1528395613_add_repo_kvpsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395613_add_repo_kvpsDownSql,
+		"1528395613_add_repo_kvps.down.sql",
+	)
+}
+
+func _1528395613_add_repo_kvpsDownSql() (*asset, error) {
+	bytes, err := _1528395613_add_repo_kvpsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395613_add_repo_kvps.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa0, 0xbf, 0x41, 0x50, 0x3e, 0x4d, 0x7f, 0x67, 0xf7, 0xa7, 0x1f, 0x8a, 0x55, 0x86, 0x2e, 0xd8, 0xe2, 0x65, 0x18, 0xdf, 0x44, 0x13, 0x8a, 0xa1, 0x95, 0x51, 0x9e, 0xff, 0x60, 0xe4, 0x53, 0x58}}
+	return a, nil
+}
+
+var __1528395613_add_repo_kvpsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\x8d\xc1\x6a\x83\x40\x18\x84\xef\xff\x53\xcc\x51\xc1\x37\xf0\xb4\xae\x63\x59\xba\xae\x65\xdd\x42\x3d\x95\x82\x4b\x11\x43\x22\xc6\x48\xf2\xf6\x41\x0d\x39\x7e\x1f\xf3\x31\x05\x3f\x8c\xcb\x45\xb4\xa7\x0a\x44\x50\x85\x25\x4c\x05\xd7\x04\xf0\xc7\xb4\xa1\xc5\x1c\xa7\xcb\xef\xb8\x4e\x57\x24\x82\x83\x86\x1e\xc3\x79\x89\xff\x71\xde\x87\xee\xdb\x5a\x78\x56\xf4\x74\x9a\x47\x91\x0c\x7d\x8a\xc6\xa1\xa4\x65\x20\xb4\x6a\xb5\x2a\x99\x09\x30\xc6\x07\x96\x78\x5f\xde\xe9\x26\xd7\xbf\xd3\x2d\xee\x7a\xa3\x2f\x6f\x6a\xe5\x3b\x7c\xb2\x43\xf2\x7a\xcc\xb6\x30\x95\x34\x17\xd1\x4d\x5d\x9b\x90\xcb\x73\x00\xd5\xd5\xe7\x22\xbd\x00\x00\x00")
+
+func _1528395613_add_repo_kvpsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395613_add_repo_kvpsUpSql,
+		"1528395613_add_repo_kvps.up.sql",
+	)
+}
+
+func _1528395613_add_repo_kvpsUpSql() (*asset, error) {
+	bytes, err := _1528395613_add_repo_kvpsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395613_add_repo_kvps.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x75, 0x6d, 0x9f, 0x32, 0xf8, 0xe5, 0x95, 0xb2, 0x86, 0x43, 0x8e, 0x77, 0x83, 0x69, 0x9, 0xda, 0x9a, 0xf, 0xf8, 0xe9, 0xd, 0xdc, 0x70, 0xaf, 0x2c, 0xf1, 0x2e, 0x3, 0x11, 0x86, 0x6a, 0xcb}}
+	return a, nil
+}
+
+var __1528395614_add_repo_last_commit_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x48\x00\xb7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x61\x73\x74\x5f\x63\x6f\x6d\x6d\x69\x74\x5f\x61\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x02\xa8\x3e\xa5\x48\x00\x00\x00")
+
+func _1528395614_add_repo_last_commit_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395614_add_repo_last_commit_atDownSql,
+		"1528395614_add_repo_last_commit_at.down.sql",
+	)
+}
+
+func _1528395614_add_repo_last_commit_atDownSql() (*asset, error) {
+	bytes, err := _1528395614_add_repo_last_commit_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395614_add_repo_last_commit_at.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x42, 0xe1, 0x72, 0xff, 0x23, 0xf5, 0xcb, 0x46, 0x4a, 0xa4, 0x54, 0xdf, 0x7f, 0x8c, 0xc8, 0x8e, 0xcd, 0x6b, 0x49, 0x8a, 0xc9, 0x21, 0xf5, 0x7b, 0x5, 0x68, 0xe5, 0xf4, 0xe, 0x68, 0xfc, 0x1f}}
+	return a, nil
+}
+
+var __1528395614_add_repo_last_commit_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x64\x00\x9b\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x61\x73\x74\x5f\x63\x6f\x6d\x6d\x69\x74\x5f\x61\x74\x20\x74\x69\x6d\x65\x73\x74\x61\x6d\x70\x20\x77\x69\x74\x68\x20\x74\x69\x6d\x65\x20\x7a\x6f\x6e\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xff\xd3\x96\x77\x64\x00\x00\x00")
+
+func _1528395614_add_repo_last_commit_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395614_add_repo_last_commit_atUpSql,
+		"1528395614_add_repo_last_commit_at.up.sql",
+	)
+}
+
+func _1528395614_add_repo_last_commit_atUpSql() (*asset, error) {
+	bytes, err := _1528395614_add_repo_last_commit_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395614_add_repo_last_commit_at.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf2, 0xb7, 0x37, 0x4d, 0x55, 0xc0, 0xc5, 0xcd, 0xfb, 0x30, 0xb5, 0x47, 0x35, 0x36, 0xf2, 0x8d, 0xbf, 0xcf, 0x5a, 0x2, 0xdc, 0x37, 0xb0, 0x1b, 0x69, 0xa7, 0xed, 0x67, 0x40, 0xd8, 0x32, 0xd1}}
+	return a, nil
+}
+
+var __1528395615_add_repo_update_scheduleDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3c\x00\xc3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x75\x70\x64\x61\x74\x65\x5f\x73\x63\x68\x65\x64\x75\x6c\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xb0\xbf\x92\xc4\x3c\x00\x00\x00")
+
+func _1528395615_add_repo_update_scheduleDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395615_add_repo_update_scheduleDownSql,
+		"1528395615_add_repo_update_schedule.down.sql",
+	)
+}
+
+func _1528395615_add_repo_update_scheduleDownSql() (*asset, error) {
+	bytes, err := _1528395615_add_repo_update_scheduleDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395615_add_repo_update_schedule.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2c, 0xaa, 0x7f, 0x73, 0x93, 0x72, 0x71, 0xe2, 0x24, 0x16, 0x69, 0xa3, 0x6b, 0x30, 0xe0, 0x65, 0x9c, 0x45, 0x81, 0x40, 0x5e, 0x47, 0xc6, 0x54, 0x97, 0x9a, 0x20, 0x4f, 0xfc, 0x94, 0x65, 0xa8}}
+	return a, nil
+}
+
+var __1528395615_add_repo_update_scheduleUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\x3f\x6b\xc3\x30\x10\x47\x77\x7d\x8a\xdf\x68\x43\x87\xee\x9e\x14\xfb\x5c\x4c\xfd\xa7\xd8\x0a\x34\x93\x30\xd6\xd1\x08\x12\xc9\xc8\x72\x03\xfd\xf4\xc5\x31\x04\x4a\x97\x8c\xc7\xbd\xf7\xb8\x3b\xd0\x5b\xd5\x66\x42\xe4\x3d\x49\x45\x50\xf2\x50\x13\xaa\x12\x6d\xa7\x40\x9f\xd5\xa0\x06\x04\x9e\xbd\x5e\x67\x33\x46\xd6\xcb\x74\x66\xb3\x5e\x18\x89\xc0\xbe\xb0\x06\xd6\x45\xfe\xe2\x80\x8f\xbe\x6a\x64\x7f\xc2\x3b\x9d\xd0\x53\x49\x3d\xb5\x39\xed\x7e\x62\x4d\x8a\xae\x45\x41\x35\x29\x42\x2e\x87\x5c\x16\xf4\x22\x70\x97\xc3\xf7\x78\xd1\x0b\x4f\xde\x99\xe5\x51\xdb\x2e\x68\x8f\x75\xbd\x41\x66\x65\x3d\x46\x44\x7b\xe5\x25\x8e\xd7\x19\x37\x1b\xcf\xf7\x11\x3f\xde\xf1\x1f\x96\x43\xf0\x41\x4f\x7e\x75\xf1\x5f\x0b\x05\x95\xf2\x58\x2b\xbc\x6e\xd5\xfd\x27\xf3\x54\xf9\x61\x3a\x7f\x4b\x52\x91\x66\x42\xe4\x5d\xd3\x54\x2a\x13\xbf\x03\x00\x93\x07\xb7\x49\x43\x01\x00\x00")
+
+func _1528395615_add_repo_update_scheduleUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395615_add_repo_update_scheduleUpSql,
+		"1528395615_add_repo_update_schedule.up.sql",
+	)
+}
+
+func _1528395615_add_repo_update_scheduleUpSql() (*asset, error) {
+	bytes, err := _1528395615_add_repo_update_scheduleUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395615_add_repo_update_schedule.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x63, 0x56, 0xe7, 0xdc, 0x1, 0x67, 0xe2, 0x2d, 0xd9, 0xc1, 0xc6, 0x10, 0xb4, 0xfc, 0x84, 0xa9, 0xe3, 0xb6, 0xa5, 0x9a, 0x8f, 0xad, 0x1e, 0xa6, 0x25, 0xf0, 0xfd, 0x47, 0x69, 0x6a, 0xea, 0x41}}
+	return a, nil
+}
+
+var __1528395616_add_repo_description_trgmDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3d\x00\xc2\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x64\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x5f\x74\x72\x67\x6d\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x68\x1d\xba\x74\x3d\x00\x00\x00")
+
+func _1528395616_add_repo_description_trgmDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395616_add_repo_description_trgmDownSql,
+		"1528395616_add_repo_description_trgm.down.sql",
+	)
+}
+
+func _1528395616_add_repo_description_trgmDownSql() (*asset, error) {
+	bytes, err := _1528395616_add_repo_description_trgmDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395616_add_repo_description_trgm.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x27, 0xae, 0x49, 0xcb, 0x7b, 0x74, 0x5, 0x71, 0xdf, 0x47, 0x9c, 0x9c, 0x4f, 0xea, 0xaf, 0xb7, 0x47, 0x69, 0xb0, 0x89, 0x66, 0xb8, 0x48, 0x89, 0xfb, 0xf1, 0x3a, 0x2d, 0xdc, 0xcc, 0x84, 0xa8}}
+	return a, nil
+}
+
+var __1528395616_add_repo_description_trgmUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x77\x00\x88\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x43\x52\x45\x41\x54\x45\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x64\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x5f\x74\x72\x67\x6d\x20\x4f\x4e\x20\x72\x65\x70\x6f\x20\x55\x53\x49\x4e\x47\x20\x67\x69\x6e\x20\x28\x6c\x6f\x77\x65\x72\x28\x64\x65\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x29\x20\x67\x69\x6e\x5f\x74\x72\x67\x6d\x5f\x6f\x70\x73\x29\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xc7\x73\xdb\x9c\x77\x00\x00\x00")
+
+func _1528395616_add_repo_description_trgmUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395616_add_repo_description_trgmUpSql,
+		"1528395616_add_repo_description_trgm.up.sql",
+	)
+}
+
+func _1528395616_add_repo_description_trgmUpSql() (*asset, error) {
+	bytes, err := _1528395616_add_repo_description_trgmUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395616_add_repo_description_trgm.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdf, 0x2c, 0xc8, 0xf0, 0x78, 0x4, 0xf3, 0x9b, 0xfe, 0x2e, 0x13, 0x5b, 0xc4, 0x38, 0x90, 0x6e, 0x71, 0x62, 0xa1, 0x5f, 0x58, 0x71, 0x45, 0x41, 0x89, 0xd, 0xdf, 0x13, 0xda, 0x5e, 0xa5, 0xf7}}
+	return a, nil
+}
+
+var __1528395617_add_labels_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x74\x00\x8b\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x73\x5f\x6c\x61\x62\x65\x6c\x73\x5f\x67\x69\x6e\x5f\x69\x64\x78\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x61\x62\x65\x6c\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xc3\x4a\xfc\x36\x74\x00\x00\x00")
+
+func _1528395617_add_labels_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395617_add_labels_to_campaignsDownSql,
+		"1528395617_add_labels_to_campaigns.down.sql",
+	)
+}
+
+func _1528395617_add_labels_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395617_add_labels_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395617_add_labels_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x57, 0x95, 0xe3, 0x88, 0xac, 0x38, 0xf9, 0x73, 0xb7, 0x47, 0x78, 0x70, 0x5e, 0x6c, 0xf0, 0x99, 0x6, 0x51, 0x71, 0x7c, 0xba, 0xa9, 0xc0, 0x2c, 0xb9, 0xe1, 0x5d, 0xd6, 0x98, 0x77, 0x9d, 0xbb}}
+	return a, nil
+}
+
+var __1528395617_add_labels_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\xcd\xcd\x8a\x83\x30\x14\x05\xe0\x7d\x9e\xe2\xec\x9c\x79\x86\xac\xa2\xb9\x4a\x20\xde\x80\xde\x80\x30\x0c\x62\x5b\x91\x80\x95\x82\x2e\x84\xd2\x77\x2f\x54\x28\xa5\xeb\xf3\xf3\xe5\x54\x39\xd6\x4a\x19\x2f\xd4\x40\x4c\xee\x09\xe7\xe1\x7a\x1b\xd2\xb4\xac\x30\xd6\xa2\x08\x3e\xd6\x0c\x57\x82\x83\x80\x3a\xd7\x4a\x8b\x79\x38\x8d\xf3\x8a\x6d\xdc\xb7\xbf\xff\x57\xc0\xd1\x7b\x58\x2a\x4d\xf4\x82\xec\xfe\xc8\xb4\x2a\x1a\x32\x42\x70\x6c\xa9\xfb\xda\xbf\x89\xfe\x78\xea\xa7\xb4\xf4\xe9\xb2\x23\xf0\x07\x1f\x5b\xc7\x15\xa6\xb4\xe0\xe7\xa8\xfd\x6a\xa5\x8a\x50\xd7\x4e\xb4\x7a\x0e\x00\xce\x5d\x15\x8b\xba\x00\x00\x00")
+
+func _1528395617_add_labels_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395617_add_labels_to_campaignsUpSql,
+		"1528395617_add_labels_to_campaigns.up.sql",
+	)
+}
+
+func _1528395617_add_labels_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395617_add_labels_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395617_add_labels_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8d, 0x46, 0x6a, 0xec, 0xe7, 0x88, 0xf2, 0xc, 0xd8, 0x95, 0x0, 0xe, 0xee, 0x35, 0x53, 0xcc, 0x20, 0xfe, 0xce, 0x12, 0x91, 0xe0, 0xc4, 0x73, 0x22, 0x27, 0x4b, 0x0, 0xde, 0xac, 0x56, 0x16}}
+	return a, nil
+}
+
+var __1528395618_add_webhook_to_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4f\x4d\xca\xc8\xcf\xcf\x8e\x2f\x2d\xca\xb1\x26\x4b\x63\x71\x6a\x72\x51\x6a\x89\x35\x17\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x60\x00\xc9\x5d\xa4\xe4\x86\x00\x00\x00")
+
+func _1528395618_add_webhook_to_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395618_add_webhook_to_campaignsDownSql,
+		"1528395618_add_webhook_to_campaigns.down.sql",
+	)
+}
+
+func _1528395618_add_webhook_to_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395618_add_webhook_to_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395618_add_webhook_to_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xef, 0xb1, 0x4a, 0x42, 0x25, 0x56, 0x5b, 0xeb, 0x1a, 0x21, 0x5c, 0x75, 0x6f, 0x6e, 0x97, 0x42, 0xde, 0x1c, 0x9b, 0xd9, 0x5b, 0xe7, 0xe, 0xa2, 0x2, 0x68, 0x4, 0x27, 0x34, 0xe3, 0xb0, 0x5a}}
+	return a, nil
+}
+
+var __1528395618_add_webhook_to_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4e\xcc\x2d\x48\xcc\x4c\xcf\x2b\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4f\x4d\xca\xc8\xcf\xcf\x8e\x2f\x2d\xca\x51\x28\x49\xad\x28\x01\xcb\xf9\x85\xfa\xf8\x28\xb8\xb8\xba\x39\x86\xfa\x84\x28\xa8\xab\x5b\x93\x6b\x68\x71\x6a\x72\x51\x6a\x09\x1e\x73\xb9\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x03\x00\x10\xf6\x7c\x92\xbe\x00\x00\x00")
+
+func _1528395618_add_webhook_to_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395618_add_webhook_to_campaignsUpSql,
+		"1528395618_add_webhook_to_campaigns.up.sql",
+	)
+}
+
+func _1528395618_add_webhook_to_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395618_add_webhook_to_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395618_add_webhook_to_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5e, 0x87, 0xac, 0x32, 0x40, 0x32, 0x2e, 0xf2, 0x8c, 0xc1, 0x1a, 0x48, 0x80, 0xb8, 0x0, 0x5e, 0xb0, 0x39, 0xa9, 0x8c, 0xfd, 0x4f, 0x19, 0xad, 0x9a, 0x21, 0x12, 0x17, 0x98, 0xd1, 0x89, 0xa6}}
+	return a, nil
+}
+
+var __1528395619_add_search_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5c\x00\xa3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x61\x72\x63\x68\x5f\x6a\x6f\x62\x5f\x72\x65\x73\x75\x6c\x74\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x61\x72\x63\x68\x5f\x6a\x6f\x62\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xe0\xa1\x5d\x4d\x5c\x00\x00\x00")
+
+func _1528395619_add_search_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395619_add_search_jobsDownSql,
+		"1528395619_add_search_jobs.down.sql",
+	)
+}
+
+func _1528395619_add_search_jobsDownSql() (*asset, error) {
+	bytes, err := _1528395619_add_search_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395619_add_search_jobs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1b, 0xd8, 0xc3, 0x2b, 0xe8, 0x63, 0x11, 0xb0, 0x19, 0xa6, 0x5e, 0xa3, 0x11, 0x8b, 0x34, 0x62, 0xc1, 0x96, 0x61, 0x2d, 0x1, 0xa8, 0xc5, 0x9, 0xb5, 0x51, 0x6d, 0x3c, 0x8f, 0x4e, 0x74, 0xcb}}
+	return a, nil
+}
+
+var __1528395619_add_search_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\xc1\x6e\x9c\x30\x10\xbd\xf3\x15\x73\x03\xa4\x3d\xf4\xbe\x27\xc2\xce\xb6\x28\xac\xa9\x80\x48\x89\xaa\xca\x22\x66\x36\xeb\x74\xd7\x26\xb6\x69\x9a\x56\xfd\xf7\x0a\x93\xcd\xd2\xd0\x26\x91\x72\xf4\xf3\x9b\xe7\xf1\xcc\x7b\x67\xf8\x31\x63\xcb\x20\x48\x4b\x4c\x6a\x84\x3a\x39\xcb\x11\xb2\x35\xb0\xa2\x06\xbc\xcc\xaa\xba\x02\x4b\x8d\x11\x3b\x7e\xab\xaf\x2d\x44\x01\x80\x6c\xc1\x92\x91\xcd\x1e\x3e\x97\xd9\x26\x29\xaf\xe0\x1c\xaf\x16\x01\x40\x6f\xc9\x70\xd9\x82\x54\x8e\x6e\xc8\x78\x0d\x76\x91\xe7\x50\xe2\x1a\x4b\x64\x29\x56\x9e\x63\x23\xd9\xc6\x50\x30\x58\x61\x8e\x35\x42\x9a\x54\x69\xb2\xc2\x41\xe2\xae\x27\xf3\x00\x8e\x7e\xb8\xa7\xea\x01\xee\x1a\xe7\xc8\x28\xee\x1e\x3a\x9a\xdf\x5a\xd7\xb8\x67\x30\xac\x70\x9d\x5c\xe4\x35\x84\x77\x3d\xf5\xd4\x86\x03\xcf\x50\xa7\xb9\xa1\xef\x16\x6e\xad\x56\xd7\x47\xc8\xf2\xf1\x87\xf4\x8f\xce\x8f\x32\x1f\x46\xb2\xed\xf7\x8e\x0b\xdd\x2b\xf7\x0a\x55\x2a\xa1\x0f\xdd\x9e\x1c\x71\xff\x84\xef\xee\xcb\xd7\x39\x3b\xfc\xf5\xdb\xf7\x46\xc6\x68\xe3\x59\xc3\x49\x18\x6a\x1c\xb5\xbc\x71\xe0\xe4\x81\xac\x6b\x0e\x1d\xdc\x4b\xb7\xf3\x47\xf8\xa9\x15\xcd\xa5\x94\xbe\x8f\xe2\xa1\xba\xef\xda\x77\x54\x6f\xa5\x92\x76\xf7\x72\xf9\xf0\x4a\x5a\xb0\xaa\x2e\x93\x8c\xd5\x53\x87\x70\xbf\x0c\x2e\x76\x24\xbe\x41\xfa\x09\xd3\x73\x88\x3c\x04\x19\x83\xe8\x69\x1b\x10\x76\x46\x0b\xb2\x56\xaa\x9b\x70\x01\xe1\x71\x5a\xfe\xca\xcf\x62\xd8\x19\x84\xa2\x51\x82\xf6\xd4\x86\x71\x1c\xc4\x27\x9b\x66\x6c\x85\x97\xff\xb7\x29\x3f\x3a\xb1\x60\x53\x38\x7a\x84\xe3\xe5\x5b\x75\xc6\xce\x9f\xa9\x78\x30\x7e\x6b\x66\xf8\xe8\x9a\x31\x3a\x13\xf8\x95\x9c\x9c\x98\x2f\xa4\x65\xb0\x16\xd7\xdb\xad\xa5\xb9\x1f\x4f\x86\xb5\xf3\xc4\x4c\x82\x0b\xd1\x5f\x4d\x2d\xa6\xa2\x8f\x33\x2f\x36\x9b\xac\x5e\x06\x7f\x06\x00\xc1\xb0\x86\xfb\x2b\x04\x00\x00")
+
+func _1528395619_add_search_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395619_add_search_jobsUpSql,
+		"1528395619_add_search_jobs.up.sql",
+	)
+}
+
+func _1528395619_add_search_jobsUpSql() (*asset, error) {
+	bytes, err := _1528395619_add_search_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395619_add_search_jobs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe5, 0x69, 0x2e, 0xa5, 0x5f, 0x9, 0xcc, 0xfb, 0xd7, 0xcb, 0x1f, 0x51, 0xfa, 0x7a, 0xe5, 0x77, 0x1e, 0x36, 0x2, 0xf, 0x69, 0x5b, 0x9c, 0xdc, 0xf1, 0x8b, 0x6c, 0x7e, 0x6f, 0xb3, 0x62, 0xc1}}
+	return a, nil
+}
+
+var __1528395620_add_external_service_config_revisionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\xc1\x6e\xdb\x30\x10\x44\xef\xfc\x8a\x39\x5a\x80\x53\xa0\x67\x9f\x14\x79\x5d\x10\x95\xa9\x56\xa2\x81\xe6\x24\xa8\xd6\xda\x5a\x20\x21\x03\x92\x4a\x8c\x7e\x7d\x21\x27\x96\x83\xba\x68\x8f\xe4\x0e\xdf\xcc\x0e\xef\xe9\x8b\x36\x2b\xa5\x8a\x9a\x72\x4b\xb0\xf9\x7d\x49\xd0\x1b\x98\xca\x82\x7e\xe8\xc6\x36\xe0\x53\xe2\xe0\xba\xc7\x36\x72\x78\x91\x3d\xb7\x7b\xef\x0e\x72\x6c\x03\xbf\x48\x14\xef\x22\x16\x0a\x90\x1e\x3f\xe5\x18\x39\x48\xf7\x88\x6f\xb5\xde\xe6\xf5\x03\xbe\xd2\xc3\x52\xe1\x96\xf0\x26\x16\x97\xce\x3e\x66\x57\x96\xa8\x69\x43\x35\x99\x82\x6e\x0d\xe3\x42\xfa\x0c\x95\xc1\x9a\x4a\xb2\x84\x22\x6f\x8a\x7c\x4d\x13\xf9\x92\x01\xe2\x12\x1f\x39\xcc\xbc\x69\xd8\x8d\x69\xf0\xa1\x1d\x23\x87\x56\xfa\x59\xf2\xc1\x69\x1a\xfd\x49\x6f\xe8\x4a\x78\xdb\x14\x89\x4f\xd7\xa4\xe7\xfb\xc0\x5d\xe2\xbe\xed\x12\x92\x3c\x71\x4c\xdd\xd3\x33\x5e\x25\x0d\xe7\x23\x7e\x79\xc7\xb3\x1e\x6b\xda\xe4\xbb\xd2\xc2\xf9\xd7\x45\x36\xbd\xde\x19\xfd\x7d\x47\x58\xfc\xa5\x96\xe5\xbc\x51\xa6\xb2\x95\x52\x77\x77\xb0\x03\x63\x3f\x86\xc0\x2e\xbd\xe7\x19\x43\x97\xc4\x3b\xf8\x03\xf8\x24\x31\x89\x3b\xce\x9d\xe1\xd2\x19\x24\x22\x0d\x2c\x01\x07\x09\x31\x4d\xa4\xc0\x7b\x1f\x7a\xee\x67\x8f\x4f\x4a\x9b\x86\x6a\x0b\x6d\x6c\xf5\xff\x7f\xfe\x77\xe0\xe5\x7b\xba\xe5\x87\x76\x32\xd5\x50\x49\x85\xc5\x24\xfc\x7c\x55\x8c\xcf\xfd\xa5\xbf\x4d\x5d\x6d\x6f\xac\xe3\x4a\xa9\xa2\xda\x6e\xb5\x5d\xa9\xdf\x03\x00\xa0\x6f\x9c\x02\xa2\x02\x00\x00")
+
+func _1528395620_add_external_service_config_revisionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395620_add_external_service_config_revisionsUpSql,
+		"1528395620_add_external_service_config_revisions.up.sql",
+	)
+}
+
+func _1528395620_add_external_service_config_revisionsUpSql() (*asset, error) {
+	bytes, err := _1528395620_add_external_service_config_revisionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395620_add_external_service_config_revisions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1f, 0xcf, 0xac, 0x2a, 0x10, 0x17, 0xe2, 0x91, 0x94, 0x94, 0xa3, 0xc2, 0xcc, 0xa9, 0x4f, 0x54, 0x80, 0x97, 0x70, 0xec, 0x32, 0x41, 0x16, 0xd2, 0xb9, 0x12, 0xe4, 0xb4, 0x5e, 0xac, 0x55, 0xc4}}
+	return a, nil
+}
+
+var __1528395620_add_external_service_config_revisionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x49\x00\xb6\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x78\x74\x65\x72\x6e\x61\x6c\x5f\x73\x65\x72\x76\x69\x63\x65\x5f\x63\x6f\x6e\x66\x69\x67\x5f\x72\x65\x76\x69\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xd0\x8f\x4a\x6b\x49\x00\x00\x00")
+
+func _1528395620_add_external_service_config_revisionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395620_add_external_service_config_revisionsDownSql,
+		"1528395620_add_external_service_config_revisions.down.sql",
+	)
+}
+
+func _1528395620_add_external_service_config_revisionsDownSql() (*asset, error) {
+	bytes, err := _1528395620_add_external_service_config_revisionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395620_add_external_service_config_revisions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc8, 0xf4, 0x37, 0x14, 0x1e, 0x39, 0x64, 0xd1, 0x7d, 0x6a, 0x88, 0x36, 0xb, 0x96, 0x2e, 0x2e, 0x64, 0x14, 0xc2, 0x57, 0xc8, 0x25, 0x22, 0x78, 0xa2, 0x7f, 0x2f, 0xf8, 0x8e, 0x69, 0x15, 0xe9}}
+	return a, nil
+}
+
+var __1528395621_add_repo_introductionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3a\x00\xc5\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x69\x6e\x74\x72\x6f\x64\x75\x63\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xb1\xa3\x05\x45\x3a\x00\x00\x00")
+
+func _1528395621_add_repo_introductionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395621_add_repo_introductionsDownSql,
+		"1528395621_add_repo_introductions.down.sql",
+	)
+}
+
+func _1528395621_add_repo_introductionsDownSql() (*asset, error) {
+	bytes, err := _1528395621_add_repo_introductionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395621_add_repo_introductions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x39, 0xf6, 0x69, 0xb9, 0xbf, 0xb9, 0xd6, 0x6a, 0x70, 0xdc, 0x4c, 0xd5, 0x8e, 0x91, 0x1f, 0x8a, 0x37, 0x6e, 0x5d, 0x8f, 0xe8, 0x3f, 0x77, 0x12, 0x3d, 0xf1, 0x12, 0x5f, 0xf6, 0x36, 0x5c, 0x5e}}
+	return a, nil
+}
+
+var __1528395621_add_repo_introductionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x93\x41\x6f\xe2\x3e\x10\xc5\xef\xfe\x14\x73\xf9\x0b\x90\xd2\x5c\xfe\x37\x38\x51\x30\xad\xd5\x90\xec\x42\x50\xdb\x53\x64\x92\x81\x78\x09\x76\x64\x3b\x0d\xd9\x4f\xbf\x72\x12\x68\xd9\x46\xda\x9b\xb1\x67\xde\x7b\xcc\xfc\xf2\x48\x9f\x58\x38\x23\xe4\xe1\x01\x34\x96\x2a\x11\xd2\x6a\x95\x55\xa9\x15\x4a\x1a\x10\x06\xb8\x04\x5e\x96\x28\xb3\x07\x25\x8b\x06\x34\xa6\x4a\x67\xa0\x0e\x50\xe7\x28\x01\x79\x9a\x03\x5e\x2c\x6a\xc9\x0b\x30\xa8\x3f\x44\x8a\x4e\xec\x20\xb4\xb1\xd0\x08\x2c\x32\xcc\xba\x32\xa7\x6f\x84\x55\xba\xf1\x81\x59\xc8\xb0\x10\x7b\xd4\xdc\x62\xd1\x40\xce\x0d\x48\x05\x07\xa5\x51\x1c\x25\x9c\xb0\x31\x1e\x18\x05\x36\x6f\xd5\xae\xae\x95\x2d\xc4\x07\x1a\x77\xfd\x29\x27\xd0\x78\xdf\x32\xb8\xe0\x19\x54\x06\xb5\x01\x61\x41\xe3\xc1\x9d\xac\xf2\xc9\x62\x43\xe7\x31\x85\x78\xfe\x18\x50\x60\x2b\x08\xa3\x18\xe8\x1b\xdb\xc6\xdb\xa1\x09\x8c\x09\x80\xc8\x60\x2f\x8e\x06\xb5\xe0\x05\xfc\xd8\xb0\xf5\x7c\xf3\x0e\x2f\xf4\xdd\x23\xd0\xb7\x64\x20\xa4\xc5\x23\xea\x56\x2d\xdc\x05\xc1\xed\x4d\xf2\x33\x42\x2a\x2c\x5e\xec\xdd\xe3\x35\x70\xd2\x07\x4e\x3a\x17\x21\xef\xcb\x78\x65\x73\xa5\x13\xf7\x47\xbe\xd8\xb8\x97\x54\x23\xb7\x98\x25\xdc\x82\x15\x67\x34\x96\x9f\x4b\xa8\x85\xcd\xdb\x9f\xf0\x5b\x49\xbc\x29\xc1\x92\xae\xe6\xbb\x20\x06\xa9\xea\xf1\xc4\x75\xef\x42\xf6\x73\x47\x61\xdc\xe7\xf7\x86\xf2\x4c\xc8\x64\x46\xae\xf3\x62\xe1\x92\xbe\xfd\x73\x5e\xc9\x80\x4c\x22\xb2\x0b\x44\xe1\x40\xf5\x78\xc8\xb4\x83\xf1\x91\xa7\xa7\x83\x28\x8a\x6f\xab\x06\x9b\x73\x0b\x78\x11\xc6\x0a\x79\x1c\xd8\x7b\xcb\x9c\x0f\xaf\x0e\x4f\x9b\x63\xe3\xd4\x6a\xd4\xd8\x23\x69\x10\x65\xcb\x75\x59\x6a\x75\x11\x67\x37\x43\xd8\x37\x1d\xce\x77\x66\x0d\xd4\xdc\x5c\xc7\xec\xb5\x3c\xd5\xb9\x72\x72\xa9\x92\x07\x71\xac\x34\x66\x6d\xbc\xbf\x33\xb8\xcb\xd6\xa3\x92\x27\xa9\x6a\xe9\x13\x16\x6e\xe9\x26\x06\x16\xc6\xd1\xd0\x18\x6e\x4b\xb8\x11\x33\xb8\x0f\xef\xcb\xce\x27\x64\x4b\x03\xba\x88\x5b\x39\xff\xda\xeb\x77\xbd\xa6\x2c\x84\x4d\x4a\xae\xed\xd8\xa8\x4a\xa7\x68\xfc\x13\x36\x1e\x8c\xa6\x23\x0f\xfe\x9f\x4c\xa7\x1d\x68\x7d\xcf\xa7\x28\x59\x6d\xa2\x75\x7b\xe9\xc1\x2f\xa3\xe4\x3e\x71\x1f\x6e\xcb\x88\xdf\x0b\x4d\x60\xbe\x85\xfe\x4c\x5e\x9f\xe9\x86\xb6\xf5\x7e\x86\x05\xf6\x34\xb2\x6d\xc7\xdc\x3c\x5c\x5e\x2b\x9d\x3d\x04\xec\x85\xc2\x08\x2f\xd6\x7c\xa4\xd3\xff\x46\x24\x0a\x61\x11\x85\xab\x80\x2d\x62\x58\x46\x8e\xd5\x67\x16\x3e\xcd\x08\x59\x44\xeb\x35\x8b\x67\xe4\xcf\x00\xc2\x6c\xca\x55\x9c\x04\x00\x00")
+
+func _1528395621_add_repo_introductionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395621_add_repo_introductionsUpSql,
+		"1528395621_add_repo_introductions.up.sql",
+	)
+}
+
+func _1528395621_add_repo_introductionsUpSql() (*asset, error) {
+	bytes, err := _1528395621_add_repo_introductionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395621_add_repo_introductions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbe, 0x2d, 0x70, 0x8a, 0x37, 0x4e, 0xb3, 0x87, 0x3, 0x2c, 0xf5, 0x93, 0xf7, 0xb, 0x4, 0xbb, 0xcd, 0x72, 0x4b, 0xf1, 0xac, 0xfa, 0x97, 0xc8, 0x82, 0x38, 0xba, 0x28, 0xb9, 0xfd, 0xf2, 0x1b}}
+	return a, nil
+}
+
+var __1528395622_add_campaigns_closed_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x48\x00\xb7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x63\x6c\x6f\x73\x65\x64\x5f\x61\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x43\x04\x8a\xa2\x48\x00\x00\x00")
+
+func _1528395622_add_campaigns_closed_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395622_add_campaigns_closed_atDownSql,
+		"1528395622_add_campaigns_closed_at.down.sql",
+	)
+}
+
+func _1528395622_add_campaigns_closed_atDownSql() (*asset, error) {
+	bytes, err := _1528395622_add_campaigns_closed_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395622_add_campaigns_closed_at.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0xe0, 0x5a, 0x76, 0x61, 0x2d, 0xbf, 0x9a, 0xa3, 0x27, 0x6e, 0x32, 0xd7, 0xa2, 0xfb, 0xae, 0x90, 0x80, 0x11, 0x51, 0x69, 0xb7, 0x91, 0x6e, 0x9e, 0xb7, 0xe1, 0x5b, 0xe0, 0x5, 0xa6, 0x58}}
+	return a, nil
+}
+
+var __1528395622_add_campaigns_closed_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x64\x00\x9b\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x61\x6d\x70\x61\x69\x67\x6e\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x63\x6c\x6f\x73\x65\x64\x5f\x61\x74\x20\x74\x69\x6d\x65\x73\x74\x61\x6d\x70\x20\x77\x69\x74\x68\x20\x74\x69\x6d\x65\x20\x7a\x6f\x6e\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x73\xf1\xe2\xb7\x64\x00\x00\x00")
+
+func _1528395622_add_campaigns_closed_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395622_add_campaigns_closed_atUpSql,
+		"1528395622_add_campaigns_closed_at.up.sql",
+	)
+}
+
+func _1528395622_add_campaigns_closed_atUpSql() (*asset, error) {
+	bytes, err := _1528395622_add_campaigns_closed_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395622_add_campaigns_closed_at.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x37, 0xd2, 0x76, 0xd6, 0x23, 0xdb, 0xdc, 0x9e, 0xc0, 0xc9, 0x94, 0x23, 0xca, 0x24, 0x0, 0xe1, 0xc9, 0x21, 0x2c, 0xdd, 0x81, 0xa0, 0xa8, 0xb5, 0xd0, 0x5d, 0x9c, 0xed, 0x1b, 0xaf, 0x31, 0x63}}
+	return a, nil
+}
+
+var __1528395623_drop_changeset_templates_from_campaignsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\xce\xc1\x0a\x82\x40\x10\xc6\xf1\xfb\x3e\xc5\xdc\x7c\x08\x4f\xab\xae\xb1\xb0\xae\x90\x23\x04\x11\x32\xda\x60\xc2\x6a\xc2\xce\xa1\x88\xde\x3d\xf0\x1c\x04\x45\xf7\x8f\xff\xef\xcb\xcc\xce\xfa\x54\x29\xed\xd0\xec\x01\x75\xe6\x0c\x0c\x34\xaf\x34\x8d\x4b\x04\x5d\x14\x90\xd7\xae\xad\x3c\xd8\x12\x7c\x8d\x60\x0e\xb6\xc1\x06\x86\x0b\x2d\x23\x47\x96\x4e\x26\x09\xdc\x09\xcf\x6b\x20\x61\x10\xbe\xc9\x36\xf4\xad\x73\x50\x98\x52\xb7\x0e\x21\x49\xd2\xef\x85\xfe\x7a\xbe\xff\x15\x08\xd4\x73\x88\xdb\xf5\xe3\xe9\x4d\xfb\xf1\xfc\xa5\x4e\x31\x4e\xe3\xc2\xfc\x09\x50\x79\x5d\x55\x16\x53\xf5\x1a\x00\x30\xec\x08\x2f\x93\x01\x00\x00")
+
+func _1528395623_drop_changeset_templates_from_campaignsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395623_drop_changeset_templates_from_campaignsDownSql,
+		"1528395623_drop_changeset_templates_from_campaigns.down.sql",
+	)
+}
+
+func _1528395623_drop_changeset_templates_from_campaignsDownSql() (*asset, error) {
+	bytes, err := _1528395623_drop_changeset_templates_from_campaignsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395623_drop_changeset_templates_from_campaigns.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x46, 0xef, 0x7e, 0x46, 0xe2, 0x9a, 0xb1, 0x26, 0x1b, 0x63, 0x81, 0xa5, 0x86, 0xd, 0xa5, 0x89, 0xce, 0xbf, 0x6e, 0x25, 0x4e, 0x2f, 0x36, 0xe7, 0xae, 0x45, 0xcd, 0x12, 0x6d, 0xbc, 0x26}}
+	return a, nil
+}
+
+var __1528395623_drop_changeset_templates_from_campaignsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xcc\x51\x0e\x82\x30\x0c\x00\xd0\xff\x9e\xa2\xf7\xd8\x17\xe0\x34\x4b\x36\x66\x60\x26\xfe\x91\x82\xcd\x5c\x32\x26\x49\xfb\xe3\xed\xbd\x03\x1e\xe0\xbd\xde\xde\xdc\x68\x00\x3a\x9f\xec\x84\xa9\xeb\xbd\xc5\x8d\xf6\x83\x4a\x6e\x82\x97\x29\xde\x71\x88\xfe\x11\x46\x74\x57\xb4\x4f\x37\xa7\x19\xb7\x37\xb5\xcc\xc2\xba\x68\xd1\xca\x8b\xf2\x7e\x54\x52\x36\x27\x97\xf5\xf3\xfa\xfe\x9d\x54\x5a\xb9\xca\x59\x4d\x22\x25\x37\x66\x31\x00\x43\x0c\xc1\x25\x03\xbf\x01\x00\xd0\x85\xe9\xea\x1b\x01\x00\x00")
+
+func _1528395623_drop_changeset_templates_from_campaignsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395623_drop_changeset_templates_from_campaignsUpSql,
+		"1528395623_drop_changeset_templates_from_campaigns.up.sql",
+	)
+}
+
+func _1528395623_drop_changeset_templates_from_campaignsUpSql() (*asset, error) {
+	bytes, err := _1528395623_drop_changeset_templates_from_campaignsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395623_drop_changeset_templates_from_campaigns.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdc, 0xe0, 0x56, 0x1f, 0x6a, 0x2c, 0x87, 0x7e, 0xe9, 0xfe, 0x6e, 0x3a, 0xba, 0x89, 0x28, 0x6f, 0xf8, 0xdb, 0x61, 0x4, 0x32, 0x5d, 0xff, 0x67, 0xf7, 0x7d, 0xd2, 0x5d, 0x7b, 0x69, 0xf1, 0xeb}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -1308,6 +2022,74 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395606_lsif_add_visible_at_tip_flag.down.sql": _1528395606_lsif_add_visible_at_tip_flagDownSql,
 
 	"1528395606_lsif_add_visible_at_tip_flag.up.sql": _1528395606_lsif_add_visible_at_tip_flagUpSql,
+
+	"1528395607_add_retry_state_to_changesets.down.sql": _1528395607_add_retry_state_to_changesetsDownSql,
+
+	"1528395607_add_retry_state_to_changesets.up.sql": _1528395607_add_retry_state_to_changesetsUpSql,
+
+	"1528395608_add_archived_mirror_to_repo.down.sql": _1528395608_add_archived_mirror_to_repoDownSql,
+
+	"1528395608_add_archived_mirror_to_repo.up.sql": _1528395608_add_archived_mirror_to_repoUpSql,
+
+	"1528395609_add_visibility_override_to_repo.down.sql": _1528395609_add_visibility_override_to_repoDownSql,
+
+	"1528395609_add_visibility_override_to_repo.up.sql": _1528395609_add_visibility_override_to_repoUpSql,
+
+	"1528395610_create_search_history.down.sql": _1528395610_create_search_historyDownSql,
+
+	"1528395610_create_search_history.up.sql": _1528395610_create_search_historyUpSql,
+
+	"1528395611_add_changeset_templates_to_campaigns.down.sql": _1528395611_add_changeset_templates_to_campaignsDownSql,
+
+	"1528395611_add_changeset_templates_to_campaigns.up.sql": _1528395611_add_changeset_templates_to_campaignsUpSql,
+
+	"1528395612_add_search_index_exclusions.down.sql": _1528395612_add_search_index_exclusionsDownSql,
+
+	"1528395612_add_search_index_exclusions.up.sql": _1528395612_add_search_index_exclusionsUpSql,
+
+	"1528395613_add_repo_kvps.down.sql": _1528395613_add_repo_kvpsDownSql,
+
+	"1528395613_add_repo_kvps.up.sql": _1528395613_add_repo_kvpsUpSql,
+
+	"1528395614_add_repo_last_commit_at.down.sql": _1528395614_add_repo_last_commit_atDownSql,
+
+	"1528395614_add_repo_last_commit_at.up.sql": _1528395614_add_repo_last_commit_atUpSql,
+
+	"1528395615_add_repo_update_schedule.down.sql": _1528395615_add_repo_update_scheduleDownSql,
+
+	"1528395615_add_repo_update_schedule.up.sql": _1528395615_add_repo_update_scheduleUpSql,
+
+	"1528395616_add_repo_description_trgm.down.sql": _1528395616_add_repo_description_trgmDownSql,
+
+	"1528395616_add_repo_description_trgm.up.sql": _1528395616_add_repo_description_trgmUpSql,
+
+	"1528395617_add_labels_to_campaigns.down.sql": _1528395617_add_labels_to_campaignsDownSql,
+
+	"1528395617_add_labels_to_campaigns.up.sql": _1528395617_add_labels_to_campaignsUpSql,
+
+	"1528395618_add_webhook_to_campaigns.down.sql": _1528395618_add_webhook_to_campaignsDownSql,
+
+	"1528395618_add_webhook_to_campaigns.up.sql": _1528395618_add_webhook_to_campaignsUpSql,
+
+	"1528395619_add_search_jobs.down.sql": _1528395619_add_search_jobsDownSql,
+
+	"1528395619_add_search_jobs.up.sql": _1528395619_add_search_jobsUpSql,
+
+	"1528395620_add_external_service_config_revisions.up.sql": _1528395620_add_external_service_config_revisionsUpSql,
+
+	"1528395620_add_external_service_config_revisions.down.sql": _1528395620_add_external_service_config_revisionsDownSql,
+
+	"1528395621_add_repo_introductions.down.sql": _1528395621_add_repo_introductionsDownSql,
+
+	"1528395621_add_repo_introductions.up.sql": _1528395621_add_repo_introductionsUpSql,
+
+	"1528395622_add_campaigns_closed_at.down.sql": _1528395622_add_campaigns_closed_atDownSql,
+
+	"1528395622_add_campaigns_closed_at.up.sql": _1528395622_add_campaigns_closed_atUpSql,
+
+	"1528395623_drop_changeset_templates_from_campaigns.down.sql": _1528395623_drop_changeset_templates_from_campaignsDownSql,
+
+	"1528395623_drop_changeset_templates_from_campaigns.up.sql": _1528395623_drop_changeset_templates_from_campaignsUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -1401,6 +2183,40 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395605_drop_recent_searches.up.sql":                                   {_1528395605_drop_recent_searchesUpSql, map[string]*bintree{}},
 	"1528395606_lsif_add_visible_at_tip_flag.down.sql":                         {_1528395606_lsif_add_visible_at_tip_flagDownSql, map[string]*bintree{}},
 	"1528395606_lsif_add_visible_at_tip_flag.up.sql":                           {_1528395606_lsif_add_visible_at_tip_flagUpSql, map[string]*bintree{}},
+	"1528395607_add_retry_state_to_changesets.down.sql":                        {_1528395607_add_retry_state_to_changesetsDownSql, map[string]*bintree{}},
+	"1528395607_add_retry_state_to_changesets.up.sql":                          {_1528395607_add_retry_state_to_changesetsUpSql, map[string]*bintree{}},
+	"1528395608_add_archived_mirror_to_repo.down.sql":                          {_1528395608_add_archived_mirror_to_repoDownSql, map[string]*bintree{}},
+	"1528395608_add_archived_mirror_to_repo.up.sql":                            {_1528395608_add_archived_mirror_to_repoUpSql, map[string]*bintree{}},
+	"1528395609_add_visibility_override_to_repo.down.sql":                      {_1528395609_add_visibility_override_to_repoDownSql, map[string]*bintree{}},
+	"1528395609_add_visibility_override_to_repo.up.sql":                        {_1528395609_add_visibility_override_to_repoUpSql, map[string]*bintree{}},
+	"1528395610_create_search_history.down.sql":                                {_1528395610_create_search_historyDownSql, map[string]*bintree{}},
+	"1528395610_create_search_history.up.sql":                                  {_1528395610_create_search_historyUpSql, map[string]*bintree{}},
+	"1528395611_add_changeset_templates_to_campaigns.down.sql":                 {_1528395611_add_changeset_templates_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395611_add_changeset_templates_to_campaigns.up.sql":                   {_1528395611_add_changeset_templates_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395612_add_search_index_exclusions.down.sql":                          {_1528395612_add_search_index_exclusionsDownSql, map[string]*bintree{}},
+	"1528395612_add_search_index_exclusions.up.sql":                            {_1528395612_add_search_index_exclusionsUpSql, map[string]*bintree{}},
+	"1528395613_add_repo_kvps.down.sql":                                        {_1528395613_add_repo_kvpsDownSql, map[string]*bintree{}},
+	"1528395613_add_repo_kvps.up.sql":                                          {_1528395613_add_repo_kvpsUpSql, map[string]*bintree{}},
+	"1528395614_add_repo_last_commit_at.down.sql":                              {_1528395614_add_repo_last_commit_atDownSql, map[string]*bintree{}},
+	"1528395614_add_repo_last_commit_at.up.sql":                                {_1528395614_add_repo_last_commit_atUpSql, map[string]*bintree{}},
+	"1528395615_add_repo_update_schedule.down.sql":                             {_1528395615_add_repo_update_scheduleDownSql, map[string]*bintree{}},
+	"1528395615_add_repo_update_schedule.up.sql":                               {_1528395615_add_repo_update_scheduleUpSql, map[string]*bintree{}},
+	"1528395616_add_repo_description_trgm.down.sql":                            {_1528395616_add_repo_description_trgmDownSql, map[string]*bintree{}},
+	"1528395616_add_repo_description_trgm.up.sql":                              {_1528395616_add_repo_description_trgmUpSql, map[string]*bintree{}},
+	"1528395617_add_labels_to_campaigns.down.sql":                              {_1528395617_add_labels_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395617_add_labels_to_campaigns.up.sql":                                {_1528395617_add_labels_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395618_add_webhook_to_campaigns.down.sql":                             {_1528395618_add_webhook_to_campaignsDownSql, map[string]*bintree{}},
+	"1528395618_add_webhook_to_campaigns.up.sql":                               {_1528395618_add_webhook_to_campaignsUpSql, map[string]*bintree{}},
+	"1528395619_add_search_jobs.down.sql":                                      {_1528395619_add_search_jobsDownSql, map[string]*bintree{}},
+	"1528395619_add_search_jobs.up.sql":                                        {_1528395619_add_search_jobsUpSql, map[string]*bintree{}},
+	"1528395620_add_external_service_config_revisions.up.sql":                  {_1528395620_add_external_service_config_revisionsUpSql, map[string]*bintree{}},
+	"1528395620_add_external_service_config_revisions.down.sql":                {_1528395620_add_external_service_config_revisionsDownSql, map[string]*bintree{}},
+	"1528395621_add_repo_introductions.down.sql":                               {_1528395621_add_repo_introductionsDownSql, map[string]*bintree{}},
+	"1528395621_add_repo_introductions.up.sql":                                 {_1528395621_add_repo_introductionsUpSql, map[string]*bintree{}},
+	"1528395622_add_campaigns_closed_at.down.sql":                              {_1528395622_add_campaigns_closed_atDownSql, map[string]*bintree{}},
+	"1528395622_add_campaigns_closed_at.up.sql":                                {_1528395622_add_campaigns_closed_atUpSql, map[string]*bintree{}},
+	"1528395623_drop_changeset_templates_from_campaigns.down.sql":              {_1528395623_drop_changeset_templates_from_campaignsDownSql, map[string]*bintree{}},
+	"1528395623_drop_changeset_templates_from_campaigns.up.sql":                {_1528395623_drop_changeset_templates_from_campaignsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
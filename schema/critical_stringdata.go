@@ -66,6 +66,58 @@ const CriticalSchemaJSON = `{
       "type": "boolean",
       "group": "Misc."
     },
+    "tracing.sampling": {
+      "description": "Controls which requests are traced when ` + "`" + `useJaeger` + "`" + ` or ` + "`" + `lightstepAccessToken` + "`" + ` is set. Changes apply without a restart. If not set, all requests are traced (unless the tracer is configured to sample them otherwise, e.g. with JAEGER_SAMPLER_* env vars).\n\nThe sampling decision is made for each request once its route and user are known, and propagated to the requests it makes to other services. The root spans of requests that were not sampled but took longer than ` + "`" + `slowRequestThreshold` + "`" + ` are recorded too.",
+      "type": "object",
+      "title": "TracingSampling",
+      "additionalProperties": false,
+      "required": ["rate"],
+      "properties": {
+        "rate": {
+          "description": "The fraction of requests that are traced, unless another rule below applies to them.",
+          "type": "number",
+          "minimum": 0,
+          "maximum": 1
+        },
+        "searchRate": {
+          "description": "The fraction of search requests (the \"graphql: Search\" and \"search\" routes) that are traced. If not set, ` + "`" + `rate` + "`" + ` applies to them.",
+          "type": "number",
+          "exclusiveMinimum": 0,
+          "maximum": 1
+        },
+        "routes": {
+          "description": "The fractions of requests to the given routes that are traced, overriding ` + "`" + `rate` + "`" + ` and ` + "`" + `searchRate` + "`" + `. Routes are named like in the ` + "`" + `src_http_request_duration_seconds` + "`" + ` metrics (e.g. \"repo.tree\" or \"graphql: Search\"), or are URL paths for services that have no named routes (e.g. \"/repo-update-scheduler-info\" for repo-updater).",
+          "type": "object",
+          "additionalProperties": {
+            "type": "number",
+            "minimum": 0,
+            "maximum": 1
+          }
+        },
+        "userIDs": {
+          "description": "The database IDs of users whose requests are always traced (the ` + "`" + `databaseID` + "`" + ` field of users in the GraphQL API).",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "slowRequestThreshold": {
+          "description": "The duration after which the root spans of requests that were not sampled are recorded anyway, such as \"5s\".",
+          "type": "string",
+          "pattern": "^[0-9]+(\\.[0-9]+)?(ms|s|m|h)$"
+        }
+      },
+      "examples": [
+        {
+          "rate": 0.01,
+          "searchRate": 0.1,
+          "routes": { "graphql: Search": 1 },
+          "userIDs": [1],
+          "slowRequestThreshold": "5s"
+        }
+      ],
+      "group": "Misc."
+    },
     "htmlHeadTop": {
       "description": "HTML to inject at the top of the ` + "`" + `<head>` + "`" + ` element on each page, for analytics scripts",
       "type": "string",
@@ -140,6 +192,49 @@ const CriticalSchemaJSON = `{
       "type": "boolean",
       "default": false
     },
+    "internalAPI.auth": {
+      "description": "Authentication for requests to the frontend's internal API, which the other Sourcegraph services use. By default, any client that can reach the internal API is trusted. When set, requests are only allowed from ` + "`" + `allowedNetworks` + "`" + ` or from services that send one of the configured secrets (from their ` + "`" + `SRC_INTERNAL_API_SECRET` + "`" + ` environment variable) in the ` + "`" + `X-Sourcegraph-Internal-Secret` + "`" + ` header.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "services": {
+          "description": "The services that may access the internal API and their secrets.",
+          "type": "array",
+          "items": {
+            "title": "InternalAPIService",
+            "type": "object",
+            "additionalProperties": false,
+            "required": ["name", "secret"],
+            "properties": {
+              "name": {
+                "description": "The name of the service (e.g. \"repo-updater\"), which it sends in the ` + "`" + `X-Sourcegraph-Service` + "`" + ` header. It is recorded in the traces of its requests.",
+                "type": "string",
+                "minLength": 1
+              },
+              "secret": {
+                "description": "The secret of the service.",
+                "type": "string",
+                "minLength": 16
+              }
+            }
+          }
+        },
+        "allowedNetworks": {
+          "description": "Networks in CIDR notation (e.g. \"10.0.0.0/8\") from which requests are allowed without a secret.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      },
+      "examples": [
+        {
+          "services": [{ "name": "repo-updater", "secret": "0123456789abcdef" }],
+          "allowedNetworks": ["127.0.0.1/32"]
+        }
+      ],
+      "group": "Security"
+    },
     "update.channel": {
       "description": "The channel on which to automatically check for Sourcegraph updates.",
       "type": ["string"],
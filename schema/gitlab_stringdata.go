@@ -29,6 +29,12 @@ const GitLabSchemaJSON = `{
       "type": "string",
       "minLength": 1
     },
+    "tokenType": {
+      "description": "The type of the token. If \"pat\", the token is a personal access token. If \"oauth\", the token is an OAuth access token (such as one obtained when connecting this GitLab instance with OAuth from the site admin area).",
+      "type": "string",
+      "enum": ["pat", "oauth"],
+      "default": "pat"
+    },
     "gitURLType": {
       "description": "The type of Git URLs to use for cloning and fetching Git repositories on this GitLab instance.\n\nIf \"http\", Sourcegraph will access GitLab repositories using Git URLs of the form http(s)://gitlab.example.com/myteam/myproject.git (using https: if the GitLab instance uses HTTPS).\n\nIf \"ssh\", Sourcegraph will access GitLab repositories using Git URLs of the form git@example.gitlab.com:myteam/myproject.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.",
       "type": "string",
@@ -8,6 +8,18 @@ import (
 	"fmt"
 )
 
+// APIQuota description: Quotas on the GraphQL API usage of each user, access token and anonymous client. Each GraphQL request costs 1 point. Requests are rejected with HTTP status 429 once a quota is used up, until it resets at the end of the window. API consumers can check their quota in the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset response headers, in the "quota" extension of GraphQL responses, and with the viewerApiQuota query.
+type APIQuota struct {
+	// AnonymousClientIPHeader description: The HTTP header, such as "X-Forwarded-For", that a trusted load balancer or reverse proxy in front of Sourcegraph sets to the IP address of the client. The last address in the header identifies anonymous clients. If unset, anonymous clients are identified by the address of the connection, so all anonymous clients behind a load balancer or reverse proxy share one quota. Only set this if the proxy overwrites or appends to the header, because clients can set it to any value.
+	AnonymousClientIPHeader string `json:"anonymousClientIPHeader,omitempty"`
+	// AnonymousPoints description: The number of points per window of anonymous clients, which share a quota per IP address. Defaults to points.
+	AnonymousPoints int `json:"anonymousPoints,omitempty"`
+	// Points description: The number of points per window of each user, and separately of each access token.
+	Points int `json:"points"`
+	// Window description: The duration after which quotas reset, such as "1h" or "15m".
+	Window string `json:"window,omitempty"`
+}
+
 // AWSCodeCommitConnection description: Configuration for a connection to AWS CodeCommit.
 type AWSCodeCommitConnection struct {
 	// AccessKeyID description: The AWS access key ID to use when listing and updating repositories from AWS CodeCommit. Must have the AWSCodeCommitReadOnly IAM policy.
@@ -312,6 +324,8 @@ type CriticalConfiguration struct {
 	HtmlHeadBottom string `json:"htmlHeadBottom,omitempty"`
 	// HtmlHeadTop description: HTML to inject at the top of the `<head>` element on each page, for analytics scripts
 	HtmlHeadTop string `json:"htmlHeadTop,omitempty"`
+	// InternalAPIAuth description: Authentication for requests to the frontend's internal API, which the other Sourcegraph services use. By default, any client that can reach the internal API is trusted. When set, requests are only allowed from `allowedNetworks` or from services that send one of the configured secrets (from their `SRC_INTERNAL_API_SECRET` environment variable) in the `X-Sourcegraph-Internal-Secret` header.
+	InternalAPIAuth *InternalAPIAuth `json:"internalAPI.auth,omitempty"`
 	// LicenseKey description: The license key associated with a Sourcegraph product subscription, which is necessary to activate Sourcegraph Enterprise functionality. To obtain this value, contact Sourcegraph to purchase a subscription.
 	LicenseKey string `json:"licenseKey,omitempty"`
 	// LightstepAccessToken description: Access token for sending traces to LightStep.
@@ -320,6 +334,10 @@ type CriticalConfiguration struct {
 	LightstepProject string `json:"lightstepProject,omitempty"`
 	// Log description: Configuration for logging and alerting, including to external services.
 	Log *Log `json:"log,omitempty"`
+	// TracingSampling description: Controls which requests are traced when `useJaeger` or `lightstepAccessToken` is set. Changes apply without a restart. If not set, all requests are traced (unless the tracer is configured to sample them otherwise, e.g. with JAEGER_SAMPLER_* env vars).
+	//
+	// The sampling decision is made for each request once its route and user are known, and propagated to the requests it makes to other services. The root spans of requests that were not sampled but took longer than `slowRequestThreshold` are recorded too.
+	TracingSampling *TracingSampling `json:"tracing.sampling,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: Use local Jaeger instance for tracing. Kubernetes cluster deployments only.
@@ -338,6 +356,13 @@ type CriticalConfiguration struct {
 	UseJaeger bool `json:"useJaeger,omitempty"`
 }
 
+type CuratedSearchFilter struct {
+	// Label description: The label of the filter in the filter bar.
+	Label string `json:"label"`
+	// Value description: The query fragment that is added to the search query when the filter is selected.
+	Value string `json:"value"`
+}
+
 // Discussions description: Configures Sourcegraph code discussions.
 type Discussions struct {
 	// AbuseEmails description: Email addresses to notify of e.g. new user reports about abusive comments. Otherwise emails will not be sent.
@@ -345,6 +370,12 @@ type Discussions struct {
 	// AbuseProtection description: Enable abuse protection features (for public instances like Sourcegraph.com, not recommended for private instances).
 	AbuseProtection bool `json:"abuseProtection,omitempty"`
 }
+type EditorPathMapping struct {
+	// LocalPath description: The absolute local path of the repository's clone, which may refer to submatches of the repository pattern, e.g. "/home/me/src/$1".
+	LocalPath string `json:"localPath"`
+	// Repository description: A regular expression matching repository names, e.g. "^github\\.com/myorg/(.*)$".
+	Repository string `json:"repository"`
+}
 type ExcludedAWSCodeCommitRepo struct {
 	// Id description: The ID of an AWS Code Commit repository (as returned by the AWS API) to exclude from mirroring. Use this to exclude the repository, even if renamed, or to differentiate between repositories with the same name in multiple regions.
 	Id string `json:"id,omitempty"`
@@ -409,6 +440,18 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// GitHubAppInstallation description: Authenticates as an installation of a GitHub App instead of with a personal access token. Sourcegraph creates short-lived installation access tokens with the App's private key and refreshes them before they expire. Use the "installation" value of "repositoryQuery" to mirror all repositories the installation has access to.
+type GitHubAppInstallation struct {
+	// AppID description: The ID of the GitHub App, shown on its settings page.
+	AppID int `json:"appID"`
+	// InstallationID description: The ID of the installation of the GitHub App on an organization or user account. Installations of the App that are created later are added as new external services (with the same configuration and this ID changed) when the App's webhook delivers their "installation" events to Sourcegraph, and installations that are deleted are removed.
+	InstallationID int `json:"installationID"`
+	// PrivateKey description: The PEM-encoded private key of the GitHub App, generated on its settings page.
+	PrivateKey string `json:"privateKey"`
+	// WebhookSecret description: The webhook secret of the GitHub App. If set, the App's webhook events (such as those of pull requests and installations) are accepted at https://sourcegraph.example.com/.api/github-webhooks.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+}
+
 // GitHubAuthProvider description: Configures the GitHub (or GitHub Enterprise) OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitHub instance: https://developer.github.com/apps/building-oauth-apps/creating-an-oauth-app/. When a user signs into Sourcegraph or links their GitHub account to their existing Sourcegraph account, GitHub will prompt the user for the repo scope.
 type GitHubAuthProvider struct {
 	// AllowSignup description: Allows new visitors to sign up for accounts via GitHub authentication. If false, users signing in via GitHub must have an existing Sourcegraph account, which will be linked to their GitHub identity after sign-in.
@@ -451,12 +494,20 @@ type GitHubConnection struct {
 	//
 	// If "ssh", Sourcegraph will access GitHub repositories using Git URLs of the form git@github.com:myteam/myproject.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
 	GitURLType string `json:"gitURLType,omitempty"`
+	// GithubAppInstallation description: Authenticates as an installation of a GitHub App instead of with a personal access token. Sourcegraph creates short-lived installation access tokens with the App's private key and refreshes them before they expire. Use the "installation" value of "repositoryQuery" to mirror all repositories the installation has access to.
+	GithubAppInstallation *GitHubAppInstallation `json:"githubAppInstallation,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. GitHub repositories can no longer be enabled or disabled explicitly. Configure repositories to be mirrored via "repos", "exclude" and "repositoryQuery" instead.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// Orgs description: An array of organization names identifying GitHub organizations whose repositories should be mirrored on Sourcegraph.
 	Orgs []string `json:"orgs,omitempty"`
 	// Repos description: An array of repository "owner/name" strings specifying which GitHub or GitHub Enterprise repositories to mirror on Sourcegraph.
 	Repos []string `json:"repos,omitempty"`
+	// RepositoryListingAPI description: The GitHub API used to list the repositories of the organizations in "orgs", and of "org:" and "affiliated" values of "repositoryQuery".
+	//
+	// If "rest", Sourcegraph lists repositories with the REST API, which returns 100 repositories per request.
+	//
+	// If "graphql", Sourcegraph lists repositories with the GraphQL API, which needs fewer requests and also returns their primary language and topics. On GitHub Enterprise instances that don't support it, Sourcegraph falls back to the REST API.
+	RepositoryListingAPI string `json:"repositoryListingAPI,omitempty"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a GitHub or GitHub Enterprise repository. In the pattern, the variable "{host}" is replaced with the GitHub host (such as github.example.com), and "{nameWithOwner}" is replaced with the GitHub repository's "owner/path" (such as "myorg/myrepo").
 	//
 	// For example, if your GitHub Enterprise URL is https://github.example.com and your Sourcegraph URL is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that a GitHub repository at https://github.example.com/myorg/myrepo is available on Sourcegraph at https://src.example.com/github.example.com/myorg/myrepo.
@@ -472,6 +523,8 @@ type GitHubConnection struct {
 	// 	- Public repositories owned by the user or their orgs
 	// 	- Public repositories with write access
 	//
+	// - `installation` mirrors all repositories that the GitHub App installation in `githubAppInstallation` has access to
+	//
 	// - `none` mirrors no repositories (except those specified in the `repos` configuration property or added manually)
 	//
 	// - All other values are executed as a GitHub advanced repository search as described at https://github.com/search/advanced. Example: to sync all repositories from the "sourcegraph" organization including forks the query would be "org:sourcegraph fork:true".
@@ -481,7 +534,7 @@ type GitHubConnection struct {
 	// If you need to narrow the set of mirrored repositories further (and don't want to enumerate it with a list or query set as above), create a new bot/machine user on GitHub or GitHub Enterprise that is only affiliated with the desired repositories.
 	RepositoryQuery []string `json:"repositoryQuery,omitempty"`
 	// Token description: A GitHub personal access token. Create one for GitHub.com at https://github.com/settings/tokens/new?scopes=repo&description=Sourcegraph (for GitHub Enterprise, replace github.com with your instance's hostname). The "repo" scope is required to mirror private repositories. If using only public repositories, you can create the token with no scopes.
-	Token string `json:"token"`
+	Token string `json:"token,omitempty"`
 	// Url description: URL of a GitHub instance, such as https://github.com or https://github-enterprise.example.com.
 	Url string `json:"url"`
 	// Webhooks description: An array of configurations defining existing GitHub webhooks that send updates back to Sourcegraph.
@@ -550,6 +603,8 @@ type GitLabConnection struct {
 	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
 	// Token description: A GitLab access token with "api" and "sudo" scopes. If this token does not have "sudo" scope, then you must set `permissions.ignore` to true.
 	Token string `json:"token"`
+	// TokenType description: The type of the token. If "pat", the token is a personal access token. If "oauth", the token is an OAuth access token (such as one obtained when connecting this GitLab instance with OAuth from the site admin area).
+	TokenType string `json:"tokenType,omitempty"`
 	// Url description: URL of a GitLab instance, such as https://gitlab.example.com or (for GitLab.com) https://gitlab.com.
 	Url string `json:"url"`
 }
@@ -642,11 +697,41 @@ func (v *IdentityProvider) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"oauth", "username", "external"})
 }
 
+// InternalAPIAuth description: Authentication for requests to the frontend's internal API, which the other Sourcegraph services use. By default, any client that can reach the internal API is trusted. When set, requests are only allowed from `allowedNetworks` or from services that send one of the configured secrets (from their `SRC_INTERNAL_API_SECRET` environment variable) in the `X-Sourcegraph-Internal-Secret` header.
+type InternalAPIAuth struct {
+	// AllowedNetworks description: Networks in CIDR notation (e.g. "10.0.0.0/8") from which requests are allowed without a secret.
+	AllowedNetworks []string `json:"allowedNetworks,omitempty"`
+	// Services description: The services that may access the internal API and their secrets.
+	Services []*InternalAPIService `json:"services,omitempty"`
+}
+type InternalAPIService struct {
+	// Name description: The name of the service (e.g. "repo-updater"), which it sends in the `X-Sourcegraph-Service` header. It is recorded in the traces of its requests.
+	Name string `json:"name"`
+	// Secret description: The secret of the service.
+	Secret string `json:"secret"`
+}
+
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
 	// Sentry description: Configuration for Sentry
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
+
+// ManifestConnection description: Configuration for a connection to a manifest listing the Git repositories to mirror, such as an export of a homegrown repository inventory.
+type ManifestConnection struct {
+	// Format description: The format of the manifest.
+	//
+	// A CSV manifest has a header row naming its columns. The "name" and "clone_url" columns are required, all other columns are stored as metadata of the repositories.
+	//
+	// A JSON manifest is an array of objects with the "name", "cloneURL" and (optional) "metadata" properties, e.g. [{"name": "git.example.com/my/repo", "cloneURL": "https://git.example.com/my/repo.git", "metadata": {"team": "search"}}].
+	Format string `json:"format,omitempty"`
+	// Manifest description: The contents of the manifest, for uploaded manifests that aren't served over HTTP(S). Only one of url and manifest may be set.
+	Manifest string `json:"manifest,omitempty"`
+	// Token description: A token sent as a bearer token in the Authorization header of requests to the manifest URL, if it requires authentication.
+	Token string `json:"token,omitempty"`
+	// Url description: URL of the manifest, which is fetched on every repository sync. Sourcegraph mirrors exactly the repositories listed in the manifest: repositories removed from it are removed from Sourcegraph.
+	Url string `json:"url,omitempty"`
+}
 type Notice struct {
 	// Dismissible description: Whether this notice can be dismissed (closed) by the user.
 	Dismissible bool `json:"dismissible,omitempty"`
@@ -771,6 +856,14 @@ type SMTPServerConfig struct {
 	// Username description: The username to use when communicating with the SMTP server.
 	Username string `json:"username,omitempty"`
 }
+
+// SearchExclusions description: Repositories and paths that are excluded from the searches of all members of an organization, such as archived mirrors or generated files. This setting is only read from organization settings, so members can't override it in their user settings. Members can still search the excluded repositories and paths by adding `orgexclusions:no` to their query.
+type SearchExclusions struct {
+	// Paths description: Regular expressions matching the paths of the files to exclude.
+	Paths []string `json:"paths,omitempty"`
+	// Repositories description: Regular expressions matching the names of the repositories to exclude.
+	Repositories []string `json:"repositories,omitempty"`
+}
 type SearchSavedQueries struct {
 	// Description description: Description of this saved query
 	Description string `json:"description"`
@@ -826,14 +919,22 @@ type Settings struct {
 	//
 	// Usually this setting is used in global and organization settings. If set in user settings, the message will only be displayed to that single user.
 	Notices []*Notice `json:"notices,omitempty"`
+	// OpenInEditorPathMappings description: Rules that map repositories to the local paths of their clones, for opening files from Sourcegraph in an editor. The first rule whose pattern matches the repository name is used.
+	OpenInEditorPathMappings []*EditorPathMapping `json:"openInEditor.pathMappings,omitempty"`
+	// OpenInEditorScheme description: The URL scheme of the editor in which files are opened from Sourcegraph (using the /-/editor-link endpoint): "vscode" for Visual Studio Code or "idea" for IntelliJ IDEA.
+	OpenInEditorScheme string `json:"openInEditor.scheme,omitempty"`
 	// Quicklinks description: Links that should be accessible quickly from the home and search pages.
 	Quicklinks []*QuickLink `json:"quicklinks,omitempty"`
 	// SearchContextLines description: The default number of lines to show as context below and above search results. Default is 1.
 	SearchContextLines int `json:"search.contextLines,omitempty"`
 	// SearchDefaultPatternType description: The default pattern type (literal or regexp) that search queries will be intepreted as.
 	SearchDefaultPatternType string `json:"search.defaultPatternType,omitempty"`
+	// SearchExclusions description: Repositories and paths that are excluded from the searches of all members of an organization, such as archived mirrors or generated files. This setting is only read from organization settings, so members can't override it in their user settings. Members can still search the excluded repositories and paths by adding `orgexclusions:no` to their query.
+	SearchExclusions *SearchExclusions `json:"search.exclusions,omitempty"`
 	// SearchRepositoryGroups description: Named groups of repositories that can be referenced in a search query using the repogroup: operator.
 	SearchRepositoryGroups map[string][]string `json:"search.repositoryGroups,omitempty"`
+	// SearchRetryWithoutQuotes description: Whether to automatically run a literal search query that contains quotes again without the quotes if it has no results (instead of only suggesting it). The results are then shown with a note that the quotes were removed.
+	SearchRetryWithoutQuotes bool `json:"search.retryWithoutQuotes,omitempty"`
 	// SearchSavedQueries description: DEPRECATED: Saved search queries
 	SearchSavedQueries []*SearchSavedQueries `json:"search.savedQueries,omitempty"`
 	// SearchScopes description: Predefined search scopes
@@ -842,6 +943,10 @@ type Settings struct {
 
 // SiteConfiguration description: Configuration for a Sourcegraph site.
 type SiteConfiguration struct {
+	// ApiQuota description: Quotas on the GraphQL API usage of each user, access token and anonymous client. Each GraphQL request costs 1 point. Requests are rejected with HTTP status 429 once a quota is used up, until it resets at the end of the window. API consumers can check their quota in the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset response headers, in the "quota" extension of GraphQL responses, and with the viewerApiQuota query.
+	ApiQuota *APIQuota `json:"api.quota,omitempty"`
+	// ArchiveDeletedRepositories description: Keep repositories that were removed from their code host as archived mirrors instead of deleting them. Archived mirrors are no longer updated, but remain indexed and can be searched with `archived-mirror:yes` (or `archived-mirror:only`).
+	ArchiveDeletedRepositories bool `json:"archiveDeletedRepositories,omitempty"`
 	// AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 	AuthAccessTokens *AuthAccessTokens `json:"auth.accessTokens,omitempty"`
 	// Branding description: Customize Sourcegraph homepage logo and search icon.
@@ -886,16 +991,50 @@ type SiteConfiguration struct {
 	LsifEnforceAuth bool `json:"lsifEnforceAuth,omitempty"`
 	// MaxReposToSearch description: The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.
 	MaxReposToSearch int `json:"maxReposToSearch,omitempty"`
+	// MaxReposToSearchCeiling description: The maximum number of repositories that a search can be run across with the `maxRepos:` query field. Defaults to maxReposToSearch, so that `maxRepos:` can only lower the limit. Any negative value means unlimited.
+	MaxReposToSearchCeiling int `json:"maxReposToSearchCeiling,omitempty"`
 	// ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
 	ParentSourcegraph *ParentSourcegraph `json:"parentSourcegraph,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
+	// SearchCuratedFilters description: Curated filters that are proposed in the filter bar of search results when their query fragment matches some of the results, to promote drill-downs that are specific to the organization. The query fragment can use the repo:, -repo:, file:, -file:, lang: and repogroup: fields.
+	SearchCuratedFilters []*CuratedSearchFilter `json:"search.curatedFilters,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
 	SearchIndexEnabled *bool `json:"search.index.enabled,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. The glob pattern syntax can be found here: https://golang.org/pkg/path/filepath/#Match.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
+	// SearchOwnershipFiles description: Paths of the files in repositories that assign owners to files, in CODEOWNERS format. The first file that exists at the searched commit is used. Defaults to CODEOWNERS, .github/CODEOWNERS, .gitlab/CODEOWNERS and docs/CODEOWNERS.
+	SearchOwnershipFiles []string `json:"search.ownershipFiles,omitempty"`
+	// SearchUnicodeNormalization description: The Unicode normalization form that the patterns and the searched text of searches with unicode:yes are converted to before matching, so that text which is canonically equivalent (e.g. precomposed and decomposed accented letters) matches. It is also used by the zoekt index server to normalize the indexed content. Defaults to NFC.
+	SearchUnicodeNormalization string `json:"search.unicodeNormalization,omitempty"`
+	// StorageSoftQuotas description: Soft quotas on the number of rows of the database tables that grow with the number of repositories synced from code hosts. Site admins are alerted when a table nears (90%) or exceeds its quota, so that the database disk can be grown before a large sync exhausts it. Nothing is rejected when a quota is exceeded. A negative quota disables the alert for the table.
+	StorageSoftQuotas *StorageSoftQuotas `json:"storage.softQuotas,omitempty"`
+}
+
+// StorageSoftQuotas description: Soft quotas on the number of rows of the database tables that grow with the number of repositories synced from code hosts. Site admins are alerted when a table nears (90%) or exceeds its quota, so that the database disk can be grown before a large sync exhausts it. Nothing is rejected when a quota is exceeded. A negative quota disables the alert for the table.
+type StorageSoftQuotas struct {
+	// Changesets description: The soft quota on the number of campaign changesets.
+	Changesets int `json:"changesets,omitempty"`
+	// Repos description: The soft quota on the number of repositories, including deleted ones that have not been purged yet.
+	Repos int `json:"repos,omitempty"`
+}
+
+// TracingSampling description: Controls which requests are traced when `useJaeger` or `lightstepAccessToken` is set. Changes apply without a restart. If not set, all requests are traced (unless the tracer is configured to sample them otherwise, e.g. with JAEGER_SAMPLER_* env vars).
+//
+// The sampling decision is made for each request once its route and user are known, and propagated to the requests it makes to other services. The root spans of requests that were not sampled but took longer than `slowRequestThreshold` are recorded too.
+type TracingSampling struct {
+	// Rate description: The fraction of requests that are traced, unless another rule below applies to them.
+	Rate float64 `json:"rate"`
+	// Routes description: The fractions of requests to the given routes that are traced, overriding `rate` and `searchRate`. Routes are named like in the `src_http_request_duration_seconds` metrics (e.g. "repo.tree" or "graphql: Search"), or are URL paths for services that have no named routes (e.g. "/repo-update-scheduler-info" for repo-updater).
+	Routes map[string]float64 `json:"routes,omitempty"`
+	// SearchRate description: The fraction of search requests (the "graphql: Search" and "search" routes) that are traced. If not set, `rate` applies to them.
+	SearchRate float64 `json:"searchRate,omitempty"`
+	// SlowRequestThreshold description: The duration after which the root spans of requests that were not sampled are recorded anyway, such as "5s".
+	SlowRequestThreshold string `json:"slowRequestThreshold,omitempty"`
+	// UserIDs description: The database IDs of users whose requests are always traced (the `databaseID` field of users in the GraphQL API).
+	UserIDs []int `json:"userIDs,omitempty"`
 }
 type UsernameIdentity struct {
 	Type string `json:"type"`
@@ -72,6 +72,30 @@ const SettingsSchemaJSON = `{
       "type": "string",
       "pattern": "literal|regexp"
     },
+    "search.retryWithoutQuotes": {
+      "description": "Whether to automatically run a literal search query that contains quotes again without the quotes if it has no results (instead of only suggesting it). The results are then shown with a note that the quotes were removed.",
+      "type": "boolean",
+      "default": false
+    },
+    "search.exclusions": {
+      "description": "Repositories and paths that are excluded from the searches of all members of an organization, such as archived mirrors or generated files. This setting is only read from organization settings, so members can't override it in their user settings. Members can still search the excluded repositories and paths by adding ` + "`" + `orgexclusions:no` + "`" + ` to their query.",
+      "title": "SearchExclusions",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "repositories": {
+          "description": "Regular expressions matching the names of the repositories to exclude.",
+          "type": "array",
+          "items": { "type": "string", "format": "regex" }
+        },
+        "paths": {
+          "description": "Regular expressions matching the paths of the files to exclude.",
+          "type": "array",
+          "items": { "type": "string", "format": "regex" }
+        }
+      },
+      "examples": [{ "repositories": ["-mirror$"], "paths": ["^vendor/", "\\.pb\\.go$"] }]
+    },
     "quicklinks": {
       "description": "Links that should be accessible quickly from the home and search pages.",
       "type": "array",
@@ -131,6 +155,33 @@ const SettingsSchemaJSON = `{
       "description": "Whether to use the code host's native hover tooltips when they exist (GitHub's jump-to-definition tooltips, for example).",
       "type": "boolean",
       "default": false
+    },
+    "openInEditor.scheme": {
+      "description": "The URL scheme of the editor in which files are opened from Sourcegraph (using the /-/editor-link endpoint): \"vscode\" for Visual Studio Code or \"idea\" for IntelliJ IDEA.",
+      "type": "string",
+      "enum": ["vscode", "idea"],
+      "default": "vscode"
+    },
+    "openInEditor.pathMappings": {
+      "description": "Rules that map repositories to the local paths of their clones, for opening files from Sourcegraph in an editor. The first rule whose pattern matches the repository name is used.",
+      "type": "array",
+      "items": {
+        "title": "EditorPathMapping",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["repository", "localPath"],
+        "properties": {
+          "repository": {
+            "description": "A regular expression matching repository names, e.g. \"^github\\\\.com/myorg/(.*)$\".",
+            "type": "string",
+            "format": "regex"
+          },
+          "localPath": {
+            "description": "The absolute local path of the repository's clone, which may refer to submatches of the repository pattern, e.g. \"/home/me/src/$1\".",
+            "type": "string"
+          }
+        }
+      }
     }
   },
   "definitions": {
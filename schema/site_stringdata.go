@@ -43,6 +43,51 @@ const SiteSchemaJSON = `{
       "group": "Search",
       "examples": [["go.sum", "package-lock.json", "*.thrift"]]
     },
+    "search.unicodeNormalization": {
+      "description": "The Unicode normalization form that the patterns and the searched text of searches with unicode:yes are converted to before matching, so that text which is canonically equivalent (e.g. precomposed and decomposed accented letters) matches. It is also used by the zoekt index server to normalize the indexed content. Defaults to NFC.",
+      "type": "string",
+      "enum": ["NFC", "NFD"],
+      "default": "NFC",
+      "group": "Search"
+    },
+    "search.ownershipFiles": {
+      "description": "Paths of the files in repositories that assign owners to files, in CODEOWNERS format. The first file that exists at the searched commit is used. Defaults to CODEOWNERS, .github/CODEOWNERS, .gitlab/CODEOWNERS and docs/CODEOWNERS.",
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "group": "Search",
+      "examples": [["CODEOWNERS", ".github/CODEOWNERS"]]
+    },
+    "search.curatedFilters": {
+      "description": "Curated filters that are proposed in the filter bar of search results when their query fragment matches some of the results, to promote drill-downs that are specific to the organization. The query fragment can use the repo:, -repo:, file:, -file:, lang: and repogroup: fields.",
+      "type": "array",
+      "items": {
+        "title": "CuratedSearchFilter",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["label", "value"],
+        "properties": {
+          "label": {
+            "description": "The label of the filter in the filter bar.",
+            "type": "string",
+            "minLength": 1
+          },
+          "value": {
+            "description": "The query fragment that is added to the search query when the filter is selected.",
+            "type": "string",
+            "minLength": 1
+          }
+        }
+      },
+      "group": "Search",
+      "examples": [
+        [
+          { "label": "Payments team", "value": "repo:^github\\.com/acme/payments-" },
+          { "label": "Good first issues", "value": "repogroup:good-first-issues lang:go" }
+        ]
+      ]
+    },
     "debug.search.symbolsParallelism": {
       "description": "(debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.",
       "type": "integer",
@@ -95,6 +140,12 @@ const SiteSchemaJSON = `{
       "default": false,
       "group": "External services"
     },
+    "archiveDeletedRepositories": {
+      "description": "Keep repositories that were removed from their code host as archived mirrors instead of deleting them. Archived mirrors are no longer updated, but remain indexed and can be searched with ` + "`" + `archived-mirror:yes` + "`" + ` (or ` + "`" + `archived-mirror:only` + "`" + `).",
+      "type": "boolean",
+      "default": false,
+      "group": "External services"
+    },
     "disablePublicRepoRedirects": {
       "description": "Disable redirects to sourcegraph.com when visiting public repositories that can't exist on this server.",
       "type": "boolean",
@@ -152,6 +203,11 @@ const SiteSchemaJSON = `{
       "default": -1,
       "group": "Search"
     },
+    "maxReposToSearchCeiling": {
+      "description": "The maximum number of repositories that a search can be run across with the ` + "`" + `maxRepos:` + "`" + ` query field. Defaults to maxReposToSearch, so that ` + "`" + `maxRepos:` + "`" + ` can only lower the limit. Any negative value means unlimited.",
+      "type": "integer",
+      "group": "Search"
+    },
     "parentSourcegraph": {
       "description": "URL to fetch unreachable repository details from. Defaults to \"https://sourcegraph.com\"",
       "type": "object",
@@ -164,6 +220,58 @@ const SiteSchemaJSON = `{
       },
       "group": "External services"
     },
+    "storage.softQuotas": {
+      "description": "Soft quotas on the number of rows of the database tables that grow with the number of repositories synced from code hosts. Site admins are alerted when a table nears (90%) or exceeds its quota, so that the database disk can be grown before a large sync exhausts it. Nothing is rejected when a quota is exceeded. A negative quota disables the alert for the table.",
+      "title": "StorageSoftQuotas",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "repos": {
+          "description": "The soft quota on the number of repositories, including deleted ones that have not been purged yet.",
+          "type": "integer",
+          "default": 500000
+        },
+        "changesets": {
+          "description": "The soft quota on the number of campaign changesets.",
+          "type": "integer",
+          "default": 100000
+        }
+      },
+      "examples": [{ "repos": 1000000, "changesets": 50000 }],
+      "group": "Misc."
+    },
+    "api.quota": {
+      "description": "Quotas on the GraphQL API usage of each user, access token and anonymous client. Each GraphQL request costs 1 point. Requests are rejected with HTTP status 429 once a quota is used up, until it resets at the end of the window. API consumers can check their quota in the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset response headers, in the \"quota\" extension of GraphQL responses, and with the viewerApiQuota query.",
+      "title": "APIQuota",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["points"],
+      "properties": {
+        "points": {
+          "description": "The number of points per window of each user, and separately of each access token.",
+          "type": "integer",
+          "minimum": 1
+        },
+        "anonymousPoints": {
+          "description": "The number of points per window of anonymous clients, which share a quota per IP address. Defaults to points.",
+          "type": "integer",
+          "minimum": 1
+        },
+        "anonymousClientIPHeader": {
+          "description": "The HTTP header, such as \"X-Forwarded-For\", that a trusted load balancer or reverse proxy in front of Sourcegraph sets to the IP address of the client. The last address in the header identifies anonymous clients. If unset, anonymous clients are identified by the address of the connection, so all anonymous clients behind a load balancer or reverse proxy share one quota. Only set this if the proxy overwrites or appends to the header, because clients can set it to any value.",
+          "type": "string",
+          "examples": ["X-Forwarded-For", "X-Real-IP"]
+        },
+        "window": {
+          "description": "The duration after which quotas reset, such as \"1h\" or \"15m\".",
+          "type": "string",
+          "pattern": "^[0-9]+(s|m|h)$",
+          "default": "1h"
+        }
+      },
+      "examples": [{ "points": 5000, "anonymousPoints": 500, "anonymousClientIPHeader": "X-Forwarded-For", "window": "1h" }],
+      "group": "Security"
+    },
     "auth.accessTokens": {
       "description": "Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.",
       "type": "object",
@@ -28,6 +28,10 @@ export function fetchAllStatusMessages(): Observable<GQL.StatusMessage[]> {
                         message
                     }
 
+                    ... on UpdateQueueBacklog {
+                        message
+                    }
+
                     ... on ExternalServiceSyncError {
                         message
                         externalService {
@@ -171,6 +175,19 @@ export class StatusMessagesNavItem extends React.PureComponent<Props, State> {
                         entryType="warning"
                     />
                 )
+            case 'UpdateQueueBacklog':
+                return (
+                    <StatusMessagesNavItemEntry
+                        key={message.message}
+                        title="Repositories updating"
+                        text={message.message}
+                        showLink={this.props.isSiteAdmin}
+                        linkTo="/site-admin/repositories"
+                        linkText="View repositories"
+                        linkOnClick={this.toggleIsOpen}
+                        entryType="progress"
+                    />
+                )
         }
     }
 
@@ -8,6 +8,7 @@ import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.j
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import manifestSchemaJSON from '../../../schema/manifest.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import settingsSchemaJSON from '../../../schema/settings.schema.json'
@@ -33,6 +34,7 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     GITHUB: githubSchemaJSON,
     GITLAB: gitlabSchemaJSON,
     GITOLITE: gitoliteSchemaJSON,
+    MANIFEST: manifestSchemaJSON,
     OTHER: otherExternalServiceSchemaJSON,
     PHABRICATOR: phabricatorSchemaJSON,
 }
@@ -14,6 +14,7 @@ import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.j
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import manifestSchemaJSON from '../../../schema/manifest.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import { PhabricatorIcon } from '../../../shared/src/components/icons'
@@ -667,6 +668,44 @@ export const ALL_EXTERNAL_SERVICES: Record<GQL.ExternalServiceKind, ExternalServ
             },
         ],
     },
+    [GQL.ExternalServiceKind.MANIFEST]: {
+        title: 'Repository manifest',
+        icon: GitIcon,
+        shortDescription: 'Mirror exactly the Git repositories listed in a CSV or JSON manifest.',
+        jsonSchema: manifestSchemaJSON,
+        defaultDisplayName: 'Repository manifest',
+        defaultConfig: `{
+  // Use Ctrl+Space for completion, and hover over JSON properties for documentation.
+  // Configuration options are documented here:
+  // https://docs.sourcegraph.com/admin/external_service/manifest#configuration
+
+  // The manifest is fetched from this URL on every repository sync.
+  "url": "https://inventory.example.com/repos.csv",
+
+  // A CSV manifest must have "name" and "clone_url" columns.
+  "format": "csv"
+}`,
+        editorActions: [
+            {
+                id: 'setURL',
+                label: 'Set manifest URL',
+                run: config => {
+                    const value = 'https://inventory.example.com/repos.csv'
+                    const edits = setProperty(config, ['url'], value, defaultFormattingOptions)
+                    return { edits, selectText: value }
+                },
+            },
+            {
+                id: 'setToken',
+                label: 'Set access token',
+                run: config => {
+                    const value = '<access token>'
+                    const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                    return { edits, selectText: value }
+                },
+            },
+        ],
+    },
     [GQL.ExternalServiceKind.PHABRICATOR]: {
         title: 'Phabricator connection',
         icon: PhabricatorIcon,